@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"os"
 	"time"
 
 	jwtgo "github.com/golang-jwt/jwt/v5"
@@ -25,6 +26,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 
@@ -57,9 +59,16 @@ import (
 	"github.com/pipe-cd/pipecd/pkg/insight"
 	"github.com/pipe-cd/pipecd/pkg/insight/insightstore"
 	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/jwt/jwtmetrics"
+	"github.com/pipe-cd/pipecd/pkg/jwt/keysource"
 	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oauthhttp"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oidc"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oidc/oidcmetrics"
+	"github.com/pipe-cd/pipecd/pkg/oauth/webauthn"
 	"github.com/pipe-cd/pipecd/pkg/redis"
 	"github.com/pipe-cd/pipecd/pkg/rpc"
+	"github.com/pipe-cd/pipecd/pkg/rpc/rpcauth"
 	"github.com/pipe-cd/pipecd/pkg/version"
 )
 
@@ -70,6 +79,14 @@ var (
 const (
 	defaultPipedStatHashKey    = "HASHKEY:PIPED:STATS"
 	apiKeyLastUsedCacheHashKey = "HASHKEY:PIPED:API_KEYS" //nolint:gosec
+	// samlAssertionCacheTTL must be longer than the SAML assertions are accepted after issued.
+	samlAssertionCacheTTL = 10 * time.Minute
+	// ssoReadinessCacheTTL limits how often the identity providers are probed.
+	ssoReadinessCacheTTL = 30 * time.Second
+	// webAuthnChallengeCacheTTL must be longer than the WebAuthn ceremonies can take.
+	webAuthnChallengeCacheTTL = 10 * time.Minute
+	// deviceAuthorizationCacheTTL must be longer than the device codes of the providers are valid.
+	deviceAuthorizationCacheTTL = 30 * time.Minute
 )
 
 type server struct {
@@ -87,11 +104,81 @@ type server struct {
 	certFile       string
 	keyFile        string
 	insecureCookie bool
+	cookieSameSite string
+	cookieDomain   string
+	cookiePath     string
+	// cookieNamePrefix is prepended to the names of the token and state cookies.
+	cookieNamePrefix string
 
 	encryptionKeyFile string
-	configFile        string
+	// signingKeyDir is the directory of the RS256 keys signing the tokens instead of the encryption key.
+	signingKeyDir string
+	// signingKeyAWSSecret and signingKeyGCPSecret are the secrets of the RS256 keys signing the tokens
+	// in the secret managers, which are used instead of signingKeyDir.
+	signingKeyAWSSecret      string
+	signingKeyGCPSecret      string
+	signingKeyReloadInterval time.Duration
+	// signingKeyFile is the PEM file of the key signing the tokens by signingAlgorithm instead of the encryption key.
+	signingKeyFile   string
+	signingAlgorithm string
+	configFile       string
+	// tokenIssuer and tokenAudience are the iss and aud claims of the login tokens.
+	tokenIssuer   string
+	tokenAudience string
+	// tokenLeeway tolerates the clock skew in verifying the exp, nbf and iat claims of the login tokens.
+	tokenLeeway time.Duration
 
 	enableGRPCReflection bool
+
+	oidcJWKSCacheTTL      time.Duration
+	oidcDiscoveryCacheTTL time.Duration
+	oidcAllowedHosts      []string
+	oauthHTTPTimeouts     oauthhttp.Timeouts
+	oauthMinTLSVersion    string
+	oauthCABundleFile     string
+	refreshTokenTTL       time.Duration
+	sessionIdleTimeout    time.Duration
+	sessionMaxLifetime    time.Duration
+	sessionBinding        string
+	stateTTL              time.Duration
+	stateSecretFile       string
+	sessionStoreTTL       time.Duration
+	membershipCheckTTL    time.Duration
+
+	callbackRateLimitPerIP           float64
+	callbackRateLimitPerIPBurst      int
+	callbackRateLimitPerProject      float64
+	callbackRateLimitPerProjectBurst int
+
+	callbackMaxBodySize int64
+
+	callbackLockoutThreshold int
+	callbackLockoutWindow    time.Duration
+	callbackLockoutCooldown  time.Duration
+
+	trustedProxies []string
+
+	returnToAllowlist  []string
+	postLogoutRedirect string
+
+	// stateOrigins are the origins of the identity providers allowed to send the callback without the state cookie.
+	stateOrigins []string
+	// strictCallbackOrigins are the origins allowed to send the callback in POST or with the Origin header.
+	strictCallbackOrigins []string
+
+	authContentSecurityPolicy string
+
+	ldapFailedBindRateLimit      float64
+	ldapFailedBindRateLimitBurst int
+
+	gravatarFallback string
+
+	linkIdentitiesByEmail      bool
+	identityLinkExcludedEmails []string
+
+	stepUpTTL time.Duration
+
+	enableDeviceAuthorization bool
 }
 
 // NewServerCommand creates a new cobra command for executing api server.
@@ -106,6 +193,28 @@ func NewServerCommand() *cobra.Command {
 		staticDir:      "web/static",
 		cacheAddress:   "cache:6379",
 		gracePeriod:    30 * time.Second,
+
+		oidcJWKSCacheTTL:      oidc.DefaultJWKSCacheTTL,
+		oidcDiscoveryCacheTTL: oidc.DefaultDiscoveryCacheTTL,
+		oauthHTTPTimeouts:     oauthhttp.DefaultTimeouts,
+		oauthMinTLSVersion:    oauthhttp.DefaultMinTLSVersion,
+		stateTTL:              30 * time.Minute,
+		callbackMaxBodySize:   64 << 10,
+		sessionMaxLifetime:    7 * 24 * time.Hour,
+		sessionBinding:        string(httpapi.SessionBindingNone),
+
+		signingKeyReloadInterval: 30 * time.Second,
+		tokenLeeway:              jwt.DefaultLeeway,
+
+		callbackRateLimitPerIPBurst:      10,
+		callbackRateLimitPerProjectBurst: 100,
+
+		callbackLockoutThreshold: 20,
+		callbackLockoutWindow:    10 * time.Minute,
+		callbackLockoutCooldown:  15 * time.Minute,
+
+		ldapFailedBindRateLimit:      1.0 / 60,
+		ldapFailedBindRateLimitBurst: 5,
 	}
 	cmd := &cobra.Command{
 		Use:   "server",
@@ -127,15 +236,66 @@ func NewServerCommand() *cobra.Command {
 	cmd.Flags().StringVar(&s.certFile, "cert-file", s.certFile, "The path to the TLS certificate file.")
 	cmd.Flags().StringVar(&s.keyFile, "key-file", s.keyFile, "The path to the TLS key file.")
 	cmd.Flags().BoolVar(&s.insecureCookie, "insecure-cookie", s.insecureCookie, "Allow cookie to be sent over an unsecured HTTP connection.")
+	cmd.Flags().StringVar(&s.cookieDomain, "cookie-domain", s.cookieDomain, "The Domain attribute of the session cookies to share them with the subdomains, e.g. example.com for ui.example.com and api.example.com. It must be the host of the control plane address or its parent domain. Empty means the host-only cookies.")
+	cmd.Flags().StringVar(&s.cookiePath, "cookie-path", s.cookiePath, "The Path attribute of the session cookies. Empty means the root path.")
+	cmd.Flags().StringVar(&s.cookieNamePrefix, "cookie-name-prefix", s.cookieNamePrefix, "The prefix of the names of the token and state cookies, e.g. tenant-a- to avoid colliding with the other control planes sharing the cookie domain. The __Secure- and __Host- prefixes are also supported. Empty means the default names.")
+	cmd.Flags().StringVar(&s.cookieSameSite, "cookie-same-site", s.cookieSameSite, "The SameSite attribute of the session cookies. One of lax, strict or none. If none, the cookies are always sent over HTTPS only even if insecure-cookie is set.")
 
 	cmd.Flags().StringVar(&s.encryptionKeyFile, "encryption-key-file", s.encryptionKeyFile, "The path to file containing a random string of bits used to encrypt sensitive data.")
 	cmd.MarkFlagRequired("encryption-key-file")
+	cmd.Flags().StringVar(&s.signingKeyDir, "signing-key-dir", s.signingKeyDir, "The path to the directory containing the PEM files of the RSA keys to sign the login tokens by RS256. The first private key in the lexical order of the file names signs new tokens, and all keys verify tokens. The keys are reloaded when the files change. Empty means the tokens are signed by the encryption key.")
+	cmd.Flags().StringVar(&s.signingKeyAWSSecret, "signing-key-aws-secret", s.signingKeyAWSSecret, "The ARN of the secret of AWS Secrets Manager containing the PEM encoded RSA keys to sign the login tokens by RS256 in the order of precedence, instead of the signing key directory.")
+	cmd.Flags().StringVar(&s.signingKeyGCPSecret, "signing-key-gcp-secret", s.signingKeyGCPSecret, "The resource ID of the secret version of GCP Secret Manager containing the PEM encoded RSA keys to sign the login tokens by RS256 in the order of precedence, e.g. projects/my-project/secrets/my-secret/versions/latest, instead of the signing key directory.")
+	cmd.Flags().DurationVar(&s.signingKeyReloadInterval, "signing-key-reload-interval", s.signingKeyReloadInterval, "How often the signing key directory or secret is checked for changes.")
+	cmd.Flags().StringVar(&s.signingKeyFile, "signing-key-file", s.signingKeyFile, "The path to the PEM file of the private key to sign the login tokens by the signing algorithm. Cannot be used with signing-key-dir. Empty means the tokens are signed by the encryption key.")
+	cmd.Flags().StringVar(&s.signingAlgorithm, "signing-algorithm", s.signingAlgorithm, "The algorithm to sign the login tokens by the signing key file. One of HS256, HS384, HS512, RS256, RS384, RS512, ES256, ES384, ES512 or EdDSA. The tokens signed by the other algorithms are rejected.")
+	cmd.Flags().StringVar(&s.tokenIssuer, "token-issuer", s.tokenIssuer, "The iss claim of the login tokens, which must match to accept them, e.g. pipecd-production. Empty means PipeCD.")
+	cmd.Flags().DurationVar(&s.tokenLeeway, "token-leeway", s.tokenLeeway, "The leeway of the expiry and the issued time of the login tokens to tolerate the clock skew between the control plane and the clients. At most 5m. Zero means no leeway.")
+	cmd.Flags().StringVar(&s.tokenAudience, "token-audience", s.tokenAudience, "The aud claim of the login tokens, which must match to accept them, e.g. https://pipecd.example.com. Empty means no audience. Setting it makes the users who logged in before have to log in again.")
 	cmd.Flags().StringVar(&s.configFile, "config-file", s.configFile, "The path to the configuration file.")
 	cmd.MarkFlagRequired("config-file")
 
 	// For debugging early in development
 	cmd.Flags().BoolVar(&s.enableGRPCReflection, "enable-grpc-reflection", s.enableGRPCReflection, "Whether to enable the reflection service or not.")
 
+	cmd.Flags().DurationVar(&s.stateTTL, "state-ttl", s.stateTTL, "How long the state of the SSO login flow is valid. The login started before that must be retried.")
+	cmd.Flags().StringVar(&s.stateSecretFile, "state-secret-file", s.stateSecretFile, "The path to file containing a random string of at least 32 bytes used to encrypt the state of the SSO login flow. The project, the nonce, the PKCE code verifier and the return path are carried by the encrypted state instead of the cookies. Empty means the state is only signed.")
+	cmd.Flags().DurationVar(&s.sessionIdleTimeout, "session-idle-timeout", s.sessionIdleTimeout, "How long a login session is kept without any request. The session is extended on each request of the web console up to session-max-lifetime. Zero means the session expires after the fixed TTL.")
+	cmd.Flags().DurationVar(&s.sessionMaxLifetime, "session-max-lifetime", s.sessionMaxLifetime, "How long a login session can be extended from the login when session-idle-timeout is set.")
+	cmd.Flags().DurationVar(&s.refreshTokenTTL, "refresh-token-ttl", s.refreshTokenTTL, "How long a refresh token can be used to extend the login session. Zero means refresh token is disabled.")
+	cmd.Flags().DurationVar(&s.sessionStoreTTL, "session-store-ttl", s.sessionStoreTTL, "How long the issued sessions are recorded to allow revoking them. This must be longer than the session TTL of all projects. Zero means sessions cannot be revoked. Enabling it logs out the users who logged in while it was disabled.")
+	cmd.Flags().DurationVar(&s.stepUpTTL, "step-up-ttl", s.stepUpTTL, "How long a login session is elevated after the step-up authentication by the WebAuthn credential of the user. The first credential can be registered within this period after login. While it is enabled, the sessions must be elevated to change the static admin, SSO, RBAC and user group settings of the project or to generate an API key. Zero means the step-up authentication is disabled.")
+	cmd.Flags().BoolVar(&s.enableDeviceAuthorization, "enable-device-authorization", s.enableDeviceAuthorization, "Whether to allow the CLI to log in by the device authorization grant of the OIDC providers supporting it.")
+	cmd.Flags().DurationVar(&s.membershipCheckTTL, "membership-check-ttl", s.membershipCheckTTL, "How long the result of looking up the logged-in user at the identity provider is cached. The session of the user removed from the provider or from the groups granting the roles is terminated within this period. The users of the OAuth providers are looked up by the tokens issued at login, so enabling it requests the refresh tokens from the providers issuing the ID tokens. The users of SAML are not looked up. Zero means no lookup.")
+	cmd.Flags().Float64Var(&s.callbackRateLimitPerIP, "callback-rate-limit-per-ip", s.callbackRateLimitPerIP, "The number of auth callback requests per second allowed from each client IP. Zero means no limit.")
+	cmd.Flags().IntVar(&s.callbackRateLimitPerIPBurst, "callback-rate-limit-per-ip-burst", s.callbackRateLimitPerIPBurst, "The burst size of auth callback requests allowed from each client IP.")
+	cmd.Flags().Float64Var(&s.callbackRateLimitPerProject, "callback-rate-limit-per-project", s.callbackRateLimitPerProject, "The number of auth callback requests per second allowed for each project. Zero means no limit.")
+	cmd.Flags().IntVar(&s.callbackRateLimitPerProjectBurst, "callback-rate-limit-per-project-burst", s.callbackRateLimitPerProjectBurst, "The burst size of auth callback requests allowed for each project.")
+	cmd.Flags().Int64Var(&s.callbackMaxBodySize, "callback-max-body-size", s.callbackMaxBodySize, "The maximum size of the body of the auth callback requests in bytes. The larger requests are rejected with 413 Request Entity Too Large.")
+	cmd.Flags().IntVar(&s.callbackLockoutThreshold, "callback-lockout-threshold", s.callbackLockoutThreshold, "The number of failed auth callback validations from each client IP within the lockout window to lock it out. Zero means no lockout.")
+	cmd.Flags().DurationVar(&s.callbackLockoutWindow, "callback-lockout-window", s.callbackLockoutWindow, "The period in which the failed auth callback validations are counted.")
+	cmd.Flags().DurationVar(&s.callbackLockoutCooldown, "callback-lockout-cooldown", s.callbackLockoutCooldown, "The period in which the auth callback requests from a locked out client IP are rejected.")
+	cmd.Flags().StringVar(&s.sessionBinding, "session-binding", s.sessionBinding, "What the sessions are bound to at login, one of none, ip, ua or both. The session used from another client IP or User-Agent than the one at login is terminated. Binding to the IP breaks the sessions of the users changing their networks, e.g. on mobile.")
+	cmd.Flags().StringSliceVar(&s.trustedProxies, "trusted-proxies", s.trustedProxies, "The CIDRs or IP addresses of the trusted proxies, e.g. the load balancer in front of the server. The client IP used by the logs and the rate limits is read from X-Forwarded-For or X-Real-IP only if the request comes from them.")
+	cmd.Flags().StringSliceVar(&s.returnToAllowlist, "return-to-allowlist", s.returnToAllowlist, "The targets allowed to redirect to after login, e.g. /applications, /deployments/* or console.example.com/pipecd/*. The targets not matched are replaced by the root path. Empty means any relative path of the same origin is allowed.")
+	cmd.Flags().StringVar(&s.postLogoutRedirect, "post-logout-redirect", s.postLogoutRedirect, "The page to land on after logout, e.g. /welcome or https://portal.example.com/, which must be allowed by the return_to allowlist. It is also sent to the end session endpoint of the OIDC providers as the post_logout_redirect_uri if it is an absolute URL. Empty means the login page.")
+	cmd.Flags().StringSliceVar(&s.stateOrigins, "state-origins", s.stateOrigins, "The origins of the identity providers, e.g. https://accounts.example.com, allowed to send the auth callback without the state cookie. Such callback is accepted if its Origin or Referer header is one of them, and its state is validated only by the signature, which trades the double-submit state cookie for the origin validation. Empty means the state cookie is always required.")
+	cmd.Flags().StringSliceVar(&s.strictCallbackOrigins, "strict-callback-origins", s.strictCallbackOrigins, "The origins allowed to send the auth callback in POST or with the Origin header, e.g. https://pipecd.example.com for the control plane itself and the origins of the identity providers posting the callback form. Such callback from another origin is rejected as the defense in depth against the login CSRF, while the callback in GET without the Origin header is not checked. Empty means the origin is not checked.")
+	cmd.Flags().StringVar(&s.authContentSecurityPolicy, "auth-content-security-policy", s.authContentSecurityPolicy, "The Content-Security-Policy header of the HTML responses of the auth endpoints. Empty means the default policy which disallows any script.")
+	cmd.Flags().Float64Var(&s.ldapFailedBindRateLimit, "ldap-failed-bind-rate-limit", s.ldapFailedBindRateLimit, "The number of failed LDAP logins per second allowed for each user. Zero means no limit.")
+	cmd.Flags().IntVar(&s.ldapFailedBindRateLimitBurst, "ldap-failed-bind-rate-limit-burst", s.ldapFailedBindRateLimitBurst, "The burst size of failed LDAP logins allowed for each user.")
+	cmd.Flags().StringVar(&s.gravatarFallback, "gravatar-fallback", s.gravatarFallback, "The default image of Gravatar, e.g. identicon, to use the Gravatar of the verified email as the avatar of the user when the identity provider gives no HTTPS avatar. Empty means no fallback.")
+	cmd.Flags().BoolVar(&s.linkIdentitiesByEmail, "link-identities-by-email", s.linkIdentitiesByEmail, "Whether to link the users logged in via the different SSO providers to one user when their emails are verified by all providers and the same.")
+	cmd.Flags().StringSliceVar(&s.identityLinkExcludedEmails, "identity-link-excluded-emails", s.identityLinkExcludedEmails, "The emails never linked, e.g. the shared mailboxes used by multiple users.")
+	cmd.Flags().DurationVar(&s.oidcJWKSCacheTTL, "oidc-jwks-cache-ttl", s.oidcJWKSCacheTTL, "How long to cache the JWKS of OIDC providers when the provider does not specify max-age.")
+	cmd.Flags().DurationVar(&s.oidcDiscoveryCacheTTL, "oidc-discovery-cache-ttl", s.oidcDiscoveryCacheTTL, "How long to cache the discovery documents of OIDC providers.")
+	cmd.Flags().StringSliceVar(&s.oidcAllowedHosts, "oidc-allowed-hosts", s.oidcAllowedHosts, "The hosts of the issuers and the endpoints of OIDC providers which the control plane is allowed to call, e.g. accounts.example.com or *.okta.com to allow the subdomains. The SSO configurations using the other hosts are rejected before sending any request. Empty means any host is allowed.")
+	cmd.Flags().DurationVar(&s.oauthHTTPTimeouts.Connect, "oauth-http-connect-timeout", s.oauthHTTPTimeouts.Connect, "How long to wait for connecting to the OAuth providers including the TLS handshake.")
+	cmd.Flags().DurationVar(&s.oauthHTTPTimeouts.Read, "oauth-http-read-timeout", s.oauthHTTPTimeouts.Read, "How long to wait for the response headers of each request to the OAuth providers.")
+	cmd.Flags().DurationVar(&s.oauthHTTPTimeouts.Total, "oauth-http-timeout", s.oauthHTTPTimeouts.Total, "The time limit of each request to the OAuth providers including reading the response body.")
+	cmd.Flags().StringVar(&s.oauthMinTLSVersion, "oauth-min-tls-version", s.oauthMinTLSVersion, "The minimum TLS version of the connections to the OAuth providers, one of 1.0, 1.1, 1.2 and 1.3. The connections negotiating a lower version fail.")
+	cmd.Flags().StringVar(&s.oauthCABundleFile, "oauth-ca-bundle-file", s.oauthCABundleFile, "The path to the PEM file of the CAs trusted in addition to the system ones to connect to the OAuth providers using the certificates of the private CAs.")
+
 	return cmd
 }
 
@@ -203,6 +363,104 @@ func (s *server) run(ctx context.Context, input cli.Input) error {
 		apiKeyLastUsedCache  = rediscache.NewHashCache(rd, apiKeyLastUsedCacheHashKey)
 	)
 
+	// The session store is optional, without it tokens stay valid until expiring.
+	var sessionStore jwt.SessionStore
+	if s.sessionStoreTTL > 0 {
+		sessionStore = jwt.NewCacheSessionStore(rediscache.NewTTLCache(rd, s.sessionStoreTTL))
+	}
+
+	// The signing keys are loaded from the directory to rotate them without restarting,
+	// otherwise the tokens are signed by the encryption key.
+	// The tokens of another deployment, e.g. staging, are rejected by the issuer and the audience.
+	// The audience is only required if configured, so that the tokens issued without it stay valid.
+	if s.tokenLeeway < 0 || s.tokenLeeway > jwt.MaxLeeway {
+		err := fmt.Errorf("token leeway must be between 0 and %s, got %s", jwt.MaxLeeway, s.tokenLeeway)
+		input.Logger.Error("invalid token leeway", zap.Error(err))
+		return err
+	}
+	tokenOpts := []jwt.Option{jwt.WithIssuer(s.tokenIssuer), jwt.WithAudience(s.tokenAudience), jwt.WithLeeway(s.tokenLeeway)}
+	signingMethod, signingKeyFile := jwtgo.SigningMethod(defaultSigningMethod), s.encryptionKeyFile
+	if s.signingKeyFile != "" || s.signingAlgorithm != "" {
+		if s.signingKeyFile == "" || s.signingAlgorithm == "" {
+			err := fmt.Errorf("signing-key-file and signing-algorithm must be set together")
+			input.Logger.Error("invalid signing key", zap.Error(err))
+			return err
+		}
+		if s.signingKeyDir != "" || s.signingKeyAWSSecret != "" || s.signingKeyGCPSecret != "" {
+			err := fmt.Errorf("signing-key-file cannot be used with signing-key-dir, signing-key-aws-secret or signing-key-gcp-secret")
+			input.Logger.Error("invalid signing key", zap.Error(err))
+			return err
+		}
+		signingMethod, err = jwt.ParseSigningMethod(s.signingAlgorithm)
+		if err != nil {
+			input.Logger.Error("invalid signing algorithm", zap.Error(err))
+			return err
+		}
+		// The key is checked to be of the algorithm before serving.
+		if _, err := jwt.NewSigner(signingMethod, s.signingKeyFile, tokenOpts...); err != nil {
+			input.Logger.Error("failed to load the signing key", zap.Error(err))
+			return err
+		}
+		signingKeyFile = s.signingKeyFile
+	}
+	var keySet *jwt.KeySet
+	keySources := 0
+	for _, v := range []string{s.signingKeyDir, s.signingKeyAWSSecret, s.signingKeyGCPSecret} {
+		if v != "" {
+			keySources++
+		}
+	}
+	if keySources > 0 {
+		if keySources > 1 {
+			err := fmt.Errorf("only one of signing-key-dir, signing-key-aws-secret and signing-key-gcp-secret can be set")
+			input.Logger.Error("invalid signing key", zap.Error(err))
+			return err
+		}
+		if s.signingKeyReloadInterval <= 0 {
+			err := fmt.Errorf("signing key reload interval must be positive, got %s", s.signingKeyReloadInterval)
+			input.Logger.Error("invalid signing key reload interval", zap.Error(err))
+			return err
+		}
+		keySet, err = s.loadSigningKeySet(ctx, group, tokenOpts, input.Logger)
+		if err != nil {
+			input.Logger.Error("failed to load the signing keys", zap.Error(err))
+			return err
+		}
+		input.Logger.Info("loaded the signing keys",
+			zap.String("signing-kid", keySet.PrimaryKeyID()),
+			zap.Strings("kids", keySet.KeyIDs()),
+		)
+	}
+	newSigner := func() (jwt.Signer, error) {
+		if keySet != nil {
+			return keySet, nil
+		}
+		return jwt.NewSigner(signingMethod, signingKeyFile, tokenOpts...)
+	}
+	newVerifier := func() (jwt.Verifier, error) {
+		if keySet != nil {
+			return keySet, nil
+		}
+		return jwt.NewVerifier(signingMethod, signingKeyFile, tokenOpts...)
+	}
+
+	encryptDecrypter, err := crypto.NewAESEncryptDecrypter(s.encryptionKeyFile)
+	if err != nil {
+		input.Logger.Error("failed to create a new AES EncryptDecrypter", zap.Error(err))
+		return err
+	}
+
+	// The membership checker is optional, without it the removed users keep their sessions until expiring.
+	// The users of the OAuth providers are looked up by the tokens issued at login, which are kept as long as the sessions can be extended.
+	var (
+		membershipChecker *httpapi.MembershipChecker
+		grantStore        *httpapi.GrantStore
+	)
+	if s.membershipCheckTTL > 0 {
+		grantStore = httpapi.NewGrantStore(rediscache.NewTTLCache(rd, max(s.sessionMaxLifetime, s.refreshTokenTTL)), encryptDecrypter)
+		membershipChecker = httpapi.NewMembershipChecker(cfg.SharedSSOConfigMap(), datastore.NewProjectStore(ds), encryptDecrypter, grantStore, input.Logger)
+	}
+
 	// Start a gRPC server for handling PipedAPI requests.
 	{
 		var (
@@ -273,19 +531,85 @@ func (s *server) run(ctx context.Context, input cli.Input) error {
 		})
 	}
 
-	encryptDecrypter, err := crypto.NewAESEncryptDecrypter(s.encryptionKeyFile)
+	sameSite, err := httpapi.ParseSameSite(s.cookieSameSite)
 	if err != nil {
-		input.Logger.Error("failed to create a new AES EncryptDecrypter", zap.Error(err))
+		input.Logger.Error("invalid cookie SameSite mode", zap.Error(err))
+		return err
+	}
+	if sameSite == http.SameSiteNoneMode && s.insecureCookie {
+		input.Logger.Warn("SameSite=None requires the Secure attribute, so the session cookies are sent over HTTPS only even though insecure-cookie is set")
+	}
+	if err := httpapi.ValidateCookieDomain(s.cookieDomain, cfg.Address); err != nil {
+		input.Logger.Error("invalid cookie domain", zap.Error(err))
+		return err
+	}
+	if err := httpapi.ValidateCookiePath(s.cookiePath); err != nil {
+		input.Logger.Error("invalid cookie path", zap.Error(err))
+		return err
+	}
+	if err := httpapi.ValidateCookieNamePrefix(s.cookieNamePrefix, s.cookieDomain, s.cookiePath, !s.insecureCookie || sameSite == http.SameSiteNoneMode); err != nil {
+		input.Logger.Error("invalid cookie name prefix", zap.Error(err))
+		return err
+	}
+	// The session cookies issued by both the HTTP server and the WebAPI server share these attributes.
+	cookieOpts := []httpapi.Option{
+		httpapi.WithCookieSameSite(sameSite),
+		httpapi.WithCookieDomain(s.cookieDomain),
+		httpapi.WithCookiePath(s.cookiePath),
+		httpapi.WithCookieNamePrefix(s.cookieNamePrefix),
+	}
+
+	slidingSession := httpapi.SlidingSession{
+		IdleTimeout: s.sessionIdleTimeout,
+		MaxLifetime: s.sessionMaxLifetime,
+	}
+	if err := slidingSession.Validate(); err != nil {
+		input.Logger.Error("invalid sliding session", zap.Error(err))
+		return err
+	}
+	sessionBinding, err := httpapi.ParseSessionBinding(s.sessionBinding)
+	if err != nil {
+		input.Logger.Error("invalid session binding", zap.Error(err))
+		return err
+	}
+	// The client IP is found in the same way by both the HTTP server and the WebAPI server.
+	trustedProxies, err := httpapi.ParseTrustedProxies(s.trustedProxies)
+	if err != nil {
+		input.Logger.Error("invalid trusted proxies", zap.Error(err))
 		return err
 	}
 
 	// Start a gRPC server for handling WebAPI requests.
 	{
-		verifier, err := jwt.NewVerifier(defaultSigningMethod, s.encryptionKeyFile)
+		verifier, err := newVerifier()
 		if err != nil {
 			input.Logger.Error("failed to create a new JWT verifier", zap.Error(err))
 			return err
 		}
+		if sessionStore != nil {
+			verifier = jwt.NewSessionVerifier(verifier, sessionStore)
+		}
+		if membershipChecker != nil {
+			verifier = jwt.NewMembershipVerifier(verifier, membershipChecker, rediscache.NewTTLCache(rd, s.membershipCheckTTL))
+		}
+		jwtOpts := []rpcauth.JWTOption{
+			rpcauth.WithSessionTerminator(httpapi.NewSessionTerminator(!s.insecureCookie, cookieOpts...)),
+			rpcauth.WithTokenCookieName(httpapi.TokenCookieName(s.cookieNamePrefix)),
+		}
+		if s.sessionIdleTimeout > 0 {
+			signer, err := newSigner()
+			if err != nil {
+				input.Logger.Error("failed to create a new signer", zap.Error(err))
+				return err
+			}
+			jwtOpts = append(jwtOpts, rpcauth.WithSessionExtender(httpapi.NewSessionExtender(signer, slidingSession, !s.insecureCookie, cookieOpts...)))
+		}
+		if sessionBinding != httpapi.SessionBindingNone {
+			jwtOpts = append(jwtOpts, rpcauth.WithSessionBinder(httpapi.NewSessionBinder(trustedProxies)))
+		}
+		if s.stepUpTTL > 0 {
+			jwtOpts = append(jwtOpts, rpcauth.WithStepUpMethods(grpcapi.WebAPIStepUpMethods...))
+		}
 
 		service := grpcapi.NewWebAPI(
 			ctx,
@@ -306,7 +630,7 @@ func (s *server) run(ctx context.Context, input cli.Input) error {
 			rpc.WithGracePeriod(s.gracePeriod),
 			rpc.WithLogger(input.Logger),
 			rpc.WithLogUnaryInterceptor(input.Logger),
-			rpc.WithJWTAuthUnaryInterceptor(verifier, webservice.NewRBACAuthorizer(ctx, ds, cfg.ProjectMap(), input.Logger), input.Logger),
+			rpc.WithJWTAuthUnaryInterceptor(verifier, webservice.NewRBACAuthorizer(ctx, ds, cfg.ProjectMap(), input.Logger), input.Logger, jwtOpts...),
 			rpc.WithRequestValidationUnaryInterceptor(),
 		}
 		if s.tls {
@@ -329,12 +653,136 @@ func (s *server) run(ctx context.Context, input cli.Input) error {
 	// such as auth callbacks, webhook events and
 	// serving static assets for web.
 	{
-		signer, err := jwt.NewSigner(defaultSigningMethod, s.encryptionKeyFile)
+		signer, err := newSigner()
 		if err != nil {
 			input.Logger.Error("failed to create a new signer", zap.Error(err))
 			return err
 		}
+		oidc.SetLogger(input.Logger)
+		oidc.SetJWKSCacheTTL(s.oidcJWKSCacheTTL)
+		oidc.SetDiscoveryCacheTTL(s.oidcDiscoveryCacheTTL)
+		if err := oidc.ValidateAllowedHosts(s.oidcAllowedHosts); err != nil {
+			input.Logger.Error("invalid OIDC allowed hosts", zap.Error(err))
+			return err
+		}
+		oidc.SetAllowedHosts(s.oidcAllowedHosts)
+		returnToAllowlist, err := httpapi.ParseReturnToAllowlist(s.returnToAllowlist)
+		if err != nil {
+			input.Logger.Error("invalid return_to allowlist", zap.Error(err))
+			return err
+		}
+		postLogoutRedirect, err := httpapi.ParsePostLogoutRedirect(s.postLogoutRedirect, returnToAllowlist)
+		if err != nil {
+			input.Logger.Error("invalid post logout redirect", zap.Error(err))
+			return err
+		}
+		stateOrigins, err := httpapi.ParseStateOrigins(s.stateOrigins)
+		if err != nil {
+			input.Logger.Error("invalid state origins", zap.Error(err))
+			return err
+		}
+		callbackOrigins, err := httpapi.ParseCallbackOrigins(s.strictCallbackOrigins)
+		if err != nil {
+			input.Logger.Error("invalid strict callback origins", zap.Error(err))
+			return err
+		}
+		if err := s.oauthHTTPTimeouts.Validate(); err != nil {
+			input.Logger.Error("invalid oauth http timeouts", zap.Error(err))
+			return err
+		}
+		oauthTLSConfig, err := oauthhttp.NewTLSConfig(s.oauthMinTLSVersion, s.oauthCABundleFile)
+		if err != nil {
+			input.Logger.Error("invalid oauth tls config", zap.Error(err))
+			return err
+		}
+		if err := httpapi.ValidateContentSecurityPolicy(s.authContentSecurityPolicy); err != nil {
+			input.Logger.Error("invalid auth content security policy", zap.Error(err))
+			return err
+		}
+		if err := httpapi.ValidateGravatarDefaultImage(s.gravatarFallback); err != nil {
+			input.Logger.Error("invalid gravatar fallback", zap.Error(err))
+			return err
+		}
 
+		var stateSecret []byte
+		if s.stateSecretFile != "" {
+			stateSecret, err = os.ReadFile(s.stateSecretFile)
+			if err != nil {
+				input.Logger.Error("failed to read the state secret file", zap.Error(err))
+				return err
+			}
+			if err := httpapi.ValidateStateSecret(stateSecret); err != nil {
+				input.Logger.Error("invalid state secret", zap.Error(err))
+				return err
+			}
+		}
+
+		opts := append(cookieOpts,
+			httpapi.WithStateTTL(s.stateTTL),
+			httpapi.WithTrustedProxies(trustedProxies),
+			httpapi.WithSessionBinding(sessionBinding),
+			httpapi.WithReturnToAllowlist(returnToAllowlist),
+			httpapi.WithPostLogoutRedirect(postLogoutRedirect),
+			httpapi.WithStateOriginCheck(stateOrigins),
+			httpapi.WithStrictCallbackOrigins(callbackOrigins),
+			httpapi.WithContentSecurityPolicy(s.authContentSecurityPolicy),
+			httpapi.WithOAuthHTTPClient(s.oauthHTTPTimeouts, oauthTLSConfig),
+			httpapi.WithCallbackRateLimit(
+				httpapi.RateLimit{RequestsPerSecond: s.callbackRateLimitPerIP, Burst: s.callbackRateLimitPerIPBurst},
+				httpapi.RateLimit{RequestsPerSecond: s.callbackRateLimitPerProject, Burst: s.callbackRateLimitPerProjectBurst},
+			),
+			httpapi.WithMaxCallbackBodySize(s.callbackMaxBodySize),
+			httpapi.WithCallbackLockout(httpapi.Lockout{
+				Threshold: s.callbackLockoutThreshold,
+				Window:    s.callbackLockoutWindow,
+				Cooldown:  s.callbackLockoutCooldown,
+			}),
+			httpapi.WithLDAPFailedBindRateLimit(httpapi.RateLimit{RequestsPerSecond: s.ldapFailedBindRateLimit, Burst: s.ldapFailedBindRateLimitBurst}),
+			httpapi.WithSAMLAssertionCache(rediscache.NewTTLCache(rd, samlAssertionCacheTTL)),
+			// The spans are no-op unless the global tracer provider is registered.
+			httpapi.WithTracerProvider(otel.GetTracerProvider()),
+			httpapi.WithSlidingSession(slidingSession),
+			httpapi.WithGravatarFallback(s.gravatarFallback),
+		)
+		if s.refreshTokenTTL > 0 {
+			opts = append(opts, httpapi.WithRefreshToken(rediscache.NewTTLCache(rd, s.refreshTokenTTL), s.refreshTokenTTL))
+		}
+		if sessionStore != nil {
+			opts = append(opts, httpapi.WithSessionStore(sessionStore))
+		}
+		if membershipChecker != nil {
+			opts = append(opts, httpapi.WithMembershipChecker(membershipChecker), httpapi.WithGrantStore(grantStore))
+		}
+		if stateSecret != nil {
+			opts = append(opts, httpapi.WithStateSecret(stateSecret))
+		}
+		if s.linkIdentitiesByEmail {
+			opts = append(opts, httpapi.WithIdentityLinking(rediscache.NewCache(rd), s.identityLinkExcludedEmails))
+		}
+		// The sessions are verified in the same way as the requests to the web API.
+		verifier, err := newVerifier()
+		if err != nil {
+			input.Logger.Error("failed to create a new JWT verifier", zap.Error(err))
+			return err
+		}
+		if sessionStore != nil {
+			verifier = jwt.NewSessionVerifier(verifier, sessionStore)
+		}
+		if membershipChecker != nil {
+			verifier = jwt.NewMembershipVerifier(verifier, membershipChecker, rediscache.NewTTLCache(rd, s.membershipCheckTTL))
+		}
+		opts = append(opts, httpapi.WithSessionVerifier(verifier))
+		if s.stepUpTTL > 0 {
+			rp, err := webauthn.NewRelyingParty(cfg.Address, "PipeCD")
+			if err != nil {
+				input.Logger.Error("invalid address for the WebAuthn relying party", zap.Error(err))
+				return err
+			}
+			opts = append(opts, httpapi.WithWebAuthnStepUp(rp, verifier, rediscache.NewCache(rd), rediscache.NewTTLCache(rd, webAuthnChallengeCacheTTL), s.stepUpTTL))
+		}
+		if s.enableDeviceAuthorization {
+			opts = append(opts, httpapi.WithDeviceAuthorization(rediscache.NewTTLCache(rd, deviceAuthorizationCacheTTL)))
+		}
 		h := httpapi.NewHandler(
 			signer,
 			s.staticDir,
@@ -346,6 +794,7 @@ func (s *server) run(ctx context.Context, input cli.Input) error {
 			datastore.NewProjectStore(ds),
 			!s.insecureCookie,
 			input.Logger,
+			opts...,
 		)
 		httpServer := &http.Server{
 			Addr:    fmt.Sprintf(":%d", s.httpPort),
@@ -370,7 +819,12 @@ func (s *server) run(ctx context.Context, input cli.Input) error {
 		admin.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
 			w.Write([]byte("ok"))
 		})
+		admin.Handle("/healthz/sso", httpapi.NewSSOReadinessHandler(cfg.SharedSSOConfigMap(), datastore.NewProjectStore(ds), ssoReadinessCacheTTL, input.Logger))
+		admin.Handle("/sso/validate", httpapi.NewSSOValidationHandler(input.Logger))
 		admin.Handle("/metrics", input.PrometheusMetricsHandlerFor(reg))
+		if sessionStore != nil {
+			admin.Handle("/sessions/revoke", httpapi.NewRevokeSessionsHandler(sessionStore, input.Logger))
+		}
 
 		group.Go(func() error {
 			return admin.Run(ctx)
@@ -537,6 +991,46 @@ func createFilestore(ctx context.Context, cfg *config.ControlPlaneSpec, logger *
 	}
 }
 
+// loadSigningKeySet loads the RS256 keys signing the tokens from the signing key directory or the secret manager,
+// and keeps reloading them at the reload interval until the context is done.
+func (s *server) loadSigningKeySet(ctx context.Context, group *errgroup.Group, opts []jwt.Option, logger *zap.Logger) (*jwt.KeySet, error) {
+	if s.signingKeyDir != "" {
+		keySet, err := jwt.NewKeySetFromDir(jwtgo.SigningMethodRS256, s.signingKeyDir, opts...)
+		if err != nil {
+			return nil, err
+		}
+		group.Go(func() error {
+			keySet.WatchDir(ctx, s.signingKeyDir, s.signingKeyReloadInterval, logger)
+			return nil
+		})
+		return keySet, nil
+	}
+
+	var (
+		source *keysource.SecretSource
+		err    error
+	)
+	if s.signingKeyAWSSecret != "" {
+		source, err = keysource.NewAWSSecretsManager(ctx, s.signingKeyAWSSecret)
+	} else {
+		source, err = keysource.NewGCPSecretManager(ctx, s.signingKeyGCPSecret)
+	}
+	if err != nil {
+		return nil, err
+	}
+	keySet, err := jwt.NewKeySetFromSource(ctx, jwtgo.SigningMethodRS256, source, opts...)
+	if err != nil {
+		source.Close()
+		return nil, err
+	}
+	group.Go(func() error {
+		defer source.Close()
+		keySet.WatchSource(ctx, source, s.signingKeyReloadInterval, logger)
+		return nil
+	})
+	return keySet, nil
+}
+
 func registerMetrics() *prometheus.Registry {
 	r := prometheus.NewRegistry()
 	wrapped := prometheus.WrapRegistererWith(map[string]string{
@@ -549,6 +1043,8 @@ func registerMetrics() *prometheus.Registry {
 
 	cachemetrics.Register(wrapped)
 	httpapimetrics.Register(wrapped)
+	jwtmetrics.Register(wrapped)
+	oidcmetrics.Register(wrapped)
 	grpcapimetrics.Register(wrapped)
 
 	return r
@@ -21,7 +21,9 @@ import (
 	"html"
 	"html/template"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -274,35 +276,78 @@ func (h *Handler) handleAddProject(w http.ResponseWriter, r *http.Request) {
 		description        = html.EscapeString(r.FormValue("Description"))
 		sharedSSOName      = html.EscapeString(r.FormValue("SharedSSO"))
 		allowStrayAsViewer = r.FormValue("AllowStrayAsViewer") == "on"
+		defaultRole        = r.FormValue("DefaultRole")
 	)
 	if id == "" {
 		http.Error(w, "invalid id", http.StatusBadRequest)
 		return
 	}
-	if sharedSSOName != "" {
+	emailDomainRoles, err := parseEmailDomainRoles(r.FormValue("EmailDomainRoles"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid email domain roles: %v", err), http.StatusBadRequest)
+		return
+	}
+	loginThrottle, err := parseLoginThrottle(
+		r.FormValue("LoginCallbackRateLimit"),
+		r.FormValue("LoginCallbackRateLimitBurst"),
+		r.FormValue("LoginLockoutThreshold"),
+		r.FormValue("LoginLockoutWindow"),
+		r.FormValue("LoginLockoutCooldown"),
+	)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid login throttling: %v", err), http.StatusBadRequest)
+		return
+	}
+	var additionalSharedSSONames []string
+	for _, name := range strings.Split(html.EscapeString(r.FormValue("AdditionalSharedSSOs")), ",") {
+		if name = strings.TrimSpace(name); name != "" && !slices.Contains(additionalSharedSSONames, name) {
+			additionalSharedSSONames = append(additionalSharedSSONames, name)
+		}
+	}
+	for _, name := range append([]string{sharedSSOName}, additionalSharedSSONames...) {
+		if name == "" {
+			continue
+		}
 		found := false
 		for i := range h.sharedSSOConfigs {
-			if h.sharedSSOConfigs[i].Name == sharedSSOName {
+			if h.sharedSSOConfigs[i].Name == name {
 				found = true
 				break
 			}
 		}
 		if !found {
-			http.Error(w, fmt.Sprintf("SharedSSOConfig %q was not found in Control Plane configuration", sharedSSOName), http.StatusBadRequest)
+			http.Error(w, fmt.Sprintf("SharedSSOConfig %q was not found in Control Plane configuration", name), http.StatusBadRequest)
 			return
 		}
 	}
 
 	var (
 		project = &model.Project{
-			Id:                 id,
-			Desc:               description,
-			SharedSsoName:      sharedSSOName,
-			AllowStrayAsViewer: allowStrayAsViewer,
+			Id:                          id,
+			Desc:                        description,
+			SharedSsoName:               sharedSSOName,
+			AdditionalSharedSsoNames:    additionalSharedSSONames,
+			AllowStrayAsViewer:          allowStrayAsViewer,
+			DefaultRole:                 defaultRole,
+			EmailDomainRoles:            emailDomainRoles,
+			LoginCallbackRateLimit:      loginThrottle.LoginCallbackRateLimit,
+			LoginCallbackRateLimitBurst: loginThrottle.LoginCallbackRateLimitBurst,
+			LoginLockoutThreshold:       loginThrottle.LoginLockoutThreshold,
+			LoginLockoutWindow:          loginThrottle.LoginLockoutWindow,
+			LoginLockoutCooldown:        loginThrottle.LoginLockoutCooldown,
 		}
 		username = model.GenerateRandomString(10)
 		password = model.GenerateRandomString(30)
 	)
+	// Only the built-in roles exist in the new project.
+	if err := project.ValidateDefaultRole(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid default role: %v", err), http.StatusBadRequest)
+		return
+	}
+	if err := project.ValidateEmailDomainRoles(); err != nil {
+		http.Error(w, fmt.Sprintf("invalid email domain roles: %v", err), http.StatusBadRequest)
+		return
+	}
 
 	if err := project.SetStaticAdmin(username, password); err != nil {
 		h.logger.Error("failed to set static admin",
@@ -337,3 +382,64 @@ func (h *Handler) handleAddProject(w http.ResponseWriter, r *http.Request) {
 		h.logger.Error("failed to render AddedProject page template", zap.Error(err))
 	}
 }
+
+// parseEmailDomainRoles parses the comma separated mappings of the email domain to the role,
+// e.g. "admin.example.com=Admin, example.com=Viewer".
+func parseEmailDomainRoles(s string) ([]*model.ProjectEmailDomainRole, error) {
+	var roles []*model.ProjectEmailDomainRole
+	for _, m := range strings.Split(s, ",") {
+		if m = strings.TrimSpace(m); m == "" {
+			continue
+		}
+		domain, role, ok := strings.Cut(m, "=")
+		domain, role = strings.TrimPrefix(strings.TrimSpace(domain), "@"), strings.TrimSpace(role)
+		if !ok || domain == "" || role == "" {
+			return nil, fmt.Errorf("%q must be in the form of domain=role", m)
+		}
+		roles = append(roles, &model.ProjectEmailDomainRole{Domain: domain, Role: role})
+	}
+	return roles, nil
+}
+
+// parseLoginThrottle parses the login throttling settings of the project given by the form.
+// The rate limit is the number of the callbacks per minute, and the window and the cooldown are durations such as "10m".
+// Empty means not set, then the limits of the control plane are applied.
+func parseLoginThrottle(rateLimit, burst, threshold, window, cooldown string) (*model.Project, error) {
+	p := &model.Project{}
+	for _, f := range []struct {
+		name  string
+		value string
+		dst   *int32
+	}{
+		{"rate limit", rateLimit, &p.LoginCallbackRateLimit},
+		{"burst", burst, &p.LoginCallbackRateLimitBurst},
+		{"lockout threshold", threshold, &p.LoginLockoutThreshold},
+	} {
+		if f.value = strings.TrimSpace(f.value); f.value == "" {
+			continue
+		}
+		n, err := strconv.ParseInt(f.value, 10, 32)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative integer: %q", f.name, f.value)
+		}
+		*f.dst = int32(n)
+	}
+	for _, f := range []struct {
+		name  string
+		value string
+		dst   *int64
+	}{
+		{"lockout window", window, &p.LoginLockoutWindow},
+		{"lockout cooldown", cooldown, &p.LoginLockoutCooldown},
+	} {
+		if f.value = strings.TrimSpace(f.value); f.value == "" {
+			continue
+		}
+		d, err := time.ParseDuration(f.value)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("%s must be a non-negative duration such as 10m: %q", f.name, f.value)
+		}
+		*f.dst = int64(d.Seconds())
+	}
+	return p, nil
+}
@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"go.uber.org/zap"
 
@@ -347,3 +348,43 @@ Unable to fetch application counts (No data for this project).
 		})
 	}
 }
+
+func TestParseEmailDomainRoles(t *testing.T) {
+	roles, err := parseEmailDomainRoles(" admin.example.com=Admin, @example.com = Viewer ,")
+	assert.NoError(t, err)
+	assert.Equal(t, []*model.ProjectEmailDomainRole{
+		{Domain: "admin.example.com", Role: "Admin"},
+		{Domain: "example.com", Role: "Viewer"},
+	}, roles)
+
+	roles, err = parseEmailDomainRoles("")
+	assert.NoError(t, err)
+	assert.Nil(t, roles)
+
+	_, err = parseEmailDomainRoles("example.com")
+	assert.Error(t, err)
+	_, err = parseEmailDomainRoles("=Admin")
+	assert.Error(t, err)
+}
+
+func TestParseLoginThrottle(t *testing.T) {
+	p, err := parseLoginThrottle("30", "", " 5 ", "10m", "1h")
+	require.NoError(t, err)
+	assert.Equal(t, int32(30), p.LoginCallbackRateLimit)
+	assert.Equal(t, int32(0), p.LoginCallbackRateLimitBurst)
+	assert.Equal(t, int32(5), p.LoginLockoutThreshold)
+	assert.Equal(t, int64(600), p.LoginLockoutWindow)
+	assert.Equal(t, int64(3600), p.LoginLockoutCooldown)
+
+	p, err = parseLoginThrottle("", "", "", "", "")
+	require.NoError(t, err)
+	assert.Zero(t, p.LoginCallbackRateLimit)
+	assert.Zero(t, p.LoginLockoutWindow)
+
+	_, err = parseLoginThrottle("-1", "", "", "", "")
+	assert.Error(t, err)
+	_, err = parseLoginThrottle("", "many", "", "", "")
+	assert.Error(t, err)
+	_, err = parseLoginThrottle("", "", "", "10", "")
+	assert.Error(t, err)
+}
@@ -118,6 +118,21 @@ type webAPIAPIKeyLastUsedStore interface {
 	Get(k string) (interface{}, error)
 }
 
+// WebAPIStepUpMethods are the methods of WebAPI changing how the users of the project log in or what they are granted,
+// which require the session elevated by the step-up authentication while it is enabled.
+var WebAPIStepUpMethods = []string{
+	"/grpc.service.webservice.WebService/UpdateProjectStaticAdmin",
+	"/grpc.service.webservice.WebService/EnableStaticAdmin",
+	"/grpc.service.webservice.WebService/UpdateProjectSSOConfig",
+	"/grpc.service.webservice.WebService/UpdateProjectRBACConfig",
+	"/grpc.service.webservice.WebService/AddProjectRBACRole",
+	"/grpc.service.webservice.WebService/UpdateProjectRBACRole",
+	"/grpc.service.webservice.WebService/DeleteProjectRBACRole",
+	"/grpc.service.webservice.WebService/AddProjectUserGroup",
+	"/grpc.service.webservice.WebService/DeleteProjectUserGroup",
+	"/grpc.service.webservice.WebService/GenerateAPIKey",
+}
+
 // WebAPI implements the behaviors for the gRPC definitions of WebAPI.
 type WebAPI struct {
 	webservice.UnimplementedWebServiceServer
@@ -1403,6 +1418,39 @@ func (a *WebAPI) UpdateProjectSSOConfig(ctx context.Context, req *webservice.Upd
 		return nil, status.Error(codes.FailedPrecondition, "Failed to update a debug project specified in the control-plane configuration")
 	}
 
+	// The default role and the email domain roles are granted via the SSO configuration,
+	// so they must be roles of the project before enabling it.
+	project, err := a.getProject(ctx, claims.Role.ProjectId)
+	if err != nil {
+		return nil, err
+	}
+	if err := project.ValidateDefaultRole(); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("Invalid default role of the project: %v", err))
+	}
+	if err := project.ValidateEmailDomainRoles(); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, fmt.Sprintf("Invalid email domain roles of the project: %v", err))
+	}
+
+	if github := req.Sso.GetGithub(); github != nil {
+		if err := github.ValidateURLs(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Invalid GitHub SSO configuration: %v", err))
+		}
+		if err := github.ValidateExtraHeaders(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Invalid GitHub SSO configuration: %v", err))
+		}
+	}
+	if oidc := req.Sso.GetOidc(); oidc != nil {
+		if err := oidc.ValidateScopes(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Invalid OIDC SSO configuration: %v", err))
+		}
+		if err := oidc.ValidateClientAuthentication(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Invalid OIDC SSO configuration: %v", err))
+		}
+		if err := oidc.ValidateExtraHeaders(); err != nil {
+			return nil, status.Error(codes.InvalidArgument, fmt.Sprintf("Invalid OIDC SSO configuration: %v", err))
+		}
+	}
+
 	if err := req.Sso.Encrypt(a.encrypter); err != nil {
 		a.logger.Error("failed to encrypt sensitive data in sso configurations", zap.Error(err))
 		return nil, gRPCStoreError(err, "encrypt sensitive data in sso configurations")
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 
+	"github.com/pipe-cd/pipecd/pkg/app/server/service/webservice"
 	"github.com/pipe-cd/pipecd/pkg/cache"
 	"github.com/pipe-cd/pipecd/pkg/cache/cachetest"
 	"github.com/pipe-cd/pipecd/pkg/datastore"
@@ -29,6 +30,16 @@ import (
 	"github.com/pipe-cd/pipecd/pkg/model"
 )
 
+func TestWebAPIStepUpMethods(t *testing.T) {
+	methods := make(map[string]bool, len(webservice.WebService_ServiceDesc.Methods))
+	for _, m := range webservice.WebService_ServiceDesc.Methods {
+		methods["/"+webservice.WebService_ServiceDesc.ServiceName+"/"+m.MethodName] = true
+	}
+	for _, m := range WebAPIStepUpMethods {
+		assert.True(t, methods[m], m)
+	}
+}
+
 func TestValidateAppBelongsToProject(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
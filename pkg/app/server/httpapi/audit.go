@@ -0,0 +1,107 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/pipe-cd/pipecd/pkg/app/server/httpapi/httpapimetrics"
+)
+
+// staticAdminProvider is the provider name of the login events by the static admin.
+const staticAdminProvider = "STATIC_ADMIN"
+
+// loginFailureReason is the coarse reason of a failed login used to label the metrics.
+type loginFailureReason string
+
+const (
+	failureReasonState           loginFailureReason = "state"
+	failureReasonMissingCode     loginFailureReason = "missing_code"
+	failureReasonCodeExpired     loginFailureReason = "code_expired"
+	failureReasonProjectNotFound loginFailureReason = "project_not_found"
+	failureReasonUnavailable     loginFailureReason = "project_unavailable"
+	failureReasonDecrypt         loginFailureReason = "decrypt"
+	failureReasonUserLookup      loginFailureReason = "user_lookup"
+	failureReasonSign            loginFailureReason = "sign"
+	failureReasonInvalidRequest  loginFailureReason = "invalid_request"
+	failureReasonInvalidConfig   loginFailureReason = "invalid_config"
+	failureReasonForbidden       loginFailureReason = "forbidden"
+	failureReasonCredentials     loginFailureReason = "credentials"
+)
+
+// LoginEvent is an audit event of a login attempt.
+// The fields which were not known at the time of failure are left empty.
+type LoginEvent struct {
+	Timestamp time.Time
+	Username  string
+	ProjectID string
+	Provider  string
+	SourceIP  string
+	Success   bool
+	// IdPInitiated is whether the login was started by the identity provider without the state.
+	IdPInitiated bool
+	// ErrorCode is the category of the failure.
+	ErrorCode string
+	// FailureReason is the message responded to the user.
+	FailureReason string
+}
+
+// AuditRecorder records the audit events of authentication.
+type AuditRecorder interface {
+	RecordLogin(ctx context.Context, event LoginEvent)
+}
+
+type nopAuditRecorder struct{}
+
+func (nopAuditRecorder) RecordLogin(context.Context, LoginEvent) {}
+
+// WithAuditRecorder sets the recorder of login events.
+func WithAuditRecorder(r AuditRecorder) Option {
+	return func(h *authHandler) {
+		h.auditRecorder = r
+	}
+}
+
+func (h *authHandler) newLoginEvent(r *http.Request) *LoginEvent {
+	return &LoginEvent{
+		SourceIP: h.clientIP(r),
+	}
+}
+
+func (h *authHandler) recordLogin(r *http.Request, event *LoginEvent) {
+	httpapimetrics.IncLoginAttempts(event.Provider, event.ProjectID)
+	if event.Success {
+		httpapimetrics.IncLoginSuccesses(event.Provider, event.ProjectID)
+	}
+	if h.auditRecorder == nil {
+		return
+	}
+	event.Timestamp = h.now()
+	h.auditRecorder.RecordLogin(r.Context(), *event)
+}
+
+// handleLoginError records the failed login event and then handles the error.
+func (h *authHandler) handleLoginError(w http.ResponseWriter, r *http.Request, event *LoginEvent, reason loginFailureReason, code errorCode, responseMessage string, err error) {
+	event.Success = false
+	event.ErrorCode = string(code)
+	event.FailureReason = responseMessage
+	h.recordLogin(r, event)
+	httpapimetrics.IncLoginFailures(event.Provider, event.ProjectID, string(reason))
+	h.recordCallbackFailure(r, event.ProjectID, reason)
+	recordSpanFailure(r, reason)
+	h.handleProviderError(w, r, event.ProjectID, event.Provider, code, responseMessage, err)
+}
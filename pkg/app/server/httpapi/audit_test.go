@@ -0,0 +1,147 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/pipe-cd/pipecd/pkg/config"
+)
+
+type fakeAuditRecorder struct {
+	mu     sync.Mutex
+	events []LoginEvent
+}
+
+func (r *fakeAuditRecorder) RecordLogin(_ context.Context, event LoginEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func TestStaticAdminLoginAudit(t *testing.T) {
+	t.Parallel()
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		form     url.Values
+		expected LoginEvent
+	}{
+		{
+			name: "success",
+			form: url.Values{projectFormKey: {"project"}, usernameFormKey: {"admin"}, passwordFormKey: {"password"}},
+			expected: LoginEvent{
+				Username:  "admin",
+				ProjectID: "project",
+				Provider:  staticAdminProvider,
+				SourceIP:  "192.0.2.1",
+				Success:   true,
+			},
+		},
+		{
+			name: "wrong password",
+			form: url.Values{projectFormKey: {"project"}, usernameFormKey: {"admin"}, passwordFormKey: {"wrong"}},
+			expected: LoginEvent{
+				Username:      "admin",
+				ProjectID:     "project",
+				Provider:      staticAdminProvider,
+				SourceIP:      "192.0.2.1",
+				ErrorCode:     string(errCodeUnauthorized),
+				FailureReason: "Unable to login",
+			},
+		},
+		{
+			name: "missing project",
+			form: url.Values{},
+			expected: LoginEvent{
+				Provider:      staticAdminProvider,
+				SourceIP:      "192.0.2.1",
+				ErrorCode:     string(errCodeInvalidRequest),
+				FailureReason: "Missing project id",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			recorder := &fakeAuditRecorder{}
+			h := newAuthHandler(
+				fakeSigner{},
+				nil,
+				"https://pipecd.dev",
+				"state-key",
+				map[string]config.ControlPlaneProject{
+					"project": {
+						ID:          "project",
+						StaticAdmin: config.ProjectStaticUser{Username: "admin", PasswordHash: string(hash)},
+					},
+				},
+				nil,
+				fakeProjectGetter{},
+				true,
+				zap.NewNop(),
+				WithAuditRecorder(recorder),
+			)
+
+			req := httptest.NewRequest(http.MethodPost, staticLoginPath, strings.NewReader(tt.form.Encode()))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			req.RemoteAddr = "192.0.2.1:1234"
+			h.handleStaticAdminLogin(httptest.NewRecorder(), req)
+
+			require.Len(t, recorder.events, 1)
+			got := recorder.events[0]
+			assert.False(t, got.Timestamp.IsZero())
+			got.Timestamp = tt.expected.Timestamp
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestCallbackAuditBeforeUserKnown(t *testing.T) {
+	t.Parallel()
+	recorder := &fakeAuditRecorder{}
+	h := &authHandler{
+		stateKey:      "state-key",
+		projectGetter: fakeProjectGetter{},
+		auditRecorder: recorder,
+		logger:        zap.NewNop(),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, callbackPath+"?state=invalid:project%3Dproject", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	h.handleCallback(httptest.NewRecorder(), req)
+
+	require.Len(t, recorder.events, 1)
+	got := recorder.events[0]
+	assert.False(t, got.Success)
+	assert.Equal(t, "project", got.ProjectID)
+	assert.Equal(t, "192.0.2.1", got.SourceIP)
+	assert.Equal(t, string(errCodeStateInvalid), got.ErrorCode)
+	assert.Empty(t, got.Username)
+}
@@ -16,16 +16,28 @@ package httpapi
 
 import (
 	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"maps"
+	"net"
 	"net/http"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
+	"golang.org/x/oauth2"
 
+	"github.com/pipe-cd/pipecd/pkg/cache"
 	"github.com/pipe-cd/pipecd/pkg/config"
 	"github.com/pipe-cd/pipecd/pkg/jwt"
 	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oidc"
 )
 
 const (
@@ -34,24 +46,54 @@ const (
 	loginPath = "/auth/login"
 	// staticLoginPath is the path to login to pipecd projects with password.
 	staticLoginPath = "/auth/login/static"
+	// ldapLoginPath is the path to login to pipecd projects with the credentials of the LDAP directory.
+	ldapLoginPath = "/auth/login/ldap"
+	// samlACSPath is the path of the assertion consumer service receiving the SAML responses.
+	samlACSPath = "/auth/saml/acs"
 	// callbackPath is the path configured in the GitHub oauth application settings.
 	callbackPath = "/auth/callback"
 	// logoutPath is the path for logging out from current session.
 	logoutPath = "/auth/logout"
+	// refreshPath is the path to extend current session by using the refresh token.
+	refreshPath = "/auth/refresh"
+	// webAuthnRegisterBeginPath and webAuthnRegisterFinishPath are the paths of the registration ceremony
+	// of the WebAuthn credentials used by the step-up authentication.
+	webAuthnRegisterBeginPath  = "/auth/webauthn/register/begin"
+	webAuthnRegisterFinishPath = "/auth/webauthn/register/finish"
+	// webAuthnAssertBeginPath and webAuthnAssertFinishPath are the paths of the assertion ceremony
+	// elevating the current session by the step-up authentication.
+	webAuthnAssertBeginPath  = "/auth/webauthn/assert/begin"
+	webAuthnAssertFinishPath = "/auth/webauthn/assert/finish"
+	// deviceAuthorizePath and deviceTokenPath are the paths of the device authorization grant used by the CLI.
+	deviceAuthorizePath = "/auth/device/authorize"
+	deviceTokenPath     = "/auth/device/token"
+	// mePath is the path to introspect the session of the logged-in user.
+	mePath = "/auth/me"
+	// providersPath is the path to list the SSO configurations of a project before login.
+	providersPath = "/auth/providers"
+	// backchannelLogoutPath is the path the OIDC providers post the logout tokens to.
+	backchannelLogoutPath = "/auth/backchannel_logout"
 
-	projectFormKey  = "project"
-	usernameFormKey = "username"
-	passwordFormKey = "password"
-	authCodeFormKey = "code"
-	stateFormKey    = "state"
+	projectFormKey     = "project"
+	usernameFormKey    = "username"
+	passwordFormKey    = "password"
+	authCodeFormKey    = "code"
+	stateFormKey       = "state"
+	ssoFormKey         = "sso"
+	logoutTokenFormKey = "logout_token"
 
-	stateCookieKey = "state"
-	errorCookieKey = "error"
+	stateCookieKey        = "state"
+	errorCookieKey        = "error"
+	codeVerifierCookieKey = "code_verifier"
+	nonceCookieKey        = "nonce"
+	idTokenCookieKey      = "id_token"
 
-	defaultTokenTTL          = 7 * 24 * time.Hour
-	defaultStateCookieMaxAge = 30 * 60
-	defaultErrorCookieMaxAge = 10 * 60
-	defaultTokenCookieMaxAge = 7 * 24 * 60 * 60
+	defaultTokenTTL = 7 * 24 * time.Hour
+	defaultStateTTL = 30 * time.Minute
+	// defaultMaxCallbackBodySize is large enough for the form posted by the providers, e.g. the ID token.
+	defaultMaxCallbackBodySize = 64 << 10
+	defaultErrorCookieMaxAge   = 10 * 60
+	defaultTokenCookieMaxAge   = 7 * 24 * 60 * 60
 )
 
 type projectGetter interface {
@@ -64,15 +106,102 @@ type decrypter interface {
 
 // authHandler handles all imcoming requests about authentication.
 type authHandler struct {
-	signer           jwt.Signer
-	decrypter        decrypter
-	callbackURL      string
-	stateKey         string
+	signer      jwt.Signer
+	decrypter   decrypter
+	callbackURL string
+	samlACSURL  string
+	stateKey    string
+	// stateTTL is how long the state of the OAuth flow is valid.
+	stateTTL time.Duration
+	// stateAEAD seals the state of the OAuth flow. Nil means the state is only signed
+	// and the data of the login flow is kept in the cookies.
+	stateAEAD cipher.AEAD
+	// stateOrigins are the origins of the identity providers allowed to send the callback requests
+	// without the state cookie. Empty means the state cookie is always required.
+	stateOrigins StateOrigins
+	// callbackOrigins are the origins allowed to send the callback requests in POST or with the Origin header.
+	// Empty means the origin is not checked.
+	callbackOrigins  CallbackOrigins
 	projectsInConfig map[string]config.ControlPlaneProject
 	sharedSSOConfigs map[string]*model.ProjectSSOConfig
 	projectGetter    projectGetter
-	secureCookie     bool
-	logger           *zap.Logger
+	// projectLookupTimeout bounds each attempt to look up the project of the login.
+	// Zero means the default timeout.
+	projectLookupTimeout time.Duration
+	// decryptedSSOs caches the decrypted SSO configurations of the projects.
+	// Nil means decrypting them on every request.
+	decryptedSSOs *decryptedSSOCache
+	// cookies makes the cookies with the configured security attributes.
+	cookies cookieFactory
+	// contentSecurityPolicy is the Content-Security-Policy header of the HTML responses.
+	// Empty means the default policy.
+	contentSecurityPolicy string
+	// refreshTokens stores the issued refresh tokens. Nil means refresh token is disabled.
+	refreshTokens   cache.Cache
+	refreshTokenTTL time.Duration
+	// callbackIPLimiter and callbackProjectLimiter throttle the callback requests.
+	// Nil means no limit.
+	callbackIPLimiter      *keyedLimiter
+	callbackProjectLimiter *keyedLimiter
+	// projectThrottles throttle the callback requests by the settings of each project
+	// under the limits of the control plane. Nil means only the limits of the control plane are applied.
+	projectThrottles *projectThrottles
+	// maxCallbackBodySize is the maximum size of the body of the callback requests in bytes.
+	// Zero means the default size.
+	maxCallbackBodySize int64
+	// callbackLockout rejects the client IPs which repeatedly failed the callback validations.
+	// Nil means no lockout.
+	callbackLockout *lockoutTracker
+	// trustedProxies are the proxies whose forwarded headers are used to find the client IP.
+	// Nil means the headers are ignored.
+	trustedProxies []*net.IPNet
+	// returnToAllowlist restricts the targets to redirect to after login.
+	// Empty means any relative path of the same origin is allowed.
+	returnToAllowlist ReturnToAllowlist
+	// postLogoutRedirect is the page to land on after logout. Empty means the login page.
+	postLogoutRedirect string
+	// sessionStore records the issued tokens. Nil means sessions cannot be revoked.
+	sessionStore jwt.SessionStore
+	// sessionBinding is what the sessions issued at login are bound to. Empty means SessionBindingNone.
+	sessionBinding SessionBinding
+	// membershipChecker looks up the users refreshing their sessions. Nil means no lookup.
+	membershipChecker jwt.MembershipChecker
+	// grants keeps the tokens issued by the OAuth providers at login. Nil means they are discarded.
+	grants *GrantStore
+	// slidingSession limits the TTL of the tokens issued at login to its idle timeout.
+	slidingSession SlidingSession
+	// ldapBindLimiter throttles the failed LDAP binds. Nil means no limit.
+	ldapBindLimiter *keyedLimiter
+	newLDAPClient   func(*model.ProjectSSOConfig_Ldap) (ldapAuthenticator, error)
+	// samlAssertions records the consumed SAML assertions. Nil means SAML login is rejected.
+	samlAssertions cache.Cache
+	newSAMLClient  func(context.Context, *model.ProjectSSOConfig_Saml, string, cache.Cache) (samlServiceProvider, error)
+	auditRecorder  AuditRecorder
+	// oauthHTTPClient is used to call the OAuth providers.
+	// Nil means the client bounded by the default timeouts with the default TLS configuration.
+	oauthHTTPClient *http.Client
+	// userResolvers resolve the users logging in via each OAuth provider.
+	// Nil means the default resolvers.
+	userResolvers map[model.ProjectSSOConfig_Provider]UserResolver
+	// gravatarDefaultImage is the default image of the Gravatar used when the user has no avatar.
+	// Empty means the Gravatar fallback is disabled.
+	gravatarDefaultImage string
+	// identityLinker links the identities of the different SSO providers. Nil means no linking.
+	identityLinker *identityLinker
+	// webAuthn elevates the sessions by the WebAuthn assertions. Nil means the step-up authentication is disabled.
+	webAuthn *webAuthnStepUp
+	// sessionVerifier verifies the token cookies of the session introspection.
+	// Nil means the session introspection is disabled.
+	sessionVerifier jwt.Verifier
+	// deviceSessions stores the device authorizations in progress. Nil means the device authorization is disabled.
+	deviceSessions cache.Cache
+	// clock and rand are the sources of the time and the randomness.
+	// Nil means the real time and crypto/rand.
+	clock Clock
+	rand  io.Reader
+	// tracer traces the login flow. Nil means no-op.
+	tracer trace.Tracer
+	logger *zap.Logger
 }
 
 // newHandler returns a handler that will used for authentication.
@@ -86,28 +215,105 @@ func newAuthHandler(
 	projectGetter projectGetter,
 	secureCookie bool,
 	logger *zap.Logger,
+	opts ...Option,
 ) *authHandler {
-	return &authHandler{
+	h := &authHandler{
 		signer:           signer,
 		decrypter:        decrypter,
 		callbackURL:      strings.TrimSuffix(address, "/") + callbackPath,
+		samlACSURL:       strings.TrimSuffix(address, "/") + samlACSPath,
 		stateKey:         stateKey,
+		stateTTL:         defaultStateTTL,
 		projectsInConfig: projectsInConfig,
 		sharedSSOConfigs: sharedSSOConfigs,
 		projectGetter:    projectGetter,
-		secureCookie:     secureCookie,
+		decryptedSSOs:    newDecryptedSSOCache(defaultDecryptedSSOTTL),
+		projectThrottles: newProjectThrottles(),
+		cookies:          cookieFactory{secure: secureCookie},
+		ldapBindLimiter:  newKeyedLimiter(defaultLDAPFailedBindRateLimit),
+		newLDAPClient:    newLDAPClient,
+		newSAMLClient:    newSAMLClient,
+		auditRecorder:    nopAuditRecorder{},
+		userResolvers:    maps.Clone(defaultUserResolvers),
+		clock:            realClock{},
+		rand:             rand.Reader,
 		logger:           logger,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
-// handleLogout cleans current cookies and redirects to login page.
+// handleLogout cleans current cookies and redirects to the post logout page, which is the login page by default.
+// If the user logged in via an OIDC provider supporting the end session endpoint,
+// it redirects to that endpoint to log the user out from the provider as well.
 func (h *authHandler) handleLogout(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
+	h.setContentSecurityPolicy(w)
+
+	if c, err := r.Cookie(refreshTokenCookieKey); err == nil && h.refreshTokens != nil {
+		if _, err := h.revokeRefreshToken(c.Value); err != nil {
+			h.logger.Info("auth-handler: failed to revoke refresh token", zap.Error(err))
+		}
+	}
+
+	redirectURL := h.postLogoutRedirectURL()
+	if c, err := r.Cookie(idTokenCookieKey); err == nil {
+		u, err := h.endSessionURL(c.Value)
+		if err != nil {
+			h.logger.Warn("auth-handler: failed to make end session url, skip logging out from the provider", zap.Error(err))
+		} else {
+			redirectURL = u
+		}
+	}
+
+	http.SetCookie(w, h.cookies.expiredToken())
+	http.SetCookie(w, h.cookies.expiredState())
+	if h.cookies.domain != "" {
+		// Also clear the host-only token cookie issued before the domain was configured.
+		http.SetCookie(w, h.cookies.expiredHostOnlyToken())
+	}
+	http.SetCookie(w, h.cookies.expiredCodeVerifier())
+	http.SetCookie(w, h.cookies.expiredNonce())
+	http.SetCookie(w, h.cookies.expiredIDToken())
+	http.SetCookie(w, h.cookies.expiredRefreshToken())
+	http.SetCookie(w, h.cookies.expiredReturnTo())
+	http.SetCookie(w, h.cookies.expiredSAMLRequest())
+
+	http.Redirect(w, r, redirectURL, http.StatusFound)
+}
+
+func (h *authHandler) endSessionURL(cookie string) (string, error) {
+	projectID, ssoName, idToken, err := parseIDTokenCookie(cookie)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 
-	http.SetCookie(w, makeExpiredTokenCookie(h.secureCookie))
-	http.SetCookie(w, makeExpiredStateCookie(h.secureCookie))
+	proj, err := h.projectGetter.Get(ctx, projectID)
+	if err != nil {
+		return "", err
+	}
+	sso, shared, err := h.findSSOConfigByName(proj, ssoName)
+	if err != nil {
+		return "", err
+	}
+	if !endSessionEnabled(sso) {
+		return "", fmt.Errorf("end session is not enabled for project %s", projectID)
+	}
+	if !shared {
+		if sso, err = h.decryptSSO(projectID, sso); err != nil {
+			return "", err
+		}
+	}
+	return oidc.EndSessionURL(h.oauthContext(ctx), sso.Oidc, idToken, h.endSessionRedirectURI())
+}
 
-	http.Redirect(w, r, rootPath, http.StatusFound)
+func endSessionEnabled(sso *model.ProjectSSOConfig) bool {
+	return sso.Provider == model.ProjectSSOConfig_OIDC && sso.Oidc != nil && sso.Oidc.PostLogoutRedirectUri != ""
 }
 
 func (h *authHandler) findSSOConfig(p *model.Project) (sso *model.ProjectSSOConfig, shared bool, err error) {
@@ -125,75 +331,122 @@ func (h *authHandler) findSSOConfig(p *model.Project) (sso *model.ProjectSSOConf
 	return nil, false, fmt.Errorf("not found shared sso configuration %s", p.SharedSsoName)
 }
 
-// handleError redirects to the root path and saves the error message to the cookie.
-// Web will use that cookie data to handle auth error.
-func (h *authHandler) handleError(w http.ResponseWriter, r *http.Request, responseMessage string, err error) {
-	if err != nil {
-		h.logger.Error(fmt.Sprintf("auth-handler: %s", responseMessage), zap.Error(err))
-	} else {
-		h.logger.Info(fmt.Sprintf("auth-handler: %s", responseMessage))
+// checkAllowedHosts checks the hosts of the OIDC provider before the auth URL is made by the discovery.
+func checkAllowedHosts(sso *model.ProjectSSOConfig) error {
+	if sso.Provider != model.ProjectSSOConfig_OIDC || sso.Oidc == nil {
+		return nil
 	}
-
-	http.SetCookie(w, makeErrorCookie(responseMessage, h.secureCookie))
-	http.Redirect(w, r, rootPath, http.StatusSeeOther)
+	return oidc.CheckAllowedHosts(sso.Oidc)
 }
 
-func makeTokenCookie(value string, secure bool) *http.Cookie {
-	return &http.Cookie{
-		Name:     jwt.SignedTokenKey,
-		Value:    value,
-		MaxAge:   defaultTokenCookieMaxAge,
-		Path:     rootPath,
-		Secure:   secure,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
+// authCodeURL returns the URL of the provider to which the user is redirected to log in.
+// The OIDC provider is discovered by the configured HTTP client.
+func (h *authHandler) authCodeURL(ctx context.Context, sso *model.ProjectSSOConfig, project, state string, opts ...oauth2.AuthCodeOption) (string, error) {
+	if sso.Provider == model.ProjectSSOConfig_OIDC && sso.Oidc != nil {
+		return oidc.AuthCodeURL(h.oauthContext(ctx), sso.Oidc, project, state, opts...)
 	}
+	return sso.GenerateAuthCodeURL(project, h.callbackURL, state, opts...)
 }
 
-func makeExpiredTokenCookie(secure bool) *http.Cookie {
-	return &http.Cookie{
-		Name:     jwt.SignedTokenKey,
-		Value:    "",
-		MaxAge:   -1,
-		Path:     rootPath,
-		Secure:   secure,
-		HttpOnly: true,
-		SameSite: http.SameSiteStrictMode,
+// ssoChoice is one of the SSO configurations the users of a project can log in with.
+type ssoChoice struct {
+	// name is the name of the additional shared SSO configuration.
+	// It is empty for the main SSO configuration of the project.
+	name   string
+	sso    *model.ProjectSSOConfig
+	shared bool
+}
+
+// findSSOChoices returns the main SSO configuration of the project followed by the additional ones.
+func (h *authHandler) findSSOChoices(p *model.Project) ([]ssoChoice, error) {
+	sso, shared, err := h.findSSOConfig(p)
+	if err != nil {
+		return nil, err
 	}
+	choices := []ssoChoice{{sso: sso, shared: shared}}
+	for _, name := range p.AdditionalSharedSsoNames {
+		sso, ok := h.sharedSSOConfigs[name]
+		if !ok {
+			return nil, fmt.Errorf("not found shared sso configuration %s", name)
+		}
+		choices = append(choices, ssoChoice{name: name, sso: sso, shared: true})
+	}
+	return choices, nil
 }
 
-func makeStateCookie(value string, secure bool) *http.Cookie {
-	return &http.Cookie{
-		Name:     stateCookieKey,
-		Value:    value,
-		MaxAge:   defaultStateCookieMaxAge,
-		Path:     rootPath,
-		Secure:   secure,
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
+// findSSOConfigByName returns the SSO configuration selected at login.
+// The main SSO configuration of the project is returned if the name is empty,
+// otherwise the name must be one of the additional shared SSO configurations of the project.
+func (h *authHandler) findSSOConfigByName(p *model.Project, name string) (sso *model.ProjectSSOConfig, shared bool, err error) {
+	if name == "" {
+		return h.findSSOConfig(p)
+	}
+	if !slices.Contains(p.AdditionalSharedSsoNames, name) {
+		return nil, false, fmt.Errorf("shared sso configuration %s is not enabled for the project", name)
 	}
+	sso, ok := h.sharedSSOConfigs[name]
+	if !ok {
+		return nil, false, fmt.Errorf("not found shared sso configuration %s", name)
+	}
+	return sso, true, nil
+}
+
+// handleError redirects to the root path and saves the error message to the cookie.
+// Web will use that cookie data to handle auth error.
+// API clients requesting JSON are responded with the error code and message instead.
+func (h *authHandler) handleError(w http.ResponseWriter, r *http.Request, code errorCode, responseMessage string, err error) {
+	h.handleProjectError(w, r, "", code, responseMessage, err)
+}
+
+// handleProjectError is handleError for the login to the given project,
+// whose login error page links to the login of the project to retry it. Empty means the project is unknown.
+func (h *authHandler) handleProjectError(w http.ResponseWriter, r *http.Request, projectID string, code errorCode, responseMessage string, err error) {
+	h.handleProviderError(w, r, projectID, "", code, responseMessage, err)
 }
 
-func makeExpiredStateCookie(secure bool) *http.Cookie {
-	return &http.Cookie{
-		Name:     stateCookieKey,
-		Value:    "",
-		MaxAge:   -1,
-		Path:     rootPath,
-		Secure:   secure,
-		HttpOnly: true,
-		SameSite: http.SameSiteLaxMode,
+// handleProviderError is handleProjectError for the login via the given provider. Empty means the provider is unknown.
+func (h *authHandler) handleProviderError(w http.ResponseWriter, r *http.Request, projectID, provider string, code errorCode, responseMessage string, err error) {
+	setNoCacheHeaders(w)
+	h.setContentSecurityPolicy(w)
+	correlationID := uuid.New().String()
+	h.logError(r, projectID, provider, code, responseMessage, correlationID, err)
+
+	if wantsJSON(r) {
+		if err := writeJSONError(w, code, responseMessage, correlationID); err != nil {
+			h.logger.Error("auth-handler: failed to write error response", zap.Error(err))
+		}
+		return
 	}
+	if code == errCodeRequestTooLarge {
+		// Retrying the login does not help since the client sent the oversized request by itself.
+		http.Error(w, responseMessage, code.statusCode())
+		return
+	}
+	http.SetCookie(w, h.cookies.loginError(responseMessage))
+	if err := writeLoginErrorRedirect(w, requestLocale(r), code, projectID, responseMessage, correlationID); err != nil {
+		h.logger.Error("auth-handler: failed to write error response", zap.Error(err))
+	}
+}
+
+// setNoCacheHeaders prevents the browsers and the intermediaries from caching the response,
+// which may contain the tokens or the login error.
+func setNoCacheHeaders(w http.ResponseWriter) {
+	w.Header().Set("Cache-Control", "no-store")
+	w.Header().Set("Pragma", "no-cache")
 }
 
-func makeErrorCookie(value string, secure bool) *http.Cookie {
-	return &http.Cookie{
-		Name:     errorCookieKey,
-		Value:    value,
-		MaxAge:   defaultErrorCookieMaxAge,
-		Path:     rootPath,
-		Secure:   secure,
-		HttpOnly: false,
-		SameSite: http.SameSiteStrictMode,
+func parseIDTokenCookie(value string) (projectID, ssoName, idToken string, err error) {
+	projectID, rest, ok := strings.Cut(value, ":")
+	if !ok || projectID == "" || rest == "" {
+		return "", "", "", fmt.Errorf("malformed id token cookie")
+	}
+	idToken, name, ok := strings.Cut(rest, ":")
+	if !ok {
+		return projectID, "", idToken, nil
+	}
+	decoded, err := hex.DecodeString(name)
+	if idToken == "" || err != nil {
+		return "", "", "", fmt.Errorf("malformed id token cookie")
 	}
+	return projectID, string(decoded), idToken, nil
 }
@@ -13,3 +13,143 @@
 // limitations under the License.
 
 package httpapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/datastore"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+type fakeProjectGetter map[string]*model.Project
+
+func (g fakeProjectGetter) Get(_ context.Context, id string) (*model.Project, error) {
+	p, ok := g[id]
+	if !ok {
+		return nil, fmt.Errorf("project %s: %w", id, datastore.ErrNotFound)
+	}
+	return p, nil
+}
+
+func TestHandleLogout(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+			"oidc": {
+				Provider: model.ProjectSSOConfig_OIDC,
+				Oidc: &model.ProjectSSOConfig_Oidc{
+					ClientId:              "client-id",
+					EndSessionEndpoint:    "https://idp.example.com/logout",
+					PostLogoutRedirectUri: "https://pipecd.example.com/",
+				},
+			},
+			"github": {
+				Provider: model.ProjectSSOConfig_GITHUB,
+				Github:   &model.ProjectSSOConfig_GitHub{},
+			},
+		},
+		projectGetter: fakeProjectGetter{
+			"oidc-project":   {Id: "oidc-project", SharedSsoName: "oidc"},
+			"github-project": {Id: "github-project", SharedSsoName: "github"},
+			"multi-project": {
+				Id:                       "multi-project",
+				SharedSsoName:            "github",
+				AdditionalSharedSsoNames: []string{"oidc"},
+			},
+		},
+		cookies: cookieFactory{secure: true},
+		logger:  zap.NewNop(),
+	}
+
+	tests := []struct {
+		name             string
+		cookies          []*http.Cookie
+		expectedLocation string
+	}{
+		{
+			name:             "no cookie",
+			expectedLocation: rootPath,
+		},
+		{
+			name: "malformed token",
+			cookies: []*http.Cookie{
+				{Name: "token", Value: "malformed"},
+			},
+			expectedLocation: rootPath,
+		},
+		{
+			name: "malformed id token cookie",
+			cookies: []*http.Cookie{
+				{Name: idTokenCookieKey, Value: "malformed"},
+			},
+			expectedLocation: rootPath,
+		},
+		{
+			name: "end session is not enabled",
+			cookies: []*http.Cookie{
+				{Name: idTokenCookieKey, Value: "github-project:id-token"},
+			},
+			expectedLocation: rootPath,
+		},
+		{
+			name: "end session",
+			cookies: []*http.Cookie{
+				{Name: idTokenCookieKey, Value: "oidc-project:id-token"},
+			},
+			expectedLocation: "https://idp.example.com/logout?client_id=client-id&id_token_hint=id-token&post_logout_redirect_uri=https%3A%2F%2Fpipecd.example.com%2F",
+		},
+		{
+			name: "end session of selected sso",
+			cookies: []*http.Cookie{
+				cookieFactory{secure: true}.idToken("multi-project", "oidc", "id-token"),
+			},
+			expectedLocation: "https://idp.example.com/logout?client_id=client-id&id_token_hint=id-token&post_logout_redirect_uri=https%3A%2F%2Fpipecd.example.com%2F",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, logoutPath, nil)
+			for _, c := range tt.cookies {
+				req.AddCookie(c)
+			}
+			rec := httptest.NewRecorder()
+			h.handleLogout(rec, req)
+
+			assert.Equal(t, http.StatusFound, rec.Code)
+			assert.Equal(t, tt.expectedLocation, rec.Header().Get("Location"))
+			for _, c := range rec.Result().Cookies() {
+				assert.Equal(t, -1, c.MaxAge, "cookie %s must be expired", c.Name)
+				assert.True(t, c.Secure)
+			}
+		})
+	}
+}
+
+func TestHandleLogoutCookieDomain(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		cookies: cookieFactory{domain: "example.com", secure: true},
+		logger:  zap.NewNop(),
+	}
+	rec := httptest.NewRecorder()
+	h.handleLogout(rec, httptest.NewRequest(http.MethodGet, logoutPath, nil))
+
+	// Both the domain cookie and the host-only cookie issued before are cleared.
+	var domains []string
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == jwt.SignedTokenKey {
+			domains = append(domains, c.Domain)
+		}
+	}
+	assert.ElementsMatch(t, []string{"example.com", ""}, domains)
+}
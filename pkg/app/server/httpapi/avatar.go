@@ -0,0 +1,87 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	gravatarBaseURL = "https://gravatar.com/avatar/"
+	// maxAvatarURLSize keeps the token cookie carrying the avatar URL small.
+	maxAvatarURLSize = 1024
+)
+
+// gravatarDefaultImages are the built-in images of Gravatar shown when the email has no avatar.
+var gravatarDefaultImages = []string{"mp", "identicon", "monsterid", "wavatar", "retro", "robohash", "blank"}
+
+// WithGravatarFallback uses the Gravatar of the verified email of the user
+// when the identity provider gives no avatar, with the given default image of Gravatar
+// shown for the emails without avatar. The image should be checked by ValidateGravatarDefaultImage in advance.
+func WithGravatarFallback(defaultImage string) Option {
+	return func(h *authHandler) {
+		h.gravatarDefaultImage = defaultImage
+	}
+}
+
+// ValidateGravatarDefaultImage checks whether the given image is one of the built-in default images of Gravatar.
+// An empty image is valid and means the Gravatar fallback is disabled.
+func ValidateGravatarDefaultImage(image string) error {
+	if image == "" || slices.Contains(gravatarDefaultImages, image) {
+		return nil
+	}
+	return fmt.Errorf("invalid Gravatar default image %q, must be one of %s", image, strings.Join(gravatarDefaultImages, ", "))
+}
+
+// resolveAvatarURL returns the avatar URL of the given user to store in the claims.
+// The avatar given by the identity provider is read by each OAuth client:
+// avatar_url of the GitHub and GitLab users, the avatar link of the Bitbucket users,
+// the picture claim (or the configured claim) of the OIDC, Google and Okta users,
+// and the configured attribute of the SAML assertion.
+// Only the HTTPS URLs are used not to load the mixed content into the web console.
+func (h *authHandler) resolveAvatarURL(user *model.User) string {
+	if avatarURL, ok := validateAvatarURL(user.AvatarUrl); ok {
+		return avatarURL
+	}
+	if h.gravatarDefaultImage != "" && user.Email != "" && user.EmailVerified {
+		return gravatarURL(user.Email, h.gravatarDefaultImage)
+	}
+	return ""
+}
+
+// validateAvatarURL checks whether the given avatar URL is an absolute HTTPS URL.
+func validateAvatarURL(avatarURL string) (string, bool) {
+	if avatarURL == "" || len(avatarURL) > maxAvatarURLSize {
+		return "", false
+	}
+	u, err := url.Parse(avatarURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" || u.User != nil {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// gravatarURL returns the URL of the Gravatar of the given email.
+func gravatarURL(email, defaultImage string) string {
+	hash := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return gravatarBaseURL + hex.EncodeToString(hash[:]) + "?" + url.Values{"d": {defaultImage}}.Encode()
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestResolveAvatarURL(t *testing.T) {
+	t.Parallel()
+	// The SHA-256 of "user@example.com".
+	const gravatar = "https://gravatar.com/avatar/b4c9a289323b21a01c3e940f150eb9b8c542587f1abfd8f0e1cc1ffc5e475514?d=identicon"
+	testcases := []struct {
+		name         string
+		user         *model.User
+		defaultImage string
+		expected     string
+	}{
+		{
+			name:     "https avatar",
+			user:     &model.User{AvatarUrl: "https://avatars.githubusercontent.com/u/1?v=4"},
+			expected: "https://avatars.githubusercontent.com/u/1?v=4",
+		},
+		{
+			name:     "http avatar",
+			user:     &model.User{AvatarUrl: "http://avatars.example.com/u/1"},
+			expected: "",
+		},
+		{
+			name:     "script avatar",
+			user:     &model.User{AvatarUrl: "javascript:alert(1)"},
+			expected: "",
+		},
+		{
+			name:     "relative avatar",
+			user:     &model.User{AvatarUrl: "/uploads/avatar.png"},
+			expected: "",
+		},
+		{
+			name:     "too long avatar",
+			user:     &model.User{AvatarUrl: "https://example.com/" + strings.Repeat("a", maxAvatarURLSize)},
+			expected: "",
+		},
+		{
+			name:     "no fallback",
+			user:     &model.User{Email: "user@example.com", EmailVerified: true},
+			expected: "",
+		},
+		{
+			name:         "gravatar fallback",
+			user:         &model.User{Email: " User@Example.com", EmailVerified: true},
+			defaultImage: "identicon",
+			expected:     gravatar,
+		},
+		{
+			name:         "gravatar fallback of insecure avatar",
+			user:         &model.User{AvatarUrl: "http://avatars.example.com/u/1", Email: "user@example.com", EmailVerified: true},
+			defaultImage: "identicon",
+			expected:     gravatar,
+		},
+		{
+			name:         "unverified email",
+			user:         &model.User{Email: "user@example.com"},
+			defaultImage: "identicon",
+			expected:     "",
+		},
+		{
+			name:         "provider avatar takes precedence",
+			user:         &model.User{AvatarUrl: "https://example.com/a.png", Email: "user@example.com", EmailVerified: true},
+			defaultImage: "identicon",
+			expected:     "https://example.com/a.png",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := &authHandler{}
+			WithGravatarFallback(tc.defaultImage)(h)
+			assert.Equal(t, tc.expected, h.resolveAvatarURL(tc.user))
+		})
+	}
+}
+
+func TestValidateGravatarDefaultImage(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, ValidateGravatarDefaultImage(""))
+	assert.NoError(t, ValidateGravatarDefaultImage("mp"))
+	assert.Error(t, ValidateGravatarDefaultImage("404"))
+	assert.Error(t, ValidateGravatarDefaultImage("https://example.com/default.png"))
+}
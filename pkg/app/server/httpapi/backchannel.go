@@ -0,0 +1,119 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oidc"
+)
+
+// handleBackchannelLogout revokes the sessions of the user who logged out at the OIDC provider,
+// as requested by the logout token the provider posts (OpenID Connect Back-Channel Logout 1.0).
+// The project and the shared SSO configuration are given by the query parameters of the URL
+// registered to the provider. It responds 200 once the sessions are revoked, or 400 if the token is invalid.
+func (h *authHandler) handleBackchannelLogout(w http.ResponseWriter, r *http.Request) {
+	setNoCacheHeaders(w)
+	if r.Method != http.MethodPost {
+		h.handleBackchannelLogoutError(w, r, "", errCodeMethodNotAllowed, "Method not allowed", nil)
+		return
+	}
+	if h.sessionStore == nil {
+		http.Error(w, "Session store is not enabled", http.StatusNotFound)
+		return
+	}
+	if err := h.parseCallbackForm(w, r); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.handleBackchannelLogoutError(w, r, "", errCodeRequestTooLarge, "Request too large", err)
+			return
+		}
+		h.handleBackchannelLogoutError(w, r, "", errCodeInvalidRequest, "Failed to parse form", err)
+		return
+	}
+	projectID := r.URL.Query().Get(projectFormKey)
+	if projectID == "" {
+		h.handleBackchannelLogoutError(w, r, "", errCodeInvalidRequest, "Missing project id", nil)
+		return
+	}
+	rawToken := r.PostFormValue(logoutTokenFormKey)
+	if rawToken == "" {
+		h.handleBackchannelLogoutError(w, r, projectID, errCodeInvalidRequest, "Missing logout token", nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	proj, err := h.getProject(ctx, projectID)
+	if err != nil {
+		_, code, message := projectLookupErrorCode(projectID, err)
+		h.handleBackchannelLogoutError(w, r, projectID, code, message, err)
+		return
+	}
+	sso, shared, err := h.findSSOConfigByName(proj, r.URL.Query().Get(ssoFormKey))
+	if err != nil {
+		h.handleBackchannelLogoutError(w, r, proj.Id, errCodeInvalidSSOConfig, fmt.Sprintf("Invalid SSO configuration: %v", err), nil)
+		return
+	}
+	if sso.Provider != model.ProjectSSOConfig_OIDC || sso.Oidc == nil {
+		h.handleBackchannelLogoutError(w, r, proj.Id, errCodeInvalidRequest, "Back-channel logout is only supported by OIDC", nil)
+		return
+	}
+	if !shared {
+		if sso, err = h.decryptSSO(proj.Id, sso); err != nil {
+			h.handleBackchannelLogoutError(w, r, proj.Id, errCodeInternal, "Failed to decrypt SSO configuration", err)
+			return
+		}
+	}
+
+	token, err := oidc.VerifyLogoutToken(h.oauthContext(ctx), sso.Oidc, rawToken)
+	if errors.Is(err, oidc.ErrInvalidLogoutToken) {
+		h.handleBackchannelLogoutError(w, r, proj.Id, errCodeInvalidRequest, "Invalid logout token", err)
+		return
+	}
+	if err != nil {
+		h.handleBackchannelLogoutError(w, r, proj.Id, errCodeUnavailable, "Unable to verify logout token", err)
+		return
+	}
+	if err := h.sessionStore.RevokeIdPSession(proj.Id, token.Subject, token.SessionID); err != nil {
+		h.handleBackchannelLogoutError(w, r, proj.Id, errCodeInternal, "Internal error", err)
+		return
+	}
+
+	h.logger.Info("revoked sessions by back-channel logout",
+		zap.String("project-id", proj.Id),
+		zap.String("idp-subject", token.Subject),
+		zap.String("idp-session-id", token.SessionID),
+	)
+	w.WriteHeader(http.StatusOK)
+}
+
+// handleBackchannelLogoutError responds the error in JSON since the request comes from the provider, not the browser.
+func (h *authHandler) handleBackchannelLogoutError(w http.ResponseWriter, r *http.Request, projectID string, code errorCode, responseMessage string, err error) {
+	correlationID := uuid.New().String()
+	h.logError(r, projectID, model.ProjectSSOConfig_OIDC.String(), code, responseMessage, correlationID, err)
+	if err := writeJSONError(w, code, responseMessage, correlationID); err != nil {
+		h.logger.Error("auth-handler: failed to write error response", zap.Error(err))
+	}
+}
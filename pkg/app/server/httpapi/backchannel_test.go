@@ -0,0 +1,184 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestHandleBackchannelLogout(t *testing.T) {
+	t.Parallel()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	var provider *httptest.Server
+	provider = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/jwks" {
+			json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+				{Key: &key.PublicKey, KeyID: "key", Algorithm: string(jose.RS256), Use: "sig"},
+			}})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":   provider.URL,
+			"jwks_uri": provider.URL + "/jwks",
+		})
+	}))
+	defer provider.Close()
+
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, (&jose.SignerOptions{}).WithHeader(jose.HeaderKey("kid"), "key"))
+	require.NoError(t, err)
+	logoutToken := func(sid string) string {
+		payload, err := json.Marshal(map[string]interface{}{
+			"iss":    provider.URL,
+			"aud":    "client-id",
+			"iat":    time.Now().Unix(),
+			"exp":    time.Now().Add(time.Minute).Unix(),
+			"jti":    "jti",
+			"sid":    sid,
+			"events": map[string]interface{}{"http://schemas.openid.net/event/backchannel-logout": map[string]interface{}{}},
+		})
+		require.NoError(t, err)
+		jws, err := signer.Sign(payload)
+		require.NoError(t, err)
+		token, err := jws.CompactSerialize()
+		require.NoError(t, err)
+		return token
+	}
+
+	store := jwt.NewCacheSessionStore(memorycache.NewCache())
+	h := &authHandler{
+		projectGetter: fakeProjectGetter{
+			"project": {Id: "project", AdditionalSharedSsoNames: []string{"idp"}},
+		},
+		sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+			"idp": {
+				Provider: model.ProjectSSOConfig_OIDC,
+				Oidc: &model.ProjectSSOConfig_Oidc{
+					ClientId: "client-id",
+					Issuer:   provider.URL,
+				},
+			},
+		},
+		sessionStore: store,
+		logger:       zap.NewNop(),
+	}
+
+	// The session logged in before the logout.
+	claims := jwt.NewClaims("user", "", time.Hour, model.Role{ProjectId: "project"})
+	claims.IssuedAt = jwtgo.NewNumericDate(time.Now().Add(-time.Minute))
+	claims.IdPSessionID = "session"
+	require.NoError(t, store.Register(claims))
+	another := jwt.NewClaims("user", "", time.Hour, model.Role{ProjectId: "project"})
+	another.IssuedAt = claims.IssuedAt
+	another.IdPSessionID = "another-session"
+	require.NoError(t, store.Register(another))
+
+	post := func(method, query, token string) *httptest.ResponseRecorder {
+		form := url.Values{}
+		if token != "" {
+			form.Set(logoutTokenFormKey, token)
+		}
+		req := httptest.NewRequest(method, backchannelLogoutPath+"?"+query, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.handleBackchannelLogout(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusMethodNotAllowed, post(http.MethodGet, "project=project&sso=idp", logoutToken("session")).Code)
+	assert.Equal(t, http.StatusBadRequest, post(http.MethodPost, "sso=idp", logoutToken("session")).Code)
+	assert.Equal(t, http.StatusBadRequest, post(http.MethodPost, "project=project&sso=idp", "").Code)
+	assert.Equal(t, http.StatusNotFound, post(http.MethodPost, "project=unknown&sso=idp", logoutToken("session")).Code)
+
+	rec := post(http.MethodPost, "project=project&sso=idp", "invalid")
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), errCodeInvalidRequest)
+	revoked, err := store.IsRevoked(claims)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	rec = post(http.MethodPost, "project=project&sso=idp", logoutToken("session"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	revoked, err = store.IsRevoked(claims)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	// The other sessions of the user are kept.
+	revoked, err = store.IsRevoked(another)
+	require.NoError(t, err)
+	assert.False(t, revoked)
+
+	// Disabled without the session store.
+	h.sessionStore = nil
+	assert.Equal(t, http.StatusNotFound, post(http.MethodPost, "project=project&sso=idp", logoutToken("session")).Code)
+}
+
+func TestHandleRefreshBackchannelLogout(t *testing.T) {
+	t.Parallel()
+	store := jwt.NewCacheSessionStore(memorycache.NewCache())
+	h := &authHandler{
+		signer: fakeSigner{},
+		projectGetter: fakeProjectGetter{
+			"project": {Id: "project"},
+		},
+		refreshTokens:   memorycache.NewCache(),
+		refreshTokenTTL: time.Hour,
+		sessionStore:    store,
+		logger:          zap.NewNop(),
+	}
+	issue := func(sid string) string {
+		value, err := h.issueRefreshToken(&refreshToken{
+			Subject:      "user",
+			ProjectID:    "project",
+			Roles:        []string{model.BuiltinRBACRoleViewer.String()},
+			TokenTTL:     time.Hour,
+			IdPSessionID: sid,
+			LoginAt:      time.Now().Add(-time.Minute),
+		})
+		require.NoError(t, err)
+		return value
+	}
+	refresh := func(value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, refreshPath, nil)
+		req.AddCookie(&http.Cookie{Name: refreshTokenCookieKey, Value: value})
+		rec := httptest.NewRecorder()
+		h.handleRefresh(rec, req)
+		return rec
+	}
+
+	kept, loggedOut := issue("another-session"), issue("session")
+	require.NoError(t, store.RevokeIdPSession("project", "", "session"))
+
+	assert.Equal(t, http.StatusUnauthorized, refresh(loggedOut).Code)
+	assert.Equal(t, http.StatusNoContent, refresh(kept).Code)
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+// SessionBinding is what the sessions are bound to at login, so that the stolen tokens cannot be used from another client.
+type SessionBinding string
+
+const (
+	// SessionBindingNone does not bind the sessions.
+	SessionBindingNone SessionBinding = "none"
+	// SessionBindingIP binds the sessions to the client IP, which breaks the sessions of the users changing their networks, e.g. on mobile.
+	SessionBindingIP SessionBinding = "ip"
+	// SessionBindingUserAgent binds the sessions to the User-Agent header.
+	SessionBindingUserAgent SessionBinding = "ua"
+	// SessionBindingBoth binds the sessions to both the client IP and the User-Agent header.
+	SessionBindingBoth SessionBinding = "both"
+)
+
+// ErrSessionBindingChanged is returned when the session is used from another client than the one it is bound to.
+var ErrSessionBindingChanged = errors.New("session binding changed")
+
+// ParseSessionBinding parses the given session binding. Empty means SessionBindingNone.
+func ParseSessionBinding(s string) (SessionBinding, error) {
+	switch b := SessionBinding(strings.ToLower(s)); b {
+	case "":
+		return SessionBindingNone, nil
+	case SessionBindingNone, SessionBindingIP, SessionBindingUserAgent, SessionBindingBoth:
+		return b, nil
+	default:
+		return "", fmt.Errorf("invalid session binding %q, must be one of none, ip, ua or both", s)
+	}
+}
+
+// enabled reports whether the sessions are bound to the clients.
+func (b SessionBinding) enabled() bool {
+	return b != "" && b != SessionBindingNone
+}
+
+// fingerprint returns the fingerprint of the client with the given IP and User-Agent,
+// which is prefixed by the binding so that it can be checked regardless of the binding configured later.
+// The IP and the User-Agent are hashed not to be exposed in the token. Empty is returned for SessionBindingNone.
+func (b SessionBinding) fingerprint(ip, userAgent string) string {
+	var v string
+	switch b {
+	case SessionBindingIP:
+		v = ip
+	case SessionBindingUserAgent:
+		v = userAgent
+	case SessionBindingBoth:
+		v = ip + "\x00" + userAgent
+	default:
+		return ""
+	}
+	sum := sha256.Sum256([]byte(v))
+	return string(b) + ":" + base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// WithSessionBinding binds the sessions issued at login to the client by the given binding.
+func WithSessionBinding(b SessionBinding) Option {
+	return func(h *authHandler) {
+		h.sessionBinding = b
+	}
+}
+
+// bindSession records the fingerprint of the client of the given request in the given claims.
+func (h *authHandler) bindSession(r *http.Request, claims *jwt.Claims) {
+	claims.Binding = h.sessionBinding.fingerprint(h.clientIP(r), r.UserAgent())
+}
+
+// SessionBinder checks whether the sessions are used from the clients they are bound to.
+type SessionBinder struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewSessionBinder returns a SessionBinder finding the client IP by the given trusted proxies
+// in the same way as at login.
+func NewSessionBinder(trustedProxies []*net.IPNet) *SessionBinder {
+	return &SessionBinder{trustedProxies: trustedProxies}
+}
+
+// Check returns ErrSessionBindingChanged unless the request from the given address with the given header
+// is sent by the client the session of the given claims is bound to. The sessions not bound are always accepted.
+func (b *SessionBinder) Check(claims *jwt.Claims, remoteAddr string, header http.Header) error {
+	return checkSessionBinding(claims.Binding, clientIPFromHeader(remoteAddr, header, b.trustedProxies), header.Get("User-Agent"))
+}
+
+// checkSessionBinding returns ErrSessionBindingChanged unless the client with the given IP and User-Agent
+// has the given fingerprint recorded at login. Empty fingerprint means the session is not bound.
+func checkSessionBinding(fingerprint, ip, userAgent string) error {
+	if fingerprint == "" {
+		return nil
+	}
+	binding, _, _ := strings.Cut(fingerprint, ":")
+	expected := SessionBinding(binding).fingerprint(ip, userAgent)
+	if subtle.ConstantTimeCompare([]byte(expected), []byte(fingerprint)) != 1 {
+		return ErrSessionBindingChanged
+	}
+	return nil
+}
@@ -0,0 +1,201 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestParseSessionBinding(t *testing.T) {
+	t.Parallel()
+	for s, expected := range map[string]SessionBinding{
+		"":     SessionBindingNone,
+		"none": SessionBindingNone,
+		"IP":   SessionBindingIP,
+		"ua":   SessionBindingUserAgent,
+		"both": SessionBindingBoth,
+	} {
+		b, err := ParseSessionBinding(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, b, s)
+	}
+	_, err := ParseSessionBinding("cookie")
+	assert.Error(t, err)
+}
+
+func TestCheckSessionBinding(t *testing.T) {
+	t.Parallel()
+	const (
+		ip = "192.0.2.1"
+		ua = "Mozilla/5.0"
+	)
+	assert.Empty(t, SessionBindingNone.fingerprint(ip, ua))
+	assert.NotContains(t, SessionBindingBoth.fingerprint(ip, ua), ip)
+
+	testcases := []struct {
+		name      string
+		binding   SessionBinding
+		ip        string
+		userAgent string
+		expectErr bool
+	}{
+		{name: "not bound", binding: SessionBindingNone, ip: "192.0.2.2", userAgent: "curl"},
+		{name: "same ip", binding: SessionBindingIP, ip: ip, userAgent: "curl"},
+		{name: "changed ip", binding: SessionBindingIP, ip: "192.0.2.2", userAgent: ua, expectErr: true},
+		{name: "same user agent", binding: SessionBindingUserAgent, ip: "192.0.2.2", userAgent: ua},
+		{name: "changed user agent", binding: SessionBindingUserAgent, ip: ip, userAgent: "curl", expectErr: true},
+		{name: "same client", binding: SessionBindingBoth, ip: ip, userAgent: ua},
+		{name: "changed ip of both", binding: SessionBindingBoth, ip: "192.0.2.2", userAgent: ua, expectErr: true},
+		{name: "changed user agent of both", binding: SessionBindingBoth, ip: ip, userAgent: "curl", expectErr: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkSessionBinding(tc.binding.fingerprint(ip, ua), tc.ip, tc.userAgent)
+			if tc.expectErr {
+				assert.ErrorIs(t, err, ErrSessionBindingChanged)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	// The fingerprint of an unknown binding never matches.
+	assert.ErrorIs(t, checkSessionBinding("unknown:abc", ip, ua), ErrSessionBindingChanged)
+}
+
+func TestSessionBinder(t *testing.T) {
+	t.Parallel()
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+	binder := NewSessionBinder(proxies)
+	claims := &jwt.Claims{Binding: SessionBindingBoth.fingerprint("192.0.2.1", "Mozilla/5.0")}
+
+	header := http.Header{}
+	header.Set("X-Forwarded-For", "192.0.2.1")
+	header.Set("User-Agent", "Mozilla/5.0")
+	assert.NoError(t, binder.Check(claims, "10.0.0.1:1234", header))
+	// The forwarded address is ignored unless the request comes from a trusted proxy.
+	assert.ErrorIs(t, binder.Check(claims, "192.0.2.2:1234", header), ErrSessionBindingChanged)
+
+	header.Set("User-Agent", "curl")
+	assert.ErrorIs(t, binder.Check(claims, "10.0.0.1:1234", header), ErrSessionBindingChanged)
+
+	// The sessions not bound are accepted.
+	assert.NoError(t, binder.Check(&jwt.Claims{}, "192.0.2.2:1234", header))
+}
+
+func TestHandleMeSessionBinding(t *testing.T) {
+	t.Parallel()
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("secret"), 0600))
+	signer, err := jwt.NewSigner(jwtgo.SigningMethodHS256, keyFile)
+	require.NoError(t, err)
+	verifier, err := jwt.NewVerifier(jwtgo.SigningMethodHS256, keyFile)
+	require.NoError(t, err)
+
+	h := &authHandler{
+		sessionVerifier: verifier,
+		sessionBinding:  SessionBindingIP,
+		logger:          zap.NewNop(),
+	}
+	claims := jwt.NewClaims("user", "", time.Hour, model.Role{ProjectId: "project"})
+	login := httptest.NewRequest(http.MethodPost, callbackPath, nil)
+	login.RemoteAddr = "192.0.2.1:1234"
+	h.bindSession(login, claims)
+	token, err := signer.Sign(claims)
+	require.NoError(t, err)
+
+	call := func(remoteAddr string) int {
+		req := httptest.NewRequest(http.MethodGet, mePath, nil)
+		req.RemoteAddr = remoteAddr
+		req.AddCookie(&http.Cookie{Name: jwt.SignedTokenKey, Value: token})
+		rec := httptest.NewRecorder()
+		h.handleMe(rec, req)
+		return rec.Code
+	}
+	assert.Equal(t, http.StatusOK, call("192.0.2.1:5678"))
+	assert.Equal(t, http.StatusUnauthorized, call("192.0.2.2:5678"))
+}
+
+func TestHandleRefreshSessionBinding(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		signer: fakeSigner{},
+		projectGetter: fakeProjectGetter{
+			"project": {Id: "project"},
+		},
+		refreshTokens:   memorycache.NewCache(),
+		refreshTokenTTL: time.Hour,
+		sessionBinding:  SessionBindingUserAgent,
+		logger:          zap.NewNop(),
+	}
+	value, err := h.issueRefreshToken(&refreshToken{
+		Subject:   "user",
+		ProjectID: "project",
+		Roles:     []string{model.BuiltinRBACRoleViewer.String()},
+		TokenTTL:  time.Hour,
+		LoginAt:   time.Now(),
+		Binding:   SessionBindingUserAgent.fingerprint("", "Mozilla/5.0"),
+	})
+	require.NoError(t, err)
+
+	refresh := func(value, userAgent string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, refreshPath, nil)
+		req.Header.Set("User-Agent", userAgent)
+		req.AddCookie(&http.Cookie{Name: refreshTokenCookieKey, Value: value})
+		rec := httptest.NewRecorder()
+		h.handleRefresh(rec, req)
+		return rec
+	}
+
+	// The refresh token used from another client is revoked as well as rejected.
+	stolen, err := h.issueRefreshToken(&refreshToken{
+		Subject:   "user",
+		ProjectID: "project",
+		Roles:     []string{model.BuiltinRBACRoleViewer.String()},
+		TokenTTL:  time.Hour,
+		LoginAt:   time.Now(),
+		Binding:   SessionBindingUserAgent.fingerprint("", "Mozilla/5.0"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, refresh(stolen, "curl").Code)
+	assert.Equal(t, http.StatusUnauthorized, refresh(stolen, "Mozilla/5.0").Code)
+
+	rec := refresh(value, "Mozilla/5.0")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	var rotated string
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == refreshTokenCookieKey {
+			rotated = c.Value
+		}
+	}
+	rt, err := h.revokeRefreshToken(rotated)
+	require.NoError(t, err)
+	assert.Equal(t, SessionBindingUserAgent.fingerprint("", "Mozilla/5.0"), rt.Binding)
+}
@@ -16,175 +16,541 @@ package httpapi
 
 import (
 	"context"
+	"crypto/hmac"
 	"crypto/subtle"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
-	"golang.org/x/net/xsrftoken"
+	"golang.org/x/oauth2"
 
+	"github.com/pipe-cd/pipecd/pkg/app/server/httpapi/httpapimetrics"
 	"github.com/pipe-cd/pipecd/pkg/jwt"
 	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/azuread"
 	"github.com/pipe-cd/pipecd/pkg/oauth/github"
+	"github.com/pipe-cd/pipecd/pkg/oauth/google"
 	"github.com/pipe-cd/pipecd/pkg/oauth/oidc"
 )
 
 func (h *authHandler) handleCallback(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
+	h.setContentSecurityPolicy(w)
+	setNoCacheHeaders(w)
 
-	// Validate request's payload.
+	r, span := h.startRequestSpan(r, "auth.callback")
+	defer span.End()
 
-	// split the project ID from the state, if it exists.
-	// This is necessary because some providers don't support passing the project ID in the query parameters.
-	state, projectID, err := parseProjectAndState(r)
-	if err != nil {
-		h.handleError(w, r, "Failed to parse state", err)
+	if l := h.callbackIPLimiter; l != nil && !l.allow(h.clientIP(r)) {
+		h.handleRateLimited(w, r, l.retryAfter(), callbackPath, "ip")
+		return
+	}
+	if l := h.callbackLockout; l != nil {
+		if d := l.lockedFor(h.clientIP(r)); d > 0 {
+			h.handleRateLimited(w, r, retryAfterSeconds(d), callbackPath, "lockout")
+			return
+		}
+	}
+
+	event := h.newLoginEvent(r)
+	start := time.Now()
+	defer func() {
+		httpapimetrics.ObserveCallbackDuration(event.Provider, time.Since(start))
+	}()
+
+	if err := h.checkCallbackOrigin(r); err != nil {
+		h.handleLoginError(w, r, event, failureReasonForbidden, errCodeForbidden, "Unexpected origin", err)
 		return
 	}
 
-	if err := checkState(r, h.stateKey, state); err != nil {
-		h.handleError(w, r, "Unauthorized access", err)
+	// Validate request's payload.
+	if err := h.parseCallbackForm(w, r); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeRequestTooLarge, "Request too large", err)
+			return
+		}
+		h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeInvalidRequest, "Failed to parse form", err)
+		return
+	}
+
+	// The login started by the identity provider comes without the state but with the project ID.
+	// It is only accepted if the SSO configuration allows, see idpInitiatedLoginAllowed.
+	idpInitiated := r.FormValue(stateFormKey) == "" && r.FormValue(projectFormKey) != ""
+	var (
+		state, projectID string
+		sealed           *sealedState
+		err              error
+	)
+	if idpInitiated {
+		projectID = r.FormValue(projectFormKey)
+	} else if isSealedState(r.FormValue(stateFormKey)) {
+		// The sealed state carries the project ID along with the other data of the login flow.
+		sealed, err = h.openSealedState(r)
+		if err != nil {
+			h.handleLoginError(w, r, event, failureReasonState, errCodeInvalidRequest, "Failed to parse state", err)
+			return
+		}
+		projectID = sealed.ProjectID
+	} else {
+		// split the project ID from the state, if it exists.
+		// This is necessary because some providers don't support passing the project ID in the query parameters.
+		state, projectID, err = parseProjectAndState(r)
+		if err != nil {
+			h.handleLoginError(w, r, event, failureReasonState, errCodeInvalidRequest, "Failed to parse state", err)
+			return
+		}
+	}
+	event.ProjectID = projectID
+	event.IdPInitiated = idpInitiated
+	span.SetAttributes(projectIDAttributeKey.String(projectID))
+
+	if l := h.callbackProjectLimiter; l != nil && !l.allow(projectID) {
+		h.handleRateLimited(w, r, l.retryAfter(), callbackPath, "project")
+		return
+	}
+	if !h.allowProjectCallback(w, r, callbackPath, projectID) {
 		return
 	}
 
+	// The context of the request is not used for the steps which must not be cancelled by the client.
+	spanCtx := trace.ContextWithSpan(context.Background(), span)
+
+	var ssoName string
+	if !idpInitiated {
+		_, stateSpan := h.startSpan(spanCtx, "auth.callback.validate_state")
+		switch {
+		case h.stateCookieOptional(r):
+			ssoName, err = h.checkStateByOrigin(r, state, sealed)
+		case sealed != nil:
+			ssoName = sealed.SSOName
+			err = checkSealedState(r, h.cookies.stateName(), h.stateKey, sealed, h.stateTTL, h.now())
+		default:
+			ssoName, err = checkState(r, h.cookies.stateName(), h.stateKey, state, h.stateTTL, h.now())
+		}
+		endSpan(stateSpan, err)
+		if err != nil {
+			if errors.Is(err, errStateExpired) {
+				h.handleLoginError(w, r, event, failureReasonState, errCodeLoginExpired, "Login expired, please retry", err)
+				return
+			}
+			h.handleLoginError(w, r, event, failureReasonState, errCodeStateInvalid, "Unauthorized access", err)
+			return
+		}
+	}
+
 	authCode := r.FormValue(authCodeFormKey)
 	if authCode == "" {
-		h.handleError(w, r, "Missing auth code", nil)
+		h.handleLoginError(w, r, event, failureReasonMissingCode, errCodeInvalidRequest, "Missing auth code", nil)
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(spanCtx, 10*time.Second)
 	defer cancel()
 
-	proj, err := h.projectGetter.Get(ctx, projectID)
+	projectCtx, projectSpan := h.startSpan(ctx, "auth.callback.get_project")
+	proj, err := h.getProject(projectCtx, projectID)
+	endSpan(projectSpan, err)
 	if err != nil {
-		h.handleError(w, r, fmt.Sprintf("Unable to find project %s", projectID), err)
+		reason, code, message := projectLookupErrorCode(projectID, err)
+		h.handleLoginError(w, r, event, reason, code, message, err)
 		return
 	}
 
 	if proj.UserGroups == nil {
-		h.handleError(w, r, "Missing User Group configuration", nil)
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeInvalidSSOConfig, "Missing User Group configuration", nil)
 		return
 	}
 
-	sso, shared, err := h.findSSOConfig(proj)
+	sso, shared, err := h.findSSOConfigByName(proj, ssoName)
 	if err != nil {
-		h.handleError(w, r, fmt.Sprintf("Invalid SSO configuration: %v", err), nil)
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeInvalidSSOConfig, fmt.Sprintf("Invalid SSO configuration: %v", err), nil)
 		return
 	}
-	sessionTTLFromConfig := sso.SessionTtl
-	var tokenTTL time.Duration
-	if sessionTTLFromConfig == 0 {
-		tokenTTL = defaultTokenTTL
-	} else {
-		tokenTTL = time.Duration(sessionTTLFromConfig) * time.Hour
+	event.Provider = sso.Provider.String()
+	span.SetAttributes(providerAttributeKey.String(event.Provider))
+	if idpInitiated && !idpInitiatedLoginAllowed(sso) {
+		h.handleLoginError(w, r, event, failureReasonState, errCodeStateInvalid, "Unauthorized access", fmt.Errorf("missing state"))
+		return
 	}
 
 	if !shared {
-		if err := sso.Decrypt(h.decrypter); err != nil {
-			h.handleError(w, r, "Failed to decrypt SSO configuration", err)
+		_, decryptSpan := h.startSpan(ctx, "auth.callback.decrypt_sso")
+		decrypted, err := h.decryptSSO(proj.Id, sso)
+		endSpan(decryptSpan, err)
+		if err != nil {
+			h.handleLoginError(w, r, event, failureReasonDecrypt, errCodeInternal, "Failed to decrypt SSO configuration", err)
+			return
+		}
+		sso = decrypted
+	}
+	// The verifier and nonce are not sent in the IdP-initiated login.
+	var opts []oauth2.AuthCodeOption
+	if pkceEnabled(sso) && !idpInitiated {
+		verifier, err := codeVerifier(r, sealed)
+		if err != nil {
+			h.handleLoginError(w, r, event, failureReasonState, errCodeInvalidRequest, "Missing PKCE code verifier", err)
 			return
 		}
+		opts = append(opts, oauth2.VerifierOption(verifier))
+	}
+	var nonce string
+	if nonceEnabled(sso) && !idpInitiated {
+		if nonce, err = h.stateNonce(r, sealed); err != nil {
+			h.handleLoginError(w, r, event, failureReasonState, errCodeStateInvalid, "Nonce validation failed", err)
+			return
+		}
+	}
+	userCtx, userSpan := h.startSpan(ctx, "auth.callback.exchange_code")
+	user, token, err := h.resolveUser(userCtx, &UserRequest{
+		SSO:          sso,
+		Project:      proj,
+		CallbackURL:  h.callbackURL,
+		Code:         authCode,
+		Nonce:        nonce,
+		IdPInitiated: idpInitiated,
+		Options:      opts,
+		Logger:       h.logger,
+	})
+	endSpan(userSpan, err)
+	if errors.Is(err, model.ErrRedirectURINotAllowed) {
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeForbidden, "redirect_uri not allowed", err)
+		return
+	}
+	if errors.Is(err, oidc.ErrHostNotAllowed) {
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeInvalidSSOConfig, "Invalid SSO configuration: host not allowed", err)
+		return
+	}
+	if errors.Is(err, google.ErrDomainNotPermitted) {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeForbidden, "Domain not permitted", err)
+		return
+	}
+	if errors.Is(err, azuread.ErrTenantNotPermitted) {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeForbidden, "Tenant not permitted", err)
+		return
+	}
+	if errors.Is(err, github.ErrNotOrgMember) {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeForbidden, "Not a member of an allowed organization", err)
+		return
+	}
+	if errors.Is(err, github.ErrCodeExpired) || errors.Is(err, oidc.ErrCodeExpired) {
+		h.handleLoginError(w, r, event, failureReasonCodeExpired, errCodeLoginExpired, "Your login timed out, please try again", err)
+		return
+	}
+	if errors.Is(err, oidc.ErrNonceValidationFailed) {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeUnauthorized, "Nonce validation failed", err)
+		return
+	}
+	if errors.Is(err, oidc.ErrEmailNotVerified) {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeForbidden, "email not verified", err)
+		return
+	}
+	if errors.Is(err, oidc.ErrReauthenticationRequired) {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeLoginExpired, "reauthentication required", err)
+		return
+	}
+	if errors.Is(err, oidc.ErrInvalidIssuer) || errors.Is(err, oidc.ErrInvalidAudience) || errors.Is(err, oidc.ErrInvalidIdPInitiatedToken) {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeUnauthorized, "Invalid ID token", err)
+		return
 	}
-	user, err := getUser(ctx, sso, proj, authCode)
 	if err != nil {
-		h.handleError(w, r, "Unable to find user", err)
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeUnauthorized, "Unable to find user", err)
 		return
 	}
+	identities, err := h.linkIdentity(proj.Id, sso.Provider, user)
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeInternal, "Internal error", err)
+		return
+	}
+	event.Username = user.Username
+	if err := ensureRole(proj, user); err != nil {
+		h.handleLoginError(w, r, event, failureReasonForbidden, errCodeForbidden, "no role assigned for your account", err)
+		return
+	}
+	tokenTTL := h.loginTokenTTL(sessionTokenTTL(sso, proj, user.Role))
+	user.AvatarUrl = h.resolveAvatarURL(user)
 
-	claims := jwt.NewClaims(
+	claims := jwt.NewClaimsAt(
+		h.now(),
 		user.Username,
 		user.AvatarUrl,
 		tokenTTL,
 		*user.Role,
+		user.Groups...,
 	)
+	claims.Identities = identities
+	claims.Provider = event.Provider
+	h.bindSession(r, claims)
+	if h.sessionStore != nil && sso.Provider == model.ProjectSSOConfig_OIDC && token != nil {
+		// Record the session at the provider so that the back-channel logout can revoke the session.
+		claims.IdPSubject, claims.IdPSessionID, err = oidc.IDTokenSession(sso.Oidc, token)
+		if err != nil {
+			h.logger.Warn("auth-handler: unable to read the session of the id token", zap.Error(err))
+		}
+	}
+	_, signSpan := h.startSpan(ctx, "auth.callback.sign_token")
 	signedToken, err := h.signer.Sign(claims)
+	endSpan(signSpan, err)
 	if err != nil {
-		h.handleError(w, r, "Internal error", err)
+		h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
+		return
+	}
+	if err := h.registerSession(claims); err != nil {
+		h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
+		return
+	}
+	if err := h.keepGrant(ssoName, claims, token); err != nil {
+		h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
 		return
 	}
 
-	h.logger.Info("user logged in",
-		zap.String("user", user.Username),
-		zap.String("project-id", proj.Id),
-		zap.String("project-role", user.Role.String()),
-	)
+	if idpInitiated {
+		h.logger.Warn("accepted an IdP-initiated login without state",
+			zap.String("user", user.Username),
+			zap.String("project-id", proj.Id),
+			zap.String("project-role", user.Role.String()),
+			zap.String("source-ip", event.SourceIP),
+		)
+	} else {
+		h.logger.Info("user logged in",
+			zap.String("user", user.Username),
+			zap.String("project-id", proj.Id),
+			zap.String("project-role", user.Role.String()),
+		)
+	}
 
-	http.SetCookie(w, makeTokenCookie(signedToken, true))
-	http.SetCookie(w, makeExpiredStateCookie(h.secureCookie))
-	http.Redirect(w, r, rootPath, http.StatusFound)
+	if h.refreshTokens != nil {
+		value, err := h.issueRefreshToken(&refreshToken{
+			Subject:      user.Username,
+			AvatarURL:    user.AvatarUrl,
+			ProjectID:    proj.Id,
+			Roles:        user.Role.ProjectRbacRoles,
+			TokenTTL:     tokenTTL,
+			Groups:       user.Groups,
+			Identities:   identities,
+			Provider:     event.Provider,
+			IdPSubject:   claims.IdPSubject,
+			IdPSessionID: claims.IdPSessionID,
+			LoginAt:      claims.AuthTime.Time,
+			Binding:      claims.Binding,
+		})
+		if err != nil {
+			h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
+			return
+		}
+		http.SetCookie(w, h.cookies.refreshToken(value, h.refreshTokenTTL))
+	}
+	if endSessionEnabled(sso) {
+		if idToken, ok := token.Extra("id_token").(string); ok {
+			http.SetCookie(w, h.cookies.idToken(proj.Id, ssoName, idToken))
+		}
+	}
+	http.SetCookie(w, h.cookies.secureToken(signedToken))
+	http.SetCookie(w, h.cookies.expiredState())
+	http.SetCookie(w, h.cookies.expiredCodeVerifier())
+	http.SetCookie(w, h.cookies.expiredNonce())
+	http.SetCookie(w, h.cookies.expiredReturnTo())
+	h.resetCallbackFailures(r, proj.Id)
+	event.Success = true
+	h.recordLogin(r, event)
+	http.Redirect(w, r, h.returnTo(r, sealed), http.StatusFound)
 }
 
-func checkState(r *http.Request, key string, state string) error {
-	rawStateToken, err := hex.DecodeString(state)
+// codeVerifier returns the PKCE code verifier of the login flow kept in the sealed state or the cookie.
+func codeVerifier(r *http.Request, sealed *sealedState) (string, error) {
+	if sealed != nil {
+		if sealed.CodeVerifier == "" {
+			return "", fmt.Errorf("missing code verifier in the state")
+		}
+		return sealed.CodeVerifier, nil
+	}
+	c, err := r.Cookie(codeVerifierCookieKey)
 	if err != nil {
-		return err
+		return "", err
+	}
+	if c.Value == "" {
+		return "", fmt.Errorf("empty code verifier cookie")
 	}
+	return c.Value, nil
+}
 
-	stateToken := string(rawStateToken)
-	if !xsrftoken.Valid(stateToken, key, "", "") {
-		return fmt.Errorf("invalid state")
+// stateNonce returns the nonce of the login flow kept in the sealed state or the cookie.
+func (h *authHandler) stateNonce(r *http.Request, sealed *sealedState) (string, error) {
+	if sealed != nil {
+		if sealed.Nonce == "" {
+			return "", fmt.Errorf("missing nonce in the state")
+		}
+		return sealed.Nonce, nil
+	}
+	c, err := r.Cookie(nonceCookieKey)
+	if err != nil {
+		return "", err
+	}
+	return verifySignedNonce(h.stateKey, c.Value)
+}
+
+// errNoRoleAssigned is returned when the user was granted no role at login.
+var errNoRoleAssigned = errors.New("no role assigned")
+
+// ensureRole assigns the stray roles of the project to the user who was granted no role by the SSO provider.
+// It returns errNoRoleAssigned if the project has no stray role, so that such user is never logged in without a role.
+func ensureRole(project *model.Project, user *model.User) error {
+	if user.Role == nil {
+		user.Role = &model.Role{ProjectId: project.Id}
+	}
+	if len(user.Role.ProjectRbacRoles) != 0 {
+		return nil
+	}
+	if !project.AssignStrayRoles(user.Role) {
+		return fmt.Errorf("%w: user %s matched no user group of project %s", errNoRoleAssigned, user.Username, project.Id)
 	}
+	return nil
+}
+
+// sessionTokenTTL returns how long the token issued with the given role is valid.
+// The session ttl of the user groups granting the role takes precedence over the one of the SSO configuration.
+func sessionTokenTTL(sso *model.ProjectSSOConfig, project *model.Project, role *model.Role) time.Duration {
+	if ttl := project.SessionTTL(role.GetProjectRbacRoles()); ttl > 0 {
+		return ttl
+	}
+	if sso.SessionTtl != 0 {
+		return time.Duration(sso.SessionTtl) * time.Hour
+	}
+	return defaultTokenTTL
+}
+
+// idpInitiatedMaxTokenAge is the max age of the ID token accepted in the IdP-initiated login.
+const idpInitiatedMaxTokenAge = 5 * time.Minute
+
+// idpInitiatedLoginAllowed returns whether the login without the state is accepted.
+// Only OIDC supports it since the ID token is strictly validated instead of the state.
+func idpInitiatedLoginAllowed(sso *model.ProjectSSOConfig) bool {
+	return sso.Provider == model.ProjectSSOConfig_OIDC && sso.Oidc != nil && sso.Oidc.AllowIdpInitiatedLogin
+}
 
-	c, err := r.Cookie(stateCookieKey)
+// stateClockSkew is the tolerance of the issued time of the state in the future.
+const stateClockSkew = time.Minute
+
+// errStateExpired is returned when the state token was valid but has expired.
+var errStateExpired = errors.New("state expired")
+
+// checkState checks the state along with the state cookie of the given name
+// and returns the name of the SSO configuration selected at login.
+func checkState(r *http.Request, cookieName, key string, state string, ttl time.Duration, now time.Time) (string, error) {
+	ps, err := parseState(state)
 	if err != nil {
-		return err
+		return "", err
+	}
+	rawStateToken, err := hex.DecodeString(ps.token)
+	if err != nil {
+		return "", err
 	}
 
-	secretState := c.Value
-	if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(secretState)) != 1 {
-		return fmt.Errorf("wrong state")
+	if err := checkStateToken(string(rawStateToken), key, ttl, now); err != nil {
+		return "", err
 	}
 
-	return nil
+	c, err := r.Cookie(cookieName)
+	if err != nil {
+		return "", err
+	}
+
+	if err := checkStateBinding(state, c.Value); err != nil {
+		return "", err
+	}
+	return ps.ssoName, nil
 }
 
-func getUser(ctx context.Context, sso *model.ProjectSSOConfig, project *model.Project, code string) (*model.User, error) {
-	switch sso.Provider {
-	case model.ProjectSSOConfig_GITHUB:
-		if sso.Github == nil {
-			return nil, fmt.Errorf("missing GitHub oauth in the SSO configuration")
-		}
-		cli, err := github.NewOAuthClient(ctx, sso.Github, project, code)
-		if err != nil {
-			return nil, err
-		}
-		return cli.GetUser(ctx)
-	case model.ProjectSSOConfig_OIDC:
-		if sso.Oidc == nil {
-			return nil, fmt.Errorf("missing OIDC oauth in the SSO configuration")
-		}
-		cli, err := oidc.NewOAuthClient(ctx, sso.Oidc, project, code)
-		if err != nil {
-			return nil, err
-		}
-		return cli.GetUser(ctx)
-	default:
-		return nil, fmt.Errorf("not implemented")
+// checkStateToken checks the signature of the state token and
+// whether it was issued within the given TTL at the given time.
+func checkStateToken(token, key string, ttl time.Duration, now time.Time) error {
+	// The token is in the format of "signature:issued-time-in-milliseconds".
+	sep := strings.LastIndex(token, ":")
+	if sep < 0 {
+		return fmt.Errorf("invalid state")
+	}
+	millis, err := strconv.ParseInt(token[sep+1:], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid state: %w", err)
 	}
+	if !hmac.Equal([]byte(token[:sep]), []byte(stateTokenSignature(key, millis))) {
+		return fmt.Errorf("invalid state")
+	}
+	issued := time.UnixMilli(millis)
+	// Allow the clock of the server which issued the state to be slightly ahead.
+	if issued.After(now.Add(stateClockSkew)) {
+		return fmt.Errorf("invalid state: issued in the future")
+	}
+	if now.Sub(issued) >= ttl {
+		return errStateExpired
+	}
+	return nil
 }
 
+// errMissingStateToken is returned when the token segment of the state is empty, e.g. ":project=<project-id>".
+var errMissingStateToken = errors.New("missing state token")
+
+// parseProjectAndState returns the state and the project ID of the callback request.
+// The project ID is carried by the state in the format of "<state>:project=<project-id>"
+// for the providers whose redirect URI cannot have it, e.g. OIDC, Okta, GitLab, Bitbucket or Google,
+// otherwise by the query parameter.
 func parseProjectAndState(r *http.Request) (string, string, error) {
 	state := r.FormValue(stateFormKey)
 	if state == "" {
 		return "", "", fmt.Errorf("missing state")
 	}
+	formProjectID := r.FormValue(projectFormKey)
 
-	// When using OIDC SSO, the state is in the format of "state-token:project-id".
-	s := strings.Split(state, ":")
-	if len(s) != 2 {
-		projectID := r.FormValue(projectFormKey)
-		if projectID == "" {
-			return s[0], "", fmt.Errorf("missing project id")
+	s := strings.SplitN(state, model.ProjectStateMarker, 2)
+	if len(s) == 1 {
+		if hasEmptyStateSegment(state) {
+			return "", "", errMissingStateToken
 		}
-		return state, projectID, nil
-	} else {
-		if s[1] == "" {
-			return s[0], "", fmt.Errorf("missing project id")
+		if formProjectID == "" {
+			return state, "", fmt.Errorf("missing project id")
 		}
-		return s[0], s[1], nil
+		return state, formProjectID, nil
+	}
+
+	state, projectID := s[0], s[1]
+	if hasEmptyStateSegment(state) {
+		return "", "", errMissingStateToken
+	}
+	if projectID == "" {
+		return state, "", fmt.Errorf("missing project id")
+	}
+	if strings.Contains(projectID, model.ProjectStateMarker) {
+		return state, "", fmt.Errorf("ambiguous state: multiple project ids")
+	}
+	if formProjectID != "" && subtle.ConstantTimeCompare([]byte(formProjectID), []byte(projectID)) != 1 {
+		return state, "", fmt.Errorf("ambiguous state: project id in the state does not match the query parameter")
+	}
+	return state, projectID, nil
+}
+
+// hasEmptyStateSegment reports whether the given state has an empty segment separated by colons,
+// e.g. ":proj", "tok:" or "::", which the issued states never have, so that checkState never
+// proceeds with an empty token.
+func hasEmptyStateSegment(state string) bool {
+	return slices.Contains(strings.Split(state, ":"), "")
+}
+
+// parseCallbackForm parses the form of the callback request whose body is limited by maxCallbackBodySize,
+// so that the oversized payloads do not exhaust the memory.
+func (h *authHandler) parseCallbackForm(w http.ResponseWriter, r *http.Request) error {
+	limit := h.maxCallbackBodySize
+	if limit <= 0 {
+		limit = defaultMaxCallbackBodySize
+	}
+	if r.Body != nil {
+		r.Body = http.MaxBytesReader(w, r.Body, limit)
 	}
+	return r.ParseForm()
 }
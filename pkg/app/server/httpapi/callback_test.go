@@ -15,12 +15,24 @@
 package httpapi
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/net/xsrftoken"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/github"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oidc"
 )
 
 func TestParseProjectAndState(t *testing.T) {
@@ -31,6 +43,7 @@ func TestParseProjectAndState(t *testing.T) {
 		expectedState string
 		expectedProj  string
 		expectErr     bool
+		expectedErr   error
 	}{
 		{
 			name:          "missing state",
@@ -51,14 +64,14 @@ func TestParseProjectAndState(t *testing.T) {
 		{
 			name: "state with project id",
 			formValues: url.Values{
-				stateFormKey: {"state-token:project-id"},
+				stateFormKey: {"state-token:project=project-id"},
 			},
 			expectedState: "state-token",
 			expectedProj:  "project-id",
 			expectErr:     false,
 		},
 		{
-			name: "state without colon and project id in form",
+			name: "state without marker and project id in form",
 			formValues: url.Values{
 				stateFormKey:   {"state-token"},
 				projectFormKey: {"project-id"},
@@ -68,9 +81,119 @@ func TestParseProjectAndState(t *testing.T) {
 			expectErr:     false,
 		},
 		{
-			name: "state with colon but missing project id in form",
+			name: "state containing colons and project id in form",
+			formValues: url.Values{
+				stateFormKey:   {"state:token:with:colons"},
+				projectFormKey: {"project-id"},
+			},
+			expectedState: "state:token:with:colons",
+			expectedProj:  "project-id",
+			expectErr:     false,
+		},
+		{
+			name: "state containing colons with project id",
+			formValues: url.Values{
+				stateFormKey: {"state:token:project=project-id"},
+			},
+			expectedState: "state:token",
+			expectedProj:  "project-id",
+			expectErr:     false,
+		},
+		{
+			name: "state containing colons without marker",
+			formValues: url.Values{
+				stateFormKey: {"state-token:project-id"},
+			},
+			expectedState: "state-token:project-id",
+			expectedProj:  "",
+			expectErr:     true,
+		},
+		{
+			name: "state with marker but missing project id",
+			formValues: url.Values{
+				stateFormKey: {"state-token:project="},
+			},
+			expectedState: "state-token",
+			expectedProj:  "",
+			expectErr:     true,
+		},
+		{
+			name: "marker without state",
+			formValues: url.Values{
+				stateFormKey: {":project=project-id"},
+			},
+			expectedState: "",
+			expectedProj:  "",
+			expectErr:     true,
+			expectedErr:   errMissingStateToken,
+		},
+		{
+			name: "multiple markers",
+			formValues: url.Values{
+				stateFormKey: {"state-token:project=project-a:project=project-b"},
+			},
+			expectedState: "state-token",
+			expectedProj:  "",
+			expectErr:     true,
+		},
+		{
+			name: "empty token before colon",
+			formValues: url.Values{
+				stateFormKey:   {":proj"},
+				projectFormKey: {"project-id"},
+			},
+			expectedState: "",
+			expectedProj:  "",
+			expectErr:     true,
+			expectedErr:   errMissingStateToken,
+		},
+		{
+			name: "empty token after colon",
+			formValues: url.Values{
+				stateFormKey:   {"tok:"},
+				projectFormKey: {"project-id"},
+			},
+			expectedState: "",
+			expectedProj:  "",
+			expectErr:     true,
+			expectedErr:   errMissingStateToken,
+		},
+		{
+			name: "only colons",
+			formValues: url.Values{
+				stateFormKey:   {"::"},
+				projectFormKey: {"project-id"},
+			},
+			expectedState: "",
+			expectedProj:  "",
+			expectErr:     true,
+			expectedErr:   errMissingStateToken,
+		},
+		{
+			name: "empty token segment with project id",
+			formValues: url.Values{
+				stateFormKey: {"tok::project=project-id"},
+			},
+			expectedState: "",
+			expectedProj:  "",
+			expectErr:     true,
+			expectedErr:   errMissingStateToken,
+		},
+		{
+			name: "same project id in state and form",
+			formValues: url.Values{
+				stateFormKey:   {"state-token:project=project-id"},
+				projectFormKey: {"project-id"},
+			},
+			expectedState: "state-token",
+			expectedProj:  "project-id",
+			expectErr:     false,
+		},
+		{
+			name: "different project ids in state and form",
 			formValues: url.Values{
-				stateFormKey: {"state-token:"},
+				stateFormKey:   {"state-token:project=project-a"},
+				projectFormKey: {"project-b"},
 			},
 			expectedState: "state-token",
 			expectedProj:  "",
@@ -87,6 +210,9 @@ func TestParseProjectAndState(t *testing.T) {
 			state, project, err := parseProjectAndState(req)
 			if tt.expectErr {
 				assert.Error(t, err)
+				if tt.expectedErr != nil {
+					assert.ErrorIs(t, err, tt.expectedErr)
+				}
 			} else {
 				assert.NoError(t, err)
 			}
@@ -95,3 +221,507 @@ func TestParseProjectAndState(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckStateToken(t *testing.T) {
+	t.Parallel()
+	const (
+		key = "state-key"
+		ttl = 10 * time.Minute
+	)
+	token := xsrftoken.Generate(key, "", "")
+	millis, err := strconv.ParseInt(token[strings.LastIndex(token, ":")+1:], 10, 64)
+	require.NoError(t, err)
+	issuedAt := time.UnixMilli(millis)
+
+	tests := []struct {
+		name        string
+		token       string
+		key         string
+		now         time.Time
+		expectedErr error
+		expectErr   bool
+	}{
+		{
+			name:  "valid",
+			token: token,
+			key:   key,
+			now:   issuedAt,
+		},
+		{
+			name:  "just valid",
+			token: token,
+			key:   key,
+			now:   issuedAt.Add(ttl - time.Millisecond),
+		},
+		{
+			name:        "just expired",
+			token:       token,
+			key:         key,
+			now:         issuedAt.Add(ttl),
+			expectedErr: errStateExpired,
+			expectErr:   true,
+		},
+		{
+			name:      "wrong key",
+			token:     token,
+			key:       "other-key",
+			now:       issuedAt,
+			expectErr: true,
+		},
+		{
+			name:      "issued in the future",
+			token:     token,
+			key:       key,
+			now:       issuedAt.Add(-stateClockSkew - time.Millisecond),
+			expectErr: true,
+		},
+		{
+			name:  "issued by the clock slightly ahead",
+			token: token,
+			key:   key,
+			now:   issuedAt.Add(-stateClockSkew),
+		},
+		{
+			name:      "malformed",
+			token:     "malformed",
+			key:       key,
+			now:       issuedAt,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := checkStateToken(tt.token, tt.key, ttl, tt.now)
+			assert.Equal(t, tt.expectErr, err != nil)
+			if tt.expectedErr != nil {
+				assert.True(t, errors.Is(err, tt.expectedErr))
+			} else if err != nil {
+				assert.False(t, errors.Is(err, errStateExpired))
+			}
+		})
+	}
+}
+
+func TestSessionTokenTTL(t *testing.T) {
+	t.Parallel()
+	project := &model.Project{
+		UserGroups: []*model.ProjectUserGroup{
+			{SsoGroup: "team/admin", Role: "Admin", SessionTtl: 1},
+			{SsoGroup: "team/viewer", Role: "Viewer", SessionTtl: 720},
+			{SsoGroup: "team/editor", Role: "Editor"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		sso      *model.ProjectSSOConfig
+		roles    []string
+		expected time.Duration
+	}{
+		{
+			name:     "group ttl overrides sso ttl",
+			sso:      &model.ProjectSSOConfig{SessionTtl: 24},
+			roles:    []string{"Viewer"},
+			expected: 720 * time.Hour,
+		},
+		{
+			name:     "shortest group ttl is used",
+			sso:      &model.ProjectSSOConfig{SessionTtl: 24},
+			roles:    []string{"Viewer", "Admin"},
+			expected: time.Hour,
+		},
+		{
+			name:     "sso ttl is used without group ttl",
+			sso:      &model.ProjectSSOConfig{SessionTtl: 24},
+			roles:    []string{"Editor"},
+			expected: 24 * time.Hour,
+		},
+		{
+			name:     "default ttl",
+			sso:      &model.ProjectSSOConfig{},
+			roles:    []string{"Editor"},
+			expected: defaultTokenTTL,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got := sessionTokenTTL(tt.sso, project, &model.Role{ProjectRbacRoles: tt.roles})
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestCallbackIdPInitiated(t *testing.T) {
+	t.Parallel()
+	// The discovery fails so that the login stops right after the state checks.
+	idp := httptest.NewServer(http.NotFoundHandler())
+	defer idp.Close()
+
+	tests := []struct {
+		name                  string
+		allow                 bool
+		expectedFailureReason string
+	}{
+		{
+			name:                  "not allowed",
+			allow:                 false,
+			expectedFailureReason: "Unauthorized access",
+		},
+		{
+			name:                  "allowed",
+			allow:                 true,
+			expectedFailureReason: "Unable to find user",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			recorder := &fakeAuditRecorder{}
+			h := &authHandler{
+				stateKey: "state-key",
+				sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+					"oidc": {
+						Provider: model.ProjectSSOConfig_OIDC,
+						Oidc: &model.ProjectSSOConfig_Oidc{
+							ClientId:               "client-id",
+							Issuer:                 idp.URL,
+							AllowIdpInitiatedLogin: tt.allow,
+						},
+					},
+				},
+				projectGetter: fakeProjectGetter{
+					"project": {
+						Id:            "project",
+						SharedSsoName: "oidc",
+						UserGroups:    []*model.ProjectUserGroup{},
+					},
+				},
+				auditRecorder: recorder,
+				logger:        zap.NewNop(),
+			}
+
+			req := httptest.NewRequest(http.MethodGet, callbackPath+"?project=project&code=code", nil)
+			h.handleCallback(httptest.NewRecorder(), req)
+
+			require.Len(t, recorder.events, 1)
+			got := recorder.events[0]
+			assert.False(t, got.Success)
+			assert.True(t, got.IdPInitiated)
+			assert.Equal(t, "project", got.ProjectID)
+			assert.Equal(t, model.ProjectSSOConfig_OIDC.String(), got.Provider)
+			assert.Equal(t, tt.expectedFailureReason, got.FailureReason)
+		})
+	}
+}
+
+func TestCallbackNoCacheHeaders(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		signer:   fakeSigner{},
+		stateKey: "state-key",
+		sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+			"oidc": {
+				Provider: model.ProjectSSOConfig_OIDC,
+				Oidc: &model.ProjectSSOConfig_Oidc{
+					AllowIdpInitiatedLogin: true,
+				},
+			},
+		},
+		userResolvers: map[model.ProjectSSOConfig_Provider]UserResolver{
+			model.ProjectSSOConfig_OIDC: fakeUserResolver{user: &model.User{
+				Username: "user",
+				Role:     &model.Role{ProjectId: "project", ProjectRbacRoles: []string{model.BuiltinRBACRoleViewer.String()}},
+			}},
+		},
+		projectGetter: fakeProjectGetter{
+			"project": {
+				Id:            "project",
+				SharedSsoName: "oidc",
+				UserGroups:    []*model.ProjectUserGroup{},
+			},
+		},
+		logger: zap.NewNop(),
+	}
+
+	tests := []struct {
+		name         string
+		target       string
+		expectedCode int
+	}{
+		{
+			name:         "logged in",
+			target:       callbackPath + "?project=project&code=code",
+			expectedCode: http.StatusFound,
+		},
+		{
+			name:         "invalid state",
+			target:       callbackPath + "?state=malformed&code=code",
+			expectedCode: http.StatusSeeOther,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			rec := httptest.NewRecorder()
+			h.handleCallback(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+			assert.Equal(t, "no-cache", rec.Header().Get("Pragma"))
+			assert.Equal(t, defaultContentSecurityPolicy, rec.Header().Get("Content-Security-Policy"))
+		})
+	}
+}
+
+func TestCallbackCodeExpired(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{
+			name: "github",
+			err:  fmt.Errorf("%w: bad_verification_code", github.ErrCodeExpired),
+		},
+		{
+			name: "oidc",
+			err:  fmt.Errorf("%w: invalid_grant", oidc.ErrCodeExpired),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			recorder := &fakeAuditRecorder{}
+			h := &authHandler{
+				sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+					"oidc": {
+						Provider: model.ProjectSSOConfig_OIDC,
+						Oidc:     &model.ProjectSSOConfig_Oidc{AllowIdpInitiatedLogin: true},
+					},
+				},
+				projectGetter: fakeProjectGetter{
+					"project": {
+						Id:            "project",
+						SharedSsoName: "oidc",
+						UserGroups:    []*model.ProjectUserGroup{},
+					},
+				},
+				userResolvers: map[model.ProjectSSOConfig_Provider]UserResolver{
+					model.ProjectSSOConfig_OIDC: fakeUserResolver{err: tt.err},
+				},
+				auditRecorder: recorder,
+				logger:        zap.NewNop(),
+			}
+
+			rec := httptest.NewRecorder()
+			h.handleCallback(rec, httptest.NewRequest(http.MethodGet, callbackPath+"?project=project&code=code", nil))
+
+			assert.Equal(t, http.StatusSeeOther, rec.Code)
+			assert.Equal(t, "/login?login_error=login_expired&project=project", rec.Header().Get("Location"))
+			require.Len(t, recorder.events, 1)
+			assert.Equal(t, "Your login timed out, please try again", recorder.events[0].FailureReason)
+			assert.Equal(t, string(errCodeLoginExpired), recorder.events[0].ErrorCode)
+		})
+	}
+}
+
+func TestCallbackReauthenticationRequired(t *testing.T) {
+	t.Parallel()
+	recorder := &fakeAuditRecorder{}
+	h := &authHandler{
+		sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+			"oidc": {
+				Provider: model.ProjectSSOConfig_OIDC,
+				Oidc:     &model.ProjectSSOConfig_Oidc{AllowIdpInitiatedLogin: true, MaxAge: 300},
+			},
+		},
+		projectGetter: fakeProjectGetter{
+			"project": {
+				Id:            "project",
+				SharedSsoName: "oidc",
+				UserGroups:    []*model.ProjectUserGroup{},
+			},
+		},
+		userResolvers: map[model.ProjectSSOConfig_Provider]UserResolver{
+			model.ProjectSSOConfig_OIDC: fakeUserResolver{err: fmt.Errorf("%w: missing auth_time in id_token", oidc.ErrReauthenticationRequired)},
+		},
+		auditRecorder: recorder,
+		logger:        zap.NewNop(),
+	}
+
+	rec := httptest.NewRecorder()
+	h.handleCallback(rec, httptest.NewRequest(http.MethodGet, callbackPath+"?project=project&code=code", nil))
+
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/login?login_error=login_expired&project=project", rec.Header().Get("Location"))
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, "reauthentication required", recorder.events[0].FailureReason)
+	assert.Equal(t, string(errCodeLoginExpired), recorder.events[0].ErrorCode)
+}
+
+func TestCallbackMaxBodySize(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+			"oidc": {
+				Provider: model.ProjectSSOConfig_OIDC,
+				Oidc:     &model.ProjectSSOConfig_Oidc{AllowIdpInitiatedLogin: true},
+			},
+		},
+		projectGetter: fakeProjectGetter{
+			"project": {
+				Id:            "project",
+				SharedSsoName: "oidc",
+				UserGroups:    []*model.ProjectUserGroup{},
+			},
+		},
+		userResolvers: map[model.ProjectSSOConfig_Provider]UserResolver{
+			model.ProjectSSOConfig_OIDC: fakeUserResolver{user: &model.User{
+				Username: "user",
+				Role:     &model.Role{ProjectId: "project", ProjectRbacRoles: []string{model.BuiltinRBACRoleViewer.String()}},
+			}},
+		},
+		signer:              fakeSigner{},
+		maxCallbackBodySize: 1024,
+		logger:              zap.NewNop(),
+	}
+
+	tests := []struct {
+		name         string
+		body         string
+		accept       string
+		expectedCode int
+	}{
+		{
+			name:         "within the limit",
+			body:         "project=project&code=code",
+			expectedCode: http.StatusFound,
+		},
+		{
+			name:         "too large",
+			body:         "project=project&code=" + strings.Repeat("c", 1024),
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:         "too large with json",
+			body:         "project=project&code=" + strings.Repeat("c", 1024),
+			accept:       "application/json",
+			expectedCode: http.StatusRequestEntityTooLarge,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodPost, callbackPath, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			rec := httptest.NewRecorder()
+			h.handleCallback(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusRequestEntityTooLarge {
+				// The oversized request is not redirected to retry the login.
+				assert.Empty(t, rec.Header().Get("Location"))
+			}
+			if tt.accept != "" {
+				assert.Contains(t, rec.Body.String(), string(errCodeRequestTooLarge))
+			}
+		})
+	}
+}
+
+func TestIdPInitiatedLoginAllowed(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		sso      *model.ProjectSSOConfig
+		expected bool
+	}{
+		{
+			name:     "oidc allowing idp-initiated login",
+			sso:      &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_OIDC, Oidc: &model.ProjectSSOConfig_Oidc{AllowIdpInitiatedLogin: true}},
+			expected: true,
+		},
+		{
+			name:     "oidc by default",
+			sso:      &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_OIDC, Oidc: &model.ProjectSSOConfig_Oidc{}},
+			expected: false,
+		},
+		{
+			name:     "missing oidc config",
+			sso:      &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_OIDC},
+			expected: false,
+		},
+		{
+			name:     "other provider",
+			sso:      &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_GITHUB, Github: &model.ProjectSSOConfig_GitHub{}},
+			expected: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, idpInitiatedLoginAllowed(tt.sso))
+		})
+	}
+}
+
+func TestEnsureRole(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name          string
+		project       *model.Project
+		role          *model.Role
+		expectedRoles []string
+		expectErr     bool
+	}{
+		{
+			name:          "mapped",
+			project:       &model.Project{Id: "project", DefaultRole: "Viewer"},
+			role:          &model.Role{ProjectId: "project", ProjectRbacRoles: []string{"Editor"}},
+			expectedRoles: []string{"Editor"},
+		},
+		{
+			name:          "unmapped with default role",
+			project:       &model.Project{Id: "project", DefaultRole: "Viewer"},
+			role:          &model.Role{ProjectId: "project"},
+			expectedRoles: []string{"Viewer"},
+		},
+		{
+			name:          "unmapped without role with default role",
+			project:       &model.Project{Id: "project", DefaultRole: "Viewer"},
+			expectedRoles: []string{"Viewer"},
+		},
+		{
+			name:          "unmapped allowing stray as viewer",
+			project:       &model.Project{Id: "project", AllowStrayAsViewer: true},
+			role:          &model.Role{ProjectId: "project"},
+			expectedRoles: []string{"Viewer"},
+		},
+		{
+			name:      "unmapped without default role",
+			project:   &model.Project{Id: "project"},
+			role:      &model.Role{ProjectId: "project"},
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			user := &model.User{Username: "user", Role: tt.role}
+			err := ensureRole(tt.project, user)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, errNoRoleAssigned)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRoles, user.Role.ProjectRbacRoles)
+			assert.Equal(t, "project", user.Role.ProjectId)
+		})
+	}
+}
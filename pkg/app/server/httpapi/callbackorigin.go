@@ -0,0 +1,65 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// CallbackOrigins are the origins allowed to send the callback requests in POST or with the Origin header,
+// e.g. the control plane itself https://pipecd.example.com.
+type CallbackOrigins []string
+
+// errUnexpectedCallbackOrigin is returned when the callback request comes from an origin not allowed.
+var errUnexpectedCallbackOrigin = errors.New("unexpected callback origin")
+
+// ParseCallbackOrigins parses the origins allowed to send the callback requests in the form of "<scheme>://<host>[:<port>]".
+func ParseCallbackOrigins(origins []string) (CallbackOrigins, error) {
+	return parseOrigins("callback origin", origins)
+}
+
+// WithStrictCallbackOrigins rejects the callback requests coming from another origin than the given ones
+// as the defense in depth against the login CSRF on top of the state. The check only applies to the callback
+// requests in POST or with the Origin header, since some providers redirect the browser to the callback in GET,
+// which comes without the Origin header.
+func WithStrictCallbackOrigins(origins CallbackOrigins) Option {
+	return func(h *authHandler) {
+		h.callbackOrigins = origins
+	}
+}
+
+// checkCallbackOrigin returns errUnexpectedCallbackOrigin unless the given callback request comes from
+// one of the allowed origins. The Origin header is used if any, otherwise the origin of the Referer header.
+// The POST request without both of them is rejected.
+func (h *authHandler) checkCallbackOrigin(r *http.Request) error {
+	if len(h.callbackOrigins) == 0 {
+		return nil
+	}
+	if r.Method != http.MethodPost && r.Header.Get("Origin") == "" {
+		return nil
+	}
+	origin, err := requestOrigin(r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errUnexpectedCallbackOrigin, err)
+	}
+	if !slices.Contains(h.callbackOrigins, strings.ToLower(origin)) {
+		return fmt.Errorf("%w: %q", errUnexpectedCallbackOrigin, origin)
+	}
+	return nil
+}
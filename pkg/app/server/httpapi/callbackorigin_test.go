@@ -0,0 +1,125 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestParseCallbackOrigins(t *testing.T) {
+	t.Parallel()
+	origins, err := ParseCallbackOrigins([]string{"https://PipeCD.Example.com/"})
+	require.NoError(t, err)
+	assert.Equal(t, CallbackOrigins{"https://pipecd.example.com"}, origins)
+
+	_, err = ParseCallbackOrigins([]string{"pipecd.example.com"})
+	assert.Error(t, err)
+}
+
+func TestCheckCallbackOrigin(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{callbackOrigins: CallbackOrigins{"https://pipecd.example.com"}}
+	testcases := []struct {
+		name    string
+		method  string
+		headers map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "get without origin",
+			method: http.MethodGet,
+		},
+		{
+			name:    "get with unexpected origin",
+			method:  http.MethodGet,
+			headers: map[string]string{"Origin": "https://evil.example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "post with origin",
+			method:  http.MethodPost,
+			headers: map[string]string{"Origin": "https://pipecd.example.com"},
+		},
+		{
+			name:    "post with referer",
+			method:  http.MethodPost,
+			headers: map[string]string{"Origin": "null", "Referer": "https://pipecd.example.com/login"},
+		},
+		{
+			name:    "post with unexpected origin",
+			method:  http.MethodPost,
+			headers: map[string]string{"Origin": "https://evil.example.com", "Referer": "https://pipecd.example.com/login"},
+			wantErr: true,
+		},
+		{
+			name:    "post without origin",
+			method:  http.MethodPost,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, callbackPath, nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			err := h.checkCallbackOrigin(req)
+			if tc.wantErr {
+				assert.ErrorIs(t, err, errUnexpectedCallbackOrigin)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	// Not checked unless configured.
+	req := httptest.NewRequest(http.MethodPost, callbackPath, nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	assert.NoError(t, (&authHandler{}).checkCallbackOrigin(req))
+}
+
+func TestCallbackStrictOrigin(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+			"oidc": {
+				Provider: model.ProjectSSOConfig_OIDC,
+				Oidc:     &model.ProjectSSOConfig_Oidc{AllowIdpInitiatedLogin: true},
+			},
+		},
+		projectGetter: fakeProjectGetter{
+			"project": {Id: "project", SharedSsoName: "oidc"},
+		},
+		callbackOrigins: CallbackOrigins{"https://pipecd.example.com"},
+		logger:          zap.NewNop(),
+	}
+	req := httptest.NewRequest(http.MethodPost, callbackPath, strings.NewReader("project=project&code=code"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Origin", "https://evil.example.com")
+	rec := httptest.NewRecorder()
+	h.handleCallback(rec, req)
+
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, loginErrorURL(errCodeForbidden, ""), rec.Header().Get("Location"))
+}
@@ -0,0 +1,125 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+var ssoChooserTemplate = template.Must(template.New("chooser").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Log in to {{.Project}}</title>
+</head>
+<body>
+<h1>Log in to {{.Project}}</h1>
+{{range .Choices}}
+<form method="post" action="{{if .LDAP}}{{$.LDAPLoginPath}}{{else}}{{$.LoginPath}}{{end}}">
+<input type="hidden" name="{{$.ProjectFormKey}}" value="{{$.Project}}">
+<input type="hidden" name="{{$.SSOFormKey}}" value="{{.Name}}">
+{{if $.ReturnTo}}<input type="hidden" name="{{$.ReturnToFormKey}}" value="{{$.ReturnTo}}">{{end}}
+{{if .LDAP}}<input type="text" name="{{$.UsernameFormKey}}" placeholder="Username" required>
+<input type="password" name="{{$.PasswordFormKey}}" placeholder="Password" required>
+{{end}}<button type="submit">{{if .Icon}}<img src="{{.Icon}}" alt="" width="16" height="16"> {{end}}Log in with {{.Label}}</button>
+</form>
+{{end}}
+</body>
+</html>
+`))
+
+type ssoChooserItem struct {
+	Name  string
+	Label string
+	Icon  string
+	LDAP  bool
+}
+
+// writeSSOChooserPage responds the page to select the SSO configuration to log in with.
+// Each choice posts the login request again along with its name and the validated return target.
+func writeSSOChooserPage(w http.ResponseWriter, projectID string, choices []ssoChoice, returnTo string) error {
+	items := make([]ssoChooserItem, 0, len(choices))
+	for _, c := range choices {
+		items = append(items, ssoChooserItem{
+			Name:  c.name,
+			Label: ssoChoiceLabel(c),
+			Icon:  ssoIconURL(c.sso.Icon),
+			LDAP:  c.sso.Provider == model.ProjectSSOConfig_LDAP,
+		})
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	return ssoChooserTemplate.Execute(w, map[string]interface{}{
+		"Project":         projectID,
+		"Choices":         items,
+		"ReturnTo":        returnTo,
+		"LoginPath":       loginPath,
+		"LDAPLoginPath":   ldapLoginPath,
+		"ProjectFormKey":  projectFormKey,
+		"SSOFormKey":      ssoFormKey,
+		"ReturnToFormKey": returnToFormKey,
+		"UsernameFormKey": usernameFormKey,
+		"PasswordFormKey": passwordFormKey,
+	})
+}
+
+// ssoChoiceLabel returns the display name of the given choice. Unless configured, it is derived from the provider
+// followed by the name of the additional configuration to tell the ones of the same provider apart.
+func ssoChoiceLabel(c ssoChoice) string {
+	if c.sso.DisplayName != "" {
+		return c.sso.DisplayName
+	}
+	label := ssoProviderLabel(c.sso.Provider)
+	if c.name != "" {
+		label += " (" + c.name + ")"
+	}
+	return label
+}
+
+// ssoIconURL returns the given icon if it is an HTTPS URL or an absolute path, otherwise empty to show no icon.
+func ssoIconURL(icon string) string {
+	if strings.HasPrefix(icon, "/") && !strings.HasPrefix(icon, "//") {
+		return icon
+	}
+	if u, err := url.Parse(icon); err == nil && u.Scheme == "https" && u.Host != "" {
+		return icon
+	}
+	return ""
+}
+
+func ssoProviderLabel(p model.ProjectSSOConfig_Provider) string {
+	switch p {
+	case model.ProjectSSOConfig_GITHUB:
+		return "GitHub"
+	case model.ProjectSSOConfig_GITLAB:
+		return "GitLab"
+	case model.ProjectSSOConfig_BITBUCKET:
+		return "Bitbucket"
+	case model.ProjectSSOConfig_GOOGLE:
+		return "Google"
+	case model.ProjectSSOConfig_AZUREAD:
+		return "Microsoft Entra ID"
+	case model.ProjectSSOConfig_OKTA:
+		return "Okta"
+	default:
+		return p.String()
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func newChooserTestHandler() *authHandler {
+	return &authHandler{
+		stateKey:    "state-key",
+		stateTTL:    time.Minute,
+		callbackURL: "https://pipecd.example.com/auth/callback",
+		sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+			"github": {
+				Provider: model.ProjectSSOConfig_GITHUB,
+				Github:   &model.ProjectSSOConfig_GitHub{ClientId: "github-client"},
+			},
+			"google": {
+				Provider: model.ProjectSSOConfig_GOOGLE,
+				Google: &model.ProjectSSOConfig_Google{
+					ClientId:    "google-client",
+					RedirectUri: "https://pipecd.example.com/auth/callback",
+				},
+			},
+			"ldap": {
+				Provider: model.ProjectSSOConfig_LDAP,
+				Ldap:     &model.ProjectSSOConfig_Ldap{Url: "ldap://ldap.example.com"},
+			},
+			"corp": {
+				Provider:    model.ProjectSSOConfig_OIDC,
+				DisplayName: "Corporate SSO",
+				Icon:        "https://sso.example.com/icon.png",
+				Oidc:        &model.ProjectSSOConfig_Oidc{ClientId: "corp-client", Issuer: "https://sso.example.com"},
+			},
+		},
+		projectGetter: fakeProjectGetter{
+			"single": {Id: "single", SharedSsoName: "github"},
+			"multi": {
+				Id:                       "multi",
+				SharedSsoName:            "github",
+				AdditionalSharedSsoNames: []string{"google", "ldap", "corp"},
+			},
+		},
+		logger: zap.NewNop(),
+	}
+}
+
+func postLogin(h *authHandler, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, loginPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.handleSSOLogin(rec, req)
+	return rec
+}
+
+func TestHandleSSOLogin_SingleProvider(t *testing.T) {
+	t.Parallel()
+	h := newChooserTestHandler()
+
+	rec := postLogin(h, url.Values{projectFormKey: {"single"}})
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Location"), "https://github.com/login/oauth/authorize"))
+}
+
+func TestHandleSSOLogin_Chooser(t *testing.T) {
+	t.Parallel()
+	h := newChooserTestHandler()
+
+	rec := postLogin(h, url.Values{projectFormKey: {"multi"}, returnToFormKey: {"/applications"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, "Log in with GitHub")
+	assert.Contains(t, body, "Log in with Google (google)")
+	assert.Contains(t, body, "Log in with LDAP (ldap)")
+	assert.Contains(t, body, `action="/auth/login/ldap"`)
+	assert.Contains(t, body, `<img src="https://sso.example.com/icon.png" alt="" width="16" height="16"> Log in with Corporate SSO`)
+	assert.Contains(t, body, `name="return_to" value="/applications"`)
+}
+
+func TestHandleSSOLogin_SelectedProvider(t *testing.T) {
+	t.Parallel()
+	h := newChooserTestHandler()
+
+	rec := postLogin(h, url.Values{projectFormKey: {"multi"}, ssoFormKey: {"google"}})
+	require.Equal(t, http.StatusFound, rec.Code)
+	location, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	assert.Equal(t, "accounts.google.com", location.Host)
+
+	// The selected configuration is kept in the state.
+	state, _, ok := strings.Cut(location.Query().Get("state"), model.ProjectStateMarker)
+	require.True(t, ok)
+	ps, err := parseState(state)
+	require.NoError(t, err)
+	assert.Equal(t, "google", ps.ssoName)
+
+	// The main configuration is selected by the empty name.
+	rec = postLogin(h, url.Values{projectFormKey: {"multi"}, ssoFormKey: {""}})
+	require.Equal(t, http.StatusFound, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Header().Get("Location"), "https://github.com/login/oauth/authorize"))
+
+	// The shared configuration not enabled for the project cannot be selected.
+	rec = postLogin(h, url.Values{projectFormKey: {"single"}, ssoFormKey: {"google"}})
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+}
+
+func TestFindSSOConfigByName(t *testing.T) {
+	t.Parallel()
+	h := newChooserTestHandler()
+	project := &model.Project{
+		Id:                       "multi",
+		SharedSsoName:            "github",
+		AdditionalSharedSsoNames: []string{"google", "missing"},
+	}
+
+	tests := []struct {
+		name             string
+		ssoName          string
+		expectedProvider model.ProjectSSOConfig_Provider
+		expectErr        bool
+	}{
+		{
+			name:             "main configuration",
+			ssoName:          "",
+			expectedProvider: model.ProjectSSOConfig_GITHUB,
+		},
+		{
+			name:             "additional configuration",
+			ssoName:          "google",
+			expectedProvider: model.ProjectSSOConfig_GOOGLE,
+		},
+		{
+			name:      "not enabled for the project",
+			ssoName:   "ldap",
+			expectErr: true,
+		},
+		{
+			name:      "not found",
+			ssoName:   "missing",
+			expectErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			sso, shared, err := h.findSSOConfigByName(project, tt.ssoName)
+			if tt.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, shared)
+			assert.Equal(t, tt.expectedProvider, sso.Provider)
+		})
+	}
+
+	_, err := h.findSSOChoices(project)
+	assert.Error(t, err)
+}
+
+func TestSSOChoiceLabel(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		choice   ssoChoice
+		expected string
+	}{
+		{
+			name:     "main configuration",
+			choice:   ssoChoice{sso: &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_AZUREAD}},
+			expected: "Microsoft Entra ID",
+		},
+		{
+			name:     "additional configuration",
+			choice:   ssoChoice{name: "staff", sso: &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_OIDC}},
+			expected: "OIDC (staff)",
+		},
+		{
+			name:     "display name",
+			choice:   ssoChoice{name: "staff", sso: &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_OIDC, DisplayName: "Staff SSO"}},
+			expected: "Staff SSO",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, ssoChoiceLabel(tt.choice))
+		})
+	}
+}
+
+func TestSSOIconURL(t *testing.T) {
+	t.Parallel()
+	for icon, expected := range map[string]string{
+		"":                              "",
+		"/assets/sso.png":               "/assets/sso.png",
+		"https://example.com/icon.png":  "https://example.com/icon.png",
+		"http://example.com/icon.png":   "",
+		"//example.com/icon.png":        "",
+		"javascript:alert(1)":           "",
+		"data:image/png;base64,iVBORw0": "",
+		"assets/sso.png":                "",
+	} {
+		assert.Equal(t, expected, ssoIconURL(icon), icon)
+	}
+}
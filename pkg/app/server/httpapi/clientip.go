@@ -0,0 +1,113 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ParseTrustedProxies parses the given CIDRs or IP addresses of the trusted proxies.
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	nets := make([]*net.IPNet, 0, len(proxies))
+	for _, p := range proxies {
+		p = strings.TrimSpace(p)
+		if !strings.Contains(p, "/") {
+			ip := net.ParseIP(p)
+			if ip == nil {
+				return nil, fmt.Errorf("invalid trusted proxy %q", p)
+			}
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy %q: %w", p, err)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// WithTrustedProxies sets the proxies whose X-Forwarded-For and X-Real-IP headers are used to find the client IP.
+// The headers are ignored unless the request comes from one of them.
+func WithTrustedProxies(proxies []*net.IPNet) Option {
+	return func(h *authHandler) {
+		h.trustedProxies = proxies
+	}
+}
+
+// clientIP returns the IP address of the client of the given request.
+func (h *authHandler) clientIP(r *http.Request) string {
+	return clientIP(r, h.trustedProxies)
+}
+
+// clientIP returns the IP address of the client of the given request.
+// The forwarded addresses are used only if the request comes from a trusted proxy, then
+// the X-Forwarded-For is read from the right skipping the trusted proxies since the addresses
+// on the left of them can be spoofed by the client. X-Real-IP is used if X-Forwarded-For is absent.
+func clientIP(r *http.Request, trusted []*net.IPNet) string {
+	return clientIPFromHeader(r.RemoteAddr, r.Header, trusted)
+}
+
+// clientIPFromHeader is clientIP for the request from the given address with the given header,
+// e.g. the one of the gRPC request.
+func clientIPFromHeader(remoteAddr string, header http.Header, trusted []*net.IPNet) string {
+	ip := remoteAddr
+	if host, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		ip = host
+	}
+	if !isTrustedProxy(ip, trusted) {
+		return ip
+	}
+
+	if xff := header.Values("X-Forwarded-For"); len(xff) > 0 {
+		addrs := strings.Split(strings.Join(xff, ","), ",")
+		for i := len(addrs) - 1; i >= 0; i-- {
+			addr := strings.TrimSpace(addrs[i])
+			if net.ParseIP(addr) == nil {
+				break
+			}
+			ip = addr
+			if !isTrustedProxy(addr, trusted) {
+				break
+			}
+		}
+		return ip
+	}
+	if addr := strings.TrimSpace(header.Get("X-Real-IP")); net.ParseIP(addr) != nil {
+		return addr
+	}
+	return ip
+}
+
+func isTrustedProxy(ip string, trusted []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, n := range trusted {
+		if n.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
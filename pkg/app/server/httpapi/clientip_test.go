@@ -0,0 +1,131 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTrustedProxies(t *testing.T) {
+	t.Parallel()
+	proxies, err := ParseTrustedProxies([]string{"10.0.0.0/8", "192.0.2.1", "2001:db8::1"})
+	require.NoError(t, err)
+	require.Len(t, proxies, 3)
+	assert.Equal(t, "10.0.0.0/8", proxies[0].String())
+	assert.Equal(t, "192.0.2.1/32", proxies[1].String())
+	assert.Equal(t, "2001:db8::1/128", proxies[2].String())
+
+	_, err = ParseTrustedProxies([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+	_, err = ParseTrustedProxies([]string{"proxy.example.com"})
+	assert.Error(t, err)
+}
+
+func TestClientIP(t *testing.T) {
+	t.Parallel()
+	trusted, err := ParseTrustedProxies([]string{"10.0.0.0/8"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		xRealIP    string
+		expected   string
+	}{
+		{
+			name:       "remote address",
+			remoteAddr: "192.0.2.1:1234",
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "forwarded by trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			xff:        "203.0.113.1",
+			expected:   "203.0.113.1",
+		},
+		{
+			name:       "forwarded by untrusted proxy",
+			remoteAddr: "192.0.2.1:1234",
+			xff:        "203.0.113.1",
+			xRealIP:    "203.0.113.2",
+			expected:   "192.0.2.1",
+		},
+		{
+			name:       "spoofed forwarded",
+			remoteAddr: "10.0.0.1:1234",
+			xff:        "198.51.100.1, 203.0.113.1",
+			expected:   "203.0.113.1",
+		},
+		{
+			name:       "forwarded through multiple trusted proxies",
+			remoteAddr: "10.0.0.1:1234",
+			xff:        "198.51.100.1, 203.0.113.1, 10.0.0.2",
+			expected:   "203.0.113.1",
+		},
+		{
+			name:       "only trusted proxies",
+			remoteAddr: "10.0.0.1:1234",
+			xff:        "10.0.0.3, 10.0.0.2",
+			expected:   "10.0.0.3",
+		},
+		{
+			name:       "malformed forwarded",
+			remoteAddr: "10.0.0.1:1234",
+			xff:        "203.0.113.1, unknown",
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "real ip from trusted proxy",
+			remoteAddr: "10.0.0.1:1234",
+			xRealIP:    "203.0.113.1",
+			expected:   "203.0.113.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.xff != "" {
+				req.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			if tt.xRealIP != "" {
+				req.Header.Set("X-Real-IP", tt.xRealIP)
+			}
+			assert.Equal(t, tt.expected, clientIP(req, trusted))
+		})
+	}
+}
+
+func TestClientIPWithoutTrustedProxies(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{}
+	req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	req.RemoteAddr = "10.0.0.1:1234"
+	req.Header.Set("X-Forwarded-For", "203.0.113.1")
+	req.Header.Set("X-Real-IP", "203.0.113.1")
+	assert.Equal(t, "10.0.0.1", h.clientIP(req))
+
+	WithTrustedProxies([]*net.IPNet{{IP: net.IPv4(10, 0, 0, 0).To4(), Mask: net.CIDRMask(8, 32)}})(h)
+	assert.Equal(t, "203.0.113.1", h.clientIP(req))
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"crypto/rand"
+	"io"
+	"time"
+)
+
+// Clock tells the current time.
+type Clock interface {
+	Now() time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// WithClock sets the clock used to issue and check the expiry of the states and tokens.
+func WithClock(c Clock) Option {
+	return func(h *authHandler) {
+		h.clock = c
+	}
+}
+
+// WithRandReader sets the source of the randomness used to generate the states, nonces and tokens.
+func WithRandReader(r io.Reader) Option {
+	return func(h *authHandler) {
+		h.rand = r
+	}
+}
+
+// now returns the current time of the clock of the handler.
+func (h *authHandler) now() time.Time {
+	if h.clock == nil {
+		return time.Now()
+	}
+	return h.clock.Now()
+}
+
+// randReader returns the source of the randomness of the handler.
+func (h *authHandler) randReader() io.Reader {
+	if h.rand == nil {
+		return rand.Reader
+	}
+	return h.rand
+}
+
+// randomBytes reads n random bytes from the given source.
+func randomBytes(r io.Reader, n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/pipe-cd/pipecd/pkg/config"
+)
+
+type fakeClock struct {
+	now time.Time
+}
+
+func (c fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestAuthHandlerClockAndRand(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{}
+	assert.WithinDuration(t, time.Now(), h.now(), time.Minute)
+	assert.NotNil(t, h.randReader())
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	WithClock(fakeClock{now: now})(h)
+	WithRandReader(bytes.NewReader(bytes.Repeat([]byte{1}, 64)))(h)
+	assert.Equal(t, now, h.now())
+
+	verifier, err := generateVerifier(h.randReader())
+	require.NoError(t, err)
+	assert.Equal(t, "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE", verifier)
+	nonce, err := generateNonce(h.randReader())
+	require.NoError(t, err)
+	assert.Equal(t, "AQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQEBAQE", nonce)
+
+	// The source is exhausted.
+	_, err = generateNonce(h.randReader())
+	assert.Error(t, err)
+}
+
+func TestStaticAdminLoginClock(t *testing.T) {
+	t.Parallel()
+	hash, err := bcrypt.GenerateFromPassword([]byte("password"), bcrypt.MinCost)
+	require.NoError(t, err)
+
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	signer := &recordingSigner{}
+	h := newAuthHandler(
+		signer,
+		nil,
+		"https://pipecd.dev",
+		"state-key",
+		map[string]config.ControlPlaneProject{
+			"project": {
+				ID:          "project",
+				StaticAdmin: config.ProjectStaticUser{Username: "admin", PasswordHash: string(hash)},
+			},
+		},
+		nil,
+		fakeProjectGetter{},
+		true,
+		zap.NewNop(),
+		WithClock(fakeClock{now: now}),
+	)
+
+	form := url.Values{projectFormKey: {"project"}, usernameFormKey: {"admin"}, passwordFormKey: {"password"}}
+	req := httptest.NewRequest(http.MethodPost, staticLoginPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	h.handleStaticAdminLogin(httptest.NewRecorder(), req)
+
+	require.NotNil(t, signer.signed)
+	assert.Equal(t, now, signer.signed.IssuedAt.Time)
+	assert.Equal(t, now, signer.signed.NotBefore.Time)
+	assert.Equal(t, now.Add(defaultTokenTTL), signer.signed.ExpiresAt.Time)
+}
@@ -0,0 +1,204 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+// cookieFactory makes the cookies set by the auth handler with the configured security attributes,
+// so that the cookie policy is decided in one place.
+type cookieFactory struct {
+	// secure is the Secure attribute of the cookies. It is forced on for SameSite=None.
+	secure bool
+	// sameSite is the SameSite attribute of the token and state cookies.
+	// Zero means using the default of each cookie.
+	sameSite http.SameSite
+	// domain and path are the Domain and Path attributes of the token and state cookies.
+	// Empty means the host-only cookie and the root path respectively.
+	domain string
+	path   string
+	// namePrefix is prepended to the names of the token and state cookies.
+	namePrefix string
+}
+
+// tokenName returns the name of the token cookie with the configured prefix.
+func (f cookieFactory) tokenName() string {
+	return TokenCookieName(f.namePrefix)
+}
+
+// stateName returns the name of the state cookie with the configured prefix.
+func (f cookieFactory) stateName() string {
+	return f.namePrefix + stateCookieKey
+}
+
+// token returns the cookie holding the signed token of the session.
+func (f cookieFactory) token(value string) *http.Cookie {
+	return f.scope(f.session(jwt.SignedTokenKey, value, defaultTokenCookieMaxAge, f.secure, http.SameSiteStrictMode))
+}
+
+// secureToken returns the token cookie which is Secure regardless of the configuration,
+// set by the callbacks from the identity providers.
+func (f cookieFactory) secureToken(value string) *http.Cookie {
+	return f.scope(f.session(jwt.SignedTokenKey, value, defaultTokenCookieMaxAge, true, http.SameSiteStrictMode))
+}
+
+func (f cookieFactory) expiredToken() *http.Cookie {
+	return f.scope(f.session(jwt.SignedTokenKey, "", -1, f.secure, http.SameSiteStrictMode))
+}
+
+// expiredHostOnlyToken returns the expired token cookie without the Domain and Path attributes
+// to clear the host-only token cookie issued before the domain was configured.
+func (f cookieFactory) expiredHostOnlyToken() *http.Cookie {
+	c := f.session(jwt.SignedTokenKey, "", -1, f.secure, http.SameSiteStrictMode)
+	c.Name = f.namePrefix + c.Name
+	return c
+}
+
+// state returns the cookie holding the state of the OAuth flow.
+// It is Lax by default to be sent on the redirect from the provider.
+func (f cookieFactory) state(value string, ttl time.Duration) *http.Cookie {
+	return f.scope(f.session(stateCookieKey, value, int(ttl.Seconds()), f.secure, http.SameSiteLaxMode))
+}
+
+func (f cookieFactory) expiredState() *http.Cookie {
+	return f.scope(f.session(stateCookieKey, "", -1, f.secure, http.SameSiteLaxMode))
+}
+
+func (f cookieFactory) codeVerifier(value string, ttl time.Duration) *http.Cookie {
+	return f.plain(codeVerifierCookieKey, value, int(ttl.Seconds()), http.SameSiteLaxMode)
+}
+
+func (f cookieFactory) expiredCodeVerifier() *http.Cookie {
+	return f.plain(codeVerifierCookieKey, "", -1, http.SameSiteLaxMode)
+}
+
+func (f cookieFactory) nonce(value string, ttl time.Duration) *http.Cookie {
+	return f.plain(nonceCookieKey, value, int(ttl.Seconds()), http.SameSiteLaxMode)
+}
+
+func (f cookieFactory) expiredNonce() *http.Cookie {
+	return f.plain(nonceCookieKey, "", -1, http.SameSiteLaxMode)
+}
+
+func (f cookieFactory) returnTo(value string, ttl time.Duration) *http.Cookie {
+	return f.plain(returnToCookieKey, value, int(ttl.Seconds()), http.SameSiteLaxMode)
+}
+
+func (f cookieFactory) expiredReturnTo() *http.Cookie {
+	return f.plain(returnToCookieKey, "", -1, http.SameSiteLaxMode)
+}
+
+// idToken returns a cookie holding the ID token used as id_token_hint
+// while logging out, along with the project ID and the name of the selected SSO configuration
+// to find the SSO configuration.
+func (f cookieFactory) idToken(projectID, ssoName, idToken string) *http.Cookie {
+	value := projectID + ":" + idToken
+	if ssoName != "" {
+		value += ":" + hex.EncodeToString([]byte(ssoName))
+	}
+	return f.plain(idTokenCookieKey, value, defaultTokenCookieMaxAge, http.SameSiteStrictMode)
+}
+
+func (f cookieFactory) expiredIDToken() *http.Cookie {
+	return f.plain(idTokenCookieKey, "", -1, http.SameSiteStrictMode)
+}
+
+func (f cookieFactory) refreshToken(value string, ttl time.Duration) *http.Cookie {
+	c := f.plain(refreshTokenCookieKey, value, int(ttl.Seconds()), http.SameSiteStrictMode)
+	c.Path = refreshTokenCookiePath
+	return c
+}
+
+func (f cookieFactory) expiredRefreshToken() *http.Cookie {
+	c := f.plain(refreshTokenCookieKey, "", -1, http.SameSiteStrictMode)
+	c.Path = refreshTokenCookiePath
+	return c
+}
+
+// samlRequest returns the cookie sent along with the cross-site POST to the ACS endpoint.
+// It must be SameSite=None, so it is always Secure.
+func (f cookieFactory) samlRequest(value string, ttl time.Duration) *http.Cookie {
+	c := f.plain(samlRequestCookieKey, value, int(ttl.Seconds()), http.SameSiteNoneMode)
+	c.Path = samlACSPath
+	c.Secure = true
+	return c
+}
+
+func (f cookieFactory) expiredSAMLRequest() *http.Cookie {
+	c := f.plain(samlRequestCookieKey, "", -1, http.SameSiteNoneMode)
+	c.Path = samlACSPath
+	c.Secure = true
+	return c
+}
+
+// loginError returns the cookie telling the web console the error of the login.
+// It is readable by the scripts to show the message.
+func (f cookieFactory) loginError(value string) *http.Cookie {
+	c := f.plain(errorCookieKey, value, defaultErrorCookieMaxAge, http.SameSiteStrictMode)
+	c.HttpOnly = false
+	return c
+}
+
+// session returns the token or state cookie with the configured SameSite attribute.
+// The Secure attribute is forced on for SameSite=None since browsers reject such cookies otherwise.
+func (f cookieFactory) session(name, value string, maxAge int, secure bool, defaultSameSite http.SameSite) *http.Cookie {
+	sameSite := f.sameSite
+	if sameSite == 0 {
+		sameSite = defaultSameSite
+	}
+	if sameSite == http.SameSiteNoneMode {
+		secure = true
+	}
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		MaxAge:   maxAge,
+		Path:     rootPath,
+		Secure:   secure,
+		HttpOnly: true,
+		SameSite: sameSite,
+	}
+}
+
+// scope applies the configured name prefix, Domain and Path attributes to the given cookie.
+func (f cookieFactory) scope(c *http.Cookie) *http.Cookie {
+	c.Name = f.namePrefix + c.Name
+	if f.domain != "" {
+		c.Domain = f.domain
+	}
+	if f.path != "" {
+		c.Path = f.path
+	}
+	return c
+}
+
+// plain returns the HttpOnly cookie of the root path which is not affected by the configured
+// SameSite, Domain, Path and name prefix.
+func (f cookieFactory) plain(name, value string, maxAge int, sameSite http.SameSite) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		MaxAge:   maxAge,
+		Path:     rootPath,
+		Secure:   f.secure,
+		HttpOnly: true,
+		SameSite: sameSite,
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+func TestCookieFactorySameSite(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name             string
+		secure           bool
+		sameSite         http.SameSite
+		expectedSecure   bool
+		expectedSameSite http.SameSite
+	}{
+		{
+			name:             "default",
+			secure:           false,
+			expectedSecure:   false,
+			expectedSameSite: http.SameSiteStrictMode,
+		},
+		{
+			name:             "lax",
+			secure:           true,
+			sameSite:         http.SameSiteLaxMode,
+			expectedSecure:   true,
+			expectedSameSite: http.SameSiteLaxMode,
+		},
+		{
+			name:             "none forces secure",
+			secure:           false,
+			sameSite:         http.SameSiteNoneMode,
+			expectedSecure:   true,
+			expectedSameSite: http.SameSiteNoneMode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			f := cookieFactory{secure: tt.secure, sameSite: tt.sameSite}
+			for _, c := range []*http.Cookie{
+				f.token("token"),
+				f.expiredToken(),
+				f.expiredHostOnlyToken(),
+			} {
+				assert.Equal(t, tt.expectedSecure, c.Secure)
+				assert.Equal(t, tt.expectedSameSite, c.SameSite)
+			}
+		})
+	}
+
+	// The state cookie keeps Lax by default to be sent on the redirect from the provider.
+	f := cookieFactory{secure: true}
+	assert.Equal(t, http.SameSiteLaxMode, f.state("state", defaultStateTTL).SameSite)
+	assert.Equal(t, http.SameSiteLaxMode, f.expiredState().SameSite)
+	assert.True(t, cookieFactory{sameSite: http.SameSiteNoneMode}.expiredState().Secure)
+
+	// The token cookie set by the callbacks is always Secure.
+	assert.True(t, cookieFactory{}.secureToken("token").Secure)
+}
+
+func TestCookieFactoryScope(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{}
+	c := h.cookies.token("token")
+	assert.Empty(t, c.Domain)
+	assert.Equal(t, rootPath, c.Path)
+
+	WithCookieDomain(".Example.com")(h)
+	WithCookiePath("/pipecd")(h)
+	for _, c := range []*http.Cookie{
+		h.cookies.token("token"),
+		h.cookies.expiredToken(),
+		h.cookies.state("state", defaultStateTTL),
+		h.cookies.expiredState(),
+	} {
+		assert.Equal(t, "example.com", c.Domain)
+		assert.Equal(t, "/pipecd", c.Path)
+	}
+	c = h.cookies.expiredHostOnlyToken()
+	assert.Empty(t, c.Domain)
+	assert.Equal(t, rootPath, c.Path)
+
+	WithCookieNamePrefix("__Secure-tenant-")(h)
+	assert.Equal(t, "__Secure-tenant-"+jwt.SignedTokenKey, h.cookies.token("token").Name)
+	assert.Equal(t, "__Secure-tenant-"+jwt.SignedTokenKey, h.cookies.expiredHostOnlyToken().Name)
+	assert.Equal(t, "__Secure-tenant-"+stateCookieKey, h.cookies.expiredState().Name)
+	assert.Equal(t, "__Secure-tenant-"+stateCookieKey, h.cookies.stateName())
+	assert.Equal(t, "__Secure-tenant-"+jwt.SignedTokenKey, h.cookies.tokenName())
+	assert.Equal(t, "__Secure-tenant-"+jwt.SignedTokenKey, TokenCookieName("__Secure-tenant-"))
+}
+
+func TestCookieFactoryFlowCookies(t *testing.T) {
+	t.Parallel()
+	// The cookies of the login flow are not affected by the attributes of the token and state cookies.
+	f := cookieFactory{
+		secure:     true,
+		sameSite:   http.SameSiteNoneMode,
+		domain:     "example.com",
+		path:       "/pipecd",
+		namePrefix: "tenant-",
+	}
+	tests := []struct {
+		cookie           *http.Cookie
+		expectedName     string
+		expectedPath     string
+		expectedSameSite http.SameSite
+		expectedMaxAge   int
+	}{
+		{f.codeVerifier("verifier", time.Minute), codeVerifierCookieKey, rootPath, http.SameSiteLaxMode, 60},
+		{f.expiredCodeVerifier(), codeVerifierCookieKey, rootPath, http.SameSiteLaxMode, -1},
+		{f.nonce("nonce", time.Minute), nonceCookieKey, rootPath, http.SameSiteLaxMode, 60},
+		{f.expiredNonce(), nonceCookieKey, rootPath, http.SameSiteLaxMode, -1},
+		{f.returnTo("/", time.Minute), returnToCookieKey, rootPath, http.SameSiteLaxMode, 60},
+		{f.expiredReturnTo(), returnToCookieKey, rootPath, http.SameSiteLaxMode, -1},
+		{f.idToken("project", "", "id-token"), idTokenCookieKey, rootPath, http.SameSiteStrictMode, defaultTokenCookieMaxAge},
+		{f.expiredIDToken(), idTokenCookieKey, rootPath, http.SameSiteStrictMode, -1},
+		{f.refreshToken("refresh", time.Hour), refreshTokenCookieKey, refreshTokenCookiePath, http.SameSiteStrictMode, 3600},
+		{f.expiredRefreshToken(), refreshTokenCookieKey, refreshTokenCookiePath, http.SameSiteStrictMode, -1},
+		{f.samlRequest("request", time.Minute), samlRequestCookieKey, samlACSPath, http.SameSiteNoneMode, 60},
+		{f.expiredSAMLRequest(), samlRequestCookieKey, samlACSPath, http.SameSiteNoneMode, -1},
+	}
+	for _, tt := range tests {
+		c := tt.cookie
+		assert.Equal(t, tt.expectedName, c.Name)
+		assert.Equal(t, tt.expectedPath, c.Path, c.Name)
+		assert.Equal(t, tt.expectedSameSite, c.SameSite, c.Name)
+		assert.Equal(t, tt.expectedMaxAge, c.MaxAge, c.Name)
+		assert.Empty(t, c.Domain, c.Name)
+		assert.True(t, c.Secure, c.Name)
+		assert.True(t, c.HttpOnly, c.Name)
+	}
+
+	// The SAML request cookie is SameSite=None, so it is always Secure.
+	assert.True(t, cookieFactory{}.samlRequest("request", time.Minute).Secure)
+
+	// The login error is read by the web console.
+	c := cookieFactory{}.loginError("message")
+	assert.Equal(t, errorCookieKey, c.Name)
+	assert.False(t, c.HttpOnly)
+	assert.False(t, c.Secure)
+	assert.Equal(t, defaultErrorCookieMaxAge, c.MaxAge)
+}
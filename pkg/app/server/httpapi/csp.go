@@ -0,0 +1,53 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// defaultContentSecurityPolicy is the policy of the HTML pages responded by the auth endpoints.
+// Those pages have no script, so any script including the inline ones is disallowed.
+// The form-action directive is not set since the login form is redirected to the identity providers.
+const defaultContentSecurityPolicy = "default-src 'none'; style-src 'self'; img-src 'self'; base-uri 'none'; frame-ancestors 'none'"
+
+// WithContentSecurityPolicy sets the Content-Security-Policy header of the responses of the auth endpoints.
+// Empty means the default policy disallowing any script.
+// The policy should be checked by ValidateContentSecurityPolicy in advance.
+func WithContentSecurityPolicy(policy string) Option {
+	return func(h *authHandler) {
+		h.contentSecurityPolicy = strings.TrimSpace(policy)
+	}
+}
+
+// ValidateContentSecurityPolicy checks whether the given policy can be used as the header value.
+// An empty policy is valid and means the default policy.
+func ValidateContentSecurityPolicy(policy string) error {
+	if strings.ContainsAny(policy, "\r\n") {
+		return fmt.Errorf("invalid content security policy, must not contain line breaks")
+	}
+	return nil
+}
+
+// setContentSecurityPolicy sets the Content-Security-Policy header to the response.
+func (h *authHandler) setContentSecurityPolicy(w http.ResponseWriter) {
+	policy := h.contentSecurityPolicy
+	if policy == "" {
+		policy = defaultContentSecurityPolicy
+	}
+	w.Header().Set("Content-Security-Policy", policy)
+}
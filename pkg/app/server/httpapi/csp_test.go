@@ -0,0 +1,66 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestContentSecurityPolicy(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected string
+	}{
+		{
+			name:     "default",
+			expected: defaultContentSecurityPolicy,
+		},
+		{
+			name:     "custom",
+			opts:     []Option{WithContentSecurityPolicy(" default-src 'self' ")},
+			expected: "default-src 'self'",
+		},
+		{
+			name:     "empty",
+			opts:     []Option{WithContentSecurityPolicy("")},
+			expected: defaultContentSecurityPolicy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newAuthHandler(nil, nil, "https://pipecd.example.com", "state-key", nil, nil, nil, true, zap.NewNop(), tt.opts...)
+			rec := httptest.NewRecorder()
+			h.handleError(rec, httptest.NewRequest(http.MethodGet, callbackPath, nil), errCodeStateInvalid, "Unauthorized", nil)
+
+			assert.Equal(t, http.StatusSeeOther, rec.Code)
+			assert.Equal(t, tt.expected, rec.Header().Get("Content-Security-Policy"))
+		})
+	}
+}
+
+func TestValidateContentSecurityPolicy(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, ValidateContentSecurityPolicy(""))
+	assert.NoError(t, ValidateContentSecurityPolicy("default-src 'self'; frame-ancestors 'none'"))
+	assert.Error(t, ValidateContentSecurityPolicy("default-src 'self'\r\nSet-Cookie: a=b"))
+}
@@ -0,0 +1,398 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/cache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oidc"
+)
+
+const (
+	deviceCodeGrantType   = "urn:ietf:params:oauth:grant-type:device_code"
+	deviceCodeKeyPrefix   = "device-code:"
+	deviceCodeSize        = 32
+	grantTypeFormKey      = "grant_type"
+	deviceCodeFormKey     = "device_code"
+	defaultDeviceInterval = 5 * time.Second
+	// defaultDeviceCodeTTL is used when the provider does not tell the expiry of its device code.
+	defaultDeviceCodeTTL = 10 * time.Minute
+)
+
+// The error codes of the device access token response defined in RFC 8628 section 3.5.
+const (
+	deviceErrInvalidRequest       = "invalid_request"
+	deviceErrInvalidGrant         = "invalid_grant"
+	deviceErrUnsupportedGrantType = "unsupported_grant_type"
+	deviceErrAuthorizationPending = "authorization_pending"
+	deviceErrSlowDown             = "slow_down"
+	deviceErrAccessDenied         = "access_denied"
+	deviceErrExpiredToken         = "expired_token"
+)
+
+// deviceSession is the device authorization in progress stored until the CLI receives its token.
+type deviceSession struct {
+	ProjectID string `json:"projectId"`
+	SSOName   string `json:"ssoName,omitempty"`
+	// ProviderDeviceCode is the device code issued by the provider, which is never sent to the CLI.
+	ProviderDeviceCode string        `json:"providerDeviceCode"`
+	Interval           time.Duration `json:"interval"`
+	ExpiresAt          time.Time     `json:"expiresAt"`
+	LastPolledAt       time.Time     `json:"lastPolledAt,omitempty"`
+}
+
+// deviceAuthorizationResponse is the response of the device authorization request of RFC 8628 section 3.2.
+type deviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+// deviceTokenResponse is the successful response of the device access token request.
+type deviceTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int64  `json:"expires_in"`
+}
+
+// WithDeviceAuthorization enables the device authorization grant of the OIDC providers for the CLI,
+// whose sessions in progress are stored in the given cache. The cache should expire them after
+// the lifetime of the device codes of the providers, which is usually a few minutes.
+func WithDeviceAuthorization(c cache.Cache) Option {
+	return func(h *authHandler) {
+		h.deviceSessions = c
+	}
+}
+
+// handleDeviceAuthorize starts the device authorization grant with the OIDC provider of the project.
+// The returned device code is opaque and only known by the control plane, while the user code
+// and the verification URI are shown to the user to approve the login on another device.
+func (h *authHandler) handleDeviceAuthorize(w http.ResponseWriter, r *http.Request) {
+	if !h.deviceRequest(w, r) {
+		return
+	}
+	projectID := r.FormValue(projectFormKey)
+	if projectID == "" {
+		h.handleDeviceError(w, deviceErrInvalidRequest, "Missing project id", nil)
+		return
+	}
+	ssoName := r.FormValue(ssoFormKey)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	proj, sso, err := h.deviceSSO(ctx, projectID, ssoName)
+	if err != nil {
+		h.handleDeviceError(w, deviceErrInvalidRequest, "Invalid SSO configuration", err)
+		return
+	}
+	var opts []oauth2.AuthCodeOption
+	if h.grants != nil {
+		opts = sso.OfflineAccessOptions()
+	}
+	da, err := oidc.StartDeviceAuthorization(h.oauthContext(ctx), sso.Oidc, proj, opts...)
+	if err != nil {
+		h.handleDeviceError(w, deviceErrInvalidRequest, "Unable to start device authorization", err)
+		return
+	}
+
+	now := h.now()
+	session := &deviceSession{
+		ProjectID:          proj.Id,
+		SSOName:            ssoName,
+		ProviderDeviceCode: da.DeviceCode,
+		Interval:           time.Duration(da.Interval) * time.Second,
+		ExpiresAt:          da.Expiry,
+	}
+	if session.Interval <= 0 {
+		session.Interval = defaultDeviceInterval
+	}
+	if session.ExpiresAt.IsZero() {
+		session.ExpiresAt = now.Add(defaultDeviceCodeTTL)
+	}
+	b, err := randomBytes(h.randReader(), deviceCodeSize)
+	if err != nil {
+		h.handleDeviceServerError(w, err)
+		return
+	}
+	code := base64.RawURLEncoding.EncodeToString(b)
+	if err := h.putDeviceSession(code, session); err != nil {
+		h.handleDeviceServerError(w, err)
+		return
+	}
+
+	writeDeviceJSON(w, http.StatusOK, &deviceAuthorizationResponse{
+		DeviceCode:              code,
+		UserCode:                da.UserCode,
+		VerificationURI:         da.VerificationURI,
+		VerificationURIComplete: da.VerificationURIComplete,
+		ExpiresIn:               int64(session.ExpiresAt.Sub(now).Seconds()),
+		Interval:                int64(session.Interval.Seconds()),
+	})
+}
+
+// handleDeviceToken is polled by the CLI with the device code until the user approves the login,
+// then the PipeCD token of the user is returned in the same way as the login in the browser.
+// The polling interval is enforced by the control plane as well, see RFC 8628 section 3.5.
+func (h *authHandler) handleDeviceToken(w http.ResponseWriter, r *http.Request) {
+	if !h.deviceRequest(w, r) {
+		return
+	}
+	if r.FormValue(grantTypeFormKey) != deviceCodeGrantType {
+		h.handleDeviceError(w, deviceErrUnsupportedGrantType, "Unsupported grant type", nil)
+		return
+	}
+	code := r.FormValue(deviceCodeFormKey)
+	if code == "" {
+		h.handleDeviceError(w, deviceErrInvalidRequest, "Missing device code", nil)
+		return
+	}
+	session, err := h.getDeviceSession(code)
+	if errors.Is(err, cache.ErrNotFound) {
+		h.handleDeviceError(w, deviceErrInvalidGrant, "Unknown device code", nil)
+		return
+	}
+	if err != nil {
+		h.handleDeviceServerError(w, err)
+		return
+	}
+
+	now := h.now()
+	if !now.Before(session.ExpiresAt) {
+		h.deleteDeviceSession(code)
+		h.handleDeviceError(w, deviceErrExpiredToken, "Device code expired", nil)
+		return
+	}
+	if !session.LastPolledAt.IsZero() && now.Sub(session.LastPolledAt) < session.Interval {
+		h.slowDownDevice(w, code, session)
+		return
+	}
+	session.LastPolledAt = now
+	if err := h.putDeviceSession(code, session); err != nil {
+		h.handleDeviceServerError(w, err)
+		return
+	}
+
+	event := h.newLoginEvent(r)
+	event.ProjectID = session.ProjectID
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	proj, sso, err := h.deviceSSO(ctx, session.ProjectID, session.SSOName)
+	if err != nil {
+		h.deleteDeviceSession(code)
+		h.handleDeviceLoginError(w, r, event, deviceErrAccessDenied, "Invalid SSO configuration", err)
+		return
+	}
+	event.Provider = sso.Provider.String()
+
+	user, token, err := h.resolveUser(ctx, &UserRequest{
+		SSO:        sso,
+		Project:    proj,
+		DeviceCode: session.ProviderDeviceCode,
+		Logger:     h.logger,
+	})
+	switch {
+	case errors.Is(err, oidc.ErrAuthorizationPending):
+		h.handleDeviceError(w, deviceErrAuthorizationPending, "Authorization pending", nil)
+		return
+	case errors.Is(err, oidc.ErrSlowDown):
+		h.slowDownDevice(w, code, session)
+		return
+	case errors.Is(err, oidc.ErrDeviceCodeExpired):
+		h.deleteDeviceSession(code)
+		h.handleDeviceLoginError(w, r, event, deviceErrExpiredToken, "Device code expired", err)
+		return
+	case errors.Is(err, oidc.ErrAccessDenied):
+		h.deleteDeviceSession(code)
+		h.handleDeviceLoginError(w, r, event, deviceErrAccessDenied, "Access denied", err)
+		return
+	case err != nil:
+		h.deleteDeviceSession(code)
+		h.handleDeviceLoginError(w, r, event, deviceErrAccessDenied, "Unable to find user", err)
+		return
+	}
+	// The device code is used only once whatever the result of the login is.
+	h.deleteDeviceSession(code)
+
+	identities, err := h.linkIdentity(proj.Id, sso.Provider, user)
+	if err != nil {
+		h.handleDeviceServerError(w, err)
+		return
+	}
+	event.Username = user.Username
+	if err := ensureRole(proj, user); err != nil {
+		h.handleDeviceLoginError(w, r, event, deviceErrAccessDenied, "no role assigned for your account", err)
+		return
+	}
+	tokenTTL := h.loginTokenTTL(sessionTokenTTL(sso, proj, user.Role))
+	user.AvatarUrl = h.resolveAvatarURL(user)
+
+	claims := jwt.NewClaimsAt(
+		h.now(),
+		user.Username,
+		user.AvatarUrl,
+		tokenTTL,
+		*user.Role,
+		user.Groups...,
+	)
+	claims.Identities = identities
+	claims.Provider = event.Provider
+	signedToken, err := h.signer.Sign(claims)
+	if err != nil {
+		h.handleDeviceServerError(w, err)
+		return
+	}
+	if err := h.registerSession(claims); err != nil {
+		h.handleDeviceServerError(w, err)
+		return
+	}
+	if err := h.keepGrant(session.SSOName, claims, token); err != nil {
+		h.handleDeviceServerError(w, err)
+		return
+	}
+
+	h.logger.Info("user logged in by device authorization",
+		zap.String("user", user.Username),
+		zap.String("project-id", proj.Id),
+		zap.String("project-role", user.Role.String()),
+	)
+	event.Success = true
+	h.recordLogin(r, event)
+	writeDeviceJSON(w, http.StatusOK, &deviceTokenResponse{
+		AccessToken: signedToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(tokenTTL.Seconds()),
+	})
+}
+
+// deviceRequest checks the common requirements of the device authorization endpoints.
+// False is returned after responding the error.
+func (h *authHandler) deviceRequest(w http.ResponseWriter, r *http.Request) bool {
+	setNoCacheHeaders(w)
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return false
+	}
+	if h.deviceSessions == nil {
+		http.Error(w, "Device authorization is not enabled", http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
+// deviceSSO returns the project and its decrypted OIDC configuration of the given name.
+func (h *authHandler) deviceSSO(ctx context.Context, projectID, ssoName string) (*model.Project, *model.ProjectSSOConfig, error) {
+	proj, err := h.projectGetter.Get(ctx, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to find project %s: %w", projectID, err)
+	}
+	if proj.UserGroups == nil {
+		return nil, nil, fmt.Errorf("missing user group configuration")
+	}
+	sso, shared, err := h.findSSOConfigByName(proj, ssoName)
+	if err != nil {
+		return nil, nil, err
+	}
+	if sso.Provider != model.ProjectSSOConfig_OIDC {
+		return nil, nil, fmt.Errorf("device authorization is not supported by %s", sso.Provider)
+	}
+	if !shared {
+		if sso, err = h.decryptSSO(proj.Id, sso); err != nil {
+			return nil, nil, err
+		}
+	}
+	return proj, sso, nil
+}
+
+// slowDownDevice increases the polling interval of the device authorization by 5 seconds
+// as required by RFC 8628 section 3.5.
+func (h *authHandler) slowDownDevice(w http.ResponseWriter, code string, session *deviceSession) {
+	session.Interval += defaultDeviceInterval
+	if err := h.putDeviceSession(code, session); err != nil {
+		h.handleDeviceServerError(w, err)
+		return
+	}
+	h.handleDeviceError(w, deviceErrSlowDown, "Polling too frequently", nil)
+}
+
+func (h *authHandler) getDeviceSession(code string) (*deviceSession, error) {
+	var s deviceSession
+	if err := getCachedJSON(h.deviceSessions, deviceCodeKeyPrefix+code, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (h *authHandler) putDeviceSession(code string, s *deviceSession) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return h.deviceSessions.Put(deviceCodeKeyPrefix+code, data)
+}
+
+func (h *authHandler) deleteDeviceSession(code string) {
+	if err := h.deviceSessions.Delete(deviceCodeKeyPrefix + code); err != nil && !errors.Is(err, cache.ErrNotFound) {
+		h.logger.Warn("auth-handler: failed to delete device code", zap.Error(err))
+	}
+}
+
+// handleDeviceLoginError records the failed login event and then responds the error.
+func (h *authHandler) handleDeviceLoginError(w http.ResponseWriter, r *http.Request, event *LoginEvent, code, description string, err error) {
+	event.Success = false
+	event.ErrorCode = code
+	event.FailureReason = description
+	h.recordLogin(r, event)
+	h.handleDeviceError(w, code, description, err)
+}
+
+// handleDeviceError responds the error in the format of RFC 6749 section 5.2.
+func (h *authHandler) handleDeviceError(w http.ResponseWriter, code, description string, err error) {
+	if err != nil {
+		h.logger.Warn(fmt.Sprintf("auth-handler: %s", description), zap.Error(err))
+	}
+	writeDeviceJSON(w, http.StatusBadRequest, map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
+
+func (h *authHandler) handleDeviceServerError(w http.ResponseWriter, err error) {
+	h.logger.Error("auth-handler: device authorization failed", zap.Error(err))
+	http.Error(w, "Internal error", http.StatusInternalServerError)
+}
+
+func writeDeviceJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
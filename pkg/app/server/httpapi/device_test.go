@@ -0,0 +1,227 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oidc"
+)
+
+// newDeviceProvider returns the OIDC provider supporting the device authorization grant.
+func newDeviceProvider(t *testing.T) *httptest.Server {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"device_authorization_endpoint":%q,"jwks_uri":%q}`,
+				server.URL, server.URL+"/auth", server.URL+"/token", server.URL+"/device", server.URL+"/keys")
+		case "/device":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"device_code":"provider-device-code","user_code":"ABCD-EFGH","verification_uri":%q,"expires_in":600,"interval":5}`, server.URL+"/activate")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDeviceAuthorization(t *testing.T) {
+	t.Parallel()
+	provider := newDeviceProvider(t)
+	now := time.Now()
+	tokens := fakeTokens{}
+
+	var result error = oidc.ErrAuthorizationPending
+	resolver := UserResolverFunc(func(_ context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+		assert.Equal(t, "provider-device-code", req.DeviceCode)
+		if result != nil {
+			return nil, nil, result
+		}
+		return &model.User{Username: "alice", Role: &model.Role{ProjectId: "project", ProjectRbacRoles: []string{"Admin"}}}, nil, nil
+	})
+	h := &authHandler{
+		signer: tokens,
+		sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+			"oidc": {Provider: model.ProjectSSOConfig_OIDC, Oidc: &model.ProjectSSOConfig_Oidc{ClientId: "client-id", Issuer: provider.URL}},
+		},
+		projectGetter: fakeProjectGetter{
+			"project": {Id: "project", SharedSsoName: "oidc", UserGroups: []*model.ProjectUserGroup{}},
+		},
+		clock:  fakeClock{now: now},
+		logger: zap.NewNop(),
+	}
+	WithDeviceAuthorization(memorycache.NewCache())(h)
+	WithUserResolver(model.ProjectSSOConfig_OIDC, resolver)(h)
+
+	rec := postDeviceForm(h.handleDeviceAuthorize, url.Values{projectFormKey: {"project"}})
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	var authz deviceAuthorizationResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&authz))
+	assert.NotEmpty(t, authz.DeviceCode)
+	assert.NotEqual(t, "provider-device-code", authz.DeviceCode)
+	assert.Equal(t, "ABCD-EFGH", authz.UserCode)
+	assert.Equal(t, provider.URL+"/activate", authz.VerificationURI)
+	assert.Equal(t, int64(5), authz.Interval)
+
+	poll := func(at time.Time) (*httptest.ResponseRecorder, string) {
+		h.clock = fakeClock{now: at}
+		rec := postDeviceForm(h.handleDeviceToken, url.Values{
+			grantTypeFormKey:  {deviceCodeGrantType},
+			deviceCodeFormKey: {authz.DeviceCode},
+		})
+		var resp struct {
+			Error string `json:"error"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		return rec, resp.Error
+	}
+
+	rec, code := poll(now)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, deviceErrAuthorizationPending, code)
+
+	// The poll within the interval slows the CLI down by 5 more seconds.
+	_, code = poll(now.Add(2 * time.Second))
+	assert.Equal(t, deviceErrSlowDown, code)
+	_, code = poll(now.Add(7 * time.Second))
+	assert.Equal(t, deviceErrSlowDown, code)
+	_, code = poll(now.Add(20 * time.Second))
+	assert.Equal(t, deviceErrAuthorizationPending, code)
+
+	result = nil
+	rec, _ = poll(now.Add(40 * time.Second))
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+	var token deviceTokenResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&token))
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, int64(defaultTokenTTL.Seconds()), token.ExpiresIn)
+	claims, err := tokens.Verify(token.AccessToken)
+	require.NoError(t, err)
+	assert.Equal(t, "alice", claims.Subject)
+	assert.Equal(t, "OIDC", claims.Provider)
+
+	// The device code cannot be used again.
+	_, code = poll(now.Add(60 * time.Second))
+	assert.Equal(t, deviceErrInvalidGrant, code)
+}
+
+func TestDeviceAuthorizationErrors(t *testing.T) {
+	t.Parallel()
+	provider := newDeviceProvider(t)
+	now := time.Now()
+	newHandler := func(result error) *authHandler {
+		h := &authHandler{
+			signer: fakeTokens{},
+			sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+				"oidc":   {Provider: model.ProjectSSOConfig_OIDC, Oidc: &model.ProjectSSOConfig_Oidc{ClientId: "client-id", Issuer: provider.URL}},
+				"github": {Provider: model.ProjectSSOConfig_GITHUB, Github: &model.ProjectSSOConfig_GitHub{ClientId: "client-id"}},
+			},
+			projectGetter: fakeProjectGetter{
+				"project": {Id: "project", SharedSsoName: "oidc", AdditionalSharedSsoNames: []string{"github"}, UserGroups: []*model.ProjectUserGroup{}},
+			},
+			clock:  fakeClock{now: now},
+			logger: zap.NewNop(),
+		}
+		WithDeviceAuthorization(memorycache.NewCache())(h)
+		WithUserResolver(model.ProjectSSOConfig_OIDC, UserResolverFunc(func(context.Context, *UserRequest) (*model.User, *oauth2.Token, error) {
+			if result != nil {
+				return nil, nil, result
+			}
+			// The project has no default role to assign.
+			return &model.User{Username: "alice"}, nil, nil
+		}))(h)
+		return h
+	}
+	start := func(h *authHandler) string {
+		rec := postDeviceForm(h.handleDeviceAuthorize, url.Values{projectFormKey: {"project"}})
+		require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+		var authz deviceAuthorizationResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&authz))
+		return authz.DeviceCode
+	}
+	errorCode := func(rec *httptest.ResponseRecorder) string {
+		var resp struct {
+			Error string `json:"error"`
+		}
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+		return resp.Error
+	}
+	token := func(h *authHandler, grantType, code string) *httptest.ResponseRecorder {
+		return postDeviceForm(h.handleDeviceToken, url.Values{grantTypeFormKey: {grantType}, deviceCodeFormKey: {code}})
+	}
+
+	testcases := []struct {
+		name     string
+		result   error
+		expected string
+	}{
+		{name: "denied", result: fmt.Errorf("%w: denied", oidc.ErrAccessDenied), expected: deviceErrAccessDenied},
+		{name: "expired", result: fmt.Errorf("%w: expired", oidc.ErrDeviceCodeExpired), expected: deviceErrExpiredToken},
+		{name: "no role", expected: deviceErrAccessDenied},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := newHandler(tc.result)
+			code := start(h)
+			assert.Equal(t, tc.expected, errorCode(token(h, deviceCodeGrantType, code)))
+			// The device code is consumed by the terminal error.
+			assert.Equal(t, deviceErrInvalidGrant, errorCode(token(h, deviceCodeGrantType, code)))
+		})
+	}
+
+	h := newHandler(nil)
+	code := start(h)
+	assert.Equal(t, deviceErrUnsupportedGrantType, errorCode(token(h, "authorization_code", code)))
+	assert.Equal(t, deviceErrInvalidRequest, errorCode(token(h, deviceCodeGrantType, "")))
+
+	// The device code expires as told by the provider.
+	h.clock = fakeClock{now: now.Add(11 * time.Minute)}
+	assert.Equal(t, deviceErrExpiredToken, errorCode(token(h, deviceCodeGrantType, code)))
+
+	// Only the OIDC providers support the device authorization grant.
+	rec := postDeviceForm(h.handleDeviceAuthorize, url.Values{projectFormKey: {"project"}, ssoFormKey: {"github"}})
+	assert.Equal(t, deviceErrInvalidRequest, errorCode(rec))
+
+	// The endpoints are not found unless enabled.
+	rec = postDeviceForm((&authHandler{logger: zap.NewNop()}).handleDeviceToken, url.Values{})
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func postDeviceForm(handler http.HandlerFunc, form url.Values) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, deviceTokenPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
@@ -0,0 +1,166 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"html/template"
+	"mime"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// errorCode is a machine-readable code of an auth error returned to API clients.
+type errorCode string
+
+const (
+	errCodeMethodNotAllowed errorCode = "method_not_allowed"
+	errCodeInvalidRequest   errorCode = "invalid_request"
+	errCodeUnauthorized     errorCode = "unauthorized"
+	errCodeLoginExpired     errorCode = "login_expired"
+	errCodeStateInvalid     errorCode = "state_invalid"
+	errCodeForbidden        errorCode = "forbidden"
+	errCodeProjectNotFound  errorCode = "project_not_found"
+	errCodeInvalidSSOConfig errorCode = "invalid_sso_configuration"
+	errCodeTooManyRequests  errorCode = "too_many_requests"
+	errCodeRequestTooLarge  errorCode = "request_too_large"
+	errCodeUnavailable      errorCode = "temporarily_unavailable"
+	errCodeInternal         errorCode = "internal"
+)
+
+const (
+	jsonContentType = "application/json"
+	formatQueryKey  = "format"
+	formatJSON      = "json"
+
+	// loginErrorPath is the page of the web UI showing the login errors.
+	loginErrorPath = "/login"
+	// loginErrorQueryKey is the query parameter carrying the error code to the web UI.
+	loginErrorQueryKey = "login_error"
+)
+
+// statusCode returns the HTTP status code to respond for the error code.
+func (c errorCode) statusCode() int {
+	switch c {
+	case errCodeMethodNotAllowed:
+		return http.StatusMethodNotAllowed
+	case errCodeInvalidRequest:
+		return http.StatusBadRequest
+	case errCodeUnauthorized, errCodeLoginExpired, errCodeStateInvalid:
+		return http.StatusUnauthorized
+	case errCodeForbidden:
+		return http.StatusForbidden
+	case errCodeProjectNotFound:
+		return http.StatusNotFound
+	case errCodeTooManyRequests:
+		return http.StatusTooManyRequests
+	case errCodeRequestTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case errCodeUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// errorResponse is the JSON body responded to API clients on auth errors.
+type errorResponse struct {
+	Code          errorCode `json:"code"`
+	Message       string    `json:"message"`
+	CorrelationID string    `json:"correlationId"`
+}
+
+// wantsJSON reports whether the client requested a JSON response
+// by the format query parameter or the Accept header.
+// Browsers which accept any type are still responded with the default redirect.
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get(formatQueryKey) == formatJSON {
+		return true
+	}
+	for _, v := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(v))
+		if err == nil && mediaType == jsonContentType {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSONError(w http.ResponseWriter, code errorCode, message, correlationID string) error {
+	w.Header().Set("Content-Type", jsonContentType)
+	w.WriteHeader(code.statusCode())
+	return json.NewEncoder(w).Encode(errorResponse{
+		Code:          code,
+		Message:       message,
+		CorrelationID: correlationID,
+	})
+}
+
+// loginErrorURL returns the address of the web UI page showing the login error of the given code.
+// The page retries the login to the given project unless it is empty.
+func loginErrorURL(code errorCode, projectID string) string {
+	q := url.Values{loginErrorQueryKey: {string(code)}}
+	if projectID != "" {
+		q.Set(projectFormKey, projectID)
+	}
+	return loginErrorPath + "?" + q.Encode()
+}
+
+var loginErrorPage = template.Must(template.New("login-error").Parse(`<!DOCTYPE html>
+<html lang="{{.Locale}}">
+<head>
+<meta charset="utf-8">
+<title>{{.Text.Title}}</title>
+</head>
+<body>
+<p>{{.Message}}</p>
+<p>{{.Text.ErrorCode}}: {{.Code}}, {{.Text.CorrelationID}}: {{.CorrelationID}}</p>
+<p><a href="{{.Location}}">{{.Text.BackToLogin}}</a></p>
+</body>
+</html>
+`))
+
+// writeLoginErrorRedirect redirects the browser to the login error page of the web UI.
+// The body describes the error in the given language for the clients which do not follow the redirect,
+// while the error code stays the same in all languages.
+// The message may contain the user input, so it must only be written through the template escaping it.
+func writeLoginErrorRedirect(w http.ResponseWriter, locale string, code errorCode, projectID, message, correlationID string) error {
+	text, ok := loginErrorCatalog[locale]
+	if !ok {
+		locale, text = defaultLocale, loginErrorCatalog[defaultLocale]
+	}
+	location := loginErrorURL(code, projectID)
+	w.Header().Set("Location", location)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Language", locale)
+	w.Header().Add("Vary", "Accept-Language")
+	w.WriteHeader(http.StatusSeeOther)
+	return loginErrorPage.Execute(w, struct {
+		Locale        string
+		Text          *loginErrorText
+		Code          errorCode
+		Message       string
+		CorrelationID string
+		Location      string
+	}{
+		Locale:        locale,
+		Text:          text,
+		Code:          code,
+		Message:       localizedMessage(locale, code, message),
+		CorrelationID: correlationID,
+		Location:      location,
+	})
+}
@@ -0,0 +1,180 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestWantsJSON(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		url      string
+		accept   string
+		expected bool
+	}{
+		{
+			name:     "browser",
+			url:      callbackPath,
+			accept:   "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8",
+			expected: false,
+		},
+		{
+			name:     "no accept header",
+			url:      callbackPath,
+			expected: false,
+		},
+		{
+			name:     "accept json",
+			url:      callbackPath,
+			accept:   "application/json",
+			expected: true,
+		},
+		{
+			name:     "accept json with parameters",
+			url:      callbackPath,
+			accept:   "text/plain, application/json; charset=utf-8",
+			expected: true,
+		},
+		{
+			name:     "format query",
+			url:      callbackPath + "?format=json",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+			assert.Equal(t, tt.expected, wantsJSON(req))
+		})
+	}
+}
+
+func TestHandleError(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{logger: zap.NewNop()}
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+		req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+		req.Header.Set("Accept", "application/json")
+		rec := httptest.NewRecorder()
+		h.handleError(rec, req, errCodeProjectNotFound, "Unable to find project p", nil)
+
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Equal(t, jsonContentType, rec.Header().Get("Content-Type"))
+		var resp errorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, errCodeProjectNotFound, resp.Code)
+		assert.Equal(t, "Unable to find project p", resp.Message)
+		assert.NotEmpty(t, resp.CorrelationID)
+	})
+
+	t.Run("redirect", func(t *testing.T) {
+		t.Parallel()
+		req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+		rec := httptest.NewRecorder()
+		h.handleError(rec, req, errCodeStateInvalid, "Unauthorized <access>", nil)
+
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "/login?login_error=state_invalid", rec.Header().Get("Location"))
+		cookies := rec.Result().Cookies()
+		require.Len(t, cookies, 1)
+		assert.Equal(t, errorCookieKey, cookies[0].Name)
+
+		// The body is for the clients which do not follow the redirect.
+		body := rec.Body.String()
+		assert.Contains(t, body, "Unauthorized &lt;access&gt;")
+		assert.Contains(t, body, "Error code: state_invalid")
+		assert.Contains(t, body, `href="/login?login_error=state_invalid"`)
+	})
+
+	t.Run("localized redirect", func(t *testing.T) {
+		t.Parallel()
+		req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+		req.Header.Set("Accept-Language", "ja-JP,ja;q=0.9,en;q=0.8")
+		rec := httptest.NewRecorder()
+		h.handleError(rec, req, errCodeStateInvalid, "Unauthorized <access>", nil)
+
+		// The location and the error code stay the same in all languages.
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "/login?login_error=state_invalid", rec.Header().Get("Location"))
+		assert.Equal(t, "ja", rec.Header().Get("Content-Language"))
+
+		body := rec.Body.String()
+		assert.Contains(t, body, `<html lang="ja">`)
+		assert.Contains(t, body, "ログインの状態が不正です。もう一度お試しください。")
+		assert.Contains(t, body, "エラーコード: state_invalid")
+		assert.NotContains(t, body, "Unauthorized")
+	})
+
+	t.Run("unsupported language", func(t *testing.T) {
+		t.Parallel()
+		req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+		req.Header.Set("Accept-Language", "fr-FR")
+		rec := httptest.NewRecorder()
+		h.handleError(rec, req, errCodeStateInvalid, "Unauthorized", nil)
+
+		assert.Equal(t, "en", rec.Header().Get("Content-Language"))
+		assert.Contains(t, rec.Body.String(), "Error code: state_invalid")
+	})
+
+	t.Run("redirect to retry login of project", func(t *testing.T) {
+		t.Parallel()
+		req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+		rec := httptest.NewRecorder()
+		h.handleProjectError(rec, req, "my project", errCodeLoginExpired, "Your login timed out, please try again", nil)
+
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "/login?login_error=login_expired&project=my+project", rec.Header().Get("Location"))
+		assert.Contains(t, rec.Body.String(), `href="/login?login_error=login_expired&amp;project=my&#43;project"`)
+	})
+}
+
+func TestHandleErrorEscapesProjectParam(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		projectGetter: fakeProjectGetter{},
+		logger:        zap.NewNop(),
+	}
+
+	payload := `<script>alert("xss")</script>`
+	form := url.Values{projectFormKey: {payload}}
+	req := httptest.NewRequest(http.MethodPost, loginPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.handleSSOLogin(rec, req)
+
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	body := rec.Body.String()
+	assert.NotContains(t, body, payload)
+	assert.NotContains(t, body, "<script>")
+	assert.Contains(t, body, "Unable to find project &lt;script&gt;alert(&#34;xss&#34;)&lt;/script&gt;")
+}
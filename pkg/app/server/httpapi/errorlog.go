@@ -0,0 +1,111 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+const (
+	redactedSecret = "[REDACTED]"
+	// minSecretSize is the minimum length of the request values redacted from the logs,
+	// so that the short values like "1" do not redact the unrelated parts of the error.
+	minSecretSize = 4
+)
+
+// secretFormKeys are the parameters of the auth requests carrying the secrets.
+var secretFormKeys = []string{
+	authCodeFormKey,
+	stateFormKey,
+	passwordFormKey,
+	"SAMLResponse",
+	"id_token",
+	"access_token",
+}
+
+// secretCookieKeys are the cookies of the auth requests carrying the secrets.
+var secretCookieKeys = []string{
+	jwt.SignedTokenKey,
+	refreshTokenCookieKey,
+	stateCookieKey,
+	codeVerifierCookieKey,
+	nonceCookieKey,
+	idTokenCookieKey,
+	samlRequestCookieKey,
+}
+
+// logError logs the error responded to the request with the fields common to all the auth errors.
+// The internal errors are logged at error level, the others such as the failed logins at warn level.
+// The secrets of the request, e.g. the auth code and the tokens, are redacted from the error.
+func (h *authHandler) logError(r *http.Request, projectID, provider string, code errorCode, responseMessage, correlationID string, err error) {
+	fields := []zap.Field{
+		zap.String("code", string(code)),
+		zap.String("correlation-id", correlationID),
+		zap.String("client-ip", h.clientIP(r)),
+	}
+	if projectID != "" {
+		fields = append(fields, zap.String("project-id", projectID))
+	}
+	if provider != "" {
+		fields = append(fields, zap.String("provider", provider))
+	}
+	if err != nil {
+		fields = append(fields, zap.String("error", redactRequestSecrets(r, h.cookies.namePrefix, err.Error())))
+	}
+
+	msg := fmt.Sprintf("auth-handler: %s", responseMessage)
+	if code == errCodeInternal {
+		h.logger.Error(msg, fields...)
+		return
+	}
+	h.logger.Warn(msg, fields...)
+}
+
+// redactRequestSecrets replaces the secrets sent by the given request in the message.
+// The form is only read if already parsed, since parsing it here may consume the body.
+// The token and state cookies are looked up with the given name prefix as well.
+func redactRequestSecrets(r *http.Request, cookieNamePrefix, msg string) string {
+	var secrets []string
+	for _, k := range secretFormKeys {
+		secrets = append(secrets, r.URL.Query()[k]...)
+		if r.Form != nil {
+			secrets = append(secrets, r.Form[k]...)
+		}
+	}
+	for _, k := range secretCookieKeys {
+		if c, err := r.Cookie(k); err == nil {
+			secrets = append(secrets, c.Value)
+		}
+		if cookieNamePrefix == "" {
+			continue
+		}
+		if c, err := r.Cookie(cookieNamePrefix + k); err == nil {
+			secrets = append(secrets, c.Value)
+		}
+	}
+	for _, s := range secrets {
+		if len(s) < minSecretSize {
+			continue
+		}
+		msg = strings.ReplaceAll(msg, s, redactedSecret)
+	}
+	return msg
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+func TestHandleErrorLogging(t *testing.T) {
+	t.Parallel()
+	core, logs := observer.New(zap.DebugLevel)
+	h := &authHandler{logger: zap.New(core)}
+
+	req := httptest.NewRequest(http.MethodGet, callbackPath+"?project=project&code=secret-code&state=secret-state", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	req.AddCookie(&http.Cookie{Name: jwt.SignedTokenKey, Value: "secret-token"})
+	event := &LoginEvent{ProjectID: "project", Provider: "GITHUB"}
+	err := fmt.Errorf("exchange secret-code with state secret-state failed for token secret-token")
+	h.handleLoginError(httptest.NewRecorder(), req, event, failureReasonUserLookup, errCodeUnauthorized, "Unable to find user", err)
+
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.WarnLevel, entry.Level)
+	assert.Equal(t, "auth-handler: Unable to find user", entry.Message)
+	fields := entry.ContextMap()
+	assert.Equal(t, "unauthorized", fields["code"])
+	assert.Equal(t, "192.0.2.1", fields["client-ip"])
+	assert.Equal(t, "project", fields["project-id"])
+	assert.Equal(t, "GITHUB", fields["provider"])
+	assert.NotEmpty(t, fields["correlation-id"])
+	assert.Equal(t, "exchange [REDACTED] with state [REDACTED] failed for token [REDACTED]", fields["error"])
+
+	// The unknown project and provider are omitted, and the internal errors are logged at error level.
+	h.handleError(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, callbackPath, nil), errCodeInternal, "Internal error", nil)
+	require.Equal(t, 2, logs.Len())
+	entry = logs.All()[1]
+	assert.Equal(t, zapcore.ErrorLevel, entry.Level)
+	fields = entry.ContextMap()
+	assert.NotContains(t, fields, "project-id")
+	assert.NotContains(t, fields, "provider")
+	assert.NotContains(t, fields, "error")
+}
+
+func TestRedactRequestSecrets(t *testing.T) {
+	t.Parallel()
+	req := httptest.NewRequest(http.MethodPost, ldapLoginPath+"?code=abc", nil)
+	req.Form = map[string][]string{passwordFormKey: {"my-password"}}
+	req.AddCookie(&http.Cookie{Name: refreshTokenCookieKey, Value: "refresh-token"})
+	req.AddCookie(&http.Cookie{Name: "tenant-" + jwt.SignedTokenKey, Value: "prefixed-token"})
+
+	// The values shorter than minSecretSize are not redacted.
+	assert.Equal(t, "bind [REDACTED] abc [REDACTED]", redactRequestSecrets(req, "", "bind my-password abc refresh-token"))
+	assert.Equal(t, "token [REDACTED]", redactRequestSecrets(req, "tenant-", "token prefixed-token"))
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/cache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+const grantKeyPrefix = "grant:"
+
+type encryptDecrypter interface {
+	Encrypt(text string) (string, error)
+	Decrypt(encryptedText string) (string, error)
+}
+
+// GrantStore keeps the tokens issued to the users by the OAuth providers at login,
+// so that the users can be looked up at the providers after login.
+// The tokens are encrypted since they allow accessing the providers as the users.
+type GrantStore struct {
+	cache     cache.Cache
+	encrypter encryptDecrypter
+}
+
+// grant is the token issued to the user by the OAuth provider at login.
+type grant struct {
+	// SSOName is the name of the additional shared SSO configuration of the login.
+	// It is empty for the main SSO configuration of the project.
+	SSOName string        `json:"ssoName,omitempty"`
+	Token   *oauth2.Token `json:"token"`
+}
+
+// NewGrantStore returns a store keeping the grants in the given cache encrypted by the given encrypter.
+// The cache must keep the stored items at least as long as the sessions can be extended.
+func NewGrantStore(c cache.Cache, e encryptDecrypter) *GrantStore {
+	return &GrantStore{
+		cache:     c,
+		encrypter: e,
+	}
+}
+
+// WithGrantStore keeps the tokens issued by the OAuth providers at login in the given store,
+// and requests the refresh tokens from the providers, so that the membership checker can look up the users by them.
+func WithGrantStore(s *GrantStore) Option {
+	return func(h *authHandler) {
+		h.grants = s
+	}
+}
+
+// keepGrant stores the token issued at login for the session of the given claims if the grant store is enabled.
+func (h *authHandler) keepGrant(ssoName string, claims *jwt.Claims, token *oauth2.Token) error {
+	if h.grants == nil || token == nil {
+		return nil
+	}
+	return h.grants.put(claims, &grant{SSOName: ssoName, Token: token})
+}
+
+func (s *GrantStore) put(claims *jwt.Claims, g *grant) error {
+	data, err := json.Marshal(g)
+	if err != nil {
+		return err
+	}
+	encrypted, err := s.encrypter.Encrypt(string(data))
+	if err != nil {
+		return err
+	}
+	return s.cache.Put(grantKey(claims), encrypted)
+}
+
+// get returns the grant of the user of the given claims, or nil if the user logged in without keeping it.
+func (s *GrantStore) get(claims *jwt.Claims) (*grant, error) {
+	v, err := s.cache.Get(grantKey(claims))
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var encrypted string
+	switch v := v.(type) {
+	case []byte:
+		encrypted = string(v)
+	case string:
+		encrypted = v
+	default:
+		return nil, fmt.Errorf("unexpected grant data type: %T", v)
+	}
+	data, err := s.encrypter.Decrypt(encrypted)
+	if err != nil {
+		return nil, err
+	}
+	var g grant
+	if err := json.Unmarshal([]byte(data), &g); err != nil {
+		return nil, err
+	}
+	return &g, nil
+}
+
+// grantKey returns the cache key of the grant of the user of the claims.
+// The grant of the latest login replaces the previous one of the same user.
+func grantKey(claims *jwt.Claims) string {
+	return grantKeyPrefix + claims.Role.ProjectId + ":" + claims.Provider + ":" + claims.Subject
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+type fakeEncrypter struct{}
+
+func (fakeEncrypter) Encrypt(text string) (string, error) {
+	return "encrypted-" + text, nil
+}
+
+func (fakeEncrypter) Decrypt(text string) (string, error) {
+	return strings.TrimPrefix(text, "encrypted-"), nil
+}
+
+func TestGrantStore(t *testing.T) {
+	t.Parallel()
+	c := memorycache.NewCache()
+	h := &authHandler{}
+	WithGrantStore(NewGrantStore(c, fakeEncrypter{}))(h)
+
+	claims := jwt.NewClaims("alice", "", time.Hour, model.Role{ProjectId: "project"})
+	claims.Provider = model.ProjectSSOConfig_OIDC.String()
+
+	g, err := h.grants.get(claims)
+	require.NoError(t, err)
+	assert.Nil(t, g)
+
+	token := &oauth2.Token{AccessToken: "access-token", RefreshToken: "refresh-token"}
+	require.NoError(t, h.keepGrant("corp-oidc", claims, token))
+
+	stored, err := c.Get(grantKey(claims))
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stored.(string), "encrypted-"))
+
+	g, err = h.grants.get(claims)
+	require.NoError(t, err)
+	require.NotNil(t, g)
+	assert.Equal(t, "corp-oidc", g.SSOName)
+	assert.Equal(t, "access-token", g.Token.AccessToken)
+	assert.Equal(t, "refresh-token", g.Token.RefreshToken)
+
+	// The grant of another user or project is not found.
+	other := jwt.NewClaims("bob", "", time.Hour, model.Role{ProjectId: "project"})
+	other.Provider = claims.Provider
+	g, err = h.grants.get(other)
+	require.NoError(t, err)
+	assert.Nil(t, g)
+}
+
+func TestKeepGrantWithoutStore(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{}
+	claims := jwt.NewClaims("alice", "", time.Hour, model.Role{ProjectId: "project"})
+	assert.NoError(t, h.keepGrant("", claims, &oauth2.Token{AccessToken: "access-token"}))
+}
@@ -15,11 +15,17 @@
 package httpapi
 
 import (
+	"fmt"
+	"net"
 	"net/http"
+	"net/url"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/NYTimes/gziphandler"
 	"go.uber.org/zap"
+	"golang.org/x/net/publicsuffix"
 
 	"github.com/pipe-cd/pipecd/pkg/app/server/httpapi/httpapimetrics"
 	"github.com/pipe-cd/pipecd/pkg/config"
@@ -27,6 +33,141 @@ import (
 	"github.com/pipe-cd/pipecd/pkg/model"
 )
 
+// Option is a function that configures the handler.
+type Option func(*authHandler)
+
+// WithCookieSameSite sets the SameSite attribute of the token and state cookies.
+// When http.SameSiteNoneMode is given, the Secure attribute of those cookies is
+// forced on regardless of secureCookie, so the control plane must be served over HTTPS.
+func WithCookieSameSite(mode http.SameSite) Option {
+	return func(h *authHandler) {
+		h.cookies.sameSite = mode
+	}
+}
+
+// WithCookieDomain sets the Domain attribute of the token and state cookies
+// so that they are shared with the subdomains of the given domain.
+// The domain should be checked by ValidateCookieDomain in advance.
+func WithCookieDomain(domain string) Option {
+	return func(h *authHandler) {
+		h.cookies.domain = strings.TrimPrefix(strings.ToLower(domain), ".")
+	}
+}
+
+// WithCookiePath sets the Path attribute of the token and state cookies.
+func WithCookiePath(path string) Option {
+	return func(h *authHandler) {
+		h.cookies.path = path
+	}
+}
+
+// ValidateCookieDomain checks whether the given cookie domain can be used for the control plane
+// served at the given address. The domain must be the host of the address or its parent domain,
+// and must not be a public suffix such as "com" or "co.uk" to avoid sharing the cookies with other sites.
+// An empty domain is valid and means the host-only cookie.
+func ValidateCookieDomain(domain, address string) error {
+	if domain == "" {
+		return nil
+	}
+	domain = strings.TrimPrefix(strings.ToLower(domain), ".")
+	u, err := url.Parse(address)
+	if err != nil {
+		return fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "" {
+		return fmt.Errorf("missing host in address %q", address)
+	}
+	if net.ParseIP(host) != nil || net.ParseIP(domain) != nil {
+		return fmt.Errorf("cookie domain cannot be used with IP address")
+	}
+	if host != domain && !strings.HasSuffix(host, "."+domain) {
+		return fmt.Errorf("cookie domain %q does not match the host %q", domain, host)
+	}
+	if suffix, _ := publicsuffix.PublicSuffix(domain); suffix == domain {
+		return fmt.Errorf("cookie domain %q is a public suffix", domain)
+	}
+	return nil
+}
+
+// WithCookieNamePrefix prepends the given prefix to the names of the token and state cookies,
+// so that they do not collide with the ones of the other control planes sharing the cookie domain.
+// The prefix should be checked by ValidateCookieNamePrefix in advance.
+func WithCookieNamePrefix(prefix string) Option {
+	return func(h *authHandler) {
+		h.cookies.namePrefix = prefix
+	}
+}
+
+// ValidateCookieNamePrefix checks whether the given prefix can be prepended to the names of the cookies
+// having the given attributes. The "__Secure-" and "__Host-" prefixes require the Secure attribute,
+// and "__Host-" also requires the host-only cookie of the root path. An empty prefix is valid.
+func ValidateCookieNamePrefix(prefix, domain, path string, secure bool) error {
+	if prefix == "" {
+		return nil
+	}
+	if err := (&http.Cookie{Name: prefix + jwt.SignedTokenKey, Value: "value"}).Valid(); err != nil {
+		return fmt.Errorf("invalid cookie name prefix %q: %w", prefix, err)
+	}
+	if (strings.HasPrefix(prefix, "__Secure-") || strings.HasPrefix(prefix, "__Host-")) && !secure {
+		return fmt.Errorf("cookie name prefix %q requires the secure cookies", prefix)
+	}
+	if strings.HasPrefix(prefix, "__Host-") && (domain != "" || (path != "" && path != "/")) {
+		return fmt.Errorf("cookie name prefix %q can not be used with the cookie domain or path", prefix)
+	}
+	return nil
+}
+
+// TokenCookieName returns the name of the token cookie with the given prefix.
+func TokenCookieName(prefix string) string {
+	return prefix + jwt.SignedTokenKey
+}
+
+// ValidateCookiePath checks whether the given path can be used as the Path attribute of the cookies.
+// An empty path is valid and means the root path.
+func ValidateCookiePath(path string) error {
+	if path == "" {
+		return nil
+	}
+	if !strings.HasPrefix(path, "/") || strings.ContainsAny(path, ";\r\n") {
+		return fmt.Errorf("invalid cookie path %q, must start with /", path)
+	}
+	return nil
+}
+
+// WithStateTTL sets how long the state of the OAuth flow is valid.
+// The login started before that is rejected as expired and needs to be retried.
+func WithStateTTL(ttl time.Duration) Option {
+	return func(h *authHandler) {
+		h.stateTTL = ttl
+	}
+}
+
+// WithMaxCallbackBodySize limits the size of the body of the callback requests in bytes.
+// The larger requests are rejected with 413 Request Entity Too Large.
+func WithMaxCallbackBodySize(n int64) Option {
+	return func(h *authHandler) {
+		h.maxCallbackBodySize = n
+	}
+}
+
+// ParseSameSite converts the given string (lax, strict or none) into http.SameSite.
+// An empty string returns zero which means using the default of each cookie.
+func ParseSameSite(s string) (http.SameSite, error) {
+	switch strings.ToLower(s) {
+	case "":
+		return 0, nil
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	default:
+		return 0, fmt.Errorf("invalid SameSite mode %q, must be one of lax, strict or none", s)
+	}
+}
+
 // NewHandler gives back an HTTP handler for serving PipeCD SPA.
 func NewHandler(
 	signer jwt.Signer,
@@ -39,6 +180,7 @@ func NewHandler(
 	projectGetter projectGetter,
 	secureCookie bool,
 	logger *zap.Logger,
+	opts ...Option,
 ) http.Handler {
 	mux := http.NewServeMux()
 	a := newAuthHandler(
@@ -51,6 +193,7 @@ func NewHandler(
 		projectGetter,
 		secureCookie,
 		logger,
+		opts...,
 	)
 
 	fs := http.FileServer(http.Dir(filepath.Join(staticDir, "assets")))
@@ -73,8 +216,20 @@ func NewHandler(
 	}))
 	register(loginPath, http.HandlerFunc(a.handleSSOLogin))
 	register(staticLoginPath, http.HandlerFunc(a.handleStaticAdminLogin))
+	register(ldapLoginPath, http.HandlerFunc(a.handleLDAPLogin))
 	register(callbackPath, http.HandlerFunc(a.handleCallback))
+	register(samlACSPath, http.HandlerFunc(a.handleSAMLACS))
 	register(logoutPath, http.HandlerFunc(a.handleLogout))
+	register(refreshPath, http.HandlerFunc(a.handleRefresh))
+	register(webAuthnRegisterBeginPath, http.HandlerFunc(a.handleWebAuthnRegisterBegin))
+	register(webAuthnRegisterFinishPath, http.HandlerFunc(a.handleWebAuthnRegisterFinish))
+	register(webAuthnAssertBeginPath, http.HandlerFunc(a.handleWebAuthnAssertBegin))
+	register(webAuthnAssertFinishPath, http.HandlerFunc(a.handleWebAuthnAssertFinish))
+	register(deviceAuthorizePath, http.HandlerFunc(a.handleDeviceAuthorize))
+	register(deviceTokenPath, http.HandlerFunc(a.handleDeviceToken))
+	register(mePath, http.HandlerFunc(a.handleMe))
+	register(providersPath, http.HandlerFunc(a.handleProviders))
+	register(backchannelLogoutPath, http.HandlerFunc(a.handleBackchannelLogout))
 
 	return mux
 }
@@ -0,0 +1,113 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSameSite(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value     string
+		expected  http.SameSite
+		expectErr bool
+	}{
+		{value: "", expected: 0},
+		{value: "lax", expected: http.SameSiteLaxMode},
+		{value: "Strict", expected: http.SameSiteStrictMode},
+		{value: "NONE", expected: http.SameSiteNoneMode},
+		{value: "unknown", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseSameSite(tt.value)
+			assert.Equal(t, tt.expectErr, err != nil)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestValidateCookieDomain(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		domain    string
+		address   string
+		expectErr bool
+	}{
+		{name: "host-only", domain: "", address: "https://pipecd.example.com"},
+		{name: "same host", domain: "pipecd.example.com", address: "https://pipecd.example.com"},
+		{name: "parent domain", domain: "example.com", address: "https://pipecd.example.com"},
+		{name: "leading dot", domain: ".Example.com", address: "https://pipecd.example.com:8080"},
+		{name: "other domain", domain: "example.org", address: "https://pipecd.example.com", expectErr: true},
+		{name: "partial label", domain: "ample.com", address: "https://pipecd.example.com", expectErr: true},
+		{name: "public suffix", domain: "com", address: "https://pipecd.example.com", expectErr: true},
+		{name: "multi-label public suffix", domain: "co.uk", address: "https://pipecd.example.co.uk", expectErr: true},
+		{name: "ip address", domain: "192.0.2.1", address: "https://192.0.2.1", expectErr: true},
+		{name: "missing host", domain: "example.com", address: "/path", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateCookieDomain(tt.domain, tt.address)
+			assert.Equal(t, tt.expectErr, err != nil)
+		})
+	}
+}
+
+func TestValidateCookiePath(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, ValidateCookiePath(""))
+	assert.NoError(t, ValidateCookiePath("/"))
+	assert.NoError(t, ValidateCookiePath("/pipecd"))
+	assert.Error(t, ValidateCookiePath("pipecd"))
+	assert.Error(t, ValidateCookiePath("/pipecd;Domain=evil.com"))
+}
+
+func TestValidateCookieNamePrefix(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		prefix    string
+		domain    string
+		path      string
+		secure    bool
+		expectErr bool
+	}{
+		{name: "empty", prefix: ""},
+		{name: "plain", prefix: "tenant-a-"},
+		{name: "invalid character", prefix: "tenant a;", expectErr: true},
+		{name: "secure", prefix: "__Secure-tenant-", secure: true},
+		{name: "secure without secure cookies", prefix: "__Secure-", expectErr: true},
+		{name: "host", prefix: "__Host-", path: "/", secure: true},
+		{name: "host with domain", prefix: "__Host-", domain: "example.com", secure: true, expectErr: true},
+		{name: "host with path", prefix: "__Host-", path: "/pipecd", secure: true, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateCookieNamePrefix(tt.prefix, tt.domain, tt.path, tt.secure)
+			assert.Equal(t, tt.expectErr, err != nil)
+		})
+	}
+}
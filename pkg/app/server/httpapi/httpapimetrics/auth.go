@@ -0,0 +1,137 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapimetrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	providerLabel = "provider"
+	projectLabel  = "project"
+	reasonLabel   = "reason"
+)
+
+var (
+	loginAttemptCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_login_attempts_total",
+			Help: "Total number of login attempts.",
+		},
+		[]string{providerLabel, projectLabel},
+	)
+
+	loginSuccessCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_login_successes_total",
+			Help: "Total number of successful logins.",
+		},
+		[]string{providerLabel, projectLabel},
+	)
+
+	loginFailureCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_login_failures_total",
+			Help: "Total number of failed logins.",
+		},
+		[]string{providerLabel, projectLabel, reasonLabel},
+	)
+
+	codeExchangeDurationHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "auth_code_exchange_duration_milliseconds",
+			Help:    "Histogram of the duration of exchanging the auth code with the SSO provider in milliseconds.",
+			Buckets: prometheus.ExponentialBuckets(1, 5, 7),
+		},
+		[]string{providerLabel},
+	)
+
+	callbackDurationHistogram = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "auth_callback_duration_milliseconds",
+			Help:    "Histogram of the duration of handling the auth callback requests in milliseconds.",
+			Buckets: prometheus.ExponentialBuckets(1, 5, 7),
+		},
+		[]string{providerLabel},
+	)
+
+	projectLookupFailureCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_project_lookup_failures_total",
+			Help: "Total number of failures to look up the project of the logins by the reason, either not_found, timeout or error.",
+		},
+		[]string{reasonLabel},
+	)
+
+	activeLockoutsGauge = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "auth_callback_active_lockouts",
+			Help: "Number of client IPs currently locked out from the auth callback.",
+		},
+	)
+)
+
+// IncLoginAttempts increments the number of login attempts.
+func IncLoginAttempts(provider, project string) {
+	loginAttemptCounter.With(prometheus.Labels{
+		providerLabel: provider,
+		projectLabel:  project,
+	}).Inc()
+}
+
+// IncLoginSuccesses increments the number of successful logins.
+func IncLoginSuccesses(provider, project string) {
+	loginSuccessCounter.With(prometheus.Labels{
+		providerLabel: provider,
+		projectLabel:  project,
+	}).Inc()
+}
+
+// IncLoginFailures increments the number of failed logins by the given coarse reason.
+func IncLoginFailures(provider, project, reason string) {
+	loginFailureCounter.With(prometheus.Labels{
+		providerLabel: provider,
+		projectLabel:  project,
+		reasonLabel:   reason,
+	}).Inc()
+}
+
+// ObserveCodeExchangeDuration records the duration of exchanging the auth code.
+func ObserveCodeExchangeDuration(provider string, d time.Duration) {
+	codeExchangeDurationHistogram.With(prometheus.Labels{
+		providerLabel: provider,
+	}).Observe(float64(d.Milliseconds()))
+}
+
+// ObserveCallbackDuration records the duration of handling an auth callback request.
+func ObserveCallbackDuration(provider string, d time.Duration) {
+	callbackDurationHistogram.With(prometheus.Labels{
+		providerLabel: provider,
+	}).Observe(float64(d.Milliseconds()))
+}
+
+// IncProjectLookupFailures increments the number of failures to look up the project by the given reason.
+func IncProjectLookupFailures(reason string) {
+	projectLookupFailureCounter.With(prometheus.Labels{
+		reasonLabel: reason,
+	}).Inc()
+}
+
+// SetActiveLockouts sets the number of client IPs currently locked out.
+func SetActiveLockouts(n int) {
+	activeLockoutsGauge.Set(float64(n))
+}
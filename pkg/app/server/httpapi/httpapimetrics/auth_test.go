@@ -0,0 +1,36 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapimetrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginCounters(t *testing.T) {
+	t.Parallel()
+
+	IncLoginAttempts("OIDC", "test-login-counters")
+	IncLoginAttempts("OIDC", "test-login-counters")
+	IncLoginSuccesses("OIDC", "test-login-counters")
+	IncLoginFailures("OIDC", "test-login-counters", "state")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(loginAttemptCounter.WithLabelValues("OIDC", "test-login-counters")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(loginSuccessCounter.WithLabelValues("OIDC", "test-login-counters")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(loginFailureCounter.WithLabelValues("OIDC", "test-login-counters", "state")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(loginFailureCounter.WithLabelValues("OIDC", "test-login-counters", "sign")))
+}
@@ -40,6 +40,7 @@ const (
 	codeLabel   = "code"
 	methodLabel = "method"
 	pathLabel   = "path"
+	keyLabel    = "key"
 )
 
 var (
@@ -59,15 +60,39 @@ var (
 		},
 		[]string{codeLabel, methodLabel, pathLabel},
 	)
+
+	rateLimitedCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "http_rate_limited_requests_total",
+			Help: "Total number of HTTP requests rejected by the rate limiter.",
+		},
+		[]string{pathLabel, keyLabel},
+	)
 )
 
 func Register(r prometheus.Registerer) {
 	r.MustRegister(
 		requestCounter,
 		durationHistgram,
+		rateLimitedCounter,
+		loginAttemptCounter,
+		loginSuccessCounter,
+		loginFailureCounter,
+		codeExchangeDurationHistogram,
+		callbackDurationHistogram,
+		activeLockoutsGauge,
+		projectLookupFailureCounter,
 	)
 }
 
+// IncRateLimitedRequests increments the number of requests rejected by the rate limiter keyed by the given key kind.
+func IncRateLimitedRequests(path, key string) {
+	rateLimitedCounter.With(prometheus.Labels{
+		pathLabel: path,
+		keyLabel:  key,
+	}).Inc()
+}
+
 func Handler(path string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		start := time.Now()
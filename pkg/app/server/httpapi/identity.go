@@ -0,0 +1,120 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/pipe-cd/pipecd/pkg/cache"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const identityLinkKeyPrefix = "identity-link:"
+
+// identityLinker links the identities of the different SSO providers
+// having the same verified email to one user whose subject is the email.
+type identityLinker struct {
+	// links stores the identities linked to each email of each project.
+	links cache.Cache
+	// excludedEmails are never linked, e.g. the shared mailboxes.
+	excludedEmails map[string]struct{}
+}
+
+// WithIdentityLinking enables linking the identities of the users logged in via the different SSO providers
+// by their verified emails. The linked identities are stored in the given cache.
+// The given emails are never linked to avoid merging the different users sharing a mailbox.
+func WithIdentityLinking(c cache.Cache, excludedEmails []string) Option {
+	return func(h *authHandler) {
+		excluded := make(map[string]struct{}, len(excludedEmails))
+		for _, e := range excludedEmails {
+			excluded[normalizeEmail(e)] = struct{}{}
+		}
+		h.identityLinker = &identityLinker{
+			links:          c,
+			excludedEmails: excluded,
+		}
+	}
+}
+
+// linkIdentity links the identity of the given user logged in via the given provider
+// and returns all identities linked to the user, e.g. "GITHUB:octocat".
+// The username of the user is replaced with its email if linked.
+// Nothing is linked if the linking is disabled, or the email is not verified or excluded.
+func (h *authHandler) linkIdentity(projectID string, provider model.ProjectSSOConfig_Provider, user *model.User) ([]string, error) {
+	l := h.identityLinker
+	if l == nil || !user.EmailVerified || user.Email == "" {
+		return nil, nil
+	}
+	email := normalizeEmail(user.Email)
+	if _, ok := l.excludedEmails[email]; ok {
+		return nil, nil
+	}
+
+	key := identityLinkKeyPrefix + projectID + ":" + email
+	identities, err := l.get(key)
+	if err != nil {
+		return nil, err
+	}
+	identity := provider.String() + ":" + user.Username
+	if !slices.Contains(identities, identity) {
+		identities = append(identities, identity)
+		slices.Sort(identities)
+		data, err := json.Marshal(identities)
+		if err != nil {
+			return nil, err
+		}
+		if err := l.links.Put(key, data); err != nil {
+			return nil, err
+		}
+	}
+
+	user.Username = email
+	return identities, nil
+}
+
+// get returns the identities stored with the given key.
+func (l *identityLinker) get(key string) ([]string, error) {
+	v, err := l.links.Get(key)
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	switch v := v.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return nil, fmt.Errorf("unexpected identity link data type: %T", v)
+	}
+	var identities []string
+	if err := json.Unmarshal(data, &identities); err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// normalizeEmail returns the email in the form compared while linking.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,81 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestLinkIdentity(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{}
+	WithIdentityLinking(memorycache.NewCache(), []string{"Team@example.com"})(h)
+
+	// The identities having the same verified email are linked.
+	github := &model.User{Username: "octocat", Email: "Octo@example.com", EmailVerified: true}
+	identities, err := h.linkIdentity("project", model.ProjectSSOConfig_GITHUB, github)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GITHUB:octocat"}, identities)
+	assert.Equal(t, "octo@example.com", github.Username)
+
+	oidc := &model.User{Username: "Octo Cat", Email: "octo@example.com", EmailVerified: true}
+	identities, err = h.linkIdentity("project", model.ProjectSSOConfig_OIDC, oidc)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GITHUB:octocat", "OIDC:Octo Cat"}, identities)
+	assert.Equal(t, "octo@example.com", oidc.Username)
+
+	// Logging in again does not duplicate the identity.
+	github = &model.User{Username: "octocat", Email: "octo@example.com", EmailVerified: true}
+	identities, err = h.linkIdentity("project", model.ProjectSSOConfig_GITHUB, github)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GITHUB:octocat", "OIDC:Octo Cat"}, identities)
+
+	// The identities are linked per project.
+	other := &model.User{Username: "octocat", Email: "octo@example.com", EmailVerified: true}
+	identities, err = h.linkIdentity("other-project", model.ProjectSSOConfig_GITHUB, other)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"GITHUB:octocat"}, identities)
+
+	// The unverified email is not linked.
+	unverified := &model.User{Username: "mallory", Email: "octo@example.com"}
+	identities, err = h.linkIdentity("project", model.ProjectSSOConfig_GITLAB, unverified)
+	require.NoError(t, err)
+	assert.Empty(t, identities)
+	assert.Equal(t, "mallory", unverified.Username)
+
+	// The excluded email is not linked.
+	shared := &model.User{Username: "alice", Email: "team@example.com", EmailVerified: true}
+	identities, err = h.linkIdentity("project", model.ProjectSSOConfig_GITHUB, shared)
+	require.NoError(t, err)
+	assert.Empty(t, identities)
+	assert.Equal(t, "alice", shared.Username)
+}
+
+func TestLinkIdentityDisabled(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{}
+
+	user := &model.User{Username: "octocat", Email: "octo@example.com", EmailVerified: true}
+	identities, err := h.linkIdentity("project", model.ProjectSSOConfig_GITHUB, user)
+	require.NoError(t, err)
+	assert.Empty(t, identities)
+	assert.Equal(t, "octocat", user.Username)
+}
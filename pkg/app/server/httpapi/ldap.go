@@ -0,0 +1,193 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/ldap"
+)
+
+// defaultLDAPFailedBindRateLimit allows 5 failed binds for each user and then one per minute.
+var defaultLDAPFailedBindRateLimit = RateLimit{RequestsPerSecond: 1.0 / 60, Burst: 5}
+
+type ldapAuthenticator interface {
+	Authenticate(project *model.Project, username, password string) (*model.User, error)
+	Lookup(project *model.Project, username string) (*model.User, error)
+}
+
+func newLDAPClient(sso *model.ProjectSSOConfig_Ldap) (ldapAuthenticator, error) {
+	return ldap.NewClient(sso)
+}
+
+// WithLDAPFailedBindRateLimit limits the failed LDAP binds for each user of each project.
+// Zero RequestsPerSecond disables the limit.
+func WithLDAPFailedBindRateLimit(limit RateLimit) Option {
+	return func(h *authHandler) {
+		h.ldapBindLimiter = nil
+		if limit.enabled() {
+			h.ldapBindLimiter = newKeyedLimiter(limit)
+		}
+	}
+}
+
+// handleLDAPLogin is called when an user requested to login with the credentials of the LDAP directory.
+func (h *authHandler) handleLDAPLogin(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	h.setContentSecurityPolicy(w)
+
+	event := h.newLoginEvent(r)
+	event.Provider = model.ProjectSSOConfig_LDAP.String()
+
+	// Validate request's payload.
+	if r.Method != http.MethodPost {
+		h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeMethodNotAllowed, "Method not allowed", nil)
+		return
+	}
+	projectID := r.FormValue(projectFormKey)
+	if projectID == "" {
+		h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeInvalidRequest, "Missing project id", nil)
+		return
+	}
+	event.ProjectID = projectID
+	username := r.FormValue(usernameFormKey)
+	event.Username = username
+	if username == "" {
+		h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeInvalidRequest, "Missing username", nil)
+		return
+	}
+	password := r.FormValue(passwordFormKey)
+	if password == "" {
+		h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeInvalidRequest, "Missing password", nil)
+		return
+	}
+
+	limitKey := projectID + "/" + username
+	if l := h.ldapBindLimiter; l != nil && !l.available(limitKey) {
+		h.handleRateLimited(w, r, l.retryAfter(), ldapLoginPath, "user")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	proj, err := h.getProject(ctx, projectID)
+	if err != nil {
+		reason, code, message := projectLookupErrorCode(projectID, err)
+		h.handleLoginError(w, r, event, reason, code, message, err)
+		return
+	}
+	sso, shared, err := h.findSSOConfigByName(proj, r.FormValue(ssoFormKey))
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeInvalidSSOConfig, fmt.Sprintf("Invalid SSO configuration: %v", err), nil)
+		return
+	}
+	if !shared {
+		if sso, err = h.decryptSSO(proj.Id, sso); err != nil {
+			h.handleLoginError(w, r, event, failureReasonDecrypt, errCodeInternal, "Failed to decrypt SSO configuration", err)
+			return
+		}
+	}
+	if sso.Provider != model.ProjectSSOConfig_LDAP || sso.Ldap == nil {
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeInvalidSSOConfig, "LDAP is not configured for the project", nil)
+		return
+	}
+
+	cli, err := h.newLDAPClient(sso.Ldap)
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeInvalidSSOConfig, "Invalid LDAP configuration", err)
+		return
+	}
+	// The error never contains the password, so it is safe to be logged.
+	user, err := cli.Authenticate(proj, username, password)
+	if errors.Is(err, ldap.ErrInvalidCredentials) {
+		if l := h.ldapBindLimiter; l != nil {
+			l.allow(limitKey)
+		}
+		h.handleLoginError(w, r, event, failureReasonCredentials, errCodeUnauthorized, "Unable to login", nil)
+		return
+	}
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeUnauthorized, "Unable to find user", err)
+		return
+	}
+	if err := ensureRole(proj, user); err != nil {
+		h.handleLoginError(w, r, event, failureReasonForbidden, errCodeForbidden, "no role assigned for your account", err)
+		return
+	}
+	tokenTTL := h.loginTokenTTL(sessionTokenTTL(sso, proj, user.Role))
+	user.AvatarUrl = h.resolveAvatarURL(user)
+
+	claims := jwt.NewClaimsAt(
+		h.now(),
+		user.Username,
+		user.AvatarUrl,
+		tokenTTL,
+		*user.Role,
+		user.Groups...,
+	)
+	claims.Provider = event.Provider
+	h.bindSession(r, claims)
+	signedToken, err := h.signer.Sign(claims)
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
+		return
+	}
+	if err := h.registerSession(claims); err != nil {
+		h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
+		return
+	}
+	if h.refreshTokens != nil {
+		value, err := h.issueRefreshToken(&refreshToken{
+			Subject:   user.Username,
+			AvatarURL: user.AvatarUrl,
+			ProjectID: proj.Id,
+			Roles:     user.Role.ProjectRbacRoles,
+			TokenTTL:  tokenTTL,
+			Groups:    user.Groups,
+			Provider:  event.Provider,
+			LoginAt:   claims.AuthTime.Time,
+			Binding:   claims.Binding,
+		})
+		if err != nil {
+			h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
+			return
+		}
+		http.SetCookie(w, h.cookies.refreshToken(value, h.refreshTokenTTL))
+	}
+
+	h.logger.Info("user logged in",
+		zap.String("user", user.Username),
+		zap.String("project-id", proj.Id),
+		zap.String("project-role", user.Role.String()),
+	)
+
+	target := rootPath
+	if t, ok := h.validateReturnTo(r.FormValue(returnToFormKey)); ok {
+		target = t
+	}
+	http.SetCookie(w, h.cookies.token(signedToken))
+	event.Success = true
+	h.recordLogin(r, event)
+	http.Redirect(w, r, target, http.StatusFound)
+}
@@ -0,0 +1,131 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/ldap"
+)
+
+type fakeLDAPAuthenticator map[string]string
+
+func (a fakeLDAPAuthenticator) Authenticate(project *model.Project, username, password string) (*model.User, error) {
+	if p, ok := a[username]; !ok || p != password {
+		return nil, ldap.ErrInvalidCredentials
+	}
+	return &model.User{
+		Username: username,
+		Role: &model.Role{
+			ProjectId:        project.Id,
+			ProjectRbacRoles: []string{model.BuiltinRBACRoleEditor.String()},
+		},
+	}, nil
+}
+
+func (a fakeLDAPAuthenticator) Lookup(project *model.Project, username string) (*model.User, error) {
+	if _, ok := a[username]; !ok {
+		return nil, ldap.ErrUserNotFound
+	}
+	return &model.User{
+		Username: username,
+		Role: &model.Role{
+			ProjectId:        project.Id,
+			ProjectRbacRoles: []string{model.BuiltinRBACRoleEditor.String()},
+		},
+	}, nil
+}
+
+func TestHandleLDAPLogin(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		signer: fakeSigner{},
+		projectGetter: fakeProjectGetter{
+			"ldap": {
+				Id: "ldap",
+				Sso: &model.ProjectSSOConfig{
+					Provider: model.ProjectSSOConfig_LDAP,
+					Ldap:     &model.ProjectSSOConfig_Ldap{Url: "ldap://ldap.example.com"},
+				},
+			},
+			"github": {
+				Id:  "github",
+				Sso: &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_GITHUB},
+			},
+		},
+		ldapBindLimiter: newKeyedLimiter(RateLimit{RequestsPerSecond: 0.001, Burst: 2}),
+		newLDAPClient: func(*model.ProjectSSOConfig_Ldap) (ldapAuthenticator, error) {
+			return fakeLDAPAuthenticator{"alice": "alice-password", "bob": "bob-password"}, nil
+		},
+		auditRecorder: nopAuditRecorder{},
+		logger:        zap.NewNop(),
+	}
+
+	login := func(project, username, password, returnTo string) *httptest.ResponseRecorder {
+		form := url.Values{
+			projectFormKey:  {project},
+			usernameFormKey: {username},
+			passwordFormKey: {password},
+			returnToFormKey: {returnTo},
+		}
+		req := httptest.NewRequest(http.MethodPost, ldapLoginPath, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		h.handleLDAPLogin(rec, req)
+		return rec
+	}
+	tokenCookie := func(rec *httptest.ResponseRecorder) string {
+		for _, c := range rec.Result().Cookies() {
+			if c.Name == jwt.SignedTokenKey {
+				return c.Value
+			}
+		}
+		return ""
+	}
+
+	rec := login("ldap", "alice", "alice-password", "/applications")
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "/applications", rec.Header().Get("Location"))
+	assert.Equal(t, "signed:alice", tokenCookie(rec))
+
+	rec = login("ldap", "alice", "alice-password", "//evil.example.com")
+	assert.Equal(t, rootPath, rec.Header().Get("Location"))
+
+	rec = login("github", "alice", "alice-password", "")
+	assert.Empty(t, tokenCookie(rec))
+
+	// Only the failed binds are counted by the limiter.
+	for i := 0; i < 2; i++ {
+		rec = login("ldap", "bob", "wrong", "")
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Empty(t, tokenCookie(rec))
+	}
+	rec = login("ldap", "bob", "bob-password", "")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Empty(t, tokenCookie(rec))
+
+	// Other users are not affected.
+	rec = login("ldap", "alice", "alice-password", "")
+	assert.Equal(t, "signed:alice", tokenCookie(rec))
+}
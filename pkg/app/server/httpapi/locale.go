@@ -0,0 +1,133 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultLocale = "en"
+
+// loginErrorText is the localized text of the login error page.
+type loginErrorText struct {
+	Title         string
+	ErrorCode     string
+	CorrelationID string
+	BackToLogin   string
+	// Messages are the localized messages of the error codes.
+	// The English page shows the detailed message of the error instead.
+	Messages map[errorCode]string
+}
+
+// loginErrorCatalog holds the text of the login error page for each supported language.
+var loginErrorCatalog = map[string]*loginErrorText{
+	"en": {
+		Title:         "Login failed",
+		ErrorCode:     "Error code",
+		CorrelationID: "correlation ID",
+		BackToLogin:   "Back to login",
+	},
+	"ja": {
+		Title:         "ログインに失敗しました",
+		ErrorCode:     "エラーコード",
+		CorrelationID: "相関 ID",
+		BackToLogin:   "ログイン画面に戻る",
+		Messages: map[errorCode]string{
+			errCodeMethodNotAllowed: "許可されていないリクエストです。",
+			errCodeInvalidRequest:   "リクエストが不正です。",
+			errCodeUnauthorized:     "認証できませんでした。",
+			errCodeLoginExpired:     "ログインの有効期限が切れました。もう一度お試しください。",
+			errCodeStateInvalid:     "ログインの状態が不正です。もう一度お試しください。",
+			errCodeForbidden:        "このプロジェクトへのアクセス権限がありません。",
+			errCodeProjectNotFound:  "プロジェクトが見つかりません。",
+			errCodeInvalidSSOConfig: "プロジェクトの SSO 設定が不正です。",
+			errCodeTooManyRequests:  "リクエストが多すぎます。しばらくしてからもう一度お試しください。",
+			errCodeRequestTooLarge:  "リクエストが大きすぎます。",
+			errCodeUnavailable:      "一時的にサービスを利用できません。しばらくしてからもう一度お試しください。",
+			errCodeInternal:         "内部エラーが発生しました。",
+		},
+	},
+	"zh": {
+		Title:         "登录失败",
+		ErrorCode:     "错误代码",
+		CorrelationID: "关联 ID",
+		BackToLogin:   "返回登录",
+		Messages: map[errorCode]string{
+			errCodeMethodNotAllowed: "不允许的请求。",
+			errCodeInvalidRequest:   "请求无效。",
+			errCodeUnauthorized:     "身份验证失败。",
+			errCodeLoginExpired:     "登录已过期，请重试。",
+			errCodeStateInvalid:     "登录状态无效，请重试。",
+			errCodeForbidden:        "您没有访问该项目的权限。",
+			errCodeProjectNotFound:  "找不到项目。",
+			errCodeInvalidSSOConfig: "项目的 SSO 配置无效。",
+			errCodeTooManyRequests:  "请求过多，请稍后重试。",
+			errCodeRequestTooLarge:  "请求过大。",
+			errCodeUnavailable:      "服务暂时不可用，请稍后重试。",
+			errCodeInternal:         "发生内部错误。",
+		},
+	},
+}
+
+// requestLocale returns the supported language most preferred by the Accept-Language header of the request,
+// e.g. "ja" for "ja-JP,en;q=0.8". English is returned if none of the languages is supported.
+func requestLocale(r *http.Request) string {
+	type weighted struct {
+		lang string
+		q    float64
+	}
+	var langs []weighted
+	for _, v := range strings.Split(r.Header.Get("Accept-Language"), ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(v), ";")
+		q := 1.0
+		if p, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			f, err := strconv.ParseFloat(p, 64)
+			if err != nil {
+				continue
+			}
+			q = f
+		}
+		if q <= 0 {
+			continue
+		}
+		lang, _, _ := strings.Cut(tag, "-")
+		langs = append(langs, weighted{lang: strings.ToLower(lang), q: q})
+	}
+	sort.SliceStable(langs, func(i, j int) bool {
+		return langs[i].q > langs[j].q
+	})
+	for _, l := range langs {
+		if _, ok := loginErrorCatalog[l.lang]; ok {
+			return l.lang
+		}
+	}
+	return defaultLocale
+}
+
+// localizedMessage returns the message of the error code in the given language.
+// The given English message is returned for English or the codes without the localized message.
+func localizedMessage(locale string, code errorCode, message string) string {
+	t, ok := loginErrorCatalog[locale]
+	if !ok {
+		return message
+	}
+	if m, ok := t.Messages[code]; ok {
+		return m
+	}
+	return message
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLocale(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		acceptLanguage string
+		expected       string
+	}{
+		{
+			name:     "no header",
+			expected: "en",
+		},
+		{
+			name:           "supported language",
+			acceptLanguage: "ja",
+			expected:       "ja",
+		},
+		{
+			name:           "region subtag",
+			acceptLanguage: "zh-CN,zh;q=0.9",
+			expected:       "zh",
+		},
+		{
+			name:           "preferred by quality",
+			acceptLanguage: "en;q=0.5, ja-JP;q=0.8",
+			expected:       "ja",
+		},
+		{
+			name:           "first supported language",
+			acceptLanguage: "fr-FR, de;q=0.9, ja;q=0.8, en;q=0.7",
+			expected:       "ja",
+		},
+		{
+			name:           "unsupported languages",
+			acceptLanguage: "fr-FR, de;q=0.9",
+			expected:       "en",
+		},
+		{
+			name:           "excluded language",
+			acceptLanguage: "ja;q=0, *",
+			expected:       "en",
+		},
+		{
+			name:           "malformed quality",
+			acceptLanguage: "ja;q=high",
+			expected:       "en",
+		},
+		{
+			name:           "case insensitive",
+			acceptLanguage: "JA-jp",
+			expected:       "ja",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+			if tt.acceptLanguage != "" {
+				req.Header.Set("Accept-Language", tt.acceptLanguage)
+			}
+			assert.Equal(t, tt.expected, requestLocale(req))
+		})
+	}
+}
+
+func TestLoginErrorCatalog(t *testing.T) {
+	t.Parallel()
+	codes := []errorCode{
+		errCodeMethodNotAllowed,
+		errCodeInvalidRequest,
+		errCodeUnauthorized,
+		errCodeLoginExpired,
+		errCodeStateInvalid,
+		errCodeForbidden,
+		errCodeProjectNotFound,
+		errCodeInvalidSSOConfig,
+		errCodeTooManyRequests,
+		errCodeRequestTooLarge,
+		errCodeUnavailable,
+		errCodeInternal,
+	}
+	for locale, text := range loginErrorCatalog {
+		assert.NotEmpty(t, text.Title, locale)
+		assert.NotEmpty(t, text.ErrorCode, locale)
+		assert.NotEmpty(t, text.CorrelationID, locale)
+		assert.NotEmpty(t, text.BackToLogin, locale)
+		if locale == defaultLocale {
+			continue
+		}
+		for _, code := range codes {
+			assert.NotEmpty(t, text.Messages[code], "%s: %s", locale, code)
+		}
+	}
+
+	assert.Equal(t, "Unable to find project p", localizedMessage("en", errCodeProjectNotFound, "Unable to find project p"))
+	assert.Equal(t, "プロジェクトが見つかりません。", localizedMessage("ja", errCodeProjectNotFound, "Unable to find project p"))
+	assert.Equal(t, "Unknown", localizedMessage("ja", errorCode("unknown"), "Unknown"))
+	assert.Equal(t, "Unknown", localizedMessage("fr", errCodeInternal, "Unknown"))
+}
@@ -0,0 +1,214 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/app/server/httpapi/httpapimetrics"
+)
+
+// Lockout is the policy to temporarily reject the requests from a client
+// after too many consecutive failures.
+// Zero Threshold means no lockout.
+type Lockout struct {
+	// Threshold is the number of failures within Window to start the lockout.
+	Threshold int
+	// Window is the period in which the failures are counted.
+	Window time.Duration
+	// Cooldown is the period in which the requests are rejected.
+	Cooldown time.Duration
+}
+
+func (l Lockout) enabled() bool {
+	return l.Threshold > 0 && l.Window > 0 && l.Cooldown > 0
+}
+
+// WithCallbackLockout locks out the client IPs which repeatedly failed the callback validations.
+// Its window and cooldown are also used by the lockouts of the projects which do not set them.
+func WithCallbackLockout(l Lockout) Option {
+	return func(h *authHandler) {
+		if l.enabled() {
+			h.callbackLockout = newLockoutTracker(l)
+		}
+		if t := h.projectThrottles; t != nil {
+			if l.Window > 0 {
+				t.defaultLockout.Window = l.Window
+			}
+			if l.Cooldown > 0 {
+				t.defaultLockout.Cooldown = l.Cooldown
+			}
+		}
+	}
+}
+
+// lockoutFailureReasons are the failures counted towards the lockout.
+// They are caused by the request itself rather than by the configuration or the provider.
+var lockoutFailureReasons = map[loginFailureReason]struct{}{
+	failureReasonState:          {},
+	failureReasonMissingCode:    {},
+	failureReasonInvalidRequest: {},
+}
+
+type lockoutEntry struct {
+	failures     int
+	windowStart  time.Time
+	lockedUntil  time.Time
+	lastModified time.Time
+}
+
+// lockoutTracker counts the consecutive failures of each key.
+type lockoutTracker struct {
+	policy Lockout
+	now    func() time.Time
+	// setActive reports the number of the keys locked out. Nil means not reported.
+	setActive func(int)
+
+	mu      sync.Mutex
+	entries map[string]*lockoutEntry
+}
+
+func newLockoutTracker(policy Lockout) *lockoutTracker {
+	return &lockoutTracker{
+		policy:    policy,
+		now:       time.Now,
+		setActive: httpapimetrics.SetActiveLockouts,
+		entries:   make(map[string]*lockoutEntry),
+	}
+}
+
+// lockedFor returns how long the given key is still locked out.
+// Zero means the key is not locked out.
+func (t *lockoutTracker) lockedFor(key string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+	e, ok := t.entries[key]
+	if !ok || !now.Before(e.lockedUntil) {
+		return 0
+	}
+	return e.lockedUntil.Sub(now)
+}
+
+// fail records a failure of the given key and reports whether it is locked out by this failure.
+func (t *lockoutTracker) fail(key string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+	e, ok := t.entries[key]
+	if !ok {
+		e = &lockoutEntry{windowStart: now}
+		t.entries[key] = e
+	}
+	e.lastModified = now
+	if now.Before(e.lockedUntil) {
+		return false
+	}
+	if now.Sub(e.windowStart) >= t.policy.Window {
+		e.failures = 0
+		e.windowStart = now
+	}
+	e.failures++
+	if e.failures < t.policy.Threshold {
+		return false
+	}
+	e.failures = 0
+	e.windowStart = now
+	e.lockedUntil = now.Add(t.policy.Cooldown)
+	t.updateMetric(now)
+	return true
+}
+
+// reset clears the failures of the given key.
+func (t *lockoutTracker) reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.entries, key)
+	t.updateMetric(t.now())
+}
+
+// sweep removes the entries which are neither locked out nor counting failures.
+// The caller must hold the lock.
+func (t *lockoutTracker) sweep(now time.Time) {
+	for k, e := range t.entries {
+		if now.Sub(e.lastModified) >= t.policy.Window && !now.Before(e.lockedUntil) {
+			delete(t.entries, k)
+		}
+	}
+	t.updateMetric(now)
+}
+
+// updateMetric reports the number of the keys locked out at the given time.
+// The caller must hold the lock.
+func (t *lockoutTracker) updateMetric(now time.Time) {
+	if t.setActive == nil {
+		return
+	}
+	active := 0
+	for _, e := range t.entries {
+		if now.Before(e.lockedUntil) {
+			active++
+		}
+	}
+	t.setActive(active)
+}
+
+// recordCallbackFailure counts the failure of the callback request towards the lockouts of its client IP
+// by the control plane and by the given project.
+func (h *authHandler) recordCallbackFailure(r *http.Request, projectID string, reason loginFailureReason) {
+	if r.URL.Path != callbackPath {
+		return
+	}
+	if _, ok := lockoutFailureReasons[reason]; !ok {
+		return
+	}
+	ip := h.clientIP(r)
+	h.recordProjectCallbackFailure(projectID, ip)
+	if h.callbackLockout == nil {
+		return
+	}
+	if h.callbackLockout.fail(ip) {
+		h.logger.Warn("auth-handler: locked out the client after too many failed callbacks",
+			zap.String("client-ip", ip),
+			zap.Duration("cooldown", h.callbackLockout.policy.Cooldown),
+		)
+	}
+}
+
+// resetCallbackFailures clears the failures of the client IP of the successful callback to the given project.
+func (h *authHandler) resetCallbackFailures(r *http.Request, projectID string) {
+	ip := h.clientIP(r)
+	if l := h.callbackLockout; l != nil {
+		l.reset(ip)
+	}
+	if t := h.projectThrottles.get(projectID); t != nil && t.tracker != nil {
+		t.tracker.reset(ip)
+	}
+}
+
+// retryAfterSeconds rounds up the given duration to the seconds for the Retry-After header.
+func retryAfterSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,107 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestLockoutTracker(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	l := newLockoutTracker(Lockout{Threshold: 3, Window: time.Minute, Cooldown: 10 * time.Minute})
+	l.now = func() time.Time { return now }
+
+	assert.False(t, l.fail("a"))
+	assert.False(t, l.fail("a"))
+	assert.Zero(t, l.lockedFor("a"))
+	assert.True(t, l.fail("a"))
+	assert.Equal(t, 10*time.Minute, l.lockedFor("a"))
+	// Other keys are counted separately.
+	assert.Zero(t, l.lockedFor("b"))
+
+	// The failures while locked out do not extend the lockout.
+	now = now.Add(5 * time.Minute)
+	assert.False(t, l.fail("a"))
+	assert.Equal(t, 5*time.Minute, l.lockedFor("a"))
+
+	now = now.Add(5 * time.Minute)
+	assert.Zero(t, l.lockedFor("a"))
+
+	// The failures out of the window are not counted.
+	assert.False(t, l.fail("b"))
+	assert.False(t, l.fail("b"))
+	now = now.Add(time.Minute)
+	assert.False(t, l.fail("b"))
+	assert.False(t, l.fail("b"))
+	assert.Zero(t, l.lockedFor("b"))
+
+	// Reset clears the failures.
+	l.reset("b")
+	assert.False(t, l.fail("b"))
+	assert.False(t, l.fail("b"))
+	assert.Zero(t, l.lockedFor("b"))
+
+	// Idle entries are removed.
+	now = now.Add(time.Minute)
+	l.lockedFor("c")
+	assert.Empty(t, l.entries)
+}
+
+func TestLockout_Enabled(t *testing.T) {
+	t.Parallel()
+	assert.False(t, Lockout{}.enabled())
+	assert.False(t, Lockout{Threshold: 1, Window: time.Minute}.enabled())
+	assert.True(t, Lockout{Threshold: 1, Window: time.Minute, Cooldown: time.Minute}.enabled())
+}
+
+func TestHandleCallbackLockout(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		stateKey:      "state-key",
+		projectGetter: fakeProjectGetter{},
+		auditRecorder: nopAuditRecorder{},
+		logger:        zap.NewNop(),
+	}
+	WithCallbackLockout(Lockout{Threshold: 2, Window: time.Minute, Cooldown: 90 * time.Second})(h)
+
+	call := func(ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, callbackPath+"?state=invalid:project%3Dproject", nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		h.handleCallback(rec, req)
+		return rec
+	}
+
+	assert.NotEqual(t, http.StatusTooManyRequests, call("192.0.2.1").Code)
+	assert.NotEqual(t, http.StatusTooManyRequests, call("192.0.2.1").Code)
+	rec := call("192.0.2.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "90", rec.Header().Get("Retry-After"))
+	assert.NotEqual(t, http.StatusTooManyRequests, call("192.0.2.2").Code)
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 1, retryAfterSeconds(time.Millisecond))
+	assert.Equal(t, 60, retryAfterSeconds(time.Minute))
+	assert.Equal(t, 61, retryAfterSeconds(time.Minute+time.Millisecond))
+}
@@ -16,13 +16,16 @@ package httpapi
 
 import (
 	"context"
-	"encoding/hex"
+	"encoding/base64"
 	"fmt"
+	"io"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/coreos/go-oidc/v3/oidc"
 	"go.uber.org/zap"
-	"golang.org/x/net/xsrftoken"
+	"golang.org/x/oauth2"
 
 	"github.com/pipe-cd/pipecd/pkg/jwt"
 	"github.com/pipe-cd/pipecd/pkg/model"
@@ -31,76 +34,208 @@ import (
 // handleSSOLogin is called when an user requested to login via SSO.
 func (h *authHandler) handleSSOLogin(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
+	h.setContentSecurityPolicy(w)
 
 	// Validate request's payload.
 	if r.Method != http.MethodPost {
-		h.handleError(w, r, "Method not allowed", nil)
+		h.handleError(w, r, errCodeMethodNotAllowed, "Method not allowed", nil)
 		return
 	}
 	projectID := r.FormValue(projectFormKey)
 	if projectID == "" {
-		h.handleError(w, r, "Missing project id", nil)
+		h.handleError(w, r, errCodeInvalidRequest, "Missing project id", nil)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	proj, err := h.projectGetter.Get(ctx, projectID)
+	proj, err := h.getProject(ctx, projectID)
 	if err != nil {
-		h.handleError(w, r, fmt.Sprintf("Unable to find project %s", projectID), err)
+		_, code, message := projectLookupErrorCode(projectID, err)
+		h.handleError(w, r, code, message, err)
 		return
 	}
 
-	sso, shared, err := h.findSSOConfig(proj)
+	choices, err := h.findSSOChoices(proj)
 	if err != nil {
-		h.handleError(w, r, fmt.Sprintf("Invalid SSO configuration: %v", err), nil)
+		h.handleError(w, r, errCodeInvalidSSOConfig, fmt.Sprintf("Invalid SSO configuration: %v", err), nil)
+		return
+	}
+	// Let the user select the SSO configuration first if there are multiple ones.
+	if len(choices) > 1 && !r.Form.Has(ssoFormKey) {
+		returnTo, _ := h.validateReturnTo(r.FormValue(returnToFormKey))
+		if err := writeSSOChooserPage(w, proj.Id, choices, returnTo); err != nil {
+			h.logger.Error("auth-handler: failed to write the sso chooser page", zap.Error(err))
+		}
+		return
+	}
+	ssoName := r.FormValue(ssoFormKey)
+	sso, shared, err := h.findSSOConfigByName(proj, ssoName)
+	if err != nil {
+		h.handleError(w, r, errCodeInvalidSSOConfig, fmt.Sprintf("Invalid SSO configuration: %v", err), nil)
 		return
 	}
 
 	if !shared {
-		if err := sso.Decrypt(h.decrypter); err != nil {
-			h.handleError(w, r, "Failed to decrypt SSO configuration", err)
+		if sso, err = h.decryptSSO(proj.Id, sso); err != nil {
+			h.handleError(w, r, errCodeInternal, "Failed to decrypt SSO configuration", err)
 			return
 		}
 	}
+	if sso.Provider == model.ProjectSSOConfig_SAML {
+		h.startSAMLLogin(w, r, proj, ssoName, sso)
+		return
+	}
+	if err := checkAllowedHosts(sso); err != nil {
+		h.handleError(w, r, errCodeInvalidSSOConfig, "Invalid SSO configuration: host not allowed", err)
+		return
+	}
 
+	// The data of the login flow is kept in the sealed state if enabled, otherwise in the cookies.
 	var (
-		stateToken = xsrftoken.Generate(h.stateKey, "", "")
-		state      = hex.EncodeToString([]byte(stateToken))
+		sealed  *sealedState
+		cookies []*http.Cookie
+		opts    []oauth2.AuthCodeOption
 	)
-	authURL, err := sso.GenerateAuthCodeURL(proj.Id, h.callbackURL, state)
+	if h.stateAEAD != nil {
+		sealed = &sealedState{ProjectID: proj.Id, SSOName: ssoName}
+	}
+	if pkceEnabled(sso) {
+		verifier, err := generateVerifier(h.randReader())
+		if err != nil {
+			h.handleError(w, r, errCodeInternal, "Internal error", err)
+			return
+		}
+		opts = append(opts, pkceChallengeOption(sso.Oidc.PkceChallengeMethod, verifier))
+		if sealed != nil {
+			sealed.CodeVerifier = verifier
+		} else {
+			cookies = append(cookies, h.cookies.codeVerifier(verifier, h.stateTTL))
+		}
+	}
+	if nonceEnabled(sso) {
+		nonce, err := generateNonce(h.randReader())
+		if err != nil {
+			h.handleError(w, r, errCodeInternal, "Internal error", err)
+			return
+		}
+		opts = append(opts, oidc.Nonce(nonce))
+		if sealed != nil {
+			sealed.Nonce = nonce
+		} else {
+			cookies = append(cookies, h.cookies.nonce(signNonce(h.stateKey, nonce), h.stateTTL))
+		}
+	}
+	if opt := maxAgeOption(sso); opt != nil {
+		opts = append(opts, opt)
+	}
+	target, ok := h.validateReturnTo(r.FormValue(returnToFormKey))
+	switch {
+	case sealed != nil:
+		sealed.ReturnTo = target
+		cookies = append(cookies, h.cookies.expiredReturnTo())
+	case ok:
+		cookies = append(cookies, h.cookies.returnTo(signReturnTo(h.stateKey, target), h.stateTTL))
+	default:
+		cookies = append(cookies, h.cookies.expiredReturnTo())
+	}
+
+	var state, stateCookie string
+	// The project ID is sent in the state unless it is sealed. GitHub still receives it
+	// in the redirect URI, which must be the same when the auth code is exchanged.
+	stateProject := proj.Id
+	if sealed != nil {
+		state, stateCookie, err = newSealedState(h.stateAEAD, h.stateKey, sealed, h.randReader(), h.now())
+		if sso.Provider != model.ProjectSSOConfig_GITHUB {
+			stateProject = ""
+		}
+	} else {
+		state, stateCookie, err = newState(h.stateKey, ssoName, h.randReader(), h.now())
+	}
+	if err != nil {
+		h.handleError(w, r, errCodeInternal, "Internal error", err)
+		return
+	}
+	if h.grants != nil {
+		// The refresh tokens are needed to look up the users at the providers issuing the ID tokens.
+		opts = append(opts, sso.OfflineAccessOptions()...)
+	}
+	authURL, err := h.authCodeURL(r.Context(), sso, stateProject, state, opts...)
 	if err != nil {
-		h.handleError(w, r, "Internal error", err)
+		h.handleError(w, r, errCodeInternal, "Internal error", err)
 		return
 	}
 
-	http.SetCookie(w, makeStateCookie(state, h.secureCookie))
+	for _, c := range cookies {
+		http.SetCookie(w, c)
+	}
+	http.SetCookie(w, h.cookies.state(stateCookie, h.stateTTL))
 	http.Redirect(w, r, authURL, http.StatusFound)
 }
 
+func pkceEnabled(sso *model.ProjectSSOConfig) bool {
+	return sso.Provider == model.ProjectSSOConfig_OIDC && sso.Oidc != nil && sso.Oidc.PkceEnabled
+}
+
+// generateVerifier returns a PKCE code verifier in the same way as oauth2.GenerateVerifier
+// but reading the randomness from the given source.
+func generateVerifier(random io.Reader) (string, error) {
+	// 32 octets are recommended by RFC 7636.
+	b, err := randomBytes(random, 32)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// pkceChallengeOption returns the option to add the code challenge
+// derived from the given verifier to the authorization request.
+func pkceChallengeOption(method, verifier string) oauth2.AuthCodeOption {
+	if method == "plain" {
+		// The code_challenge_method defaults to "plain" when omitted (RFC 7636).
+		return oauth2.SetAuthURLParam("code_challenge", verifier)
+	}
+	return oauth2.S256ChallengeOption(verifier)
+}
+
+// maxAgeOption returns the option to send the max_age of the OIDC configuration,
+// which asks the provider to reauthenticate the user who authenticated before it. Nil if not configured.
+func maxAgeOption(sso *model.ProjectSSOConfig) oauth2.AuthCodeOption {
+	if sso.Provider != model.ProjectSSOConfig_OIDC || sso.Oidc.GetMaxAge() <= 0 {
+		return nil
+	}
+	return oauth2.SetAuthURLParam("max_age", strconv.FormatInt(sso.Oidc.MaxAge, 10))
+}
+
 // handleStaticAdminLogin is called when an user requested to login as a static admin.
 func (h *authHandler) handleStaticAdminLogin(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
+	h.setContentSecurityPolicy(w)
+
+	event := h.newLoginEvent(r)
+	event.Provider = staticAdminProvider
 
 	// Validate request's payload.
 	if r.Method != http.MethodPost {
-		h.handleError(w, r, "Method not allowed", nil)
+		h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeMethodNotAllowed, "Method not allowed", nil)
 		return
 	}
 	projectID := r.FormValue(projectFormKey)
 	if projectID == "" {
-		h.handleError(w, r, "Missing project id", nil)
+		h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeInvalidRequest, "Missing project id", nil)
 		return
 	}
+	event.ProjectID = projectID
 	username := r.FormValue(usernameFormKey)
+	event.Username = username
 	if username == "" {
-		h.handleError(w, r, "Missing username", nil)
+		h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeInvalidRequest, "Missing username", nil)
 		return
 	}
 	password := r.FormValue(passwordFormKey)
 	if password == "" {
-		h.handleError(w, r, "Missing password", nil)
+		h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeInvalidRequest, "Missing password", nil)
 		return
 	}
 
@@ -116,33 +251,39 @@ func (h *authHandler) handleStaticAdminLogin(w http.ResponseWriter, r *http.Requ
 
 		proj, err := h.projectGetter.Get(ctx, projectID)
 		if err != nil {
-			h.handleError(w, r, fmt.Sprintf("Unable to find project: %s", projectID), err)
+			h.handleLoginError(w, r, event, failureReasonProjectNotFound, errCodeProjectNotFound, fmt.Sprintf("Unable to find project: %s", projectID), err)
 			return
 		}
 		if proj.StaticAdminDisabled {
-			h.handleError(w, r, "Static admin is disabling", nil)
+			h.handleLoginError(w, r, event, failureReasonForbidden, errCodeForbidden, "Static admin is disabling", nil)
 			return
 		}
 		admin = proj.StaticAdmin
 	}
 
 	if err := admin.Auth(username, password); err != nil {
-		h.handleError(w, r, "Unable to login", err)
+		h.handleLoginError(w, r, event, failureReasonCredentials, errCodeUnauthorized, "Unable to login", err)
 		return
 	}
 
-	claims := jwt.NewClaims(
+	claims := jwt.NewClaimsAt(
+		h.now(),
 		admin.Username,
 		"",
-		defaultTokenTTL,
+		h.loginTokenTTL(defaultTokenTTL),
 		model.Role{
 			ProjectId:        projectID,
 			ProjectRbacRoles: []string{model.BuiltinRBACRoleAdmin.String()},
 		},
 	)
+	h.bindSession(r, claims)
 	signedToken, err := h.signer.Sign(claims)
 	if err != nil {
-		h.handleError(w, r, "Internal error", err)
+		h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
+		return
+	}
+	if err := h.registerSession(claims); err != nil {
+		h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
 		return
 	}
 
@@ -151,6 +292,8 @@ func (h *authHandler) handleStaticAdminLogin(w http.ResponseWriter, r *http.Requ
 		zap.String("project-id", projectID),
 		zap.String("project-role", model.BuiltinRBACRoleAdmin.String()),
 	)
-	http.SetCookie(w, makeTokenCookie(signedToken, h.secureCookie))
+	http.SetCookie(w, h.cookies.token(signedToken))
+	event.Success = true
+	h.recordLogin(r, event)
 	http.Redirect(w, r, rootPath, http.StatusFound)
 }
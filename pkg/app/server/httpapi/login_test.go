@@ -13,3 +13,99 @@
 // limitations under the License.
 
 package httpapi
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestPKCEChallengeOption(t *testing.T) {
+	t.Parallel()
+	verifier := oauth2.GenerateVerifier()
+	tests := []struct {
+		name              string
+		method            string
+		expectedChallenge string
+		expectedMethod    string
+	}{
+		{
+			name:              "default to S256",
+			method:            "",
+			expectedChallenge: oauth2.S256ChallengeFromVerifier(verifier),
+			expectedMethod:    "S256",
+		},
+		{
+			name:              "S256",
+			method:            "S256",
+			expectedChallenge: oauth2.S256ChallengeFromVerifier(verifier),
+			expectedMethod:    "S256",
+		},
+		{
+			name:              "plain",
+			method:            "plain",
+			expectedChallenge: verifier,
+			expectedMethod:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			cfg := oauth2.Config{Endpoint: oauth2.Endpoint{AuthURL: "https://example.com/auth"}}
+			u, err := url.Parse(cfg.AuthCodeURL("state", pkceChallengeOption(tt.method, verifier)))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedChallenge, u.Query().Get("code_challenge"))
+			assert.Equal(t, tt.expectedMethod, u.Query().Get("code_challenge_method"))
+		})
+	}
+}
+
+func TestMaxAgeOption(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name           string
+		sso            *model.ProjectSSOConfig
+		expectedMaxAge string
+	}{
+		{
+			name: "max age",
+			sso: &model.ProjectSSOConfig{
+				Provider: model.ProjectSSOConfig_OIDC,
+				Oidc:     &model.ProjectSSOConfig_Oidc{MaxAge: 300},
+			},
+			expectedMaxAge: "300",
+		},
+		{
+			name: "no max age",
+			sso: &model.ProjectSSOConfig{
+				Provider: model.ProjectSSOConfig_OIDC,
+				Oidc:     &model.ProjectSSOConfig_Oidc{},
+			},
+		},
+		{
+			name: "not oidc",
+			sso:  &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_GITHUB},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opt := maxAgeOption(tt.sso)
+			if tt.expectedMaxAge == "" {
+				assert.Nil(t, opt)
+				return
+			}
+			cfg := oauth2.Config{Endpoint: oauth2.Endpoint{AuthURL: "https://example.com/auth"}}
+			u, err := url.Parse(cfg.AuthCodeURL("state", opt))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedMaxAge, u.Query().Get("max_age"))
+		})
+	}
+}
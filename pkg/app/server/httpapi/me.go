@@ -0,0 +1,106 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+// WithSessionVerifier enables the session introspection endpoint /auth/me,
+// which verifies the token cookie by the given verifier.
+func WithSessionVerifier(verifier jwt.Verifier) Option {
+	return func(h *authHandler) {
+		h.sessionVerifier = verifier
+	}
+}
+
+// sessionInfo is the session of the logged-in user returned by /auth/me.
+type sessionInfo struct {
+	Username  string      `json:"username"`
+	AvatarURL string      `json:"avatarUrl"`
+	Role      sessionRole `json:"role"`
+	Groups    []string    `json:"groups"`
+	// ExpiresAt is the exp claim of the token in seconds since the epoch.
+	ExpiresAt int64 `json:"exp"`
+}
+
+type sessionRole struct {
+	ProjectID        string   `json:"projectId"`
+	ProjectRBACRoles []string `json:"projectRbacRoles"`
+}
+
+func newSessionInfo(claims *jwt.Claims) sessionInfo {
+	info := sessionInfo{
+		Username:  claims.Subject,
+		AvatarURL: claims.AvatarURL,
+		Role: sessionRole{
+			ProjectID:        claims.Role.ProjectId,
+			ProjectRBACRoles: claims.Role.ProjectRbacRoles,
+		},
+		Groups: claims.Groups,
+	}
+	// The empty lists are returned as [] rather than null for the web console.
+	if info.Role.ProjectRBACRoles == nil {
+		info.Role.ProjectRBACRoles = []string{}
+	}
+	if info.Groups == nil {
+		info.Groups = []string{}
+	}
+	if claims.ExpiresAt != nil {
+		info.ExpiresAt = claims.ExpiresAt.Unix()
+	}
+	return info
+}
+
+// handleMe returns the session of the logged-in user read from the token cookie,
+// so that the web console does not decode the token by itself.
+// It responds 401 without body unless the token is valid.
+func (h *authHandler) handleMe(w http.ResponseWriter, r *http.Request) {
+	setNoCacheHeaders(w)
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.sessionVerifier == nil {
+		http.Error(w, "Session introspection is not enabled", http.StatusNotFound)
+		return
+	}
+	c, err := r.Cookie(h.cookies.tokenName())
+	if err != nil || c.Value == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	claims, err := h.sessionVerifier.Verify(c.Value)
+	if err != nil {
+		h.logger.Debug("auth-handler: unable to verify the session", zap.Error(err))
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if h.sessionBinding.enabled() {
+		if err := checkSessionBinding(claims.Binding, h.clientIP(r), r.UserAgent()); err != nil {
+			h.logger.Debug("auth-handler: the session is used from another client", zap.Error(err))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(newSessionInfo(claims))
+}
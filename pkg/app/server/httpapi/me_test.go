@@ -0,0 +1,107 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestHandleMe(t *testing.T) {
+	t.Parallel()
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("secret"), 0600))
+	signer, err := jwt.NewSigner(jwtgo.SigningMethodHS256, keyFile)
+	require.NoError(t, err)
+	verifier, err := jwt.NewVerifier(jwtgo.SigningMethodHS256, keyFile)
+	require.NoError(t, err)
+
+	h := &authHandler{
+		sessionVerifier: verifier,
+		logger:          zap.NewNop(),
+	}
+	sign := func(claims *jwt.Claims) string {
+		token, err := signer.Sign(claims)
+		require.NoError(t, err)
+		return token
+	}
+	call := func(method, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, mePath, nil)
+		if token != "" {
+			req.AddCookie(&http.Cookie{Name: jwt.SignedTokenKey, Value: token})
+		}
+		rec := httptest.NewRecorder()
+		h.handleMe(rec, req)
+		return rec
+	}
+
+	claims := jwt.NewClaims("user", "https://example.com/avatar.png", time.Hour, model.Role{
+		ProjectId:        "project",
+		ProjectRbacRoles: []string{"Admin"},
+	}, "org/team")
+	rec := call(http.MethodGet, sign(claims))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	var info map[string]interface{}
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&info))
+	assert.Equal(t, map[string]interface{}{
+		"username":  "user",
+		"avatarUrl": "https://example.com/avatar.png",
+		"role": map[string]interface{}{
+			"projectId":        "project",
+			"projectRbacRoles": []interface{}{"Admin"},
+		},
+		"groups": []interface{}{"org/team"},
+		"exp":    float64(claims.ExpiresAt.Unix()),
+	}, info)
+
+	// The empty lists are not null.
+	rec = call(http.MethodGet, sign(jwt.NewClaims("user", "", time.Hour, model.Role{ProjectId: "project"})))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"projectRbacRoles":[]`)
+	assert.Contains(t, rec.Body.String(), `"groups":[]`)
+
+	// The missing, invalid and expired tokens are rejected without body.
+	expired := jwt.NewClaims("user", "", -time.Hour, model.Role{ProjectId: "project"})
+	for name, token := range map[string]string{
+		"missing": "",
+		"invalid": "invalid",
+		"expired": sign(expired),
+	} {
+		rec := call(http.MethodGet, token)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+		assert.Empty(t, rec.Body.String(), name)
+	}
+
+	assert.Equal(t, http.StatusMethodNotAllowed, call(http.MethodPost, sign(claims)).Code)
+
+	// The introspection is disabled without the verifier.
+	h.sessionVerifier = nil
+	assert.Equal(t, http.StatusNotFound, call(http.MethodGet, sign(claims)).Code)
+}
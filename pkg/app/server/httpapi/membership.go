@@ -0,0 +1,231 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/ldap"
+)
+
+const membershipLookupTimeout = 10 * time.Second
+
+// MembershipChecker looks up the logged-in users at the identity provider
+// to find the ones removed or moved out of the groups granting their roles.
+// The LDAP users are searched by the service account of the SSO configuration,
+// while the users of the OAuth providers are looked up by the tokens issued to them at login,
+// which are kept in the grant store. The tokens revoked or no longer refreshed by the provider
+// mean the user is no longer a member.
+// The users logged in via SAML, which cannot look up the users after login, and the ones
+// whose tokens were not kept, e.g. logged in before the grant store was enabled, are considered as members.
+type MembershipChecker struct {
+	h      *authHandler
+	grants *GrantStore
+	// refreshUser looks up the user of the OAuth provider by the token issued at login.
+	refreshUser func(context.Context, *model.ProjectSSOConfig, *model.Project, *oauth2.Token) (*model.User, *oauth2.Token, error)
+}
+
+// NewMembershipChecker returns a MembershipChecker looking up the users
+// by the SSO configurations of the projects, which are decrypted by the given decrypter,
+// and by the tokens of the OAuth providers kept in the given grant store.
+func NewMembershipChecker(sharedSSOConfigs map[string]*model.ProjectSSOConfig, projectGetter projectGetter, decrypter decrypter, grants *GrantStore, logger *zap.Logger) *MembershipChecker {
+	return &MembershipChecker{
+		h: &authHandler{
+			sharedSSOConfigs: sharedSSOConfigs,
+			projectGetter:    projectGetter,
+			decrypter:        decrypter,
+			newLDAPClient:    newLDAPClient,
+			logger:           logger,
+		},
+		grants:      grants,
+		refreshUser: refreshOAuthUser,
+	}
+}
+
+// WithMembershipChecker rejects refreshing the sessions of the users who are no longer members.
+func WithMembershipChecker(c jwt.MembershipChecker) Option {
+	return func(h *authHandler) {
+		h.membershipChecker = c
+	}
+}
+
+// IsMember reports whether the user of the given claims is still found by the identity provider of the login
+// and is still granted all the roles of the claims.
+func (c *MembershipChecker) IsMember(claims *jwt.Claims) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), membershipLookupTimeout)
+	defer cancel()
+
+	var (
+		member bool
+		err    error
+	)
+	switch claims.Provider {
+	case "", model.ProjectSSOConfig_SAML.String():
+		// The static admin and the users of SAML are not looked up.
+		return true, nil
+	case model.ProjectSSOConfig_LDAP.String():
+		member, err = c.isLDAPMember(ctx, claims)
+	default:
+		member, err = c.isOAuthMember(ctx, claims)
+	}
+	if err != nil || member {
+		return member, err
+	}
+
+	c.h.logger.Info("user is no longer a member of the project",
+		zap.String("user", claims.Subject),
+		zap.String("project-id", claims.Role.ProjectId),
+		zap.String("provider", claims.Provider),
+		zap.Strings("roles", claims.Role.ProjectRbacRoles),
+	)
+	return false, nil
+}
+
+// isLDAPMember reports whether the user is still found by one of the LDAP configurations of the project.
+func (c *MembershipChecker) isLDAPMember(ctx context.Context, claims *jwt.Claims) (bool, error) {
+	h := c.h
+	proj, err := h.projectGetter.Get(ctx, claims.Role.ProjectId)
+	if err != nil {
+		return false, err
+	}
+	choices, err := h.findSSOChoices(proj)
+	if err != nil {
+		return false, err
+	}
+
+	var lastErr error
+	for _, choice := range choices {
+		sso := choice.sso
+		if sso.Provider != model.ProjectSSOConfig_LDAP || sso.Ldap == nil {
+			continue
+		}
+		if !choice.shared {
+			if sso, err = h.decryptSSO(proj.Id, sso); err != nil {
+				lastErr = err
+				continue
+			}
+		}
+		cli, err := h.newLDAPClient(sso.Ldap)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		user, err := cli.Lookup(proj, claims.Subject)
+		if errors.Is(err, ldap.ErrUserNotFound) || errors.Is(err, ldap.ErrNoRole) {
+			continue
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if hasAllRoles(user.Role, claims.Role.ProjectRbacRoles) {
+			return true, nil
+		}
+	}
+	return false, lastErr
+}
+
+// isOAuthMember reports whether the user is still found by the OAuth provider of the login
+// with the token issued at login, which is replaced by the one refreshed by the provider.
+func (c *MembershipChecker) isOAuthMember(ctx context.Context, claims *jwt.Claims) (bool, error) {
+	if c.grants == nil {
+		return true, nil
+	}
+	h := c.h
+	g, err := c.grants.get(claims)
+	if err != nil {
+		return false, err
+	}
+	if g == nil {
+		return true, nil
+	}
+
+	proj, err := h.projectGetter.Get(ctx, claims.Role.ProjectId)
+	if err != nil {
+		return false, err
+	}
+	sso, shared, err := h.findSSOConfigByName(proj, g.SSOName)
+	if err != nil {
+		h.logger.Info("sso configuration of the login was removed from the project", zap.Error(err))
+		return false, nil
+	}
+	if sso.Provider.String() != claims.Provider {
+		return false, nil
+	}
+	if !shared {
+		if sso, err = h.decryptSSO(proj.Id, sso); err != nil {
+			return false, err
+		}
+	}
+
+	user, token, err := c.refreshUser(ctx, sso, proj, g.Token)
+	// Another server may have rotated the refresh token at the same time, so it is retried with the new one.
+	if isGrantRevoked(err) {
+		if latest, getErr := c.grants.get(claims); getErr == nil && latest != nil && latest.Token.RefreshToken != g.Token.RefreshToken {
+			g = latest
+			user, token, err = c.refreshUser(ctx, sso, proj, g.Token)
+		}
+	}
+	if err != nil {
+		if isTransientLookupError(err) {
+			return false, err
+		}
+		h.logger.Info("unable to look up user at the provider", zap.String("user", claims.Subject), zap.Error(err))
+		return false, nil
+	}
+	if token != nil && (token.AccessToken != g.Token.AccessToken || token.RefreshToken != g.Token.RefreshToken) {
+		g.Token = token
+		if err := c.grants.put(claims, g); err != nil {
+			h.logger.Warn("failed to keep the refreshed token of the user", zap.String("user", claims.Subject), zap.Error(err))
+		}
+	}
+	return hasAllRoles(user.Role, claims.Role.ProjectRbacRoles), nil
+}
+
+// isGrantRevoked reports whether the token endpoint of the provider rejected the refresh token.
+func isGrantRevoked(err error) bool {
+	var re *oauth2.RetrieveError
+	return errors.As(err, &re) && re.ErrorCode == "invalid_grant"
+}
+
+// isTransientLookupError reports whether the provider was unreachable or failed to respond,
+// in which case the lookup is retried by the next request instead of rejecting the user.
+func isTransientLookupError(err error) bool {
+	var re *oauth2.RetrieveError
+	if errors.As(err, &re) {
+		return re.Response == nil || re.Response.StatusCode >= 500
+	}
+	var ue *url.Error
+	return errors.As(err, &ue) || errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)
+}
+
+// hasAllRoles reports whether the given role grants all the given RBAC roles.
+func hasAllRoles(role *model.Role, roles []string) bool {
+	for _, r := range roles {
+		if !slices.Contains(role.GetProjectRbacRoles(), r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,280 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+type failingLDAPAuthenticator struct {
+	fakeLDAPAuthenticator
+}
+
+func (failingLDAPAuthenticator) Lookup(*model.Project, string) (*model.User, error) {
+	return nil, errors.New("unavailable")
+}
+
+func TestMembershipCheckerIsMember(t *testing.T) {
+	t.Parallel()
+	ldapSSO := &model.ProjectSSOConfig{
+		Provider: model.ProjectSSOConfig_LDAP,
+		Ldap:     &model.ProjectSSOConfig_Ldap{Url: "ldap://ldap.example.com"},
+	}
+	projects := fakeProjectGetter{
+		"ldap": {Id: "ldap", Sso: ldapSSO},
+		"shared": {
+			Id:                       "shared",
+			Sso:                      &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_GITHUB},
+			AdditionalSharedSsoNames: []string{"corp-ldap"},
+		},
+	}
+	newClaims := func(projectID, user, provider string, roles ...string) *jwt.Claims {
+		c := jwt.NewClaims(user, "", time.Hour, model.Role{ProjectId: projectID, ProjectRbacRoles: roles})
+		c.Provider = provider
+		return c
+	}
+	ldapProvider := model.ProjectSSOConfig_LDAP.String()
+	editor := model.BuiltinRBACRoleEditor.String()
+	admin := model.BuiltinRBACRoleAdmin.String()
+
+	testcases := []struct {
+		name        string
+		claims      *jwt.Claims
+		failing     bool
+		expected    bool
+		expectedErr bool
+	}{
+		{
+			name:     "member",
+			claims:   newClaims("ldap", "alice", ldapProvider, editor),
+			expected: true,
+		},
+		{
+			name:     "member of additional shared sso",
+			claims:   newClaims("shared", "alice", ldapProvider, editor),
+			expected: true,
+		},
+		{
+			name:   "removed user",
+			claims: newClaims("ldap", "carol", ldapProvider, editor),
+		},
+		{
+			name:   "role no longer granted",
+			claims: newClaims("ldap", "alice", ldapProvider, admin, editor),
+		},
+		{
+			name:     "oauth provider without grant store",
+			claims:   newClaims("ldap", "carol", model.ProjectSSOConfig_GITHUB.String(), admin),
+			expected: true,
+		},
+		{
+			name:     "saml",
+			claims:   newClaims("ldap", "carol", model.ProjectSSOConfig_SAML.String(), admin),
+			expected: true,
+		},
+		{
+			name:     "static admin",
+			claims:   newClaims("ldap", "admin", "", admin),
+			expected: true,
+		},
+		{
+			name:        "unknown project",
+			claims:      newClaims("deleted", "alice", ldapProvider, editor),
+			expectedErr: true,
+		},
+		{
+			name:        "lookup failure",
+			claims:      newClaims("ldap", "alice", ldapProvider, editor),
+			failing:     true,
+			expectedErr: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			c := NewMembershipChecker(map[string]*model.ProjectSSOConfig{"corp-ldap": ldapSSO}, projects, &countingDecrypter{}, nil, zap.NewNop())
+			c.h.newLDAPClient = func(*model.ProjectSSOConfig_Ldap) (ldapAuthenticator, error) {
+				a := fakeLDAPAuthenticator{"alice": "alice-password"}
+				if tc.failing {
+					return failingLDAPAuthenticator{a}, nil
+				}
+				return a, nil
+			}
+			member, err := c.IsMember(tc.claims)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, member)
+		})
+	}
+}
+
+func TestMembershipCheckerDecryptsProjectSSO(t *testing.T) {
+	t.Parallel()
+	projects := fakeProjectGetter{
+		"ldap": {Id: "ldap", Sso: &model.ProjectSSOConfig{
+			Provider: model.ProjectSSOConfig_LDAP,
+			Ldap:     &model.ProjectSSOConfig_Ldap{Url: "ldap://ldap.example.com", BindPassword: "encrypted-bind-password"},
+		}},
+	}
+	c := NewMembershipChecker(nil, projects, &countingDecrypter{}, nil, zap.NewNop())
+	var bindPassword string
+	c.h.newLDAPClient = func(sso *model.ProjectSSOConfig_Ldap) (ldapAuthenticator, error) {
+		bindPassword = sso.BindPassword
+		return fakeLDAPAuthenticator{"alice": "alice-password"}, nil
+	}
+
+	claims := jwt.NewClaims("alice", "", time.Hour, model.Role{ProjectId: "ldap", ProjectRbacRoles: []string{model.BuiltinRBACRoleEditor.String()}})
+	claims.Provider = model.ProjectSSOConfig_LDAP.String()
+	member, err := c.IsMember(claims)
+	require.NoError(t, err)
+	assert.True(t, member)
+	assert.Equal(t, "bind-password", bindPassword)
+}
+
+func TestMembershipCheckerLooksUpOAuthUsers(t *testing.T) {
+	t.Parallel()
+	projects := fakeProjectGetter{
+		"github": {Id: "github", Sso: &model.ProjectSSOConfig{
+			Provider: model.ProjectSSOConfig_GITHUB,
+			Github:   &model.ProjectSSOConfig_GitHub{ClientId: "client-id", ClientSecret: "encrypted-client-secret"},
+		}},
+	}
+	githubProvider := model.ProjectSSOConfig_GITHUB.String()
+	editor := model.BuiltinRBACRoleEditor.String()
+	viewer := model.BuiltinRBACRoleViewer.String()
+	invalidGrant := &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusBadRequest}, ErrorCode: "invalid_grant"}
+
+	testcases := []struct {
+		name     string
+		provider string
+		grant    *grant
+		// lookup returns the roles of the user found by the given refresh token.
+		lookup               func(refreshToken string, grants *GrantStore, claims *jwt.Claims) ([]string, error)
+		expected             bool
+		expectedErr          bool
+		expectedRefreshToken string
+	}{
+		{
+			name:  "member",
+			grant: &grant{Token: &oauth2.Token{AccessToken: "at", RefreshToken: "rt"}},
+			lookup: func(string, *GrantStore, *jwt.Claims) ([]string, error) {
+				return []string{editor}, nil
+			},
+			expected:             true,
+			expectedRefreshToken: "rt-refreshed",
+		},
+		{
+			name:  "role no longer granted",
+			grant: &grant{Token: &oauth2.Token{AccessToken: "at", RefreshToken: "rt"}},
+			lookup: func(string, *GrantStore, *jwt.Claims) ([]string, error) {
+				return []string{viewer}, nil
+			},
+		},
+		{
+			name:     "logged in before keeping grants",
+			expected: true,
+		},
+		{
+			name:  "revoked grant",
+			grant: &grant{Token: &oauth2.Token{AccessToken: "at", RefreshToken: "rt"}},
+			lookup: func(string, *GrantStore, *jwt.Claims) ([]string, error) {
+				return nil, invalidGrant
+			},
+		},
+		{
+			name:  "refresh token rotated by another server",
+			grant: &grant{Token: &oauth2.Token{AccessToken: "at", RefreshToken: "rt"}},
+			lookup: func(refreshToken string, grants *GrantStore, claims *jwt.Claims) ([]string, error) {
+				if refreshToken == "rt" {
+					require.NoError(t, grants.put(claims, &grant{Token: &oauth2.Token{AccessToken: "at-2", RefreshToken: "rt-2"}}))
+					return nil, invalidGrant
+				}
+				return []string{editor}, nil
+			},
+			expected:             true,
+			expectedRefreshToken: "rt-2-refreshed",
+		},
+		{
+			name:  "provider unavailable",
+			grant: &grant{Token: &oauth2.Token{AccessToken: "at", RefreshToken: "rt"}},
+			lookup: func(string, *GrantStore, *jwt.Claims) ([]string, error) {
+				return nil, &oauth2.RetrieveError{Response: &http.Response{StatusCode: http.StatusServiceUnavailable}}
+			},
+			expectedErr: true,
+		},
+		{
+			name:  "sso configuration removed",
+			grant: &grant{SSOName: "deleted", Token: &oauth2.Token{AccessToken: "at", RefreshToken: "rt"}},
+		},
+		{
+			name:     "provider changed",
+			provider: model.ProjectSSOConfig_OIDC.String(),
+			grant:    &grant{Token: &oauth2.Token{AccessToken: "at", RefreshToken: "rt"}},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			grants := NewGrantStore(memorycache.NewCache(), fakeEncrypter{})
+			c := NewMembershipChecker(nil, projects, &countingDecrypter{}, grants, zap.NewNop())
+			claims := jwt.NewClaims("alice", "", time.Hour, model.Role{ProjectId: "github", ProjectRbacRoles: []string{editor}})
+			claims.Provider = githubProvider
+			if tc.provider != "" {
+				claims.Provider = tc.provider
+			}
+			if tc.grant != nil {
+				require.NoError(t, grants.put(claims, tc.grant))
+			}
+			c.refreshUser = func(_ context.Context, sso *model.ProjectSSOConfig, _ *model.Project, token *oauth2.Token) (*model.User, *oauth2.Token, error) {
+				assert.Equal(t, "client-secret", sso.Github.ClientSecret)
+				roles, err := tc.lookup(token.RefreshToken, grants, claims)
+				if err != nil {
+					return nil, nil, err
+				}
+				refreshed := &oauth2.Token{AccessToken: token.AccessToken + "-refreshed", RefreshToken: token.RefreshToken + "-refreshed"}
+				return &model.User{Username: "alice", Role: &model.Role{ProjectId: "github", ProjectRbacRoles: roles}}, refreshed, nil
+			}
+
+			member, err := c.IsMember(claims)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, member)
+			if tc.expectedRefreshToken != "" {
+				g, err := grants.get(claims)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedRefreshToken, g.Token.RefreshToken)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const nonceSize = 32
+
+func nonceEnabled(sso *model.ProjectSSOConfig) bool {
+	return sso.Provider == model.ProjectSSOConfig_OIDC && sso.Oidc != nil && !sso.Oidc.DisableNonceValidation
+}
+
+func generateNonce(random io.Reader) (string, error) {
+	b, err := randomBytes(random, nonceSize)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// signNonce returns the nonce with its HMAC signature appended,
+// so that it can be stored in a cookie without being tampered with.
+func signNonce(key, nonce string) string {
+	return nonce + "." + hmacSignature(key, nonce)
+}
+
+// verifySignedNonce checks the signature of the given value and returns the nonce.
+func verifySignedNonce(key, value string) (string, error) {
+	nonce, sig, ok := strings.Cut(value, ".")
+	if !ok || nonce == "" {
+		return "", fmt.Errorf("malformed nonce")
+	}
+	if !hmac.Equal([]byte(sig), []byte(hmacSignature(key, nonce))) {
+		return "", fmt.Errorf("invalid nonce signature")
+	}
+	return nonce, nil
+}
+
+// hmacSignature returns the HMAC signature of the value to detect tampering of cookies.
+func hmacSignature(key, value string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
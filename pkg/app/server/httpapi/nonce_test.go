@@ -0,0 +1,71 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifySignedNonce(t *testing.T) {
+	t.Parallel()
+	nonce, err := generateNonce(rand.Reader)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name      string
+		value     string
+		expected  string
+		expectErr bool
+	}{
+		{
+			name:     "valid",
+			value:    signNonce("key", nonce),
+			expected: nonce,
+		},
+		{
+			name:      "signed with another key",
+			value:     signNonce("another-key", nonce),
+			expectErr: true,
+		},
+		{
+			name:      "tampered nonce",
+			value:     "tampered" + signNonce("key", nonce),
+			expectErr: true,
+		},
+		{
+			name:      "missing signature",
+			value:     nonce,
+			expectErr: true,
+		},
+		{
+			name:      "empty",
+			value:     "",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := verifySignedNonce("key", tt.value)
+			assert.Equal(t, tt.expectErr, err != nil)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
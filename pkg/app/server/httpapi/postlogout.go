@@ -0,0 +1,60 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"fmt"
+	"net/url"
+)
+
+// ParsePostLogoutRedirect checks whether the given page to land on after logout is allowed by the given allowlist
+// in the same way as the targets to redirect to after login, which prevents the open redirects,
+// and returns it in the normalized form. Empty means the login page.
+func ParsePostLogoutRedirect(target string, allowlist ReturnToAllowlist) (string, error) {
+	if target == "" {
+		return "", nil
+	}
+	t, ok := allowlist.validate(target)
+	if !ok {
+		return "", fmt.Errorf("post logout redirect %q is not allowed by the return_to allowlist", target)
+	}
+	return t, nil
+}
+
+// WithPostLogoutRedirect makes the users land on the given page after logout instead of the login page.
+// The target should be parsed by ParsePostLogoutRedirect in advance.
+func WithPostLogoutRedirect(target string) Option {
+	return func(h *authHandler) {
+		h.postLogoutRedirect = target
+	}
+}
+
+// postLogoutRedirectURL returns the page to land on after logout.
+func (h *authHandler) postLogoutRedirectURL() string {
+	if h.postLogoutRedirect == "" {
+		return rootPath
+	}
+	return h.postLogoutRedirect
+}
+
+// endSessionRedirectURI returns the post_logout_redirect_uri sent to the end session endpoint of the OIDC provider,
+// which is the page to land on after logout if it is an absolute URL. Empty means the one of the SSO configuration.
+func (h *authHandler) endSessionRedirectURI() string {
+	u, err := url.Parse(h.postLogoutRedirect)
+	if err != nil || !u.IsAbs() {
+		return ""
+	}
+	return h.postLogoutRedirect
+}
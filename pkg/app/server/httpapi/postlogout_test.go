@@ -0,0 +1,106 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestParsePostLogoutRedirect(t *testing.T) {
+	t.Parallel()
+	allowlist, err := ParseReturnToAllowlist([]string{"/welcome", "portal.example.com/*"})
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name      string
+		target    string
+		allowlist ReturnToAllowlist
+		expected  string
+		expectErr bool
+	}{
+		{name: "empty", target: "", expected: ""},
+		{name: "relative path without allowlist", target: "/welcome?from=logout", expected: "/welcome?from=logout"},
+		{name: "absolute url without allowlist", target: "https://portal.example.com/", expectErr: true},
+		{name: "scheme relative url", target: "//evil.com", expectErr: true},
+		{name: "allowed path", target: "/welcome", allowlist: allowlist, expected: "/welcome"},
+		{name: "not allowed path", target: "/applications", allowlist: allowlist, expectErr: true},
+		{name: "allowed absolute url", target: "https://portal.example.com/home", allowlist: allowlist, expected: "https://portal.example.com/home"},
+		{name: "not allowed host", target: "https://evil.com/home", allowlist: allowlist, expectErr: true},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ParsePostLogoutRedirect(tc.target, tc.allowlist)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestHandleLogoutPostLogoutRedirect(t *testing.T) {
+	t.Parallel()
+	newHandler := func(target string) *authHandler {
+		return &authHandler{
+			sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+				"oidc": {
+					Provider: model.ProjectSSOConfig_OIDC,
+					Oidc: &model.ProjectSSOConfig_Oidc{
+						ClientId:              "client-id",
+						EndSessionEndpoint:    "https://idp.example.com/logout",
+						PostLogoutRedirectUri: "https://pipecd.example.com/",
+					},
+				},
+			},
+			projectGetter: fakeProjectGetter{
+				"oidc-project": {Id: "oidc-project", SharedSsoName: "oidc"},
+			},
+			postLogoutRedirect: target,
+			logger:             zap.NewNop(),
+		}
+	}
+	logout := func(h *authHandler, cookies ...*http.Cookie) string {
+		req := httptest.NewRequest(http.MethodGet, logoutPath, nil)
+		for _, c := range cookies {
+			req.AddCookie(c)
+		}
+		rec := httptest.NewRecorder()
+		h.handleLogout(rec, req)
+		require.Equal(t, http.StatusFound, rec.Code)
+		return rec.Header().Get("Location")
+	}
+	idToken := &http.Cookie{Name: idTokenCookieKey, Value: "oidc-project:id-token"}
+
+	assert.Equal(t, rootPath, logout(newHandler("")))
+
+	h := newHandler("/welcome")
+	assert.Equal(t, "/welcome", logout(h))
+	// The relative page is not sent to the provider.
+	assert.Equal(t, "https://idp.example.com/logout?client_id=client-id&id_token_hint=id-token&post_logout_redirect_uri=https%3A%2F%2Fpipecd.example.com%2F", logout(h, idToken))
+
+	h = newHandler("https://portal.example.com/")
+	assert.Equal(t, "https://portal.example.com/", logout(h))
+	assert.Equal(t, "https://idp.example.com/logout?client_id=client-id&id_token_hint=id-token&post_logout_redirect_uri=https%3A%2F%2Fportal.example.com%2F", logout(h, idToken))
+}
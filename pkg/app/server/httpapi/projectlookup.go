@@ -0,0 +1,102 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/app/server/httpapi/httpapimetrics"
+	"github.com/pipe-cd/pipecd/pkg/backoff"
+	"github.com/pipe-cd/pipecd/pkg/datastore"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	// defaultProjectLookupTimeout bounds each attempt to look up the project,
+	// so that the degraded datastore is retried instead of consuming the whole deadline of the login.
+	defaultProjectLookupTimeout = 2 * time.Second
+	projectLookupAttempts       = 3
+	projectLookupBackoffBase    = 100 * time.Millisecond
+	projectLookupBackoffMax     = time.Second
+
+	projectLookupNotFound = "not_found"
+	projectLookupTimeout  = "timeout"
+	projectLookupError    = "error"
+)
+
+// errProjectUnavailable is returned when the project could not be looked up, e.g. the datastore is degraded,
+// which is told to the user as a temporary error to retry instead of the project not found.
+var errProjectUnavailable = errors.New("project temporarily unavailable")
+
+// getProject looks up the project of the login. Each attempt is bounded by its own short timeout
+// and retried with backoff unless the project is not found.
+func (h *authHandler) getProject(ctx context.Context, projectID string) (*model.Project, error) {
+	timeout := h.projectLookupTimeout
+	if timeout <= 0 {
+		timeout = defaultProjectLookupTimeout
+	}
+	retry := backoff.NewRetry(projectLookupAttempts, backoff.NewExponential(projectLookupBackoffBase, projectLookupBackoffMax))
+	var lastErr error
+	v, err := retry.Do(ctx, func() (interface{}, error) {
+		attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		proj, err := h.projectGetter.Get(attemptCtx, projectID)
+		if err == nil {
+			return proj, nil
+		}
+		lastErr = err
+		if errors.Is(err, datastore.ErrNotFound) {
+			return nil, backoff.NewError(err, false)
+		}
+		h.logger.Warn("auth-handler: failed to look up project",
+			zap.String("project-id", projectID),
+			zap.Int("attempt", retry.Calls()),
+			zap.Error(err),
+		)
+		return nil, err
+	})
+	if err == nil {
+		proj := v.(*model.Project)
+		h.projectThrottles.update(proj, h.now())
+		return proj, nil
+	}
+	if lastErr == nil {
+		// The context was done before any attempt.
+		lastErr = err
+	}
+	switch {
+	case errors.Is(lastErr, datastore.ErrNotFound):
+		httpapimetrics.IncProjectLookupFailures(projectLookupNotFound)
+		return nil, lastErr
+	case errors.Is(lastErr, context.DeadlineExceeded):
+		httpapimetrics.IncProjectLookupFailures(projectLookupTimeout)
+	default:
+		httpapimetrics.IncProjectLookupFailures(projectLookupError)
+	}
+	return nil, fmt.Errorf("%w: %w", errProjectUnavailable, lastErr)
+}
+
+// projectLookupErrorCode returns the error code and the message telling the user the failure of getProject.
+func projectLookupErrorCode(projectID string, err error) (loginFailureReason, errorCode, string) {
+	if errors.Is(err, errProjectUnavailable) {
+		return failureReasonUnavailable, errCodeUnavailable, "Service temporarily unavailable, please retry"
+	}
+	return failureReasonProjectNotFound, errCodeProjectNotFound, fmt.Sprintf("Unable to find project %s", projectID)
+}
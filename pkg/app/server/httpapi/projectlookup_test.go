@@ -0,0 +1,124 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/datastore"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+// flakyProjectGetter fails the given number of lookups by the given error before succeeding.
+type flakyProjectGetter struct {
+	failures int32
+	err      error
+	// block makes the failed lookups wait until their context is done.
+	block bool
+	calls atomic.Int32
+}
+
+func (g *flakyProjectGetter) Get(ctx context.Context, id string) (*model.Project, error) {
+	if g.calls.Add(1) > g.failures {
+		return &model.Project{Id: id}, nil
+	}
+	if g.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return nil, g.err
+}
+
+func TestGetProject(t *testing.T) {
+	t.Parallel()
+	testcases := []struct {
+		name          string
+		getter        *flakyProjectGetter
+		expectedCalls int32
+		unavailable   bool
+		notFound      bool
+	}{
+		{
+			name:          "found",
+			getter:        &flakyProjectGetter{},
+			expectedCalls: 1,
+		},
+		{
+			name:          "recovered by retry",
+			getter:        &flakyProjectGetter{failures: 2, err: errors.New("connection reset")},
+			expectedCalls: 3,
+		},
+		{
+			name:          "not found is not retried",
+			getter:        &flakyProjectGetter{failures: 10, err: datastore.ErrNotFound},
+			expectedCalls: 1,
+			notFound:      true,
+		},
+		{
+			name:          "unavailable",
+			getter:        &flakyProjectGetter{failures: 10, err: errors.New("connection reset")},
+			expectedCalls: projectLookupAttempts,
+			unavailable:   true,
+		},
+		{
+			name:          "timeout",
+			getter:        &flakyProjectGetter{failures: 10, block: true},
+			expectedCalls: projectLookupAttempts,
+			unavailable:   true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			h := &authHandler{
+				projectGetter:        tc.getter,
+				projectLookupTimeout: 10 * time.Millisecond,
+				logger:               zap.NewNop(),
+			}
+			proj, err := h.getProject(context.Background(), "project")
+			assert.Equal(t, tc.expectedCalls, tc.getter.calls.Load())
+			if !tc.unavailable && !tc.notFound {
+				require.NoError(t, err)
+				assert.Equal(t, "project", proj.Id)
+				return
+			}
+			assert.Equal(t, tc.unavailable, errors.Is(err, errProjectUnavailable))
+			assert.Equal(t, tc.notFound, errors.Is(err, datastore.ErrNotFound))
+		})
+	}
+}
+
+func TestProjectLookupErrorCode(t *testing.T) {
+	t.Parallel()
+	reason, code, message := projectLookupErrorCode("project", errProjectUnavailable)
+	assert.Equal(t, failureReasonUnavailable, reason)
+	assert.Equal(t, errCodeUnavailable, code)
+	assert.Equal(t, http.StatusServiceUnavailable, code.statusCode())
+	assert.Equal(t, "Service temporarily unavailable, please retry", message)
+
+	reason, code, message = projectLookupErrorCode("project", datastore.ErrNotFound)
+	assert.Equal(t, failureReasonProjectNotFound, reason)
+	assert.Equal(t, errCodeProjectNotFound, code)
+	assert.Equal(t, "Unable to find project project", message)
+}
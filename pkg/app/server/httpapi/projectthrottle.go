@@ -0,0 +1,179 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	// defaultProjectLockoutWindow and defaultProjectLockoutCooldown are used by the projects
+	// which set only the lockout threshold, unless the lockout of the control plane is configured.
+	defaultProjectLockoutWindow   = 10 * time.Minute
+	defaultProjectLockoutCooldown = 15 * time.Minute
+)
+
+// projectThrottle throttles the login callbacks of a project by the settings of the project.
+type projectThrottle struct {
+	rateLimit RateLimit
+	lockout   Lockout
+	// limiter and tracker are nil if the project sets no rate limit and no lockout respectively.
+	limiter  *keyedLimiter
+	tracker  *lockoutTracker
+	lastSeen time.Time
+}
+
+// retention returns how long the idle throttle is kept so that its lockouts and buckets are not dropped early.
+func (t *projectThrottle) retention() time.Duration {
+	return max(rateLimitIdleTTL, t.lockout.Window, t.lockout.Cooldown)
+}
+
+// projectThrottles keeps the throttles of the projects applied under the limits of the control plane.
+// The settings of a project are cached whenever the project is read through the project getter at login,
+// rather than looked up for each callback, so that the callbacks of unknown projects cost no lookup.
+// The state of the throttle is kept as long as the settings of the project are unchanged.
+type projectThrottles struct {
+	// defaultLockout gives the window and the cooldown to the projects setting only the threshold.
+	defaultLockout Lockout
+
+	mu      sync.Mutex
+	entries map[string]*projectThrottle
+}
+
+func newProjectThrottles() *projectThrottles {
+	return &projectThrottles{
+		defaultLockout: Lockout{
+			Window:   defaultProjectLockoutWindow,
+			Cooldown: defaultProjectLockoutCooldown,
+		},
+		entries: make(map[string]*projectThrottle),
+	}
+}
+
+// projectThrottleSettings returns the rate limit and the lockout set by the given project.
+// The lockout window and cooldown which are not set fall back to the given default.
+func projectThrottleSettings(p *model.Project, defaultLockout Lockout) (RateLimit, Lockout) {
+	limit := RateLimit{
+		RequestsPerSecond: float64(p.LoginCallbackRateLimit) / 60,
+		Burst:             int(p.LoginCallbackRateLimitBurst),
+	}
+	if limit.Burst == 0 {
+		limit.Burst = int(p.LoginCallbackRateLimit)
+	}
+	lockout := Lockout{
+		Threshold: int(p.LoginLockoutThreshold),
+		Window:    time.Duration(p.LoginLockoutWindow) * time.Second,
+		Cooldown:  time.Duration(p.LoginLockoutCooldown) * time.Second,
+	}
+	if lockout.Window == 0 {
+		lockout.Window = defaultLockout.Window
+	}
+	if lockout.Cooldown == 0 {
+		lockout.Cooldown = defaultLockout.Cooldown
+	}
+	return limit, lockout
+}
+
+// update caches the settings of the given project. The throttle is renewed only if the settings were changed.
+func (t *projectThrottles) update(p *model.Project, now time.Time) {
+	if t == nil {
+		return
+	}
+	limit, lockout := projectThrottleSettings(p, t.defaultLockout)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for id, e := range t.entries {
+		if now.Sub(e.lastSeen) >= e.retention() {
+			delete(t.entries, id)
+		}
+	}
+	if e, ok := t.entries[p.Id]; ok && e.rateLimit == limit && e.lockout == lockout {
+		e.lastSeen = now
+		return
+	}
+	if !limit.enabled() && !lockout.enabled() {
+		delete(t.entries, p.Id)
+		return
+	}
+	e := &projectThrottle{
+		rateLimit: limit,
+		lockout:   lockout,
+		lastSeen:  now,
+	}
+	if limit.enabled() {
+		e.limiter = newKeyedLimiter(limit)
+	}
+	if lockout.enabled() {
+		e.tracker = newLockoutTracker(lockout)
+		// The gauge counts the lockouts of the control plane only.
+		e.tracker.setActive = nil
+	}
+	t.entries[p.Id] = e
+}
+
+// get returns the throttle of the given project. Nil means the project sets no throttle
+// or has not been read yet, then only the limits of the control plane are applied.
+func (t *projectThrottles) get(projectID string) *projectThrottle {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.entries[projectID]
+}
+
+// allowProjectCallback reports whether the callback to the given project is allowed by the settings of the project.
+// It responds 429 to the rejected request.
+func (h *authHandler) allowProjectCallback(w http.ResponseWriter, r *http.Request, path, projectID string) bool {
+	t := h.projectThrottles.get(projectID)
+	if t == nil {
+		return true
+	}
+	if t.limiter != nil && !t.limiter.allow(projectID) {
+		h.handleRateLimited(w, r, t.limiter.retryAfter(), path, "project_setting")
+		return false
+	}
+	if t.tracker != nil {
+		if d := t.tracker.lockedFor(h.clientIP(r)); d > 0 {
+			h.handleRateLimited(w, r, retryAfterSeconds(d), path, "project_lockout")
+			return false
+		}
+	}
+	return true
+}
+
+// recordProjectCallbackFailure counts the failure of the callback to the given project
+// towards the lockout of the project for the client IP.
+func (h *authHandler) recordProjectCallbackFailure(projectID, ip string) {
+	t := h.projectThrottles.get(projectID)
+	if t == nil || t.tracker == nil {
+		return
+	}
+	if t.tracker.fail(ip) {
+		h.logger.Warn("auth-handler: locked out the client of the project after too many failed callbacks",
+			zap.String("project-id", projectID),
+			zap.String("client-ip", ip),
+			zap.Duration("cooldown", t.lockout.Cooldown),
+		)
+	}
+}
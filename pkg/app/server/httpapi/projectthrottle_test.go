@@ -0,0 +1,153 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestProjectThrottleSettings(t *testing.T) {
+	t.Parallel()
+	defaults := Lockout{Window: time.Minute, Cooldown: time.Hour}
+
+	limit, lockout := projectThrottleSettings(&model.Project{}, defaults)
+	assert.False(t, limit.enabled())
+	assert.False(t, lockout.enabled())
+
+	limit, lockout = projectThrottleSettings(&model.Project{
+		LoginCallbackRateLimit: 120,
+		LoginLockoutThreshold:  3,
+	}, defaults)
+	assert.Equal(t, RateLimit{RequestsPerSecond: 2, Burst: 120}, limit)
+	assert.Equal(t, Lockout{Threshold: 3, Window: time.Minute, Cooldown: time.Hour}, lockout)
+
+	limit, lockout = projectThrottleSettings(&model.Project{
+		LoginCallbackRateLimit:      60,
+		LoginCallbackRateLimitBurst: 5,
+		LoginLockoutThreshold:       3,
+		LoginLockoutWindow:          30,
+		LoginLockoutCooldown:        300,
+	}, defaults)
+	assert.Equal(t, RateLimit{RequestsPerSecond: 1, Burst: 5}, limit)
+	assert.Equal(t, Lockout{Threshold: 3, Window: 30 * time.Second, Cooldown: 5 * time.Minute}, lockout)
+}
+
+func TestProjectThrottlesUpdate(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	throttles := newProjectThrottles()
+
+	throttles.update(&model.Project{Id: "none"}, now)
+	assert.Nil(t, throttles.get("none"))
+
+	p := &model.Project{Id: "project", LoginCallbackRateLimit: 1, LoginLockoutThreshold: 1}
+	throttles.update(p, now)
+	th := throttles.get("project")
+	require.NotNil(t, th)
+	require.NotNil(t, th.limiter)
+	require.NotNil(t, th.tracker)
+	th.tracker.fail("192.0.2.1")
+
+	// The state is kept while the settings are unchanged.
+	throttles.update(p, now)
+	assert.Same(t, th, throttles.get("project"))
+	assert.NotZero(t, throttles.get("project").tracker.lockedFor("192.0.2.1"))
+
+	// The changed settings renew the throttle.
+	p.LoginLockoutThreshold = 2
+	throttles.update(p, now)
+	assert.NotSame(t, th, throttles.get("project"))
+	assert.Zero(t, throttles.get("project").tracker.lockedFor("192.0.2.1"))
+
+	// Unsetting the settings removes the throttle.
+	throttles.update(&model.Project{Id: "project"}, now)
+	assert.Nil(t, throttles.get("project"))
+
+	// The idle throttles are removed after their lockouts are over.
+	throttles.update(&model.Project{Id: "idle", LoginCallbackRateLimit: 1}, now)
+	throttles.update(&model.Project{Id: "other"}, now.Add(defaultProjectLockoutCooldown-time.Second))
+	assert.NotNil(t, throttles.get("idle"))
+	throttles.update(&model.Project{Id: "other"}, now.Add(defaultProjectLockoutCooldown))
+	assert.Nil(t, throttles.get("idle"))
+
+	// Nil means no throttle.
+	var nilThrottles *projectThrottles
+	nilThrottles.update(p, now)
+	assert.Nil(t, nilThrottles.get("project"))
+}
+
+func TestHandleCallbackProjectThrottle(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		stateKey: "state-key",
+		projectGetter: fakeProjectGetter{
+			"limited": {Id: "limited", LoginCallbackRateLimit: 1},
+			"locked":  {Id: "locked", LoginLockoutThreshold: 2, LoginLockoutCooldown: 90},
+			"default": {Id: "default"},
+		},
+		projectThrottles: newProjectThrottles(),
+		auditRecorder:    nopAuditRecorder{},
+		clock:            realClock{},
+		logger:           zap.NewNop(),
+	}
+	// The control plane allows more than the project.
+	WithCallbackRateLimit(RateLimit{}, RateLimit{RequestsPerSecond: 100, Burst: 100})(h)
+
+	call := func(project, ip string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, callbackPath+"?state=invalid:project%3D"+project, nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+		h.handleCallback(rec, req)
+		return rec
+	}
+
+	// The settings are not applied until the project is read at login.
+	for i := 0; i < 3; i++ {
+		assert.NotEqual(t, http.StatusTooManyRequests, call("limited", "192.0.2.1").Code)
+	}
+	for _, id := range []string{"limited", "locked", "default"} {
+		_, err := h.getProject(context.Background(), id)
+		require.NoError(t, err)
+	}
+
+	assert.NotEqual(t, http.StatusTooManyRequests, call("limited", "192.0.2.1").Code)
+	rec := call("limited", "192.0.2.2")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "61", rec.Header().Get("Retry-After"))
+
+	assert.NotEqual(t, http.StatusTooManyRequests, call("locked", "192.0.2.1").Code)
+	assert.NotEqual(t, http.StatusTooManyRequests, call("locked", "192.0.2.1").Code)
+	rec = call("locked", "192.0.2.1")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "90", rec.Header().Get("Retry-After"))
+	// The lockout of a project does not affect the other clients and projects.
+	assert.NotEqual(t, http.StatusTooManyRequests, call("locked", "192.0.2.2").Code)
+	assert.NotEqual(t, http.StatusTooManyRequests, call("default", "192.0.2.1").Code)
+
+	// The projects setting nothing fall back to the limits of the control plane.
+	for i := 0; i < 5; i++ {
+		assert.NotEqual(t, http.StatusTooManyRequests, call("default", "192.0.2.3").Code)
+	}
+}
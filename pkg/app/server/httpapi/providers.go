@@ -0,0 +1,118 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+// ssoProvider is an SSO configuration of the project returned by /auth/providers.
+// It contains nothing but what the login page shows, so the client credentials are never exposed.
+type ssoProvider struct {
+	// Name is the name of the additional shared SSO configuration, or empty for the main one.
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	DisplayName string `json:"displayName"`
+	Icon        string `json:"icon,omitempty"`
+	// LoginURL is the path to post the login form to, along with LoginParams.
+	LoginURL    string            `json:"loginUrl"`
+	LoginParams map[string]string `json:"loginParams"`
+	// Credentials reports whether the login form requires the username and the password, e.g. for LDAP.
+	Credentials bool `json:"credentials"`
+}
+
+type ssoProvidersResponse struct {
+	Project   string        `json:"project"`
+	Providers []ssoProvider `json:"providers"`
+}
+
+func newSSOProvider(projectID string, c ssoChoice) ssoProvider {
+	p := ssoProvider{
+		Name:        c.name,
+		Type:        c.sso.Provider.String(),
+		DisplayName: ssoChoiceLabel(c),
+		Icon:        ssoIconURL(c.sso.Icon),
+		LoginURL:    loginPath,
+		LoginParams: map[string]string{
+			projectFormKey: projectID,
+			ssoFormKey:     c.name,
+		},
+	}
+	if c.sso.Provider == model.ProjectSSOConfig_LDAP {
+		p.LoginURL = ldapLoginPath
+		p.Credentials = true
+	}
+	return p
+}
+
+// handleProviders returns the SSO configurations of the project given by the project query parameter,
+// which lets the web console show the providers to choose before login, so it requires no session.
+func (h *authHandler) handleProviders(w http.ResponseWriter, r *http.Request) {
+	setNoCacheHeaders(w)
+	if r.Method != http.MethodGet {
+		h.handleProvidersError(w, r, "", errCodeMethodNotAllowed, "Method not allowed", nil)
+		return
+	}
+	projectID := r.URL.Query().Get(projectFormKey)
+	if projectID == "" {
+		h.handleProvidersError(w, r, "", errCodeInvalidRequest, "Missing project id", nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	proj, err := h.getProject(ctx, projectID)
+	if err != nil {
+		_, code, message := projectLookupErrorCode(projectID, err)
+		h.handleProvidersError(w, r, projectID, code, message, err)
+		return
+	}
+	choices, err := h.findSSOChoices(proj)
+	if err != nil {
+		h.handleProvidersError(w, r, proj.Id, errCodeInvalidSSOConfig, fmt.Sprintf("Invalid SSO configuration: %v", err), nil)
+		return
+	}
+
+	resp := ssoProvidersResponse{
+		Project:   proj.Id,
+		Providers: make([]ssoProvider, 0, len(choices)),
+	}
+	for _, c := range choices {
+		resp.Providers = append(resp.Providers, newSSOProvider(proj.Id, c))
+	}
+	w.Header().Set("Content-Type", jsonContentType)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		h.logger.Error("auth-handler: failed to write the sso providers", zap.Error(err))
+	}
+}
+
+// handleProvidersError responds the error in JSON regardless of the Accept header since the endpoint is an API.
+func (h *authHandler) handleProvidersError(w http.ResponseWriter, r *http.Request, projectID string, code errorCode, responseMessage string, err error) {
+	correlationID := uuid.New().String()
+	h.logError(r, projectID, "", code, responseMessage, correlationID, err)
+	if err := writeJSONError(w, code, responseMessage, correlationID); err != nil {
+		h.logger.Error("auth-handler: failed to write error response", zap.Error(err))
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestHandleProviders(t *testing.T) {
+	t.Parallel()
+	h := newChooserTestHandler()
+	h.sharedSSOConfigs["github"].Github.ClientSecret = "github-secret"
+	call := func(method, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, nil)
+		rec := httptest.NewRecorder()
+		h.handleProviders(rec, req)
+		return rec
+	}
+
+	rec := call(http.MethodGet, providersPath+"?project=multi")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, jsonContentType, rec.Header().Get("Content-Type"))
+	assert.Equal(t, "no-store", rec.Header().Get("Cache-Control"))
+	assert.NotContains(t, rec.Body.String(), "github-secret")
+	assert.NotContains(t, rec.Body.String(), "corp-client")
+
+	var resp ssoProvidersResponse
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	assert.Equal(t, ssoProvidersResponse{
+		Project: "multi",
+		Providers: []ssoProvider{
+			{
+				Type:        "GITHUB",
+				DisplayName: "GitHub",
+				LoginURL:    loginPath,
+				LoginParams: map[string]string{projectFormKey: "multi", ssoFormKey: ""},
+			},
+			{
+				Name:        "google",
+				Type:        "GOOGLE",
+				DisplayName: "Google (google)",
+				LoginURL:    loginPath,
+				LoginParams: map[string]string{projectFormKey: "multi", ssoFormKey: "google"},
+			},
+			{
+				Name:        "ldap",
+				Type:        "LDAP",
+				DisplayName: "LDAP (ldap)",
+				LoginURL:    ldapLoginPath,
+				LoginParams: map[string]string{projectFormKey: "multi", ssoFormKey: "ldap"},
+				Credentials: true,
+			},
+			{
+				Name:        "corp",
+				Type:        "OIDC",
+				DisplayName: "Corporate SSO",
+				Icon:        "https://sso.example.com/icon.png",
+				LoginURL:    loginPath,
+				LoginParams: map[string]string{projectFormKey: "multi", ssoFormKey: "corp"},
+			},
+		},
+	}, resp)
+
+	rec = call(http.MethodGet, providersPath+"?project=single")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NoError(t, json.NewDecoder(rec.Body).Decode(&resp))
+	require.Len(t, resp.Providers, 1)
+	assert.Equal(t, model.ProjectSSOConfig_GITHUB.String(), resp.Providers[0].Type)
+
+	// The errors are always responded in JSON.
+	tests := []struct {
+		name   string
+		method string
+		target string
+		code   errorCode
+	}{
+		{name: "missing project", method: http.MethodGet, target: providersPath, code: errCodeInvalidRequest},
+		{name: "unknown project", method: http.MethodGet, target: providersPath + "?project=unknown", code: errCodeProjectNotFound},
+		{name: "method not allowed", method: http.MethodPost, target: providersPath + "?project=multi", code: errCodeMethodNotAllowed},
+	}
+	for _, tt := range tests {
+		rec := call(tt.method, tt.target)
+		assert.Equal(t, tt.code.statusCode(), rec.Code, tt.name)
+		var e errorResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&e), tt.name)
+		assert.Equal(t, tt.code, e.Code, tt.name)
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/time/rate"
+
+	"github.com/pipe-cd/pipecd/pkg/app/server/httpapi/httpapimetrics"
+)
+
+const (
+	rateLimitIdleTTL       = 10 * time.Minute
+	rateLimitSweepInterval = time.Minute
+)
+
+// RateLimit is the token-bucket rate of requests.
+// Zero RequestsPerSecond means no limit.
+type RateLimit struct {
+	RequestsPerSecond float64
+	Burst             int
+}
+
+func (l RateLimit) enabled() bool {
+	return l.RequestsPerSecond > 0
+}
+
+// WithCallbackRateLimit limits the requests to the callback endpoint per client IP and per project.
+func WithCallbackRateLimit(perIP, perProject RateLimit) Option {
+	return func(h *authHandler) {
+		if perIP.enabled() {
+			h.callbackIPLimiter = newKeyedLimiter(perIP)
+		}
+		if perProject.enabled() {
+			h.callbackProjectLimiter = newKeyedLimiter(perProject)
+		}
+	}
+}
+
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// keyedLimiter holds a token bucket for each key.
+// The buckets which have not been used for a while are removed.
+type keyedLimiter struct {
+	limit RateLimit
+	now   func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*limiterEntry
+	lastSweep time.Time
+}
+
+func newKeyedLimiter(limit RateLimit) *keyedLimiter {
+	if limit.Burst <= 0 {
+		limit.Burst = 1
+	}
+	return &keyedLimiter{
+		limit:   limit,
+		now:     time.Now,
+		entries: make(map[string]*limiterEntry),
+	}
+}
+
+// allow reports whether a request for the given key can be processed now.
+func (l *keyedLimiter) allow(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	return l.entry(key, now).limiter.AllowN(now, 1)
+}
+
+// available reports whether a token for the given key is left without consuming it.
+func (l *keyedLimiter) available(key string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	return l.entry(key, now).limiter.TokensAt(now) >= 1
+}
+
+// entry returns the bucket for the given key and removes the idle ones.
+// The caller must hold the lock.
+func (l *keyedLimiter) entry(key string, now time.Time) *limiterEntry {
+	if now.Sub(l.lastSweep) >= rateLimitSweepInterval {
+		for k, e := range l.entries {
+			if now.Sub(e.lastSeen) >= rateLimitIdleTTL {
+				delete(l.entries, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	e, ok := l.entries[key]
+	if !ok {
+		e = &limiterEntry{
+			limiter: rate.NewLimiter(rate.Limit(l.limit.RequestsPerSecond), l.limit.Burst),
+		}
+		l.entries[key] = e
+	}
+	e.lastSeen = now
+	return e
+}
+
+// retryAfter returns the seconds to wait before the next token is available.
+func (l *keyedLimiter) retryAfter() int {
+	return int(1/l.limit.RequestsPerSecond) + 1
+}
+
+// handleRateLimited responds 429 to the request rejected by the limiter of the given key kind.
+func (h *authHandler) handleRateLimited(w http.ResponseWriter, r *http.Request, retryAfter int, path, key string) {
+	httpapimetrics.IncRateLimitedRequests(path, key)
+	h.logger.Info("auth-handler: too many requests",
+		zap.String("path", path),
+		zap.String("limit-key", key),
+		zap.String("client-ip", h.clientIP(r)),
+	)
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	if wantsJSON(r) {
+		if err := writeJSONError(w, errCodeTooManyRequests, "Too many requests", ""); err != nil {
+			h.logger.Error("auth-handler: failed to write error response", zap.Error(err))
+		}
+		return
+	}
+	http.Error(w, "Too many requests", http.StatusTooManyRequests)
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+)
+
+func TestKeyedLimiter(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	l := newKeyedLimiter(RateLimit{RequestsPerSecond: 1, Burst: 2})
+	l.now = func() time.Time { return now }
+
+	assert.True(t, l.allow("a"))
+	assert.True(t, l.allow("a"))
+	assert.False(t, l.allow("a"))
+	// Other keys have their own bucket.
+	assert.True(t, l.allow("b"))
+
+	now = now.Add(time.Second)
+	assert.True(t, l.allow("a"))
+	assert.False(t, l.allow("a"))
+
+	// Idle buckets are removed.
+	now = now.Add(rateLimitIdleTTL)
+	l.allow("b")
+	assert.Len(t, l.entries, 1)
+}
+
+func TestHandleCallbackRateLimited(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		stateKey:      "state-key",
+		projectGetter: fakeProjectGetter{},
+		logger:        zap.NewNop(),
+	}
+	WithCallbackRateLimit(RateLimit{}, RateLimit{RequestsPerSecond: 1, Burst: 1})(h)
+	assert.Nil(t, h.callbackIPLimiter)
+
+	call := func(project string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, callbackPath+"?state=state:project%3D"+project, nil)
+		rec := httptest.NewRecorder()
+		h.handleCallback(rec, req)
+		return rec
+	}
+
+	assert.NotEqual(t, http.StatusTooManyRequests, call("project").Code)
+	rec := call("project")
+	assert.Equal(t, http.StatusTooManyRequests, rec.Code)
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+	assert.NotEqual(t, http.StatusTooManyRequests, call("other").Code)
+}
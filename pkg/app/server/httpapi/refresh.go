@@ -0,0 +1,261 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/cache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	refreshTokenCookieKey  = "refresh_token"
+	refreshTokenCookiePath = "/auth"
+	refreshTokenKeyPrefix  = "refresh-token:"
+	refreshTokenSize       = 32
+)
+
+// refreshToken is the session data stored for a refresh token
+// to mint a new access token without logging in again.
+type refreshToken struct {
+	Subject   string        `json:"subject"`
+	AvatarURL string        `json:"avatarUrl"`
+	ProjectID string        `json:"projectId"`
+	Roles     []string      `json:"roles"`
+	TokenTTL  time.Duration `json:"tokenTtl"`
+	// Groups are the user groups of the project matched at login.
+	Groups []string `json:"groups,omitempty"`
+	// Identities are the SSO identities linked to the subject.
+	Identities []string `json:"identities,omitempty"`
+	// Provider is the SSO provider the user logged in with.
+	Provider string `json:"provider,omitempty"`
+	// IdPSubject and IdPSessionID identify the session at the OIDC provider to be revoked by the back-channel logout.
+	IdPSubject   string `json:"idpSubject,omitempty"`
+	IdPSessionID string `json:"idpSessionId,omitempty"`
+	// LoginAt is when the user logged in, which is kept while the refresh token is rotated.
+	LoginAt time.Time `json:"loginAt"`
+	// Binding is the fingerprint of the client the session is bound to at login. Empty if not bound.
+	Binding string `json:"binding,omitempty"`
+}
+
+// WithRefreshToken enables refresh tokens which are stored in the given cache.
+// The cache must expire the stored tokens after the given TTL.
+func WithRefreshToken(c cache.Cache, ttl time.Duration) Option {
+	return func(h *authHandler) {
+		h.refreshTokens = c
+		h.refreshTokenTTL = ttl
+	}
+}
+
+// handleRefresh is called when the web requested to extend the current session.
+// The refresh token is rotated on each use, so the used one is revoked.
+func (h *authHandler) handleRefresh(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if h.refreshTokens == nil {
+		http.Error(w, "Refresh token is not enabled", http.StatusNotFound)
+		return
+	}
+
+	c, err := r.Cookie(refreshTokenCookieKey)
+	if err != nil || c.Value == "" {
+		h.handleRefreshError(w, "Missing refresh token", err)
+		return
+	}
+	rt, err := h.revokeRefreshToken(c.Value)
+	if err != nil {
+		h.handleRefreshError(w, "Invalid refresh token", err)
+		return
+	}
+	// The age of the session is unknown if the login time was not recorded, so the user must log in again.
+	if rt.LoginAt.IsZero() {
+		h.handleRefreshError(w, "Missing login time of refresh token", nil)
+		return
+	}
+
+	// The refresh token can only be used from the client the session is bound to, as well as the token.
+	if h.sessionBinding.enabled() {
+		if err := checkSessionBinding(rt.Binding, h.clientIP(r), r.UserAgent()); err != nil {
+			h.handleRefreshError(w, "Session is bound to another client", err)
+			return
+		}
+	}
+	if h.sessionStore != nil && (rt.IdPSubject != "" || rt.IdPSessionID != "") {
+		revoked, err := h.sessionStore.IsIdPSessionRevoked(rt.ProjectID, rt.IdPSubject, rt.IdPSessionID, rt.LoginAt)
+		if err != nil {
+			h.handleRefreshError(w, "Internal error", err)
+			return
+		}
+		if revoked {
+			h.handleRefreshError(w, "User logged out at the identity provider", nil)
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	proj, err := h.projectGetter.Get(ctx, rt.ProjectID)
+	if err != nil {
+		h.handleRefreshError(w, fmt.Sprintf("Unable to find project %s", rt.ProjectID), err)
+		return
+	}
+	// Drop the roles removed from the project since the user logged in.
+	roles := make([]string, 0, len(rt.Roles))
+	for _, role := range rt.Roles {
+		if proj.HasRBACRole(role) || isBuiltinRole(role) {
+			roles = append(roles, role)
+		}
+	}
+	if len(roles) == 0 {
+		h.handleRefreshError(w, "User no longer has any role in the project", nil)
+		return
+	}
+	rt.Roles = roles
+	// The groups removed from the project are dropped as well as the roles.
+	rt.Groups = proj.MatchedUserGroups(rt.Groups)
+
+	claims := jwt.NewClaimsAt(
+		h.now(),
+		rt.Subject,
+		rt.AvatarURL,
+		rt.TokenTTL,
+		model.Role{
+			ProjectId:        rt.ProjectID,
+			ProjectRbacRoles: rt.Roles,
+		},
+		rt.Groups...,
+	)
+	// The session started at the login, not at this refresh, so that it never outlives the max lifetime.
+	claims.AuthTime = jwtgo.NewNumericDate(rt.LoginAt)
+	claims.Identities = rt.Identities
+	claims.Provider = rt.Provider
+	claims.IdPSubject = rt.IdPSubject
+	claims.IdPSessionID = rt.IdPSessionID
+	h.bindSession(r, claims)
+	rt.Binding = claims.Binding
+	if h.membershipChecker != nil {
+		member, err := h.membershipChecker.IsMember(claims)
+		if err != nil {
+			h.handleRefreshError(w, "Unable to check membership", err)
+			return
+		}
+		if !member {
+			h.handleRefreshError(w, "User is no longer a member of the project", nil)
+			return
+		}
+	}
+	signedToken, err := h.signer.Sign(claims)
+	if err != nil {
+		h.handleRefreshError(w, "Internal error", err)
+		return
+	}
+	if err := h.registerSession(claims); err != nil {
+		h.handleRefreshError(w, "Internal error", err)
+		return
+	}
+	value, err := h.issueRefreshToken(rt)
+	if err != nil {
+		h.handleRefreshError(w, "Internal error", err)
+		return
+	}
+
+	h.logger.Info("user session refreshed",
+		zap.String("user", rt.Subject),
+		zap.String("project-id", rt.ProjectID),
+	)
+
+	http.SetCookie(w, h.cookies.token(signedToken))
+	http.SetCookie(w, h.cookies.refreshToken(value, h.refreshTokenTTL))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func isBuiltinRole(role string) bool {
+	return role == model.BuiltinRBACRoleAdmin.String() ||
+		role == model.BuiltinRBACRoleEditor.String() ||
+		role == model.BuiltinRBACRoleViewer.String()
+}
+
+func (h *authHandler) handleRefreshError(w http.ResponseWriter, responseMessage string, err error) {
+	if err != nil {
+		h.logger.Info(fmt.Sprintf("auth-handler: %s", responseMessage), zap.Error(err))
+	} else {
+		h.logger.Info(fmt.Sprintf("auth-handler: %s", responseMessage))
+	}
+
+	http.SetCookie(w, h.cookies.expiredRefreshToken())
+	http.Error(w, responseMessage, http.StatusUnauthorized)
+}
+
+// issueRefreshToken stores the given session data and returns a new refresh token for it.
+func (h *authHandler) issueRefreshToken(rt *refreshToken) (string, error) {
+	b, err := randomBytes(h.randReader(), refreshTokenSize)
+	if err != nil {
+		return "", err
+	}
+	value := base64.RawURLEncoding.EncodeToString(b)
+
+	data, err := json.Marshal(rt)
+	if err != nil {
+		return "", err
+	}
+	if err := h.refreshTokens.Put(refreshTokenKeyPrefix+value, data); err != nil {
+		return "", err
+	}
+	return value, nil
+}
+
+// revokeRefreshToken deletes the given refresh token and returns its session data.
+func (h *authHandler) revokeRefreshToken(value string) (*refreshToken, error) {
+	key := refreshTokenKeyPrefix + value
+	v, err := h.refreshTokens.Get(key)
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, fmt.Errorf("refresh token was not found or already revoked")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := h.refreshTokens.Delete(key); err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	switch v := v.(type) {
+	case []byte:
+		data = v
+	case string:
+		data = []byte(v)
+	default:
+		return nil, fmt.Errorf("unexpected refresh token data type: %T", v)
+	}
+	var rt refreshToken
+	if err := json.Unmarshal(data, &rt); err != nil {
+		return nil, err
+	}
+	return &rt, nil
+}
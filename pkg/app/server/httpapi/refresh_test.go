@@ -0,0 +1,243 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+type fakeSigner struct{}
+
+func (fakeSigner) Sign(claims *jwt.Claims) (string, error) {
+	return "signed:" + claims.Subject, nil
+}
+
+func TestHandleRefresh(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		signer: fakeSigner{},
+		projectGetter: fakeProjectGetter{
+			"project": {
+				Id:        "project",
+				RbacRoles: []*model.ProjectRBACRole{{Name: "custom"}},
+				UserGroups: []*model.ProjectUserGroup{
+					{SsoGroup: "dev", Role: "custom"},
+				},
+			},
+		},
+		refreshTokens:   memorycache.NewCache(),
+		refreshTokenTTL: time.Hour,
+		cookies:         cookieFactory{secure: true},
+		logger:          zap.NewNop(),
+	}
+
+	value, err := h.issueRefreshToken(&refreshToken{
+		Subject:   "user",
+		ProjectID: "project",
+		Roles:     []string{"custom", "removed", model.BuiltinRBACRoleViewer.String()},
+		TokenTTL:  time.Hour,
+		Groups:    []string{"dev", "removed"},
+		LoginAt:   time.Now(),
+	})
+	require.NoError(t, err)
+
+	refresh := func(value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, refreshPath, nil)
+		req.AddCookie(&http.Cookie{Name: refreshTokenCookieKey, Value: value})
+		rec := httptest.NewRecorder()
+		h.handleRefresh(rec, req)
+		return rec
+	}
+
+	rec := refresh(value)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	cookies := make(map[string]*http.Cookie)
+	for _, c := range rec.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+	require.Contains(t, cookies, jwt.SignedTokenKey)
+	assert.Equal(t, "signed:user", cookies[jwt.SignedTokenKey].Value)
+	require.Contains(t, cookies, refreshTokenCookieKey)
+	rotated := cookies[refreshTokenCookieKey].Value
+	assert.NotEqual(t, value, rotated)
+	assert.True(t, cookies[refreshTokenCookieKey].HttpOnly)
+
+	rt, err := h.revokeRefreshToken(rotated)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"custom", model.BuiltinRBACRoleViewer.String()}, rt.Roles)
+	assert.Equal(t, []string{"dev"}, rt.Groups)
+
+	// The used and the revoked tokens can not be used anymore.
+	assert.Equal(t, http.StatusUnauthorized, refresh(value).Code)
+	assert.Equal(t, http.StatusUnauthorized, refresh(rotated).Code)
+}
+
+func TestHandleRefreshInvalid(t *testing.T) {
+	t.Parallel()
+	newHandler := func() *authHandler {
+		return &authHandler{
+			signer:          fakeSigner{},
+			projectGetter:   fakeProjectGetter{"project": {Id: "project"}},
+			refreshTokens:   memorycache.NewCache(),
+			refreshTokenTTL: time.Hour,
+			logger:          zap.NewNop(),
+		}
+	}
+
+	tests := []struct {
+		name         string
+		method       string
+		token        *refreshToken
+		expectedCode int
+	}{
+		{
+			name:         "method not allowed",
+			method:       http.MethodGet,
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:         "missing token",
+			method:       http.MethodPost,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "project not found",
+			method:       http.MethodPost,
+			token:        &refreshToken{Subject: "user", ProjectID: "deleted", Roles: []string{"Admin"}, LoginAt: time.Now()},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "no remaining role",
+			method:       http.MethodPost,
+			token:        &refreshToken{Subject: "user", ProjectID: "project", Roles: []string{"removed"}, LoginAt: time.Now()},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "missing login time",
+			method:       http.MethodPost,
+			token:        &refreshToken{Subject: "user", ProjectID: "project", Roles: []string{"Admin"}},
+			expectedCode: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			h := newHandler()
+			req := httptest.NewRequest(tt.method, refreshPath, nil)
+			if tt.token != nil {
+				value, err := h.issueRefreshToken(tt.token)
+				require.NoError(t, err)
+				req.AddCookie(&http.Cookie{Name: refreshTokenCookieKey, Value: value})
+			}
+			rec := httptest.NewRecorder()
+			h.handleRefresh(rec, req)
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
+
+type fakeMembershipChecker map[string]bool
+
+func (c fakeMembershipChecker) IsMember(claims *jwt.Claims) (bool, error) {
+	member, ok := c[claims.Subject]
+	if !ok {
+		return false, errors.New("unavailable")
+	}
+	return member, nil
+}
+
+func TestHandleRefreshMembership(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		signer:            fakeSigner{},
+		projectGetter:     fakeProjectGetter{"project": {Id: "project"}},
+		refreshTokens:     memorycache.NewCache(),
+		refreshTokenTTL:   time.Hour,
+		membershipChecker: fakeMembershipChecker{"alice": true, "bob": false},
+		logger:            zap.NewNop(),
+	}
+	refresh := func(user string) int {
+		value, err := h.issueRefreshToken(&refreshToken{
+			Subject:   user,
+			ProjectID: "project",
+			Roles:     []string{model.BuiltinRBACRoleEditor.String()},
+			TokenTTL:  time.Hour,
+			Provider:  model.ProjectSSOConfig_LDAP.String(),
+			LoginAt:   time.Now(),
+		})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, refreshPath, nil)
+		req.AddCookie(&http.Cookie{Name: refreshTokenCookieKey, Value: value})
+		rec := httptest.NewRecorder()
+		h.handleRefresh(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusNoContent, refresh("alice"))
+	assert.Equal(t, http.StatusUnauthorized, refresh("bob"))
+	assert.Equal(t, http.StatusUnauthorized, refresh("carol"))
+}
+
+func TestHandleRefreshKeepsLoginTime(t *testing.T) {
+	t.Parallel()
+	login := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	session := SlidingSession{IdleTimeout: time.Hour, MaxLifetime: 8 * time.Hour}
+	now := login.Add(session.MaxLifetime + time.Hour)
+	signer := &recordingSigner{}
+	h := &authHandler{
+		signer:          signer,
+		projectGetter:   fakeProjectGetter{"project": {Id: "project"}},
+		refreshTokens:   memorycache.NewCache(),
+		refreshTokenTTL: 24 * time.Hour,
+		clock:           fakeClock{now: now},
+		logger:          zap.NewNop(),
+	}
+	value, err := h.issueRefreshToken(&refreshToken{
+		Subject:   "user",
+		ProjectID: "project",
+		Roles:     []string{model.BuiltinRBACRoleViewer.String()},
+		TokenTTL:  session.IdleTimeout,
+		LoginAt:   login,
+	})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, refreshPath, nil)
+	req.AddCookie(&http.Cookie{Name: refreshTokenCookieKey, Value: value})
+	rec := httptest.NewRecorder()
+	h.handleRefresh(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.NotNil(t, signer.signed)
+	assert.Equal(t, login, signer.signed.AuthTime.Time.UTC())
+	assert.Equal(t, now.Add(session.IdleTimeout), signer.signed.ExpiresAt.Time.UTC())
+
+	// The refreshed token is not extended since the session is already over the max lifetime.
+	e := NewSessionExtender(&recordingSigner{}, session, true, WithClock(fakeClock{now: now.Add(30 * time.Minute)}))
+	cookie, err := e.Extend(signer.signed)
+	require.NoError(t, err)
+	assert.Empty(t, cookie)
+}
@@ -0,0 +1,366 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/app/server/httpapi/httpapimetrics"
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/azuread"
+	"github.com/pipe-cd/pipecd/pkg/oauth/bitbucket"
+	"github.com/pipe-cd/pipecd/pkg/oauth/github"
+	"github.com/pipe-cd/pipecd/pkg/oauth/gitlab"
+	"github.com/pipe-cd/pipecd/pkg/oauth/google"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oauthhttp"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oidc"
+	"github.com/pipe-cd/pipecd/pkg/oauth/okta"
+)
+
+// UserRequest is the callback of the OAuth provider to resolve the user from.
+type UserRequest struct {
+	SSO     *model.ProjectSSOConfig
+	Project *model.Project
+	// CallbackURL is the URL of the callback endpoint of the control plane.
+	CallbackURL string
+	// Code is the auth code to exchange with the provider.
+	Code string
+	// Nonce is compared with the nonce claim of the OIDC ID token unless it is empty.
+	Nonce string
+	// IdPInitiated is true if the login was started by the identity provider without the state.
+	IdPInitiated bool
+	// DeviceCode is the device code of the device authorization grant polled instead of the auth code.
+	DeviceCode string
+	// Options are passed to the token exchange, e.g. the PKCE code verifier.
+	Options []oauth2.AuthCodeOption
+	Logger  *zap.Logger
+}
+
+// UserResolver exchanges the auth code with the OAuth provider and resolves the user logging in.
+// The returned token is kept to look up the user after login if the grant store is enabled.
+type UserResolver interface {
+	ResolveUser(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error)
+}
+
+// UserResolverFunc is an adapter to use the function as the UserResolver.
+type UserResolverFunc func(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error)
+
+// ResolveUser calls f(ctx, req).
+func (f UserResolverFunc) ResolveUser(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+	return f(ctx, req)
+}
+
+// defaultUserResolvers are the resolvers of the OAuth providers supported by default.
+var defaultUserResolvers = map[model.ProjectSSOConfig_Provider]UserResolver{
+	model.ProjectSSOConfig_GITHUB:    UserResolverFunc(resolveGitHubUser),
+	model.ProjectSSOConfig_OIDC:      UserResolverFunc(resolveOIDCUser),
+	model.ProjectSSOConfig_GOOGLE:    UserResolverFunc(resolveGoogleUser),
+	model.ProjectSSOConfig_AZUREAD:   UserResolverFunc(resolveAzureADUser),
+	model.ProjectSSOConfig_GITLAB:    UserResolverFunc(resolveGitLabUser),
+	model.ProjectSSOConfig_BITBUCKET: UserResolverFunc(resolveBitbucketUser),
+	model.ProjectSSOConfig_OKTA:      UserResolverFunc(resolveOktaUser),
+}
+
+// WithUserResolver registers the resolver of the users logging in via the given provider,
+// which replaces the default one of the provider.
+func WithUserResolver(provider model.ProjectSSOConfig_Provider, r UserResolver) Option {
+	return func(h *authHandler) {
+		if h.userResolvers == nil {
+			h.userResolvers = maps.Clone(defaultUserResolvers)
+		}
+		h.userResolvers[provider] = r
+	}
+}
+
+// WithOAuthHTTPClient bounds the HTTP calls to the OAuth providers by the given timeouts
+// and connects to them with the given TLS configuration made by oauthhttp.NewTLSConfig.
+// The timeouts should be checked by their Validate in advance. Nil TLS configuration means the default one.
+func WithOAuthHTTPClient(t oauthhttp.Timeouts, tlsConfig *tls.Config) Option {
+	return func(h *authHandler) {
+		h.oauthHTTPClient = oauthhttp.NewClient(t, tlsConfig)
+	}
+}
+
+// oauthContext returns the context carrying the HTTP client used to call the OAuth providers.
+func (h *authHandler) oauthContext(ctx context.Context) context.Context {
+	if h.oauthHTTPClient == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, h.oauthHTTPClient)
+}
+
+// resolveUser resolves the user by the resolver registered for the provider of the given SSO configuration.
+func (h *authHandler) resolveUser(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+	ctx = h.oauthContext(ctx)
+	resolvers := h.userResolvers
+	if resolvers == nil {
+		resolvers = defaultUserResolvers
+	}
+	r, ok := resolvers[req.SSO.Provider]
+	if !ok {
+		return nil, nil, fmt.Errorf("not implemented")
+	}
+	return r.ResolveUser(ctx, req)
+}
+
+// observeCodeExchange records the duration of the code exchange started at the given time.
+// The auth code is exchanged while creating the oauth clients.
+func observeCodeExchange(provider model.ProjectSSOConfig_Provider, start time.Time) {
+	httpapimetrics.ObserveCodeExchangeDuration(provider.String(), time.Since(start))
+}
+
+// logTokenError logs the error responded by the token endpoint of the provider, e.g. invalid_client
+// or redirect_uri_mismatch, which is not shown to the user logging in. Only the error fields and
+// the status of the response are logged, so the auth code and the client secret are never logged.
+func logTokenError(req *UserRequest, err error) {
+	var re *oauth2.RetrieveError
+	if req.Logger == nil || !errors.As(err, &re) {
+		return
+	}
+	fields := []zap.Field{
+		zap.String("project-id", req.Project.Id),
+		zap.String("provider", req.SSO.Provider.String()),
+		zap.String("error-code", re.ErrorCode),
+		zap.String("error-description", re.ErrorDescription),
+		zap.String("error-uri", re.ErrorURI),
+	}
+	if re.Response != nil {
+		fields = append(fields, zap.Int("status-code", re.Response.StatusCode))
+	}
+	req.Logger.Warn("token endpoint rejected the auth code exchange", fields...)
+}
+
+func resolveGitHubUser(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+	sso := req.SSO
+	if sso.Github == nil {
+		return nil, nil, fmt.Errorf("missing GitHub oauth in the SSO configuration")
+	}
+	start := time.Now()
+	redirectURI := sso.Github.RedirectURI(req.Project.Id, req.CallbackURL)
+	cli, err := github.NewOAuthClient(ctx, sso.Github, req.Project, req.Code, redirectURI, sso.AllowedRedirectUris)
+	observeCodeExchange(sso.Provider, start)
+	if err != nil {
+		logTokenError(req, err)
+		return nil, nil, err
+	}
+	user, teams, err := cli.GetUser(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, t := range teams {
+		req.Logger.Info("github team is mapped to project role",
+			zap.String("user", user.Username),
+			zap.String("project-id", req.Project.Id),
+			zap.String("team", t.Team),
+			zap.String("role", t.Role),
+			zap.Strings("granted-roles", user.Role.ProjectRbacRoles),
+		)
+	}
+	return user, cli.Token, nil
+}
+
+func resolveOIDCUser(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+	sso := req.SSO
+	if sso.Oidc == nil {
+		return nil, nil, fmt.Errorf("missing OIDC oauth in the SSO configuration")
+	}
+	if req.DeviceCode != "" {
+		cli, err := oidc.NewDeviceOAuthClient(ctx, sso.Oidc, req.Project, req.DeviceCode)
+		if err != nil {
+			// The pending authorization is the expected response of the polls.
+			if !errors.Is(err, oidc.ErrAuthorizationPending) && !errors.Is(err, oidc.ErrSlowDown) {
+				logTokenError(req, err)
+			}
+			return nil, nil, err
+		}
+		user, err := cli.GetUser(ctx)
+		return user, cli.Token, err
+	}
+	start := time.Now()
+	cli, err := oidc.NewOAuthClient(ctx, sso.Oidc, req.Project, req.Code, sso.AllowedRedirectUris, req.Nonce, req.Options...)
+	observeCodeExchange(sso.Provider, start)
+	if err != nil {
+		logTokenError(req, err)
+		return nil, nil, err
+	}
+	if req.IdPInitiated {
+		cli.SetIdPInitiated(idpInitiatedMaxTokenAge)
+	}
+	user, err := cli.GetUser(ctx)
+	return user, cli.Token, err
+}
+
+func resolveGoogleUser(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+	sso := req.SSO
+	if sso.Google == nil {
+		return nil, nil, fmt.Errorf("missing Google oauth in the SSO configuration")
+	}
+	start := time.Now()
+	cli, err := google.NewOAuthClient(ctx, sso.Google, req.Project, req.Code)
+	observeCodeExchange(sso.Provider, start)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := cli.GetUser(ctx)
+	return user, cli.Token, err
+}
+
+func resolveAzureADUser(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+	sso := req.SSO
+	if sso.AzureAd == nil {
+		return nil, nil, fmt.Errorf("missing Azure AD oauth in the SSO configuration")
+	}
+	start := time.Now()
+	cli, err := azuread.NewOAuthClient(ctx, sso.AzureAd, req.Project, req.Code)
+	observeCodeExchange(sso.Provider, start)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := cli.GetUser(ctx)
+	return user, cli.Token, err
+}
+
+func resolveGitLabUser(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+	sso := req.SSO
+	if sso.Gitlab == nil {
+		return nil, nil, fmt.Errorf("missing GitLab oauth in the SSO configuration")
+	}
+	start := time.Now()
+	cli, err := gitlab.NewOAuthClient(ctx, sso.Gitlab, req.Project, req.Code)
+	observeCodeExchange(sso.Provider, start)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := cli.GetUser(ctx)
+	return user, cli.Token, err
+}
+
+func resolveBitbucketUser(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+	sso := req.SSO
+	if sso.Bitbucket == nil {
+		return nil, nil, fmt.Errorf("missing Bitbucket oauth in the SSO configuration")
+	}
+	start := time.Now()
+	cli, err := bitbucket.NewOAuthClient(ctx, sso.Bitbucket, req.Project, req.Code)
+	observeCodeExchange(sso.Provider, start)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := cli.GetUser(ctx)
+	return user, cli.Token, err
+}
+
+func resolveOktaUser(ctx context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+	sso := req.SSO
+	if sso.Okta == nil {
+		return nil, nil, fmt.Errorf("missing Okta oauth in the SSO configuration")
+	}
+	start := time.Now()
+	cli, err := okta.NewOAuthClient(ctx, sso.Okta, req.Project, req.Code, sso.AllowedRedirectUris)
+	observeCodeExchange(sso.Provider, start)
+	if err != nil {
+		return nil, nil, err
+	}
+	user, err := cli.GetUser(ctx)
+	return user, cli.Token, err
+}
+
+// refreshOAuthUser looks up the user at the OAuth provider of the given SSO configuration by the token issued at login.
+// The tokens of the providers identifying the users by the ID tokens are refreshed to get the new ID tokens,
+// while the others are used as they are until they expire. It returns the token replacing the given one.
+func refreshOAuthUser(ctx context.Context, sso *model.ProjectSSOConfig, project *model.Project, token *oauth2.Token) (*model.User, *oauth2.Token, error) {
+	switch sso.Provider {
+	case model.ProjectSSOConfig_GITHUB:
+		if sso.Github == nil {
+			return nil, nil, fmt.Errorf("missing GitHub oauth in the SSO configuration")
+		}
+		cli, err := github.RefreshOAuthClient(ctx, sso.Github, project, token)
+		if err != nil {
+			return nil, nil, err
+		}
+		user, _, err := cli.GetUser(ctx)
+		return user, cli.Token, err
+	case model.ProjectSSOConfig_OIDC:
+		if sso.Oidc == nil {
+			return nil, nil, fmt.Errorf("missing OIDC oauth in the SSO configuration")
+		}
+		cli, err := oidc.RefreshOAuthClient(ctx, sso.Oidc, project, token.RefreshToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		user, err := cli.GetUser(ctx)
+		return user, cli.Token, err
+	case model.ProjectSSOConfig_GOOGLE:
+		if sso.Google == nil {
+			return nil, nil, fmt.Errorf("missing Google oauth in the SSO configuration")
+		}
+		cli, err := google.RefreshOAuthClient(ctx, sso.Google, project, token.RefreshToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		user, err := cli.GetUser(ctx)
+		return user, cli.Token, err
+	case model.ProjectSSOConfig_AZUREAD:
+		if sso.AzureAd == nil {
+			return nil, nil, fmt.Errorf("missing Azure AD oauth in the SSO configuration")
+		}
+		cli, err := azuread.RefreshOAuthClient(ctx, sso.AzureAd, project, token.RefreshToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		user, err := cli.GetUser(ctx)
+		return user, cli.Token, err
+	case model.ProjectSSOConfig_GITLAB:
+		if sso.Gitlab == nil {
+			return nil, nil, fmt.Errorf("missing GitLab oauth in the SSO configuration")
+		}
+		cli, err := gitlab.RefreshOAuthClient(ctx, sso.Gitlab, project, token)
+		if err != nil {
+			return nil, nil, err
+		}
+		user, err := cli.GetUser(ctx)
+		return user, cli.Token, err
+	case model.ProjectSSOConfig_BITBUCKET:
+		if sso.Bitbucket == nil {
+			return nil, nil, fmt.Errorf("missing Bitbucket oauth in the SSO configuration")
+		}
+		cli, err := bitbucket.RefreshOAuthClient(ctx, sso.Bitbucket, project, token)
+		if err != nil {
+			return nil, nil, err
+		}
+		user, err := cli.GetUser(ctx)
+		return user, cli.Token, err
+	case model.ProjectSSOConfig_OKTA:
+		if sso.Okta == nil {
+			return nil, nil, fmt.Errorf("missing Okta oauth in the SSO configuration")
+		}
+		cli, err := okta.RefreshOAuthClient(ctx, sso.Okta, project, token.RefreshToken)
+		if err != nil {
+			return nil, nil, err
+		}
+		user, err := cli.GetUser(ctx)
+		return user, cli.Token, err
+	default:
+		return nil, nil, fmt.Errorf("not implemented")
+	}
+}
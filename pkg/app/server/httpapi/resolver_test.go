@@ -0,0 +1,199 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oauthhttp"
+)
+
+type fakeUserResolver struct {
+	user  *model.User
+	token *oauth2.Token
+	err   error
+}
+
+func (r fakeUserResolver) ResolveUser(_ context.Context, _ *UserRequest) (*model.User, *oauth2.Token, error) {
+	return r.user, r.token, r.err
+}
+
+func TestDefaultUserResolvers(t *testing.T) {
+	t.Parallel()
+	for _, p := range []model.ProjectSSOConfig_Provider{
+		model.ProjectSSOConfig_GITHUB,
+		model.ProjectSSOConfig_OIDC,
+		model.ProjectSSOConfig_GOOGLE,
+		model.ProjectSSOConfig_AZUREAD,
+		model.ProjectSSOConfig_GITLAB,
+		model.ProjectSSOConfig_BITBUCKET,
+		model.ProjectSSOConfig_OKTA,
+	} {
+		h := newAuthHandler(nil, nil, "https://pipecd.example.com", "state-key", nil, nil, nil, true, zap.NewNop())
+		_, _, err := h.resolveUser(context.Background(), &UserRequest{
+			SSO:     &model.ProjectSSOConfig{Provider: p},
+			Project: &model.Project{Id: "project"},
+		})
+		// No provider configuration is given to the registered resolvers.
+		require.Error(t, err, p.String())
+		assert.Contains(t, err.Error(), "missing", p.String())
+	}
+}
+
+func TestWithUserResolver(t *testing.T) {
+	t.Parallel()
+	user := &model.User{Username: "user"}
+	h := newAuthHandler(nil, nil, "https://pipecd.example.com", "state-key", nil, nil, nil, true, zap.NewNop(),
+		WithUserResolver(model.ProjectSSOConfig_GITHUB, fakeUserResolver{user: user}),
+	)
+
+	got, _, err := h.resolveUser(context.Background(), &UserRequest{
+		SSO: &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_GITHUB},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, user, got)
+
+	// The other providers keep the default resolvers.
+	_, _, err = h.resolveUser(context.Background(), &UserRequest{
+		SSO: &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_OIDC},
+	})
+	assert.EqualError(t, err, "missing OIDC oauth in the SSO configuration")
+
+	// The registration does not change the default resolvers.
+	_, _, err = (&authHandler{}).resolveUser(context.Background(), &UserRequest{
+		SSO: &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_GITHUB},
+	})
+	assert.EqualError(t, err, "missing GitHub oauth in the SSO configuration")
+
+	_, _, err = (&authHandler{}).resolveUser(context.Background(), &UserRequest{
+		SSO: &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_SAML},
+	})
+	assert.EqualError(t, err, "not implemented")
+}
+
+func TestWithOAuthHTTPTimeouts(t *testing.T) {
+	t.Parallel()
+	var got *http.Client
+	resolver := UserResolverFunc(func(ctx context.Context, _ *UserRequest) (*model.User, *oauth2.Token, error) {
+		got, _ = ctx.Value(oauth2.HTTPClient).(*http.Client)
+		return &model.User{}, nil, nil
+	})
+	req := &UserRequest{SSO: &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_OIDC}}
+
+	tlsConfig, err := oauthhttp.NewTLSConfig("1.3", "")
+	require.NoError(t, err)
+	h := newAuthHandler(nil, nil, "https://pipecd.example.com", "state-key", nil, nil, nil, true, zap.NewNop(),
+		WithUserResolver(model.ProjectSSOConfig_OIDC, resolver),
+		WithOAuthHTTPClient(oauthhttp.Timeouts{Connect: time.Second, Read: 2 * time.Second, Total: 3 * time.Second}, tlsConfig),
+	)
+	_, _, err = h.resolveUser(context.Background(), req)
+	require.NoError(t, err)
+	require.NotNil(t, got)
+	assert.Equal(t, 3*time.Second, got.Timeout)
+	assert.Equal(t, uint16(tls.VersionTLS13), got.Transport.(*http.Transport).TLSClientConfig.MinVersion)
+
+	// The resolvers use the default client of the oauth packages unless configured.
+	got = nil
+	h = newAuthHandler(nil, nil, "https://pipecd.example.com", "state-key", nil, nil, nil, true, zap.NewNop(),
+		WithUserResolver(model.ProjectSSOConfig_OIDC, resolver),
+	)
+	_, _, err = h.resolveUser(context.Background(), req)
+	require.NoError(t, err)
+	assert.Nil(t, got)
+}
+
+func TestResolveOIDCUserLogsTokenError(t *testing.T) {
+	t.Parallel()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"jwks_uri":%q}`,
+				srv.URL, srv.URL+"/auth", srv.URL+"/token", srv.URL+"/keys")
+		case "/token":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"invalid_client","error_description":"Client authentication failed"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	core, logs := observer.New(zap.DebugLevel)
+	req := &UserRequest{
+		SSO: &model.ProjectSSOConfig{
+			Provider: model.ProjectSSOConfig_OIDC,
+			Oidc: &model.ProjectSSOConfig_Oidc{
+				ClientId:     "client-id",
+				ClientSecret: "client-secret",
+				Issuer:       srv.URL,
+				RedirectUri:  "https://pipecd.example.com/auth/callback",
+			},
+		},
+		Project: &model.Project{Id: "project"},
+		Code:    "auth-code",
+		Logger:  zap.New(core),
+	}
+	_, _, err := resolveOIDCUser(context.Background(), req)
+	require.Error(t, err)
+
+	require.Equal(t, 1, logs.Len())
+	entry := logs.All()[0]
+	assert.Equal(t, zapcore.WarnLevel, entry.Level)
+	fields := entry.ContextMap()
+	assert.Equal(t, "project", fields["project-id"])
+	assert.Equal(t, "OIDC", fields["provider"])
+	assert.Equal(t, "invalid_client", fields["error-code"])
+	assert.Equal(t, "Client authentication failed", fields["error-description"])
+	assert.Equal(t, int64(http.StatusUnauthorized), fields["status-code"])
+	for _, v := range fields {
+		assert.NotContains(t, fmt.Sprint(v), "auth-code")
+		assert.NotContains(t, fmt.Sprint(v), "client-secret")
+	}
+}
+
+func TestLogTokenError(t *testing.T) {
+	t.Parallel()
+	core, logs := observer.New(zap.DebugLevel)
+	req := &UserRequest{
+		SSO:     &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_GITHUB},
+		Project: &model.Project{Id: "project"},
+		Logger:  zap.New(core),
+	}
+
+	// The errors other than the ones of the token endpoint are not logged here.
+	logTokenError(req, fmt.Errorf("redirect uri is not allowed"))
+	assert.Equal(t, 0, logs.Len())
+
+	// The wrapped errors, e.g. the expired code, are logged as well.
+	logTokenError(req, fmt.Errorf("code expired: %w", &oauth2.RetrieveError{ErrorCode: "bad_verification_code"}))
+	require.Equal(t, 1, logs.Len())
+	assert.Equal(t, "bad_verification_code", logs.All()[0].ContextMap()["error-code"])
+}
@@ -0,0 +1,227 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	returnToFormKey   = "return_to"
+	returnToCookieKey = "return_to"
+)
+
+// validateReturnTo checks whether the given target is a same-origin relative path
+// which is safe to redirect to after login, and returns it in the normalized form.
+func validateReturnTo(target string) (string, bool) {
+	// Reject the scheme-relative URLs like "//evil.com" and "/\evil.com"
+	// which browsers treat as the address of another host.
+	if !strings.HasPrefix(target, "/") || strings.HasPrefix(target, "//") || strings.HasPrefix(target, "/\\") {
+		return "", false
+	}
+	if strings.ContainsAny(target, "\\\r\n\t") {
+		return "", false
+	}
+	u, err := url.Parse(target)
+	if err != nil || u.Scheme != "" || u.Host != "" || u.User != nil {
+		return "", false
+	}
+	// Redirecting to the auth endpoints again makes no sense.
+	if u.Path == "/auth" || strings.HasPrefix(u.Path, "/auth/") {
+		return "", false
+	}
+	return u.RequestURI(), true
+}
+
+// ReturnToAllowlist is the list of the targets allowed to redirect to after login.
+type ReturnToAllowlist []returnToPattern
+
+// returnToPattern is an entry of the allowlist, which matches the path exactly,
+// or as a prefix if ended with "*". The host is empty for the relative paths.
+type returnToPattern struct {
+	scheme string
+	host   string
+	// subdomains is true if the host is given as "*.example.com", which matches its subdomains but itself.
+	subdomains bool
+	path       string
+	prefix     bool
+}
+
+// ParseReturnToAllowlist parses the entries of the allowlist of the targets to redirect to after login.
+// Each entry is either a path like "/applications" or a host with a path like "console.example.com/pipecd/*",
+// optionally with the scheme. The trailing "*" allows the paths starting with the rest, and the leading "*."
+// of the host allows its subdomains. The scheme is https unless given.
+func ParseReturnToAllowlist(entries []string) (ReturnToAllowlist, error) {
+	list := make(ReturnToAllowlist, 0, len(entries))
+	for _, e := range entries {
+		e = strings.TrimSpace(e)
+		p := returnToPattern{}
+		rest := e
+		if scheme, r, ok := strings.Cut(rest, "://"); ok {
+			if scheme != "http" && scheme != "https" {
+				return nil, fmt.Errorf("invalid return_to allowlist entry %q: scheme must be http or https", e)
+			}
+			p.scheme, rest = scheme, r
+		}
+		if !strings.HasPrefix(rest, "/") {
+			host, path, _ := strings.Cut(rest, "/")
+			p.host, rest = strings.ToLower(host), "/"+path
+			if p.scheme == "" {
+				p.scheme = "https"
+			}
+		}
+		if p.scheme != "" && p.host == "" {
+			return nil, fmt.Errorf("invalid return_to allowlist entry %q: missing host", e)
+		}
+		if h, ok := strings.CutPrefix(p.host, "*."); ok {
+			p.host, p.subdomains = h, true
+		}
+		if strings.Contains(p.host, "*") {
+			return nil, fmt.Errorf("invalid return_to allowlist entry %q: wildcard is only allowed at the start of the host", e)
+		}
+		if path, ok := strings.CutSuffix(rest, "*"); ok {
+			rest, p.prefix = path, true
+		}
+		if strings.ContainsAny(rest, "*?#\\") {
+			return nil, fmt.Errorf("invalid return_to allowlist entry %q: wildcard is only allowed at the end of the path", e)
+		}
+		p.path = rest
+		list = append(list, p)
+	}
+	return list, nil
+}
+
+// allows reports whether the given target matches any of the entries.
+func (l ReturnToAllowlist) allows(u *url.URL) bool {
+	path := u.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	// The dot segments resolved by the browser could escape the allowed prefix.
+	if strings.Contains(path+"/", "/../") || strings.Contains(path+"/", "/./") {
+		return false
+	}
+	for _, p := range l {
+		if p.host != "" || u.Host != "" {
+			if p.scheme != u.Scheme || !p.matchesHost(strings.ToLower(u.Host)) {
+				continue
+			}
+		}
+		if path == p.path || (p.prefix && strings.HasPrefix(path, p.path)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p returnToPattern) matchesHost(host string) bool {
+	if p.subdomains {
+		return strings.HasSuffix(host, "."+p.host)
+	}
+	return host == p.host
+}
+
+// WithReturnToAllowlist restricts the targets to redirect to after login to the given allowlist.
+// The absolute URLs are also allowed if matched, while the relative paths are rejected unless matched.
+func WithReturnToAllowlist(l ReturnToAllowlist) Option {
+	return func(h *authHandler) {
+		h.returnToAllowlist = l
+	}
+}
+
+// validateReturnTo checks whether the given target is allowed to redirect to after login,
+// and returns it in the normalized form. Any safe relative path is allowed if the allowlist is not configured.
+func (h *authHandler) validateReturnTo(target string) (string, bool) {
+	return h.returnToAllowlist.validate(target)
+}
+
+// validate checks whether the given target is allowed by the allowlist, and returns it in the normalized form.
+// Any safe relative path is allowed if the allowlist is empty.
+func (l ReturnToAllowlist) validate(target string) (string, bool) {
+	if len(l) == 0 {
+		return validateReturnTo(target)
+	}
+	if t, ok := validateReturnTo(target); ok {
+		u, err := url.Parse(t)
+		if err != nil || !l.allows(u) {
+			return "", false
+		}
+		return t, true
+	}
+	if strings.ContainsAny(target, "\\\r\n\t") {
+		return "", false
+	}
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" || u.User != nil || u.Opaque != "" {
+		return "", false
+	}
+	if !l.allows(u) {
+		return "", false
+	}
+	return u.String(), true
+}
+
+// signReturnTo returns the encoded target with its HMAC signature appended,
+// so that it can be stored in a cookie without being tampered with.
+func signReturnTo(key, target string) string {
+	encoded := base64.RawURLEncoding.EncodeToString([]byte(target))
+	return encoded + "." + hmacSignature(key, encoded)
+}
+
+// verifySignedReturnTo checks the signature of the given value and returns the validated target.
+func (h *authHandler) verifySignedReturnTo(value string) (string, error) {
+	key := h.stateKey
+	encoded, sig, ok := strings.Cut(value, ".")
+	if !ok || encoded == "" {
+		return "", fmt.Errorf("malformed return target")
+	}
+	if !hmac.Equal([]byte(sig), []byte(hmacSignature(key, encoded))) {
+		return "", fmt.Errorf("invalid return target signature")
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	target, ok := h.validateReturnTo(string(raw))
+	if !ok {
+		return "", fmt.Errorf("invalid return target %q", raw)
+	}
+	return target, nil
+}
+
+// returnTo returns the path to redirect to after login, or rootPath if not requested.
+// The path is kept in the sealed state if given, otherwise in the cookie.
+func (h *authHandler) returnTo(r *http.Request, sealed *sealedState) string {
+	if sealed != nil {
+		if target, ok := h.validateReturnTo(sealed.ReturnTo); ok {
+			return target
+		}
+		return rootPath
+	}
+	c, err := r.Cookie(returnToCookieKey)
+	if err != nil || c.Value == "" {
+		return rootPath
+	}
+	target, err := h.verifySignedReturnTo(c.Value)
+	if err != nil {
+		return rootPath
+	}
+	return target
+}
@@ -0,0 +1,268 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateReturnTo(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+		valid    bool
+	}{
+		{
+			name:     "relative path",
+			target:   "/deployments/deployment-id",
+			expected: "/deployments/deployment-id",
+			valid:    true,
+		},
+		{
+			name:     "with query",
+			target:   "/applications?kind=KUBERNETES",
+			expected: "/applications?kind=KUBERNETES",
+			valid:    true,
+		},
+		{
+			name:   "empty",
+			target: "",
+		},
+		{
+			name:   "absolute url",
+			target: "https://evil.com/deployments",
+		},
+		{
+			name:   "scheme-relative url",
+			target: "//evil.com",
+		},
+		{
+			name:   "backslash",
+			target: "/\\evil.com",
+		},
+		{
+			name:   "embedded backslash",
+			target: "/foo\\..\\\\evil.com",
+		},
+		{
+			name:   "javascript",
+			target: "javascript:alert(1)",
+		},
+		{
+			name:   "not starting with slash",
+			target: "deployments",
+		},
+		{
+			name:   "newline",
+			target: "/foo\r\nLocation: https://evil.com",
+		},
+		{
+			name:   "auth endpoint",
+			target: "/auth/logout",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := validateReturnTo(tt.target)
+			assert.Equal(t, tt.valid, ok)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestSignedReturnTo(t *testing.T) {
+	t.Parallel()
+	const key = "state-key"
+	h := &authHandler{stateKey: key}
+	value := signReturnTo(key, "/deployments/deployment-id")
+
+	got, err := h.verifySignedReturnTo(value)
+	require.NoError(t, err)
+	assert.Equal(t, "/deployments/deployment-id", got)
+
+	_, err = (&authHandler{stateKey: "other-key"}).verifySignedReturnTo(value)
+	assert.Error(t, err)
+
+	tampered := signReturnTo("other-key", "//evil.com")
+	_, err = h.verifySignedReturnTo(tampered)
+	assert.Error(t, err)
+
+	// Even with a valid signature, an unsafe target is rejected.
+	_, err = h.verifySignedReturnTo(signReturnTo(key, "//evil.com"))
+	assert.Error(t, err)
+}
+
+func TestParseReturnToAllowlist(t *testing.T) {
+	t.Parallel()
+	list, err := ParseReturnToAllowlist([]string{
+		"/applications",
+		"/deployments/*",
+		"console.example.com/pipecd/*",
+		"http://localhost:3000/*",
+		"*.example.org/",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ReturnToAllowlist{
+		{path: "/applications"},
+		{path: "/deployments/", prefix: true},
+		{scheme: "https", host: "console.example.com", path: "/pipecd/", prefix: true},
+		{scheme: "http", host: "localhost:3000", path: "/", prefix: true},
+		{scheme: "https", host: "example.org", subdomains: true, path: "/"},
+	}, list)
+
+	for _, entry := range []string{
+		"",
+		"ftp://example.com/",
+		"https:///applications",
+		"console.*.example.com/",
+		"/deployments/*/logs",
+	} {
+		_, err := ParseReturnToAllowlist([]string{entry})
+		assert.Error(t, err, entry)
+	}
+}
+
+func TestValidateReturnToWithAllowlist(t *testing.T) {
+	t.Parallel()
+	list, err := ParseReturnToAllowlist([]string{
+		"/applications",
+		"/deployments/*",
+		"console.example.com/pipecd/*",
+		"*.example.org/",
+	})
+	require.NoError(t, err)
+	h := &authHandler{returnToAllowlist: list}
+
+	tests := []struct {
+		name     string
+		target   string
+		expected string
+	}{
+		{
+			name:     "exact relative path",
+			target:   "/applications?kind=KUBERNETES",
+			expected: "/applications?kind=KUBERNETES",
+		},
+		{
+			name:     "relative path with allowed prefix",
+			target:   "/deployments/deployment-id",
+			expected: "/deployments/deployment-id",
+		},
+		{
+			name:   "relative path not allowed",
+			target: "/settings",
+		},
+		{
+			name:   "relative path longer than the exact path",
+			target: "/applications/application-id",
+		},
+		{
+			name:   "unsafe relative path",
+			target: "//evil.com/applications",
+		},
+		{
+			name:     "allowed host with path prefix",
+			target:   "https://console.example.com/pipecd/applications",
+			expected: "https://console.example.com/pipecd/applications",
+		},
+		{
+			name:     "allowed subdomain",
+			target:   "https://pipecd.example.org/",
+			expected: "https://pipecd.example.org/",
+		},
+		{
+			name:   "subdomain pattern does not match the domain itself",
+			target: "https://example.org/",
+		},
+		{
+			name:   "host not allowed",
+			target: "https://evil.com/pipecd/applications",
+		},
+		{
+			name:   "suffix of the allowed host",
+			target: "https://evilexample.org/",
+		},
+		{
+			name:   "path not allowed",
+			target: "https://console.example.com/other",
+		},
+		{
+			name:   "scheme not allowed",
+			target: "http://console.example.com/pipecd/applications",
+		},
+		{
+			name:   "dot segments",
+			target: "https://console.example.com/pipecd/../other",
+		},
+		{
+			name:   "user info",
+			target: "https://user@console.example.com/pipecd/",
+		},
+		{
+			name:   "javascript",
+			target: "javascript:alert(1)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, ok := h.validateReturnTo(tt.target)
+			assert.Equal(t, tt.expected != "", ok)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
+
+func TestReturnTo(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{stateKey: "state-key"}
+
+	req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	assert.Equal(t, rootPath, h.returnTo(req, nil))
+
+	req = httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	req.AddCookie(&http.Cookie{Name: returnToCookieKey, Value: signReturnTo("state-key", "/settings")})
+	assert.Equal(t, "/settings", h.returnTo(req, nil))
+
+	req = httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	req.AddCookie(&http.Cookie{Name: returnToCookieKey, Value: "invalid"})
+	assert.Equal(t, rootPath, h.returnTo(req, nil))
+
+	// The targets not in the allowlist fall back to the root path.
+	list, err := ParseReturnToAllowlist([]string{"/deployments/*", "console.example.com/*"})
+	require.NoError(t, err)
+	h.returnToAllowlist = list
+
+	req = httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	req.AddCookie(&http.Cookie{Name: returnToCookieKey, Value: signReturnTo("state-key", "/settings")})
+	assert.Equal(t, rootPath, h.returnTo(req, nil))
+
+	req = httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	req.AddCookie(&http.Cookie{Name: returnToCookieKey, Value: signReturnTo("state-key", "https://console.example.com/applications")})
+	assert.Equal(t, "https://console.example.com/applications", h.returnTo(req, nil))
+
+	assert.Equal(t, "/deployments/id", h.returnTo(req, &sealedState{ReturnTo: "/deployments/id"}))
+	assert.Equal(t, rootPath, h.returnTo(req, &sealedState{ReturnTo: "https://evil.com/"}))
+}
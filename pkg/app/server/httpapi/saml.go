@@ -0,0 +1,316 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/app/server/httpapi/httpapimetrics"
+	"github.com/pipe-cd/pipecd/pkg/cache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/saml"
+)
+
+const (
+	samlRequestCookieKey = "saml_request"
+	relayStateFormKey    = "RelayState"
+)
+
+type samlServiceProvider interface {
+	AuthnRequestURL(relayState string) (string, string, error)
+	Authenticate(r *http.Request, project *model.Project, requestID string) (*model.User, error)
+}
+
+func newSAMLClient(ctx context.Context, sso *model.ProjectSSOConfig_Saml, acsURL string, assertions cache.Cache) (samlServiceProvider, error) {
+	return saml.NewClient(ctx, sso, acsURL, assertions)
+}
+
+// WithSAMLAssertionCache sets the cache recording the IDs of the consumed SAML assertions
+// to reject replaying them. The entries must be kept longer than the assertions are valid.
+// Logging in via SAML is rejected unless this is set.
+func WithSAMLAssertionCache(c cache.Cache) Option {
+	return func(h *authHandler) {
+		h.samlAssertions = c
+	}
+}
+
+// samlRequest is the authentication request sent to the SAML identity provider,
+// which is kept in a cookie to bind the response to the browser started the login.
+type samlRequest struct {
+	ProjectID string `json:"project_id"`
+	SSOName   string `json:"sso_name,omitempty"`
+	RequestID string `json:"request_id"`
+	ReturnTo  string `json:"return_to,omitempty"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// signSAMLRequest returns the encoded request with its HMAC signature appended,
+// so that it can be stored in a cookie without being tampered with.
+func signSAMLRequest(key string, req *samlRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+	return encoded + "." + hmacSignature(key, encoded), nil
+}
+
+// verifySignedSAMLRequest checks the signature and the expiry of the given value and returns the request.
+func verifySignedSAMLRequest(key, value string, now time.Time) (*samlRequest, error) {
+	encoded, sig, ok := strings.Cut(value, ".")
+	if !ok || encoded == "" {
+		return nil, fmt.Errorf("malformed SAML request")
+	}
+	if !hmac.Equal([]byte(sig), []byte(hmacSignature(key, encoded))) {
+		return nil, fmt.Errorf("invalid SAML request signature")
+	}
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	var req samlRequest
+	if err := json.Unmarshal(data, &req); err != nil {
+		return nil, err
+	}
+	if now.Unix() >= req.ExpiresAt {
+		return nil, errStateExpired
+	}
+	return &req, nil
+}
+
+// startSAMLLogin redirects the user to the SAML identity provider with a new authentication request.
+func (h *authHandler) startSAMLLogin(w http.ResponseWriter, r *http.Request, proj *model.Project, ssoName string, sso *model.ProjectSSOConfig) {
+	if sso.Saml == nil {
+		h.handleError(w, r, errCodeInvalidSSOConfig, "Missing SAML in the SSO configuration", nil)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	cli, err := h.newSAMLClient(ctx, sso.Saml, h.samlACSURL, h.samlAssertions)
+	if err != nil {
+		h.handleError(w, r, errCodeInvalidSSOConfig, "Invalid SAML configuration", err)
+		return
+	}
+	authURL, requestID, err := cli.AuthnRequestURL(proj.Id)
+	if err != nil {
+		h.handleError(w, r, errCodeInternal, "Internal error", err)
+		return
+	}
+
+	req := &samlRequest{
+		ProjectID: proj.Id,
+		SSOName:   ssoName,
+		RequestID: requestID,
+		ExpiresAt: h.now().Add(h.stateTTL).Unix(),
+	}
+	if target, ok := h.validateReturnTo(r.FormValue(returnToFormKey)); ok {
+		req.ReturnTo = target
+	}
+	value, err := signSAMLRequest(h.stateKey, req)
+	if err != nil {
+		h.handleError(w, r, errCodeInternal, "Internal error", err)
+		return
+	}
+	http.SetCookie(w, h.cookies.samlRequest(value, h.stateTTL))
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
+
+// handleSAMLACS is called when the SAML identity provider posted the response to the assertion consumer service.
+func (h *authHandler) handleSAMLACS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	h.setContentSecurityPolicy(w)
+
+	if l := h.callbackIPLimiter; l != nil && !l.allow(h.clientIP(r)) {
+		h.handleRateLimited(w, r, l.retryAfter(), samlACSPath, "ip")
+		return
+	}
+
+	event := h.newLoginEvent(r)
+	event.Provider = model.ProjectSSOConfig_SAML.String()
+	start := time.Now()
+	defer func() {
+		httpapimetrics.ObserveCallbackDuration(event.Provider, time.Since(start))
+	}()
+
+	if r.Method != http.MethodPost {
+		h.handleLoginError(w, r, event, failureReasonInvalidRequest, errCodeMethodNotAllowed, "Method not allowed", nil)
+		return
+	}
+	c, err := r.Cookie(samlRequestCookieKey)
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonState, errCodeStateInvalid, "Unauthorized access", err)
+		return
+	}
+	req, err := verifySignedSAMLRequest(h.stateKey, c.Value, h.now())
+	if errors.Is(err, errStateExpired) {
+		h.handleLoginError(w, r, event, failureReasonState, errCodeLoginExpired, "Login expired, please retry", err)
+		return
+	}
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonState, errCodeStateInvalid, "Unauthorized access", err)
+		return
+	}
+	event.ProjectID = req.ProjectID
+	if rs := r.PostFormValue(relayStateFormKey); rs != req.ProjectID {
+		h.handleLoginError(w, r, event, failureReasonState, errCodeStateInvalid, "Unauthorized access", fmt.Errorf("relay state %q does not match the project", rs))
+		return
+	}
+
+	if l := h.callbackProjectLimiter; l != nil && !l.allow(req.ProjectID) {
+		h.handleRateLimited(w, r, l.retryAfter(), samlACSPath, "project")
+		return
+	}
+	if !h.allowProjectCallback(w, r, samlACSPath, req.ProjectID) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	proj, err := h.getProject(ctx, req.ProjectID)
+	if err != nil {
+		reason, code, message := projectLookupErrorCode(req.ProjectID, err)
+		h.handleLoginError(w, r, event, reason, code, message, err)
+		return
+	}
+	sso, shared, err := h.findSSOConfigByName(proj, req.SSOName)
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeInvalidSSOConfig, fmt.Sprintf("Invalid SSO configuration: %v", err), nil)
+		return
+	}
+	if sso.Provider != model.ProjectSSOConfig_SAML || sso.Saml == nil {
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeInvalidSSOConfig, "SAML is not configured for the project", nil)
+		return
+	}
+	if h.samlAssertions == nil {
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeInvalidSSOConfig, "SAML is not enabled on the control plane", nil)
+		return
+	}
+	if !shared {
+		if sso, err = h.decryptSSO(proj.Id, sso); err != nil {
+			h.handleLoginError(w, r, event, failureReasonDecrypt, errCodeInternal, "Failed to decrypt SSO configuration", err)
+			return
+		}
+	}
+
+	cli, err := h.newSAMLClient(ctx, sso.Saml, h.samlACSURL, h.samlAssertions)
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonInvalidConfig, errCodeInvalidSSOConfig, "Invalid SAML configuration", err)
+		return
+	}
+	user, err := cli.Authenticate(r, proj, req.RequestID)
+	if errors.Is(err, saml.ErrReplayedAssertion) {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeUnauthorized, "SAML assertion has already been used", err)
+		return
+	}
+	if errors.Is(err, saml.ErrInvalidResponse) {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeUnauthorized, "Invalid SAML response", err)
+		return
+	}
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonUserLookup, errCodeUnauthorized, "Unable to find user", err)
+		return
+	}
+	event.Username = user.Username
+	if err := ensureRole(proj, user); err != nil {
+		h.handleLoginError(w, r, event, failureReasonForbidden, errCodeForbidden, "no role assigned for your account", err)
+		return
+	}
+	tokenTTL := h.loginTokenTTL(sessionTokenTTL(sso, proj, user.Role))
+	user.AvatarUrl = h.resolveAvatarURL(user)
+
+	claims := jwt.NewClaimsAt(
+		h.now(),
+		user.Username,
+		user.AvatarUrl,
+		tokenTTL,
+		*user.Role,
+		user.Groups...,
+	)
+	claims.Provider = event.Provider
+	h.bindSession(r, claims)
+	signedToken, err := h.signer.Sign(claims)
+	if err != nil {
+		h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
+		return
+	}
+	if err := h.registerSession(claims); err != nil {
+		h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
+		return
+	}
+
+	h.logger.Info("user logged in",
+		zap.String("user", user.Username),
+		zap.String("project-id", proj.Id),
+		zap.String("project-role", user.Role.String()),
+	)
+
+	if h.refreshTokens != nil {
+		value, err := h.issueRefreshToken(&refreshToken{
+			Subject:   user.Username,
+			AvatarURL: user.AvatarUrl,
+			ProjectID: proj.Id,
+			Roles:     user.Role.ProjectRbacRoles,
+			TokenTTL:  tokenTTL,
+			Groups:    user.Groups,
+			Provider:  event.Provider,
+			LoginAt:   claims.AuthTime.Time,
+			Binding:   claims.Binding,
+		})
+		if err != nil {
+			h.handleLoginError(w, r, event, failureReasonSign, errCodeInternal, "Internal error", err)
+			return
+		}
+		http.SetCookie(w, h.cookies.refreshToken(value, h.refreshTokenTTL))
+	}
+	http.SetCookie(w, h.cookies.secureToken(signedToken))
+	http.SetCookie(w, h.cookies.expiredSAMLRequest())
+	event.Success = true
+	h.recordLogin(r, event)
+
+	target := rootPath
+	if t, ok := h.validateReturnTo(req.ReturnTo); ok {
+		target = t
+	}
+	if err := writeRedirectPage(w, target); err != nil {
+		h.logger.Error("auth-handler: failed to write redirect page", zap.Error(err))
+	}
+}
+
+var redirectPage = template.Must(template.New("redirect").Parse(`<!DOCTYPE html><html><head><meta http-equiv="refresh" content="0;url={{.}}"></head><body></body></html>`))
+
+// writeRedirectPage navigates the browser to the target from a page of this origin.
+// Redirecting the cross-site POST of the identity provider directly makes browsers
+// drop the SameSite=Strict token cookie from the redirected request.
+func writeRedirectPage(w http.ResponseWriter, target string) error {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	return redirectPage.Execute(w, target)
+}
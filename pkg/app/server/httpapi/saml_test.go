@@ -0,0 +1,207 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/cache"
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/saml"
+)
+
+// fakeSAMLServiceProvider accepts the SAMLResponse "valid" once for the request "request-1".
+type fakeSAMLServiceProvider struct {
+	assertions cache.Cache
+}
+
+func (p fakeSAMLServiceProvider) AuthnRequestURL(relayState string) (string, string, error) {
+	return "https://idp.example.com/sso?RelayState=" + relayState, "request-1", nil
+}
+
+func (p fakeSAMLServiceProvider) Authenticate(r *http.Request, project *model.Project, requestID string) (*model.User, error) {
+	if r.PostFormValue("SAMLResponse") != "valid" || requestID != "request-1" {
+		return nil, saml.ErrInvalidResponse
+	}
+	if _, err := p.assertions.Get("used"); err == nil {
+		return nil, saml.ErrReplayedAssertion
+	}
+	p.assertions.Put("used", true)
+	return &model.User{
+		Username: "alice",
+		Role: &model.Role{
+			ProjectId:        project.Id,
+			ProjectRbacRoles: []string{model.BuiltinRBACRoleEditor.String()},
+		},
+	}, nil
+}
+
+func newSAMLTestHandler() *authHandler {
+	return &authHandler{
+		signer:   fakeSigner{},
+		stateKey: "state-key",
+		stateTTL: time.Minute,
+		projectGetter: fakeProjectGetter{
+			"saml": {
+				Id: "saml",
+				Sso: &model.ProjectSSOConfig{
+					Provider: model.ProjectSSOConfig_SAML,
+					Saml:     &model.ProjectSSOConfig_Saml{EntityId: "https://pipecd.example.com"},
+				},
+			},
+			"github": {
+				Id:  "github",
+				Sso: &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_GITHUB},
+			},
+		},
+		samlAssertions: memorycache.NewCache(),
+		newSAMLClient: func(_ context.Context, _ *model.ProjectSSOConfig_Saml, _ string, c cache.Cache) (samlServiceProvider, error) {
+			return fakeSAMLServiceProvider{assertions: c}, nil
+		},
+		auditRecorder: nopAuditRecorder{},
+		logger:        zap.NewNop(),
+	}
+}
+
+func TestHandleSAMLLogin(t *testing.T) {
+	t.Parallel()
+	h := newSAMLTestHandler()
+
+	form := url.Values{
+		projectFormKey:  {"saml"},
+		returnToFormKey: {"/applications"},
+	}
+	req := httptest.NewRequest(http.MethodPost, loginPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.handleSSOLogin(rec, req)
+
+	assert.Equal(t, http.StatusFound, rec.Code)
+	assert.Equal(t, "https://idp.example.com/sso?RelayState=saml", rec.Header().Get("Location"))
+	var cookie *http.Cookie
+	for _, c := range rec.Result().Cookies() {
+		if c.Name == samlRequestCookieKey {
+			cookie = c
+		}
+	}
+	require.NotNil(t, cookie)
+	assert.Equal(t, http.SameSiteNoneMode, cookie.SameSite)
+	assert.True(t, cookie.Secure)
+
+	got, err := verifySignedSAMLRequest(h.stateKey, cookie.Value, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, "saml", got.ProjectID)
+	assert.Equal(t, "request-1", got.RequestID)
+	assert.Equal(t, "/applications", got.ReturnTo)
+}
+
+func TestHandleSAMLACS(t *testing.T) {
+	t.Parallel()
+	h := newSAMLTestHandler()
+
+	signed := func(projectID string) string {
+		v, err := signSAMLRequest(h.stateKey, &samlRequest{
+			ProjectID: projectID,
+			RequestID: "request-1",
+			ReturnTo:  "/applications",
+			ExpiresAt: time.Now().Add(time.Minute).Unix(),
+		})
+		require.NoError(t, err)
+		return v
+	}
+	post := func(cookie, relayState, response string) *httptest.ResponseRecorder {
+		form := url.Values{
+			relayStateFormKey: {relayState},
+			"SAMLResponse":    {response},
+		}
+		req := httptest.NewRequest(http.MethodPost, samlACSPath, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: samlRequestCookieKey, Value: cookie})
+		}
+		rec := httptest.NewRecorder()
+		h.handleSAMLACS(rec, req)
+		return rec
+	}
+	tokenCookie := func(rec *httptest.ResponseRecorder) string {
+		for _, c := range rec.Result().Cookies() {
+			if c.Name == jwt.SignedTokenKey {
+				return c.Value
+			}
+		}
+		return ""
+	}
+
+	// Missing or tampered cookie.
+	rec := post("", "saml", "valid")
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Empty(t, tokenCookie(rec))
+	rec = post(signed("saml")+"x", "saml", "valid")
+	assert.Empty(t, tokenCookie(rec))
+
+	// Relay state not matching the project of the request.
+	rec = post(signed("saml"), "github", "valid")
+	assert.Empty(t, tokenCookie(rec))
+
+	// Project not using SAML.
+	rec = post(signed("github"), "github", "valid")
+	assert.Empty(t, tokenCookie(rec))
+
+	rec = post(signed("saml"), "saml", "invalid")
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Empty(t, tokenCookie(rec))
+
+	rec = post(signed("saml"), "saml", "valid")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "signed:alice", tokenCookie(rec))
+	assert.Contains(t, rec.Body.String(), `url=/applications`)
+
+	// Replaying the same assertion.
+	rec = post(signed("saml"), "saml", "valid")
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Empty(t, tokenCookie(rec))
+}
+
+func TestVerifySignedSAMLRequest(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	value, err := signSAMLRequest("key", &samlRequest{ProjectID: "project", RequestID: "id", ExpiresAt: now.Add(time.Minute).Unix()})
+	require.NoError(t, err)
+
+	got, err := verifySignedSAMLRequest("key", value, now)
+	require.NoError(t, err)
+	assert.Equal(t, "project", got.ProjectID)
+
+	_, err = verifySignedSAMLRequest("other-key", value, now)
+	assert.Error(t, err)
+
+	_, err = verifySignedSAMLRequest("key", value, now.Add(time.Hour))
+	assert.ErrorIs(t, err, errStateExpired)
+
+	_, err = verifySignedSAMLRequest("key", "malformed", now)
+	assert.Error(t, err)
+}
@@ -0,0 +1,194 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// stateVersion4 is the version of the state sealed by the state secret,
+	// which carries all the data of the login flow instead of the cookies.
+	stateVersion4 = "4"
+	// minStateSecretSize is the minimum length of the state secret in bytes.
+	minStateSecretSize = 32
+)
+
+// sealedState is the content of the state encrypted and authenticated by the state secret.
+// The fields are only added with new names, and the unknown ones are ignored,
+// so that the state issued by a newer server is still accepted by the older one.
+type sealedState struct {
+	// Token is the state token signed by the state key, see generateStateToken.
+	Token string `json:"t"`
+	// Binding is the HMAC of the token by the secret kept in the state cookie.
+	Binding   string `json:"b"`
+	ProjectID string `json:"p"`
+	// SSOName is the name of the SSO configuration selected at login.
+	SSOName string `json:"s,omitempty"`
+	// Nonce is the nonce of the OIDC authentication request.
+	Nonce string `json:"n,omitempty"`
+	// CodeVerifier is the PKCE code verifier.
+	CodeVerifier string `json:"v,omitempty"`
+	// ReturnTo is the path to redirect to after login.
+	ReturnTo string `json:"r,omitempty"`
+}
+
+// WithStateSecret seals the state of the OAuth flow by the given secret.
+// The state issued at login is encrypted and authenticated, and carries the project ID,
+// the nonce, the PKCE code verifier and the return path instead of the cookies.
+// The secret should be checked by ValidateStateSecret in advance.
+func WithStateSecret(secret []byte) Option {
+	return func(h *authHandler) {
+		aead, err := newStateAEAD(secret)
+		if err != nil {
+			h.logger.Error("auth-handler: failed to create the state cipher, the state is not sealed", zap.Error(err))
+			return
+		}
+		h.stateAEAD = aead
+	}
+}
+
+// ValidateStateSecret checks whether the given secret is long enough to seal the state.
+func ValidateStateSecret(secret []byte) error {
+	if n := len(bytes.TrimSpace(secret)); n < minStateSecretSize {
+		return fmt.Errorf("state secret must be at least %d bytes, got %d", minStateSecretSize, n)
+	}
+	return nil
+}
+
+// newStateAEAD returns the AES-256-GCM cipher whose key is derived from the given secret.
+func newStateAEAD(secret []byte) (cipher.AEAD, error) {
+	if err := ValidateStateSecret(secret); err != nil {
+		return nil, err
+	}
+	mac := hmac.New(sha256.New, bytes.TrimSpace(secret))
+	mac.Write([]byte("pipecd auth state"))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// isSealedState reports whether the given state is in the sealed format.
+func isSealedState(state string) bool {
+	return strings.HasPrefix(state, stateVersion4+stateVersionSep)
+}
+
+// encodeSealedState returns the state in the format of "4.<base64 encoded nonce and ciphertext>".
+// The version is authenticated along with the content.
+func encodeSealedState(aead cipher.AEAD, s *sealedState, random io.Reader) (string, error) {
+	plaintext, err := json.Marshal(s)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomBytes(random, aead.NonceSize())
+	if err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plaintext, []byte(stateVersion4))
+	return stateVersion4 + stateVersionSep + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// decodeSealedState decrypts the given state and returns its content.
+func decodeSealedState(aead cipher.AEAD, state string) (*sealedState, error) {
+	version, payload, ok := strings.Cut(state, stateVersionSep)
+	if !ok {
+		return nil, fmt.Errorf("malformed state")
+	}
+	if version != stateVersion4 {
+		return nil, fmt.Errorf("unsupported state version %q", version)
+	}
+	if aead == nil {
+		return nil, fmt.Errorf("sealed state is not enabled")
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return nil, fmt.Errorf("malformed state")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(version))
+	if err != nil {
+		return nil, fmt.Errorf("invalid state: %w", err)
+	}
+	var s sealedState
+	if err := json.Unmarshal(plaintext, &s); err != nil {
+		return nil, fmt.Errorf("malformed state: %w", err)
+	}
+	if s.Token == "" || s.Binding == "" || s.ProjectID == "" {
+		return nil, fmt.Errorf("malformed state")
+	}
+	return &s, nil
+}
+
+// newSealedState fills the state token and its binding to the state cookie in the given state,
+// and returns the sealed state sent to the provider and the value of the state cookie.
+func newSealedState(aead cipher.AEAD, key string, s *sealedState, random io.Reader, now time.Time) (state, cookie string, err error) {
+	b, err := randomBytes(random, stateSecretSize)
+	if err != nil {
+		return "", "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b)
+	s.Token = generateStateToken(key, now)
+	s.Binding = hmacSignature(secret, s.Token)
+	state, err = encodeSealedState(aead, s, random)
+	if err != nil {
+		return "", "", err
+	}
+	return state, secret, nil
+}
+
+// openSealedState decodes the sealed state of the callback request.
+// The project ID passed by the query parameter, e.g. the redirect URI of GitHub, must be the same as the sealed one.
+func (h *authHandler) openSealedState(r *http.Request) (*sealedState, error) {
+	s, err := decodeSealedState(h.stateAEAD, r.FormValue(stateFormKey))
+	if err != nil {
+		return nil, err
+	}
+	if p := r.FormValue(projectFormKey); p != "" && subtle.ConstantTimeCompare([]byte(p), []byte(s.ProjectID)) != 1 {
+		return nil, fmt.Errorf("ambiguous state: project id in the state does not match the query parameter")
+	}
+	return s, nil
+}
+
+// checkSealedState checks whether the given sealed state was issued within the TTL
+// along with the state cookie of the given name.
+func checkSealedState(r *http.Request, cookieName, key string, s *sealedState, ttl time.Duration, now time.Time) error {
+	if err := checkStateToken(s.Token, key, ttl, now); err != nil {
+		return err
+	}
+	c, err := r.Cookie(cookieName)
+	if err != nil {
+		return err
+	}
+	if c.Value == "" || !hmac.Equal([]byte(s.Binding), []byte(hmacSignature(c.Value, s.Token))) {
+		return fmt.Errorf("wrong state")
+	}
+	return nil
+}
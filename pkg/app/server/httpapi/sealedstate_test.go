@@ -0,0 +1,292 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+var testStateSecret = []byte(strings.Repeat("s", minStateSecretSize))
+
+func TestSealedState(t *testing.T) {
+	t.Parallel()
+	aead, err := newStateAEAD(testStateSecret)
+	require.NoError(t, err)
+	otherAEAD, err := newStateAEAD([]byte(strings.Repeat("o", minStateSecretSize)))
+	require.NoError(t, err)
+
+	s := &sealedState{
+		Token:        "token",
+		Binding:      "binding",
+		ProjectID:    "project",
+		SSOName:      "github",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ReturnTo:     "/settings",
+	}
+	state, err := encodeSealedState(aead, s, rand.Reader)
+	require.NoError(t, err)
+	assert.True(t, isSealedState(state))
+	for _, v := range []string{"project", "nonce", "verifier", "settings"} {
+		assert.NotContains(t, state, v)
+	}
+
+	got, err := decodeSealedState(aead, state)
+	require.NoError(t, err)
+	assert.Equal(t, s, got)
+
+	_, err = decodeSealedState(otherAEAD, state)
+	assert.Error(t, err)
+
+	_, err = decodeSealedState(nil, state)
+	assert.EqualError(t, err, "sealed state is not enabled")
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(state, "4."))
+	require.NoError(t, err)
+	payload[len(payload)-1] ^= 1
+	_, err = decodeSealedState(aead, "4."+base64.RawURLEncoding.EncodeToString(payload))
+	assert.Error(t, err)
+
+	// The version is authenticated along with the content.
+	_, err = decodeSealedState(aead, "5."+strings.TrimPrefix(state, "4."))
+	assert.EqualError(t, err, `unsupported state version "5"`)
+
+	for _, malformed := range []string{"4", "4.", "4.!!!", "4.AAAA"} {
+		_, err = decodeSealedState(aead, malformed)
+		assert.Error(t, err, malformed)
+	}
+}
+
+func TestSealedStateUnknownFields(t *testing.T) {
+	t.Parallel()
+	aead, err := newStateAEAD(testStateSecret)
+	require.NoError(t, err)
+
+	// The fields added by a newer server are ignored.
+	nonce := make([]byte, aead.NonceSize())
+	plaintext := []byte(`{"t":"token","b":"binding","p":"project","x":"added later"}`)
+	state := "4." + base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, []byte("4")))
+	got, err := decodeSealedState(aead, state)
+	require.NoError(t, err)
+	assert.Equal(t, &sealedState{Token: "token", Binding: "binding", ProjectID: "project"}, got)
+
+	// The required fields must be given.
+	plaintext = []byte(`{"t":"token","b":"binding"}`)
+	state = "4." + base64.RawURLEncoding.EncodeToString(aead.Seal(nonce, nonce, plaintext, []byte("4")))
+	_, err = decodeSealedState(aead, state)
+	assert.Error(t, err)
+}
+
+func TestValidateStateSecret(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, ValidateStateSecret(testStateSecret))
+	assert.NoError(t, ValidateStateSecret(append(testStateSecret, '\n')))
+	assert.Error(t, ValidateStateSecret([]byte("short")))
+	assert.Error(t, ValidateStateSecret(nil))
+}
+
+func TestCheckSealedState(t *testing.T) {
+	t.Parallel()
+	aead, err := newStateAEAD(testStateSecret)
+	require.NoError(t, err)
+	now := time.Now()
+	state, cookie, err := newSealedState(aead, "state-key", &sealedState{ProjectID: "project"}, rand.Reader, now)
+	require.NoError(t, err)
+	s, err := decodeSealedState(aead, state)
+	require.NoError(t, err)
+	_, otherCookie, err := newSealedState(aead, "state-key", &sealedState{ProjectID: "project"}, rand.Reader, now)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		cookie      string
+		key         string
+		now         time.Time
+		expectedErr string
+	}{
+		{
+			name:   "bound",
+			cookie: cookie,
+			key:    "state-key",
+			now:    now,
+		},
+		{
+			name:        "cookie of another flow",
+			cookie:      otherCookie,
+			key:         "state-key",
+			now:         now,
+			expectedErr: "wrong state",
+		},
+		{
+			name:        "missing cookie",
+			key:         "state-key",
+			now:         now,
+			expectedErr: "http: named cookie not present",
+		},
+		{
+			name:        "expired",
+			cookie:      cookie,
+			key:         "state-key",
+			now:         now.Add(time.Hour),
+			expectedErr: errStateExpired.Error(),
+		},
+		{
+			name:        "other state key",
+			cookie:      cookie,
+			key:         "other-key",
+			now:         now,
+			expectedErr: "invalid state",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: stateCookieKey, Value: tt.cookie})
+			}
+			err := checkSealedState(req, stateCookieKey, tt.key, s, 30*time.Minute, tt.now)
+			if tt.expectedErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, tt.expectedErr)
+		})
+	}
+}
+
+func TestSSOLoginSealedState(t *testing.T) {
+	t.Parallel()
+	h := newAuthHandler(nil, nil, "https://pipecd.example.com", "state-key", nil,
+		map[string]*model.ProjectSSOConfig{
+			"gitlab": {
+				Provider: model.ProjectSSOConfig_GITLAB,
+				Gitlab:   &model.ProjectSSOConfig_GitLab{ClientId: "client-id", RedirectUri: "https://pipecd.example.com/auth/callback"},
+			},
+		},
+		fakeProjectGetter{"project": {Id: "project", SharedSsoName: "gitlab"}},
+		true, zap.NewNop(), WithStateSecret(testStateSecret),
+	)
+
+	form := url.Values{projectFormKey: {"project"}, returnToFormKey: {"/settings"}}
+	req := httptest.NewRequest(http.MethodPost, loginPath, strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	h.handleSSOLogin(rec, req)
+	require.Equal(t, http.StatusFound, rec.Code)
+
+	u, err := url.Parse(rec.Header().Get("Location"))
+	require.NoError(t, err)
+	state := u.Query().Get("state")
+	// The project ID is only carried by the sealed state.
+	assert.NotContains(t, state, model.ProjectStateMarker)
+	s, err := decodeSealedState(h.stateAEAD, state)
+	require.NoError(t, err)
+	assert.Equal(t, "project", s.ProjectID)
+	assert.Equal(t, "/settings", s.ReturnTo)
+
+	var stateCookie string
+	for _, c := range rec.Result().Cookies() {
+		switch c.Name {
+		case stateCookieKey:
+			stateCookie = c.Value
+		case returnToCookieKey:
+			assert.Empty(t, c.Value)
+		}
+	}
+	cb := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	cb.AddCookie(&http.Cookie{Name: stateCookieKey, Value: stateCookie})
+	assert.NoError(t, checkSealedState(cb, stateCookieKey, "state-key", s, h.stateTTL, time.Now()))
+}
+
+func TestCallbackSealedState(t *testing.T) {
+	t.Parallel()
+	var got *UserRequest
+	h := &authHandler{
+		signer:   fakeSigner{},
+		stateKey: "state-key",
+		stateTTL: defaultStateTTL,
+		sharedSSOConfigs: map[string]*model.ProjectSSOConfig{
+			"oidc": {
+				Provider: model.ProjectSSOConfig_OIDC,
+				Oidc:     &model.ProjectSSOConfig_Oidc{PkceEnabled: true},
+			},
+		},
+		userResolvers: map[model.ProjectSSOConfig_Provider]UserResolver{
+			model.ProjectSSOConfig_OIDC: UserResolverFunc(func(_ context.Context, req *UserRequest) (*model.User, *oauth2.Token, error) {
+				got = req
+				return &model.User{
+					Username: "user",
+					Role:     &model.Role{ProjectId: "project", ProjectRbacRoles: []string{model.BuiltinRBACRoleViewer.String()}},
+				}, nil, nil
+			}),
+		},
+		projectGetter: fakeProjectGetter{
+			"project": {
+				Id:            "project",
+				SharedSsoName: "oidc",
+				UserGroups:    []*model.ProjectUserGroup{},
+			},
+		},
+		logger: zap.NewNop(),
+	}
+	WithStateSecret(testStateSecret)(h)
+
+	state, cookie, err := newSealedState(h.stateAEAD, h.stateKey, &sealedState{
+		ProjectID:    "project",
+		Nonce:        "nonce",
+		CodeVerifier: "verifier",
+		ReturnTo:     "/settings",
+	}, rand.Reader, time.Now())
+	require.NoError(t, err)
+
+	t.Run("logged in", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, callbackPath+"?"+url.Values{stateFormKey: {state}, authCodeFormKey: {"code"}}.Encode(), nil)
+		req.AddCookie(&http.Cookie{Name: stateCookieKey, Value: cookie})
+		rec := httptest.NewRecorder()
+		h.handleCallback(rec, req)
+
+		assert.Equal(t, http.StatusFound, rec.Code)
+		assert.Equal(t, "/settings", rec.Header().Get("Location"))
+		require.NotNil(t, got)
+		assert.Equal(t, "nonce", got.Nonce)
+		assert.Len(t, got.Options, 1)
+	})
+
+	t.Run("project mismatch", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, callbackPath+"?"+url.Values{stateFormKey: {state}, authCodeFormKey: {"code"}, projectFormKey: {"other"}}.Encode(), nil)
+		req.AddCookie(&http.Cookie{Name: stateCookieKey, Value: cookie})
+		rec := httptest.NewRecorder()
+		h.handleCallback(rec, req)
+
+		assert.Equal(t, http.StatusSeeOther, rec.Code)
+		assert.Equal(t, "/login?login_error=invalid_request", rec.Header().Get("Location"))
+	})
+}
@@ -0,0 +1,114 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+// WithSessionStore enables registering the issued tokens to the given store
+// so that they can be revoked before expiring.
+func WithSessionStore(store jwt.SessionStore) Option {
+	return func(h *authHandler) {
+		h.sessionStore = store
+	}
+}
+
+// registerSession records the session of the given claims if the session store is enabled.
+func (h *authHandler) registerSession(claims *jwt.Claims) error {
+	if h.sessionStore == nil {
+		return nil
+	}
+	return h.sessionStore.Register(claims)
+}
+
+// NewRevokeSessionsHandler returns an HTTP handler to revoke the sessions in the given store.
+// The session of a single token is revoked when the "id" parameter is given,
+// otherwise all sessions of the user given by the "project" and "user" parameters are revoked.
+func NewRevokeSessionsHandler(store jwt.SessionStore, logger *zap.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if id := r.FormValue("id"); id != "" {
+			if err := store.Revoke(id); err != nil {
+				logger.Error("failed to revoke session", zap.String("id", id), zap.Error(err))
+				http.Error(w, "Internal error", http.StatusInternalServerError)
+				return
+			}
+			logger.Info("revoked session", zap.String("id", id))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		projectID, user := r.FormValue("project"), r.FormValue("user")
+		if projectID == "" || user == "" {
+			http.Error(w, "Missing id or project and user", http.StatusBadRequest)
+			return
+		}
+		if err := store.RevokeAll(projectID, user); err != nil {
+			logger.Error("failed to revoke sessions",
+				zap.String("project-id", projectID),
+				zap.String("user", user),
+				zap.Error(err),
+			)
+			http.Error(w, "Internal error", http.StatusInternalServerError)
+			return
+		}
+		logger.Info("revoked all sessions of user",
+			zap.String("project-id", projectID),
+			zap.String("user", user),
+		)
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// SessionTerminator removes the cookies of the revoked sessions from the browser,
+// so that the web console goes back to the login page.
+type SessionTerminator struct {
+	// cookies makes the cookies with the same attributes as the ones of the login.
+	cookies cookieFactory
+}
+
+// NewSessionTerminator returns a SessionTerminator removing the cookies issued at login
+// by the handler created with the given options.
+func NewSessionTerminator(secureCookie bool, opts ...Option) *SessionTerminator {
+	h := &authHandler{
+		cookies: cookieFactory{secure: secureCookie},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return &SessionTerminator{cookies: h.cookies}
+}
+
+// Terminate returns the Set-Cookie header values expiring the token and the refresh token cookies.
+func (t *SessionTerminator) Terminate() []string {
+	cookies := []string{
+		t.cookies.expiredToken().String(),
+		t.cookies.expiredRefreshToken().String(),
+	}
+	if t.cookies.domain != "" {
+		// Also clear the host-only token cookie issued before the domain was configured.
+		cookies = append(cookies, t.cookies.expiredHostOnlyToken().String())
+	}
+	return cookies
+}
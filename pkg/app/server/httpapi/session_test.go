@@ -0,0 +1,126 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestRevokeSessionsHandler(t *testing.T) {
+	t.Parallel()
+
+	newClaims := func(user string) *jwt.Claims {
+		c := jwt.NewClaims(user, "", time.Hour, model.Role{ProjectId: "project-1"})
+		c.IssuedAt.Time = time.Now().Add(-time.Minute)
+		return c
+	}
+	store := jwt.NewCacheSessionStore(memorycache.NewCache())
+	var (
+		user1 = newClaims("user-1")
+		user2 = newClaims("user-2")
+		user3 = newClaims("user-3")
+	)
+	for _, c := range []*jwt.Claims{user1, user2, user3} {
+		require.NoError(t, store.Register(c))
+	}
+	handler := NewRevokeSessionsHandler(store, zap.NewNop())
+
+	testcases := []struct {
+		name         string
+		method       string
+		form         url.Values
+		expectedCode int
+	}{
+		{
+			name:         "method not allowed",
+			method:       http.MethodGet,
+			expectedCode: http.StatusMethodNotAllowed,
+		},
+		{
+			name:         "missing user",
+			method:       http.MethodPost,
+			form:         url.Values{"project": {"project-1"}},
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "revoke all sessions of user",
+			method:       http.MethodPost,
+			form:         url.Values{"project": {"project-1"}, "user": {"user-1"}},
+			expectedCode: http.StatusNoContent,
+		},
+		{
+			name:         "revoke single session",
+			method:       http.MethodPost,
+			form:         url.Values{"id": {user2.ID}},
+			expectedCode: http.StatusNoContent,
+		},
+	}
+	for _, tc := range testcases {
+		req := httptest.NewRequest(tc.method, "/sessions/revoke", strings.NewReader(tc.form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, tc.expectedCode, rec.Code, tc.name)
+	}
+
+	for _, tc := range []struct {
+		claims   *jwt.Claims
+		expected bool
+	}{
+		{claims: user1, expected: true},
+		{claims: user2, expected: true},
+		{claims: user3, expected: false},
+	} {
+		revoked, err := store.IsRevoked(tc.claims)
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, revoked, tc.claims.Subject)
+	}
+}
+
+func TestSessionTerminator(t *testing.T) {
+	t.Parallel()
+	terminator := NewSessionTerminator(true, WithCookieDomain("example.com"), WithCookiePath("/pipecd"))
+	cookies := (&http.Response{Header: http.Header{"Set-Cookie": terminator.Terminate()}}).Cookies()
+	require.Len(t, cookies, 3)
+
+	token := cookies[0]
+	assert.Equal(t, jwt.SignedTokenKey, token.Name)
+	assert.Empty(t, token.Value)
+	assert.Equal(t, -1, token.MaxAge)
+	assert.Equal(t, "example.com", token.Domain)
+	assert.Equal(t, "/pipecd", token.Path)
+	assert.True(t, token.Secure)
+
+	assert.Equal(t, refreshTokenCookieKey, cookies[1].Name)
+	assert.Equal(t, -1, cookies[1].MaxAge)
+
+	// The host-only token cookie is also removed.
+	assert.Equal(t, jwt.SignedTokenKey, cookies[2].Name)
+	assert.Empty(t, cookies[2].Domain)
+	assert.Equal(t, -1, cookies[2].MaxAge)
+}
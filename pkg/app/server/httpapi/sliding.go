@@ -0,0 +1,123 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"fmt"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+// minSessionExtension is the minimum extension of the session to re-issue the token,
+// which avoids re-issuing it on every request of the active user.
+const minSessionExtension = time.Minute
+
+// SlidingSession is the session which expires after being idle for IdleTimeout,
+// while it is extended on each request up to MaxLifetime after the login.
+// Zero IdleTimeout means the session expires after the fixed TTL.
+type SlidingSession struct {
+	IdleTimeout time.Duration
+	MaxLifetime time.Duration
+}
+
+func (s SlidingSession) enabled() bool {
+	return s.IdleTimeout > 0
+}
+
+// Validate checks the idle timeout is within the max lifetime.
+func (s SlidingSession) Validate() error {
+	if !s.enabled() {
+		return nil
+	}
+	if s.MaxLifetime < s.IdleTimeout {
+		return fmt.Errorf("session max lifetime %s must not be shorter than the idle timeout %s", s.MaxLifetime, s.IdleTimeout)
+	}
+	return nil
+}
+
+// WithSlidingSession makes the tokens issued at login expire after the idle timeout of the given session.
+// The sessions are extended by the SessionExtender created with the same session.
+func WithSlidingSession(s SlidingSession) Option {
+	return func(h *authHandler) {
+		h.slidingSession = s
+	}
+}
+
+// loginTokenTTL returns the TTL of the token issued at login,
+// which is the idle timeout for the sliding session.
+func (h *authHandler) loginTokenTTL(ttl time.Duration) time.Duration {
+	if h.slidingSession.enabled() && h.slidingSession.IdleTimeout < ttl {
+		return h.slidingSession.IdleTimeout
+	}
+	return ttl
+}
+
+// SessionExtender re-issues the token of the active sliding session with the refreshed expiry.
+type SessionExtender struct {
+	signer  jwt.Signer
+	session SlidingSession
+	// handler holds the cookie attributes and the clock, which are the same as the ones of the login.
+	handler *authHandler
+}
+
+// NewSessionExtender returns a SessionExtender of the given sliding session.
+// The re-issued token cookie has the same attributes as the one issued at login
+// by the handler created with the given options.
+func NewSessionExtender(signer jwt.Signer, session SlidingSession, secureCookie bool, opts ...Option) *SessionExtender {
+	h := &authHandler{
+		cookies: cookieFactory{secure: secureCookie},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return &SessionExtender{
+		signer:  signer,
+		session: session,
+		handler: h,
+	}
+}
+
+// Extend returns the Set-Cookie header value of the token extended until the idle timeout from now,
+// which never exceeds the max lifetime from the login. Empty is returned if the token is not extended.
+// The token keeps its ID and issued time so that the session can still be revoked.
+func (e *SessionExtender) Extend(claims *jwt.Claims) (string, error) {
+	authTime := claims.AuthTime
+	if authTime == nil {
+		authTime = claims.IssuedAt
+	}
+	if authTime == nil || claims.ExpiresAt == nil {
+		return "", nil
+	}
+
+	expiry := e.handler.now().Add(e.session.IdleTimeout)
+	if limit := authTime.Add(e.session.MaxLifetime); expiry.After(limit) {
+		expiry = limit
+	}
+	if expiry.Sub(claims.ExpiresAt.Time) < minSessionExtension {
+		return "", nil
+	}
+
+	extended := claims.Clone()
+	extended.ExpiresAt = jwtgo.NewNumericDate(expiry)
+	extended.AuthTime = authTime
+	signedToken, err := e.signer.Sign(extended)
+	if err != nil {
+		return "", err
+	}
+	return e.handler.cookies.token(signedToken).String(), nil
+}
@@ -0,0 +1,158 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+type recordingSigner struct {
+	signed *jwt.Claims
+}
+
+func (s *recordingSigner) Sign(claims *jwt.Claims) (string, error) {
+	s.signed = claims
+	return "signed:" + claims.ID, nil
+}
+
+func TestSessionExtenderExtend(t *testing.T) {
+	t.Parallel()
+	login := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	session := SlidingSession{IdleTimeout: time.Hour, MaxLifetime: 8 * time.Hour}
+
+	cases := []struct {
+		name       string
+		now        time.Time
+		expiresAt  time.Time
+		authTime   *jwtgo.NumericDate
+		wantExpiry time.Time
+	}{
+		{
+			name:       "extended by the idle timeout",
+			now:        login.Add(30 * time.Minute),
+			expiresAt:  login.Add(time.Hour),
+			authTime:   jwtgo.NewNumericDate(login),
+			wantExpiry: login.Add(90 * time.Minute),
+		},
+		{
+			name:       "capped by the max lifetime",
+			now:        login.Add(7*time.Hour + 30*time.Minute),
+			expiresAt:  login.Add(7*time.Hour + 40*time.Minute),
+			authTime:   jwtgo.NewNumericDate(login),
+			wantExpiry: login.Add(8 * time.Hour),
+		},
+		{
+			name:      "not extended when recently extended",
+			now:       login.Add(30 * time.Minute),
+			expiresAt: login.Add(90*time.Minute - 30*time.Second),
+			authTime:  jwtgo.NewNumericDate(login),
+		},
+		{
+			name:      "not extended beyond the max lifetime",
+			now:       login.Add(7*time.Hour + 30*time.Minute),
+			expiresAt: login.Add(8 * time.Hour),
+			authTime:  jwtgo.NewNumericDate(login),
+		},
+		{
+			name:       "issued time is used without auth time",
+			now:        login.Add(7*time.Hour + 30*time.Minute),
+			expiresAt:  login.Add(7*time.Hour + 40*time.Minute),
+			wantExpiry: login.Add(8 * time.Hour),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			signer := &recordingSigner{}
+			e := NewSessionExtender(signer, session, true, WithClock(fakeClock{now: tc.now}))
+			claims := &jwt.Claims{
+				RegisteredClaims: jwtgo.RegisteredClaims{
+					ID:        "token-id",
+					Subject:   "user",
+					IssuedAt:  jwtgo.NewNumericDate(login),
+					ExpiresAt: jwtgo.NewNumericDate(tc.expiresAt),
+				},
+				AuthTime: tc.authTime,
+			}
+
+			cookie, err := e.Extend(claims)
+			require.NoError(t, err)
+			if tc.wantExpiry.IsZero() {
+				assert.Empty(t, cookie)
+				assert.Nil(t, signer.signed)
+				return
+			}
+			require.NotNil(t, signer.signed)
+			assert.Equal(t, tc.wantExpiry, signer.signed.ExpiresAt.Time.UTC())
+			assert.Equal(t, "token-id", signer.signed.ID)
+			assert.Equal(t, login, signer.signed.IssuedAt.Time.UTC())
+			assert.Equal(t, login, signer.signed.AuthTime.Time.UTC())
+			// The claims of the current request are kept as is.
+			assert.Equal(t, tc.expiresAt, claims.ExpiresAt.Time.UTC())
+			assert.NotEmpty(t, cookie)
+		})
+	}
+}
+
+func TestSessionExtenderCookie(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)
+	e := NewSessionExtender(
+		&recordingSigner{},
+		SlidingSession{IdleTimeout: time.Hour, MaxLifetime: 8 * time.Hour},
+		true,
+		WithClock(fakeClock{now: now}),
+		WithCookieSameSite(http.SameSiteStrictMode),
+		WithCookiePath("/pipecd"),
+	)
+	value, err := e.Extend(&jwt.Claims{
+		RegisteredClaims: jwtgo.RegisteredClaims{
+			ID:        "token-id",
+			IssuedAt:  jwtgo.NewNumericDate(now.Add(-30 * time.Minute)),
+			ExpiresAt: jwtgo.NewNumericDate(now.Add(30 * time.Minute)),
+		},
+	})
+	require.NoError(t, err)
+
+	cookies := (&http.Response{Header: http.Header{"Set-Cookie": {value}}}).Cookies()
+	require.Len(t, cookies, 1)
+	c := cookies[0]
+	assert.Equal(t, "signed:token-id", c.Value)
+	assert.Equal(t, "/pipecd", c.Path)
+	assert.True(t, c.Secure)
+	assert.True(t, c.HttpOnly)
+	assert.Equal(t, http.SameSiteStrictMode, c.SameSite)
+}
+
+func TestSlidingSessionLoginTokenTTL(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{}
+	assert.Equal(t, defaultTokenTTL, h.loginTokenTTL(defaultTokenTTL))
+
+	WithSlidingSession(SlidingSession{IdleTimeout: time.Hour, MaxLifetime: 8 * time.Hour})(h)
+	assert.Equal(t, time.Hour, h.loginTokenTTL(defaultTokenTTL))
+	assert.Equal(t, 30*time.Minute, h.loginTokenTTL(30*time.Minute))
+
+	assert.Error(t, SlidingSession{IdleTimeout: 2 * time.Hour, MaxLifetime: time.Hour}.Validate())
+	assert.NoError(t, SlidingSession{MaxLifetime: time.Hour}.Validate())
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+// defaultDecryptedSSOTTL is how long the decrypted SSO configurations are reused.
+const defaultDecryptedSSOTTL = time.Minute
+
+type decryptedSSOEntry struct {
+	sso       *model.ProjectSSOConfig
+	expiresAt time.Time
+}
+
+// decryptedSSOCache keeps the decrypted SSO configurations for a short time
+// so that the concurrent logins to the same project do not decrypt them again.
+// The entries are keyed by the project ID and the hash of the encrypted configuration,
+// so the entry of the old configuration is never used once the configuration is changed.
+type decryptedSSOCache struct {
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]decryptedSSOEntry
+}
+
+func newDecryptedSSOCache(ttl time.Duration) *decryptedSSOCache {
+	return &decryptedSSOCache{
+		ttl:     ttl,
+		entries: make(map[string]decryptedSSOEntry),
+	}
+}
+
+// get returns a copy of the decrypted configuration cached for the given key.
+func (c *decryptedSSOCache) get(key string, now time.Time) (*model.ProjectSSOConfig, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if !now.Before(e.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return proto.Clone(e.sso).(*model.ProjectSSOConfig), true
+}
+
+// put caches a copy of the decrypted configuration and removes the expired entries.
+func (c *decryptedSSOCache) put(key string, sso *model.ProjectSSOConfig, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for k, e := range c.entries {
+		if !now.Before(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = decryptedSSOEntry{
+		sso:       proto.Clone(sso).(*model.ProjectSSOConfig),
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+// decryptedSSOCacheKey returns the key of the given encrypted configuration of the project.
+func decryptedSSOCacheKey(projectID string, sso *model.ProjectSSOConfig) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(sso)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash the SSO configuration of project %s", projectID)
+	}
+	sum := sha256.Sum256(data)
+	return projectID + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// decryptSSO returns the decrypted copy of the given SSO configuration of the project.
+// The given configuration is left encrypted.
+func (h *authHandler) decryptSSO(projectID string, sso *model.ProjectSSOConfig) (*model.ProjectSSOConfig, error) {
+	if h.decryptedSSOs == nil {
+		decrypted := proto.Clone(sso).(*model.ProjectSSOConfig)
+		if err := decrypted.Decrypt(h.decrypter); err != nil {
+			return nil, err
+		}
+		return decrypted, nil
+	}
+
+	key, err := decryptedSSOCacheKey(projectID, sso)
+	if err != nil {
+		return nil, err
+	}
+	now := h.now()
+	if decrypted, ok := h.decryptedSSOs.get(key, now); ok {
+		return decrypted, nil
+	}
+	decrypted := proto.Clone(sso).(*model.ProjectSSOConfig)
+	if err := decrypted.Decrypt(h.decrypter); err != nil {
+		return nil, err
+	}
+	h.decryptedSSOs.put(key, decrypted, now)
+	return decrypted, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+type countingDecrypter struct {
+	calls atomic.Int32
+	err   error
+}
+
+func (d *countingDecrypter) Decrypt(text string) (string, error) {
+	d.calls.Add(1)
+	if d.err != nil {
+		return "", d.err
+	}
+	return strings.TrimPrefix(text, "encrypted-"), nil
+}
+
+func newEncryptedGitHubConfig(secret string) *model.ProjectSSOConfig {
+	return &model.ProjectSSOConfig{
+		Provider: model.ProjectSSOConfig_GITHUB,
+		Github: &model.ProjectSSOConfig_GitHub{
+			ClientId:     "encrypted-id",
+			ClientSecret: "encrypted-" + secret,
+		},
+	}
+}
+
+func TestDecryptSSO(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := &fakeClock{now: now}
+	d := &countingDecrypter{}
+	h := &authHandler{
+		decrypter:     d,
+		decryptedSSOs: newDecryptedSSOCache(time.Minute),
+		clock:         clock,
+	}
+
+	sso := newEncryptedGitHubConfig("secret")
+	got, err := h.decryptSSO("project", sso)
+	require.NoError(t, err)
+	assert.Equal(t, "secret", got.Github.ClientSecret)
+	// The given configuration is left encrypted.
+	assert.Equal(t, "encrypted-secret", sso.Github.ClientSecret)
+	calls := d.calls.Load()
+
+	// The decrypted configuration is reused and cannot be modified by the callers.
+	got.Github.ClientSecret = "modified"
+	got, err = h.decryptSSO("project", newEncryptedGitHubConfig("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", got.Github.ClientSecret)
+	assert.Equal(t, calls, d.calls.Load())
+
+	// The changed configuration and the other projects are decrypted again.
+	got, err = h.decryptSSO("project", newEncryptedGitHubConfig("rotated"))
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", got.Github.ClientSecret)
+	assert.Equal(t, 2*calls, d.calls.Load())
+
+	_, err = h.decryptSSO("other", newEncryptedGitHubConfig("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, 3*calls, d.calls.Load())
+
+	// The expired configuration is decrypted again.
+	clock.now = now.Add(time.Minute)
+	_, err = h.decryptSSO("project", newEncryptedGitHubConfig("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, 4*calls, d.calls.Load())
+	assert.Len(t, h.decryptedSSOs.entries, 1)
+}
+
+func TestDecryptSSOFailure(t *testing.T) {
+	t.Parallel()
+	d := &countingDecrypter{err: errors.New("cipher: message authentication failed")}
+	h := &authHandler{
+		decrypter:     d,
+		decryptedSSOs: newDecryptedSSOCache(time.Minute),
+	}
+
+	_, err := h.decryptSSO("project", newEncryptedGitHubConfig("secret"))
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+	assert.Empty(t, h.decryptedSSOs.entries)
+
+	// The failure is not cached.
+	d.err = nil
+	got, err := h.decryptSSO("project", newEncryptedGitHubConfig("secret"))
+	require.NoError(t, err)
+	assert.Equal(t, "secret", got.Github.ClientSecret)
+}
+
+func TestDecryptSSOWithoutCache(t *testing.T) {
+	t.Parallel()
+	d := &countingDecrypter{}
+	h := &authHandler{decrypter: d}
+
+	sso := newEncryptedGitHubConfig("secret")
+	for i := 0; i < 2; i++ {
+		got, err := h.decryptSSO("project", sso)
+		require.NoError(t, err)
+		assert.Equal(t, "secret", got.Github.ClientSecret)
+	}
+	assert.Equal(t, "encrypted-secret", sso.Github.ClientSecret)
+	assert.Equal(t, int32(4), d.calls.Load())
+}
+
+func TestDecryptSSOConcurrently(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		decrypter:     &countingDecrypter{},
+		decryptedSSOs: newDecryptedSSOCache(time.Minute),
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := h.decryptSSO("project", newEncryptedGitHubConfig("secret"))
+			assert.NoError(t, err)
+			assert.Equal(t, "secret", got.Github.ClientSecret)
+			got.Github.ClientSecret = "modified"
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,345 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/datastore"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const ssoProbeTimeout = 5 * time.Second
+
+type projectLister interface {
+	List(ctx context.Context, opts datastore.ListOptions) ([]model.Project, error)
+}
+
+// ssoProbeStatus is the reachability of the identity provider of an SSO configuration.
+type ssoProbeStatus struct {
+	// Name is the name of the shared SSO configuration or the id of the project
+	// using its own SSO configuration.
+	Name     string `json:"name"`
+	Shared   bool   `json:"shared"`
+	Provider string `json:"provider"`
+	Target   string `json:"target,omitempty"`
+	Ready    bool   `json:"ready"`
+	Error    string `json:"error,omitempty"`
+}
+
+type ssoReadiness struct {
+	Ready     bool             `json:"ready"`
+	CheckedAt time.Time        `json:"checkedAt"`
+	Providers []ssoProbeStatus `json:"providers"`
+}
+
+type ssoReadinessChecker struct {
+	sharedSSOConfigs map[string]*model.ProjectSSOConfig
+	projectLister    projectLister
+	cacheTTL         time.Duration
+	httpClient       *http.Client
+	dialer           *net.Dialer
+	logger           *zap.Logger
+
+	mu      sync.Mutex
+	result  *ssoReadiness
+	nowFunc func() time.Time
+}
+
+// NewSSOReadinessHandler returns an HTTP handler reporting whether the identity providers
+// of the shared SSO configurations and the projects' own SSO configurations are reachable.
+// It responds 503 if any of them is unreachable. The result is cached for the given TTL
+// to avoid sending requests to the identity providers on every probe.
+func NewSSOReadinessHandler(sharedSSOConfigs map[string]*model.ProjectSSOConfig, projectLister projectLister, cacheTTL time.Duration, logger *zap.Logger) http.Handler {
+	return &ssoReadinessChecker{
+		sharedSSOConfigs: sharedSSOConfigs,
+		projectLister:    projectLister,
+		cacheTTL:         cacheTTL,
+		httpClient:       &http.Client{Timeout: ssoProbeTimeout},
+		dialer:           &net.Dialer{Timeout: ssoProbeTimeout},
+		logger:           logger.Named("sso-readiness"),
+		nowFunc:          time.Now,
+	}
+}
+
+func (c *ssoReadinessChecker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	result := c.check(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	if !result.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(result)
+}
+
+// check returns the cached result or probes all identity providers if it is expired.
+// The lock is held while probing so that concurrent probes share the same result.
+func (c *ssoReadinessChecker) check(ctx context.Context) *ssoReadiness {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.nowFunc()
+	if c.result != nil && now.Sub(c.result.CheckedAt) < c.cacheTTL {
+		return c.result
+	}
+
+	result := &ssoReadiness{
+		Ready:     true,
+		CheckedAt: now,
+		Providers: c.probeAll(ctx),
+	}
+	for _, s := range result.Providers {
+		if !s.Ready {
+			result.Ready = false
+			c.logger.Warn("sso provider is unreachable",
+				zap.String("name", s.Name),
+				zap.String("provider", s.Provider),
+				zap.String("target", s.Target),
+				zap.String("error", s.Error),
+			)
+		}
+	}
+	c.result = result
+	return result
+}
+
+func (c *ssoReadinessChecker) probeAll(ctx context.Context) []ssoProbeStatus {
+	statuses := make([]ssoProbeStatus, 0, len(c.sharedSSOConfigs))
+	for name, sso := range c.sharedSSOConfigs {
+		s := c.probe(ctx, sso)
+		s.Name, s.Shared = name, true
+		statuses = append(statuses, s)
+	}
+
+	projects, err := c.projectLister.List(ctx, datastore.ListOptions{})
+	if err != nil {
+		statuses = append(statuses, ssoProbeStatus{
+			Name:  "projects",
+			Error: fmt.Sprintf("failed to list projects: %v", err),
+		})
+	}
+	for i := range projects {
+		p := &projects[i]
+		if p.SharedSsoName != "" || p.Sso == nil {
+			continue
+		}
+		s := c.probe(ctx, p.Sso)
+		s.Name = p.Id
+		statuses = append(statuses, s)
+	}
+
+	sort.Slice(statuses, func(i, j int) bool {
+		if statuses[i].Shared != statuses[j].Shared {
+			return statuses[i].Shared
+		}
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+func (c *ssoReadinessChecker) probe(ctx context.Context, sso *model.ProjectSSOConfig) ssoProbeStatus {
+	s := ssoProbeStatus{Provider: sso.Provider.String()}
+
+	target, err := ssoProbeTarget(sso)
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	// Nothing to reach, e.g. SAML with the inline metadata.
+	if target == nil {
+		s.Ready = true
+		return s
+	}
+	s.Target = target.String()
+
+	ctx, cancel := context.WithTimeout(ctx, ssoProbeTimeout)
+	defer cancel()
+
+	if target.Scheme == "ldap" || target.Scheme == "ldaps" {
+		err = c.dial(ctx, target)
+	} else {
+		err = c.get(ctx, target, ssoProxyURL(sso))
+	}
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+	s.Ready = true
+	return s
+}
+
+// get sends a GET request to the target.
+// Any response except the server errors means the provider is reachable,
+// while the discovery documents must be found.
+func (c *ssoReadinessChecker) get(ctx context.Context, target *url.URL, proxy string) error {
+	client, err := ssoHTTPClient(c.httpClient, proxy)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	if strings.HasSuffix(target.Path, "/.well-known/openid-configuration") && resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// dial opens and closes a TCP connection to the LDAP server.
+func (c *ssoReadinessChecker) dial(ctx context.Context, target *url.URL) error {
+	conn, err := c.dialer.DialContext(ctx, "tcp", target.Host)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// ssoProbeTarget returns the endpoint used to check the reachability of the identity provider.
+// Nil is returned if there is nothing to check.
+func ssoProbeTarget(sso *model.ProjectSSOConfig) (*url.URL, error) {
+	var target string
+	switch sso.Provider {
+	case model.ProjectSSOConfig_GITHUB:
+		if sso.Github == nil {
+			return nil, fmt.Errorf("missing GitHub oauth in the SSO configuration")
+		}
+		target = "https://api.github.com"
+		if u := sso.Github.APIURL(); u != "" {
+			target = u
+		}
+	case model.ProjectSSOConfig_GITLAB:
+		if sso.Gitlab == nil {
+			return nil, fmt.Errorf("missing GitLab oauth in the SSO configuration")
+		}
+		target = "https://gitlab.com"
+		if sso.Gitlab.BaseUrl != "" {
+			target = sso.Gitlab.BaseUrl
+		}
+	case model.ProjectSSOConfig_BITBUCKET:
+		if sso.Bitbucket == nil {
+			return nil, fmt.Errorf("missing Bitbucket oauth in the SSO configuration")
+		}
+		base, err := sso.Bitbucket.BaseURL()
+		if err != nil {
+			return nil, err
+		}
+		target = base
+	case model.ProjectSSOConfig_GOOGLE:
+		target = discoveryURL("https://accounts.google.com")
+	case model.ProjectSSOConfig_AZUREAD:
+		if sso.AzureAd == nil {
+			return nil, fmt.Errorf("missing Azure AD oauth in the SSO configuration")
+		}
+		target = discoveryURL("https://login.microsoftonline.com/" + sso.AzureAd.TenantOrDefault() + "/v2.0")
+	case model.ProjectSSOConfig_OKTA:
+		if sso.Okta == nil {
+			return nil, fmt.Errorf("missing Okta oauth in the SSO configuration")
+		}
+		target = discoveryURL(sso.Okta.Issuer())
+	case model.ProjectSSOConfig_OIDC:
+		if sso.Oidc == nil {
+			return nil, fmt.Errorf("missing OIDC oauth in the SSO configuration")
+		}
+		target = discoveryURL(sso.Oidc.Issuer)
+	case model.ProjectSSOConfig_LDAP:
+		if sso.Ldap == nil {
+			return nil, fmt.Errorf("missing LDAP in the SSO configuration")
+		}
+		u, err := url.Parse(sso.Ldap.Url)
+		if err != nil {
+			return nil, err
+		}
+		if u.Port() == "" {
+			port := "389"
+			if u.Scheme == "ldaps" {
+				port = "636"
+			}
+			u.Host = net.JoinHostPort(u.Hostname(), port)
+		}
+		return u, nil
+	case model.ProjectSSOConfig_SAML:
+		if sso.Saml == nil {
+			return nil, fmt.Errorf("missing SAML in the SSO configuration")
+		}
+		if sso.Saml.IdpMetadataUrl == "" {
+			return nil, nil
+		}
+		target = sso.Saml.IdpMetadataUrl
+	default:
+		return nil, fmt.Errorf("not implemented")
+	}
+	return url.Parse(target)
+}
+
+func discoveryURL(issuer string) string {
+	return strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+}
+
+func ssoProxyURL(sso *model.ProjectSSOConfig) string {
+	switch sso.Provider {
+	case model.ProjectSSOConfig_GITHUB:
+		return sso.Github.GetProxyUrl()
+	case model.ProjectSSOConfig_GITLAB:
+		return sso.Gitlab.GetProxyUrl()
+	case model.ProjectSSOConfig_BITBUCKET:
+		return sso.Bitbucket.GetProxyUrl()
+	case model.ProjectSSOConfig_GOOGLE:
+		return sso.Google.GetProxyUrl()
+	case model.ProjectSSOConfig_AZUREAD:
+		return sso.AzureAd.GetProxyUrl()
+	case model.ProjectSSOConfig_OKTA:
+		return sso.Okta.GetProxyUrl()
+	case model.ProjectSSOConfig_OIDC:
+		return sso.Oidc.GetProxyUrl()
+	default:
+		return ""
+	}
+}
+
+// ssoHTTPClient returns the client sending the requests via the given proxy if set.
+func ssoHTTPClient(base *http.Client, proxy string) (*http.Client, error) {
+	if proxy == "" {
+		return base, nil
+	}
+	proxyURL, err := url.Parse(proxy)
+	if err != nil {
+		return nil, err
+	}
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.Proxy = http.ProxyURL(proxyURL)
+	return &http.Client{Transport: t, Timeout: base.Timeout}, nil
+}
@@ -0,0 +1,187 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/datastore"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+type fakeProjectLister struct {
+	projects []model.Project
+	err      error
+	calls    int
+}
+
+func (l *fakeProjectLister) List(_ context.Context, _ datastore.ListOptions) ([]model.Project, error) {
+	l.calls++
+	return l.projects, l.err
+}
+
+func TestSSOProbeTarget(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		sso     *model.ProjectSSOConfig
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "github default",
+			sso:  &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_GITHUB, Github: &model.ProjectSSOConfig_GitHub{}},
+			want: "https://api.github.com",
+		},
+		{
+			name: "oidc discovery",
+			sso:  &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_OIDC, Oidc: &model.ProjectSSOConfig_Oidc{Issuer: "https://idp.example.com/"}},
+			want: "https://idp.example.com/.well-known/openid-configuration",
+		},
+		{
+			name: "azure ad default tenant",
+			sso:  &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_AZUREAD, AzureAd: &model.ProjectSSOConfig_AzureAD{}},
+			want: "https://login.microsoftonline.com/organizations/v2.0/.well-known/openid-configuration",
+		},
+		{
+			name: "ldaps default port",
+			sso:  &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_LDAP, Ldap: &model.ProjectSSOConfig_Ldap{Url: "ldaps://ldap.example.com"}},
+			want: "ldaps://ldap.example.com:636",
+		},
+		{
+			name: "saml inline metadata",
+			sso:  &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_SAML, Saml: &model.ProjectSSOConfig_Saml{IdpMetadata: "<xml/>"}},
+		},
+		{
+			name:    "missing config",
+			sso:     &model.ProjectSSOConfig{Provider: model.ProjectSSOConfig_OIDC},
+			wantErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := ssoProbeTarget(tc.sso)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.want == "" {
+				assert.Nil(t, got)
+				return
+			}
+			assert.Equal(t, tc.want, got.String())
+		})
+	}
+}
+
+func TestSSOReadinessHandler(t *testing.T) {
+	t.Parallel()
+
+	idp := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/good/.well-known/openid-configuration" {
+			w.Write([]byte("{}"))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer idp.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	shared := map[string]*model.ProjectSSOConfig{
+		"good": {
+			Provider: model.ProjectSSOConfig_OIDC,
+			Oidc:     &model.ProjectSSOConfig_Oidc{Issuer: idp.URL + "/good"},
+		},
+	}
+	lister := &fakeProjectLister{
+		projects: []model.Project{
+			{
+				Id: "ldap-project",
+				Sso: &model.ProjectSSOConfig{
+					Provider: model.ProjectSSOConfig_LDAP,
+					Ldap:     &model.ProjectSSOConfig_Ldap{Url: "ldap://" + ln.Addr().String()},
+				},
+			},
+			{
+				Id:            "shared-project",
+				SharedSsoName: "good",
+			},
+		},
+	}
+	h := NewSSOReadinessHandler(shared, lister, time.Minute, zap.NewNop()).(*ssoReadinessChecker)
+	now := time.Now()
+	h.nowFunc = func() time.Time { return now }
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/sso", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var got ssoReadiness
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.True(t, got.Ready)
+	require.Len(t, got.Providers, 2)
+	assert.Equal(t, "good", got.Providers[0].Name)
+	assert.True(t, got.Providers[0].Shared)
+	assert.Equal(t, "ldap-project", got.Providers[1].Name)
+	assert.Equal(t, "LDAP", got.Providers[1].Provider)
+
+	// The cached result is returned until the TTL passes.
+	shared["bad"] = &model.ProjectSSOConfig{
+		Provider: model.ProjectSSOConfig_OIDC,
+		Oidc:     &model.ProjectSSOConfig_Oidc{Issuer: idp.URL + "/bad"},
+	}
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/sso", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 1, lister.calls)
+
+	now = now.Add(time.Minute)
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/sso", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.Equal(t, 2, lister.calls)
+
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+	assert.False(t, got.Ready)
+	assert.Equal(t, "bad", got.Providers[0].Name)
+	assert.False(t, got.Providers[0].Ready)
+	assert.NotEmpty(t, got.Providers[0].Error)
+}
+
+func TestSSOReadinessHandler_ListError(t *testing.T) {
+	t.Parallel()
+
+	h := NewSSOReadinessHandler(nil, &fakeProjectLister{err: errors.New("datastore is down")}, time.Minute, zap.NewNop())
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/healthz/sso", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+}
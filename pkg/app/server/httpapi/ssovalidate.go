@@ -0,0 +1,311 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oidc"
+)
+
+const maxSSOValidationBodySize = 1 << 20
+
+const (
+	ssoCheckConfig            = "config"
+	ssoCheckClientCredentials = "client_credentials"
+	ssoCheckRedirectURI       = "redirect_uri"
+	ssoCheckDiscovery         = "discovery"
+	ssoCheckJWKS              = "jwks"
+	ssoCheckReachability      = "reachability"
+)
+
+// ssoCheck is the result of a check of the SSO configuration.
+type ssoCheck struct {
+	Name   string `json:"name"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ssoValidationReport struct {
+	Valid    bool       `json:"valid"`
+	Provider string     `json:"provider"`
+	Checks   []ssoCheck `json:"checks"`
+}
+
+func (r *ssoValidationReport) add(name, detail string, err error) {
+	c := ssoCheck{Name: name, Passed: err == nil, Detail: detail}
+	if err != nil {
+		c.Error = err.Error()
+		r.Valid = false
+	}
+	r.Checks = append(r.Checks, c)
+}
+
+type ssoValidator struct {
+	// prober reuses the reachability checks of the SSO readiness.
+	prober *ssoReadinessChecker
+	logger *zap.Logger
+}
+
+// NewSSOValidationHandler returns an HTTP handler checking the SSO configuration given as the JSON
+// request body in the same format as the shared SSO configuration of the control plane.
+// It responds the report of the configuration, the client credentials, the redirect URI and the
+// reachability of the identity provider, including its discovery document and JWKS for OpenID providers,
+// without going through the login flow.
+func NewSSOValidationHandler(logger *zap.Logger) http.Handler {
+	logger = logger.Named("sso-validation")
+	return &ssoValidator{
+		prober: &ssoReadinessChecker{
+			httpClient: &http.Client{Timeout: ssoProbeTimeout},
+			dialer:     &net.Dialer{Timeout: ssoProbeTimeout},
+			logger:     logger,
+		},
+		logger: logger,
+	}
+}
+
+func (v *ssoValidator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxSSOValidationBodySize))
+	if err != nil {
+		http.Error(w, "Unable to read the request body", http.StatusBadRequest)
+		return
+	}
+	var sso model.ProjectSSOConfig
+	if err := protojson.Unmarshal(body, &sso); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid SSO configuration: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	report := v.validate(r.Context(), &sso)
+	if !report.Valid {
+		v.logger.Info("sso configuration is invalid", zap.String("provider", report.Provider))
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(report)
+}
+
+func (v *ssoValidator) validate(ctx context.Context, sso *model.ProjectSSOConfig) *ssoValidationReport {
+	report := &ssoValidationReport{
+		Valid:    true,
+		Provider: sso.Provider.String(),
+	}
+	err := sso.Validate()
+	if err == nil && sso.Oidc != nil {
+		err = sso.Oidc.ValidateScopes()
+	}
+	if err == nil && sso.Oidc != nil {
+		err = sso.Oidc.ValidateExtraHeaders()
+	}
+	if err == nil && sso.Github != nil {
+		err = sso.Github.ValidateExtraHeaders()
+	}
+	report.add(ssoCheckConfig, "", err)
+
+	if ok, err := checkSSOClientCredentials(sso); ok {
+		report.add(ssoCheckClientCredentials, "", err)
+	}
+	if uri, ok := ssoRedirectURI(sso); ok {
+		report.add(ssoCheckRedirectURI, uri, checkSSORedirectURI(uri, sso.AllowedRedirectUris))
+	}
+
+	issuer, strict := ssoIssuer(sso)
+	if issuer == "" {
+		s := v.prober.probe(ctx, sso)
+		var err error
+		if !s.Ready {
+			err = errors.New(s.Error)
+		}
+		report.add(ssoCheckReachability, s.Target, err)
+		return report
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ssoProbeTimeout)
+	defer cancel()
+	client, err := ssoHTTPClient(v.prober.httpClient, ssoProxyURL(sso))
+	if err != nil {
+		report.add(ssoCheckDiscovery, discoveryURL(issuer), err)
+		return report
+	}
+	meta, err := oidc.FetchProviderMetadata(ctx, issuer, client)
+	if err == nil && strict && meta.Issuer != issuer {
+		err = fmt.Errorf("issuer %q in the discovery document does not match the configured issuer", meta.Issuer)
+	}
+	report.add(ssoCheckDiscovery, discoveryURL(issuer), err)
+	if meta == nil {
+		report.add(ssoCheckJWKS, "", errors.New("skipped since the discovery document is unavailable"))
+		return report
+	}
+	n, err := oidc.FetchKeyCount(ctx, meta.JWKSURL, client)
+	if err == nil && n == 0 {
+		err = errors.New("no key found")
+	}
+	report.add(ssoCheckJWKS, meta.JWKSURL, err)
+	return report
+}
+
+// checkSSOClientCredentials checks the client of the OAuth provider has the credentials.
+// False is returned if the provider does not use them.
+func checkSSOClientCredentials(sso *model.ProjectSSOConfig) (bool, error) {
+	var id, secret string
+	switch sso.Provider {
+	case model.ProjectSSOConfig_GITHUB:
+		id, secret = sso.GetGithub().GetClientId(), sso.GetGithub().GetClientSecret()
+	case model.ProjectSSOConfig_GOOGLE:
+		id, secret = sso.GetGoogle().GetClientId(), sso.GetGoogle().GetClientSecret()
+	case model.ProjectSSOConfig_GITLAB:
+		id, secret = sso.GetGitlab().GetClientId(), sso.GetGitlab().GetClientSecret()
+	case model.ProjectSSOConfig_AZUREAD:
+		id, secret = sso.GetAzureAd().GetClientId(), sso.GetAzureAd().GetClientSecret()
+	case model.ProjectSSOConfig_OKTA:
+		id, secret = sso.GetOkta().GetClientId(), sso.GetOkta().GetClientSecret()
+	case model.ProjectSSOConfig_BITBUCKET:
+		id, secret = sso.GetBitbucket().GetClientId(), sso.GetBitbucket().GetClientSecret()
+	case model.ProjectSSOConfig_OIDC:
+		o := sso.GetOidc()
+		if o.GetClientId() == "" {
+			return true, errors.New("missing client id")
+		}
+		if o.GetClientCertificate() != "" || o.GetClientKey() != "" {
+			if o.GetClientCertificate() == "" || o.GetClientKey() == "" {
+				return true, errors.New("both client certificate and client key are required")
+			}
+			return true, nil
+		}
+		if o.GetClientAssertionKey() != "" {
+			return true, nil
+		}
+		if o.IsPublicClient() && o.GetPkceEnabled() {
+			// The public client has no secret and is protected by PKCE instead.
+			return true, nil
+		}
+		id, secret = o.GetClientId(), o.GetClientSecret()
+	default:
+		return false, nil
+	}
+	switch {
+	case id == "":
+		return true, errors.New("missing client id")
+	case secret == "":
+		return true, errors.New("missing client secret")
+	default:
+		return true, nil
+	}
+}
+
+// ssoRedirectURI returns the redirect URI of the OAuth provider.
+// False is returned if it is not configurable, e.g. GitHub which uses the address of the control plane.
+func ssoRedirectURI(sso *model.ProjectSSOConfig) (string, bool) {
+	switch sso.Provider {
+	case model.ProjectSSOConfig_GOOGLE:
+		return sso.GetGoogle().GetRedirectUri(), true
+	case model.ProjectSSOConfig_GITLAB:
+		return sso.GetGitlab().GetRedirectUri(), true
+	case model.ProjectSSOConfig_AZUREAD:
+		return sso.GetAzureAd().GetRedirectUri(), true
+	case model.ProjectSSOConfig_OKTA:
+		return sso.GetOkta().GetRedirectUri(), true
+	case model.ProjectSSOConfig_BITBUCKET:
+		return sso.GetBitbucket().GetRedirectUri(), true
+	case model.ProjectSSOConfig_OIDC:
+		return sso.GetOidc().GetRedirectUri(), true
+	default:
+		return "", false
+	}
+}
+
+// checkSSORedirectURI checks the redirect URI is the absolute URL of the callback endpoint and is allowed.
+// HTTP is only allowed for the loopback address used while developing.
+func checkSSORedirectURI(uri string, allowed []string) error {
+	if uri == "" {
+		return errors.New("missing redirect uri")
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("invalid redirect uri: %w", err)
+	}
+	if !u.IsAbs() || u.Host == "" {
+		return errors.New("redirect uri must be an absolute URL")
+	}
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if !isLoopbackHost(u.Hostname()) {
+			return errors.New("redirect uri must use https")
+		}
+	default:
+		return fmt.Errorf("unsupported scheme %q of redirect uri", u.Scheme)
+	}
+	if u.Fragment != "" {
+		return errors.New("redirect uri must not contain a fragment")
+	}
+	if !strings.HasSuffix(u.Path, callbackPath) {
+		return fmt.Errorf("redirect uri must point to the %s endpoint", callbackPath)
+	}
+	return model.CheckRedirectURI(uri, allowed)
+}
+
+func isLoopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// ssoIssuer returns the issuer of the OpenID provider, or empty if the provider is not.
+// The issuer in the discovery document must strictly match it unless the endpoints are customized,
+// while the multi-tenant issuers such as the one of Azure AD contain the tenant placeholder.
+func ssoIssuer(sso *model.ProjectSSOConfig) (string, bool) {
+	switch sso.Provider {
+	case model.ProjectSSOConfig_GOOGLE:
+		return "https://accounts.google.com", true
+	case model.ProjectSSOConfig_AZUREAD:
+		if sso.AzureAd == nil {
+			return "", false
+		}
+		return "https://login.microsoftonline.com/" + sso.AzureAd.TenantOrDefault() + "/v2.0", false
+	case model.ProjectSSOConfig_OKTA:
+		if sso.Okta == nil {
+			return "", false
+		}
+		return sso.Okta.Issuer(), true
+	case model.ProjectSSOConfig_OIDC:
+		o := sso.Oidc
+		if o == nil || o.Issuer == "" {
+			return "", false
+		}
+		custom := o.AuthorizationEndpoint != "" || o.TokenEndpoint != "" || o.UserInfoEndpoint != ""
+		return o.Issuer, !custom
+	default:
+		return "", false
+	}
+}
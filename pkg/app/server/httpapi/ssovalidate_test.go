@@ -0,0 +1,175 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newFakeOpenIDProvider(t *testing.T, keys string) *httptest.Server {
+	t.Helper()
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer":%q,"jwks_uri":%q}`, srv.URL, srv.URL+"/keys")
+		case "/keys":
+			fmt.Fprint(w, keys)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func validateSSO(t *testing.T, body string) (int, *ssoValidationReport) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/sso/validate", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	NewSSOValidationHandler(zap.NewNop()).ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		return rec.Code, nil
+	}
+	var report ssoValidationReport
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec.Code, &report
+}
+
+func failedSSOChecks(report *ssoValidationReport) []string {
+	var failed []string
+	for _, c := range report.Checks {
+		if !c.Passed {
+			failed = append(failed, c.Name)
+		}
+	}
+	return failed
+}
+
+func TestSSOValidationHandler(t *testing.T) {
+	t.Parallel()
+	idp := newFakeOpenIDProvider(t, `{"keys":[{"kty":"oct","kid":"k","k":"c2VjcmV0"}]}`)
+	noKeys := newFakeOpenIDProvider(t, `{"keys":[]}`)
+
+	cases := []struct {
+		name       string
+		body       string
+		wantValid  bool
+		wantChecks []string
+		wantFailed []string
+	}{
+		{
+			name:       "valid oidc",
+			body:       fmt.Sprintf(`{"provider":"OIDC","sessionTtl":24,"oidc":{"clientId":"id","clientSecret":"secret","issuer":%q,"redirectUri":"https://pipecd.example.com/auth/callback"},"allowedRedirectUris":["https://pipecd.example.com/auth/callback"]}`, idp.URL),
+			wantValid:  true,
+			wantChecks: []string{"config", "client_credentials", "redirect_uri", "discovery", "jwks"},
+		},
+		{
+			name:       "oidc with client assertion key and no keys",
+			body:       fmt.Sprintf(`{"provider":"OIDC","sessionTtl":24,"oidc":{"clientId":"id","clientAssertionKey":"key","issuer":%q,"redirectUri":"https://pipecd.example.com/auth/callback"}}`, noKeys.URL),
+			wantChecks: []string{"config", "client_credentials", "redirect_uri", "discovery", "jwks"},
+			wantFailed: []string{"jwks"},
+		},
+		{
+			name:       "oidc public client with pkce",
+			body:       fmt.Sprintf(`{"provider":"OIDC","sessionTtl":24,"oidc":{"clientId":"id","pkceEnabled":true,"issuer":%q,"redirectUri":"https://pipecd.example.com/auth/callback"}}`, idp.URL),
+			wantValid:  true,
+			wantChecks: []string{"config", "client_credentials", "redirect_uri", "discovery", "jwks"},
+		},
+		{
+			name:       "oidc with unreachable issuer",
+			body:       `{"provider":"OIDC","sessionTtl":24,"oidc":{"clientId":"id","issuer":"http://127.0.0.1:1","redirectUri":"http://pipecd.example.com/callback"}}`,
+			wantChecks: []string{"config", "client_credentials", "redirect_uri", "discovery", "jwks"},
+			wantFailed: []string{"client_credentials", "redirect_uri", "discovery", "jwks"},
+		},
+		{
+			name:       "redirect uri not allowed",
+			body:       fmt.Sprintf(`{"provider":"OIDC","sessionTtl":24,"oidc":{"clientId":"id","clientSecret":"secret","issuer":%q,"redirectUri":"https://evil.example.com/auth/callback"},"allowedRedirectUris":["https://pipecd.example.com/auth/callback"]}`, idp.URL),
+			wantChecks: []string{"config", "client_credentials", "redirect_uri", "discovery", "jwks"},
+			wantFailed: []string{"redirect_uri"},
+		},
+		{
+			name:       "oidc scopes without openid",
+			body:       fmt.Sprintf(`{"provider":"OIDC","sessionTtl":24,"oidc":{"clientId":"id","clientSecret":"secret","issuer":%q,"redirectUri":"https://pipecd.example.com/auth/callback","scopes":["profile"]}}`, idp.URL),
+			wantChecks: []string{"config", "client_credentials", "redirect_uri", "discovery", "jwks"},
+			wantFailed: []string{"config"},
+		},
+		{
+			name:       "github without secret",
+			body:       `{"provider":"GITHUB","github":{"clientId":"id","apiUrl":"http://127.0.0.1:1"}}`,
+			wantChecks: []string{"config", "client_credentials", "reachability"},
+			wantFailed: []string{"config", "client_credentials", "reachability"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			code, report := validateSSO(t, tc.body)
+			require.Equal(t, http.StatusOK, code)
+			assert.Equal(t, tc.wantValid, report.Valid)
+
+			names := make([]string, 0, len(report.Checks))
+			for _, c := range report.Checks {
+				names = append(names, c.Name)
+			}
+			assert.Equal(t, tc.wantChecks, names)
+			assert.Equal(t, tc.wantFailed, failedSSOChecks(report))
+		})
+	}
+}
+
+func TestSSOValidationHandlerBadRequest(t *testing.T) {
+	t.Parallel()
+	code, _ := validateSSO(t, `{"provider":"UNKNOWN"}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	req := httptest.NewRequest(http.MethodGet, "/sso/validate", nil)
+	rec := httptest.NewRecorder()
+	NewSSOValidationHandler(zap.NewNop()).ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestCheckSSORedirectURI(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		uri     string
+		wantErr bool
+	}{
+		{uri: "https://pipecd.example.com/auth/callback"},
+		{uri: "http://localhost:8080/auth/callback"},
+		{uri: "", wantErr: true},
+		{uri: "/auth/callback", wantErr: true},
+		{uri: "http://pipecd.example.com/auth/callback", wantErr: true},
+		{uri: "https://pipecd.example.com/auth/callback#fragment", wantErr: true},
+		{uri: "https://pipecd.example.com/login", wantErr: true},
+	}
+	for _, tc := range cases {
+		err := checkSSORedirectURI(tc.uri, nil)
+		if tc.wantErr {
+			assert.Error(t, err, tc.uri)
+			continue
+		}
+		assert.NoError(t, err, tc.uri)
+	}
+}
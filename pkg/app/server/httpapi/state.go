@@ -0,0 +1,141 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// stateVersion2 is the version of the state bound to the secret in the state cookie.
+	// The legacy state has no version and is the same as the cookie value.
+	stateVersion2 = "2"
+	// stateVersion3 is the version 2 along with the name of the SSO configuration selected at login.
+	stateVersion3   = "3"
+	stateSecretSize = 32
+	stateVersionSep = "."
+)
+
+// newState returns the state sent to the provider and the value of the state cookie.
+// The state is in the format of "2.<hex encoded state token>.<HMAC of the token>"
+// where the HMAC key is the per-request secret kept in the cookie, so that
+// a state cookie cannot be used along with the state of another login flow.
+// When the name of the selected SSO configuration is given, the state is in the format of
+// "3.<hex encoded name>.<hex encoded state token>.<HMAC of the name and token>" instead.
+func newState(key, ssoName string, random io.Reader, now time.Time) (state, cookie string, err error) {
+	b, err := randomBytes(random, stateSecretSize)
+	if err != nil {
+		return "", "", err
+	}
+	secret := base64.RawURLEncoding.EncodeToString(b)
+	token := hex.EncodeToString([]byte(generateStateToken(key, now)))
+	if ssoName == "" {
+		state = strings.Join([]string{stateVersion2, token, hmacSignature(secret, token)}, stateVersionSep)
+		return state, secret, nil
+	}
+	name := hex.EncodeToString([]byte(ssoName))
+	signed := name + stateVersionSep + token
+	state = strings.Join([]string{stateVersion3, signed, hmacSignature(secret, signed)}, stateVersionSep)
+	return state, secret, nil
+}
+
+// generateStateToken returns the state token signed by the given key at the given time.
+// It is in the same format as the token of golang.org/x/net/xsrftoken,
+// "<signature>:<issued time in milliseconds>", so the issued states are still accepted.
+func generateStateToken(key string, now time.Time) string {
+	// Round up the time to milliseconds as the library does.
+	millis := (now.UnixNano() + 1e6 - 1) / 1e6
+	return stateTokenSignature(key, millis) + ":" + strconv.FormatInt(millis, 10)
+}
+
+func stateTokenSignature(key string, millis int64) string {
+	h := hmac.New(sha1.New, []byte(key))
+	// The library signs "<user id>:<action id>:<time>" where both IDs are empty for the state.
+	fmt.Fprintf(h, "::%d", millis)
+	return base64.RawURLEncoding.EncodeToString(h.Sum(nil))
+}
+
+// parsedState is the content of the state.
+type parsedState struct {
+	// token is the hex encoded state token, or the whole state for the legacy state.
+	token string
+	// ssoName is the name of the SSO configuration selected at login.
+	ssoName string
+	// signed is the part of the state signed by mac.
+	signed string
+	// mac is empty for the legacy state.
+	mac string
+}
+
+// parseState returns the state token, the selected SSO configuration and the HMAC in the given state.
+func parseState(state string) (parsedState, error) {
+	version, rest, ok := strings.Cut(state, stateVersionSep)
+	if !ok {
+		return parsedState{token: state}, nil
+	}
+	switch version {
+	case stateVersion2:
+		token, mac, ok := strings.Cut(rest, stateVersionSep)
+		if !ok || token == "" || mac == "" {
+			return parsedState{}, fmt.Errorf("malformed state")
+		}
+		return parsedState{token: token, signed: token, mac: mac}, nil
+	case stateVersion3:
+		parts := strings.Split(rest, stateVersionSep)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return parsedState{}, fmt.Errorf("malformed state")
+		}
+		name, err := hex.DecodeString(parts[0])
+		if err != nil {
+			return parsedState{}, fmt.Errorf("malformed state")
+		}
+		return parsedState{
+			token:   parts[1],
+			ssoName: string(name),
+			signed:  parts[0] + stateVersionSep + parts[1],
+			mac:     parts[2],
+		}, nil
+	default:
+		return parsedState{}, fmt.Errorf("unsupported state version %q", version)
+	}
+}
+
+// checkStateBinding checks whether the state was issued along with the given state cookie.
+func checkStateBinding(state, cookie string) error {
+	ps, err := parseState(state)
+	if err != nil {
+		return err
+	}
+	if ps.mac == "" {
+		// The legacy state is kept as is in the cookie.
+		if state == "" || subtle.ConstantTimeCompare([]byte(state), []byte(cookie)) != 1 {
+			return fmt.Errorf("wrong state")
+		}
+		return nil
+	}
+	if cookie == "" || !hmac.Equal([]byte(ps.mac), []byte(hmacSignature(cookie, ps.signed))) {
+		return fmt.Errorf("wrong state")
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/xsrftoken"
+)
+
+func TestCheckStateBinding(t *testing.T) {
+	t.Parallel()
+	state, cookie, err := newState("state-key", "", rand.Reader, time.Now())
+	require.NoError(t, err)
+	otherState, otherCookie, err := newState("state-key", "", rand.Reader, time.Now())
+	require.NoError(t, err)
+	selected, selectedCookie, err := newState("state-key", "github", rand.Reader, time.Now())
+	require.NoError(t, err)
+	legacy := hex.EncodeToString([]byte(xsrftoken.Generate("state-key", "", "")))
+
+	tests := []struct {
+		name      string
+		state     string
+		cookie    string
+		expectErr bool
+	}{
+		{
+			name:   "bound",
+			state:  state,
+			cookie: cookie,
+		},
+		{
+			name:      "cookie of another flow",
+			state:     state,
+			cookie:    otherCookie,
+			expectErr: true,
+		},
+		{
+			name:      "state of another flow",
+			state:     otherState,
+			cookie:    cookie,
+			expectErr: true,
+		},
+		{
+			name:      "missing cookie",
+			state:     state,
+			cookie:    "",
+			expectErr: true,
+		},
+		{
+			name:   "bound with selected sso",
+			state:  selected,
+			cookie: selectedCookie,
+		},
+		{
+			name:      "selected sso tampered",
+			state:     stateVersion3 + "." + hex.EncodeToString([]byte("oidc")) + selected[strings.Index(selected[2:], ".")+2:],
+			cookie:    selectedCookie,
+			expectErr: true,
+		},
+		{
+			name:      "version 2 as version 3",
+			state:     stateVersion3 + state[1:],
+			cookie:    cookie,
+			expectErr: true,
+		},
+		{
+			name:      "unsupported version",
+			state:     "4" + state[1:],
+			cookie:    cookie,
+			expectErr: true,
+		},
+		{
+			name:      "malformed",
+			state:     stateVersion2 + ".token",
+			cookie:    cookie,
+			expectErr: true,
+		},
+		{
+			name:   "legacy state",
+			state:  legacy,
+			cookie: legacy,
+		},
+		{
+			name:      "legacy state mismatched",
+			state:     legacy,
+			cookie:    "other",
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := checkStateBinding(tt.state, tt.cookie)
+			assert.Equal(t, tt.expectErr, err != nil)
+		})
+	}
+}
+
+func TestCheckState(t *testing.T) {
+	t.Parallel()
+	const key = "state-key"
+	now := time.Now()
+	state, cookie, err := newState(key, "", rand.Reader, now)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	req.AddCookie(&http.Cookie{Name: stateCookieKey, Value: cookie})
+	ssoName, err := checkState(req, stateCookieKey, key, state, time.Minute, now)
+	assert.NoError(t, err)
+	assert.Empty(t, ssoName)
+	_, err = checkState(req, stateCookieKey, "other-key", state, time.Minute, now)
+	assert.Error(t, err)
+
+	// The selected SSO configuration is returned.
+	selected, selectedCookie, err := newState(key, "github", rand.Reader, now)
+	require.NoError(t, err)
+	req = httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	req.AddCookie(&http.Cookie{Name: stateCookieKey, Value: selectedCookie})
+	ssoName, err = checkState(req, stateCookieKey, key, selected, time.Minute, now)
+	assert.NoError(t, err)
+	assert.Equal(t, "github", ssoName)
+
+	// The state issued earlier than the TTL is expired.
+	_, err = checkState(req, stateCookieKey, key, selected, time.Minute, now.Add(2*time.Minute))
+	assert.ErrorIs(t, err, errStateExpired)
+
+	// The state cookie of another flow cannot be used.
+	_, otherCookie, err := newState(key, "", rand.Reader, now)
+	require.NoError(t, err)
+	req = httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	req.AddCookie(&http.Cookie{Name: stateCookieKey, Value: otherCookie})
+	_, err = checkState(req, stateCookieKey, key, state, time.Minute, now)
+	assert.Error(t, err)
+
+	// The state cookie is read by the configured name only.
+	f := cookieFactory{namePrefix: "tenant-"}
+	req = httptest.NewRequest(http.MethodGet, callbackPath, nil)
+	req.AddCookie(&http.Cookie{Name: stateCookieKey, Value: cookie})
+	_, err = checkState(req, f.stateName(), key, state, time.Minute, now)
+	assert.Error(t, err)
+	req.AddCookie(&http.Cookie{Name: "tenant-" + stateCookieKey, Value: cookie})
+	_, err = checkState(req, f.stateName(), key, state, time.Minute, now)
+	assert.NoError(t, err)
+}
+
+func TestNewState_Deterministic(t *testing.T) {
+	t.Parallel()
+	const key = "state-key"
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	random := func() *bytes.Reader {
+		return bytes.NewReader(bytes.Repeat([]byte{1}, stateSecretSize))
+	}
+
+	state, cookie, err := newState(key, "github", random(), now)
+	require.NoError(t, err)
+	otherState, otherCookie, err := newState(key, "github", random(), now)
+	require.NoError(t, err)
+	assert.Equal(t, state, otherState)
+	assert.Equal(t, cookie, otherCookie)
+
+	// Not enough randomness.
+	_, _, err = newState(key, "", bytes.NewReader(nil), now)
+	assert.Error(t, err)
+}
+
+func TestGenerateStateToken(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	// The token is compatible with the one of xsrftoken.
+	token := generateStateToken("state-key", now)
+	assert.True(t, xsrftoken.Valid(token, "state-key", "", ""))
+	assert.False(t, xsrftoken.Valid(token, "other-key", "", ""))
+	assert.NoError(t, checkStateToken(token, "state-key", time.Minute, now))
+}
@@ -0,0 +1,135 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// StateOrigins are the origins of the identity providers allowed to send
+// the callback requests without the state cookie, e.g. https://accounts.example.com.
+type StateOrigins []string
+
+// ParseStateOrigins parses the origins of the identity providers in the form of "<scheme>://<host>[:<port>]".
+func ParseStateOrigins(origins []string) (StateOrigins, error) {
+	return parseOrigins("state origin", origins)
+}
+
+// parseOrigins parses the given origins in the form of "<scheme>://<host>[:<port>]" into the lower case.
+// The given kind is used in the errors.
+func parseOrigins(kind string, origins []string) ([]string, error) {
+	parsed := make([]string, 0, len(origins))
+	for _, o := range origins {
+		o = strings.TrimSpace(o)
+		u, err := url.Parse(o)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", kind, o, err)
+		}
+		if u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+			return nil, fmt.Errorf("invalid %s %q: must be an absolute HTTP(S) URL", kind, o)
+		}
+		if u.User != nil || (u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.Fragment != "" {
+			return nil, fmt.Errorf("invalid %s %q: must not have the user, path, query or fragment", kind, o)
+		}
+		parsed = append(parsed, strings.ToLower(u.Scheme+"://"+u.Host))
+	}
+	return parsed, nil
+}
+
+// WithStateOriginCheck trades the double-submit state cookie for the origin validation.
+// The callback request without the state cookie, e.g. dropped by the browser or the proxy,
+// is accepted when its Origin or Referer header is one of the given origins of the identity providers,
+// and its state is validated only by the signature and the TTL.
+// The request with the state cookie is still validated against it.
+func WithStateOriginCheck(origins StateOrigins) Option {
+	return func(h *authHandler) {
+		h.stateOrigins = origins
+	}
+}
+
+// stateCookieOptional reports whether the state of the callback request should be validated by its origin,
+// which is only the case when the origin check is enabled and the state cookie is missing.
+func (h *authHandler) stateCookieOptional(r *http.Request) bool {
+	if len(h.stateOrigins) == 0 {
+		return false
+	}
+	c, err := r.Cookie(h.cookies.stateName())
+	return err != nil || c.Value == ""
+}
+
+// checkStateByOrigin checks whether the callback request comes from one of the allowed origins
+// and the given state or sealed state was issued within the TTL, and returns the selected SSO configuration.
+func (h *authHandler) checkStateByOrigin(r *http.Request, state string, sealed *sealedState) (string, error) {
+	if err := checkRequestOrigin(r, h.stateOrigins); err != nil {
+		return "", err
+	}
+	if sealed != nil {
+		if err := checkStateToken(sealed.Token, h.stateKey, h.stateTTL, h.now()); err != nil {
+			return "", err
+		}
+		return sealed.SSOName, nil
+	}
+	ps, err := parseState(state)
+	if err != nil {
+		return "", err
+	}
+	// The legacy state is not bound to anything but the cookie.
+	if ps.mac == "" {
+		return "", fmt.Errorf("legacy state requires the state cookie")
+	}
+	token, err := hex.DecodeString(ps.token)
+	if err != nil {
+		return "", err
+	}
+	if err := checkStateToken(string(token), h.stateKey, h.stateTTL, h.now()); err != nil {
+		return "", err
+	}
+	return ps.ssoName, nil
+}
+
+// checkRequestOrigin checks whether the origin of the request is one of the given origins.
+// The Origin header is used if any, otherwise the origin of the Referer header.
+func checkRequestOrigin(r *http.Request, origins StateOrigins) error {
+	origin, err := requestOrigin(r)
+	if err != nil {
+		return fmt.Errorf("missing state cookie and %w", err)
+	}
+	if !slices.Contains(origins, strings.ToLower(origin)) {
+		return fmt.Errorf("missing state cookie and unexpected origin %q", origin)
+	}
+	return nil
+}
+
+// requestOrigin returns the origin of the request given by the Origin header,
+// or by the Referer header if the Origin header is missing or "null".
+func requestOrigin(r *http.Request) (string, error) {
+	if origin := r.Header.Get("Origin"); origin != "" && origin != "null" {
+		return origin, nil
+	}
+	referer := r.Header.Get("Referer")
+	if referer == "" {
+		return "", fmt.Errorf("missing origin")
+	}
+	u, err := url.Parse(referer)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid referer")
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStateOrigins(t *testing.T) {
+	t.Parallel()
+	origins, err := ParseStateOrigins([]string{"https://Accounts.Example.com", " https://idp.example.com:8443/ "})
+	require.NoError(t, err)
+	assert.Equal(t, StateOrigins{"https://accounts.example.com", "https://idp.example.com:8443"}, origins)
+
+	for _, o := range []string{
+		"accounts.example.com",
+		"ftp://accounts.example.com",
+		"https://accounts.example.com/login",
+		"https://user@accounts.example.com",
+		"https://accounts.example.com?q=1",
+	} {
+		_, err := ParseStateOrigins([]string{o})
+		assert.Error(t, err, o)
+	}
+}
+
+func TestCheckRequestOrigin(t *testing.T) {
+	t.Parallel()
+	origins := StateOrigins{"https://accounts.example.com"}
+	testcases := []struct {
+		name    string
+		headers map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "origin",
+			headers: map[string]string{"Origin": "https://Accounts.Example.com"},
+		},
+		{
+			name:    "referer",
+			headers: map[string]string{"Referer": "https://accounts.example.com/login?client_id=pipecd"},
+		},
+		{
+			name:    "null origin with referer",
+			headers: map[string]string{"Origin": "null", "Referer": "https://accounts.example.com/"},
+		},
+		{
+			name:    "origin takes precedence",
+			headers: map[string]string{"Origin": "https://evil.example.com", "Referer": "https://accounts.example.com/"},
+			wantErr: true,
+		},
+		{
+			name:    "other scheme",
+			headers: map[string]string{"Origin": "http://accounts.example.com"},
+			wantErr: true,
+		},
+		{
+			name:    "relative referer",
+			headers: map[string]string{"Referer": "/login"},
+			wantErr: true,
+		},
+		{
+			name:    "no header",
+			wantErr: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			r := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+			for k, v := range tc.headers {
+				r.Header.Set(k, v)
+			}
+			err := checkRequestOrigin(r, origins)
+			assert.Equal(t, tc.wantErr, err != nil, err)
+		})
+	}
+}
+
+func TestCheckStateByOrigin(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	h := &authHandler{
+		stateKey:     "state-key",
+		stateTTL:     10 * time.Minute,
+		stateOrigins: StateOrigins{"https://accounts.example.com"},
+		clock:        fakeClock{now: now},
+	}
+	newRequest := func(origin string, cookie string) *http.Request {
+		r := httptest.NewRequest(http.MethodGet, callbackPath, nil)
+		r.Header.Set("Origin", origin)
+		if cookie != "" {
+			r.AddCookie(&http.Cookie{Name: stateCookieKey, Value: cookie})
+		}
+		return r
+	}
+
+	state, cookie, err := newState(h.stateKey, "github", rand.Reader, now)
+	require.NoError(t, err)
+
+	// The state cookie is still checked if sent.
+	assert.False(t, h.stateCookieOptional(newRequest("https://accounts.example.com", cookie)))
+	assert.True(t, h.stateCookieOptional(newRequest("https://accounts.example.com", "")))
+	assert.False(t, (&authHandler{}).stateCookieOptional(newRequest("https://accounts.example.com", "")))
+
+	ssoName, err := h.checkStateByOrigin(newRequest("https://accounts.example.com", ""), state, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "github", ssoName)
+
+	_, err = h.checkStateByOrigin(newRequest("https://evil.example.com", ""), state, nil)
+	assert.Error(t, err)
+
+	_, err = (&authHandler{stateKey: "other-key", stateTTL: time.Minute, stateOrigins: h.stateOrigins}).
+		checkStateByOrigin(newRequest("https://accounts.example.com", ""), state, nil)
+	assert.Error(t, err)
+
+	_, err = h.checkStateByOrigin(newRequest("https://accounts.example.com", ""), generateStateToken(h.stateKey, now), nil)
+	assert.EqualError(t, err, "legacy state requires the state cookie")
+
+	expired := &authHandler{stateKey: h.stateKey, stateTTL: h.stateTTL, stateOrigins: h.stateOrigins, clock: fakeClock{now: now.Add(time.Hour)}}
+	_, err = expired.checkStateByOrigin(newRequest("https://accounts.example.com", ""), state, nil)
+	assert.ErrorIs(t, err, errStateExpired)
+
+	sealed := &sealedState{Token: generateStateToken(h.stateKey, now), ProjectID: "project", SSOName: "oidc"}
+	ssoName, err = h.checkStateByOrigin(newRequest("https://accounts.example.com", ""), "", sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "oidc", ssoName)
+}
@@ -0,0 +1,78 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	tracerName = "httpapi/auth"
+
+	projectIDAttributeKey     = attribute.Key("pipecd.project.id")
+	providerAttributeKey      = attribute.Key("pipecd.sso.provider")
+	failureReasonAttributeKey = attribute.Key("pipecd.login.failure_reason")
+)
+
+// WithTracerProvider traces the login flow with the given tracer provider.
+// The spans are not recorded unless this is set.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(h *authHandler) {
+		h.tracer = tp.Tracer(tracerName)
+	}
+}
+
+// startRequestSpan starts the span of the given request as the child of the trace context in its headers.
+// The returned request carries the span, so that the failure of the request can be recorded to it.
+func (h *authHandler) startRequestSpan(r *http.Request, name string) (*http.Request, trace.Span) {
+	ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+	ctx, span := h.getTracer().Start(ctx, name, trace.WithSpanKind(trace.SpanKindServer))
+	return r.WithContext(ctx), span
+}
+
+// startSpan starts the span of a step of the login flow.
+func (h *authHandler) startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return h.getTracer().Start(ctx, name)
+}
+
+func (h *authHandler) getTracer() trace.Tracer {
+	if h.tracer == nil {
+		return noop.NewTracerProvider().Tracer(tracerName)
+	}
+	return h.tracer
+}
+
+// endSpan ends the span of a step, marking it as failed if err is not nil.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// recordSpanFailure marks the span of the given request as failed for the given reason.
+func recordSpanFailure(r *http.Request, reason loginFailureReason) {
+	span := trace.SpanFromContext(r.Context())
+	span.SetAttributes(failureReasonAttributeKey.String(string(reason)))
+	span.SetStatus(codes.Error, string(reason))
+}
@@ -0,0 +1,72 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+func TestCallbackSpans(t *testing.T) {
+	t.Parallel()
+	recorder := tracetest.NewSpanRecorder()
+	h := &authHandler{
+		projectGetter: fakeProjectGetter{},
+		logger:        zap.NewNop(),
+	}
+	WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))(h)
+
+	req := httptest.NewRequest(http.MethodGet, callbackPath+"?state=invalid:project=project&code=code", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	h.handleCallback(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	state, callback := spans[0], spans[1]
+
+	assert.Equal(t, "auth.callback.validate_state", state.Name())
+	assert.Equal(t, codes.Error, state.Status().Code)
+	assert.Equal(t, callback.SpanContext().SpanID(), state.Parent().SpanID())
+
+	// The span of the callback continues the incoming trace.
+	assert.Equal(t, "auth.callback", callback.Name())
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", callback.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", callback.Parent().SpanID().String())
+	assert.Equal(t, codes.Error, callback.Status().Code)
+	assert.Contains(t, callback.Attributes(), attribute.String("pipecd.project.id", "project"))
+	assert.Contains(t, callback.Attributes(), attribute.String("pipecd.login.failure_reason", "state"))
+}
+
+func TestCallbackSpansNoop(t *testing.T) {
+	t.Parallel()
+	h := &authHandler{
+		projectGetter: fakeProjectGetter{},
+		logger:        zap.NewNop(),
+	}
+
+	req := httptest.NewRequest(http.MethodGet, callbackPath+"?state=invalid:project=project&code=code", nil)
+	rec := httptest.NewRecorder()
+	h.handleCallback(rec, req)
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+}
@@ -0,0 +1,500 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/cache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/oauth/webauthn"
+)
+
+const (
+	webAuthnCredentialsKeyPrefix = "webauthn-credentials:"
+	webAuthnChallengeKeyPrefix   = "webauthn-challenge:"
+	webAuthnChallengeSize        = 32
+	// webAuthnChallengeTTL is how long the ceremony can take, which is also passed to the browser as its timeout.
+	webAuthnChallengeTTL = 5 * time.Minute
+	// maxWebAuthnCredentials is the maximum number of the credentials registered by a user.
+	maxWebAuthnCredentials = 10
+	maxWebAuthnBodySize    = 64 << 10
+
+	webAuthnCeremonyRegister = "register"
+	webAuthnCeremonyAssert   = "assert"
+)
+
+// webAuthnStepUp elevates the sessions by the WebAuthn assertions of the credentials registered by the users.
+type webAuthnStepUp struct {
+	rp       *webauthn.RelyingParty
+	verifier jwt.Verifier
+	// credentials stores the credentials registered by each user of each project.
+	credentials cache.Cache
+	// challenges stores the challenges of the ceremonies in progress.
+	challenges cache.Cache
+	// ttl is how long the session is elevated after the assertion.
+	ttl time.Duration
+}
+
+// WithWebAuthnStepUp enables the step-up authentication by the WebAuthn credentials of the given relying party.
+// The session is read from the token cookie verified by the given verifier, and elevated for the given TTL
+// after the assertion. The registered credentials are stored in the credentials cache, which must not expire them,
+// and the challenges of the ceremonies in the challenges cache, which should expire them after a few minutes.
+func WithWebAuthnStepUp(rp *webauthn.RelyingParty, verifier jwt.Verifier, credentials, challenges cache.Cache, ttl time.Duration) Option {
+	return func(h *authHandler) {
+		h.webAuthn = &webAuthnStepUp{
+			rp:          rp,
+			verifier:    verifier,
+			credentials: credentials,
+			challenges:  challenges,
+			ttl:         ttl,
+		}
+	}
+}
+
+// base64URL is the binary data encoded in base64url in JSON, as done by the WebAuthn clients.
+type base64URL []byte
+
+func (b base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+func (b *base64URL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	v, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return err
+	}
+	*b = v
+	return nil
+}
+
+// webAuthnChallenge is the challenge of a ceremony stored until it is finished.
+type webAuthnChallenge struct {
+	Challenge []byte    `json:"challenge"`
+	IssuedAt  time.Time `json:"issuedAt"`
+}
+
+type webAuthnCredentialParameter struct {
+	Type string `json:"type"`
+	Alg  int    `json:"alg"`
+}
+
+type webAuthnCredentialDescriptor struct {
+	Type string    `json:"type"`
+	ID   base64URL `json:"id"`
+}
+
+// webAuthnCreationOptions is passed to navigator.credentials.create() as its publicKey.
+type webAuthnCreationOptions struct {
+	Challenge base64URL `json:"challenge"`
+	RP        struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"rp"`
+	User struct {
+		ID          base64URL `json:"id"`
+		Name        string    `json:"name"`
+		DisplayName string    `json:"displayName"`
+	} `json:"user"`
+	PubKeyCredParams       []webAuthnCredentialParameter  `json:"pubKeyCredParams"`
+	Timeout                int64                          `json:"timeout"`
+	ExcludeCredentials     []webAuthnCredentialDescriptor `json:"excludeCredentials"`
+	AuthenticatorSelection struct {
+		ResidentKey      string `json:"residentKey"`
+		UserVerification string `json:"userVerification"`
+	} `json:"authenticatorSelection"`
+	Attestation string `json:"attestation"`
+}
+
+// webAuthnRequestOptions is passed to navigator.credentials.get() as its publicKey.
+type webAuthnRequestOptions struct {
+	Challenge        base64URL                      `json:"challenge"`
+	RPID             string                         `json:"rpId"`
+	Timeout          int64                          `json:"timeout"`
+	AllowCredentials []webAuthnCredentialDescriptor `json:"allowCredentials"`
+	UserVerification string                         `json:"userVerification"`
+}
+
+// handleWebAuthnRegisterBegin starts the registration of a new credential of the logged-in user.
+func (h *authHandler) handleWebAuthnRegisterBegin(w http.ResponseWriter, r *http.Request) {
+	claims, ok := h.webAuthnSession(w, r)
+	if !ok {
+		return
+	}
+	creds, err := h.webAuthnCredentials(claims)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusInternalServerError, "Internal error", err)
+		return
+	}
+	if err := h.checkWebAuthnRegistration(claims, creds); err != nil {
+		h.handleWebAuthnError(w, http.StatusForbidden, "Step-up authentication required", err)
+		return
+	}
+	challenge, err := h.issueWebAuthnChallenge(webAuthnCeremonyRegister, claims)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusInternalServerError, "Internal error", err)
+		return
+	}
+
+	opts := &webAuthnCreationOptions{
+		Challenge: challenge,
+		PubKeyCredParams: []webAuthnCredentialParameter{
+			{Type: "public-key", Alg: webauthn.AlgES256},
+			{Type: "public-key", Alg: webauthn.AlgRS256},
+		},
+		Timeout:     webAuthnChallengeTTL.Milliseconds(),
+		Attestation: "none",
+	}
+	opts.RP.ID = h.webAuthn.rp.ID
+	opts.RP.Name = h.webAuthn.rp.Name
+	opts.User.ID = webAuthnUserHandle(claims)
+	opts.User.Name = claims.Subject
+	opts.User.DisplayName = claims.Subject
+	opts.ExcludeCredentials = webAuthnDescriptors(creds)
+	opts.AuthenticatorSelection.ResidentKey = "discouraged"
+	opts.AuthenticatorSelection.UserVerification = "required"
+	writeWebAuthnOptions(w, opts)
+}
+
+// handleWebAuthnRegisterFinish stores the credential created by the browser for the logged-in user.
+func (h *authHandler) handleWebAuthnRegisterFinish(w http.ResponseWriter, r *http.Request) {
+	claims, ok := h.webAuthnSession(w, r)
+	if !ok {
+		return
+	}
+	resp, ok := h.readWebAuthnResponse(w, r)
+	if !ok {
+		return
+	}
+	challenge, err := h.consumeWebAuthnChallenge(webAuthnCeremonyRegister, claims)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusBadRequest, "Invalid challenge", err)
+		return
+	}
+	cred, err := h.webAuthn.rp.VerifyRegistration(challenge, resp)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusBadRequest, "Invalid credential", err)
+		return
+	}
+
+	creds, err := h.webAuthnCredentials(claims)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusInternalServerError, "Internal error", err)
+		return
+	}
+	// The credentials may have been registered since the registration began.
+	if err := h.checkWebAuthnRegistration(claims, creds); err != nil {
+		h.handleWebAuthnError(w, http.StatusForbidden, "Step-up authentication required", err)
+		return
+	}
+	if slices.ContainsFunc(creds, func(c webauthn.Credential) bool { return bytes.Equal(c.ID, cred.ID) }) {
+		h.handleWebAuthnError(w, http.StatusConflict, "Credential already registered", nil)
+		return
+	}
+	if len(creds) >= maxWebAuthnCredentials {
+		h.handleWebAuthnError(w, http.StatusConflict, "Too many credentials", nil)
+		return
+	}
+	if err := h.putWebAuthnCredentials(claims, append(creds, *cred)); err != nil {
+		h.handleWebAuthnError(w, http.StatusInternalServerError, "Internal error", err)
+		return
+	}
+
+	h.logger.Info("registered WebAuthn credential",
+		zap.String("user", claims.Subject),
+		zap.String("project-id", claims.Role.ProjectId),
+	)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleWebAuthnAssertBegin starts the step-up authentication by the registered credentials of the logged-in user.
+func (h *authHandler) handleWebAuthnAssertBegin(w http.ResponseWriter, r *http.Request) {
+	claims, ok := h.webAuthnSession(w, r)
+	if !ok {
+		return
+	}
+	creds, err := h.webAuthnCredentials(claims)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusInternalServerError, "Internal error", err)
+		return
+	}
+	if len(creds) == 0 {
+		h.handleWebAuthnError(w, http.StatusNotFound, "No credential registered", nil)
+		return
+	}
+	challenge, err := h.issueWebAuthnChallenge(webAuthnCeremonyAssert, claims)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusInternalServerError, "Internal error", err)
+		return
+	}
+
+	writeWebAuthnOptions(w, &webAuthnRequestOptions{
+		Challenge:        challenge,
+		RPID:             h.webAuthn.rp.ID,
+		Timeout:          webAuthnChallengeTTL.Milliseconds(),
+		AllowCredentials: webAuthnDescriptors(creds),
+		UserVerification: "required",
+	})
+}
+
+// handleWebAuthnAssertFinish verifies the assertion of the registered credential,
+// and re-issues the token of the session elevated by the step-up authentication.
+func (h *authHandler) handleWebAuthnAssertFinish(w http.ResponseWriter, r *http.Request) {
+	claims, ok := h.webAuthnSession(w, r)
+	if !ok {
+		return
+	}
+	resp, ok := h.readWebAuthnResponse(w, r)
+	if !ok {
+		return
+	}
+	challenge, err := h.consumeWebAuthnChallenge(webAuthnCeremonyAssert, claims)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusBadRequest, "Invalid challenge", err)
+		return
+	}
+	assertion, err := webauthn.ParseAssertion(resp)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusBadRequest, "Invalid assertion", err)
+		return
+	}
+
+	creds, err := h.webAuthnCredentials(claims)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusInternalServerError, "Internal error", err)
+		return
+	}
+	i := slices.IndexFunc(creds, func(c webauthn.Credential) bool { return bytes.Equal(c.ID, assertion.CredentialID()) })
+	if i < 0 {
+		h.handleWebAuthnError(w, http.StatusBadRequest, "Unknown credential", nil)
+		return
+	}
+	signCount, err := h.webAuthn.rp.VerifyAssertion(challenge, &creds[i], assertion)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusUnauthorized, "Invalid assertion", err)
+		return
+	}
+	creds[i].SignCount = signCount
+	if err := h.putWebAuthnCredentials(claims, creds); err != nil {
+		h.handleWebAuthnError(w, http.StatusInternalServerError, "Internal error", err)
+		return
+	}
+
+	// The token keeps its ID so that the session can still be revoked, and the elevation never outlives it.
+	elevated := claims.Clone()
+	expiry := h.now().Add(h.webAuthn.ttl)
+	if claims.ExpiresAt != nil && claims.ExpiresAt.Before(expiry) {
+		expiry = claims.ExpiresAt.Time
+	}
+	elevated.ACR = jwt.ACRStepUp
+	elevated.AMR = []string{jwt.AMRHardwareKey, jwt.AMRMultiFactor}
+	elevated.StepUpExpiresAt = jwtgo.NewNumericDate(expiry)
+	signedToken, err := h.signer.Sign(elevated)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusInternalServerError, "Internal error", err)
+		return
+	}
+
+	h.logger.Info("session elevated by step-up authentication",
+		zap.String("user", claims.Subject),
+		zap.String("project-id", claims.Role.ProjectId),
+		zap.Time("step-up-expires-at", expiry),
+	)
+	http.SetCookie(w, h.cookies.token(signedToken))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// webAuthnSession returns the claims of the session of the request.
+// False is returned after responding the error if the step-up is disabled or the session is invalid.
+func (h *authHandler) webAuthnSession(w http.ResponseWriter, r *http.Request) (*jwt.Claims, bool) {
+	setNoCacheHeaders(w)
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return nil, false
+	}
+	if h.webAuthn == nil {
+		http.Error(w, "Step-up authentication is not enabled", http.StatusNotFound)
+		return nil, false
+	}
+	c, err := r.Cookie(h.cookies.tokenName())
+	if err != nil || c.Value == "" {
+		h.handleWebAuthnError(w, http.StatusUnauthorized, "Unauthenticated", err)
+		return nil, false
+	}
+	claims, err := h.webAuthn.verifier.Verify(c.Value)
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusUnauthorized, "Unauthenticated", err)
+		return nil, false
+	}
+	return claims, true
+}
+
+// readWebAuthnResponse reads the JSON encoded PublicKeyCredential returned by the browser from the body,
+// which is parsed and verified by the relying party.
+// The JSON content type is required so that the request cannot be sent by a cross-site form.
+func (h *authHandler) readWebAuthnResponse(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	if mt, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mt != "application/json" {
+		h.handleWebAuthnError(w, http.StatusUnsupportedMediaType, "Content-Type must be application/json", nil)
+		return nil, false
+	}
+	resp, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebAuthnBodySize))
+	if err != nil {
+		h.handleWebAuthnError(w, http.StatusBadRequest, "Invalid credential", err)
+		return nil, false
+	}
+	return resp, true
+}
+
+// checkWebAuthnRegistration checks whether the session can register a new credential. The first credential
+// can be registered within the step-up TTL from the login, and the others only by the elevated session,
+// so that the stolen session cannot register the credential of the attacker.
+func (h *authHandler) checkWebAuthnRegistration(claims *jwt.Claims, creds []webauthn.Credential) error {
+	now := h.now()
+	if len(creds) > 0 {
+		if !claims.SteppedUp(now) {
+			return fmt.Errorf("registering another credential requires the step-up authentication")
+		}
+		return nil
+	}
+	// The auth time is the time of the login, which is kept while the session is refreshed or extended.
+	if claims.AuthTime == nil || now.Sub(claims.AuthTime.Time) > h.webAuthn.ttl {
+		return fmt.Errorf("registering the first credential requires a recent login")
+	}
+	return nil
+}
+
+func (h *authHandler) handleWebAuthnError(w http.ResponseWriter, status int, responseMessage string, err error) {
+	if err != nil {
+		h.logger.Warn(fmt.Sprintf("auth-handler: %s", responseMessage), zap.Error(err))
+	} else {
+		h.logger.Warn(fmt.Sprintf("auth-handler: %s", responseMessage))
+	}
+	http.Error(w, responseMessage, status)
+}
+
+// issueWebAuthnChallenge stores a new challenge of the given ceremony of the session.
+// Only the latest challenge of each ceremony of the session can be used.
+func (h *authHandler) issueWebAuthnChallenge(ceremony string, claims *jwt.Claims) ([]byte, error) {
+	challenge, err := randomBytes(h.randReader(), webAuthnChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(&webAuthnChallenge{Challenge: challenge, IssuedAt: h.now()})
+	if err != nil {
+		return nil, err
+	}
+	if err := h.webAuthn.challenges.Put(webAuthnChallengeKey(ceremony, claims), data); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// consumeWebAuthnChallenge deletes the challenge of the given ceremony of the session and returns it.
+func (h *authHandler) consumeWebAuthnChallenge(ceremony string, claims *jwt.Claims) ([]byte, error) {
+	key := webAuthnChallengeKey(ceremony, claims)
+	var c webAuthnChallenge
+	if err := getCachedJSON(h.webAuthn.challenges, key, &c); err != nil {
+		return nil, err
+	}
+	if err := h.webAuthn.challenges.Delete(key); err != nil {
+		return nil, err
+	}
+	if h.now().Sub(c.IssuedAt) > webAuthnChallengeTTL {
+		return nil, fmt.Errorf("challenge expired")
+	}
+	return c.Challenge, nil
+}
+
+// webAuthnCredentials returns the credentials registered by the user of the session.
+func (h *authHandler) webAuthnCredentials(claims *jwt.Claims) ([]webauthn.Credential, error) {
+	var creds []webauthn.Credential
+	err := getCachedJSON(h.webAuthn.credentials, webAuthnCredentialsKey(claims), &creds)
+	if errors.Is(err, cache.ErrNotFound) {
+		return nil, nil
+	}
+	return creds, err
+}
+
+func (h *authHandler) putWebAuthnCredentials(claims *jwt.Claims, creds []webauthn.Credential) error {
+	data, err := json.Marshal(creds)
+	if err != nil {
+		return err
+	}
+	return h.webAuthn.credentials.Put(webAuthnCredentialsKey(claims), data)
+}
+
+// getCachedJSON decodes the JSON value stored with the given key into v.
+func getCachedJSON(c cache.Cache, key string, v interface{}) error {
+	value, err := c.Get(key)
+	if err != nil {
+		return err
+	}
+	var data []byte
+	switch value := value.(type) {
+	case []byte:
+		data = value
+	case string:
+		data = []byte(value)
+	default:
+		return fmt.Errorf("unexpected data type of %s: %T", key, value)
+	}
+	return json.Unmarshal(data, v)
+}
+
+func webAuthnChallengeKey(ceremony string, claims *jwt.Claims) string {
+	return webAuthnChallengeKeyPrefix + ceremony + ":" + claims.ID
+}
+
+func webAuthnCredentialsKey(claims *jwt.Claims) string {
+	return webAuthnCredentialsKeyPrefix + claims.Role.ProjectId + ":" + claims.Subject
+}
+
+// webAuthnUserHandle returns the user handle of the user of the session,
+// which is opaque not to reveal the username to the authenticator.
+func webAuthnUserHandle(claims *jwt.Claims) []byte {
+	h := sha256.Sum256([]byte(claims.Role.ProjectId + "\x00" + claims.Subject))
+	return h[:]
+}
+
+func webAuthnDescriptors(creds []webauthn.Credential) []webAuthnCredentialDescriptor {
+	descs := make([]webAuthnCredentialDescriptor, 0, len(creds))
+	for _, c := range creds {
+		descs = append(descs, webAuthnCredentialDescriptor{Type: "public-key", ID: c.ID})
+	}
+	return descs
+}
+
+func writeWebAuthnOptions(w http.ResponseWriter, opts interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{"publicKey": opts})
+}
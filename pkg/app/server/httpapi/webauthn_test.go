@@ -0,0 +1,251 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package httpapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/webauthn"
+	"github.com/pipe-cd/pipecd/pkg/oauth/webauthn/webauthntest"
+)
+
+// fakeTokens signs the claims into the opaque tokens which can be verified later.
+type fakeTokens map[string]*jwt.Claims
+
+func (f fakeTokens) Sign(claims *jwt.Claims) (string, error) {
+	token := fmt.Sprintf("token-%d", len(f))
+	f[token] = claims
+	return token, nil
+}
+
+func (f fakeTokens) Verify(token string) (*jwt.Claims, error) {
+	claims, ok := f[token]
+	if !ok {
+		return nil, fmt.Errorf("unknown token")
+	}
+	return claims, nil
+}
+
+type webAuthnTestClient struct {
+	t     *testing.T
+	h     *authHandler
+	token string
+}
+
+func (c *webAuthnTestClient) do(path string, body []byte) *httptest.ResponseRecorder {
+	var req *http.Request
+	if body == nil {
+		req = httptest.NewRequest(http.MethodPost, path, nil)
+	} else {
+		req = httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.AddCookie(&http.Cookie{Name: jwt.SignedTokenKey, Value: c.token})
+	handlers := map[string]http.HandlerFunc{
+		webAuthnRegisterBeginPath:  c.h.handleWebAuthnRegisterBegin,
+		webAuthnRegisterFinishPath: c.h.handleWebAuthnRegisterFinish,
+		webAuthnAssertBeginPath:    c.h.handleWebAuthnAssertBegin,
+		webAuthnAssertFinishPath:   c.h.handleWebAuthnAssertFinish,
+	}
+	rec := httptest.NewRecorder()
+	handlers[path](rec, req)
+	return rec
+}
+
+// begin starts the ceremony and returns its challenge.
+func (c *webAuthnTestClient) begin(path string) []byte {
+	rec := c.do(path, nil)
+	require.Equal(c.t, http.StatusOK, rec.Code, rec.Body.String())
+	var opts struct {
+		PublicKey struct {
+			Challenge base64URL `json:"challenge"`
+		} `json:"publicKey"`
+	}
+	require.NoError(c.t, json.NewDecoder(rec.Body).Decode(&opts))
+	return opts.PublicKey.Challenge
+}
+
+func (c *webAuthnTestClient) register(a *webauthntest.Authenticator) *httptest.ResponseRecorder {
+	return c.do(webAuthnRegisterFinishPath, a.Create(c.begin(webAuthnRegisterBeginPath)))
+}
+
+func (c *webAuthnTestClient) assert(a *webauthntest.Authenticator) *httptest.ResponseRecorder {
+	return c.do(webAuthnAssertFinishPath, a.Get(c.begin(webAuthnAssertBeginPath)))
+}
+
+func newWebAuthnTestHandler(t *testing.T, now time.Time) (*authHandler, fakeTokens, *webauthn.RelyingParty) {
+	tokens := fakeTokens{}
+	rp := &webauthn.RelyingParty{ID: "pipecd.example.com", Name: "PipeCD", Origin: "https://pipecd.example.com"}
+	h := &authHandler{
+		signer:  tokens,
+		cookies: cookieFactory{secure: true},
+		clock:   fakeClock{now: now},
+		logger:  zap.NewNop(),
+	}
+	WithWebAuthnStepUp(rp, tokens, memorycache.NewCache(), memorycache.NewCache(), 10*time.Minute)(h)
+	return h, tokens, rp
+}
+
+func newWebAuthnTestSession(t *testing.T, tokens fakeTokens, authTime time.Time) string {
+	claims := jwt.NewClaims("user", "", time.Hour, model.Role{ProjectId: "project"})
+	claims.ID = "session"
+	claims.AuthTime = jwtgo.NewNumericDate(authTime)
+	token, err := tokens.Sign(claims)
+	require.NoError(t, err)
+	return token
+}
+
+func TestWebAuthnStepUp(t *testing.T) {
+	t.Parallel()
+	now := time.Now().Truncate(time.Second)
+	h, tokens, rp := newWebAuthnTestHandler(t, now)
+	c := &webAuthnTestClient{t: t, h: h, token: newWebAuthnTestSession(t, tokens, now.Add(-time.Minute))}
+	a := webauthntest.NewAuthenticator(t, rp.ID, rp.Origin)
+
+	// No credential to assert yet.
+	rec := c.do(webAuthnAssertBeginPath, nil)
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = c.register(a)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+
+	// Registering another credential requires the elevated session.
+	rec = c.do(webAuthnRegisterBeginPath, nil)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	rec = c.assert(a)
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	var elevated *http.Cookie
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == jwt.SignedTokenKey {
+			elevated = cookie
+		}
+	}
+	require.NotNil(t, elevated)
+	claims, err := tokens.Verify(elevated.Value)
+	require.NoError(t, err)
+	assert.Equal(t, "session", claims.ID)
+	assert.Equal(t, jwt.ACRStepUp, claims.ACR)
+	assert.Equal(t, []string{jwt.AMRHardwareKey, jwt.AMRMultiFactor}, claims.AMR)
+	assert.Equal(t, now.Add(10*time.Minute), claims.StepUpExpiresAt.Time)
+	assert.True(t, claims.SteppedUp(now))
+
+	// The elevated session can register another credential.
+	c.token = elevated.Value
+	rec = c.register(webauthntest.NewAuthenticator(t, rp.ID, rp.Origin))
+	assert.Equal(t, http.StatusConflict, rec.Code, "the credential ID is already registered")
+	other := webauthntest.NewAuthenticator(t, rp.ID, rp.Origin)
+	other.ID = []byte("other-credential-id")
+	rec = c.register(other)
+	assert.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+}
+
+func TestWebAuthnStepUpRejected(t *testing.T) {
+	t.Parallel()
+	now := time.Now().Truncate(time.Second)
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		h := &authHandler{logger: zap.NewNop()}
+		rec := (&webAuthnTestClient{t: t, h: h}).do(webAuthnRegisterBeginPath, nil)
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		t.Parallel()
+		h, _, _ := newWebAuthnTestHandler(t, now)
+		rec := (&webAuthnTestClient{t: t, h: h, token: "unknown"}).do(webAuthnRegisterBeginPath, nil)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("stale login", func(t *testing.T) {
+		t.Parallel()
+		h, tokens, _ := newWebAuthnTestHandler(t, now)
+		c := &webAuthnTestClient{t: t, h: h, token: newWebAuthnTestSession(t, tokens, now.Add(-time.Hour))}
+		rec := c.do(webAuthnRegisterBeginPath, nil)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("reused challenge", func(t *testing.T) {
+		t.Parallel()
+		h, tokens, rp := newWebAuthnTestHandler(t, now)
+		c := &webAuthnTestClient{t: t, h: h, token: newWebAuthnTestSession(t, tokens, now)}
+		a := webauthntest.NewAuthenticator(t, rp.ID, rp.Origin)
+		resp := a.Create(c.begin(webAuthnRegisterBeginPath))
+		rec := c.do(webAuthnRegisterFinishPath, resp)
+		require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+		rec = c.do(webAuthnRegisterFinishPath, resp)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("refreshed after stale login", func(t *testing.T) {
+		t.Parallel()
+		h, _, _ := newWebAuthnTestHandler(t, now)
+		h.projectGetter = fakeProjectGetter{"project": {Id: "project"}}
+		h.refreshTokens = memorycache.NewCache()
+		h.refreshTokenTTL = 24 * time.Hour
+		value, err := h.issueRefreshToken(&refreshToken{
+			Subject:   "user",
+			ProjectID: "project",
+			Roles:     []string{model.BuiltinRBACRoleAdmin.String()},
+			TokenTTL:  time.Hour,
+			LoginAt:   now.Add(-time.Hour),
+		})
+		require.NoError(t, err)
+		req := httptest.NewRequest(http.MethodPost, refreshPath, nil)
+		req.AddCookie(&http.Cookie{Name: refreshTokenCookieKey, Value: value})
+		rec := httptest.NewRecorder()
+		h.handleRefresh(rec, req)
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		// The refreshed session is as old as the login, so it cannot register the first credential.
+		c := &webAuthnTestClient{t: t, h: h}
+		for _, cookie := range rec.Result().Cookies() {
+			if cookie.Name == jwt.SignedTokenKey {
+				c.token = cookie.Value
+			}
+		}
+		require.NotEmpty(t, c.token)
+		rec = c.do(webAuthnRegisterBeginPath, nil)
+		assert.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("form content type", func(t *testing.T) {
+		t.Parallel()
+		h, tokens, _ := newWebAuthnTestHandler(t, now)
+		c := &webAuthnTestClient{t: t, h: h, token: newWebAuthnTestSession(t, tokens, now)}
+		c.begin(webAuthnRegisterBeginPath)
+		req := httptest.NewRequest(http.MethodPost, webAuthnRegisterFinishPath, bytes.NewReader([]byte("{}")))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.AddCookie(&http.Cookie{Name: jwt.SignedTokenKey, Value: c.token})
+		rec := httptest.NewRecorder()
+		h.handleWebAuthnRegisterFinish(rec, req)
+		assert.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+	})
+}
@@ -47,6 +47,31 @@ type ControlPlaneSpec struct {
 }
 
 func (s *ControlPlaneSpec) Validate() error {
+	for i := range s.SharedSSOConfigs {
+		c := &s.SharedSSOConfigs[i]
+		if c.Github != nil {
+			if err := c.Github.ValidateURLs(); err != nil {
+				return fmt.Errorf("invalid GitHub configuration of shared SSO %s: %w", c.Name, err)
+			}
+			if err := c.Github.ValidateApp(); err != nil {
+				return fmt.Errorf("invalid GitHub configuration of shared SSO %s: %w", c.Name, err)
+			}
+			if err := c.Github.ValidateExtraHeaders(); err != nil {
+				return fmt.Errorf("invalid GitHub configuration of shared SSO %s: %w", c.Name, err)
+			}
+		}
+		if c.Oidc != nil {
+			if err := c.Oidc.ValidateScopes(); err != nil {
+				return fmt.Errorf("invalid OIDC configuration of shared SSO %s: %w", c.Name, err)
+			}
+			if err := c.Oidc.ValidateClientAuthentication(); err != nil {
+				return fmt.Errorf("invalid OIDC configuration of shared SSO %s: %w", c.Name, err)
+			}
+			if err := c.Oidc.ValidateExtraHeaders(); err != nil {
+				return fmt.Errorf("invalid OIDC configuration of shared SSO %s: %w", c.Name, err)
+			}
+		}
+	}
 	return nil
 }
 
@@ -55,8 +55,8 @@ func TestControlPlaneConfig(t *testing.T) {
 							Github: &model.ProjectSSOConfig_GitHub{
 								ClientId:     "client-id",
 								ClientSecret: "client-secret",
-								BaseUrl:      "base-url",
-								UploadUrl:    "upload-url",
+								BaseUrl:      "https://github.example.com/api/v3/",
+								UploadUrl:    "https://github.example.com/api/uploads/",
 							},
 						},
 					},
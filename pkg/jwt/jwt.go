@@ -15,20 +15,38 @@
 package jwt
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"errors"
 	"fmt"
 	"os"
+	"slices"
 	"time"
 
 	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
 
 	"github.com/pipe-cd/pipecd/pkg/model"
 )
 
+// ErrInvalidKey is returned when the key file does not contain the key of the type required by the signing method,
+// e.g. the RSA key for ES256 or the P-384 key for ES256.
+var ErrInvalidKey = errors.New("invalid key")
+
 const (
 	// Issuer is the name of the issuer for PipeCD token.
 	Issuer = "PipeCD"
 	// SignedTokenKey is the name of singed token key in cookie.
 	SignedTokenKey = "token"
+	// ACRStepUp is the authentication context class of the session elevated by the step-up authentication.
+	ACRStepUp = "urn:pipecd:acr:step-up"
+	// AMRHardwareKey and AMRMultiFactor are the authentication methods (RFC 8176) of the WebAuthn assertion
+	// with the user verification, which proves the possession of the key and the PIN or the biometrics.
+	AMRHardwareKey = "hwk"
+	AMRMultiFactor = "mfa"
+	// MaxGroups is the maximum number of the groups in the claims, which keeps the token small enough for the cookie.
+	MaxGroups = 20
 )
 
 // Claims extends the RegisteredClaims with the role to access PipeCD resources.
@@ -36,13 +54,74 @@ type Claims struct {
 	jwtgo.RegisteredClaims
 	AvatarURL string     `json:"avatarUrl,omitempty"`
 	Role      model.Role `json:"role,omitempty"`
+	// Identities are the SSO identities linked to the subject, e.g. "GITHUB:octocat".
+	// Empty if the identities are not linked.
+	Identities []string `json:"identities,omitempty"`
+	// Groups are the user groups of the project the user belongs to, e.g. "org/team",
+	// which the web console can use to show the features. Empty if the user belongs to none of them.
+	Groups []string `json:"groups,omitempty"`
+	// Provider is the SSO provider the user logged in with, e.g. "LDAP".
+	// Empty for the static admin.
+	Provider string `json:"provider,omitempty"`
+	// IdPSubject and IdPSessionID are the sub and sid claims of the ID token of the OIDC provider the user logged in with,
+	// which the back-channel logout of the provider identifies the sessions to revoke by. Empty if not given by the provider.
+	IdPSubject   string `json:"idp_sub,omitempty"`
+	IdPSessionID string `json:"idp_sid,omitempty"`
+	// Binding is the fingerprint of the client the session is bound to at login, e.g. its IP address,
+	// which the session must be used from. Empty if the session is not bound.
+	Binding string `json:"bnd,omitempty"`
+	// AuthTime is when the user logged in, which is kept while the session is extended.
+	AuthTime *jwtgo.NumericDate `json:"auth_time,omitempty"`
+	// ACR and AMR are the authentication context class and methods of the step-up authentication.
+	// Empty if the session has not been elevated.
+	ACR string   `json:"acr,omitempty"`
+	AMR []string `json:"amr,omitempty"`
+	// StepUpExpiresAt is when the elevation by the step-up authentication expires,
+	// which is shorter than the session itself.
+	StepUpExpiresAt *jwtgo.NumericDate `json:"step_up_exp,omitempty"`
+}
+
+// SteppedUp reports whether the session is elevated by the step-up authentication at the given time.
+func (c *Claims) SteppedUp(now time.Time) bool {
+	return c.ACR == ACRStepUp && c.StepUpExpiresAt != nil && now.Before(c.StepUpExpiresAt.Time)
+}
+
+// Clone returns a deep copy of the claims, which can be modified and signed as a new token
+// without copying the role message by value.
+func (c *Claims) Clone() *Claims {
+	cloned := &Claims{
+		RegisteredClaims: c.RegisteredClaims,
+		AvatarURL:        c.AvatarURL,
+		Identities:       slices.Clone(c.Identities),
+		Groups:           slices.Clone(c.Groups),
+		Provider:         c.Provider,
+		IdPSubject:       c.IdPSubject,
+		IdPSessionID:     c.IdPSessionID,
+		Binding:          c.Binding,
+		AuthTime:         c.AuthTime,
+		ACR:              c.ACR,
+		AMR:              slices.Clone(c.AMR),
+		StepUpExpiresAt:  c.StepUpExpiresAt,
+	}
+	cloned.RegisteredClaims.Audience = slices.Clone(c.Audience)
+	proto.Merge(&cloned.Role, &c.Role)
+	return cloned
 }
 
 // NewClaims creates a new claims for a given github user.
-func NewClaims(githubUserID, avatarURL string, ttl time.Duration, role model.Role) *Claims {
-	now := time.Now().UTC()
+// The given groups are the user groups of the project matched at login, not the raw groups of the provider,
+// and only the first MaxGroups of them are kept.
+func NewClaims(githubUserID, avatarURL string, ttl time.Duration, role model.Role, groups ...string) *Claims {
+	return NewClaimsAt(time.Now(), githubUserID, avatarURL, ttl, role, groups...)
+}
+
+// NewClaimsAt is the same as NewClaims but issues the claims at the given time instead of the current time,
+// so that the clock of the caller controls the iat, nbf and exp claims.
+func NewClaimsAt(now time.Time, githubUserID, avatarURL string, ttl time.Duration, role model.Role, groups ...string) *Claims {
+	now = now.UTC()
 	return &Claims{
 		RegisteredClaims: jwtgo.RegisteredClaims{
+			ID:        uuid.NewString(),
 			Subject:   githubUserID,
 			Issuer:    Issuer,
 			IssuedAt:  jwtgo.NewNumericDate(now),
@@ -51,22 +130,80 @@ func NewClaims(githubUserID, avatarURL string, ttl time.Duration, role model.Rol
 		},
 		AvatarURL: avatarURL,
 		Role:      role,
+		Groups:    slices.Clone(groups[:min(len(groups), MaxGroups)]),
+		AuthTime:  jwtgo.NewNumericDate(now),
 	}
 }
 
+// ParseSigningMethod returns the signing method of the given alg header,
+// one of the HMAC (HS*), RSA (RS*), ECDSA (ES*) and EdDSA algorithms.
+func ParseSigningMethod(alg string) (jwtgo.SigningMethod, error) {
+	switch alg {
+	case "HS256", "HS384", "HS512", "RS256", "RS384", "RS512", "ES256", "ES384", "ES512", "EdDSA":
+		return jwtgo.GetSigningMethod(alg), nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrUnexpectedSigningMethod, alg)
+}
+
+// readKeyFile reads the key of the given method from the file. The HMAC key is the content of the file as is,
+// and the others are PEM encoded. The private key is also accepted to verify by its public key.
 func readKeyFile(method jwtgo.SigningMethod, keyFile string, isSigningKey bool) (interface{}, error) {
 	data, err := os.ReadFile(keyFile)
 	if err != nil {
 		return nil, fmt.Errorf("unable to read key file: %v", err)
 	}
-	switch method.Alg() {
-	case "HS256", "HS384", "HS512":
+	switch m := method.(type) {
+	case *jwtgo.SigningMethodHMAC:
 		return data, nil
-	case "RS256", "RS384", "RS512":
+	case *jwtgo.SigningMethodRSA:
+		if key, err := jwtgo.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			if isSigningKey {
+				return key, nil
+			}
+			return &key.PublicKey, nil
+		} else if isSigningKey {
+			return nil, fmt.Errorf("%w for %s: %w", ErrInvalidKey, m.Alg(), err)
+		}
+		key, err := jwtgo.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s: %w", ErrInvalidKey, m.Alg(), err)
+		}
+		return key, nil
+	case *jwtgo.SigningMethodECDSA:
+		var publicKey *ecdsa.PublicKey
+		privateKey, err := jwtgo.ParseECPrivateKeyFromPEM(data)
+		switch {
+		case err == nil:
+			publicKey = &privateKey.PublicKey
+		case isSigningKey:
+			return nil, fmt.Errorf("%w for %s: %w", ErrInvalidKey, m.Alg(), err)
+		default:
+			if publicKey, err = jwtgo.ParseECPublicKeyFromPEM(data); err != nil {
+				return nil, fmt.Errorf("%w for %s: %w", ErrInvalidKey, m.Alg(), err)
+			}
+		}
+		// The curve is fixed by the algorithm, e.g. P-256 for ES256.
+		if curve := publicKey.Curve.Params(); curve.BitSize != m.CurveBits {
+			return nil, fmt.Errorf("%w for %s: unexpected curve %s", ErrInvalidKey, m.Alg(), curve.Name)
+		}
 		if isSigningKey {
-			return jwtgo.ParseRSAPrivateKeyFromPEM(data)
+			return privateKey, nil
+		}
+		return publicKey, nil
+	case *jwtgo.SigningMethodEd25519:
+		if key, err := jwtgo.ParseEdPrivateKeyFromPEM(data); err == nil {
+			if isSigningKey {
+				return key, nil
+			}
+			return key.(ed25519.PrivateKey).Public(), nil
+		} else if isSigningKey {
+			return nil, fmt.Errorf("%w for %s: %w", ErrInvalidKey, m.Alg(), err)
+		}
+		key, err := jwtgo.ParseEdPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w for %s: %w", ErrInvalidKey, m.Alg(), err)
 		}
-		return jwtgo.ParseRSAPublicKeyFromPEM(data)
+		return key, nil
 	}
-	return nil, fmt.Errorf("unexpected signing method: %v", method.Alg())
+	return nil, fmt.Errorf("%w: %v", ErrUnexpectedSigningMethod, method.Alg())
 }
@@ -0,0 +1,108 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestClaimsSteppedUp(t *testing.T) {
+	now := time.Now()
+	claims := &Claims{}
+	assert.False(t, claims.SteppedUp(now))
+
+	claims.ACR = ACRStepUp
+	claims.StepUpExpiresAt = jwtgo.NewNumericDate(now.Add(time.Minute))
+	assert.True(t, claims.SteppedUp(now))
+	assert.False(t, claims.SteppedUp(now.Add(2*time.Minute)))
+
+	claims.ACR = "other"
+	assert.False(t, claims.SteppedUp(now))
+}
+
+func TestClaimsClone(t *testing.T) {
+	claims := NewClaims("user", "avatar-url", time.Hour, model.Role{
+		ProjectId:        "project",
+		ProjectRbacRoles: []string{"Admin"},
+	}, "dev")
+	claims.Audience = []string{"https://pipecd.example.com"}
+	claims.Provider = "LDAP"
+	claims.AMR = []string{AMRHardwareKey}
+
+	cloned := claims.Clone()
+	assert.Equal(t, claims.RegisteredClaims, cloned.RegisteredClaims)
+	assert.Equal(t, claims.Groups, cloned.Groups)
+	assert.Equal(t, claims.Provider, cloned.Provider)
+	assert.Equal(t, claims.AMR, cloned.AMR)
+	assert.True(t, proto.Equal(&claims.Role, &cloned.Role))
+
+	// The clone does not share the slices and the role with the original.
+	cloned.Groups[0] = "changed"
+	cloned.AMR[0] = "changed"
+	cloned.Audience[0] = "changed"
+	cloned.Role.ProjectRbacRoles[0] = "changed"
+	assert.Equal(t, "dev", claims.Groups[0])
+	assert.Equal(t, AMRHardwareKey, claims.AMR[0])
+	assert.Equal(t, "https://pipecd.example.com", claims.Audience[0])
+	assert.Equal(t, "Admin", claims.Role.ProjectRbacRoles[0])
+}
+
+func TestNewClaimsAt(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	claims := NewClaimsAt(now, "user", "avatar-url", time.Hour, model.Role{ProjectId: "project"})
+	assert.Equal(t, now, claims.IssuedAt.Time)
+	assert.Equal(t, now, claims.NotBefore.Time)
+	assert.Equal(t, now, claims.AuthTime.Time)
+	assert.Equal(t, now.Add(time.Hour), claims.ExpiresAt.Time)
+}
+
+func TestNewClaimsGroups(t *testing.T) {
+	claims := NewClaims("user", "avatar-url", time.Hour, model.Role{})
+	assert.Nil(t, claims.Groups)
+
+	claims = NewClaims("user", "avatar-url", time.Hour, model.Role{}, "dev", "ops")
+	assert.Equal(t, []string{"dev", "ops"}, claims.Groups)
+
+	groups := make([]string, MaxGroups+5)
+	for i := range groups {
+		groups[i] = fmt.Sprintf("group-%d", i)
+	}
+	claims = NewClaims("user", "avatar-url", time.Hour, model.Role{}, groups...)
+	assert.Equal(t, groups[:MaxGroups], claims.Groups)
+
+	// The claims do not share the given slice.
+	groups[0] = "changed"
+	assert.Equal(t, "group-0", claims.Groups[0])
+}
+
+func TestParseSigningMethod(t *testing.T) {
+	for _, alg := range []string{"HS256", "RS256", "ES256", "ES384", "EdDSA"} {
+		m, err := ParseSigningMethod(alg)
+		assert.NoError(t, err)
+		assert.Equal(t, alg, m.Alg())
+	}
+	for _, alg := range []string{"", "none", "PS256", "es256"} {
+		_, err := ParseSigningMethod(alg)
+		assert.ErrorIs(t, err, ErrUnexpectedSigningMethod)
+	}
+}
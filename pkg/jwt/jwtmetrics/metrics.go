@@ -0,0 +1,61 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtmetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const kidLabel = "kid"
+
+var (
+	signingKeyGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "jwt_signing_key_active",
+			Help: "Whether the key of the kid is used to sign new tokens. Only one kid is 1 at a time.",
+		},
+		[]string{kidLabel},
+	)
+
+	verificationCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "jwt_verifications_total",
+			Help: "Total number of tokens successfully verified by the key of the kid.",
+		},
+		[]string{kidLabel},
+	)
+)
+
+func Register(r prometheus.Registerer) {
+	r.MustRegister(
+		signingKeyGauge,
+		verificationCounter,
+	)
+}
+
+// SetActiveSigningKey marks the given kid as the one signing new tokens.
+func SetActiveSigningKey(kid string) {
+	signingKeyGauge.Reset()
+	signingKeyGauge.With(prometheus.Labels{
+		kidLabel: kid,
+	}).Set(1)
+}
+
+// IncVerifications increments the number of tokens verified by the key of the given kid.
+func IncVerifications(kid string) {
+	verificationCounter.With(prometheus.Labels{
+		kidLabel: kid,
+	}).Inc()
+}
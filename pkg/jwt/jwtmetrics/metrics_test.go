@@ -0,0 +1,38 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwtmetrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetActiveSigningKey(t *testing.T) {
+	SetActiveSigningKey("old")
+	SetActiveSigningKey("new")
+
+	assert.Equal(t, 1, testutil.CollectAndCount(signingKeyGauge))
+	assert.Equal(t, 1.0, testutil.ToFloat64(signingKeyGauge.WithLabelValues("new")))
+}
+
+func TestIncVerifications(t *testing.T) {
+	before := testutil.ToFloat64(verificationCounter.WithLabelValues("test-inc-verifications"))
+	IncVerifications("test-inc-verifications")
+	IncVerifications("test-inc-verifications")
+
+	assert.Equal(t, before+2, testutil.ToFloat64(verificationCounter.WithLabelValues("test-inc-verifications")))
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"context"
+	"crypto/rsa"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// NewKeySetFromDir returns a new key set using the given RSA signing method
+// and the keys loaded from the PEM files in the given directory, see LoadKeyDir.
+func NewKeySetFromDir(method *jwtgo.SigningMethodRSA, dir string, opts ...Option) (*KeySet, error) {
+	ks := &KeySet{method: method, scope: newScope(opts)}
+	if err := ks.ReloadFromDir(dir); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// LoadKeyDir loads the RSA keys from the PEM files in the given directory in the lexical order of their names,
+// e.g. "01-current.pem" followed by "02-previous.pem". Each file contains either a private key or a public key.
+// The first private key is the one to sign tokens, and the other keys are only used to verify tokens.
+// The hidden files and the directories are ignored, such as the ones of the mounted Kubernetes secrets.
+func LoadKeyDir(dir string) ([]*rsa.PrivateKey, []*rsa.PublicKey, error) {
+	files, err := keyFiles(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		privateKeys []*rsa.PrivateKey
+		publicKeys  []*rsa.PublicKey
+	)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to read key file: %v", err)
+		}
+		if k, err := jwtgo.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			privateKeys = append(privateKeys, k)
+			continue
+		}
+		k, err := jwtgo.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to parse key file %s: %v", filepath.Base(f), err)
+		}
+		publicKeys = append(publicKeys, k)
+	}
+	if len(privateKeys) == 0 {
+		return nil, nil, fmt.Errorf("no private key found in %s", dir)
+	}
+	return privateKeys, publicKeys, nil
+}
+
+// ReloadFromDir replaces all keys of the set with the ones loaded from the given directory.
+// The current keys are kept if loading fails.
+func (k *KeySet) ReloadFromDir(dir string) error {
+	// The version is taken before loading, so that the files changed while loading are loaded again.
+	version, err := keyDirVersion(dir)
+	if err != nil {
+		return err
+	}
+	privateKeys, publicKeys, err := LoadKeyDir(dir)
+	if err != nil {
+		return err
+	}
+	if err := k.SetKeys(privateKeys, publicKeys...); err != nil {
+		return err
+	}
+	k.mu.Lock()
+	k.dirVersion = version
+	k.mu.Unlock()
+	return nil
+}
+
+func (k *KeySet) loadedDirVersion() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.dirVersion
+}
+
+// WatchDir reloads the keys from the given directory whenever its files change
+// until the context is done. The directory is checked at the given interval.
+func (k *KeySet) WatchDir(ctx context.Context, dir string, interval time.Duration, logger *zap.Logger) {
+	logger = logger.Named("jwt-key-watcher")
+	// The failed version is not retried until the files change again.
+	var failed string
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		version, err := keyDirVersion(dir)
+		if err != nil {
+			logger.Warn("failed to check the key directory", zap.String("dir", dir), zap.Error(err))
+			continue
+		}
+		if version == k.loadedDirVersion() || version == failed {
+			continue
+		}
+		if err := k.ReloadFromDir(dir); err != nil {
+			logger.Error("failed to reload the keys, the current keys are kept", zap.String("dir", dir), zap.Error(err))
+			failed = version
+			continue
+		}
+		logger.Info("reloaded the keys",
+			zap.String("dir", dir),
+			zap.String("signing-kid", k.PrimaryKeyID()),
+			zap.Strings("kids", k.KeyIDs()),
+		)
+	}
+}
+
+// keyFiles returns the paths of the key files in the given directory in the lexical order.
+func keyFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read key directory: %v", err)
+	}
+	files := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		path := filepath.Join(dir, e.Name())
+		// Stat follows the symbolic links of the mounted secrets.
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, path)
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// keyDirVersion returns a string which changes whenever a key file in the given directory
+// is added, removed or modified.
+func keyDirVersion(dir string) (string, error) {
+	files, err := keyFiles(dir)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(filepath.Base(f))
+		b.WriteByte(':')
+		b.WriteString(strconv.FormatInt(info.Size(), 10))
+		b.WriteByte(':')
+		b.WriteString(strconv.FormatInt(info.ModTime().UnixNano(), 10))
+		b.WriteByte('\n')
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func copyKeyFile(t *testing.T, src, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(src)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(dst, data, 0600))
+}
+
+func publicKeyID(t *testing.T, file string) string {
+	t.Helper()
+	k, err := readRSAPublicKeyFile(file)
+	require.NoError(t, err)
+	kid, err := KeyID(k)
+	require.NoError(t, err)
+	return kid
+}
+
+func TestNewKeySetFromDir(t *testing.T) {
+	t.Parallel()
+	var (
+		currentKID = publicKeyID(t, "testdata/rotated_public.key")
+		oldKID     = publicKeyID(t, "testdata/public.key")
+	)
+
+	dir := t.TempDir()
+	copyKeyFile(t, "testdata/rotated_private.key", filepath.Join(dir, "01-current.pem"))
+	copyKeyFile(t, "testdata/private.key", filepath.Join(dir, "02-old.pem"))
+	// The hidden files and the directories are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".hidden"), []byte("not a key"), 0600))
+	require.NoError(t, os.Mkdir(filepath.Join(dir, "..data"), 0700))
+
+	ks, err := NewKeySetFromDir(jwtgo.SigningMethodRS256, dir)
+	require.NoError(t, err)
+	assert.Equal(t, currentKID, ks.PrimaryKeyID())
+	assert.ElementsMatch(t, []string{currentKID, oldKID}, ks.KeyIDs())
+
+	// A public key is only used for verification.
+	dir = t.TempDir()
+	copyKeyFile(t, "testdata/public.key", filepath.Join(dir, "00-old.pem"))
+	copyKeyFile(t, "testdata/rotated_private.key", filepath.Join(dir, "01-current.pem"))
+	ks, err = NewKeySetFromDir(jwtgo.SigningMethodRS256, dir)
+	require.NoError(t, err)
+	assert.Equal(t, currentKID, ks.PrimaryKeyID())
+	assert.ElementsMatch(t, []string{currentKID, oldKID}, ks.KeyIDs())
+
+	// At least one private key is required.
+	dir = t.TempDir()
+	copyKeyFile(t, "testdata/public.key", filepath.Join(dir, "00-old.pem"))
+	_, err = NewKeySetFromDir(jwtgo.SigningMethodRS256, dir)
+	require.Error(t, err)
+
+	dir = t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.pem"), []byte("not a key"), 0600))
+	_, err = NewKeySetFromDir(jwtgo.SigningMethodRS256, dir)
+	require.Error(t, err)
+
+	_, err = NewKeySetFromDir(jwtgo.SigningMethodRS256, filepath.Join(dir, "missing"))
+	require.Error(t, err)
+}
+
+func TestKeySetWatchDir(t *testing.T) {
+	t.Parallel()
+	claims := NewClaims("user-1", "avatar-url", time.Hour, model.Role{
+		ProjectId: "project-1",
+	})
+	var (
+		oldKID = publicKeyID(t, "testdata/public.key")
+		newKID = publicKeyID(t, "testdata/rotated_public.key")
+	)
+
+	dir := t.TempDir()
+	copyKeyFile(t, "testdata/private.key", filepath.Join(dir, "02-old.pem"))
+	ks, err := NewKeySetFromDir(jwtgo.SigningMethodRS256, dir)
+	require.NoError(t, err)
+	oldToken, err := ks.Sign(claims)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ks.WatchDir(ctx, dir, 10*time.Millisecond, zap.NewNop())
+
+	// The new key is added in front of the old one.
+	copyKeyFile(t, "testdata/rotated_private.key", filepath.Join(dir, "01-new.pem"))
+	require.Eventually(t, func() bool {
+		return ks.PrimaryKeyID() == newKID
+	}, 5*time.Second, 10*time.Millisecond)
+
+	newToken, err := ks.Sign(claims)
+	require.NoError(t, err)
+	token, _, err := jwtgo.NewParser().ParseUnverified(newToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, newKID, token.Header["kid"])
+	_, err = ks.Verify(oldToken)
+	require.NoError(t, err)
+
+	// A broken key file does not change the current keys.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "03-broken.pem"), []byte("not a key"), 0600))
+	time.Sleep(50 * time.Millisecond)
+	assert.ElementsMatch(t, []string{newKID, oldKID}, ks.KeyIDs())
+	require.NoError(t, os.Remove(filepath.Join(dir, "03-broken.pem")))
+
+	// The old token is rejected once its key is removed.
+	require.NoError(t, os.Remove(filepath.Join(dir, "02-old.pem")))
+	require.Eventually(t, func() bool {
+		return len(ks.KeyIDs()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+	_, err = ks.Verify(oldToken)
+	require.Error(t, err)
+	_, err = ks.Verify(newToken)
+	require.NoError(t, err)
+}
@@ -0,0 +1,288 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt/jwtmetrics"
+)
+
+// KeySet signs tokens with a primary RSA key and verifies them with a set of
+// public keys identified by the kid header, so that the primary key can be
+// rotated without invalidating the tokens signed by the previous keys.
+// It implements both Signer and Verifier.
+type KeySet struct {
+	method *jwtgo.SigningMethodRSA
+	scope  scope
+
+	mu         sync.RWMutex
+	primaryKID string
+	primaryKey *rsa.PrivateKey
+	// publicKeys holds the keys used to verify tokens, keyed by kid.
+	// It always contains the public key of the primary key.
+	publicKeys map[string]*rsa.PublicKey
+	// dirVersion identifies the files of the directory the keys were loaded from.
+	// Empty if the keys were not loaded from a directory.
+	dirVersion string
+	// sourceDigest identifies the keys loaded from a KeySource.
+	// Empty if the keys were not loaded from a source.
+	sourceDigest string
+}
+
+// NewKeySet returns a new key set using the given RSA signing method.
+// The private key is used as the primary signing key, and the public keys
+// are only used to verify tokens signed by rotated keys.
+func NewKeySet(method *jwtgo.SigningMethodRSA, privateKey *rsa.PrivateKey, publicKeys ...*rsa.PublicKey) (*KeySet, error) {
+	ks := &KeySet{
+		method:     method,
+		scope:      newScope(nil),
+		publicKeys: make(map[string]*rsa.PublicKey, len(publicKeys)+1),
+	}
+	for _, k := range publicKeys {
+		if err := ks.AddVerificationKey(k); err != nil {
+			return nil, err
+		}
+	}
+	if err := ks.Rotate(privateKey); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// NewKeySetFromPEMFiles returns a new key set using the given RSA signing method
+// and the keys loaded from the given PEM files.
+func NewKeySetFromPEMFiles(method *jwtgo.SigningMethodRSA, privateKeyFile string, publicKeyFiles ...string) (*KeySet, error) {
+	privateKey, err := readRSAPrivateKeyFile(privateKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	publicKeys := make([]*rsa.PublicKey, 0, len(publicKeyFiles))
+	for _, f := range publicKeyFiles {
+		k, err := readRSAPublicKeyFile(f)
+		if err != nil {
+			return nil, err
+		}
+		publicKeys = append(publicKeys, k)
+	}
+	return NewKeySet(method, privateKey, publicKeys...)
+}
+
+// Rotate replaces the primary signing key with the given one.
+// The public key of the previous primary key is kept to verify the tokens
+// signed by it until it is removed by RemoveVerificationKey.
+func (k *KeySet) Rotate(privateKey *rsa.PrivateKey) error {
+	if privateKey == nil {
+		return fmt.Errorf("missing private key")
+	}
+	kid, err := KeyID(&privateKey.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.primaryKID = kid
+	k.primaryKey = privateKey
+	k.publicKeys[kid] = &privateKey.PublicKey
+	jwtmetrics.SetActiveSigningKey(kid)
+	return nil
+}
+
+// SetKeys replaces all keys of the set at once. The first private key becomes the primary
+// signing key, and all the given keys are used to verify tokens.
+// The keys which are not given anymore are removed, so the tokens signed by them are rejected.
+func (k *KeySet) SetKeys(privateKeys []*rsa.PrivateKey, publicKeys ...*rsa.PublicKey) error {
+	if len(privateKeys) == 0 || privateKeys[0] == nil {
+		return fmt.Errorf("missing private key")
+	}
+	keys := make(map[string]*rsa.PublicKey, len(privateKeys)+len(publicKeys))
+	for _, pk := range publicKeys {
+		kid, err := KeyID(pk)
+		if err != nil {
+			return err
+		}
+		keys[kid] = pk
+	}
+	for _, pk := range privateKeys {
+		if pk == nil {
+			return fmt.Errorf("missing private key")
+		}
+		kid, err := KeyID(&pk.PublicKey)
+		if err != nil {
+			return err
+		}
+		keys[kid] = &pk.PublicKey
+	}
+	primaryKID, _ := KeyID(&privateKeys[0].PublicKey)
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.primaryKID = primaryKID
+	k.primaryKey = privateKeys[0]
+	k.publicKeys = keys
+	jwtmetrics.SetActiveSigningKey(primaryKID)
+	return nil
+}
+
+// KeyIDs returns the kids of all keys used to verify tokens in the lexical order.
+func (k *KeySet) KeyIDs() []string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	kids := make([]string, 0, len(k.publicKeys))
+	for kid := range k.publicKeys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+	return kids
+}
+
+// RotateFromPEMFile replaces the primary signing key with the one loaded from the given PEM file.
+func (k *KeySet) RotateFromPEMFile(privateKeyFile string) error {
+	privateKey, err := readRSAPrivateKeyFile(privateKeyFile)
+	if err != nil {
+		return err
+	}
+	return k.Rotate(privateKey)
+}
+
+// AddVerificationKey adds a public key used to verify tokens.
+func (k *KeySet) AddVerificationKey(publicKey *rsa.PublicKey) error {
+	kid, err := KeyID(publicKey)
+	if err != nil {
+		return err
+	}
+
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.publicKeys[kid] = publicKey
+	return nil
+}
+
+// RemoveVerificationKey removes the public key of the given kid.
+// The public key of the primary key can not be removed.
+func (k *KeySet) RemoveVerificationKey(kid string) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if kid == k.primaryKID {
+		return fmt.Errorf("unable to remove the primary key %s", kid)
+	}
+	delete(k.publicKeys, kid)
+	return nil
+}
+
+// PrimaryKeyID returns the kid of the current primary signing key.
+func (k *KeySet) PrimaryKeyID() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.primaryKID
+}
+
+// Sign signs the given claims with the primary key and stamps its kid in the header.
+func (k *KeySet) Sign(claims *Claims) (string, error) {
+	k.mu.RLock()
+	kid, key := k.primaryKID, k.primaryKey
+	k.mu.RUnlock()
+
+	k.scope.stamp(claims)
+	token := jwtgo.NewWithClaims(k.method, claims)
+	token.Header["kid"] = kid
+	return token.SignedString(key)
+}
+
+// Verify verifies the given token with the key matching its kid.
+// Tokens without a known kid are verified against all keys in the set.
+func (k *KeySet) Verify(tokenString string) (*Claims, error) {
+	k.mu.RLock()
+	keys := make(map[string]*rsa.PublicKey, len(k.publicKeys))
+	for kid, key := range k.publicKeys {
+		keys[kid] = key
+	}
+	k.mu.RUnlock()
+
+	token, _, err := jwtgo.NewParser().ParseUnverified(tokenString, &Claims{})
+	if err == nil {
+		if kid, ok := token.Header["kid"].(string); ok {
+			if key, ok := keys[kid]; ok {
+				return k.verifyWithKey(tokenString, kid, key)
+			}
+		}
+	}
+
+	// Fall back to all keys, i.e. during the rotation window.
+	// The keys are tried in the lexical order of their kids to make it deterministic.
+	kids := make([]string, 0, len(keys))
+	for kid := range keys {
+		kids = append(kids, kid)
+	}
+	sort.Strings(kids)
+	err = fmt.Errorf("no verification key")
+	for _, kid := range kids {
+		var claims *Claims
+		if claims, err = k.verifyWithKey(tokenString, kid, keys[kid]); err == nil {
+			return claims, nil
+		}
+	}
+	return nil, err
+}
+
+// verifyWithKey verifies the given token with the given key and counts it for the kid of the key.
+func (k *KeySet) verifyWithKey(tokenString, kid string, key *rsa.PublicKey) (*Claims, error) {
+	claims, err := parseClaims(tokenString, k.method, key, k.scope)
+	if err != nil {
+		return nil, err
+	}
+	jwtmetrics.IncVerifications(kid)
+	return claims, nil
+}
+
+// KeyID returns the kid of the given public key, which is the base64url encoded
+// SHA-256 digest of its DER encoding.
+func KeyID(publicKey *rsa.PublicKey) (string, error) {
+	if publicKey == nil {
+		return "", fmt.Errorf("missing public key")
+	}
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(der)
+	return base64.RawURLEncoding.EncodeToString(sum[:]), nil
+}
+
+func readRSAPrivateKeyFile(keyFile string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read key file: %v", err)
+	}
+	return jwtgo.ParseRSAPrivateKeyFromPEM(data)
+}
+
+func readRSAPublicKeyFile(keyFile string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read key file: %v", err)
+	}
+	return jwtgo.ParseRSAPublicKeyFromPEM(data)
+}
@@ -0,0 +1,129 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestKeySet(t *testing.T) {
+	claims := NewClaims("user-1", "avatar-url", time.Hour, model.Role{
+		ProjectId: "project-1",
+	})
+
+	ks, err := NewKeySetFromPEMFiles(jwtgo.SigningMethodRS256, "testdata/private.key")
+	require.NoError(t, err)
+	oldKID := ks.PrimaryKeyID()
+
+	oldToken, err := ks.Sign(claims)
+	require.NoError(t, err)
+	token, _, err := jwtgo.NewParser().ParseUnverified(oldToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, oldKID, token.Header["kid"])
+
+	// Rotate the primary key.
+	require.NoError(t, ks.RotateFromPEMFile("testdata/rotated_private.key"))
+	newKID := ks.PrimaryKeyID()
+	assert.NotEqual(t, oldKID, newKID)
+
+	newToken, err := ks.Sign(claims)
+	require.NoError(t, err)
+	token, _, err = jwtgo.NewParser().ParseUnverified(newToken, &Claims{})
+	require.NoError(t, err)
+	assert.Equal(t, newKID, token.Header["kid"])
+
+	// Both tokens are valid during the rotation window.
+	got, err := ks.Verify(oldToken)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", got.Subject)
+	_, err = ks.Verify(newToken)
+	require.NoError(t, err)
+
+	// The primary key can not be removed.
+	require.Error(t, ks.RemoveVerificationKey(newKID))
+
+	// The old token is rejected once its key is removed.
+	require.NoError(t, ks.RemoveVerificationKey(oldKID))
+	_, err = ks.Verify(oldToken)
+	require.Error(t, err)
+	_, err = ks.Verify(newToken)
+	require.NoError(t, err)
+}
+
+func TestKeySetVerifyWithoutKID(t *testing.T) {
+	claims := NewClaims("user-1", "avatar-url", time.Hour, model.Role{
+		ProjectId: "project-1",
+	})
+
+	// Tokens signed before introducing the key set have no kid.
+	s, err := NewSigner(jwtgo.SigningMethodRS256, "testdata/private.key")
+	require.NoError(t, err)
+	token, err := s.Sign(claims)
+	require.NoError(t, err)
+
+	ks, err := NewKeySetFromPEMFiles(jwtgo.SigningMethodRS256, "testdata/rotated_private.key", "testdata/public.key")
+	require.NoError(t, err)
+	_, err = ks.Verify(token)
+	require.NoError(t, err)
+
+	ks, err = NewKeySetFromPEMFiles(jwtgo.SigningMethodRS256, "testdata/rotated_private.key")
+	require.NoError(t, err)
+	_, err = ks.Verify(token)
+	require.Error(t, err)
+
+	// Tokens signed by another method are rejected.
+	hs, err := NewSigner(jwtgo.SigningMethodHS256, "testdata/private.key")
+	require.NoError(t, err)
+	token, err = hs.Sign(claims)
+	require.NoError(t, err)
+	_, err = ks.Verify(token)
+	require.Error(t, err)
+}
+
+func TestKeySetVerifyUnexpectedSigningMethod(t *testing.T) {
+	claims := NewClaims("user-1", "avatar-url", time.Hour, model.Role{
+		ProjectId: "project-1",
+	})
+	ks, err := NewKeySetFromPEMFiles(jwtgo.SigningMethodRS256, "testdata/private.key")
+	require.NoError(t, err)
+	publicKey, err := os.ReadFile("testdata/public.key")
+	require.NoError(t, err)
+
+	for name, token := range map[string]*jwtgo.Token{
+		"none":  jwtgo.NewWithClaims(jwtgo.SigningMethodNone, claims),
+		"HS256": jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, claims),
+	} {
+		t.Run(name, func(t *testing.T) {
+			var key interface{} = publicKey
+			if token.Method == jwtgo.SigningMethodNone {
+				key = jwtgo.UnsafeAllowNoneSignatureType
+			}
+			// The kid of the known key does not make the token trusted.
+			token.Header["kid"] = ks.PrimaryKeyID()
+			signed, err := token.SignedString(key)
+			require.NoError(t, err)
+			_, err = ks.Verify(signed)
+			assert.ErrorIs(t, err, ErrUnexpectedSigningMethod)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"go.uber.org/zap"
+)
+
+// KeySource provides the RSA keys of a KeySet, e.g. from the files or a secret manager,
+// so that the keys can be loaded from anywhere without mounting the key files.
+type KeySource interface {
+	// Keys returns the PEM encoded keys in the order of precedence, each of which is either
+	// a private key or a public key. The first private key signs tokens.
+	Keys(ctx context.Context) ([][]byte, error)
+}
+
+// NewDirKeySource returns the KeySource loading the keys from the PEM files in the given directory
+// in the same way as LoadKeyDir, which is the default source of the keys.
+func NewDirKeySource(dir string) KeySource {
+	return dirKeySource(dir)
+}
+
+type dirKeySource string
+
+func (s dirKeySource) Keys(_ context.Context) ([][]byte, error) {
+	files, err := keyFiles(string(s))
+	if err != nil {
+		return nil, err
+	}
+	keys := make([][]byte, 0, len(files))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read key file: %v", err)
+		}
+		keys = append(keys, data)
+	}
+	return keys, nil
+}
+
+// SplitPEM splits the given data into the PEM encoded blocks, e.g. the value of a secret
+// containing the current private key followed by the previous ones.
+func SplitPEM(data []byte) ([][]byte, error) {
+	var blocks [][]byte
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, pem.EncodeToMemory(block))
+	}
+	if len(bytes.TrimSpace(data)) != 0 {
+		return nil, fmt.Errorf("unable to parse PEM data")
+	}
+	return blocks, nil
+}
+
+// ParseKeys parses the given PEM encoded RSA keys, each of which is either a private key or a public key.
+// At least one private key is required.
+func ParseKeys(keys [][]byte) ([]*rsa.PrivateKey, []*rsa.PublicKey, error) {
+	var (
+		privateKeys []*rsa.PrivateKey
+		publicKeys  []*rsa.PublicKey
+	)
+	for i, data := range keys {
+		if k, err := jwtgo.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			privateKeys = append(privateKeys, k)
+			continue
+		}
+		k, err := jwtgo.ParseRSAPublicKeyFromPEM(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to parse key #%d: %v", i, err)
+		}
+		publicKeys = append(publicKeys, k)
+	}
+	if len(privateKeys) == 0 {
+		return nil, nil, fmt.Errorf("no private key found")
+	}
+	return privateKeys, publicKeys, nil
+}
+
+// NewKeySetFromSource returns a new key set using the given RSA signing method
+// and the keys loaded from the given source.
+func NewKeySetFromSource(ctx context.Context, method *jwtgo.SigningMethodRSA, source KeySource, opts ...Option) (*KeySet, error) {
+	ks := &KeySet{method: method, scope: newScope(opts)}
+	if _, err := ks.ReloadFromSource(ctx, source); err != nil {
+		return nil, err
+	}
+	return ks, nil
+}
+
+// ReloadFromSource replaces all keys of the set with the ones loaded from the given source,
+// and reports whether they were replaced. The keys are not replaced if unchanged since the last reload.
+// The current keys are kept if loading fails.
+func (k *KeySet) ReloadFromSource(ctx context.Context, source KeySource) (bool, error) {
+	keys, err := source.Keys(ctx)
+	if err != nil {
+		return false, err
+	}
+	digest := keysDigest(keys)
+	if digest == k.loadedSourceDigest() {
+		return false, nil
+	}
+	privateKeys, publicKeys, err := ParseKeys(keys)
+	if err != nil {
+		return false, err
+	}
+	if err := k.SetKeys(privateKeys, publicKeys...); err != nil {
+		return false, err
+	}
+	k.mu.Lock()
+	k.sourceDigest = digest
+	k.mu.Unlock()
+	return true, nil
+}
+
+func (k *KeySet) loadedSourceDigest() string {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	return k.sourceDigest
+}
+
+// WatchSource reloads the keys from the given source at the given interval until the context is done,
+// so that the rotated keys are picked up without restarting.
+func (k *KeySet) WatchSource(ctx context.Context, source KeySource, interval time.Duration, logger *zap.Logger) {
+	logger = logger.Named("jwt-key-watcher")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := k.ReloadFromSource(ctx, source)
+		if err != nil {
+			logger.Error("failed to reload the keys, the current keys are kept", zap.Error(err))
+			continue
+		}
+		if reloaded {
+			logger.Info("reloaded the keys",
+				zap.String("signing-kid", k.PrimaryKeyID()),
+				zap.Strings("kids", k.KeyIDs()),
+			)
+		}
+	}
+}
+
+// keysDigest returns the digest of the given keys, which changes whenever any of them changes.
+func keysDigest(keys [][]byte) string {
+	h := sha256.New()
+	for _, key := range keys {
+		sum := sha256.Sum256(key)
+		h.Write(sum[:])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,122 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package keysource provides the jwt.KeySource loading the signing keys from the secret managers,
+// which lets the operators avoid mounting the key files.
+package keysource
+
+import (
+	"context"
+	"fmt"
+
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	secretmanagerpb "cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	awssecretsmanager "github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+// secretClient fetches the value of a secret from a secret manager.
+type secretClient interface {
+	secretValue(ctx context.Context, id string) ([]byte, error)
+	close() error
+}
+
+// SecretSource is a jwt.KeySource loading the keys from a secret of a secret manager,
+// whose value is the PEM encoded keys concatenated in the order of precedence,
+// e.g. the current private key followed by the previous one.
+type SecretSource struct {
+	client secretClient
+	id     string
+}
+
+// Keys returns the PEM encoded keys in the latest value of the secret.
+func (s *SecretSource) Keys(ctx context.Context) ([][]byte, error) {
+	data, err := s.client.secretValue(ctx, s.id)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch secret %s: %w", s.id, err)
+	}
+	keys, err := jwt.SplitPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret %s: %w", s.id, err)
+	}
+	return keys, nil
+}
+
+// Close closes the client of the secret manager.
+func (s *SecretSource) Close() error {
+	return s.client.close()
+}
+
+// NewAWSSecretsManager returns the SecretSource loading the keys from the given secret of AWS Secrets Manager,
+// e.g. its ARN. The credentials are loaded from the default credential chain of the AWS SDK.
+func NewAWSSecretsManager(ctx context.Context, secretID string) (*SecretSource, error) {
+	cfg, err := awsconfig.LoadDefaultConfig(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretSource{
+		client: awsClient{awssecretsmanager.NewFromConfig(cfg)},
+		id:     secretID,
+	}, nil
+}
+
+type awsClient struct {
+	client *awssecretsmanager.Client
+}
+
+func (c awsClient) secretValue(ctx context.Context, id string) ([]byte, error) {
+	out, err := c.client.GetSecretValue(ctx, &awssecretsmanager.GetSecretValueInput{SecretId: &id})
+	if err != nil {
+		return nil, err
+	}
+	if out.SecretString != nil {
+		return []byte(*out.SecretString), nil
+	}
+	return out.SecretBinary, nil
+}
+
+func (c awsClient) close() error {
+	return nil
+}
+
+// NewGCPSecretManager returns the SecretSource loading the keys from the given secret version of GCP Secret Manager,
+// e.g. "projects/my-project/secrets/my-secret/versions/latest". The credentials are the application default credentials.
+func NewGCPSecretManager(ctx context.Context, name string) (*SecretSource, error) {
+	client, err := secretmanager.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &SecretSource{
+		client: gcpClient{client},
+		id:     name,
+	}, nil
+}
+
+type gcpClient struct {
+	client *secretmanager.Client
+}
+
+func (c gcpClient) secretValue(ctx context.Context, name string) ([]byte, error) {
+	resp, err := c.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{Name: name})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetPayload().GetData(), nil
+}
+
+func (c gcpClient) close() error {
+	return c.client.Close()
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package keysource
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/jwt"
+)
+
+type fakeSecretClient struct {
+	values map[string][]byte
+	closed bool
+}
+
+func (c *fakeSecretClient) secretValue(_ context.Context, id string) ([]byte, error) {
+	v, ok := c.values[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return v, nil
+}
+
+func (c *fakeSecretClient) close() error {
+	c.closed = true
+	return nil
+}
+
+func TestSecretSource(t *testing.T) {
+	t.Parallel()
+	private, err := os.ReadFile("../testdata/rotated_private.key")
+	require.NoError(t, err)
+	public, err := os.ReadFile("../testdata/public.key")
+	require.NoError(t, err)
+
+	client := &fakeSecretClient{values: map[string][]byte{
+		"keys":    append(append([]byte{}, private...), public...),
+		"invalid": []byte("not a key"),
+	}}
+	source := &SecretSource{client: client, id: "keys"}
+	ks, err := jwt.NewKeySetFromSource(context.Background(), jwtgo.SigningMethodRS256, source)
+	require.NoError(t, err)
+	assert.Len(t, ks.KeyIDs(), 2)
+
+	_, err = (&SecretSource{client: client, id: "invalid"}).Keys(context.Background())
+	assert.ErrorContains(t, err, "invalid secret invalid")
+	_, err = (&SecretSource{client: client, id: "missing"}).Keys(context.Background())
+	assert.ErrorContains(t, err, "unable to fetch secret missing")
+
+	require.NoError(t, source.Close())
+	assert.True(t, client.closed)
+}
@@ -0,0 +1,144 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type fakeKeySource struct {
+	mu   sync.Mutex
+	keys [][]byte
+	err  error
+}
+
+func (s *fakeKeySource) Keys(_ context.Context) ([][]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.keys, s.err
+}
+
+func (s *fakeKeySource) set(keys [][]byte, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys, s.err = keys, err
+}
+
+func readTestKey(t *testing.T, file string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	return data
+}
+
+func TestSplitPEM(t *testing.T) {
+	t.Parallel()
+	private, public := readTestKey(t, "testdata/rotated_private.key"), readTestKey(t, "testdata/public.key")
+	data := append(append([]byte{}, private...), public...)
+
+	blocks, err := SplitPEM(data)
+	require.NoError(t, err)
+	require.Len(t, blocks, 2)
+	privateKeys, publicKeys, err := ParseKeys(blocks)
+	require.NoError(t, err)
+	assert.Len(t, privateKeys, 1)
+	assert.Len(t, publicKeys, 1)
+
+	_, err = SplitPEM(append(data, []byte("not a key")...))
+	assert.Error(t, err)
+}
+
+func TestParseKeys(t *testing.T) {
+	t.Parallel()
+	_, _, err := ParseKeys([][]byte{readTestKey(t, "testdata/public.key")})
+	assert.Error(t, err)
+
+	_, _, err = ParseKeys([][]byte{readTestKey(t, "testdata/private.key"), []byte("not a key")})
+	assert.Error(t, err)
+}
+
+func TestNewKeySetFromSource(t *testing.T) {
+	t.Parallel()
+	var (
+		currentKID = publicKeyID(t, "testdata/rotated_public.key")
+		oldKID     = publicKeyID(t, "testdata/public.key")
+	)
+
+	// The directory is the default source of the keys.
+	dir := t.TempDir()
+	copyKeyFile(t, "testdata/rotated_private.key", filepath.Join(dir, "01-current.pem"))
+	copyKeyFile(t, "testdata/public.key", filepath.Join(dir, "02-old.pem"))
+	ks, err := NewKeySetFromSource(context.Background(), jwtgo.SigningMethodRS256, NewDirKeySource(dir))
+	require.NoError(t, err)
+	assert.Equal(t, currentKID, ks.PrimaryKeyID())
+	assert.ElementsMatch(t, []string{currentKID, oldKID}, ks.KeyIDs())
+
+	source := &fakeKeySource{err: errors.New("unavailable")}
+	_, err = NewKeySetFromSource(context.Background(), jwtgo.SigningMethodRS256, source)
+	assert.Error(t, err)
+
+	source.set([][]byte{readTestKey(t, "testdata/private.key")}, nil)
+	ks, err = NewKeySetFromSource(context.Background(), jwtgo.SigningMethodRS256, source)
+	require.NoError(t, err)
+	assert.Equal(t, oldKID, ks.PrimaryKeyID())
+
+	// The unchanged keys are not reloaded.
+	reloaded, err := ks.ReloadFromSource(context.Background(), source)
+	require.NoError(t, err)
+	assert.False(t, reloaded)
+}
+
+func TestKeySetWatchSource(t *testing.T) {
+	t.Parallel()
+	var (
+		oldKID = publicKeyID(t, "testdata/public.key")
+		newKID = publicKeyID(t, "testdata/rotated_public.key")
+	)
+	source := &fakeKeySource{keys: [][]byte{readTestKey(t, "testdata/private.key")}}
+	ks, err := NewKeySetFromSource(context.Background(), jwtgo.SigningMethodRS256, source)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go ks.WatchSource(ctx, source, 10*time.Millisecond, zap.NewNop())
+
+	// The rotated secret is picked up.
+	source.set([][]byte{readTestKey(t, "testdata/rotated_private.key"), readTestKey(t, "testdata/private.key")}, nil)
+	require.Eventually(t, func() bool {
+		return ks.PrimaryKeyID() == newKID
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{newKID, oldKID}, ks.KeyIDs())
+
+	// The current keys are kept while the source fails.
+	source.set(nil, errors.New("unavailable"))
+	time.Sleep(50 * time.Millisecond)
+	assert.ElementsMatch(t, []string{newKID, oldKID}, ks.KeyIDs())
+
+	source.set([][]byte{readTestKey(t, "testdata/rotated_private.key")}, nil)
+	require.Eventually(t, func() bool {
+		return len(ks.KeyIDs()) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,104 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pipe-cd/pipecd/pkg/cache"
+)
+
+const membershipKeyPrefix = "membership:"
+
+// ErrMembershipRevoked is returned when the user of the token no longer exists
+// or is no longer granted the roles of the token by the identity provider.
+var ErrMembershipRevoked = errors.New("membership has been revoked")
+
+// MembershipChecker looks up the user of the token at the identity provider.
+type MembershipChecker interface {
+	// IsMember reports whether the user of the given claims still exists
+	// and is still granted all the roles of the claims.
+	IsMember(claims *Claims) (bool, error)
+}
+
+type membershipVerifier struct {
+	verifier Verifier
+	checker  MembershipChecker
+	cache    cache.Cache
+}
+
+// NewMembershipVerifier returns a verifier that additionally rejects
+// the tokens whose user was removed from the identity provider.
+// The results of the checker are kept in the given cache, whose TTL
+// bounds how long the removed user can keep using the token.
+func NewMembershipVerifier(v Verifier, checker MembershipChecker, c cache.Cache) Verifier {
+	return &membershipVerifier{
+		verifier: v,
+		checker:  checker,
+		cache:    c,
+	}
+}
+
+func (v *membershipVerifier) Verify(token string) (*Claims, error) {
+	claims, err := v.verifier.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	member, err := v.isMember(claims)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check membership: %w", err)
+	}
+	if !member {
+		return nil, ErrMembershipRevoked
+	}
+	return claims, nil
+}
+
+// isMember returns the cached result of the checker if any.
+// The errors of the checker are not cached so that it is retried by the next request.
+func (v *membershipVerifier) isMember(claims *Claims) (bool, error) {
+	key := membershipKey(claims)
+	if value, err := v.cache.Get(key); err == nil {
+		switch value := value.(type) {
+		case []byte:
+			return string(value) == "1", nil
+		case string:
+			return value == "1", nil
+		}
+	} else if !errors.Is(err, cache.ErrNotFound) {
+		return false, err
+	}
+
+	member, err := v.checker.IsMember(claims)
+	if err != nil {
+		return false, err
+	}
+	value := "0"
+	if member {
+		value = "1"
+	}
+	if err := v.cache.Put(key, value); err != nil {
+		return false, err
+	}
+	return member, nil
+}
+
+// membershipKey returns the cache key of the claims.
+// The roles are part of the key since the result depends on them.
+func membershipKey(claims *Claims) string {
+	return membershipKeyPrefix + claims.Role.ProjectId + ":" + claims.Provider + ":" + claims.Subject + ":" + strings.Join(claims.Role.ProjectRbacRoles, ",")
+}
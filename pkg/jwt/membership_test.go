@@ -0,0 +1,90 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+type fakeMembershipChecker struct {
+	member bool
+	err    error
+	calls  int
+}
+
+func (c *fakeMembershipChecker) IsMember(*Claims) (bool, error) {
+	c.calls++
+	return c.member, c.err
+}
+
+func TestMembershipVerifier(t *testing.T) {
+	claims := NewClaims("user-1", "", time.Hour, model.Role{ProjectId: "project-1", ProjectRbacRoles: []string{"Admin"}})
+	checker := &fakeMembershipChecker{member: true}
+	c := memorycache.NewCache()
+
+	v := NewMembershipVerifier(fakeVerifier{claims: claims}, checker, c)
+	for i := 0; i < 3; i++ {
+		got, err := v.Verify("token")
+		require.NoError(t, err)
+		assert.Equal(t, claims, got)
+	}
+	// The result is cached until the cache expires it.
+	assert.Equal(t, 1, checker.calls)
+
+	checker.member = false
+	_, err := v.Verify("token")
+	require.NoError(t, err)
+
+	require.NoError(t, c.Delete(membershipKey(claims)))
+	_, err = v.Verify("token")
+	assert.ErrorIs(t, err, ErrMembershipRevoked)
+	_, err = v.Verify("token")
+	assert.ErrorIs(t, err, ErrMembershipRevoked)
+	assert.Equal(t, 2, checker.calls)
+
+	// The errors of the checker are not cached.
+	unavailable := errors.New("unavailable")
+	checker = &fakeMembershipChecker{err: unavailable}
+	v = NewMembershipVerifier(fakeVerifier{claims: claims}, checker, memorycache.NewCache())
+	for i := 0; i < 2; i++ {
+		_, err = v.Verify("token")
+		assert.ErrorIs(t, err, unavailable)
+	}
+	assert.Equal(t, 2, checker.calls)
+
+	invalid := errors.New("invalid")
+	v = NewMembershipVerifier(fakeVerifier{err: invalid}, checker, c)
+	_, err = v.Verify("token")
+	assert.ErrorIs(t, err, invalid)
+}
+
+func TestMembershipKey(t *testing.T) {
+	admin := NewClaims("user-1", "", time.Hour, model.Role{ProjectId: "project-1", ProjectRbacRoles: []string{"Admin"}})
+	viewer := NewClaims("user-1", "", time.Hour, model.Role{ProjectId: "project-1", ProjectRbacRoles: []string{"Viewer"}})
+	other := NewClaims("user-1", "", time.Hour, model.Role{ProjectId: "project-2", ProjectRbacRoles: []string{"Admin"}})
+	assert.NotEqual(t, membershipKey(admin), membershipKey(viewer))
+	assert.NotEqual(t, membershipKey(admin), membershipKey(other))
+
+	again := NewClaims("user-1", "", time.Hour, model.Role{ProjectId: "project-1", ProjectRbacRoles: []string{"Admin"}})
+	assert.Equal(t, membershipKey(admin), membershipKey(again))
+}
@@ -0,0 +1,96 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// DefaultLeeway is the leeway of the time claims of the tokens unless configured.
+	DefaultLeeway = time.Minute
+	// MaxLeeway bounds the leeway so that it does not extend the sessions significantly.
+	MaxLeeway = 5 * time.Minute
+)
+
+// Option configures the issuer and the audience stamped on the tokens by the signers
+// and required by the verifiers, which keeps the tokens of a deployment,
+// e.g. staging, from being accepted by another one, e.g. production.
+type Option func(*scope)
+
+// WithIssuer sets the iss claim of the tokens. Empty means the default issuer "PipeCD".
+func WithIssuer(issuer string) Option {
+	return func(s *scope) {
+		s.issuer = issuer
+	}
+}
+
+// WithAudience sets the aud claim of the tokens, e.g. the address of the control plane.
+// Empty means the tokens have no audience and the audience is not verified.
+func WithAudience(audience string) Option {
+	return func(s *scope) {
+		s.audience = audience
+	}
+}
+
+// WithLeeway sets the leeway of the exp, nbf and iat claims verified by the verifiers,
+// which tolerates the clock skew between the control plane and the clients.
+// It is bounded to [0, MaxLeeway]. Zero means no leeway.
+func WithLeeway(leeway time.Duration) Option {
+	return func(s *scope) {
+		s.leeway = min(max(leeway, 0), MaxLeeway)
+	}
+}
+
+type scope struct {
+	// issuer is empty if not configured, then the issuer set by NewClaims is kept.
+	issuer   string
+	audience string
+	leeway   time.Duration
+}
+
+func newScope(opts []Option) scope {
+	s := scope{leeway: DefaultLeeway}
+	for _, opt := range opts {
+		opt(&s)
+	}
+	return s
+}
+
+// stamp sets the issuer and the audience to the given claims.
+func (s scope) stamp(claims *Claims) {
+	if s.issuer != "" {
+		claims.Issuer = s.issuer
+	}
+	if s.audience != "" {
+		claims.Audience = jwtgo.ClaimStrings{s.audience}
+	}
+}
+
+// parserOptions returns the options of the parser requiring the issuer and the audience
+// and allowing the leeway of the time claims.
+func (s scope) parserOptions() []jwtgo.ParserOption {
+	issuer := s.issuer
+	if issuer == "" {
+		issuer = Issuer
+	}
+	opts := []jwtgo.ParserOption{jwtgo.WithIssuer(issuer), jwtgo.WithLeeway(s.leeway)}
+	if s.audience != "" {
+		opts = append(opts, jwtgo.WithAudience(s.audience))
+	}
+	return opts
+}
@@ -0,0 +1,124 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	jwtgo "github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestIssuerAndAudience(t *testing.T) {
+	t.Parallel()
+	newSigner := func(opts ...Option) Signer {
+		s, err := NewSigner(jwtgo.SigningMethodRS256, "testdata/private.key", opts...)
+		require.NoError(t, err)
+		return s
+	}
+	newVerifier := func(opts ...Option) Verifier {
+		v, err := NewVerifier(jwtgo.SigningMethodRS256, "testdata/public.key", opts...)
+		require.NoError(t, err)
+		return v
+	}
+	sign := func(s Signer) string {
+		token, err := s.Sign(NewClaims("user", "", time.Hour, model.Role{ProjectId: "project"}))
+		require.NoError(t, err)
+		return token
+	}
+
+	production := []Option{WithIssuer("pipecd-production"), WithAudience("https://pipecd.example.com")}
+	staging := []Option{WithIssuer("pipecd-staging"), WithAudience("https://staging.pipecd.example.com")}
+
+	claims, err := newVerifier(production...).Verify(sign(newSigner(production...)))
+	require.NoError(t, err)
+	assert.Equal(t, "pipecd-production", claims.Issuer)
+	assert.Equal(t, jwtgo.ClaimStrings{"https://pipecd.example.com"}, claims.Audience)
+
+	// The token of another deployment is rejected.
+	_, err = newVerifier(production...).Verify(sign(newSigner(staging...)))
+	assert.ErrorContains(t, err, "invalid issuer")
+	_, err = newVerifier(WithAudience("https://pipecd.example.com")).Verify(sign(newSigner(WithAudience("https://staging.pipecd.example.com"))))
+	assert.ErrorContains(t, err, "invalid audience")
+
+	// The token without the audience is rejected once the audience is required.
+	_, err = newVerifier(WithAudience("https://pipecd.example.com")).Verify(sign(newSigner()))
+	assert.ErrorContains(t, err, "aud claim is required")
+
+	// The default issuer is used unless configured.
+	claims, err = newVerifier(WithIssuer("")).Verify(sign(newSigner()))
+	require.NoError(t, err)
+	assert.Equal(t, Issuer, claims.Issuer)
+	assert.Empty(t, claims.Audience)
+
+	// The key set loaded from the directory is configured in the same way.
+	dir := t.TempDir()
+	copyKeyFile(t, "testdata/private.key", filepath.Join(dir, "01-current.pem"))
+	ks, err := NewKeySetFromDir(jwtgo.SigningMethodRS256, dir, production...)
+	require.NoError(t, err)
+	_, err = ks.Verify(sign(ks))
+	require.NoError(t, err)
+	_, err = ks.Verify(sign(newSigner(staging...)))
+	assert.ErrorContains(t, err, "invalid issuer")
+}
+
+func TestLeeway(t *testing.T) {
+	t.Parallel()
+	s, err := NewSigner(jwtgo.SigningMethodRS256, "testdata/private.key")
+	require.NoError(t, err)
+	newVerifier := func(opts ...Option) Verifier {
+		v, err := NewVerifier(jwtgo.SigningMethodRS256, "testdata/public.key", opts...)
+		require.NoError(t, err)
+		return v
+	}
+	sign := func(issuedAt, expiresAt time.Time) string {
+		claims := NewClaims("user", "", time.Hour, model.Role{ProjectId: "project"})
+		claims.IssuedAt = jwtgo.NewNumericDate(issuedAt)
+		claims.NotBefore = jwtgo.NewNumericDate(issuedAt)
+		claims.ExpiresAt = jwtgo.NewNumericDate(expiresAt)
+		token, err := s.Sign(claims)
+		require.NoError(t, err)
+		return token
+	}
+	now := time.Now()
+	justExpired := sign(now.Add(-time.Hour), now.Add(-30*time.Second))
+	issuedAhead := sign(now.Add(30*time.Second), now.Add(time.Hour))
+	longExpired := sign(now.Add(-time.Hour), now.Add(-10*time.Minute))
+
+	// The default leeway accepts the tokens within the clock skew.
+	for _, token := range []string{justExpired, issuedAhead} {
+		_, err := newVerifier().Verify(token)
+		assert.NoError(t, err)
+	}
+	_, err = newVerifier().Verify(longExpired)
+	assert.ErrorIs(t, err, jwtgo.ErrTokenExpired)
+
+	// Zero means no leeway.
+	_, err = newVerifier(WithLeeway(0)).Verify(justExpired)
+	assert.ErrorIs(t, err, jwtgo.ErrTokenExpired)
+	_, err = newVerifier(WithLeeway(0)).Verify(issuedAhead)
+	assert.ErrorIs(t, err, jwtgo.ErrTokenUsedBeforeIssued)
+
+	// The leeway is bounded by the maximum.
+	assert.Equal(t, MaxLeeway, newScope([]Option{WithLeeway(time.Hour)}).leeway)
+	assert.Equal(t, time.Duration(0), newScope([]Option{WithLeeway(-time.Minute)}).leeway)
+	_, err = newVerifier(WithLeeway(time.Hour)).Verify(longExpired)
+	assert.ErrorIs(t, err, jwtgo.ErrTokenExpired)
+}
@@ -0,0 +1,201 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pipe-cd/pipecd/pkg/cache"
+)
+
+const (
+	sessionKeyPrefix       = "session:"
+	revokedAtKeyPrefix     = "session-revoked-at:"
+	idpRevokedAtKeyPrefix  = "session-idp-revoked-at:"
+	idpSubjectKeyNamespace = "sub"
+	idpSessionKeyNamespace = "sid"
+)
+
+// ErrSessionRevoked is returned when the session of the token has been revoked.
+var ErrSessionRevoked = errors.New("session has been revoked")
+
+// SessionStore records the issued tokens so that they can be revoked before expiring.
+type SessionStore interface {
+	// Register records the session of the given claims.
+	Register(claims *Claims) error
+	// Revoke revokes the session of the given token ID.
+	Revoke(id string) error
+	// RevokeAll revokes all sessions issued so far for the given user in the given project.
+	RevokeAll(projectID, subject string) error
+	// RevokeIdPSession revokes the sessions issued so far in the given project for the logins to the identity provider
+	// with the given subject or session ID, e.g. as requested by the back-channel logout of the provider.
+	// Either of them may be empty.
+	RevokeIdPSession(projectID, idpSubject, idpSessionID string) error
+	// IsIdPSessionRevoked reports whether the session of the identity provider with the given subject or session ID
+	// was revoked by RevokeIdPSession since the user logged in at the given time.
+	IsIdPSessionRevoked(projectID, idpSubject, idpSessionID string, loggedInAt time.Time) (bool, error)
+	// IsRevoked reports whether the session of the given claims was revoked or never registered.
+	// The session of the token without id is only revoked by RevokeAll and RevokeIdPSession.
+	IsRevoked(claims *Claims) (bool, error)
+}
+
+type cacheSessionStore struct {
+	cache cache.Cache
+	now   func() time.Time
+}
+
+// NewCacheSessionStore returns a session store using the given cache.
+// The cache must keep the stored items at least as long as the longest token TTL.
+func NewCacheSessionStore(c cache.Cache) SessionStore {
+	return &cacheSessionStore{
+		cache: c,
+		now:   time.Now,
+	}
+}
+
+func (s *cacheSessionStore) Register(claims *Claims) error {
+	if claims.ID == "" {
+		return fmt.Errorf("missing token id")
+	}
+	return s.cache.Put(sessionKeyPrefix+claims.ID, claims.Role.ProjectId)
+}
+
+func (s *cacheSessionStore) Revoke(id string) error {
+	return s.cache.Delete(sessionKeyPrefix + id)
+}
+
+func (s *cacheSessionStore) RevokeAll(projectID, subject string) error {
+	return s.cache.Put(revokedAtKey(projectID, subject), strconv.FormatInt(s.now().Unix(), 10))
+}
+
+func (s *cacheSessionStore) RevokeIdPSession(projectID, idpSubject, idpSessionID string) error {
+	now := strconv.FormatInt(s.now().Unix(), 10)
+	if idpSubject != "" {
+		if err := s.cache.Put(idpRevokedAtKey(projectID, idpSubjectKeyNamespace, idpSubject), now); err != nil {
+			return err
+		}
+	}
+	if idpSessionID != "" {
+		if err := s.cache.Put(idpRevokedAtKey(projectID, idpSessionKeyNamespace, idpSessionID), now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *cacheSessionStore) IsIdPSessionRevoked(projectID, idpSubject, idpSessionID string, loggedInAt time.Time) (bool, error) {
+	if idpSubject != "" {
+		revoked, err := s.revokedSince(idpRevokedAtKey(projectID, idpSubjectKeyNamespace, idpSubject), loggedInAt.Unix())
+		if revoked || err != nil {
+			return revoked, err
+		}
+	}
+	if idpSessionID != "" {
+		return s.revokedSince(idpRevokedAtKey(projectID, idpSessionKeyNamespace, idpSessionID), loggedInAt.Unix())
+	}
+	return false, nil
+}
+
+func (s *cacheSessionStore) IsRevoked(claims *Claims) (bool, error) {
+	// The token without id was issued by the older version which had no session store,
+	// so it is accepted until it expires unless all sessions of the user are revoked.
+	if claims.ID != "" {
+		if _, err := s.cache.Get(sessionKeyPrefix + claims.ID); err != nil {
+			if errors.Is(err, cache.ErrNotFound) {
+				return true, nil
+			}
+			return false, err
+		}
+	}
+	// The session issued at unknown time is revoked by any of the revocations.
+	var issuedAt time.Time
+	if claims.IssuedAt != nil {
+		issuedAt = claims.IssuedAt.Time
+	}
+	revoked, err := s.revokedSince(revokedAtKey(claims.Role.ProjectId, claims.Subject), issuedAt.Unix())
+	if revoked || err != nil {
+		return revoked, err
+	}
+	return s.IsIdPSessionRevoked(claims.Role.ProjectId, claims.IdPSubject, claims.IdPSessionID, issuedAt)
+}
+
+// revokedSince reports whether the revocation recorded at the given key was made at or after the given time in seconds.
+func (s *cacheSessionStore) revokedSince(key string, since int64) (bool, error) {
+	v, err := s.cache.Get(key)
+	if errors.Is(err, cache.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	revokedAt, err := parseUnix(v)
+	if err != nil {
+		return false, err
+	}
+	return since <= revokedAt, nil
+}
+
+func revokedAtKey(projectID, subject string) string {
+	return revokedAtKeyPrefix + projectID + ":" + subject
+}
+
+// idpRevokedAtKey returns the key of the revocation of the given subject or session ID of the identity provider,
+// which is namespaced by the given claim name so that a subject never matches a session ID.
+func idpRevokedAtKey(projectID, namespace, value string) string {
+	return idpRevokedAtKeyPrefix + projectID + ":" + namespace + ":" + value
+}
+
+func parseUnix(v interface{}) (int64, error) {
+	switch v := v.(type) {
+	case []byte:
+		return strconv.ParseInt(string(v), 10, 64)
+	case string:
+		return strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, fmt.Errorf("unexpected revocation time type: %T", v)
+	}
+}
+
+type sessionVerifier struct {
+	verifier Verifier
+	store    SessionStore
+}
+
+// NewSessionVerifier returns a verifier that additionally rejects
+// the tokens whose session was revoked in the given store.
+func NewSessionVerifier(v Verifier, store SessionStore) Verifier {
+	return &sessionVerifier{
+		verifier: v,
+		store:    store,
+	}
+}
+
+func (v *sessionVerifier) Verify(token string) (*Claims, error) {
+	claims, err := v.verifier.Verify(token)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := v.store.IsRevoked(claims)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check session: %w", err)
+	}
+	if revoked {
+		return nil, ErrSessionRevoked
+	}
+	return claims, nil
+}
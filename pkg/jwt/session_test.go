@@ -0,0 +1,172 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestCacheSessionStore(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := &cacheSessionStore{
+		cache: memorycache.NewCache(),
+		now:   func() time.Time { return now },
+	}
+	newClaims := func(subject, projectID string, issuedAt time.Time) *Claims {
+		c := NewClaims(subject, "", time.Hour, model.Role{ProjectId: projectID})
+		c.IssuedAt.Time = issuedAt
+		return c
+	}
+
+	unregistered := newClaims("user-1", "project-1", now)
+	revoked, err := store.IsRevoked(unregistered)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+
+	var (
+		old     = newClaims("user-1", "project-1", now.Add(-time.Minute))
+		other   = newClaims("user-2", "project-1", now.Add(-time.Minute))
+		another = newClaims("user-1", "project-2", now.Add(-time.Minute))
+		single  = newClaims("user-3", "project-1", now.Add(-time.Minute))
+		fresh   = newClaims("user-1", "project-1", now.Add(time.Second))
+		legacy  = newClaims("user-1", "project-1", now.Add(-time.Minute))
+		kept    = newClaims("user-2", "project-1", now.Add(-time.Minute))
+	)
+	// The tokens issued before the session store was introduced have no id.
+	legacy.ID, kept.ID = "", ""
+	for _, c := range []*Claims{old, other, another, single, fresh} {
+		require.NoError(t, store.Register(c))
+		revoked, err := store.IsRevoked(c)
+		require.NoError(t, err)
+		assert.False(t, revoked)
+	}
+
+	require.NoError(t, store.RevokeAll("project-1", "user-1"))
+	require.NoError(t, store.Revoke(single.ID))
+
+	testcases := []struct {
+		name     string
+		claims   *Claims
+		expected bool
+	}{
+		{name: "issued before revoking all", claims: old, expected: true},
+		{name: "another user", claims: other, expected: false},
+		{name: "another project", claims: another, expected: false},
+		{name: "revoked individually", claims: single, expected: true},
+		{name: "issued after revoking all", claims: fresh, expected: false},
+		{name: "without id issued before revoking all", claims: legacy, expected: true},
+		{name: "without id", claims: kept, expected: false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			revoked, err := store.IsRevoked(tc.claims)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, revoked)
+		})
+	}
+}
+
+func TestCacheSessionStoreIdPSession(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	store := &cacheSessionStore{
+		cache: memorycache.NewCache(),
+		now:   func() time.Time { return now },
+	}
+	newClaims := func(projectID, idpSubject, idpSessionID string, issuedAt time.Time) *Claims {
+		c := NewClaims("user-1", "", time.Hour, model.Role{ProjectId: projectID})
+		c.IssuedAt.Time = issuedAt
+		c.IdPSubject = idpSubject
+		c.IdPSessionID = idpSessionID
+		require.NoError(t, store.Register(c))
+		return c
+	}
+
+	var (
+		bySession      = newClaims("project-1", "sub-1", "sid-1", now.Add(-time.Minute))
+		otherSession   = newClaims("project-1", "sub-2", "sid-2", now.Add(-time.Minute))
+		bySubject      = newClaims("project-1", "sub-3", "sid-3", now.Add(-time.Minute))
+		anotherProject = newClaims("project-2", "sub-1", "sid-1", now.Add(-time.Minute))
+		noIdPSession   = newClaims("project-1", "", "", now.Add(-time.Minute))
+		fresh          = newClaims("project-1", "sub-3", "sid-4", now.Add(time.Second))
+	)
+	require.NoError(t, store.RevokeIdPSession("project-1", "", "sid-1"))
+	require.NoError(t, store.RevokeIdPSession("project-1", "sub-3", ""))
+	// The subject is never matched with the session ID.
+	require.NoError(t, store.RevokeIdPSession("project-1", "sid-2", ""))
+
+	testcases := []struct {
+		name     string
+		claims   *Claims
+		expected bool
+	}{
+		{name: "revoked by session id", claims: bySession, expected: true},
+		{name: "another session", claims: otherSession, expected: false},
+		{name: "revoked by subject", claims: bySubject, expected: true},
+		{name: "another project", claims: anotherProject, expected: false},
+		{name: "not logged in via identity provider", claims: noIdPSession, expected: false},
+		{name: "logged in after revoking", claims: fresh, expected: false},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			revoked, err := store.IsRevoked(tc.claims)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, revoked)
+		})
+	}
+
+	revoked, err := store.IsIdPSessionRevoked("project-1", "", "sid-1", now)
+	require.NoError(t, err)
+	assert.True(t, revoked)
+	revoked, err = store.IsIdPSessionRevoked("project-1", "sub-3", "", now.Add(time.Second))
+	require.NoError(t, err)
+	assert.False(t, revoked)
+}
+
+type fakeVerifier struct {
+	claims *Claims
+	err    error
+}
+
+func (v fakeVerifier) Verify(string) (*Claims, error) {
+	return v.claims, v.err
+}
+
+func TestSessionVerifier(t *testing.T) {
+	store := NewCacheSessionStore(memorycache.NewCache())
+	claims := NewClaims("user-1", "", time.Hour, model.Role{ProjectId: "project-1"})
+	require.NoError(t, store.Register(claims))
+
+	v := NewSessionVerifier(fakeVerifier{claims: claims}, store)
+	got, err := v.Verify("token")
+	require.NoError(t, err)
+	assert.Equal(t, claims, got)
+
+	require.NoError(t, store.Revoke(claims.ID))
+	_, err = v.Verify("token")
+	assert.ErrorIs(t, err, ErrSessionRevoked)
+
+	invalid := errors.New("invalid")
+	v = NewSessionVerifier(fakeVerifier{err: invalid}, store)
+	_, err = v.Verify("token")
+	assert.ErrorIs(t, err, invalid)
+}
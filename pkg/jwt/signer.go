@@ -27,21 +27,24 @@ type Signer interface {
 type signer struct {
 	key    interface{}
 	method jwtgo.SigningMethod
+	scope  scope
 }
 
-// NewSigner returns a new signer using SigningMethodRS256.
-func NewSigner(method jwtgo.SigningMethod, keyFile string) (Signer, error) {
+// NewSigner returns a new signer using the given signing method.
+func NewSigner(method jwtgo.SigningMethod, keyFile string, opts ...Option) (Signer, error) {
 	key, err := readKeyFile(method, keyFile, true)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read key file: %v", err)
+		return nil, fmt.Errorf("unable to read key file: %w", err)
 	}
 	return &signer{
 		key:    key,
 		method: method,
+		scope:  newScope(opts),
 	}, nil
 }
 
 func (s *signer) Sign(claims *Claims) (string, error) {
+	s.scope.stamp(claims)
 	token := jwtgo.NewWithClaims(s.method, claims)
 	return token.SignedString(s.key)
 }
@@ -15,11 +15,16 @@
 package jwt
 
 import (
+	"errors"
 	"fmt"
 
 	jwtgo "github.com/golang-jwt/jwt/v5"
 )
 
+// ErrUnexpectedSigningMethod is returned when the alg header of the token is not the expected one,
+// e.g. "none" or HS256 of the token forged with the RSA public key.
+var ErrUnexpectedSigningMethod = errors.New("unexpected signing method")
+
 type Verifier interface {
 	Verify(token string) (*Claims, error)
 }
@@ -27,37 +32,47 @@ type Verifier interface {
 type verifier struct {
 	key    interface{}
 	method jwtgo.SigningMethod
+	scope  scope
 }
 
 // NewVerifier returns a new verifier using given signing method.
-func NewVerifier(method jwtgo.SigningMethod, keyFile string) (Verifier, error) {
+func NewVerifier(method jwtgo.SigningMethod, keyFile string, opts ...Option) (Verifier, error) {
 	key, err := readKeyFile(method, keyFile, false)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read key file: %v", err)
+		return nil, fmt.Errorf("unable to read key file: %w", err)
 	}
 	return &verifier{
 		key:    key,
 		method: method,
+		scope:  newScope(opts),
 	}, nil
 }
 
 func (v *verifier) Verify(tokenString string) (*Claims, error) {
+	return parseClaims(tokenString, v.method, v.key, v.scope)
+}
+
+// parseClaims parses the token signed by the given method with the given key.
+// The method is enforced instead of trusting the alg header of the token,
+// so that the tokens with alg=none or another algorithm are rejected before their signature is checked.
+func parseClaims(tokenString string, method jwtgo.SigningMethod, key interface{}, s scope) (*Claims, error) {
+	keyFunc := func(token *jwtgo.Token) (interface{}, error) {
+		if token.Method.Alg() != method.Alg() {
+			return nil, fmt.Errorf("%w: %v", ErrUnexpectedSigningMethod, token.Header["alg"])
+		}
+		return key, nil
+	}
+
 	// NOTE: The issuedAt and notBefore claims are set to "used if exists" by default.
 	// ref: https://github.com/golang-jwt/jwt/issues/411#issuecomment-2423818974
-	parser := jwtgo.NewParser(
-		jwtgo.WithIssuer(Issuer),
+	parser := jwtgo.NewParser(append(s.parserOptions(),
 		jwtgo.WithIssuedAt(),
 		jwtgo.WithExpirationRequired(),
-	)
+	)...)
 
-	token, err := parser.ParseWithClaims(tokenString, &Claims{}, func(token *jwtgo.Token) (interface{}, error) {
-		if v.method != token.Method {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Method.Alg())
-		}
-		return v.key, nil
-	})
+	token, err := parser.ParseWithClaims(tokenString, &Claims{}, keyFunc)
 	if err != nil {
-		return nil, fmt.Errorf("unable to parse token: %v", err)
+		return nil, fmt.Errorf("unable to parse token: %w", err)
 	}
 	if !token.Valid {
 		return nil, fmt.Errorf("token is not valid")
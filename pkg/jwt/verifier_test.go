@@ -16,6 +16,7 @@ package jwt
 
 import (
 	"fmt"
+	"os"
 	"strings"
 	"testing"
 	"time"
@@ -129,6 +130,26 @@ func TestVerify(t *testing.T) {
 
 	testFunc(hsS, hsV)
 
+	for _, tc := range []struct {
+		method     jwtgo.SigningMethod
+		privateKey string
+		publicKey  string
+	}{
+		{jwtgo.SigningMethodES256, "testdata/es256_private.key", "testdata/es256_public.key"},
+		{jwtgo.SigningMethodEdDSA, "testdata/ed25519_private.key", "testdata/ed25519_public.key"},
+	} {
+		s, err := NewSigner(tc.method, tc.privateKey)
+		require.NoError(t, err)
+		v, err := NewVerifier(tc.method, tc.publicKey)
+		require.NoError(t, err)
+		testFunc(s, v)
+
+		// The private key also verifies by its public key.
+		v, err = NewVerifier(tc.method, tc.privateKey)
+		require.NoError(t, err)
+		testFunc(s, v)
+	}
+
 	c := NewClaims("user", "avatar-url", time.Hour, model.Role{
 		ProjectId: "project",
 	})
@@ -147,3 +168,120 @@ func TestVerify(t *testing.T) {
 	require.Error(t, err)
 	require.Nil(t, got)
 }
+
+func TestVerifyUnexpectedSigningMethod(t *testing.T) {
+	claims := NewClaims("user", "avatar-url", time.Hour, model.Role{
+		ProjectId: "project",
+	})
+
+	rsV, err := NewVerifier(jwtgo.SigningMethodRS256, "testdata/public.key")
+	require.NoError(t, err)
+	hsV, err := NewVerifier(jwtgo.SigningMethodHS256, "testdata/private.key")
+	require.NoError(t, err)
+
+	noneToken, err := jwtgo.NewWithClaims(jwtgo.SigningMethodNone, claims).SignedString(jwtgo.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	// The token forged by signing with HS256 by the RSA public key, which is known to anyone.
+	publicKey, err := os.ReadFile("testdata/public.key")
+	require.NoError(t, err)
+	swappedToken, err := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, claims).SignedString(publicKey)
+	require.NoError(t, err)
+
+	// The token signed by another algorithm of the same family.
+	hs512Token, err := NewSigner(jwtgo.SigningMethodHS512, "testdata/private.key")
+	require.NoError(t, err)
+	otherToken, err := hs512Token.Sign(claims)
+	require.NoError(t, err)
+
+	esS, err := NewSigner(jwtgo.SigningMethodES256, "testdata/es256_private.key")
+	require.NoError(t, err)
+	esToken, err := esS.Sign(claims)
+	require.NoError(t, err)
+	esV, err := NewVerifier(jwtgo.SigningMethodES256, "testdata/es256_public.key")
+	require.NoError(t, err)
+	edS, err := NewSigner(jwtgo.SigningMethodEdDSA, "testdata/ed25519_private.key")
+	require.NoError(t, err)
+	edToken, err := edS.Sign(claims)
+	require.NoError(t, err)
+	edV, err := NewVerifier(jwtgo.SigningMethodEdDSA, "testdata/ed25519_public.key")
+	require.NoError(t, err)
+
+	testcases := []struct {
+		name     string
+		verifier Verifier
+		token    string
+	}{
+		{name: "none for RS256", verifier: rsV, token: noneToken},
+		{name: "none for HS256", verifier: hsV, token: noneToken},
+		{name: "HS256 for RS256", verifier: rsV, token: swappedToken},
+		{name: "HS512 for HS256", verifier: hsV, token: otherToken},
+		{name: "none for ES256", verifier: esV, token: noneToken},
+		{name: "EdDSA for ES256", verifier: esV, token: edToken},
+		{name: "ES256 for EdDSA", verifier: edV, token: esToken},
+		{name: "ES256 for RS256", verifier: rsV, token: esToken},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.verifier.Verify(tc.token)
+			assert.ErrorIs(t, err, ErrUnexpectedSigningMethod)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestVerifyUnknownClaims(t *testing.T) {
+	key, err := os.ReadFile("testdata/private.key")
+	require.NoError(t, err)
+	verifier, err := NewVerifier(jwtgo.SigningMethodHS256, "testdata/private.key")
+	require.NoError(t, err)
+
+	// The token issued by the newer server with the groups and a claim unknown to this server.
+	now := time.Now()
+	token, err := jwtgo.NewWithClaims(jwtgo.SigningMethodHS256, jwtgo.MapClaims{
+		"iss":     Issuer,
+		"sub":     "user",
+		"iat":     now.Unix(),
+		"nbf":     now.Unix(),
+		"exp":     now.Add(time.Hour).Unix(),
+		"role":    map[string]interface{}{"project_id": "project"},
+		"groups":  []string{"dev"},
+		"unknown": "value",
+	}).SignedString(key)
+	require.NoError(t, err)
+
+	claims, err := verifier.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, "user", claims.Subject)
+	assert.Equal(t, "project", claims.Role.ProjectId)
+	assert.Equal(t, []string{"dev"}, claims.Groups)
+}
+
+func TestNewVerifierInvalidKey(t *testing.T) {
+	testcases := []struct {
+		name    string
+		method  jwtgo.SigningMethod
+		keyFile string
+	}{
+		{name: "RSA key for ES256", method: jwtgo.SigningMethodES256, keyFile: "testdata/public.key"},
+		{name: "P-384 key for ES256", method: jwtgo.SigningMethodES256, keyFile: "testdata/es384_private.key"},
+		{name: "Ed25519 key for ES256", method: jwtgo.SigningMethodES256, keyFile: "testdata/ed25519_public.key"},
+		{name: "EC key for EdDSA", method: jwtgo.SigningMethodEdDSA, keyFile: "testdata/es256_public.key"},
+		{name: "RSA key for EdDSA", method: jwtgo.SigningMethodEdDSA, keyFile: "testdata/private.key"},
+		{name: "EC key for RS256", method: jwtgo.SigningMethodRS256, keyFile: "testdata/es256_private.key"},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewVerifier(tc.method, tc.keyFile)
+			assert.ErrorIs(t, err, ErrInvalidKey)
+			_, err = NewSigner(tc.method, tc.keyFile)
+			assert.ErrorIs(t, err, ErrInvalidKey)
+		})
+	}
+
+	// The public key cannot sign.
+	_, err := NewSigner(jwtgo.SigningMethodES256, "testdata/es256_public.key")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+	_, err = NewSigner(jwtgo.SigningMethodEdDSA, "testdata/ed25519_public.key")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -17,17 +17,39 @@ package model
 import (
 	"context"
 	"crypto/subtle"
+	"errors"
 	"fmt"
+	"net/http"
 	"net/url"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/net/http/httpguts"
 	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/bitbucket"
 	"golang.org/x/oauth2/github"
+	"golang.org/x/oauth2/gitlab"
+	"golang.org/x/oauth2/google"
+	"golang.org/x/oauth2/microsoft"
+	"google.golang.org/protobuf/proto"
 )
 
+// ErrRedirectURINotAllowed is returned when the redirect URI is not in the allowed list.
+var ErrRedirectURINotAllowed = errors.New("redirect_uri not allowed")
+
 var (
-	githubScopes = []string{"read:org"}
+	githubScopes  = []string{"read:org", "user:email"}
+	gitlabScopes  = []string{"read_api"}
+	googleScopes  = []string{oidc.ScopeOpenID, "email", "profile", "https://www.googleapis.com/auth/admin.directory.group.readonly"}
+	azureADScopes = []string{oidc.ScopeOpenID, "email", "profile"}
+	oktaScopes    = []string{oidc.ScopeOpenID, "email", "profile", "groups"}
+	oidcScopes    = []string{oidc.ScopeOpenID, "profile", "email"}
+
+	bitbucketCloudScopes  = []string{"account"}
+	bitbucketServerScopes = []string{"PUBLIC_REPOS"}
 
 	builtinAdminRBACRole = &ProjectRBACRole{
 		Name:      BuiltinRBACRoleAdmin.String(),
@@ -216,43 +238,214 @@ func (p *ProjectStaticUser) Auth(username, password string) error {
 
 // RedactSensitiveData redacts sensitive data.
 func (p *ProjectSSOConfig) RedactSensitiveData() {
-	if p.Github == nil {
-		return
+	if p.Github != nil {
+		p.Github.RedactSensitiveData()
+	}
+	if p.Oidc != nil {
+		p.Oidc.RedactSensitiveData()
+	}
+	if p.Gitlab != nil {
+		p.Gitlab.RedactSensitiveData()
+	}
+	if p.Google != nil {
+		p.Google.RedactSensitiveData()
+	}
+	if p.AzureAd != nil {
+		p.AzureAd.RedactSensitiveData()
+	}
+	if p.Ldap != nil {
+		p.Ldap.RedactSensitiveData()
+	}
+	if p.Saml != nil {
+		p.Saml.RedactSensitiveData()
+	}
+	if p.Okta != nil {
+		p.Okta.RedactSensitiveData()
+	}
+	if p.Bitbucket != nil {
+		p.Bitbucket.RedactSensitiveData()
 	}
-	p.Github.RedactSensitiveData()
 }
 
 // Update updates ProjectSSOConfig with given data.
 func (p *ProjectSSOConfig) Update(sso *ProjectSSOConfig) error {
 	p.Provider = sso.Provider
-	if sso.Github == nil {
-		return nil
+	p.DisplayName = sso.DisplayName
+	p.Icon = sso.Icon
+	if sso.Github != nil {
+		if p.Github == nil {
+			p.Github = &ProjectSSOConfig_GitHub{}
+		}
+		if err := p.Github.Update(sso.Github); err != nil {
+			return err
+		}
 	}
-
-	if p.Github == nil {
-		p.Github = &ProjectSSOConfig_GitHub{}
+	if sso.Oidc != nil {
+		if p.Oidc == nil {
+			p.Oidc = &ProjectSSOConfig_Oidc{}
+		}
+		if err := p.Oidc.Update(sso.Oidc); err != nil {
+			return err
+		}
+	}
+	if sso.Gitlab != nil {
+		if p.Gitlab == nil {
+			p.Gitlab = &ProjectSSOConfig_GitLab{}
+		}
+		if err := p.Gitlab.Update(sso.Gitlab); err != nil {
+			return err
+		}
+	}
+	if sso.Google != nil {
+		if p.Google == nil {
+			p.Google = &ProjectSSOConfig_Google{}
+		}
+		if err := p.Google.Update(sso.Google); err != nil {
+			return err
+		}
+	}
+	if sso.AzureAd != nil {
+		if p.AzureAd == nil {
+			p.AzureAd = &ProjectSSOConfig_AzureAD{}
+		}
+		if err := p.AzureAd.Update(sso.AzureAd); err != nil {
+			return err
+		}
+	}
+	if sso.Ldap != nil {
+		if p.Ldap == nil {
+			p.Ldap = &ProjectSSOConfig_Ldap{}
+		}
+		if err := p.Ldap.Update(sso.Ldap); err != nil {
+			return err
+		}
+	}
+	if sso.Saml != nil {
+		if p.Saml == nil {
+			p.Saml = &ProjectSSOConfig_Saml{}
+		}
+		if err := p.Saml.Update(sso.Saml); err != nil {
+			return err
+		}
+	}
+	if sso.Okta != nil {
+		if p.Okta == nil {
+			p.Okta = &ProjectSSOConfig_Okta{}
+		}
+		if err := p.Okta.Update(sso.Okta); err != nil {
+			return err
+		}
 	}
-	return p.Github.Update(sso.Github)
+	if sso.Bitbucket != nil {
+		if p.Bitbucket == nil {
+			p.Bitbucket = &ProjectSSOConfig_Bitbucket{}
+		}
+		if err := p.Bitbucket.Update(sso.Bitbucket); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // Encrypt encrypts sensitive data in ProjectSSOConfig.
 func (p *ProjectSSOConfig) Encrypt(encrypter encrypter) error {
-	if p.Github == nil {
-		return nil
+	if p.Github != nil {
+		if err := p.Github.Encrypt(encrypter); err != nil {
+			return err
+		}
 	}
-	return p.Github.Encrypt(encrypter)
+	if p.Oidc != nil {
+		if err := p.Oidc.Encrypt(encrypter); err != nil {
+			return err
+		}
+	}
+	if p.Gitlab != nil {
+		if err := p.Gitlab.Encrypt(encrypter); err != nil {
+			return err
+		}
+	}
+	if p.Google != nil {
+		if err := p.Google.Encrypt(encrypter); err != nil {
+			return err
+		}
+	}
+	if p.AzureAd != nil {
+		if err := p.AzureAd.Encrypt(encrypter); err != nil {
+			return err
+		}
+	}
+	if p.Ldap != nil {
+		if err := p.Ldap.Encrypt(encrypter); err != nil {
+			return err
+		}
+	}
+	if p.Saml != nil {
+		if err := p.Saml.Encrypt(encrypter); err != nil {
+			return err
+		}
+	}
+	if p.Okta != nil {
+		if err := p.Okta.Encrypt(encrypter); err != nil {
+			return err
+		}
+	}
+	if p.Bitbucket != nil {
+		return p.Bitbucket.Encrypt(encrypter)
+	}
+	return nil
 }
 
 // Decrypt decrypts encrypted data in ProjectSSOConfig.
 func (p *ProjectSSOConfig) Decrypt(decrypter decrypter) error {
-	if p.Github == nil {
-		return nil
+	if p.Github != nil {
+		if err := p.Github.Decrypt(decrypter); err != nil {
+			return err
+		}
 	}
-	return p.Github.Decrypt(decrypter)
+	if p.Oidc != nil {
+		if err := p.Oidc.Decrypt(decrypter); err != nil {
+			return err
+		}
+	}
+	if p.Gitlab != nil {
+		if err := p.Gitlab.Decrypt(decrypter); err != nil {
+			return err
+		}
+	}
+	if p.Google != nil {
+		if err := p.Google.Decrypt(decrypter); err != nil {
+			return err
+		}
+	}
+	if p.AzureAd != nil {
+		if err := p.AzureAd.Decrypt(decrypter); err != nil {
+			return err
+		}
+	}
+	if p.Ldap != nil {
+		if err := p.Ldap.Decrypt(decrypter); err != nil {
+			return err
+		}
+	}
+	if p.Saml != nil {
+		if err := p.Saml.Decrypt(decrypter); err != nil {
+			return err
+		}
+	}
+	if p.Okta != nil {
+		if err := p.Okta.Decrypt(decrypter); err != nil {
+			return err
+		}
+	}
+	if p.Bitbucket != nil {
+		return p.Bitbucket.Decrypt(decrypter)
+	}
+	return nil
 }
 
 // GenerateAuthCodeURL generates an auth URL for the specified configuration.
-func (p *ProjectSSOConfig) GenerateAuthCodeURL(project, callbackURL, state string) (string, error) {
+// The given options are only applied to the OIDC provider.
+func (p *ProjectSSOConfig) GenerateAuthCodeURL(project, callbackURL, state string, opts ...oauth2.AuthCodeOption) (string, error) {
 	switch p.Provider {
 	case ProjectSSOConfig_GITHUB:
 		if p.Github == nil {
@@ -263,17 +456,185 @@ func (p *ProjectSSOConfig) GenerateAuthCodeURL(project, callbackURL, state strin
 		if p.Oidc == nil {
 			return "", fmt.Errorf("missing OIDC oauth in the SSO configuration")
 		}
-		return p.Oidc.GenerateAuthCodeURL(project, state)
+		return p.Oidc.GenerateAuthCodeURL(project, state, opts...)
+	case ProjectSSOConfig_GITLAB:
+		if p.Gitlab == nil {
+			return "", fmt.Errorf("missing GitLab oauth in the SSO configuration")
+		}
+		return p.Gitlab.GenerateAuthCodeURL(project, state)
+	case ProjectSSOConfig_GOOGLE:
+		if p.Google == nil {
+			return "", fmt.Errorf("missing Google oauth in the SSO configuration")
+		}
+		return p.Google.GenerateAuthCodeURL(project, state, opts...)
+	case ProjectSSOConfig_AZUREAD:
+		if p.AzureAd == nil {
+			return "", fmt.Errorf("missing Azure AD oauth in the SSO configuration")
+		}
+		return p.AzureAd.GenerateAuthCodeURL(project, state, opts...)
+	case ProjectSSOConfig_LDAP:
+		return "", fmt.Errorf("LDAP does not use the authorization code flow, log in with the username and password instead")
+	case ProjectSSOConfig_OKTA:
+		if p.Okta == nil {
+			return "", fmt.Errorf("missing Okta oauth in the SSO configuration")
+		}
+		return p.Okta.GenerateAuthCodeURL(project, state, opts...)
+	case ProjectSSOConfig_BITBUCKET:
+		if p.Bitbucket == nil {
+			return "", fmt.Errorf("missing Bitbucket oauth in the SSO configuration")
+		}
+		return p.Bitbucket.GenerateAuthCodeURL(project, state)
+	case ProjectSSOConfig_SAML:
+		return "", fmt.Errorf("SAML does not use the authorization code flow, send the authentication request to the identity provider instead")
 
 	default:
 		return "", fmt.Errorf("not implemented")
 	}
 }
 
+// OfflineAccessOptions returns the options of the authorization request to issue the refresh token to the user,
+// which is used to look up the user at the provider after login. GitHub, GitLab and Bitbucket need no option
+// since their tokens can be used or refreshed without it.
+func (p *ProjectSSOConfig) OfflineAccessOptions() []oauth2.AuthCodeOption {
+	switch p.Provider {
+	case ProjectSSOConfig_GOOGLE:
+		return []oauth2.AuthCodeOption{oauth2.AccessTypeOffline}
+	case ProjectSSOConfig_AZUREAD:
+		return scopesWithOfflineAccess(azureADScopes)
+	case ProjectSSOConfig_OKTA:
+		if p.Okta != nil {
+			return scopesWithOfflineAccess(p.Okta.AllScopes())
+		}
+	case ProjectSSOConfig_OIDC:
+		if p.Oidc != nil {
+			return scopesWithOfflineAccess(p.Oidc.ScopesOrDefault())
+		}
+	}
+	return nil
+}
+
+// scopesWithOfflineAccess returns the option requesting the given scopes along with offline_access,
+// which replaces the scopes of the authorization request.
+func scopesWithOfflineAccess(scopes []string) []oauth2.AuthCodeOption {
+	if !slices.Contains(scopes, oidc.ScopeOfflineAccess) {
+		scopes = append(slices.Clone(scopes), oidc.ScopeOfflineAccess)
+	}
+	return []oauth2.AuthCodeOption{oauth2.SetAuthURLParam("scope", strings.Join(scopes, " "))}
+}
+
+// reservedExtraHeaders are the headers which cannot be configured as the extra headers,
+// since they are set by the HTTP client or change how the request is framed and forwarded.
+var reservedExtraHeaders = []string{
+	"Authorization",
+	"Connection",
+	"Content-Length",
+	"Content-Type",
+	"Cookie",
+	"Host",
+	"Keep-Alive",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// ValidateExtraHeaders checks that each of the given extra headers is a valid HTTP header field
+// which does not override the reserved headers, so that no header can smuggle another request.
+func ValidateExtraHeaders(headers map[string]string) error {
+	for name, value := range headers {
+		if !httpguts.ValidHeaderFieldName(name) {
+			return fmt.Errorf("invalid extra header name %q", name)
+		}
+		if slices.Contains(reservedExtraHeaders, http.CanonicalHeaderKey(name)) {
+			return fmt.Errorf("extra header %s is not allowed", name)
+		}
+		if !httpguts.ValidHeaderFieldValue(value) {
+			return fmt.Errorf("invalid value of extra header %s", name)
+		}
+	}
+	return nil
+}
+
+func redactExtraHeaders(headers map[string]string) {
+	for name := range headers {
+		headers[name] = redactedMessage
+	}
+}
+
+func encryptExtraHeaders(headers map[string]string, encrypter encrypter) error {
+	for name, value := range headers {
+		if value == "" {
+			continue
+		}
+		encryptedValue, err := encrypter.Encrypt(value)
+		if err != nil {
+			return err
+		}
+		headers[name] = encryptedValue
+	}
+	return nil
+}
+
+func decryptExtraHeaders(headers map[string]string, decrypter decrypter) error {
+	for name, value := range headers {
+		if value == "" {
+			continue
+		}
+		decryptedValue, err := decrypter.Decrypt(value)
+		if err != nil {
+			return err
+		}
+		headers[name] = decryptedValue
+	}
+	return nil
+}
+
+func redactValues(values ...*string) {
+	for _, v := range values {
+		if *v != "" {
+			*v = redactedMessage
+		}
+	}
+}
+
+func encryptValues(encrypter encrypter, values ...*string) error {
+	for _, v := range values {
+		if *v == "" {
+			continue
+		}
+		encryptedValue, err := encrypter.Encrypt(*v)
+		if err != nil {
+			return err
+		}
+		*v = encryptedValue
+	}
+	return nil
+}
+
+func decryptValues(decrypter decrypter, values ...*string) error {
+	for _, v := range values {
+		if *v == "" {
+			continue
+		}
+		decryptedValue, err := decrypter.Decrypt(*v)
+		if err != nil {
+			return err
+		}
+		*v = decryptedValue
+	}
+	return nil
+}
+
 // RedactSensitiveData redacts sensitive data.
 func (p *ProjectSSOConfig_GitHub) RedactSensitiveData() {
 	p.ClientId = redactedMessage
 	p.ClientSecret = redactedMessage
+	if p.AppPrivateKey != "" {
+		p.AppPrivateKey = redactedMessage
+	}
+	redactExtraHeaders(p.ExtraHeaders)
 }
 
 // Update updates ProjectSSOConfig with given data.
@@ -290,6 +651,24 @@ func (p *ProjectSSOConfig_GitHub) Update(input *ProjectSSOConfig_GitHub) error {
 	if input.UploadUrl != "" {
 		p.UploadUrl = input.UploadUrl
 	}
+	if input.ApiUrl != "" {
+		p.ApiUrl = input.ApiUrl
+	}
+	if input.AppId != 0 {
+		p.AppId = input.AppId
+	}
+	if input.AppInstallationId != 0 {
+		p.AppInstallationId = input.AppInstallationId
+	}
+	if input.AppPrivateKey != "" {
+		p.AppPrivateKey = input.AppPrivateKey
+	}
+	if len(input.ExtraHeaders) > 0 {
+		p.ExtraHeaders = input.ExtraHeaders
+	}
+	if len(input.RequiredOrgs) > 0 {
+		p.RequiredOrgs = input.RequiredOrgs
+	}
 	return nil
 }
 
@@ -309,7 +688,14 @@ func (p *ProjectSSOConfig_GitHub) Encrypt(encrypter encrypter) error {
 		}
 		p.ClientSecret = encryptedClientSecret
 	}
-	return nil
+	if p.AppPrivateKey != "" {
+		encryptedAppPrivateKey, err := encrypter.Encrypt(p.AppPrivateKey)
+		if err != nil {
+			return err
+		}
+		p.AppPrivateKey = encryptedAppPrivateKey
+	}
+	return encryptExtraHeaders(p.ExtraHeaders, encrypter)
 }
 
 // Decrypt decrypts ProjectSSOConfig.
@@ -328,53 +714,616 @@ func (p *ProjectSSOConfig_GitHub) Decrypt(decrypter decrypter) error {
 		}
 		p.ClientSecret = decryptedClientSecret
 	}
+	if p.AppPrivateKey != "" {
+		decryptedAppPrivateKey, err := decrypter.Decrypt(p.AppPrivateKey)
+		if err != nil {
+			return err
+		}
+		p.AppPrivateKey = decryptedAppPrivateKey
+	}
+	return decryptExtraHeaders(p.ExtraHeaders, decrypter)
+}
+
+// RedirectURI returns the redirect URI used for the given project.
+func (p *ProjectSSOConfig_GitHub) RedirectURI(project, callbackURL string) string {
+	return fmt.Sprintf("%s?project=%s", callbackURL, project)
+}
+
+// ValidateURLs checks that the configured addresses of GitHub Enterprise Server are well-formed HTTPS URLs.
+func (p *ProjectSSOConfig_GitHub) ValidateURLs() error {
+	if p.ApiUrl != "" && p.BaseUrl == "" {
+		return fmt.Errorf("base_url is required when api_url is set")
+	}
+	for name, v := range map[string]string{"base_url": p.BaseUrl, "api_url": p.ApiUrl, "upload_url": p.UploadUrl} {
+		if v == "" {
+			continue
+		}
+		u, err := url.Parse(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", name, err)
+		}
+		if u.Scheme != "https" || u.Host == "" {
+			return fmt.Errorf("%s must be an absolute HTTPS URL: %s", name, v)
+		}
+	}
+	return nil
+}
+
+// AppEnabled reports whether the GitHub App is configured to look up the organizations and teams of the user.
+func (p *ProjectSSOConfig_GitHub) AppEnabled() bool {
+	return p.AppId != 0
+}
+
+// ValidateApp checks that the GitHub App is fully configured if enabled.
+func (p *ProjectSSOConfig_GitHub) ValidateApp() error {
+	if !p.AppEnabled() {
+		return nil
+	}
+	if p.AppId < 0 {
+		return fmt.Errorf("app_id must be positive")
+	}
+	if p.AppInstallationId <= 0 {
+		return fmt.Errorf("app_installation_id is required when app_id is set")
+	}
+	if p.AppPrivateKey == "" {
+		return fmt.Errorf("app_private_key is required when app_id is set")
+	}
 	return nil
 }
 
+// ValidateExtraHeaders checks that the configured extra headers can be attached to the requests to GitHub.
+func (p *ProjectSSOConfig_GitHub) ValidateExtraHeaders() error {
+	return ValidateExtraHeaders(p.ExtraHeaders)
+}
+
+// Endpoint returns the OAuth endpoint of github.com, or of GitHub Enterprise Server if base_url is set.
+func (p *ProjectSSOConfig_GitHub) Endpoint() (oauth2.Endpoint, error) {
+	if p.BaseUrl == "" {
+		return github.Endpoint, nil
+	}
+	u, err := url.Parse(p.BaseUrl)
+	if err != nil {
+		return oauth2.Endpoint{}, err
+	}
+	return oauth2.Endpoint{
+		AuthURL:  fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, "/login/oauth/authorize"),
+		TokenURL: fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, "/login/oauth/access_token"),
+	}, nil
+}
+
+// APIURL returns the address of the REST API of GitHub Enterprise Server, empty for github.com.
+func (p *ProjectSSOConfig_GitHub) APIURL() string {
+	if p.ApiUrl != "" {
+		return p.ApiUrl
+	}
+	return p.BaseUrl
+}
+
 // GenerateAuthCodeURL generates an auth URL for the specified configuration.
 func (p *ProjectSSOConfig_GitHub) GenerateAuthCodeURL(project, callbackURL, state string) (string, error) {
+	if err := p.ValidateURLs(); err != nil {
+		return "", err
+	}
+	endpoint, err := p.Endpoint()
+	if err != nil {
+		return "", err
+	}
 	cfg := oauth2.Config{
 		ClientID: p.ClientId,
-		Endpoint: github.Endpoint,
+		Endpoint: endpoint,
+	}
+	cfg.Scopes = githubScopes
+	cfg.RedirectURL = p.RedirectURI(project, callbackURL)
+	authURL := cfg.AuthCodeURL(state, oauth2.ApprovalForce, oauth2.AccessTypeOnline)
+
+	return authURL, nil
+}
+
+// RedactSensitiveData redacts sensitive data.
+func (p *ProjectSSOConfig_GitLab) RedactSensitiveData() {
+	redactValues(&p.ClientId, &p.ClientSecret)
+}
+
+// Update updates ProjectSSOConfig_GitLab with given data.
+// The client id and secret are left as is unless they are given.
+func (p *ProjectSSOConfig_GitLab) Update(input *ProjectSSOConfig_GitLab) error {
+	clientID, clientSecret := p.ClientId, p.ClientSecret
+	proto.Reset(p)
+	proto.Merge(p, input)
+	if p.ClientId == "" {
+		p.ClientId = clientID
+	}
+	if p.ClientSecret == "" {
+		p.ClientSecret = clientSecret
+	}
+	return nil
+}
+
+// Encrypt encrypts the client id and secret.
+func (p *ProjectSSOConfig_GitLab) Encrypt(encrypter encrypter) error {
+	return encryptValues(encrypter, &p.ClientId, &p.ClientSecret)
+}
+
+// Decrypt decrypts the client id and secret.
+func (p *ProjectSSOConfig_GitLab) Decrypt(decrypter decrypter) error {
+	return decryptValues(decrypter, &p.ClientId, &p.ClientSecret)
+}
+
+// GenerateAuthCodeURL generates an auth URL for the specified configuration.
+func (p *ProjectSSOConfig_GitLab) GenerateAuthCodeURL(project, state string) (string, error) {
+	cfg := oauth2.Config{
+		ClientID:    p.ClientId,
+		Endpoint:    gitlab.Endpoint,
+		Scopes:      gitlabScopes,
+		RedirectURL: p.RedirectUri,
 	}
 	if p.BaseUrl != "" {
 		u, err := url.Parse(p.BaseUrl)
 		if err != nil {
 			return "", err
 		}
-		cfg.Endpoint.AuthURL = fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, "/login/oauth/authorize")
+		cfg.Endpoint.AuthURL = fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, "/oauth/authorize")
 	}
-	cfg.Scopes = githubScopes
-	cfg.RedirectURL = fmt.Sprintf("%s?project=%s", callbackURL, project)
+
+	// GitLab requires the redirect uri to exactly match the registered one,
+	// so the project ID is passed through the state like OIDC.
+	state = StateWithProject(state, project)
 	authURL := cfg.AuthCodeURL(state, oauth2.ApprovalForce, oauth2.AccessTypeOnline)
 
 	return authURL, nil
 }
 
+// RedactSensitiveData redacts sensitive data.
+func (p *ProjectSSOConfig_Google) RedactSensitiveData() {
+	redactValues(&p.ClientId, &p.ClientSecret)
+}
+
+// Update updates ProjectSSOConfig_Google with given data.
+// The client id and secret are left as is unless they are given.
+func (p *ProjectSSOConfig_Google) Update(input *ProjectSSOConfig_Google) error {
+	clientID, clientSecret := p.ClientId, p.ClientSecret
+	proto.Reset(p)
+	proto.Merge(p, input)
+	if p.ClientId == "" {
+		p.ClientId = clientID
+	}
+	if p.ClientSecret == "" {
+		p.ClientSecret = clientSecret
+	}
+	return nil
+}
+
+// Encrypt encrypts the client id and secret.
+func (p *ProjectSSOConfig_Google) Encrypt(encrypter encrypter) error {
+	return encryptValues(encrypter, &p.ClientId, &p.ClientSecret)
+}
+
+// Decrypt decrypts the client id and secret.
+func (p *ProjectSSOConfig_Google) Decrypt(decrypter decrypter) error {
+	return decryptValues(decrypter, &p.ClientId, &p.ClientSecret)
+}
+
+// GenerateAuthCodeURL generates an auth URL for the specified configuration.
+func (p *ProjectSSOConfig_Google) GenerateAuthCodeURL(project, state string, opts ...oauth2.AuthCodeOption) (string, error) {
+	cfg := oauth2.Config{
+		ClientID:    p.ClientId,
+		Endpoint:    google.Endpoint,
+		Scopes:      googleScopes,
+		RedirectURL: p.RedirectUri,
+	}
+	authOpts := []oauth2.AuthCodeOption{oauth2.ApprovalForce, oauth2.AccessTypeOnline}
+	// Google only accepts a single hosted domain hint,
+	// the actual restriction is done while verifying the ID token.
+	if len(p.AllowedDomains) == 1 {
+		authOpts = append(authOpts, oauth2.SetAuthURLParam("hd", p.AllowedDomains[0]))
+	}
+
+	state = StateWithProject(state, project)
+	authURL := cfg.AuthCodeURL(state, append(authOpts, opts...)...)
+
+	return authURL, nil
+}
+
+// ProjectStateMarker separates the state token and the project ID in the state
+// sent to the providers whose redirect URI cannot carry the project ID.
+const ProjectStateMarker = ":project="
+
+// StateWithProject returns the state carrying the given project ID,
+// in the format of "<state>:project=<project-id>".
+// The state is returned as is if the project ID is empty, e.g. the state already carries it.
+func StateWithProject(state, project string) string {
+	if project == "" {
+		return state
+	}
+	return state + ProjectStateMarker + project
+}
+
+// defaultAzureADTenant is the tenant allowing users of any organization to log in.
+const defaultAzureADTenant = "organizations"
+
+// RedactSensitiveData redacts sensitive data.
+func (p *ProjectSSOConfig_AzureAD) RedactSensitiveData() {
+	redactValues(&p.ClientId, &p.ClientSecret)
+}
+
+// Update updates ProjectSSOConfig_AzureAD with given data.
+// The client id and secret are left as is unless they are given.
+func (p *ProjectSSOConfig_AzureAD) Update(input *ProjectSSOConfig_AzureAD) error {
+	clientID, clientSecret := p.ClientId, p.ClientSecret
+	proto.Reset(p)
+	proto.Merge(p, input)
+	if p.ClientId == "" {
+		p.ClientId = clientID
+	}
+	if p.ClientSecret == "" {
+		p.ClientSecret = clientSecret
+	}
+	return nil
+}
+
+// Encrypt encrypts the client id and secret.
+func (p *ProjectSSOConfig_AzureAD) Encrypt(encrypter encrypter) error {
+	return encryptValues(encrypter, &p.ClientId, &p.ClientSecret)
+}
+
+// Decrypt decrypts the client id and secret.
+func (p *ProjectSSOConfig_AzureAD) Decrypt(decrypter decrypter) error {
+	return decryptValues(decrypter, &p.ClientId, &p.ClientSecret)
+}
+
+// TenantOrDefault returns the tenant used to log in.
+func (p *ProjectSSOConfig_AzureAD) TenantOrDefault() string {
+	if p.Tenant == "" {
+		return defaultAzureADTenant
+	}
+	return p.Tenant
+}
+
+// GenerateAuthCodeURL generates an auth URL for the specified configuration.
+func (p *ProjectSSOConfig_AzureAD) GenerateAuthCodeURL(project, state string, opts ...oauth2.AuthCodeOption) (string, error) {
+	cfg := oauth2.Config{
+		ClientID:    p.ClientId,
+		Endpoint:    microsoft.AzureADEndpoint(p.TenantOrDefault()),
+		Scopes:      azureADScopes,
+		RedirectURL: p.RedirectUri,
+	}
+
+	state = StateWithProject(state, project)
+	authURL := cfg.AuthCodeURL(state, append([]oauth2.AuthCodeOption{oauth2.AccessTypeOnline}, opts...)...)
+
+	return authURL, nil
+}
+
+// RedactSensitiveData redacts sensitive data.
+func (p *ProjectSSOConfig_Okta) RedactSensitiveData() {
+	redactValues(&p.ClientId, &p.ClientSecret)
+}
+
+// Update updates ProjectSSOConfig_Okta with given data.
+// The client id and secret are left as is unless they are given.
+func (p *ProjectSSOConfig_Okta) Update(input *ProjectSSOConfig_Okta) error {
+	clientID, clientSecret := p.ClientId, p.ClientSecret
+	proto.Reset(p)
+	proto.Merge(p, input)
+	if p.ClientId == "" {
+		p.ClientId = clientID
+	}
+	if p.ClientSecret == "" {
+		p.ClientSecret = clientSecret
+	}
+	return nil
+}
+
+// Encrypt encrypts the client id and secret.
+func (p *ProjectSSOConfig_Okta) Encrypt(encrypter encrypter) error {
+	return encryptValues(encrypter, &p.ClientId, &p.ClientSecret)
+}
+
+// Decrypt decrypts the client id and secret.
+func (p *ProjectSSOConfig_Okta) Decrypt(decrypter decrypter) error {
+	return decryptValues(decrypter, &p.ClientId, &p.ClientSecret)
+}
+
+// Issuer returns the issuer of the tokens issued by the configured authorization server.
+func (p *ProjectSSOConfig_Okta) Issuer() string {
+	domain := strings.TrimSuffix(p.Domain, "/")
+	if p.AuthorizationServerId == "" {
+		return domain
+	}
+	return domain + "/oauth2/" + p.AuthorizationServerId
+}
+
+// Endpoint returns the endpoint of the configured authorization server.
+func (p *ProjectSSOConfig_Okta) Endpoint() oauth2.Endpoint {
+	base := p.Issuer() + "/v1"
+	if p.AuthorizationServerId == "" {
+		// The endpoints of the org authorization server are under /oauth2 unlike its issuer.
+		base = strings.TrimSuffix(p.Domain, "/") + "/oauth2/v1"
+	}
+	return oauth2.Endpoint{
+		AuthURL:  base + "/authorize",
+		TokenURL: base + "/token",
+	}
+}
+
+// AllScopes returns the scopes to request including the additional ones.
+func (p *ProjectSSOConfig_Okta) AllScopes() []string {
+	scopes := slices.Clone(oktaScopes)
+	for _, s := range p.Scopes {
+		if !slices.Contains(scopes, s) {
+			scopes = append(scopes, s)
+		}
+	}
+	return scopes
+}
+
+// GenerateAuthCodeURL generates an auth URL for the specified configuration.
+func (p *ProjectSSOConfig_Okta) GenerateAuthCodeURL(project, state string, opts ...oauth2.AuthCodeOption) (string, error) {
+	cfg := oauth2.Config{
+		ClientID:    p.ClientId,
+		Endpoint:    p.Endpoint(),
+		Scopes:      p.AllScopes(),
+		RedirectURL: p.RedirectUri,
+	}
+
+	state = StateWithProject(state, project)
+	authURL := cfg.AuthCodeURL(state, append([]oauth2.AuthCodeOption{oauth2.AccessTypeOnline}, opts...)...)
+
+	return authURL, nil
+}
+
+// RedactSensitiveData redacts sensitive data.
+func (p *ProjectSSOConfig_Bitbucket) RedactSensitiveData() {
+	redactValues(&p.ClientId, &p.ClientSecret)
+}
+
+// Update updates ProjectSSOConfig_Bitbucket with given data.
+// The client id and secret are left as is unless they are given.
+func (p *ProjectSSOConfig_Bitbucket) Update(input *ProjectSSOConfig_Bitbucket) error {
+	clientID, clientSecret := p.ClientId, p.ClientSecret
+	proto.Reset(p)
+	proto.Merge(p, input)
+	if p.ClientId == "" {
+		p.ClientId = clientID
+	}
+	if p.ClientSecret == "" {
+		p.ClientSecret = clientSecret
+	}
+	return nil
+}
+
+// Encrypt encrypts the client id and secret.
+func (p *ProjectSSOConfig_Bitbucket) Encrypt(encrypter encrypter) error {
+	return encryptValues(encrypter, &p.ClientId, &p.ClientSecret)
+}
+
+// Decrypt decrypts the client id and secret.
+func (p *ProjectSSOConfig_Bitbucket) Decrypt(decrypter decrypter) error {
+	return decryptValues(decrypter, &p.ClientId, &p.ClientSecret)
+}
+
+// BaseURL returns the address of the configured Bitbucket service.
+func (p *ProjectSSOConfig_Bitbucket) BaseURL() (string, error) {
+	if p.Flavor == ProjectSSOConfig_Bitbucket_CLOUD && p.BaseUrl == "" {
+		return "https://bitbucket.org", nil
+	}
+	if p.BaseUrl == "" {
+		return "", fmt.Errorf("base_url is required for Bitbucket Server")
+	}
+	u, err := url.Parse(p.BaseUrl)
+	if err != nil {
+		return "", err
+	}
+	// Bitbucket Server can be served under a context path.
+	return fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, strings.TrimSuffix(u.Path, "/")), nil
+}
+
+// Endpoint returns the endpoint of the configured Bitbucket service.
+func (p *ProjectSSOConfig_Bitbucket) Endpoint() (oauth2.Endpoint, error) {
+	if p.Flavor == ProjectSSOConfig_Bitbucket_CLOUD && p.BaseUrl == "" {
+		return bitbucket.Endpoint, nil
+	}
+	base, err := p.BaseURL()
+	if err != nil {
+		return oauth2.Endpoint{}, err
+	}
+	if p.Flavor == ProjectSSOConfig_Bitbucket_CLOUD {
+		return oauth2.Endpoint{
+			AuthURL:  base + "/site/oauth2/authorize",
+			TokenURL: base + "/site/oauth2/access_token",
+		}, nil
+	}
+	return oauth2.Endpoint{
+		AuthURL:  base + "/rest/oauth2/latest/authorize",
+		TokenURL: base + "/rest/oauth2/latest/token",
+	}, nil
+}
+
+// AllScopes returns the configured scopes or the default ones of the flavor.
+func (p *ProjectSSOConfig_Bitbucket) AllScopes() []string {
+	if len(p.Scopes) != 0 {
+		return p.Scopes
+	}
+	if p.Flavor == ProjectSSOConfig_Bitbucket_SERVER {
+		return bitbucketServerScopes
+	}
+	return bitbucketCloudScopes
+}
+
+// GenerateAuthCodeURL generates an auth URL for the specified configuration.
+func (p *ProjectSSOConfig_Bitbucket) GenerateAuthCodeURL(project, state string) (string, error) {
+	endpoint, err := p.Endpoint()
+	if err != nil {
+		return "", err
+	}
+	cfg := oauth2.Config{
+		ClientID:    p.ClientId,
+		Endpoint:    endpoint,
+		Scopes:      p.AllScopes(),
+		RedirectURL: p.RedirectUri,
+	}
+
+	state = StateWithProject(state, project)
+	authURL := cfg.AuthCodeURL(state, oauth2.AccessTypeOnline)
+
+	return authURL, nil
+}
+
+// RedactSensitiveData redacts sensitive data.
+func (p *ProjectSSOConfig_Ldap) RedactSensitiveData() {
+	redactValues(&p.BindPassword)
+}
+
+// Update updates ProjectSSOConfig_Ldap with given data.
+// The bind password is left as is unless it is given.
+func (p *ProjectSSOConfig_Ldap) Update(input *ProjectSSOConfig_Ldap) error {
+	bindPassword := p.BindPassword
+	proto.Reset(p)
+	proto.Merge(p, input)
+	if p.BindPassword == "" {
+		p.BindPassword = bindPassword
+	}
+	return nil
+}
+
+// Encrypt encrypts the bind password.
+func (p *ProjectSSOConfig_Ldap) Encrypt(encrypter encrypter) error {
+	return encryptValues(encrypter, &p.BindPassword)
+}
+
+// Decrypt decrypts the bind password.
+func (p *ProjectSSOConfig_Ldap) Decrypt(decrypter decrypter) error {
+	return decryptValues(decrypter, &p.BindPassword)
+}
+
+// RedactSensitiveData redacts sensitive data.
+func (p *ProjectSSOConfig_Saml) RedactSensitiveData() {
+	redactValues(&p.PrivateKey)
+}
+
+// Update updates ProjectSSOConfig_Saml with given data.
+// The private key is left as is unless it is given.
+func (p *ProjectSSOConfig_Saml) Update(input *ProjectSSOConfig_Saml) error {
+	privateKey := p.PrivateKey
+	proto.Reset(p)
+	proto.Merge(p, input)
+	if p.PrivateKey == "" {
+		p.PrivateKey = privateKey
+	}
+	return nil
+}
+
+// Encrypt encrypts the private key.
+func (p *ProjectSSOConfig_Saml) Encrypt(encrypter encrypter) error {
+	return encryptValues(encrypter, &p.PrivateKey)
+}
+
+// Decrypt decrypts the private key.
+func (p *ProjectSSOConfig_Saml) Decrypt(decrypter decrypter) error {
+	return decryptValues(decrypter, &p.PrivateKey)
+}
+
+// RedactSensitiveData redacts sensitive data.
+func (p *ProjectSSOConfig_Oidc) RedactSensitiveData() {
+	redactValues(&p.ClientSecret, &p.ClientKey, &p.ClientAssertionKey)
+	redactExtraHeaders(p.ExtraHeaders)
+}
+
+// Update updates ProjectSSOConfig_Oidc with given data.
+// The client secret, the private keys and the extra headers are left as is unless they are given.
+func (p *ProjectSSOConfig_Oidc) Update(input *ProjectSSOConfig_Oidc) error {
+	clientSecret, clientKey, clientAssertionKey, extraHeaders := p.ClientSecret, p.ClientKey, p.ClientAssertionKey, p.ExtraHeaders
+	proto.Reset(p)
+	proto.Merge(p, input)
+	if p.ClientSecret == "" {
+		p.ClientSecret = clientSecret
+	}
+	if p.ClientKey == "" {
+		p.ClientKey = clientKey
+	}
+	if p.ClientAssertionKey == "" {
+		p.ClientAssertionKey = clientAssertionKey
+	}
+	if len(p.ExtraHeaders) == 0 {
+		p.ExtraHeaders = extraHeaders
+	}
+	return nil
+}
+
+// Encrypt encrypts the client secret, the private keys of the client certificate and the client assertion, and the values of the extra headers.
+func (p *ProjectSSOConfig_Oidc) Encrypt(encrypter encrypter) error {
+	if err := encryptValues(encrypter, &p.ClientSecret, &p.ClientKey, &p.ClientAssertionKey); err != nil {
+		return err
+	}
+	return encryptExtraHeaders(p.ExtraHeaders, encrypter)
+}
+
+// Decrypt decrypts the client secret, the private keys of the client certificate and the client assertion, and the values of the extra headers.
+func (p *ProjectSSOConfig_Oidc) Decrypt(decrypter decrypter) error {
+	if err := decryptValues(decrypter, &p.ClientSecret, &p.ClientKey, &p.ClientAssertionKey); err != nil {
+		return err
+	}
+	return decryptExtraHeaders(p.ExtraHeaders, decrypter)
+}
+
+// ScopesOrDefault returns the configured scopes, or openid, profile and email if not configured.
+func (p *ProjectSSOConfig_Oidc) ScopesOrDefault() []string {
+	if len(p.Scopes) == 0 {
+		return slices.Clone(oidcScopes)
+	}
+	return slices.Clone(p.Scopes)
+}
+
+// ValidateScopes checks that the configured scopes include openid, which is required to get the ID token.
+func (p *ProjectSSOConfig_Oidc) ValidateScopes() error {
+	if len(p.Scopes) > 0 && !slices.Contains(p.Scopes, oidc.ScopeOpenID) {
+		return fmt.Errorf("scopes must include %s", oidc.ScopeOpenID)
+	}
+	return nil
+}
+
+// IsPublicClient reports whether the client has no credential to authenticate to the token endpoint,
+// i.e. none of the client secret, the client certificate and the client assertion key is configured.
+func (p *ProjectSSOConfig_Oidc) IsPublicClient() bool {
+	return p.ClientSecret == "" && p.ClientCertificate == "" && p.ClientAssertionKey == ""
+}
+
+// ValidateClientAuthentication checks that the client either has a credential to authenticate to the token endpoint,
+// or is a public client using PKCE, which protects the auth code instead of the client secret.
+func (p *ProjectSSOConfig_Oidc) ValidateClientAuthentication() error {
+	if p.IsPublicClient() && !p.PkceEnabled {
+		return fmt.Errorf("client_secret is required unless pkce_enabled, client_certificate or client_assertion_key is set")
+	}
+	return nil
+}
+
+// ValidateExtraHeaders checks that the configured extra headers can be attached to the requests to the identity provider.
+func (p *ProjectSSOConfig_Oidc) ValidateExtraHeaders() error {
+	return ValidateExtraHeaders(p.ExtraHeaders)
+}
+
 // GenerateAuthCodeURL generates an auth URL for the specified configuration.
-func (p *ProjectSSOConfig_Oidc) GenerateAuthCodeURL(project, state string) (string, error) {
+func (p *ProjectSSOConfig_Oidc) GenerateAuthCodeURL(project, state string, opts ...oauth2.AuthCodeOption) (string, error) {
 	ctx := context.Background()
 	provider, err := oidc.NewProvider(ctx, p.Issuer)
 	if err != nil {
 		return "", err
 	}
 
-	scopes := []string{}
-	if len(p.Scopes) == 0 {
-		scopes = append(scopes, oidc.ScopeOpenID)
-	} else {
-		scopes = p.Scopes
+	if err := p.ValidateScopes(); err != nil {
+		return "", err
 	}
 
 	cfg := oauth2.Config{
 		ClientID:    p.ClientId,
 		Endpoint:    provider.Endpoint(),
-		Scopes:      scopes,
+		Scopes:      p.ScopesOrDefault(),
 		RedirectURL: p.RedirectUri,
 	}
 
-	state = fmt.Sprintf("%s:%s", state, project)
-	authURL := cfg.AuthCodeURL(state, oauth2.ApprovalForce, oauth2.AccessTypeOnline)
+	state = StateWithProject(state, project)
+	opts = append([]oauth2.AuthCodeOption{oauth2.ApprovalForce, oauth2.AccessTypeOnline}, opts...)
+	authURL := cfg.AuthCodeURL(state, opts...)
 
 	return authURL, nil
 }
@@ -389,6 +1338,66 @@ func (p *Project) HasRBACRole(name string) bool {
 	return false
 }
 
+// StrayRoles returns the roles granted to the users who belong to no user group.
+// The default role takes precedence over AllowStrayAsViewer, nil is returned if neither is set.
+func (p *Project) StrayRoles() []string {
+	if p.DefaultRole != "" {
+		return []string{p.DefaultRole}
+	}
+	if p.AllowStrayAsViewer {
+		return []string{BuiltinRBACRoleViewer.String()}
+	}
+	return nil
+}
+
+// AssignStrayRoles assigns the stray roles of the project, such as the default role or Viewer role,
+// to the given role of the user who was granted no role by the SSO provider, e.g. belonging to no user group.
+// It reports false if the project has no stray role, in which case the user must be rejected.
+func (p *Project) AssignStrayRoles(role *Role) bool {
+	roles := p.StrayRoles()
+	if len(roles) == 0 {
+		return false
+	}
+	role.ProjectRbacRoles = roles
+	return true
+}
+
+// ValidateDefaultRole checks whether the default role is a built-in or custom RBAC role of the project.
+func (p *Project) ValidateDefaultRole() error {
+	if p.DefaultRole == "" || isBuiltinRBACRole(p.DefaultRole) || p.HasRBACRole(p.DefaultRole) {
+		return nil
+	}
+	return fmt.Errorf("default role %s does not exist", p.DefaultRole)
+}
+
+// RolesByEmailDomain returns the roles mapped to the domain of the given email by the email domain roles.
+// The domain is compared case-insensitively, nil is returned if no mapping matches.
+// The email must have been verified by the provider since it decides the roles.
+func (p *Project) RolesByEmailDomain(email string) []string {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return nil
+	}
+	domain := email[at+1:]
+	var roles []string
+	for _, m := range p.EmailDomainRoles {
+		if strings.EqualFold(m.Domain, domain) && !slices.Contains(roles, m.Role) {
+			roles = append(roles, m.Role)
+		}
+	}
+	return roles
+}
+
+// ValidateEmailDomainRoles checks whether the roles of the email domain roles are built-in or custom RBAC roles of the project.
+func (p *Project) ValidateEmailDomainRoles() error {
+	for _, m := range p.EmailDomainRoles {
+		if !isBuiltinRBACRole(m.Role) && !p.HasRBACRole(m.Role) {
+			return fmt.Errorf("role %s of email domain %s does not exist", m.Role, m.Domain)
+		}
+	}
+	return nil
+}
+
 // HasUserGroup checks whether the user group is exists.
 func (p *Project) HasUserGroup(sso string) bool {
 	for _, v := range p.UserGroups {
@@ -402,6 +1411,33 @@ func (p *Project) HasUserGroup(sso string) bool {
 	return false
 }
 
+// MatchedUserGroups returns the SSO groups of the user groups which are in the given groups
+// of the identity provider, in the order of the user groups without duplicates.
+func (p *Project) MatchedUserGroups(groups []string) []string {
+	var matched []string
+	for _, g := range p.UserGroups {
+		if slices.Contains(groups, g.SsoGroup) && !slices.Contains(matched, g.SsoGroup) {
+			matched = append(matched, g.SsoGroup)
+		}
+	}
+	return matched
+}
+
+// SessionTTL returns the shortest session ttl of the user groups granting one of the given roles.
+// Zero means no user group specifies it.
+func (p *Project) SessionTTL(roles []string) time.Duration {
+	var ttl time.Duration
+	for _, g := range p.UserGroups {
+		if g.SessionTtl <= 0 || !slices.Contains(roles, g.Role) {
+			continue
+		}
+		if d := time.Duration(g.SessionTtl) * time.Hour; ttl == 0 || d < ttl {
+			ttl = d
+		}
+	}
+	return ttl
+}
+
 // SetLegacyUserGroups sets the legacy RBAC config as user groups if exists.
 // If the same team exists in the legacy RBAC config, this method just only sets the user group that has the highest authority level.
 func (p *Project) SetLegacyUserGroups() {
@@ -534,6 +1570,14 @@ func (p *Project) DeleteRBACRole(name string) error {
 	if isBuiltinRBACRole(name) {
 		return fmt.Errorf("built-in role cannot be deleted")
 	}
+	if p.DefaultRole != "" && name == p.DefaultRole {
+		return fmt.Errorf("%s role is the default role of the project", name)
+	}
+	for _, m := range p.EmailDomainRoles {
+		if m.Role == name {
+			return fmt.Errorf("%s role is mapped to email domain %s", name, m.Domain)
+		}
+	}
 	for i, v := range p.RbacRoles {
 		if v.Name == name {
 			c := copy(p.RbacRoles[i:], p.RbacRoles[i+1:])
@@ -573,3 +1617,25 @@ func (p *ProjectRBACPolicy) HasPermission(typ ProjectRBACResource_ResourceType,
 	}
 	return false
 }
+
+// CheckRedirectURI checks whether the given redirect URI is in the allowed list.
+// Only the scheme, host and path are compared, and all redirect URIs are allowed if the list is empty.
+func CheckRedirectURI(uri string, allowed []string) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	u, err := url.Parse(uri)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrRedirectURINotAllowed, err)
+	}
+	for _, a := range allowed {
+		au, err := url.Parse(a)
+		if err != nil {
+			continue
+		}
+		if strings.EqualFold(u.Scheme, au.Scheme) && strings.EqualFold(u.Host, au.Host) && u.Path == au.Path {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrRedirectURINotAllowed, uri)
+}
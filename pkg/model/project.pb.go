@@ -39,9 +39,15 @@ const (
 type ProjectSSOConfig_Provider int32
 
 const (
-	ProjectSSOConfig_GITHUB ProjectSSOConfig_Provider = 0
-	ProjectSSOConfig_GOOGLE ProjectSSOConfig_Provider = 2
-	ProjectSSOConfig_OIDC   ProjectSSOConfig_Provider = 3
+	ProjectSSOConfig_GITHUB    ProjectSSOConfig_Provider = 0
+	ProjectSSOConfig_GOOGLE    ProjectSSOConfig_Provider = 2
+	ProjectSSOConfig_OIDC      ProjectSSOConfig_Provider = 3
+	ProjectSSOConfig_GITLAB    ProjectSSOConfig_Provider = 4
+	ProjectSSOConfig_AZUREAD   ProjectSSOConfig_Provider = 5
+	ProjectSSOConfig_LDAP      ProjectSSOConfig_Provider = 6
+	ProjectSSOConfig_SAML      ProjectSSOConfig_Provider = 7
+	ProjectSSOConfig_OKTA      ProjectSSOConfig_Provider = 8
+	ProjectSSOConfig_BITBUCKET ProjectSSOConfig_Provider = 9
 )
 
 // Enum value maps for ProjectSSOConfig_Provider.
@@ -50,11 +56,23 @@ var (
 		0: "GITHUB",
 		2: "GOOGLE",
 		3: "OIDC",
+		4: "GITLAB",
+		5: "AZUREAD",
+		6: "LDAP",
+		7: "SAML",
+		8: "OKTA",
+		9: "BITBUCKET",
 	}
 	ProjectSSOConfig_Provider_value = map[string]int32{
-		"GITHUB": 0,
-		"GOOGLE": 2,
-		"OIDC":   3,
+		"GITHUB":    0,
+		"GOOGLE":    2,
+		"OIDC":      3,
+		"GITLAB":    4,
+		"AZUREAD":   5,
+		"LDAP":      6,
+		"SAML":      7,
+		"OKTA":      8,
+		"BITBUCKET": 9,
 	}
 )
 
@@ -85,6 +103,54 @@ func (ProjectSSOConfig_Provider) EnumDescriptor() ([]byte, []int) {
 	return file_pkg_model_project_proto_rawDescGZIP(), []int{2, 0}
 }
 
+type ProjectSSOConfig_Bitbucket_Flavor int32
+
+const (
+	// Bitbucket Cloud (bitbucket.org).
+	ProjectSSOConfig_Bitbucket_CLOUD ProjectSSOConfig_Bitbucket_Flavor = 0
+	// Self-hosted Bitbucket Server or Data Center.
+	ProjectSSOConfig_Bitbucket_SERVER ProjectSSOConfig_Bitbucket_Flavor = 1
+)
+
+// Enum value maps for ProjectSSOConfig_Bitbucket_Flavor.
+var (
+	ProjectSSOConfig_Bitbucket_Flavor_name = map[int32]string{
+		0: "CLOUD",
+		1: "SERVER",
+	}
+	ProjectSSOConfig_Bitbucket_Flavor_value = map[string]int32{
+		"CLOUD":  0,
+		"SERVER": 1,
+	}
+)
+
+func (x ProjectSSOConfig_Bitbucket_Flavor) Enum() *ProjectSSOConfig_Bitbucket_Flavor {
+	p := new(ProjectSSOConfig_Bitbucket_Flavor)
+	*p = x
+	return p
+}
+
+func (x ProjectSSOConfig_Bitbucket_Flavor) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProjectSSOConfig_Bitbucket_Flavor) Descriptor() protoreflect.EnumDescriptor {
+	return file_pkg_model_project_proto_enumTypes[1].Descriptor()
+}
+
+func (ProjectSSOConfig_Bitbucket_Flavor) Type() protoreflect.EnumType {
+	return &file_pkg_model_project_proto_enumTypes[1]
+}
+
+func (x ProjectSSOConfig_Bitbucket_Flavor) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProjectSSOConfig_Bitbucket_Flavor.Descriptor instead.
+func (ProjectSSOConfig_Bitbucket_Flavor) EnumDescriptor() ([]byte, []int) {
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{2, 7, 0}
+}
+
 type ProjectRBACResource_ResourceType int32
 
 const (
@@ -136,11 +202,11 @@ func (x ProjectRBACResource_ResourceType) String() string {
 }
 
 func (ProjectRBACResource_ResourceType) Descriptor() protoreflect.EnumDescriptor {
-	return file_pkg_model_project_proto_enumTypes[1].Descriptor()
+	return file_pkg_model_project_proto_enumTypes[2].Descriptor()
 }
 
 func (ProjectRBACResource_ResourceType) Type() protoreflect.EnumType {
-	return &file_pkg_model_project_proto_enumTypes[1]
+	return &file_pkg_model_project_proto_enumTypes[2]
 }
 
 func (x ProjectRBACResource_ResourceType) Number() protoreflect.EnumNumber {
@@ -149,7 +215,7 @@ func (x ProjectRBACResource_ResourceType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ProjectRBACResource_ResourceType.Descriptor instead.
 func (ProjectRBACResource_ResourceType) EnumDescriptor() ([]byte, []int) {
-	return file_pkg_model_project_proto_rawDescGZIP(), []int{6, 0}
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{7, 0}
 }
 
 type ProjectRBACPolicy_Action int32
@@ -194,11 +260,11 @@ func (x ProjectRBACPolicy_Action) String() string {
 }
 
 func (ProjectRBACPolicy_Action) Descriptor() protoreflect.EnumDescriptor {
-	return file_pkg_model_project_proto_enumTypes[2].Descriptor()
+	return file_pkg_model_project_proto_enumTypes[3].Descriptor()
 }
 
 func (ProjectRBACPolicy_Action) Type() protoreflect.EnumType {
-	return &file_pkg_model_project_proto_enumTypes[2]
+	return &file_pkg_model_project_proto_enumTypes[3]
 }
 
 func (x ProjectRBACPolicy_Action) Number() protoreflect.EnumNumber {
@@ -207,7 +273,7 @@ func (x ProjectRBACPolicy_Action) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ProjectRBACPolicy_Action.Descriptor instead.
 func (ProjectRBACPolicy_Action) EnumDescriptor() ([]byte, []int) {
-	return file_pkg_model_project_proto_rawDescGZIP(), []int{7, 0}
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{8, 0}
 }
 
 // Project contains needed data for a PipeCD project.
@@ -242,10 +308,32 @@ type Project struct {
 	RbacRoles []*ProjectRBACRole `protobuf:"bytes,9,rep,name=rbac_roles,json=rbacRoles,proto3" json:"rbac_roles,omitempty"`
 	// Mapping SSO group and RBAC role.
 	UserGroups []*ProjectUserGroup `protobuf:"bytes,10,rep,name=user_groups,json=userGroups,proto3" json:"user_groups,omitempty"`
+	// The names of the shared SSO configurations the users can choose to log in with
+	// in addition to sso or shared_sso_name. A provider selection page is shown at login
+	// when this is not empty.
+	AdditionalSharedSsoNames []string `protobuf:"bytes,11,rep,name=additional_shared_sso_names,json=additionalSharedSsoNames,proto3" json:"additional_shared_sso_names,omitempty"`
+	// The name of the RBAC role granted to the users who belong to no user group.
+	// It takes precedence over allow_stray_as_viewer. The login of such users is rejected if neither is set.
+	DefaultRole string `protobuf:"bytes,12,opt,name=default_role,json=defaultRole,proto3" json:"default_role,omitempty"`
+	// Mapping the domain of the verified email and RBAC role.
+	// It is used for the users who belong to no user group, and takes precedence over default_role.
+	EmailDomainRoles []*ProjectEmailDomainRole `protobuf:"bytes,13,rep,name=email_domain_roles,json=emailDomainRoles,proto3" json:"email_domain_roles,omitempty"`
 	// Unix time when the project is created.
 	CreatedAt int64 `protobuf:"varint,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	// Unix time of the last time when the project is updated.
 	UpdatedAt int64 `protobuf:"varint,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// The number of the login callbacks of this project allowed per minute.
+	// It is applied under the rate limit of the control plane, so it cannot loosen that. Zero means no limit of this project.
+	LoginCallbackRateLimit int32 `protobuf:"varint,16,opt,name=login_callback_rate_limit,json=loginCallbackRateLimit,proto3" json:"login_callback_rate_limit,omitempty"`
+	// The burst size of the login callbacks of this project. Zero means login_callback_rate_limit.
+	LoginCallbackRateLimitBurst int32 `protobuf:"varint,17,opt,name=login_callback_rate_limit_burst,json=loginCallbackRateLimitBurst,proto3" json:"login_callback_rate_limit_burst,omitempty"`
+	// The number of the failed login callbacks of this project from a client IP within login_lockout_window to lock it out of this project.
+	// It is applied under the lockout of the control plane. Zero means no lockout of this project.
+	LoginLockoutThreshold int32 `protobuf:"varint,18,opt,name=login_lockout_threshold,json=loginLockoutThreshold,proto3" json:"login_lockout_threshold,omitempty"`
+	// The period in seconds in which the failed login callbacks are counted. Zero means the lockout window of the control plane.
+	LoginLockoutWindow int64 `protobuf:"varint,19,opt,name=login_lockout_window,json=loginLockoutWindow,proto3" json:"login_lockout_window,omitempty"`
+	// The period in seconds in which the login callbacks from a locked out client IP are rejected. Zero means the lockout cooldown of the control plane.
+	LoginLockoutCooldown int64 `protobuf:"varint,20,opt,name=login_lockout_cooldown,json=loginLockoutCooldown,proto3" json:"login_lockout_cooldown,omitempty"`
 }
 
 func (x *Project) Reset() {
@@ -350,6 +438,27 @@ func (x *Project) GetUserGroups() []*ProjectUserGroup {
 	return nil
 }
 
+func (x *Project) GetAdditionalSharedSsoNames() []string {
+	if x != nil {
+		return x.AdditionalSharedSsoNames
+	}
+	return nil
+}
+
+func (x *Project) GetDefaultRole() string {
+	if x != nil {
+		return x.DefaultRole
+	}
+	return ""
+}
+
+func (x *Project) GetEmailDomainRoles() []*ProjectEmailDomainRole {
+	if x != nil {
+		return x.EmailDomainRoles
+	}
+	return nil
+}
+
 func (x *Project) GetCreatedAt() int64 {
 	if x != nil {
 		return x.CreatedAt
@@ -364,6 +473,41 @@ func (x *Project) GetUpdatedAt() int64 {
 	return 0
 }
 
+func (x *Project) GetLoginCallbackRateLimit() int32 {
+	if x != nil {
+		return x.LoginCallbackRateLimit
+	}
+	return 0
+}
+
+func (x *Project) GetLoginCallbackRateLimitBurst() int32 {
+	if x != nil {
+		return x.LoginCallbackRateLimitBurst
+	}
+	return 0
+}
+
+func (x *Project) GetLoginLockoutThreshold() int32 {
+	if x != nil {
+		return x.LoginLockoutThreshold
+	}
+	return 0
+}
+
+func (x *Project) GetLoginLockoutWindow() int64 {
+	if x != nil {
+		return x.LoginLockoutWindow
+	}
+	return 0
+}
+
+func (x *Project) GetLoginLockoutCooldown() int64 {
+	if x != nil {
+		return x.LoginLockoutCooldown
+	}
+	return 0
+}
+
 // ProjectStaticUser represents a local user who can logged in to the project by username and password.
 type ProjectStaticUser struct {
 	state         protoimpl.MessageState
@@ -429,10 +573,25 @@ type ProjectSSOConfig struct {
 
 	Provider ProjectSSOConfig_Provider `protobuf:"varint,1,opt,name=provider,proto3,enum=model.ProjectSSOConfig_Provider" json:"provider,omitempty"`
 	// The session ttl for users (hours)
-	SessionTtl int64                    `protobuf:"varint,2,opt,name=session_ttl,json=sessionTtl,proto3" json:"session_ttl,omitempty"`
-	Github     *ProjectSSOConfig_GitHub `protobuf:"bytes,10,opt,name=github,proto3" json:"github,omitempty"`
-	Google     *ProjectSSOConfig_Google `protobuf:"bytes,11,opt,name=google,proto3" json:"google,omitempty"`
-	Oidc       *ProjectSSOConfig_Oidc   `protobuf:"bytes,12,opt,name=oidc,proto3" json:"oidc,omitempty"`
+	SessionTtl int64 `protobuf:"varint,2,opt,name=session_ttl,json=sessionTtl,proto3" json:"session_ttl,omitempty"`
+	// The redirect URIs allowed to be used while exchanging the auth code.
+	// The query of the redirect URI is not compared. All redirect URIs are allowed if empty.
+	AllowedRedirectUris []string                    `protobuf:"bytes,3,rep,name=allowed_redirect_uris,json=allowedRedirectUris,proto3" json:"allowed_redirect_uris,omitempty"`
+	Github              *ProjectSSOConfig_GitHub    `protobuf:"bytes,10,opt,name=github,proto3" json:"github,omitempty"`
+	Google              *ProjectSSOConfig_Google    `protobuf:"bytes,11,opt,name=google,proto3" json:"google,omitempty"`
+	Oidc                *ProjectSSOConfig_Oidc      `protobuf:"bytes,12,opt,name=oidc,proto3" json:"oidc,omitempty"`
+	Gitlab              *ProjectSSOConfig_GitLab    `protobuf:"bytes,13,opt,name=gitlab,proto3" json:"gitlab,omitempty"`
+	AzureAd             *ProjectSSOConfig_AzureAD   `protobuf:"bytes,14,opt,name=azure_ad,json=azureAd,proto3" json:"azure_ad,omitempty"`
+	Ldap                *ProjectSSOConfig_Ldap      `protobuf:"bytes,15,opt,name=ldap,proto3" json:"ldap,omitempty"`
+	Saml                *ProjectSSOConfig_Saml      `protobuf:"bytes,16,opt,name=saml,proto3" json:"saml,omitempty"`
+	Okta                *ProjectSSOConfig_Okta      `protobuf:"bytes,17,opt,name=okta,proto3" json:"okta,omitempty"`
+	Bitbucket           *ProjectSSOConfig_Bitbucket `protobuf:"bytes,18,opt,name=bitbucket,proto3" json:"bitbucket,omitempty"`
+	// The name of the provider shown on the login button, e.g. "Corporate SSO".
+	// The name derived from the provider is used if empty.
+	DisplayName string `protobuf:"bytes,19,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// The URL of the icon shown on the login button, either an HTTPS URL or an absolute path
+	// of the control plane. No icon is shown if empty.
+	Icon string `protobuf:"bytes,20,opt,name=icon,proto3" json:"icon,omitempty"`
 }
 
 func (x *ProjectSSOConfig) Reset() {
@@ -481,6 +640,13 @@ func (x *ProjectSSOConfig) GetSessionTtl() int64 {
 	return 0
 }
 
+func (x *ProjectSSOConfig) GetAllowedRedirectUris() []string {
+	if x != nil {
+		return x.AllowedRedirectUris
+	}
+	return nil
+}
+
 func (x *ProjectSSOConfig) GetGithub() *ProjectSSOConfig_GitHub {
 	if x != nil {
 		return x.Github
@@ -502,6 +668,62 @@ func (x *ProjectSSOConfig) GetOidc() *ProjectSSOConfig_Oidc {
 	return nil
 }
 
+func (x *ProjectSSOConfig) GetGitlab() *ProjectSSOConfig_GitLab {
+	if x != nil {
+		return x.Gitlab
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig) GetAzureAd() *ProjectSSOConfig_AzureAD {
+	if x != nil {
+		return x.AzureAd
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig) GetLdap() *ProjectSSOConfig_Ldap {
+	if x != nil {
+		return x.Ldap
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig) GetSaml() *ProjectSSOConfig_Saml {
+	if x != nil {
+		return x.Saml
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig) GetOkta() *ProjectSSOConfig_Okta {
+	if x != nil {
+		return x.Okta
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig) GetBitbucket() *ProjectSSOConfig_Bitbucket {
+	if x != nil {
+		return x.Bitbucket
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig) GetIcon() string {
+	if x != nil {
+		return x.Icon
+	}
+	return ""
+}
+
 type ProjectRBACConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -575,6 +797,9 @@ type ProjectUserGroup struct {
 	SsoGroup string `protobuf:"bytes,1,opt,name=sso_group,json=ssoGroup,proto3" json:"sso_group,omitempty"`
 	// The name of rbac role.
 	Role string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+	// The session ttl for users granted the role of this group (hours).
+	// It overrides the session ttl of the SSO configuration. The shortest one is used when multiple groups match.
+	SessionTtl int64 `protobuf:"varint,3,opt,name=session_ttl,json=sessionTtl,proto3" json:"session_ttl,omitempty"`
 }
 
 func (x *ProjectUserGroup) Reset() {
@@ -623,6 +848,71 @@ func (x *ProjectUserGroup) GetRole() string {
 	return ""
 }
 
+func (x *ProjectUserGroup) GetSessionTtl() int64 {
+	if x != nil {
+		return x.SessionTtl
+	}
+	return 0
+}
+
+// ProjectEmailDomainRole represents a mapping of the email domain to the RBAC role.
+type ProjectEmailDomainRole struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The domain of the email, e.g. admin.example.com.
+	Domain string `protobuf:"bytes,1,opt,name=domain,proto3" json:"domain,omitempty"`
+	// The name of rbac role.
+	Role string `protobuf:"bytes,2,opt,name=role,proto3" json:"role,omitempty"`
+}
+
+func (x *ProjectEmailDomainRole) Reset() {
+	*x = ProjectEmailDomainRole{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_model_project_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProjectEmailDomainRole) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectEmailDomainRole) ProtoMessage() {}
+
+func (x *ProjectEmailDomainRole) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_model_project_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectEmailDomainRole.ProtoReflect.Descriptor instead.
+func (*ProjectEmailDomainRole) Descriptor() ([]byte, []int) {
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ProjectEmailDomainRole) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ProjectEmailDomainRole) GetRole() string {
+	if x != nil {
+		return x.Role
+	}
+	return ""
+}
+
 // ProjectRBACRole represents a RBAC role.
 type ProjectRBACRole struct {
 	state         protoimpl.MessageState
@@ -640,7 +930,7 @@ type ProjectRBACRole struct {
 func (x *ProjectRBACRole) Reset() {
 	*x = ProjectRBACRole{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_model_project_proto_msgTypes[5]
+		mi := &file_pkg_model_project_proto_msgTypes[6]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -653,7 +943,7 @@ func (x *ProjectRBACRole) String() string {
 func (*ProjectRBACRole) ProtoMessage() {}
 
 func (x *ProjectRBACRole) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_model_project_proto_msgTypes[5]
+	mi := &file_pkg_model_project_proto_msgTypes[6]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -666,7 +956,7 @@ func (x *ProjectRBACRole) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProjectRBACRole.ProtoReflect.Descriptor instead.
 func (*ProjectRBACRole) Descriptor() ([]byte, []int) {
-	return file_pkg_model_project_proto_rawDescGZIP(), []int{5}
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ProjectRBACRole) GetName() string {
@@ -705,7 +995,7 @@ type ProjectRBACResource struct {
 func (x *ProjectRBACResource) Reset() {
 	*x = ProjectRBACResource{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_model_project_proto_msgTypes[6]
+		mi := &file_pkg_model_project_proto_msgTypes[7]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -718,7 +1008,7 @@ func (x *ProjectRBACResource) String() string {
 func (*ProjectRBACResource) ProtoMessage() {}
 
 func (x *ProjectRBACResource) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_model_project_proto_msgTypes[6]
+	mi := &file_pkg_model_project_proto_msgTypes[7]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -731,7 +1021,7 @@ func (x *ProjectRBACResource) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProjectRBACResource.ProtoReflect.Descriptor instead.
 func (*ProjectRBACResource) Descriptor() ([]byte, []int) {
-	return file_pkg_model_project_proto_rawDescGZIP(), []int{6}
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ProjectRBACResource) GetType() ProjectRBACResource_ResourceType {
@@ -762,7 +1052,7 @@ type ProjectRBACPolicy struct {
 func (x *ProjectRBACPolicy) Reset() {
 	*x = ProjectRBACPolicy{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_model_project_proto_msgTypes[7]
+		mi := &file_pkg_model_project_proto_msgTypes[8]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -775,7 +1065,7 @@ func (x *ProjectRBACPolicy) String() string {
 func (*ProjectRBACPolicy) ProtoMessage() {}
 
 func (x *ProjectRBACPolicy) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_model_project_proto_msgTypes[7]
+	mi := &file_pkg_model_project_proto_msgTypes[8]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -788,7 +1078,7 @@ func (x *ProjectRBACPolicy) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ProjectRBACPolicy.ProtoReflect.Descriptor instead.
 func (*ProjectRBACPolicy) Descriptor() ([]byte, []int) {
-	return file_pkg_model_project_proto_rawDescGZIP(), []int{7}
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *ProjectRBACPolicy) GetResources() []*ProjectRBACResource {
@@ -815,17 +1105,35 @@ type ProjectSSOConfig_GitHub struct {
 	// The client secret string of GitHub oauth app.
 	ClientSecret string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
 	// The address of GitHub service. Required if enterprise.
+	// The OAuth endpoints are on its host, e.g. https://github.example.com.
 	BaseUrl string `protobuf:"bytes,3,opt,name=base_url,json=baseUrl,proto3" json:"base_url,omitempty"`
 	// The upload url of GitHub service.
 	UploadUrl string `protobuf:"bytes,4,opt,name=upload_url,json=uploadUrl,proto3" json:"upload_url,omitempty"`
 	// The address of the proxy used while communicating with the GitHub service.
 	ProxyUrl string `protobuf:"bytes,5,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+	// The address of the REST API of GitHub Enterprise Server, e.g. https://github.example.com/api/v3/.
+	// Default is derived from base_url. All of the addresses must use HTTPS.
+	ApiUrl string `protobuf:"bytes,6,opt,name=api_url,json=apiUrl,proto3" json:"api_url,omitempty"`
+	// The GitHub organizations whose members are allowed to log in.
+	// Users who are not a member of any of them are rejected before the team to role mapping.
+	// All users are allowed if empty. The read:org scope is requested to see the private memberships.
+	RequiredOrgs []string `protobuf:"bytes,7,rep,name=required_orgs,json=requiredOrgs,proto3" json:"required_orgs,omitempty"`
+	// The ID of the GitHub App used to look up the organizations and teams of the user instead of the user's OAuth token.
+	// The OAuth token of the user is used if zero.
+	AppId int64 `protobuf:"varint,8,opt,name=app_id,json=appId,proto3" json:"app_id,omitempty"`
+	// The ID of the installation of the GitHub App. Required if app_id is set.
+	AppInstallationId int64 `protobuf:"varint,9,opt,name=app_installation_id,json=appInstallationId,proto3" json:"app_installation_id,omitempty"`
+	// The PEM encoded private key of the GitHub App. Required if app_id is set.
+	AppPrivateKey string `protobuf:"bytes,10,opt,name=app_private_key,json=appPrivateKey,proto3" json:"app_private_key,omitempty"`
+	// The extra headers attached to the requests to GitHub, e.g. the key of the API gateway in front of GitHub Enterprise Server.
+	// The values are encrypted along with the client secret.
+	ExtraHeaders map[string]string `protobuf:"bytes,11,rep,name=extra_headers,json=extraHeaders,proto3" json:"extra_headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
 }
 
 func (x *ProjectSSOConfig_GitHub) Reset() {
 	*x = ProjectSSOConfig_GitHub{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_model_project_proto_msgTypes[8]
+		mi := &file_pkg_model_project_proto_msgTypes[9]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -838,7 +1146,7 @@ func (x *ProjectSSOConfig_GitHub) String() string {
 func (*ProjectSSOConfig_GitHub) ProtoMessage() {}
 
 func (x *ProjectSSOConfig_GitHub) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_model_project_proto_msgTypes[8]
+	mi := &file_pkg_model_project_proto_msgTypes[9]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -889,6 +1197,48 @@ func (x *ProjectSSOConfig_GitHub) GetProxyUrl() string {
 	return ""
 }
 
+func (x *ProjectSSOConfig_GitHub) GetApiUrl() string {
+	if x != nil {
+		return x.ApiUrl
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_GitHub) GetRequiredOrgs() []string {
+	if x != nil {
+		return x.RequiredOrgs
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig_GitHub) GetAppId() int64 {
+	if x != nil {
+		return x.AppId
+	}
+	return 0
+}
+
+func (x *ProjectSSOConfig_GitHub) GetAppInstallationId() int64 {
+	if x != nil {
+		return x.AppInstallationId
+	}
+	return 0
+}
+
+func (x *ProjectSSOConfig_GitHub) GetAppPrivateKey() string {
+	if x != nil {
+		return x.AppPrivateKey
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_GitHub) GetExtraHeaders() map[string]string {
+	if x != nil {
+		return x.ExtraHeaders
+	}
+	return nil
+}
+
 type ProjectSSOConfig_Google struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -898,12 +1248,20 @@ type ProjectSSOConfig_Google struct {
 	ClientId string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	// The client secret string of Google oauth app.
 	ClientSecret string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	// The address of the redirect uri.
+	RedirectUri string `protobuf:"bytes,3,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	// The Google Workspace domains allowed to log in.
+	// Users whose hosted domain (hd) is not in this list are rejected.
+	// All domains are allowed if empty.
+	AllowedDomains []string `protobuf:"bytes,4,rep,name=allowed_domains,json=allowedDomains,proto3" json:"allowed_domains,omitempty"`
+	// The address of the proxy used while communicating with the Google service.
+	ProxyUrl string `protobuf:"bytes,5,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
 }
 
 func (x *ProjectSSOConfig_Google) Reset() {
 	*x = ProjectSSOConfig_Google{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_model_project_proto_msgTypes[9]
+		mi := &file_pkg_model_project_proto_msgTypes[10]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -916,7 +1274,7 @@ func (x *ProjectSSOConfig_Google) String() string {
 func (*ProjectSSOConfig_Google) ProtoMessage() {}
 
 func (x *ProjectSSOConfig_Google) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_model_project_proto_msgTypes[9]
+	mi := &file_pkg_model_project_proto_msgTypes[10]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -946,6 +1304,27 @@ func (x *ProjectSSOConfig_Google) GetClientSecret() string {
 	return ""
 }
 
+func (x *ProjectSSOConfig_Google) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Google) GetAllowedDomains() []string {
+	if x != nil {
+		return x.AllowedDomains
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig_Google) GetProxyUrl() string {
+	if x != nil {
+		return x.ProxyUrl
+	}
+	return ""
+}
+
 type ProjectSSOConfig_Oidc struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
@@ -954,6 +1333,7 @@ type ProjectSSOConfig_Oidc struct {
 	// The client id string of OpenID Connect oauth app.
 	ClientId string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
 	// The client secret string of OpenID Connect oauth app.
+	// Required unless the client authenticates by client_certificate or client_assertion_key.
 	ClientSecret string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
 	// The address of OpenID Connect service.
 	Issuer string `protobuf:"bytes,3,opt,name=issuer,proto3" json:"issuer,omitempty"`
@@ -967,20 +1347,87 @@ type ProjectSSOConfig_Oidc struct {
 	UserInfoEndpoint string `protobuf:"bytes,7,opt,name=user_info_endpoint,json=userInfoEndpoint,proto3" json:"user_info_endpoint,omitempty"`
 	// The address of the proxy used while communicating with the OpenID Connect service.
 	ProxyUrl string `protobuf:"bytes,8,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
-	// Scopes to request from the OpenID Connect service.
+	// Scopes to request from the OpenID Connect service, which must include openid.
+	// Default is openid, profile and email.
 	Scopes []string `protobuf:"bytes,9,rep,name=scopes,proto3" json:"scopes,omitempty"`
 	// The key used to extract roles from the claims. If not specified, well-known keys such as "groups" or "roles" will be used.
 	RolesClaimKey string `protobuf:"bytes,10,opt,name=roles_claim_key,json=rolesClaimKey,proto3" json:"roles_claim_key,omitempty"`
-	// The key used to extract the username from the claims. If not specified, well-known keys such as "username" or "name" will be used.
+	// The key used to extract the username from the claims. If not specified, well-known keys such as "username" or "name" will be used,
+	// falling back to "email" and then "sub".
 	UsernameClaimKey string `protobuf:"bytes,11,opt,name=username_claim_key,json=usernameClaimKey,proto3" json:"username_claim_key,omitempty"`
 	// The key used to extract the avatar URL from the claims. If not specified, well-known keys such as "picture" or "avatar_url" will be used.
 	AvatarUrlClaimKey string `protobuf:"bytes,12,opt,name=avatar_url_claim_key,json=avatarUrlClaimKey,proto3" json:"avatar_url_claim_key,omitempty"`
+	// Whether to use PKCE (Proof Key for Code Exchange) in the authorization code flow.
+	PkceEnabled bool `protobuf:"varint,13,opt,name=pkce_enabled,json=pkceEnabled,proto3" json:"pkce_enabled,omitempty"`
+	// The PKCE code challenge method. Default is S256.
+	PkceChallengeMethod string `protobuf:"bytes,14,opt,name=pkce_challenge_method,json=pkceChallengeMethod,proto3" json:"pkce_challenge_method,omitempty"`
+	// Whether to skip validating the nonce claim of the ID token.
+	// The nonce is validated by default to prevent token replay attacks.
+	DisableNonceValidation bool `protobuf:"varint,15,opt,name=disable_nonce_validation,json=disableNonceValidation,proto3" json:"disable_nonce_validation,omitempty"`
+	// The address to redirect to after logging out from the OpenID Connect service.
+	// Logging out from the service via its end session endpoint is only enabled if this is set.
+	PostLogoutRedirectUri string `protobuf:"bytes,16,opt,name=post_logout_redirect_uri,json=postLogoutRedirectUri,proto3" json:"post_logout_redirect_uri,omitempty"`
+	// The address of the end session endpoint. Only set if you want to use custom end session endpoint.
+	EndSessionEndpoint string `protobuf:"bytes,17,opt,name=end_session_endpoint,json=endSessionEndpoint,proto3" json:"end_session_endpoint,omitempty"`
+	// The additional audiences accepted in the ID token besides the client id.
+	AllowedAudiences []string `protobuf:"bytes,18,rep,name=allowed_audiences,json=allowedAudiences,proto3" json:"allowed_audiences,omitempty"`
+	// The PEM encoded private key (RSA or EC) used to decrypt the encrypted ID token (JWE).
+	// The ID token which is not encrypted is still accepted.
+	IdTokenDecryptionKey string `protobuf:"bytes,19,opt,name=id_token_decryption_key,json=idTokenDecryptionKey,proto3" json:"id_token_decryption_key,omitempty"`
+	// The content encryption algorithms accepted for the encrypted ID token.
+	// Default is all of A128GCM, A192GCM, A256GCM, A128CBC-HS256, A192CBC-HS384 and A256CBC-HS512.
+	IdTokenContentEncryptionAlgorithms []string `protobuf:"bytes,20,rep,name=id_token_content_encryption_algorithms,json=idTokenContentEncryptionAlgorithms,proto3" json:"id_token_content_encryption_algorithms,omitempty"`
+	// Whether to reject the users whose email_verified claim is false or absent.
+	RequireVerifiedEmail bool `protobuf:"varint,21,opt,name=require_verified_email,json=requireVerifiedEmail,proto3" json:"require_verified_email,omitempty"`
+	// Whether to fetch the claims from the user info endpoint and merge them over the ID token claims.
+	// The user info endpoint is always used if user_info_endpoint is set.
+	FetchUserinfo bool `protobuf:"varint,22,opt,name=fetch_userinfo,json=fetchUserinfo,proto3" json:"fetch_userinfo,omitempty"`
+	// Whether to accept the login started by the identity provider (IdP-initiated login) without the state.
+	// This reduces the CSRF protection of the login, so enable it only if your identity provider requires it.
+	// The ID token of such login must be issued recently and must not contain the nonce claim. Default is false.
+	AllowIdpInitiatedLogin bool `protobuf:"varint,23,opt,name=allow_idp_initiated_login,json=allowIdpInitiatedLogin,proto3" json:"allow_idp_initiated_login,omitempty"`
+	// The PEM encoded client certificate presented to the token endpoint for the mutual TLS client authentication (tls_client_auth).
+	// The client secret is not required if this is set.
+	ClientCertificate string `protobuf:"bytes,24,opt,name=client_certificate,json=clientCertificate,proto3" json:"client_certificate,omitempty"`
+	// The PEM encoded private key of the client certificate. Required if client_certificate is set.
+	ClientKey string `protobuf:"bytes,25,opt,name=client_key,json=clientKey,proto3" json:"client_key,omitempty"`
+	// The PEM encoded private key (RSA or EC) used to sign the client assertion for the private_key_jwt client authentication.
+	// The client secret is not required if this is set.
+	ClientAssertionKey string `protobuf:"bytes,26,opt,name=client_assertion_key,json=clientAssertionKey,proto3" json:"client_assertion_key,omitempty"`
+	// The algorithm used to sign the client assertion. One of RS256 or ES256.
+	// Default is RS256 for the RSA key and ES256 for the EC key.
+	ClientAssertionSigningAlgorithm string `protobuf:"bytes,27,opt,name=client_assertion_signing_algorithm,json=clientAssertionSigningAlgorithm,proto3" json:"client_assertion_signing_algorithm,omitempty"`
+	// The dot-separated paths of the nested claims to extract roles from, e.g. realm_access.roles or resource_access.<client_id>.roles of Keycloak.
+	// The extracted roles are mapped to the project roles via the user groups, in addition to the roles from roles_claim_key.
+	RolesClaimPaths []string `protobuf:"bytes,28,rep,name=roles_claim_paths,json=rolesClaimPaths,proto3" json:"roles_claim_paths,omitempty"`
+	// The maximum elapsed seconds since the user last actively authenticated at the identity provider.
+	// It is sent as the max_age parameter at login, and the auth_time claim of the ID token must be within it.
+	// Zero means no limit. Default is 0.
+	MaxAge int64 `protobuf:"varint,29,opt,name=max_age,json=maxAge,proto3" json:"max_age,omitempty"`
+	// The claim telling that the groups were omitted from the ID token because the user belongs to too many groups,
+	// e.g. _claim_names of Microsoft Entra ID or hasgroups. When it is present, the full groups are fetched
+	// from groups_endpoint or the user info endpoint. The fallback is disabled if empty.
+	GroupsOverflowClaim string `protobuf:"bytes,30,opt,name=groups_overflow_claim,json=groupsOverflowClaim,proto3" json:"groups_overflow_claim,omitempty"`
+	// The address of the endpoint returning the groups of the user for the access token, either a JSON array of the groups
+	// or a JSON object containing them in the claim of roles_claim_key. The user info endpoint is used if empty.
+	GroupsEndpoint string `protobuf:"bytes,31,opt,name=groups_endpoint,json=groupsEndpoint,proto3" json:"groups_endpoint,omitempty"`
+	// The maximum number of the groups fetched when the groups overflow, the login fails if the user belongs to more.
+	// Zero means 1000. Default is 0.
+	MaxGroups int32 `protobuf:"varint,32,opt,name=max_groups,json=maxGroups,proto3" json:"max_groups,omitempty"`
+	// The extra headers attached to the requests to the identity provider, e.g. the key of the API gateway in front of it.
+	// The values are encrypted along with the client key.
+	ExtraHeaders map[string]string `protobuf:"bytes,33,rep,name=extra_headers,json=extraHeaders,proto3" json:"extra_headers,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	// The claims to map the project roles from via the user groups besides the groups, e.g. roles or entitlements,
+	// for the identity providers giving the roles in a dedicated claim. Each of them is either a claim name or
+	// a dot-separated path of the nested claim. They are tried in the order and only the first one present in the claims is used.
+	// Empty means the roles are mapped from the groups only.
+	RoleSourceClaims []string `protobuf:"bytes,34,rep,name=role_source_claims,json=roleSourceClaims,proto3" json:"role_source_claims,omitempty"`
 }
 
 func (x *ProjectSSOConfig_Oidc) Reset() {
 	*x = ProjectSSOConfig_Oidc{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_pkg_model_project_proto_msgTypes[10]
+		mi := &file_pkg_model_project_proto_msgTypes[11]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -993,7 +1440,7 @@ func (x *ProjectSSOConfig_Oidc) String() string {
 func (*ProjectSSOConfig_Oidc) ProtoMessage() {}
 
 func (x *ProjectSSOConfig_Oidc) ProtoReflect() protoreflect.Message {
-	mi := &file_pkg_model_project_proto_msgTypes[10]
+	mi := &file_pkg_model_project_proto_msgTypes[11]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1093,15 +1540,818 @@ func (x *ProjectSSOConfig_Oidc) GetAvatarUrlClaimKey() string {
 	return ""
 }
 
-var File_pkg_model_project_proto protoreflect.FileDescriptor
+func (x *ProjectSSOConfig_Oidc) GetPkceEnabled() bool {
+	if x != nil {
+		return x.PkceEnabled
+	}
+	return false
+}
 
-var file_pkg_model_project_proto_rawDesc = []byte{
-	0x0a, 0x17, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x6a,
-	0x65, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
-	0x1a, 0x17, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64,
-	0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+func (x *ProjectSSOConfig_Oidc) GetPkceChallengeMethod() string {
+	if x != nil {
+		return x.PkceChallengeMethod
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Oidc) GetDisableNonceValidation() bool {
+	if x != nil {
+		return x.DisableNonceValidation
+	}
+	return false
+}
+
+func (x *ProjectSSOConfig_Oidc) GetPostLogoutRedirectUri() string {
+	if x != nil {
+		return x.PostLogoutRedirectUri
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Oidc) GetEndSessionEndpoint() string {
+	if x != nil {
+		return x.EndSessionEndpoint
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Oidc) GetAllowedAudiences() []string {
+	if x != nil {
+		return x.AllowedAudiences
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig_Oidc) GetIdTokenDecryptionKey() string {
+	if x != nil {
+		return x.IdTokenDecryptionKey
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Oidc) GetIdTokenContentEncryptionAlgorithms() []string {
+	if x != nil {
+		return x.IdTokenContentEncryptionAlgorithms
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig_Oidc) GetRequireVerifiedEmail() bool {
+	if x != nil {
+		return x.RequireVerifiedEmail
+	}
+	return false
+}
+
+func (x *ProjectSSOConfig_Oidc) GetFetchUserinfo() bool {
+	if x != nil {
+		return x.FetchUserinfo
+	}
+	return false
+}
+
+func (x *ProjectSSOConfig_Oidc) GetAllowIdpInitiatedLogin() bool {
+	if x != nil {
+		return x.AllowIdpInitiatedLogin
+	}
+	return false
+}
+
+func (x *ProjectSSOConfig_Oidc) GetClientCertificate() string {
+	if x != nil {
+		return x.ClientCertificate
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Oidc) GetClientKey() string {
+	if x != nil {
+		return x.ClientKey
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Oidc) GetClientAssertionKey() string {
+	if x != nil {
+		return x.ClientAssertionKey
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Oidc) GetClientAssertionSigningAlgorithm() string {
+	if x != nil {
+		return x.ClientAssertionSigningAlgorithm
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Oidc) GetRolesClaimPaths() []string {
+	if x != nil {
+		return x.RolesClaimPaths
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig_Oidc) GetMaxAge() int64 {
+	if x != nil {
+		return x.MaxAge
+	}
+	return 0
+}
+
+func (x *ProjectSSOConfig_Oidc) GetGroupsOverflowClaim() string {
+	if x != nil {
+		return x.GroupsOverflowClaim
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Oidc) GetGroupsEndpoint() string {
+	if x != nil {
+		return x.GroupsEndpoint
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Oidc) GetMaxGroups() int32 {
+	if x != nil {
+		return x.MaxGroups
+	}
+	return 0
+}
+
+func (x *ProjectSSOConfig_Oidc) GetExtraHeaders() map[string]string {
+	if x != nil {
+		return x.ExtraHeaders
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig_Oidc) GetRoleSourceClaims() []string {
+	if x != nil {
+		return x.RoleSourceClaims
+	}
+	return nil
+}
+
+type ProjectSSOConfig_GitLab struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The client id string of GitLab oauth app.
+	ClientId string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// The client secret string of GitLab oauth app.
+	ClientSecret string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	// The address of GitLab service. Required if self-hosted.
+	BaseUrl string `protobuf:"bytes,3,opt,name=base_url,json=baseUrl,proto3" json:"base_url,omitempty"`
+	// The address of the redirect uri.
+	RedirectUri string `protobuf:"bytes,4,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	// The address of the proxy used while communicating with the GitLab service.
+	ProxyUrl string `protobuf:"bytes,5,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+}
+
+func (x *ProjectSSOConfig_GitLab) Reset() {
+	*x = ProjectSSOConfig_GitLab{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_model_project_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProjectSSOConfig_GitLab) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectSSOConfig_GitLab) ProtoMessage() {}
+
+func (x *ProjectSSOConfig_GitLab) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_model_project_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectSSOConfig_GitLab.ProtoReflect.Descriptor instead.
+func (*ProjectSSOConfig_GitLab) Descriptor() ([]byte, []int) {
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{2, 3}
+}
+
+func (x *ProjectSSOConfig_GitLab) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_GitLab) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_GitLab) GetBaseUrl() string {
+	if x != nil {
+		return x.BaseUrl
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_GitLab) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_GitLab) GetProxyUrl() string {
+	if x != nil {
+		return x.ProxyUrl
+	}
+	return ""
+}
+
+type ProjectSSOConfig_AzureAD struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The client id string of Microsoft Entra ID application.
+	ClientId string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// The client secret string of Microsoft Entra ID application.
+	ClientSecret string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	// The tenant used to log in. One of the tenant ID, "organizations" or "common".
+	// Default is "organizations".
+	Tenant string `protobuf:"bytes,3,opt,name=tenant,proto3" json:"tenant,omitempty"`
+	// The address of the redirect uri.
+	RedirectUri string `protobuf:"bytes,4,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	// The tenant IDs allowed to log in.
+	// Required if the tenant is not a tenant ID.
+	AllowedTenants []string `protobuf:"bytes,5,rep,name=allowed_tenants,json=allowedTenants,proto3" json:"allowed_tenants,omitempty"`
+	// The address of the proxy used while communicating with the Microsoft Entra ID service.
+	ProxyUrl string `protobuf:"bytes,6,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+}
+
+func (x *ProjectSSOConfig_AzureAD) Reset() {
+	*x = ProjectSSOConfig_AzureAD{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_model_project_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProjectSSOConfig_AzureAD) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectSSOConfig_AzureAD) ProtoMessage() {}
+
+func (x *ProjectSSOConfig_AzureAD) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_model_project_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectSSOConfig_AzureAD.ProtoReflect.Descriptor instead.
+func (*ProjectSSOConfig_AzureAD) Descriptor() ([]byte, []int) {
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{2, 4}
+}
+
+func (x *ProjectSSOConfig_AzureAD) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_AzureAD) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_AzureAD) GetTenant() string {
+	if x != nil {
+		return x.Tenant
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_AzureAD) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_AzureAD) GetAllowedTenants() []string {
+	if x != nil {
+		return x.AllowedTenants
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig_AzureAD) GetProxyUrl() string {
+	if x != nil {
+		return x.ProxyUrl
+	}
+	return ""
+}
+
+type ProjectSSOConfig_Ldap struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The address of the LDAP server, e.g. ldap://ldap.example.com:389 or ldaps://ldap.example.com:636.
+	Url string `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	// Whether to upgrade the ldap:// connection by StartTLS.
+	StartTls bool `protobuf:"varint,2,opt,name=start_tls,json=startTls,proto3" json:"start_tls,omitempty"`
+	// The PEM encoded CA certificates used to verify the LDAP server.
+	// The system root CAs are used if empty.
+	RootCa string `protobuf:"bytes,3,opt,name=root_ca,json=rootCa,proto3" json:"root_ca,omitempty"`
+	// The DN of the service account used to search users and groups.
+	// The anonymous bind is used if empty.
+	BindDn string `protobuf:"bytes,4,opt,name=bind_dn,json=bindDn,proto3" json:"bind_dn,omitempty"`
+	// The password of the service account.
+	BindPassword string `protobuf:"bytes,5,opt,name=bind_password,json=bindPassword,proto3" json:"bind_password,omitempty"`
+	// The DN where the users are searched.
+	UserSearchBase string `protobuf:"bytes,6,opt,name=user_search_base,json=userSearchBase,proto3" json:"user_search_base,omitempty"`
+	// The filter to find the user. "{username}" is replaced by the escaped username.
+	// Default is "(uid={username})". Use "(sAMAccountName={username})" for Active Directory.
+	UserFilter string `protobuf:"bytes,7,opt,name=user_filter,json=userFilter,proto3" json:"user_filter,omitempty"`
+	// The DN where the groups are searched.
+	// Only the memberOf attribute of the user is used if empty.
+	GroupSearchBase string `protobuf:"bytes,8,opt,name=group_search_base,json=groupSearchBase,proto3" json:"group_search_base,omitempty"`
+	// The filter to find the groups of the user. "{dn}" is replaced by the escaped DN of the user.
+	// Default is "(member={dn})".
+	GroupFilter string `protobuf:"bytes,9,opt,name=group_filter,json=groupFilter,proto3" json:"group_filter,omitempty"`
+	// The attribute of the group used as the SSO group name. Default is "cn".
+	GroupNameAttribute string `protobuf:"bytes,10,opt,name=group_name_attribute,json=groupNameAttribute,proto3" json:"group_name_attribute,omitempty"`
+	// The maximum number of idle connections kept to the LDAP server. Default is 4.
+	PoolSize int32 `protobuf:"varint,11,opt,name=pool_size,json=poolSize,proto3" json:"pool_size,omitempty"`
+}
+
+func (x *ProjectSSOConfig_Ldap) Reset() {
+	*x = ProjectSSOConfig_Ldap{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_model_project_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProjectSSOConfig_Ldap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectSSOConfig_Ldap) ProtoMessage() {}
+
+func (x *ProjectSSOConfig_Ldap) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_model_project_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectSSOConfig_Ldap.ProtoReflect.Descriptor instead.
+func (*ProjectSSOConfig_Ldap) Descriptor() ([]byte, []int) {
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{2, 5}
+}
+
+func (x *ProjectSSOConfig_Ldap) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Ldap) GetStartTls() bool {
+	if x != nil {
+		return x.StartTls
+	}
+	return false
+}
+
+func (x *ProjectSSOConfig_Ldap) GetRootCa() string {
+	if x != nil {
+		return x.RootCa
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Ldap) GetBindDn() string {
+	if x != nil {
+		return x.BindDn
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Ldap) GetBindPassword() string {
+	if x != nil {
+		return x.BindPassword
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Ldap) GetUserSearchBase() string {
+	if x != nil {
+		return x.UserSearchBase
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Ldap) GetUserFilter() string {
+	if x != nil {
+		return x.UserFilter
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Ldap) GetGroupSearchBase() string {
+	if x != nil {
+		return x.GroupSearchBase
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Ldap) GetGroupFilter() string {
+	if x != nil {
+		return x.GroupFilter
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Ldap) GetGroupNameAttribute() string {
+	if x != nil {
+		return x.GroupNameAttribute
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Ldap) GetPoolSize() int32 {
+	if x != nil {
+		return x.PoolSize
+	}
+	return 0
+}
+
+type ProjectSSOConfig_Okta struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The client id string of Okta application.
+	ClientId string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// The client secret string of Okta application.
+	ClientSecret string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	// The address of Okta organization, e.g. https://example.okta.com.
+	Domain string `protobuf:"bytes,3,opt,name=domain,proto3" json:"domain,omitempty"`
+	// The ID of the custom authorization server, e.g. "default".
+	// The org authorization server is used if empty.
+	AuthorizationServerId string `protobuf:"bytes,4,opt,name=authorization_server_id,json=authorizationServerId,proto3" json:"authorization_server_id,omitempty"`
+	// The address of the redirect uri.
+	RedirectUri string `protobuf:"bytes,5,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	// The claim holding the groups of the user. Default is "groups".
+	GroupsClaim string `protobuf:"bytes,6,opt,name=groups_claim,json=groupsClaim,proto3" json:"groups_claim,omitempty"`
+	// The additional scopes to request besides openid, profile, email and groups.
+	Scopes []string `protobuf:"bytes,7,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// The address of the proxy used while communicating with the Okta service.
+	ProxyUrl string `protobuf:"bytes,8,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+}
+
+func (x *ProjectSSOConfig_Okta) Reset() {
+	*x = ProjectSSOConfig_Okta{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_model_project_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProjectSSOConfig_Okta) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectSSOConfig_Okta) ProtoMessage() {}
+
+func (x *ProjectSSOConfig_Okta) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_model_project_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectSSOConfig_Okta.ProtoReflect.Descriptor instead.
+func (*ProjectSSOConfig_Okta) Descriptor() ([]byte, []int) {
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{2, 6}
+}
+
+func (x *ProjectSSOConfig_Okta) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Okta) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Okta) GetDomain() string {
+	if x != nil {
+		return x.Domain
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Okta) GetAuthorizationServerId() string {
+	if x != nil {
+		return x.AuthorizationServerId
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Okta) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Okta) GetGroupsClaim() string {
+	if x != nil {
+		return x.GroupsClaim
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Okta) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig_Okta) GetProxyUrl() string {
+	if x != nil {
+		return x.ProxyUrl
+	}
+	return ""
+}
+
+type ProjectSSOConfig_Bitbucket struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The client id string of Bitbucket OAuth consumer (Cloud) or incoming application link (Server).
+	ClientId string `protobuf:"bytes,1,opt,name=client_id,json=clientId,proto3" json:"client_id,omitempty"`
+	// The client secret string of Bitbucket OAuth consumer or incoming application link.
+	ClientSecret string `protobuf:"bytes,2,opt,name=client_secret,json=clientSecret,proto3" json:"client_secret,omitempty"`
+	// The flavor of the Bitbucket service.
+	Flavor ProjectSSOConfig_Bitbucket_Flavor `protobuf:"varint,3,opt,name=flavor,proto3,enum=model.ProjectSSOConfig_Bitbucket_Flavor" json:"flavor,omitempty"`
+	// The address of Bitbucket service. Required if SERVER.
+	BaseUrl string `protobuf:"bytes,4,opt,name=base_url,json=baseUrl,proto3" json:"base_url,omitempty"`
+	// The address of the redirect uri.
+	RedirectUri string `protobuf:"bytes,5,opt,name=redirect_uri,json=redirectUri,proto3" json:"redirect_uri,omitempty"`
+	// The scopes to request. Default is "account" for CLOUD and "PUBLIC_REPOS" for SERVER.
+	Scopes []string `protobuf:"bytes,6,rep,name=scopes,proto3" json:"scopes,omitempty"`
+	// The address of the proxy used while communicating with the Bitbucket service.
+	ProxyUrl string `protobuf:"bytes,7,opt,name=proxy_url,json=proxyUrl,proto3" json:"proxy_url,omitempty"`
+}
+
+func (x *ProjectSSOConfig_Bitbucket) Reset() {
+	*x = ProjectSSOConfig_Bitbucket{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_model_project_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProjectSSOConfig_Bitbucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectSSOConfig_Bitbucket) ProtoMessage() {}
+
+func (x *ProjectSSOConfig_Bitbucket) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_model_project_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectSSOConfig_Bitbucket.ProtoReflect.Descriptor instead.
+func (*ProjectSSOConfig_Bitbucket) Descriptor() ([]byte, []int) {
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{2, 7}
+}
+
+func (x *ProjectSSOConfig_Bitbucket) GetClientId() string {
+	if x != nil {
+		return x.ClientId
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Bitbucket) GetClientSecret() string {
+	if x != nil {
+		return x.ClientSecret
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Bitbucket) GetFlavor() ProjectSSOConfig_Bitbucket_Flavor {
+	if x != nil {
+		return x.Flavor
+	}
+	return ProjectSSOConfig_Bitbucket_CLOUD
+}
+
+func (x *ProjectSSOConfig_Bitbucket) GetBaseUrl() string {
+	if x != nil {
+		return x.BaseUrl
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Bitbucket) GetRedirectUri() string {
+	if x != nil {
+		return x.RedirectUri
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Bitbucket) GetScopes() []string {
+	if x != nil {
+		return x.Scopes
+	}
+	return nil
+}
+
+func (x *ProjectSSOConfig_Bitbucket) GetProxyUrl() string {
+	if x != nil {
+		return x.ProxyUrl
+	}
+	return ""
+}
+
+type ProjectSSOConfig_Saml struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// The address of the metadata of the SAML identity provider.
+	// Either this or idp_metadata must be set.
+	IdpMetadataUrl string `protobuf:"bytes,1,opt,name=idp_metadata_url,json=idpMetadataUrl,proto3" json:"idp_metadata_url,omitempty"`
+	// The XML metadata of the SAML identity provider.
+	IdpMetadata string `protobuf:"bytes,2,opt,name=idp_metadata,json=idpMetadata,proto3" json:"idp_metadata,omitempty"`
+	// The entity ID of PipeCD as the service provider. It must be in the audience of the assertions.
+	EntityId string `protobuf:"bytes,3,opt,name=entity_id,json=entityId,proto3" json:"entity_id,omitempty"`
+	// The attribute used as the username. The NameID of the subject is used if empty.
+	UsernameAttribute string `protobuf:"bytes,4,opt,name=username_attribute,json=usernameAttribute,proto3" json:"username_attribute,omitempty"`
+	// The attribute used as the avatar URL.
+	AvatarUrlAttribute string `protobuf:"bytes,5,opt,name=avatar_url_attribute,json=avatarUrlAttribute,proto3" json:"avatar_url_attribute,omitempty"`
+	// The attribute holding the groups of the user. Default is "groups".
+	GroupsAttribute string `protobuf:"bytes,6,opt,name=groups_attribute,json=groupsAttribute,proto3" json:"groups_attribute,omitempty"`
+	// The PEM encoded certificate of the service provider.
+	Certificate string `protobuf:"bytes,7,opt,name=certificate,proto3" json:"certificate,omitempty"`
+	// The PEM encoded RSA private key of the service provider, used to sign the authentication requests
+	// and to decrypt the encrypted assertions. Required if the certificate is set.
+	PrivateKey string `protobuf:"bytes,8,opt,name=private_key,json=privateKey,proto3" json:"private_key,omitempty"`
+}
+
+func (x *ProjectSSOConfig_Saml) Reset() {
+	*x = ProjectSSOConfig_Saml{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pkg_model_project_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ProjectSSOConfig_Saml) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectSSOConfig_Saml) ProtoMessage() {}
+
+func (x *ProjectSSOConfig_Saml) ProtoReflect() protoreflect.Message {
+	mi := &file_pkg_model_project_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectSSOConfig_Saml.ProtoReflect.Descriptor instead.
+func (*ProjectSSOConfig_Saml) Descriptor() ([]byte, []int) {
+	return file_pkg_model_project_proto_rawDescGZIP(), []int{2, 8}
+}
+
+func (x *ProjectSSOConfig_Saml) GetIdpMetadataUrl() string {
+	if x != nil {
+		return x.IdpMetadataUrl
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Saml) GetIdpMetadata() string {
+	if x != nil {
+		return x.IdpMetadata
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Saml) GetEntityId() string {
+	if x != nil {
+		return x.EntityId
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Saml) GetUsernameAttribute() string {
+	if x != nil {
+		return x.UsernameAttribute
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Saml) GetAvatarUrlAttribute() string {
+	if x != nil {
+		return x.AvatarUrlAttribute
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Saml) GetGroupsAttribute() string {
+	if x != nil {
+		return x.GroupsAttribute
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Saml) GetCertificate() string {
+	if x != nil {
+		return x.Certificate
+	}
+	return ""
+}
+
+func (x *ProjectSSOConfig_Saml) GetPrivateKey() string {
+	if x != nil {
+		return x.PrivateKey
+	}
+	return ""
+}
+
+var File_pkg_model_project_proto protoreflect.FileDescriptor
+
+var file_pkg_model_project_proto_rawDesc = []byte{
+	0x0a, 0x17, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2f, 0x70, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x1a, 0x17, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x65, 0x2f, 0x76, 0x61, 0x6c, 0x69, 0x64,
+	0x61, 0x74, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
 	0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xa6, 0x04, 0x0a, 0x07,
+	0x69, 0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0xb3, 0x08, 0x0a, 0x07,
 	0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x12, 0x17, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
 	0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x02, 0x69, 0x64,
 	0x12, 0x12, 0x0a, 0x04, 0x64, 0x65, 0x73, 0x63, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04,
@@ -1131,150 +2381,447 @@ var file_pkg_model_project_proto_rawDesc = []byte{
 	0x65, 0x72, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32,
 	0x17, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x55,
 	0x73, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x52, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x47, 0x72,
-	0x6f, 0x75, 0x70, 0x73, 0x12, 0x26, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f,
-	0x61, 0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x20,
-	0x00, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x26, 0x0a, 0x0a,
-	0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03,
-	0x42, 0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x20, 0x00, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74,
-	0x65, 0x64, 0x41, 0x74, 0x22, 0x66, 0x0a, 0x11, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x53,
-	0x74, 0x61, 0x74, 0x69, 0x63, 0x55, 0x73, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x08, 0x75, 0x73, 0x65,
-	0x72, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04,
-	0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2c,
-	0x0a, 0x0d, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x06, 0x52, 0x0c,
-	0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x48, 0x61, 0x73, 0x68, 0x22, 0xce, 0x08, 0x0a,
-	0x10, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69,
-	0x67, 0x12, 0x46, 0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x0e, 0x32, 0x20, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a,
-	0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x72, 0x6f,
-	0x76, 0x69, 0x64, 0x65, 0x72, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x82, 0x01, 0x02, 0x10, 0x01, 0x52,
-	0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x0b, 0x73, 0x65, 0x73,
-	0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x74, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07,
-	0xfa, 0x42, 0x04, 0x22, 0x02, 0x20, 0x00, 0x52, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e,
-	0x54, 0x74, 0x6c, 0x12, 0x36, 0x0a, 0x06, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x18, 0x0a, 0x20,
-	0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a,
-	0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x47, 0x69, 0x74,
-	0x48, 0x75, 0x62, 0x52, 0x06, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x12, 0x36, 0x0a, 0x06, 0x67,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x6f,
-	0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f,
-	0x6e, 0x66, 0x69, 0x67, 0x2e, 0x47, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x52, 0x06, 0x67, 0x6f, 0x6f,
-	0x67, 0x6c, 0x65, 0x12, 0x30, 0x0a, 0x04, 0x6f, 0x69, 0x64, 0x63, 0x18, 0x0c, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63,
-	0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4f, 0x69, 0x64, 0x63, 0x52,
-	0x04, 0x6f, 0x69, 0x64, 0x63, 0x1a, 0xb3, 0x01, 0x0a, 0x06, 0x47, 0x69, 0x74, 0x48, 0x75, 0x62,
-	0x12, 0x24, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x63, 0x6c,
-	0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
-	0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa,
-	0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65,
-	0x63, 0x72, 0x65, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x61, 0x73, 0x65, 0x5f, 0x75, 0x72, 0x6c,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x73, 0x65, 0x55, 0x72, 0x6c, 0x12,
-	0x1d, 0x0a, 0x0a, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x55, 0x72, 0x6c, 0x12, 0x1b,
-	0x0a, 0x09, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x72, 0x6c, 0x1a, 0x5c, 0x0a, 0x06, 0x47,
-	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x12, 0x24, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10,
-	0x01, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x0d, 0x63,
-	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x0c, 0x63, 0x6c, 0x69,
-	0x65, 0x6e, 0x74, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x1a, 0xdd, 0x03, 0x0a, 0x04, 0x4f, 0x69,
-	0x64, 0x63, 0x12, 0x24, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
+	0x6f, 0x75, 0x70, 0x73, 0x12, 0x4d, 0x0a, 0x1b, 0x61, 0x64, 0x64, 0x69, 0x74, 0x69, 0x6f, 0x6e,
+	0x61, 0x6c, 0x5f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x5f, 0x73, 0x73, 0x6f, 0x5f, 0x6e, 0x61,
+	0x6d, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28, 0x09, 0x42, 0x0e, 0xfa, 0x42, 0x0b, 0x92, 0x01,
+	0x08, 0x18, 0x01, 0x22, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x18, 0x61, 0x64, 0x64, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x53, 0x68, 0x61, 0x72, 0x65, 0x64, 0x53, 0x73, 0x6f, 0x4e, 0x61,
+	0x6d, 0x65, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x5f, 0x72,
+	0x6f, 0x6c, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x66, 0x61, 0x75,
+	0x6c, 0x74, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x4b, 0x0a, 0x12, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x5f,
+	0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x5f, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65,
+	0x63, 0x74, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f, 0x6c,
+	0x65, 0x52, 0x10, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x52, 0x6f,
+	0x6c, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61,
+	0x74, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x20, 0x00,
+	0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x26, 0x0a, 0x0a, 0x75,
+	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x03, 0x42,
+	0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x20, 0x00, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x42, 0x0a, 0x19, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f, 0x63, 0x61, 0x6c,
+	0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x05, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x1a, 0x02, 0x28, 0x00, 0x52,
+	0x16, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x61,
+	0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x4d, 0x0a, 0x1f, 0x6c, 0x6f, 0x67, 0x69, 0x6e,
+	0x5f, 0x63, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x5f, 0x72, 0x61, 0x74, 0x65, 0x5f, 0x6c,
+	0x69, 0x6d, 0x69, 0x74, 0x5f, 0x62, 0x75, 0x72, 0x73, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x05,
+	0x42, 0x07, 0xfa, 0x42, 0x04, 0x1a, 0x02, 0x28, 0x00, 0x52, 0x1b, 0x6c, 0x6f, 0x67, 0x69, 0x6e,
+	0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x61, 0x74, 0x65, 0x4c, 0x69, 0x6d, 0x69,
+	0x74, 0x42, 0x75, 0x72, 0x73, 0x74, 0x12, 0x3f, 0x0a, 0x17, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f,
+	0x6c, 0x6f, 0x63, 0x6b, 0x6f, 0x75, 0x74, 0x5f, 0x74, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c,
+	0x64, 0x18, 0x12, 0x20, 0x01, 0x28, 0x05, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x1a, 0x02, 0x28, 0x00,
+	0x52, 0x15, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x6f, 0x75, 0x74, 0x54, 0x68,
+	0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x39, 0x0a, 0x14, 0x6c, 0x6f, 0x67, 0x69, 0x6e,
+	0x5f, 0x6c, 0x6f, 0x63, 0x6b, 0x6f, 0x75, 0x74, 0x5f, 0x77, 0x69, 0x6e, 0x64, 0x6f, 0x77, 0x18,
+	0x13, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x28, 0x00, 0x52, 0x12,
+	0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x6f, 0x75, 0x74, 0x57, 0x69, 0x6e, 0x64,
+	0x6f, 0x77, 0x12, 0x3d, 0x0a, 0x16, 0x6c, 0x6f, 0x67, 0x69, 0x6e, 0x5f, 0x6c, 0x6f, 0x63, 0x6b,
+	0x6f, 0x75, 0x74, 0x5f, 0x63, 0x6f, 0x6f, 0x6c, 0x64, 0x6f, 0x77, 0x6e, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x03, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x28, 0x00, 0x52, 0x14, 0x6c, 0x6f, 0x67,
+	0x69, 0x6e, 0x4c, 0x6f, 0x63, 0x6b, 0x6f, 0x75, 0x74, 0x43, 0x6f, 0x6f, 0x6c, 0x64, 0x6f, 0x77,
+	0x6e, 0x22, 0x66, 0x0a, 0x11, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x74, 0x61, 0x74,
+	0x69, 0x63, 0x55, 0x73, 0x65, 0x72, 0x12, 0x23, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10,
+	0x01, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x0d, 0x70,
+	0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x5f, 0x68, 0x61, 0x73, 0x68, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x06, 0x52, 0x0c, 0x70, 0x61, 0x73,
+	0x73, 0x77, 0x6f, 0x72, 0x64, 0x48, 0x61, 0x73, 0x68, 0x22, 0xd2, 0x28, 0x0a, 0x10, 0x50, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x46,
+	0x0a, 0x08, 0x70, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x20, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64,
+	0x65, 0x72, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x82, 0x01, 0x02, 0x10, 0x01, 0x52, 0x08, 0x70, 0x72,
+	0x6f, 0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x28, 0x0a, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f,
+	0x6e, 0x5f, 0x74, 0x74, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xfa, 0x42, 0x04,
+	0x22, 0x02, 0x20, 0x00, 0x52, 0x0a, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x74, 0x6c,
+	0x12, 0x32, 0x0a, 0x15, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x64, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x13, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x55, 0x72, 0x69, 0x73, 0x12, 0x36, 0x0a, 0x06, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x18, 0x0a,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x47, 0x69,
+	0x74, 0x48, 0x75, 0x62, 0x52, 0x06, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x12, 0x36, 0x0a, 0x06,
+	0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x47, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x52, 0x06, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x12, 0x30, 0x0a, 0x04, 0x6f, 0x69, 0x64, 0x63, 0x18, 0x0c, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65,
+	0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4f, 0x69, 0x64, 0x63,
+	0x52, 0x04, 0x6f, 0x69, 0x64, 0x63, 0x12, 0x36, 0x0a, 0x06, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62,
+	0x18, 0x0d, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50,
+	0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e,
+	0x47, 0x69, 0x74, 0x4c, 0x61, 0x62, 0x52, 0x06, 0x67, 0x69, 0x74, 0x6c, 0x61, 0x62, 0x12, 0x3a,
+	0x0a, 0x08, 0x61, 0x7a, 0x75, 0x72, 0x65, 0x5f, 0x61, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1f, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x41, 0x7a, 0x75, 0x72, 0x65, 0x41,
+	0x44, 0x52, 0x07, 0x61, 0x7a, 0x75, 0x72, 0x65, 0x41, 0x64, 0x12, 0x30, 0x0a, 0x04, 0x6c, 0x64,
+	0x61, 0x70, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x2e, 0x4c, 0x64, 0x61, 0x70, 0x52, 0x04, 0x6c, 0x64, 0x61, 0x70, 0x12, 0x30, 0x0a, 0x04,
+	0x73, 0x61, 0x6d, 0x6c, 0x18, 0x10, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d, 0x6f, 0x64,
+	0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x2e, 0x53, 0x61, 0x6d, 0x6c, 0x52, 0x04, 0x73, 0x61, 0x6d, 0x6c, 0x12, 0x30,
+	0x0a, 0x04, 0x6f, 0x6b, 0x74, 0x61, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x6d,
+	0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4f, 0x6b, 0x74, 0x61, 0x52, 0x04, 0x6f, 0x6b, 0x74, 0x61,
+	0x12, 0x3f, 0x0a, 0x09, 0x62, 0x69, 0x74, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x18, 0x12, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a,
+	0x65, 0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x42, 0x69, 0x74,
+	0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x52, 0x09, 0x62, 0x69, 0x74, 0x62, 0x75, 0x63, 0x6b, 0x65,
+	0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d,
+	0x65, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79,
+	0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x18, 0x14, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x69, 0x63, 0x6f, 0x6e, 0x1a, 0xf8, 0x03, 0x0a, 0x06, 0x47, 0x69, 0x74,
+	0x48, 0x75, 0x62, 0x12, 0x24, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52,
+	0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x0d, 0x63, 0x6c, 0x69,
+	0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x62, 0x61, 0x73, 0x65, 0x5f,
+	0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x62, 0x61, 0x73, 0x65, 0x55,
+	0x72, 0x6c, 0x12, 0x1d, 0x0a, 0x0a, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x5f, 0x75, 0x72, 0x6c,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x75, 0x70, 0x6c, 0x6f, 0x61, 0x64, 0x55, 0x72,
+	0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x72, 0x6c, 0x12, 0x17,
+	0x0a, 0x07, 0x61, 0x70, 0x69, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x61, 0x70, 0x69, 0x55, 0x72, 0x6c, 0x12, 0x23, 0x0a, 0x0d, 0x72, 0x65, 0x71, 0x75, 0x69,
+	0x72, 0x65, 0x64, 0x5f, 0x6f, 0x72, 0x67, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0c,
+	0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x4f, 0x72, 0x67, 0x73, 0x12, 0x15, 0x0a, 0x06,
+	0x61, 0x70, 0x70, 0x5f, 0x69, 0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x03, 0x52, 0x05, 0x61, 0x70,
+	0x70, 0x49, 0x64, 0x12, 0x2e, 0x0a, 0x13, 0x61, 0x70, 0x70, 0x5f, 0x69, 0x6e, 0x73, 0x74, 0x61,
+	0x6c, 0x6c, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x11, 0x61, 0x70, 0x70, 0x49, 0x6e, 0x73, 0x74, 0x61, 0x6c, 0x6c, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x49, 0x64, 0x12, 0x26, 0x0a, 0x0f, 0x61, 0x70, 0x70, 0x5f, 0x70, 0x72, 0x69, 0x76, 0x61,
+	0x74, 0x65, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x61, 0x70,
+	0x70, 0x50, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x4b, 0x65, 0x79, 0x12, 0x55, 0x0a, 0x0d, 0x65,
+	0x78, 0x74, 0x72, 0x61, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x0b, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x30, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65,
+	0x63, 0x74, 0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x47, 0x69, 0x74, 0x48,
+	0x75, 0x62, 0x2e, 0x45, 0x78, 0x74, 0x72, 0x61, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x0c, 0x65, 0x78, 0x74, 0x72, 0x61, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x1a, 0x3f, 0x0a, 0x11, 0x45, 0x78, 0x74, 0x72, 0x61, 0x48, 0x65, 0x61, 0x64, 0x65,
+	0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0xce, 0x01, 0x0a, 0x06, 0x47, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x12, 0x24,
+	0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x73,
+	0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04,
+	0x72, 0x02, 0x10, 0x01, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x63, 0x72,
+	0x65, 0x74, 0x12, 0x2a, 0x0a, 0x0c, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x75,
+	0x72, 0x69, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10,
+	0x01, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x55, 0x72, 0x69, 0x12, 0x27,
+	0x0a, 0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64,
+	0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x78, 0x79,
+	0x5f, 0x75, 0x72, 0x6c, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x78,
+	0x79, 0x55, 0x72, 0x6c, 0x1a, 0x86, 0x0e, 0x0a, 0x04, 0x4f, 0x69, 0x64, 0x63, 0x12, 0x24, 0x0a,
+	0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e,
+	0x74, 0x49, 0x64, 0x12, 0x23, 0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65,
+	0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x73, 0x73, 0x75,
+	0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72,
+	0x12, 0x21, 0x0a, 0x0c, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x69,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x55, 0x72, 0x69, 0x12, 0x35, 0x0a, 0x16, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x15, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x6f,
+	0x6b, 0x65, 0x6e, 0x5f, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0d, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x12, 0x2c, 0x0a, 0x12, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x65,
+	0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12,
+	0x1b, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x08, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06,
+	0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63,
+	0x6f, 0x70, 0x65, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6c,
+	0x61, 0x69, 0x6d, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72,
+	0x6f, 0x6c, 0x65, 0x73, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12,
+	0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61,
+	0x6d, 0x65, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x4b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x14, 0x61, 0x76,
+	0x61, 0x74, 0x61, 0x72, 0x5f, 0x75, 0x72, 0x6c, 0x5f, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72,
+	0x55, 0x72, 0x6c, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x4b, 0x65, 0x79, 0x12, 0x21, 0x0a, 0x0c, 0x70,
+	0x6b, 0x63, 0x65, 0x5f, 0x65, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x18, 0x0d, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x0b, 0x70, 0x6b, 0x63, 0x65, 0x45, 0x6e, 0x61, 0x62, 0x6c, 0x65, 0x64, 0x12, 0x48,
+	0x0a, 0x15, 0x70, 0x6b, 0x63, 0x65, 0x5f, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65,
+	0x5f, 0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x09, 0x42, 0x14, 0xfa,
+	0x42, 0x11, 0x72, 0x0f, 0x52, 0x00, 0x52, 0x04, 0x53, 0x32, 0x35, 0x36, 0x52, 0x05, 0x70, 0x6c,
+	0x61, 0x69, 0x6e, 0x52, 0x13, 0x70, 0x6b, 0x63, 0x65, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e,
+	0x67, 0x65, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x12, 0x38, 0x0a, 0x18, 0x64, 0x69, 0x73, 0x61,
+	0x62, 0x6c, 0x65, 0x5f, 0x6e, 0x6f, 0x6e, 0x63, 0x65, 0x5f, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x64, 0x69, 0x73, 0x61,
+	0x62, 0x6c, 0x65, 0x4e, 0x6f, 0x6e, 0x63, 0x65, 0x56, 0x61, 0x6c, 0x69, 0x64, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x12, 0x37, 0x0a, 0x18, 0x70, 0x6f, 0x73, 0x74, 0x5f, 0x6c, 0x6f, 0x67, 0x6f, 0x75,
+	0x74, 0x5f, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x10,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x15, 0x70, 0x6f, 0x73, 0x74, 0x4c, 0x6f, 0x67, 0x6f, 0x75, 0x74,
+	0x52, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x55, 0x72, 0x69, 0x12, 0x30, 0x0a, 0x14, 0x65,
+	0x6e, 0x64, 0x5f, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x65, 0x6e, 0x64, 0x70, 0x6f,
+	0x69, 0x6e, 0x74, 0x18, 0x11, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x65, 0x6e, 0x64, 0x53, 0x65,
+	0x73, 0x73, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x2b, 0x0a,
+	0x11, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f, 0x61, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63,
+	0x65, 0x73, 0x18, 0x12, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65,
+	0x64, 0x41, 0x75, 0x64, 0x69, 0x65, 0x6e, 0x63, 0x65, 0x73, 0x12, 0x35, 0x0a, 0x17, 0x69, 0x64,
+	0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x64, 0x65, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x13, 0x20, 0x01, 0x28, 0x09, 0x52, 0x14, 0x69, 0x64, 0x54,
+	0x6f, 0x6b, 0x65, 0x6e, 0x44, 0x65, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x4b, 0x65,
+	0x79, 0x12, 0xa6, 0x01, 0x0a, 0x26, 0x69, 0x64, 0x5f, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x5f, 0x63,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x65, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f,
+	0x6e, 0x5f, 0x61, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x73, 0x18, 0x14, 0x20, 0x03,
+	0x28, 0x09, 0x42, 0x52, 0xfa, 0x42, 0x4f, 0x92, 0x01, 0x4c, 0x22, 0x4a, 0x72, 0x48, 0x52, 0x07,
+	0x41, 0x31, 0x32, 0x38, 0x47, 0x43, 0x4d, 0x52, 0x07, 0x41, 0x31, 0x39, 0x32, 0x47, 0x43, 0x4d,
+	0x52, 0x07, 0x41, 0x32, 0x35, 0x36, 0x47, 0x43, 0x4d, 0x52, 0x0d, 0x41, 0x31, 0x32, 0x38, 0x43,
+	0x42, 0x43, 0x2d, 0x48, 0x53, 0x32, 0x35, 0x36, 0x52, 0x0d, 0x41, 0x31, 0x39, 0x32, 0x43, 0x42,
+	0x43, 0x2d, 0x48, 0x53, 0x33, 0x38, 0x34, 0x52, 0x0d, 0x41, 0x32, 0x35, 0x36, 0x43, 0x42, 0x43,
+	0x2d, 0x48, 0x53, 0x35, 0x31, 0x32, 0x52, 0x22, 0x69, 0x64, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x43,
+	0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x45, 0x6e, 0x63, 0x72, 0x79, 0x70, 0x74, 0x69, 0x6f, 0x6e,
+	0x41, 0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x73, 0x12, 0x34, 0x0a, 0x16, 0x72, 0x65,
+	0x71, 0x75, 0x69, 0x72, 0x65, 0x5f, 0x76, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x5f, 0x65,
+	0x6d, 0x61, 0x69, 0x6c, 0x18, 0x15, 0x20, 0x01, 0x28, 0x08, 0x52, 0x14, 0x72, 0x65, 0x71, 0x75,
+	0x69, 0x72, 0x65, 0x56, 0x65, 0x72, 0x69, 0x66, 0x69, 0x65, 0x64, 0x45, 0x6d, 0x61, 0x69, 0x6c,
+	0x12, 0x25, 0x0a, 0x0e, 0x66, 0x65, 0x74, 0x63, 0x68, 0x5f, 0x75, 0x73, 0x65, 0x72, 0x69, 0x6e,
+	0x66, 0x6f, 0x18, 0x16, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x66, 0x65, 0x74, 0x63, 0x68, 0x55,
+	0x73, 0x65, 0x72, 0x69, 0x6e, 0x66, 0x6f, 0x12, 0x39, 0x0a, 0x19, 0x61, 0x6c, 0x6c, 0x6f, 0x77,
+	0x5f, 0x69, 0x64, 0x70, 0x5f, 0x69, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x6c,
+	0x6f, 0x67, 0x69, 0x6e, 0x18, 0x17, 0x20, 0x01, 0x28, 0x08, 0x52, 0x16, 0x61, 0x6c, 0x6c, 0x6f,
+	0x77, 0x49, 0x64, 0x70, 0x49, 0x6e, 0x69, 0x74, 0x69, 0x61, 0x74, 0x65, 0x64, 0x4c, 0x6f, 0x67,
+	0x69, 0x6e, 0x12, 0x2d, 0x0a, 0x12, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x63, 0x65, 0x72,
+	0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65, 0x18, 0x18, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x43, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74,
+	0x65, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x6b, 0x65, 0x79, 0x18,
+	0x19, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x4b, 0x65, 0x79,
+	0x12, 0x30, 0x0a, 0x14, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x73, 0x73, 0x65, 0x72,
+	0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x1a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x41, 0x73, 0x73, 0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x4b,
+	0x65, 0x79, 0x12, 0x62, 0x0a, 0x22, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x61, 0x73, 0x73,
+	0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x61,
+	0x6c, 0x67, 0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x18, 0x1b, 0x20, 0x01, 0x28, 0x09, 0x42, 0x15,
+	0xfa, 0x42, 0x12, 0x72, 0x10, 0x52, 0x00, 0x52, 0x05, 0x52, 0x53, 0x32, 0x35, 0x36, 0x52, 0x05,
+	0x45, 0x53, 0x32, 0x35, 0x36, 0x52, 0x1f, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x41, 0x73, 0x73,
+	0x65, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x53, 0x69, 0x67, 0x6e, 0x69, 0x6e, 0x67, 0x41, 0x6c, 0x67,
+	0x6f, 0x72, 0x69, 0x74, 0x68, 0x6d, 0x12, 0x2a, 0x0a, 0x11, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x5f,
+	0x63, 0x6c, 0x61, 0x69, 0x6d, 0x5f, 0x70, 0x61, 0x74, 0x68, 0x73, 0x18, 0x1c, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0f, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x50, 0x61, 0x74,
+	0x68, 0x73, 0x12, 0x20, 0x0a, 0x07, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x67, 0x65, 0x18, 0x1d, 0x20,
+	0x01, 0x28, 0x03, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x28, 0x00, 0x52, 0x06, 0x6d, 0x61,
+	0x78, 0x41, 0x67, 0x65, 0x12, 0x32, 0x0a, 0x15, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x5f, 0x6f,
+	0x76, 0x65, 0x72, 0x66, 0x6c, 0x6f, 0x77, 0x5f, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x18, 0x1e, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x13, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x4f, 0x76, 0x65, 0x72, 0x66,
+	0x6c, 0x6f, 0x77, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x12, 0x27, 0x0a, 0x0f, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x73, 0x5f, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x1f, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e,
+	0x74, 0x12, 0x26, 0x0a, 0x0a, 0x6d, 0x61, 0x78, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x18,
+	0x20, 0x20, 0x01, 0x28, 0x05, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x1a, 0x02, 0x28, 0x00, 0x52, 0x09,
+	0x6d, 0x61, 0x78, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x12, 0x53, 0x0a, 0x0d, 0x65, 0x78, 0x74,
+	0x72, 0x61, 0x5f, 0x68, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x18, 0x21, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x2e, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x4f, 0x69, 0x64, 0x63, 0x2e, 0x45,
+	0x78, 0x74, 0x72, 0x61, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x0c, 0x65, 0x78, 0x74, 0x72, 0x61, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x12, 0x2c,
+	0x0a, 0x12, 0x72, 0x6f, 0x6c, 0x65, 0x5f, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f, 0x63, 0x6c,
+	0x61, 0x69, 0x6d, 0x73, 0x18, 0x22, 0x20, 0x03, 0x28, 0x09, 0x52, 0x10, 0x72, 0x6f, 0x6c, 0x65,
+	0x53, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x73, 0x1a, 0x3f, 0x0a, 0x11,
+	0x45, 0x78, 0x74, 0x72, 0x61, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x73, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0xc0, 0x01,
+	0x0a, 0x06, 0x47, 0x69, 0x74, 0x4c, 0x61, 0x62, 0x12, 0x24, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65,
+	0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04,
+	0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2c,
+	0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x0c,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x19, 0x0a, 0x08,
+	0x62, 0x61, 0x73, 0x65, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x62, 0x61, 0x73, 0x65, 0x55, 0x72, 0x6c, 0x12, 0x2a, 0x0a, 0x0c, 0x72, 0x65, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa,
+	0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x55, 0x72, 0x69, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x75, 0x72, 0x6c,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x72, 0x6c,
+	0x1a, 0xe7, 0x01, 0x0a, 0x07, 0x41, 0x7a, 0x75, 0x72, 0x65, 0x41, 0x44, 0x12, 0x24, 0x0a, 0x09,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x2c, 0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02,
+	0x10, 0x01, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x06, 0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x12, 0x2a, 0x0a, 0x0c, 0x72, 0x65, 0x64, 0x69,
+	0x72, 0x65, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07,
+	0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63,
+	0x74, 0x55, 0x72, 0x69, 0x12, 0x27, 0x0a, 0x0f, 0x61, 0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x5f,
+	0x74, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0e, 0x61,
+	0x6c, 0x6c, 0x6f, 0x77, 0x65, 0x64, 0x54, 0x65, 0x6e, 0x61, 0x6e, 0x74, 0x73, 0x12, 0x1b, 0x0a,
+	0x09, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x08, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x72, 0x6c, 0x1a, 0x9c, 0x03, 0x0a, 0x04, 0x4c,
+	0x64, 0x61, 0x70, 0x12, 0x25, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x13, 0xfa, 0x42, 0x10, 0x72, 0x0e, 0x32, 0x0a, 0x5e, 0x6c, 0x64, 0x61, 0x70, 0x73, 0x3f,
+	0x3a, 0x2f, 0x2f, 0x10, 0x01, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x5f, 0x74, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x54, 0x6c, 0x73, 0x12, 0x17, 0x0a, 0x07, 0x72, 0x6f, 0x6f, 0x74, 0x5f,
+	0x63, 0x61, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x72, 0x6f, 0x6f, 0x74, 0x43, 0x61,
+	0x12, 0x17, 0x0a, 0x07, 0x62, 0x69, 0x6e, 0x64, 0x5f, 0x64, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x62, 0x69, 0x6e, 0x64, 0x44, 0x6e, 0x12, 0x23, 0x0a, 0x0d, 0x62, 0x69, 0x6e,
+	0x64, 0x5f, 0x70, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0c, 0x62, 0x69, 0x6e, 0x64, 0x50, 0x61, 0x73, 0x73, 0x77, 0x6f, 0x72, 0x64, 0x12, 0x31,
+	0x0a, 0x10, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x61, 0x72, 0x63, 0x68, 0x5f, 0x62, 0x61,
+	0x73, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10,
+	0x01, 0x52, 0x0e, 0x75, 0x73, 0x65, 0x72, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x61, 0x73,
+	0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x46, 0x69, 0x6c, 0x74,
+	0x65, 0x72, 0x12, 0x2a, 0x0a, 0x11, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x73, 0x65, 0x61, 0x72,
+	0x63, 0x68, 0x5f, 0x62, 0x61, 0x73, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x67,
+	0x72, 0x6f, 0x75, 0x70, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x42, 0x61, 0x73, 0x65, 0x12, 0x21,
+	0x0a, 0x0c, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x66, 0x69, 0x6c, 0x74, 0x65, 0x72, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x46, 0x69, 0x6c, 0x74, 0x65,
+	0x72, 0x12, 0x30, 0x0a, 0x14, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x5f,
+	0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x12, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62,
+	0x75, 0x74, 0x65, 0x12, 0x24, 0x0a, 0x09, 0x70, 0x6f, 0x6f, 0x6c, 0x5f, 0x73, 0x69, 0x7a, 0x65,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x1a, 0x02, 0x28, 0x00, 0x52,
+	0x08, 0x70, 0x6f, 0x6f, 0x6c, 0x53, 0x69, 0x7a, 0x65, 0x1a, 0xc2, 0x02, 0x0a, 0x04, 0x4f, 0x6b,
+	0x74, 0x61, 0x12, 0x24, 0x0a, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18,
 	0x01, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08,
 	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x49, 0x64, 0x12, 0x2c, 0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65,
 	0x6e, 0x74, 0x5f, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42,
 	0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
-	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x73, 0x73, 0x75, 0x65, 0x72, 0x12, 0x21,
-	0x0a, 0x0c, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x04,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x55, 0x72,
-	0x69, 0x12, 0x35, 0x0a, 0x16, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69,
-	0x6f, 0x6e, 0x5f, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x15, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69, 0x6f, 0x6e,
-	0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x6f, 0x6b, 0x65,
-	0x6e, 0x5f, 0x65, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x0d, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12,
-	0x2c, 0x0a, 0x12, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x6e, 0x66, 0x6f, 0x5f, 0x65, 0x6e, 0x64,
-	0x70, 0x6f, 0x69, 0x6e, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x75, 0x73, 0x65,
-	0x72, 0x49, 0x6e, 0x66, 0x6f, 0x45, 0x6e, 0x64, 0x70, 0x6f, 0x69, 0x6e, 0x74, 0x12, 0x1b, 0x0a,
-	0x09, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x08, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63,
-	0x6f, 0x70, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x70,
-	0x65, 0x73, 0x12, 0x26, 0x0a, 0x0f, 0x72, 0x6f, 0x6c, 0x65, 0x73, 0x5f, 0x63, 0x6c, 0x61, 0x69,
-	0x6d, 0x5f, 0x6b, 0x65, 0x79, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x72, 0x6f, 0x6c,
-	0x65, 0x73, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x4b, 0x65, 0x79, 0x12, 0x2c, 0x0a, 0x12, 0x75, 0x73,
-	0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x5f, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x5f, 0x6b, 0x65, 0x79,
-	0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65,
-	0x43, 0x6c, 0x61, 0x69, 0x6d, 0x4b, 0x65, 0x79, 0x12, 0x2f, 0x0a, 0x14, 0x61, 0x76, 0x61, 0x74,
-	0x61, 0x72, 0x5f, 0x75, 0x72, 0x6c, 0x5f, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x5f, 0x6b, 0x65, 0x79,
-	0x18, 0x0c, 0x20, 0x01, 0x28, 0x09, 0x52, 0x11, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x55, 0x72,
-	0x6c, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x4b, 0x65, 0x79, 0x22, 0x32, 0x0a, 0x08, 0x50, 0x72, 0x6f,
-	0x76, 0x69, 0x64, 0x65, 0x72, 0x12, 0x0a, 0x0a, 0x06, 0x47, 0x49, 0x54, 0x48, 0x55, 0x42, 0x10,
-	0x00, 0x12, 0x0a, 0x0a, 0x06, 0x47, 0x4f, 0x4f, 0x47, 0x4c, 0x45, 0x10, 0x02, 0x12, 0x08, 0x0a,
-	0x04, 0x4f, 0x49, 0x44, 0x43, 0x10, 0x03, 0x22, 0x04, 0x08, 0x01, 0x10, 0x01, 0x22, 0x59, 0x0a,
-	0x11, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41, 0x43, 0x43, 0x6f, 0x6e, 0x66,
-	0x69, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x64, 0x69, 0x74,
-	0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x64, 0x69, 0x74, 0x6f, 0x72,
-	0x12, 0x16, 0x0a, 0x06, 0x76, 0x69, 0x65, 0x77, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x76, 0x69, 0x65, 0x77, 0x65, 0x72, 0x22, 0x55, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x6a,
-	0x65, 0x63, 0x74, 0x55, 0x73, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x24, 0x0a, 0x09,
-	0x73, 0x73, 0x6f, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42,
-	0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x73, 0x73, 0x6f, 0x47, 0x72, 0x6f,
-	0x75, 0x70, 0x12, 0x1b, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
-	0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x22,
-	0x8d, 0x01, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41, 0x43, 0x52,
-	0x6f, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
-	0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x12, 0x3e, 0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03,
-	0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65,
-	0x63, 0x74, 0x52, 0x42, 0x41, 0x43, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x42, 0x08, 0xfa, 0x42,
-	0x05, 0x92, 0x01, 0x02, 0x08, 0x01, 0x52, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73,
-	0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x74, 0x69, 0x6e, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x42, 0x75, 0x69, 0x6c, 0x74, 0x69, 0x6e, 0x22,
-	0xff, 0x02, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41, 0x43, 0x52,
-	0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72,
-	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41, 0x43, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63,
-	0x65, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x42, 0x08,
-	0xfa, 0x42, 0x05, 0x82, 0x01, 0x02, 0x10, 0x01, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x58,
-	0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x26,
-	0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42,
-	0x41, 0x43, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e, 0x4c, 0x61, 0x62, 0x65, 0x6c,
-	0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x42, 0x18, 0xfa, 0x42, 0x09, 0x9a, 0x01, 0x06, 0x22, 0x04,
-	0x72, 0x02, 0x10, 0x01, 0xfa, 0x42, 0x09, 0x9a, 0x01, 0x06, 0x2a, 0x04, 0x72, 0x02, 0x10, 0x01,
-	0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x4c, 0x61, 0x62, 0x65,
-	0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c,
-	0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
-	0x02, 0x38, 0x01, 0x22, 0x8b, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65,
-	0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4c, 0x4c, 0x10, 0x00, 0x12, 0x0f, 0x0a,
-	0x0b, 0x41, 0x50, 0x50, 0x4c, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x0e,
-	0x0a, 0x0a, 0x44, 0x45, 0x50, 0x4c, 0x4f, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x10, 0x02, 0x12, 0x09,
-	0x0a, 0x05, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x10, 0x03, 0x12, 0x09, 0x0a, 0x05, 0x50, 0x49, 0x50,
-	0x45, 0x44, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x45, 0x50, 0x4c, 0x4f, 0x59, 0x4d, 0x45,
-	0x4e, 0x54, 0x5f, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x10, 0x05, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x52,
-	0x4f, 0x4a, 0x45, 0x43, 0x54, 0x10, 0x06, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x50, 0x49, 0x5f, 0x4b,
-	0x45, 0x59, 0x10, 0x07, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x53, 0x49, 0x47, 0x48, 0x54, 0x10,
-	0x08, 0x22, 0xf3, 0x01, 0x0a, 0x11, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41,
-	0x43, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x42, 0x0a, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75,
-	0x72, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x6f, 0x64,
-	0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41, 0x43, 0x52, 0x65,
-	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x92, 0x01, 0x02, 0x08, 0x01,
-	0x52, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x50, 0x0a, 0x07, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x6d,
-	0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41, 0x43,
-	0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x15, 0xfa,
-	0x42, 0x05, 0x92, 0x01, 0x02, 0x08, 0x01, 0xfa, 0x42, 0x0a, 0x92, 0x01, 0x07, 0x22, 0x05, 0x82,
-	0x01, 0x02, 0x10, 0x01, 0x52, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x22, 0x48, 0x0a,
-	0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4c, 0x4c, 0x10, 0x00,
-	0x12, 0x07, 0x0a, 0x03, 0x47, 0x45, 0x54, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x49, 0x53,
-	0x54, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45, 0x10, 0x03, 0x12,
-	0x0a, 0x0a, 0x06, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x44,
-	0x45, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x05, 0x42, 0x25, 0x5a, 0x23, 0x67, 0x69, 0x74, 0x68, 0x75,
-	0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x69, 0x70, 0x65, 0x2d, 0x63, 0x64, 0x2f, 0x70, 0x69,
-	0x70, 0x65, 0x63, 0x64, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x62, 0x06,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x53, 0x65, 0x63, 0x72, 0x65, 0x74, 0x12, 0x2a, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x42, 0x12, 0xfa, 0x42, 0x0f, 0x72, 0x0d, 0x10, 0x01, 0x32,
+	0x09, 0x5e, 0x68, 0x74, 0x74, 0x70, 0x73, 0x3a, 0x2f, 0x2f, 0x52, 0x06, 0x64, 0x6f, 0x6d, 0x61,
+	0x69, 0x6e, 0x12, 0x36, 0x0a, 0x17, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x5f, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x15, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x69, 0x7a, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72, 0x49, 0x64, 0x12, 0x2a, 0x0a, 0x0c, 0x72, 0x65,
+	0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x55, 0x72, 0x69, 0x12, 0x21, 0x0a, 0x0c, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73,
+	0x5f, 0x63, 0x6c, 0x61, 0x69, 0x6d, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x73, 0x43, 0x6c, 0x61, 0x69, 0x6d, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x6f,
+	0x70, 0x65, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65,
+	0x73, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x08,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x72, 0x6c, 0x1a, 0xc8,
+	0x02, 0x0a, 0x09, 0x42, 0x69, 0x74, 0x62, 0x75, 0x63, 0x6b, 0x65, 0x74, 0x12, 0x24, 0x0a, 0x09,
+	0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x42,
+	0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74,
+	0x49, 0x64, 0x12, 0x2c, 0x0a, 0x0d, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f, 0x73, 0x65, 0x63,
+	0x72, 0x65, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02,
+	0x10, 0x01, 0x52, 0x0c, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x53, 0x65, 0x63, 0x72, 0x65, 0x74,
+	0x12, 0x4a, 0x0a, 0x06, 0x66, 0x6c, 0x61, 0x76, 0x6f, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x28, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x53, 0x53, 0x4f, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2e, 0x42, 0x69, 0x74, 0x62, 0x75, 0x63,
+	0x6b, 0x65, 0x74, 0x2e, 0x46, 0x6c, 0x61, 0x76, 0x6f, 0x72, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x82,
+	0x01, 0x02, 0x10, 0x01, 0x52, 0x06, 0x66, 0x6c, 0x61, 0x76, 0x6f, 0x72, 0x12, 0x19, 0x0a, 0x08,
+	0x62, 0x61, 0x73, 0x65, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
+	0x62, 0x61, 0x73, 0x65, 0x55, 0x72, 0x6c, 0x12, 0x2a, 0x0a, 0x0c, 0x72, 0x65, 0x64, 0x69, 0x72,
+	0x65, 0x63, 0x74, 0x5f, 0x75, 0x72, 0x69, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa,
+	0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x0b, 0x72, 0x65, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74,
+	0x55, 0x72, 0x69, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x18, 0x06, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x70,
+	0x72, 0x6f, 0x78, 0x79, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x70, 0x72, 0x6f, 0x78, 0x79, 0x55, 0x72, 0x6c, 0x22, 0x1f, 0x0a, 0x06, 0x46, 0x6c, 0x61, 0x76,
+	0x6f, 0x72, 0x12, 0x09, 0x0a, 0x05, 0x43, 0x4c, 0x4f, 0x55, 0x44, 0x10, 0x00, 0x12, 0x0a, 0x0a,
+	0x06, 0x53, 0x45, 0x52, 0x56, 0x45, 0x52, 0x10, 0x01, 0x1a, 0xc8, 0x02, 0x0a, 0x04, 0x53, 0x61,
+	0x6d, 0x6c, 0x12, 0x28, 0x0a, 0x10, 0x69, 0x64, 0x70, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61,
+	0x74, 0x61, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0e, 0x69, 0x64,
+	0x70, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x55, 0x72, 0x6c, 0x12, 0x21, 0x0a, 0x0c,
+	0x69, 0x64, 0x70, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x69, 0x64, 0x70, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12,
+	0x24, 0x0a, 0x09, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x65, 0x6e, 0x74,
+	0x69, 0x74, 0x79, 0x49, 0x64, 0x12, 0x2d, 0x0a, 0x12, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d,
+	0x65, 0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x11, 0x75, 0x73, 0x65, 0x72, 0x6e, 0x61, 0x6d, 0x65, 0x41, 0x74, 0x74, 0x72, 0x69,
+	0x62, 0x75, 0x74, 0x65, 0x12, 0x30, 0x0a, 0x14, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x5f, 0x75,
+	0x72, 0x6c, 0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x12, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x55, 0x72, 0x6c, 0x41, 0x74, 0x74,
+	0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x12, 0x29, 0x0a, 0x10, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73,
+	0x5f, 0x61, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x73, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x65, 0x12, 0x20, 0x0a, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63, 0x61, 0x74, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x63, 0x65, 0x72, 0x74, 0x69, 0x66, 0x69, 0x63,
+	0x61, 0x74, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74, 0x65, 0x5f, 0x6b,
+	0x65, 0x79, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x70, 0x72, 0x69, 0x76, 0x61, 0x74,
+	0x65, 0x4b, 0x65, 0x79, 0x22, 0x78, 0x0a, 0x08, 0x50, 0x72, 0x6f, 0x76, 0x69, 0x64, 0x65, 0x72,
+	0x12, 0x0a, 0x0a, 0x06, 0x47, 0x49, 0x54, 0x48, 0x55, 0x42, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06,
+	0x47, 0x4f, 0x4f, 0x47, 0x4c, 0x45, 0x10, 0x02, 0x12, 0x08, 0x0a, 0x04, 0x4f, 0x49, 0x44, 0x43,
+	0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x47, 0x49, 0x54, 0x4c, 0x41, 0x42, 0x10, 0x04, 0x12, 0x0b,
+	0x0a, 0x07, 0x41, 0x5a, 0x55, 0x52, 0x45, 0x41, 0x44, 0x10, 0x05, 0x12, 0x08, 0x0a, 0x04, 0x4c,
+	0x44, 0x41, 0x50, 0x10, 0x06, 0x12, 0x08, 0x0a, 0x04, 0x53, 0x41, 0x4d, 0x4c, 0x10, 0x07, 0x12,
+	0x08, 0x0a, 0x04, 0x4f, 0x4b, 0x54, 0x41, 0x10, 0x08, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x49, 0x54,
+	0x42, 0x55, 0x43, 0x4b, 0x45, 0x54, 0x10, 0x09, 0x22, 0x04, 0x08, 0x01, 0x10, 0x01, 0x22, 0x59,
+	0x0a, 0x11, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41, 0x43, 0x43, 0x6f, 0x6e,
+	0x66, 0x69, 0x67, 0x12, 0x14, 0x0a, 0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x61, 0x64, 0x6d, 0x69, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x65, 0x64, 0x69,
+	0x74, 0x6f, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x65, 0x64, 0x69, 0x74, 0x6f,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x69, 0x65, 0x77, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x06, 0x76, 0x69, 0x65, 0x77, 0x65, 0x72, 0x22, 0x7f, 0x0a, 0x10, 0x50, 0x72, 0x6f,
+	0x6a, 0x65, 0x63, 0x74, 0x55, 0x73, 0x65, 0x72, 0x47, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x24, 0x0a,
+	0x09, 0x73, 0x73, 0x6f, 0x5f, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x08, 0x73, 0x73, 0x6f, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x12, 0x1b, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x04, 0x72, 0x6f, 0x6c, 0x65,
+	0x12, 0x28, 0x0a, 0x0b, 0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x74, 0x6c, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x03, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x22, 0x02, 0x28, 0x00, 0x52, 0x0a,
+	0x73, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x54, 0x74, 0x6c, 0x22, 0x56, 0x0a, 0x16, 0x50, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x45, 0x6d, 0x61, 0x69, 0x6c, 0x44, 0x6f, 0x6d, 0x61, 0x69, 0x6e,
+	0x52, 0x6f, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x06, 0x64, 0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x06, 0x64,
+	0x6f, 0x6d, 0x61, 0x69, 0x6e, 0x12, 0x1b, 0x0a, 0x04, 0x72, 0x6f, 0x6c, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x04, 0x72, 0x6f,
+	0x6c, 0x65, 0x22, 0x8d, 0x01, 0x0a, 0x0f, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42,
+	0x41, 0x43, 0x52, 0x6f, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x42, 0x07, 0xfa, 0x42, 0x04, 0x72, 0x02, 0x10, 0x01, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x3e, 0x0a, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63, 0x69, 0x65, 0x73, 0x18,
+	0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72,
+	0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41, 0x43, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x42,
+	0x08, 0xfa, 0x42, 0x05, 0x92, 0x01, 0x02, 0x08, 0x01, 0x52, 0x08, 0x70, 0x6f, 0x6c, 0x69, 0x63,
+	0x69, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x69, 0x73, 0x5f, 0x62, 0x75, 0x69, 0x6c, 0x74, 0x69,
+	0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x69, 0x73, 0x42, 0x75, 0x69, 0x6c, 0x74,
+	0x69, 0x6e, 0x22, 0xff, 0x02, 0x0a, 0x13, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42,
+	0x41, 0x43, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x27, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c,
+	0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41, 0x43, 0x52, 0x65, 0x73, 0x6f,
+	0x75, 0x72, 0x63, 0x65, 0x2e, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x54, 0x79, 0x70,
+	0x65, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x82, 0x01, 0x02, 0x10, 0x01, 0x52, 0x04, 0x74, 0x79, 0x70,
+	0x65, 0x12, 0x58, 0x0a, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x26, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63,
+	0x74, 0x52, 0x42, 0x41, 0x43, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x2e, 0x4c, 0x61,
+	0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x42, 0x18, 0xfa, 0x42, 0x09, 0x9a, 0x01,
+	0x06, 0x22, 0x04, 0x72, 0x02, 0x10, 0x01, 0xfa, 0x42, 0x09, 0x9a, 0x01, 0x06, 0x2a, 0x04, 0x72,
+	0x02, 0x10, 0x01, 0x52, 0x06, 0x6c, 0x61, 0x62, 0x65, 0x6c, 0x73, 0x1a, 0x39, 0x0a, 0x0b, 0x4c,
+	0x61, 0x62, 0x65, 0x6c, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65,
+	0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x14, 0x0a, 0x05,
+	0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x76, 0x61, 0x6c,
+	0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x22, 0x8b, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x73, 0x6f, 0x75,
+	0x72, 0x63, 0x65, 0x54, 0x79, 0x70, 0x65, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4c, 0x4c, 0x10, 0x00,
+	0x12, 0x0f, 0x0a, 0x0b, 0x41, 0x50, 0x50, 0x4c, 0x49, 0x43, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10,
+	0x01, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x45, 0x50, 0x4c, 0x4f, 0x59, 0x4d, 0x45, 0x4e, 0x54, 0x10,
+	0x02, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x56, 0x45, 0x4e, 0x54, 0x10, 0x03, 0x12, 0x09, 0x0a, 0x05,
+	0x50, 0x49, 0x50, 0x45, 0x44, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x44, 0x45, 0x50, 0x4c, 0x4f,
+	0x59, 0x4d, 0x45, 0x4e, 0x54, 0x5f, 0x43, 0x48, 0x41, 0x49, 0x4e, 0x10, 0x05, 0x12, 0x0b, 0x0a,
+	0x07, 0x50, 0x52, 0x4f, 0x4a, 0x45, 0x43, 0x54, 0x10, 0x06, 0x12, 0x0b, 0x0a, 0x07, 0x41, 0x50,
+	0x49, 0x5f, 0x4b, 0x45, 0x59, 0x10, 0x07, 0x12, 0x0b, 0x0a, 0x07, 0x49, 0x4e, 0x53, 0x49, 0x47,
+	0x48, 0x54, 0x10, 0x08, 0x22, 0xf3, 0x01, 0x0a, 0x11, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74,
+	0x52, 0x42, 0x41, 0x43, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x12, 0x42, 0x0a, 0x09, 0x72, 0x65,
+	0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e,
+	0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52, 0x42, 0x41,
+	0x43, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x42, 0x08, 0xfa, 0x42, 0x05, 0x92, 0x01,
+	0x02, 0x08, 0x01, 0x52, 0x09, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x73, 0x12, 0x50,
+	0x0a, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0e, 0x32,
+	0x1f, 0x2e, 0x6d, 0x6f, 0x64, 0x65, 0x6c, 0x2e, 0x50, 0x72, 0x6f, 0x6a, 0x65, 0x63, 0x74, 0x52,
+	0x42, 0x41, 0x43, 0x50, 0x6f, 0x6c, 0x69, 0x63, 0x79, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x42, 0x15, 0xfa, 0x42, 0x05, 0x92, 0x01, 0x02, 0x08, 0x01, 0xfa, 0x42, 0x0a, 0x92, 0x01, 0x07,
+	0x22, 0x05, 0x82, 0x01, 0x02, 0x10, 0x01, 0x52, 0x07, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x22, 0x48, 0x0a, 0x06, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x07, 0x0a, 0x03, 0x41, 0x4c,
+	0x4c, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x47, 0x45, 0x54, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04,
+	0x4c, 0x49, 0x53, 0x54, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x52, 0x45, 0x41, 0x54, 0x45,
+	0x10, 0x03, 0x12, 0x0a, 0x0a, 0x06, 0x55, 0x50, 0x44, 0x41, 0x54, 0x45, 0x10, 0x04, 0x12, 0x0a,
+	0x0a, 0x06, 0x44, 0x45, 0x4c, 0x45, 0x54, 0x45, 0x10, 0x05, 0x42, 0x25, 0x5a, 0x23, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x70, 0x69, 0x70, 0x65, 0x2d, 0x63, 0x64,
+	0x2f, 0x70, 0x69, 0x70, 0x65, 0x63, 0x64, 0x2f, 0x70, 0x6b, 0x67, 0x2f, 0x6d, 0x6f, 0x64, 0x65,
+	0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -1289,45 +2836,65 @@ func file_pkg_model_project_proto_rawDescGZIP() []byte {
 	return file_pkg_model_project_proto_rawDescData
 }
 
-var file_pkg_model_project_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
-var file_pkg_model_project_proto_msgTypes = make([]protoimpl.MessageInfo, 12)
+var file_pkg_model_project_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_pkg_model_project_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_pkg_model_project_proto_goTypes = []interface{}{
-	(ProjectSSOConfig_Provider)(0),        // 0: model.ProjectSSOConfig.Provider
-	(ProjectRBACResource_ResourceType)(0), // 1: model.ProjectRBACResource.ResourceType
-	(ProjectRBACPolicy_Action)(0),         // 2: model.ProjectRBACPolicy.Action
-	(*Project)(nil),                       // 3: model.Project
-	(*ProjectStaticUser)(nil),             // 4: model.ProjectStaticUser
-	(*ProjectSSOConfig)(nil),              // 5: model.ProjectSSOConfig
-	(*ProjectRBACConfig)(nil),             // 6: model.ProjectRBACConfig
-	(*ProjectUserGroup)(nil),              // 7: model.ProjectUserGroup
-	(*ProjectRBACRole)(nil),               // 8: model.ProjectRBACRole
-	(*ProjectRBACResource)(nil),           // 9: model.ProjectRBACResource
-	(*ProjectRBACPolicy)(nil),             // 10: model.ProjectRBACPolicy
-	(*ProjectSSOConfig_GitHub)(nil),       // 11: model.ProjectSSOConfig.GitHub
-	(*ProjectSSOConfig_Google)(nil),       // 12: model.ProjectSSOConfig.Google
-	(*ProjectSSOConfig_Oidc)(nil),         // 13: model.ProjectSSOConfig.Oidc
-	nil,                                   // 14: model.ProjectRBACResource.LabelsEntry
+	(ProjectSSOConfig_Provider)(0),         // 0: model.ProjectSSOConfig.Provider
+	(ProjectSSOConfig_Bitbucket_Flavor)(0), // 1: model.ProjectSSOConfig.Bitbucket.Flavor
+	(ProjectRBACResource_ResourceType)(0),  // 2: model.ProjectRBACResource.ResourceType
+	(ProjectRBACPolicy_Action)(0),          // 3: model.ProjectRBACPolicy.Action
+	(*Project)(nil),                        // 4: model.Project
+	(*ProjectStaticUser)(nil),              // 5: model.ProjectStaticUser
+	(*ProjectSSOConfig)(nil),               // 6: model.ProjectSSOConfig
+	(*ProjectRBACConfig)(nil),              // 7: model.ProjectRBACConfig
+	(*ProjectUserGroup)(nil),               // 8: model.ProjectUserGroup
+	(*ProjectEmailDomainRole)(nil),         // 9: model.ProjectEmailDomainRole
+	(*ProjectRBACRole)(nil),                // 10: model.ProjectRBACRole
+	(*ProjectRBACResource)(nil),            // 11: model.ProjectRBACResource
+	(*ProjectRBACPolicy)(nil),              // 12: model.ProjectRBACPolicy
+	(*ProjectSSOConfig_GitHub)(nil),        // 13: model.ProjectSSOConfig.GitHub
+	(*ProjectSSOConfig_Google)(nil),        // 14: model.ProjectSSOConfig.Google
+	(*ProjectSSOConfig_Oidc)(nil),          // 15: model.ProjectSSOConfig.Oidc
+	(*ProjectSSOConfig_GitLab)(nil),        // 16: model.ProjectSSOConfig.GitLab
+	(*ProjectSSOConfig_AzureAD)(nil),       // 17: model.ProjectSSOConfig.AzureAD
+	(*ProjectSSOConfig_Ldap)(nil),          // 18: model.ProjectSSOConfig.Ldap
+	(*ProjectSSOConfig_Okta)(nil),          // 19: model.ProjectSSOConfig.Okta
+	(*ProjectSSOConfig_Bitbucket)(nil),     // 20: model.ProjectSSOConfig.Bitbucket
+	(*ProjectSSOConfig_Saml)(nil),          // 21: model.ProjectSSOConfig.Saml
+	nil,                                    // 22: model.ProjectSSOConfig.GitHub.ExtraHeadersEntry
+	nil,                                    // 23: model.ProjectSSOConfig.Oidc.ExtraHeadersEntry
+	nil,                                    // 24: model.ProjectRBACResource.LabelsEntry
 }
 var file_pkg_model_project_proto_depIdxs = []int32{
-	4,  // 0: model.Project.static_admin:type_name -> model.ProjectStaticUser
-	5,  // 1: model.Project.sso:type_name -> model.ProjectSSOConfig
-	6,  // 2: model.Project.rbac:type_name -> model.ProjectRBACConfig
-	8,  // 3: model.Project.rbac_roles:type_name -> model.ProjectRBACRole
-	7,  // 4: model.Project.user_groups:type_name -> model.ProjectUserGroup
-	0,  // 5: model.ProjectSSOConfig.provider:type_name -> model.ProjectSSOConfig.Provider
-	11, // 6: model.ProjectSSOConfig.github:type_name -> model.ProjectSSOConfig.GitHub
-	12, // 7: model.ProjectSSOConfig.google:type_name -> model.ProjectSSOConfig.Google
-	13, // 8: model.ProjectSSOConfig.oidc:type_name -> model.ProjectSSOConfig.Oidc
-	10, // 9: model.ProjectRBACRole.policies:type_name -> model.ProjectRBACPolicy
-	1,  // 10: model.ProjectRBACResource.type:type_name -> model.ProjectRBACResource.ResourceType
-	14, // 11: model.ProjectRBACResource.labels:type_name -> model.ProjectRBACResource.LabelsEntry
-	9,  // 12: model.ProjectRBACPolicy.resources:type_name -> model.ProjectRBACResource
-	2,  // 13: model.ProjectRBACPolicy.actions:type_name -> model.ProjectRBACPolicy.Action
-	14, // [14:14] is the sub-list for method output_type
-	14, // [14:14] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	5,  // 0: model.Project.static_admin:type_name -> model.ProjectStaticUser
+	6,  // 1: model.Project.sso:type_name -> model.ProjectSSOConfig
+	7,  // 2: model.Project.rbac:type_name -> model.ProjectRBACConfig
+	10, // 3: model.Project.rbac_roles:type_name -> model.ProjectRBACRole
+	8,  // 4: model.Project.user_groups:type_name -> model.ProjectUserGroup
+	9,  // 5: model.Project.email_domain_roles:type_name -> model.ProjectEmailDomainRole
+	0,  // 6: model.ProjectSSOConfig.provider:type_name -> model.ProjectSSOConfig.Provider
+	13, // 7: model.ProjectSSOConfig.github:type_name -> model.ProjectSSOConfig.GitHub
+	14, // 8: model.ProjectSSOConfig.google:type_name -> model.ProjectSSOConfig.Google
+	15, // 9: model.ProjectSSOConfig.oidc:type_name -> model.ProjectSSOConfig.Oidc
+	16, // 10: model.ProjectSSOConfig.gitlab:type_name -> model.ProjectSSOConfig.GitLab
+	17, // 11: model.ProjectSSOConfig.azure_ad:type_name -> model.ProjectSSOConfig.AzureAD
+	18, // 12: model.ProjectSSOConfig.ldap:type_name -> model.ProjectSSOConfig.Ldap
+	21, // 13: model.ProjectSSOConfig.saml:type_name -> model.ProjectSSOConfig.Saml
+	19, // 14: model.ProjectSSOConfig.okta:type_name -> model.ProjectSSOConfig.Okta
+	20, // 15: model.ProjectSSOConfig.bitbucket:type_name -> model.ProjectSSOConfig.Bitbucket
+	12, // 16: model.ProjectRBACRole.policies:type_name -> model.ProjectRBACPolicy
+	2,  // 17: model.ProjectRBACResource.type:type_name -> model.ProjectRBACResource.ResourceType
+	24, // 18: model.ProjectRBACResource.labels:type_name -> model.ProjectRBACResource.LabelsEntry
+	11, // 19: model.ProjectRBACPolicy.resources:type_name -> model.ProjectRBACResource
+	3,  // 20: model.ProjectRBACPolicy.actions:type_name -> model.ProjectRBACPolicy.Action
+	22, // 21: model.ProjectSSOConfig.GitHub.extra_headers:type_name -> model.ProjectSSOConfig.GitHub.ExtraHeadersEntry
+	23, // 22: model.ProjectSSOConfig.Oidc.extra_headers:type_name -> model.ProjectSSOConfig.Oidc.ExtraHeadersEntry
+	1,  // 23: model.ProjectSSOConfig.Bitbucket.flavor:type_name -> model.ProjectSSOConfig.Bitbucket.Flavor
+	24, // [24:24] is the sub-list for method output_type
+	24, // [24:24] is the sub-list for method input_type
+	24, // [24:24] is the sub-list for extension type_name
+	24, // [24:24] is the sub-list for extension extendee
+	0,  // [0:24] is the sub-list for field type_name
 }
 
 func init() { file_pkg_model_project_proto_init() }
@@ -1397,7 +2964,7 @@ func file_pkg_model_project_proto_init() {
 			}
 		}
 		file_pkg_model_project_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProjectRBACRole); i {
+			switch v := v.(*ProjectEmailDomainRole); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1409,7 +2976,7 @@ func file_pkg_model_project_proto_init() {
 			}
 		}
 		file_pkg_model_project_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProjectRBACResource); i {
+			switch v := v.(*ProjectRBACRole); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1421,7 +2988,7 @@ func file_pkg_model_project_proto_init() {
 			}
 		}
 		file_pkg_model_project_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProjectRBACPolicy); i {
+			switch v := v.(*ProjectRBACResource); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1433,7 +3000,7 @@ func file_pkg_model_project_proto_init() {
 			}
 		}
 		file_pkg_model_project_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProjectSSOConfig_GitHub); i {
+			switch v := v.(*ProjectRBACPolicy); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1445,7 +3012,7 @@ func file_pkg_model_project_proto_init() {
 			}
 		}
 		file_pkg_model_project_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*ProjectSSOConfig_Google); i {
+			switch v := v.(*ProjectSSOConfig_GitHub); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -1457,6 +3024,18 @@ func file_pkg_model_project_proto_init() {
 			}
 		}
 		file_pkg_model_project_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProjectSSOConfig_Google); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_model_project_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*ProjectSSOConfig_Oidc); i {
 			case 0:
 				return &v.state
@@ -1468,14 +3047,86 @@ func file_pkg_model_project_proto_init() {
 				return nil
 			}
 		}
+		file_pkg_model_project_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProjectSSOConfig_GitLab); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_model_project_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProjectSSOConfig_AzureAD); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_model_project_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProjectSSOConfig_Ldap); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_model_project_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProjectSSOConfig_Okta); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_model_project_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProjectSSOConfig_Bitbucket); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pkg_model_project_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ProjectSSOConfig_Saml); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_pkg_model_project_proto_rawDesc,
-			NumEnums:      3,
-			NumMessages:   12,
+			NumEnums:      4,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
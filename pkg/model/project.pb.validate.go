@@ -241,6 +241,73 @@ func (m *Project) validate(all bool) error {
 
 	}
 
+	_Project_AdditionalSharedSsoNames_Unique := make(map[string]struct{}, len(m.GetAdditionalSharedSsoNames()))
+
+	for idx, item := range m.GetAdditionalSharedSsoNames() {
+		_, _ = idx, item
+
+		if _, exists := _Project_AdditionalSharedSsoNames_Unique[item]; exists {
+			err := ProjectValidationError{
+				field:  fmt.Sprintf("AdditionalSharedSsoNames[%v]", idx),
+				reason: "repeated value must contain unique items",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		} else {
+			_Project_AdditionalSharedSsoNames_Unique[item] = struct{}{}
+		}
+
+		if utf8.RuneCountInString(item) < 1 {
+			err := ProjectValidationError{
+				field:  fmt.Sprintf("AdditionalSharedSsoNames[%v]", idx),
+				reason: "value length must be at least 1 runes",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	// no validation rules for DefaultRole
+
+	for idx, item := range m.GetEmailDomainRoles() {
+		_, _ = idx, item
+
+		if all {
+			switch v := interface{}(item).(type) {
+			case interface{ ValidateAll() error }:
+				if err := v.ValidateAll(); err != nil {
+					errors = append(errors, ProjectValidationError{
+						field:  fmt.Sprintf("EmailDomainRoles[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			case interface{ Validate() error }:
+				if err := v.Validate(); err != nil {
+					errors = append(errors, ProjectValidationError{
+						field:  fmt.Sprintf("EmailDomainRoles[%v]", idx),
+						reason: "embedded message failed validation",
+						cause:  err,
+					})
+				}
+			}
+		} else if v, ok := interface{}(item).(interface{ Validate() error }); ok {
+			if err := v.Validate(); err != nil {
+				return ProjectValidationError{
+					field:  fmt.Sprintf("EmailDomainRoles[%v]", idx),
+					reason: "embedded message failed validation",
+					cause:  err,
+				}
+			}
+		}
+
+	}
+
 	if m.GetCreatedAt() <= 0 {
 		err := ProjectValidationError{
 			field:  "CreatedAt",
@@ -263,6 +330,61 @@ func (m *Project) validate(all bool) error {
 		errors = append(errors, err)
 	}
 
+	if m.GetLoginCallbackRateLimit() < 0 {
+		err := ProjectValidationError{
+			field:  "LoginCallbackRateLimit",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetLoginCallbackRateLimitBurst() < 0 {
+		err := ProjectValidationError{
+			field:  "LoginCallbackRateLimitBurst",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetLoginLockoutThreshold() < 0 {
+		err := ProjectValidationError{
+			field:  "LoginLockoutThreshold",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetLoginLockoutWindow() < 0 {
+		err := ProjectValidationError{
+			field:  "LoginLockoutWindow",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetLoginLockoutCooldown() < 0 {
+		err := ProjectValidationError{
+			field:  "LoginLockoutCooldown",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
 	if len(errors) > 0 {
 		return ProjectMultiError(errors)
 	}
@@ -595,6 +717,184 @@ func (m *ProjectSSOConfig) validate(all bool) error {
 		}
 	}
 
+	if all {
+		switch v := interface{}(m.GetGitlab()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "Gitlab",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "Gitlab",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetGitlab()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ProjectSSOConfigValidationError{
+				field:  "Gitlab",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetAzureAd()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "AzureAd",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "AzureAd",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetAzureAd()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ProjectSSOConfigValidationError{
+				field:  "AzureAd",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetLdap()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "Ldap",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "Ldap",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetLdap()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ProjectSSOConfigValidationError{
+				field:  "Ldap",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetSaml()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "Saml",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "Saml",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetSaml()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ProjectSSOConfigValidationError{
+				field:  "Saml",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetOkta()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "Okta",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "Okta",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetOkta()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ProjectSSOConfigValidationError{
+				field:  "Okta",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	if all {
+		switch v := interface{}(m.GetBitbucket()).(type) {
+		case interface{ ValidateAll() error }:
+			if err := v.ValidateAll(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "Bitbucket",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		case interface{ Validate() error }:
+			if err := v.Validate(); err != nil {
+				errors = append(errors, ProjectSSOConfigValidationError{
+					field:  "Bitbucket",
+					reason: "embedded message failed validation",
+					cause:  err,
+				})
+			}
+		}
+	} else if v, ok := interface{}(m.GetBitbucket()).(interface{ Validate() error }); ok {
+		if err := v.Validate(); err != nil {
+			return ProjectSSOConfigValidationError{
+				field:  "Bitbucket",
+				reason: "embedded message failed validation",
+				cause:  err,
+			}
+		}
+	}
+
+	// no validation rules for DisplayName
+
+	// no validation rules for Icon
+
 	if len(errors) > 0 {
 		return ProjectSSOConfigMultiError(errors)
 	}
@@ -825,6 +1125,17 @@ func (m *ProjectUserGroup) validate(all bool) error {
 		errors = append(errors, err)
 	}
 
+	if m.GetSessionTtl() < 0 {
+		err := ProjectUserGroupValidationError{
+			field:  "SessionTtl",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
 	if len(errors) > 0 {
 		return ProjectUserGroupMultiError(errors)
 	}
@@ -903,18 +1214,142 @@ var _ interface {
 	ErrorName() string
 } = ProjectUserGroupValidationError{}
 
-// Validate checks the field values on ProjectRBACRole with the rules defined
-// in the proto definition for this message. If any rules are violated, the
-// first error encountered is returned, or nil if there are no violations.
-func (m *ProjectRBACRole) Validate() error {
+// Validate checks the field values on ProjectEmailDomainRole with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ProjectEmailDomainRole) Validate() error {
 	return m.validate(false)
 }
 
-// ValidateAll checks the field values on ProjectRBACRole with the rules
-// defined in the proto definition for this message. If any rules are
+// ValidateAll checks the field values on ProjectEmailDomainRole with the
+// rules defined in the proto definition for this message. If any rules are
 // violated, the result is a list of violation errors wrapped in
-// ProjectRBACRoleMultiError, or nil if none found.
-func (m *ProjectRBACRole) ValidateAll() error {
+// ProjectEmailDomainRoleMultiError, or nil if none found.
+func (m *ProjectEmailDomainRole) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ProjectEmailDomainRole) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetDomain()) < 1 {
+		err := ProjectEmailDomainRoleValidationError{
+			field:  "Domain",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetRole()) < 1 {
+		err := ProjectEmailDomainRoleValidationError{
+			field:  "Role",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ProjectEmailDomainRoleMultiError(errors)
+	}
+
+	return nil
+}
+
+// ProjectEmailDomainRoleMultiError is an error wrapping multiple validation
+// errors returned by ProjectEmailDomainRole.ValidateAll() if the designated
+// constraints aren't met.
+type ProjectEmailDomainRoleMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ProjectEmailDomainRoleMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ProjectEmailDomainRoleMultiError) AllErrors() []error { return m }
+
+// ProjectEmailDomainRoleValidationError is the validation error returned by
+// ProjectEmailDomainRole.Validate if the designated constraints aren't met.
+type ProjectEmailDomainRoleValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProjectEmailDomainRoleValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProjectEmailDomainRoleValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProjectEmailDomainRoleValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProjectEmailDomainRoleValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProjectEmailDomainRoleValidationError) ErrorName() string {
+	return "ProjectEmailDomainRoleValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ProjectEmailDomainRoleValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProjectEmailDomainRole.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProjectEmailDomainRoleValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProjectEmailDomainRoleValidationError{}
+
+// Validate checks the field values on ProjectRBACRole with the rules defined
+// in the proto definition for this message. If any rules are violated, the
+// first error encountered is returned, or nil if there are no violations.
+func (m *ProjectRBACRole) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ProjectRBACRole with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ProjectRBACRoleMultiError, or nil if none found.
+func (m *ProjectRBACRole) ValidateAll() error {
 	return m.validate(true)
 }
 
@@ -1435,6 +1870,16 @@ func (m *ProjectSSOConfig_GitHub) validate(all bool) error {
 
 	// no validation rules for ProxyUrl
 
+	// no validation rules for ApiUrl
+
+	// no validation rules for AppId
+
+	// no validation rules for AppInstallationId
+
+	// no validation rules for AppPrivateKey
+
+	// no validation rules for ExtraHeaders
+
 	if len(errors) > 0 {
 		return ProjectSSOConfig_GitHubMultiError(errors)
 	}
@@ -1559,6 +2004,19 @@ func (m *ProjectSSOConfig_Google) validate(all bool) error {
 		errors = append(errors, err)
 	}
 
+	if utf8.RuneCountInString(m.GetRedirectUri()) < 1 {
+		err := ProjectSSOConfig_GoogleValidationError{
+			field:  "RedirectUri",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for ProxyUrl
+
 	if len(errors) > 0 {
 		return ProjectSSOConfig_GoogleMultiError(errors)
 	}
@@ -1672,16 +2130,7 @@ func (m *ProjectSSOConfig_Oidc) validate(all bool) error {
 		errors = append(errors, err)
 	}
 
-	if utf8.RuneCountInString(m.GetClientSecret()) < 1 {
-		err := ProjectSSOConfig_OidcValidationError{
-			field:  "ClientSecret",
-			reason: "value length must be at least 1 runes",
-		}
-		if !all {
-			return err
-		}
-		errors = append(errors, err)
-	}
+	// no validation rules for ClientSecret
 
 	// no validation rules for Issuer
 
@@ -1701,6 +2150,94 @@ func (m *ProjectSSOConfig_Oidc) validate(all bool) error {
 
 	// no validation rules for AvatarUrlClaimKey
 
+	// no validation rules for PkceEnabled
+
+	if _, ok := _ProjectSSOConfig_Oidc_PkceChallengeMethod_InLookup[m.GetPkceChallengeMethod()]; !ok {
+		err := ProjectSSOConfig_OidcValidationError{
+			field:  "PkceChallengeMethod",
+			reason: "value must be in list [ S256 plain]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for DisableNonceValidation
+
+	// no validation rules for PostLogoutRedirectUri
+
+	// no validation rules for EndSessionEndpoint
+
+	// no validation rules for IdTokenDecryptionKey
+
+	for idx, item := range m.GetIdTokenContentEncryptionAlgorithms() {
+		_, _ = idx, item
+
+		if _, ok := _ProjectSSOConfig_Oidc_IdTokenContentEncryptionAlgorithms_InLookup[item]; !ok {
+			err := ProjectSSOConfig_OidcValidationError{
+				field:  fmt.Sprintf("IdTokenContentEncryptionAlgorithms[%v]", idx),
+				reason: "value must be in list [A128GCM A192GCM A256GCM A128CBC-HS256 A192CBC-HS384 A256CBC-HS512]",
+			}
+			if !all {
+				return err
+			}
+			errors = append(errors, err)
+		}
+
+	}
+
+	// no validation rules for RequireVerifiedEmail
+
+	// no validation rules for FetchUserinfo
+
+	// no validation rules for AllowIdpInitiatedLogin
+
+	// no validation rules for ClientCertificate
+
+	// no validation rules for ClientKey
+
+	// no validation rules for ClientAssertionKey
+
+	if _, ok := _ProjectSSOConfig_Oidc_ClientAssertionSigningAlgorithm_InLookup[m.GetClientAssertionSigningAlgorithm()]; !ok {
+		err := ProjectSSOConfig_OidcValidationError{
+			field:  "ClientAssertionSigningAlgorithm",
+			reason: "value must be in list [ RS256 ES256]",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if m.GetMaxAge() < 0 {
+		err := ProjectSSOConfig_OidcValidationError{
+			field:  "MaxAge",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for GroupsOverflowClaim
+
+	// no validation rules for GroupsEndpoint
+
+	if m.GetMaxGroups() < 0 {
+		err := ProjectSSOConfig_OidcValidationError{
+			field:  "MaxGroups",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for ExtraHeaders
+
 	if len(errors) > 0 {
 		return ProjectSSOConfig_OidcMultiError(errors)
 	}
@@ -1780,3 +2317,908 @@ var _ interface {
 	Cause() error
 	ErrorName() string
 } = ProjectSSOConfig_OidcValidationError{}
+
+var _ProjectSSOConfig_Oidc_PkceChallengeMethod_InLookup = map[string]struct{}{
+	"":      {},
+	"S256":  {},
+	"plain": {},
+}
+
+var _ProjectSSOConfig_Oidc_IdTokenContentEncryptionAlgorithms_InLookup = map[string]struct{}{
+	"A128GCM":       {},
+	"A192GCM":       {},
+	"A256GCM":       {},
+	"A128CBC-HS256": {},
+	"A192CBC-HS384": {},
+	"A256CBC-HS512": {},
+}
+
+var _ProjectSSOConfig_Oidc_ClientAssertionSigningAlgorithm_InLookup = map[string]struct{}{
+	"":      {},
+	"RS256": {},
+	"ES256": {},
+}
+
+// Validate checks the field values on ProjectSSOConfig_GitLab with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ProjectSSOConfig_GitLab) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ProjectSSOConfig_GitLab with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ProjectSSOConfig_GitLabMultiError, or nil if none found.
+func (m *ProjectSSOConfig_GitLab) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ProjectSSOConfig_GitLab) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetClientId()) < 1 {
+		err := ProjectSSOConfig_GitLabValidationError{
+			field:  "ClientId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetClientSecret()) < 1 {
+		err := ProjectSSOConfig_GitLabValidationError{
+			field:  "ClientSecret",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for BaseUrl
+
+	if utf8.RuneCountInString(m.GetRedirectUri()) < 1 {
+		err := ProjectSSOConfig_GitLabValidationError{
+			field:  "RedirectUri",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for ProxyUrl
+
+	if len(errors) > 0 {
+		return ProjectSSOConfig_GitLabMultiError(errors)
+	}
+
+	return nil
+}
+
+// ProjectSSOConfig_GitLabMultiError is an error wrapping multiple validation
+// errors returned by ProjectSSOConfig_GitLab.ValidateAll() if the designated
+// constraints aren't met.
+type ProjectSSOConfig_GitLabMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ProjectSSOConfig_GitLabMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ProjectSSOConfig_GitLabMultiError) AllErrors() []error { return m }
+
+// ProjectSSOConfig_GitLabValidationError is the validation error returned by
+// ProjectSSOConfig_GitLab.Validate if the designated constraints aren't met.
+type ProjectSSOConfig_GitLabValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProjectSSOConfig_GitLabValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProjectSSOConfig_GitLabValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProjectSSOConfig_GitLabValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProjectSSOConfig_GitLabValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProjectSSOConfig_GitLabValidationError) ErrorName() string {
+	return "ProjectSSOConfig_GitLabValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ProjectSSOConfig_GitLabValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProjectSSOConfig_GitLab.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProjectSSOConfig_GitLabValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProjectSSOConfig_GitLabValidationError{}
+
+// Validate checks the field values on ProjectSSOConfig_AzureAD with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ProjectSSOConfig_AzureAD) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ProjectSSOConfig_AzureAD with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ProjectSSOConfig_AzureADMultiError, or nil if none found.
+func (m *ProjectSSOConfig_AzureAD) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ProjectSSOConfig_AzureAD) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetClientId()) < 1 {
+		err := ProjectSSOConfig_AzureADValidationError{
+			field:  "ClientId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetClientSecret()) < 1 {
+		err := ProjectSSOConfig_AzureADValidationError{
+			field:  "ClientSecret",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for Tenant
+
+	if utf8.RuneCountInString(m.GetRedirectUri()) < 1 {
+		err := ProjectSSOConfig_AzureADValidationError{
+			field:  "RedirectUri",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for ProxyUrl
+
+	if len(errors) > 0 {
+		return ProjectSSOConfig_AzureADMultiError(errors)
+	}
+
+	return nil
+}
+
+// ProjectSSOConfig_AzureADMultiError is an error wrapping multiple validation
+// errors returned by ProjectSSOConfig_AzureAD.ValidateAll() if the designated
+// constraints aren't met.
+type ProjectSSOConfig_AzureADMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ProjectSSOConfig_AzureADMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ProjectSSOConfig_AzureADMultiError) AllErrors() []error { return m }
+
+// ProjectSSOConfig_AzureADValidationError is the validation error returned by
+// ProjectSSOConfig_AzureAD.Validate if the designated constraints aren't met.
+type ProjectSSOConfig_AzureADValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProjectSSOConfig_AzureADValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProjectSSOConfig_AzureADValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProjectSSOConfig_AzureADValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProjectSSOConfig_AzureADValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProjectSSOConfig_AzureADValidationError) ErrorName() string {
+	return "ProjectSSOConfig_AzureADValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ProjectSSOConfig_AzureADValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProjectSSOConfig_AzureAD.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProjectSSOConfig_AzureADValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProjectSSOConfig_AzureADValidationError{}
+
+// Validate checks the field values on ProjectSSOConfig_Ldap with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ProjectSSOConfig_Ldap) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ProjectSSOConfig_Ldap with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ProjectSSOConfig_LdapMultiError, or nil if none found.
+func (m *ProjectSSOConfig_Ldap) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ProjectSSOConfig_Ldap) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetUrl()) < 1 {
+		err := ProjectSSOConfig_LdapValidationError{
+			field:  "Url",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_ProjectSSOConfig_Ldap_Url_Pattern.MatchString(m.GetUrl()) {
+		err := ProjectSSOConfig_LdapValidationError{
+			field:  "Url",
+			reason: "value does not match regex pattern \"^ldaps?://\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for StartTls
+
+	// no validation rules for RootCa
+
+	// no validation rules for BindDn
+
+	// no validation rules for BindPassword
+
+	if utf8.RuneCountInString(m.GetUserSearchBase()) < 1 {
+		err := ProjectSSOConfig_LdapValidationError{
+			field:  "UserSearchBase",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for UserFilter
+
+	// no validation rules for GroupSearchBase
+
+	// no validation rules for GroupFilter
+
+	// no validation rules for GroupNameAttribute
+
+	if m.GetPoolSize() < 0 {
+		err := ProjectSSOConfig_LdapValidationError{
+			field:  "PoolSize",
+			reason: "value must be greater than or equal to 0",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if len(errors) > 0 {
+		return ProjectSSOConfig_LdapMultiError(errors)
+	}
+
+	return nil
+}
+
+// ProjectSSOConfig_LdapMultiError is an error wrapping multiple validation
+// errors returned by ProjectSSOConfig_Ldap.ValidateAll() if the designated
+// constraints aren't met.
+type ProjectSSOConfig_LdapMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ProjectSSOConfig_LdapMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ProjectSSOConfig_LdapMultiError) AllErrors() []error { return m }
+
+// ProjectSSOConfig_LdapValidationError is the validation error returned by
+// ProjectSSOConfig_Ldap.Validate if the designated constraints aren't met.
+type ProjectSSOConfig_LdapValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProjectSSOConfig_LdapValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProjectSSOConfig_LdapValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProjectSSOConfig_LdapValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProjectSSOConfig_LdapValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProjectSSOConfig_LdapValidationError) ErrorName() string {
+	return "ProjectSSOConfig_LdapValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ProjectSSOConfig_LdapValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProjectSSOConfig_Ldap.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProjectSSOConfig_LdapValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProjectSSOConfig_LdapValidationError{}
+
+var _ProjectSSOConfig_Ldap_Url_Pattern = regexp.MustCompile("^ldaps?://")
+
+// Validate checks the field values on ProjectSSOConfig_Okta with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ProjectSSOConfig_Okta) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ProjectSSOConfig_Okta with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ProjectSSOConfig_OktaMultiError, or nil if none found.
+func (m *ProjectSSOConfig_Okta) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ProjectSSOConfig_Okta) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetClientId()) < 1 {
+		err := ProjectSSOConfig_OktaValidationError{
+			field:  "ClientId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetClientSecret()) < 1 {
+		err := ProjectSSOConfig_OktaValidationError{
+			field:  "ClientSecret",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetDomain()) < 1 {
+		err := ProjectSSOConfig_OktaValidationError{
+			field:  "Domain",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if !_ProjectSSOConfig_Okta_Domain_Pattern.MatchString(m.GetDomain()) {
+		err := ProjectSSOConfig_OktaValidationError{
+			field:  "Domain",
+			reason: "value does not match regex pattern \"^https://\"",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for AuthorizationServerId
+
+	if utf8.RuneCountInString(m.GetRedirectUri()) < 1 {
+		err := ProjectSSOConfig_OktaValidationError{
+			field:  "RedirectUri",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for GroupsClaim
+
+	// no validation rules for ProxyUrl
+
+	if len(errors) > 0 {
+		return ProjectSSOConfig_OktaMultiError(errors)
+	}
+
+	return nil
+}
+
+// ProjectSSOConfig_OktaMultiError is an error wrapping multiple validation
+// errors returned by ProjectSSOConfig_Okta.ValidateAll() if the designated
+// constraints aren't met.
+type ProjectSSOConfig_OktaMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ProjectSSOConfig_OktaMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ProjectSSOConfig_OktaMultiError) AllErrors() []error { return m }
+
+// ProjectSSOConfig_OktaValidationError is the validation error returned by
+// ProjectSSOConfig_Okta.Validate if the designated constraints aren't met.
+type ProjectSSOConfig_OktaValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProjectSSOConfig_OktaValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProjectSSOConfig_OktaValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProjectSSOConfig_OktaValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProjectSSOConfig_OktaValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProjectSSOConfig_OktaValidationError) ErrorName() string {
+	return "ProjectSSOConfig_OktaValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ProjectSSOConfig_OktaValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProjectSSOConfig_Okta.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProjectSSOConfig_OktaValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProjectSSOConfig_OktaValidationError{}
+
+var _ProjectSSOConfig_Okta_Domain_Pattern = regexp.MustCompile("^https://")
+
+// Validate checks the field values on ProjectSSOConfig_Bitbucket with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ProjectSSOConfig_Bitbucket) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ProjectSSOConfig_Bitbucket with the
+// rules defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ProjectSSOConfig_BitbucketMultiError, or nil if none found.
+func (m *ProjectSSOConfig_Bitbucket) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ProjectSSOConfig_Bitbucket) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	if utf8.RuneCountInString(m.GetClientId()) < 1 {
+		err := ProjectSSOConfig_BitbucketValidationError{
+			field:  "ClientId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if utf8.RuneCountInString(m.GetClientSecret()) < 1 {
+		err := ProjectSSOConfig_BitbucketValidationError{
+			field:  "ClientSecret",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	if _, ok := ProjectSSOConfig_Bitbucket_Flavor_name[int32(m.GetFlavor())]; !ok {
+		err := ProjectSSOConfig_BitbucketValidationError{
+			field:  "Flavor",
+			reason: "value must be one of the defined enum values",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for BaseUrl
+
+	if utf8.RuneCountInString(m.GetRedirectUri()) < 1 {
+		err := ProjectSSOConfig_BitbucketValidationError{
+			field:  "RedirectUri",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for ProxyUrl
+
+	if len(errors) > 0 {
+		return ProjectSSOConfig_BitbucketMultiError(errors)
+	}
+
+	return nil
+}
+
+// ProjectSSOConfig_BitbucketMultiError is an error wrapping multiple
+// validation errors returned by ProjectSSOConfig_Bitbucket.ValidateAll() if
+// the designated constraints aren't met.
+type ProjectSSOConfig_BitbucketMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ProjectSSOConfig_BitbucketMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ProjectSSOConfig_BitbucketMultiError) AllErrors() []error { return m }
+
+// ProjectSSOConfig_BitbucketValidationError is the validation error returned
+// by ProjectSSOConfig_Bitbucket.Validate if the designated constraints aren't met.
+type ProjectSSOConfig_BitbucketValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProjectSSOConfig_BitbucketValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProjectSSOConfig_BitbucketValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProjectSSOConfig_BitbucketValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProjectSSOConfig_BitbucketValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProjectSSOConfig_BitbucketValidationError) ErrorName() string {
+	return "ProjectSSOConfig_BitbucketValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ProjectSSOConfig_BitbucketValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProjectSSOConfig_Bitbucket.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProjectSSOConfig_BitbucketValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProjectSSOConfig_BitbucketValidationError{}
+
+// Validate checks the field values on ProjectSSOConfig_Saml with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the first error encountered is returned, or nil if there are no violations.
+func (m *ProjectSSOConfig_Saml) Validate() error {
+	return m.validate(false)
+}
+
+// ValidateAll checks the field values on ProjectSSOConfig_Saml with the rules
+// defined in the proto definition for this message. If any rules are
+// violated, the result is a list of violation errors wrapped in
+// ProjectSSOConfig_SamlMultiError, or nil if none found.
+func (m *ProjectSSOConfig_Saml) ValidateAll() error {
+	return m.validate(true)
+}
+
+func (m *ProjectSSOConfig_Saml) validate(all bool) error {
+	if m == nil {
+		return nil
+	}
+
+	var errors []error
+
+	// no validation rules for IdpMetadataUrl
+
+	// no validation rules for IdpMetadata
+
+	if utf8.RuneCountInString(m.GetEntityId()) < 1 {
+		err := ProjectSSOConfig_SamlValidationError{
+			field:  "EntityId",
+			reason: "value length must be at least 1 runes",
+		}
+		if !all {
+			return err
+		}
+		errors = append(errors, err)
+	}
+
+	// no validation rules for UsernameAttribute
+
+	// no validation rules for AvatarUrlAttribute
+
+	// no validation rules for GroupsAttribute
+
+	// no validation rules for Certificate
+
+	// no validation rules for PrivateKey
+
+	if len(errors) > 0 {
+		return ProjectSSOConfig_SamlMultiError(errors)
+	}
+
+	return nil
+}
+
+// ProjectSSOConfig_SamlMultiError is an error wrapping multiple validation
+// errors returned by ProjectSSOConfig_Saml.ValidateAll() if the designated
+// constraints aren't met.
+type ProjectSSOConfig_SamlMultiError []error
+
+// Error returns a concatenation of all the error messages it wraps.
+func (m ProjectSSOConfig_SamlMultiError) Error() string {
+	var msgs []string
+	for _, err := range m {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// AllErrors returns a list of validation violation errors.
+func (m ProjectSSOConfig_SamlMultiError) AllErrors() []error { return m }
+
+// ProjectSSOConfig_SamlValidationError is the validation error returned by
+// ProjectSSOConfig_Saml.Validate if the designated constraints aren't met.
+type ProjectSSOConfig_SamlValidationError struct {
+	field  string
+	reason string
+	cause  error
+	key    bool
+}
+
+// Field function returns field value.
+func (e ProjectSSOConfig_SamlValidationError) Field() string { return e.field }
+
+// Reason function returns reason value.
+func (e ProjectSSOConfig_SamlValidationError) Reason() string { return e.reason }
+
+// Cause function returns cause value.
+func (e ProjectSSOConfig_SamlValidationError) Cause() error { return e.cause }
+
+// Key function returns key value.
+func (e ProjectSSOConfig_SamlValidationError) Key() bool { return e.key }
+
+// ErrorName returns error name.
+func (e ProjectSSOConfig_SamlValidationError) ErrorName() string {
+	return "ProjectSSOConfig_SamlValidationError"
+}
+
+// Error satisfies the builtin error interface
+func (e ProjectSSOConfig_SamlValidationError) Error() string {
+	cause := ""
+	if e.cause != nil {
+		cause = fmt.Sprintf(" | caused by: %v", e.cause)
+	}
+
+	key := ""
+	if e.key {
+		key = "key for "
+	}
+
+	return fmt.Sprintf(
+		"invalid %sProjectSSOConfig_Saml.%s: %s%s",
+		key,
+		e.field,
+		e.reason,
+		cause)
+}
+
+var _ error = ProjectSSOConfig_SamlValidationError{}
+
+var _ interface {
+	Field() string
+	Reason() string
+	Key() bool
+	Cause() error
+	ErrorName() string
+} = ProjectSSOConfig_SamlValidationError{}
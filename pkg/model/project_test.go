@@ -15,10 +15,13 @@
 package model
 
 import (
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
+	"google.golang.org/protobuf/proto"
 )
 
 type mockEncrypter struct {
@@ -66,6 +69,262 @@ func TestRedactSensitiveData(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "redact github app private key",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Github: &ProjectSSOConfig_GitHub{
+						AppId:             1,
+						AppInstallationId: 2,
+						AppPrivateKey:     "raw",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Github: &ProjectSSOConfig_GitHub{
+						ClientId:          "redacted",
+						ClientSecret:      "redacted",
+						AppId:             1,
+						AppInstallationId: 2,
+						AppPrivateKey:     "redacted",
+					},
+				},
+			},
+		},
+		{
+			name: "redact oidc client key",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Oidc: &ProjectSSOConfig_Oidc{
+						ClientCertificate: "cert",
+						ClientKey:         "raw",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Oidc: &ProjectSSOConfig_Oidc{
+						ClientCertificate: "cert",
+						ClientKey:         "redacted",
+					},
+				},
+			},
+		},
+		{
+			name: "redact oidc client assertion key",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Oidc: &ProjectSSOConfig_Oidc{
+						ClientAssertionKey:              "raw",
+						ClientAssertionSigningAlgorithm: "ES256",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Oidc: &ProjectSSOConfig_Oidc{
+						ClientAssertionKey:              "redacted",
+						ClientAssertionSigningAlgorithm: "ES256",
+					},
+				},
+			},
+		},
+		{
+			name: "redact extra headers",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Github: &ProjectSSOConfig_GitHub{
+						ExtraHeaders: map[string]string{"X-Api-Key": "raw"},
+					},
+					Oidc: &ProjectSSOConfig_Oidc{
+						ExtraHeaders: map[string]string{"X-Api-Key": "raw"},
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Github: &ProjectSSOConfig_GitHub{
+						ClientId:     "redacted",
+						ClientSecret: "redacted",
+						ExtraHeaders: map[string]string{"X-Api-Key": "redacted"},
+					},
+					Oidc: &ProjectSSOConfig_Oidc{
+						ExtraHeaders: map[string]string{"X-Api-Key": "redacted"},
+					},
+				},
+			},
+		},
+		{
+			name: "redact gitlab",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Gitlab: &ProjectSSOConfig_GitLab{
+						ClientId:     "raw",
+						ClientSecret: "raw",
+						BaseUrl:      "https://gitlab.example.com",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Gitlab: &ProjectSSOConfig_GitLab{
+						ClientId:     "redacted",
+						ClientSecret: "redacted",
+						BaseUrl:      "https://gitlab.example.com",
+					},
+				},
+			},
+		},
+		{
+			name: "redact google",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Google: &ProjectSSOConfig_Google{
+						ClientId:     "raw",
+						ClientSecret: "raw",
+						RedirectUri:  "https://pipecd.example.com/auth/callback",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Google: &ProjectSSOConfig_Google{
+						ClientId:     "redacted",
+						ClientSecret: "redacted",
+						RedirectUri:  "https://pipecd.example.com/auth/callback",
+					},
+				},
+			},
+		},
+		{
+			name: "redact azure ad",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					AzureAd: &ProjectSSOConfig_AzureAD{
+						ClientId:     "raw",
+						ClientSecret: "raw",
+						Tenant:       "tenant-id",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					AzureAd: &ProjectSSOConfig_AzureAD{
+						ClientId:     "redacted",
+						ClientSecret: "redacted",
+						Tenant:       "tenant-id",
+					},
+				},
+			},
+		},
+		{
+			name: "redact ldap",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Ldap: &ProjectSSOConfig_Ldap{
+						BindPassword: "raw",
+						Url:          "ldaps://ldap.example.com",
+						BindDn:       "cn=pipecd,dc=example,dc=com",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Ldap: &ProjectSSOConfig_Ldap{
+						BindPassword: "redacted",
+						Url:          "ldaps://ldap.example.com",
+						BindDn:       "cn=pipecd,dc=example,dc=com",
+					},
+				},
+			},
+		},
+		{
+			name: "redact saml",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Saml: &ProjectSSOConfig_Saml{
+						PrivateKey:  "raw",
+						EntityId:    "https://pipecd.example.com/saml",
+						Certificate: "certificate",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Saml: &ProjectSSOConfig_Saml{
+						PrivateKey:  "redacted",
+						EntityId:    "https://pipecd.example.com/saml",
+						Certificate: "certificate",
+					},
+				},
+			},
+		},
+		{
+			name: "redact okta",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Okta: &ProjectSSOConfig_Okta{
+						ClientId:     "raw",
+						ClientSecret: "raw",
+						Domain:       "https://example.okta.com",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Okta: &ProjectSSOConfig_Okta{
+						ClientId:     "redacted",
+						ClientSecret: "redacted",
+						Domain:       "https://example.okta.com",
+					},
+				},
+			},
+		},
+		{
+			name: "redact bitbucket",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Bitbucket: &ProjectSSOConfig_Bitbucket{
+						ClientId:     "raw",
+						ClientSecret: "raw",
+						Flavor:       ProjectSSOConfig_Bitbucket_SERVER,
+						BaseUrl:      "https://bitbucket.example.com",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Bitbucket: &ProjectSSOConfig_Bitbucket{
+						ClientId:     "redacted",
+						ClientSecret: "redacted",
+						Flavor:       ProjectSSOConfig_Bitbucket_SERVER,
+						BaseUrl:      "https://bitbucket.example.com",
+					},
+				},
+			},
+		},
+		{
+			name: "redact oidc client secret",
+			project: &Project{
+				Sso: &ProjectSSOConfig{
+					Oidc: &ProjectSSOConfig_Oidc{
+						ClientId:     "client-id",
+						ClientSecret: "raw",
+						Issuer:       "https://accounts.example.com",
+					},
+				},
+			},
+			expect: &Project{
+				Sso: &ProjectSSOConfig{
+					Oidc: &ProjectSSOConfig_Oidc{
+						ClientId:     "client-id",
+						ClientSecret: "redacted",
+						Issuer:       "https://accounts.example.com",
+					},
+				},
+			},
+		},
 	}
 
 	for _, tc := range cases {
@@ -106,9 +365,10 @@ func TestUpdateProjectStaticUser(t *testing.T) {
 
 func TestUpdateProjectSSOConfig(t *testing.T) {
 	cases := []struct {
-		name   string
-		sso    *ProjectSSOConfig
-		expect *ProjectSSOConfig
+		name    string
+		current *ProjectSSOConfig
+		sso     *ProjectSSOConfig
+		expect  *ProjectSSOConfig
 	}{
 		{
 			name: "update",
@@ -133,266 +393,1480 @@ func TestUpdateProjectSSOConfig(t *testing.T) {
 				Google: nil,
 			},
 		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			p := &ProjectSSOConfig{}
-			p.Update(tc.sso)
-			assert.Equal(t, tc.expect, p)
-		})
-	}
-}
-
-func TestEncrypt(t *testing.T) {
-	cases := []struct {
-		name   string
-		sso    *ProjectSSOConfig
-		expect *ProjectSSOConfig
-	}{
 		{
-			name: "encrypt",
+			name: "update extra headers",
 			sso: &ProjectSSOConfig{
 				Provider: ProjectSSOConfig_GITHUB,
 				Github: &ProjectSSOConfig_GitHub{
-					ClientId:     "client-id",
-					ClientSecret: "client-secret",
-					BaseUrl:      "base-url",
-					UploadUrl:    "upload-url",
+					ExtraHeaders: map[string]string{"X-Api-Key": "key"},
 				},
-				Google: nil,
 			},
 			expect: &ProjectSSOConfig{
 				Provider: ProjectSSOConfig_GITHUB,
 				Github: &ProjectSSOConfig_GitHub{
-					ClientId:     "encrypted-client-id",
-					ClientSecret: "encrypted-client-secret",
-					BaseUrl:      "base-url",
-					UploadUrl:    "upload-url",
+					ExtraHeaders: map[string]string{"X-Api-Key": "key"},
 				},
-				Google: nil,
 			},
 		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			e := &mockEncrypter{}
-			err := tc.sso.Encrypt(e)
-			assert.NoError(t, err)
-			assert.Equal(t, tc.expect, tc.sso)
-		})
-	}
-}
-
-func TestDecrypt(t *testing.T) {
-	cases := []struct {
-		name   string
-		sso    *ProjectSSOConfig
-		expect *ProjectSSOConfig
-	}{
 		{
-			name: "decrypt",
-			sso: &ProjectSSOConfig{
+			name: "update required orgs",
+			current: &ProjectSSOConfig{
 				Provider: ProjectSSOConfig_GITHUB,
 				Github: &ProjectSSOConfig_GitHub{
 					ClientId:     "client-id",
-					ClientSecret: "client-secret",
-					BaseUrl:      "base-url",
-					UploadUrl:    "upload-url",
+					RequiredOrgs: []string{"old-org"},
+				},
+			},
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITHUB,
+				Github: &ProjectSSOConfig_GitHub{
+					RequiredOrgs: []string{"org-a", "org-b"},
 				},
-				Google: nil,
 			},
 			expect: &ProjectSSOConfig{
 				Provider: ProjectSSOConfig_GITHUB,
 				Github: &ProjectSSOConfig_GitHub{
-					ClientId:     "decrypted-client-id",
-					ClientSecret: "decrypted-client-secret",
-					BaseUrl:      "base-url",
-					UploadUrl:    "upload-url",
+					ClientId:     "client-id",
+					RequiredOrgs: []string{"org-a", "org-b"},
 				},
-				Google: nil,
 			},
 		},
-	}
-
-	for _, tc := range cases {
-		t.Run(tc.name, func(t *testing.T) {
-			d := &mockDecrypter{}
-			err := tc.sso.Decrypt(d)
-			assert.NoError(t, err)
-			assert.Equal(t, tc.expect, tc.sso)
-		})
-	}
-}
-
-func TestProject_HasRBACRole(t *testing.T) {
-	roles := []*ProjectRBACRole{
 		{
-			Name: "test",
-			Policies: []*ProjectRBACPolicy{
-				{
-					Resources: []*ProjectRBACResource{
+			name: "update display name and icon",
+			sso: &ProjectSSOConfig{
+				Provider:    ProjectSSOConfig_OIDC,
+				DisplayName: "Corporate SSO",
+				Icon:        "/assets/sso.png",
+			},
+			expect: &ProjectSSOConfig{
+				Provider:    ProjectSSOConfig_OIDC,
+				DisplayName: "Corporate SSO",
+				Icon:        "/assets/sso.png",
+			},
+		},
+		{
+			name: "update oidc",
+			current: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Issuer:       "https://old.example.com",
+					Scopes:       []string{"openid", "groups"},
+				},
+			},
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId: "updated-client-id",
+					Issuer:   "https://new.example.com",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:     "updated-client-id",
+					ClientSecret: "client-secret",
+					Issuer:       "https://new.example.com",
+				},
+			},
+		},
+		{
+			name: "update gitlab",
+			current: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITLAB,
+				Gitlab: &ProjectSSOConfig_GitLab{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					BaseUrl:      "https://old.example.com",
+				},
+			},
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITLAB,
+				Gitlab: &ProjectSSOConfig_GitLab{
+					BaseUrl:     "https://new.example.com",
+					RedirectUri: "https://pipecd.example.com/auth/callback",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITLAB,
+				Gitlab: &ProjectSSOConfig_GitLab{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					BaseUrl:      "https://new.example.com",
+					RedirectUri:  "https://pipecd.example.com/auth/callback",
+				},
+			},
+		},
+		{
+			name: "update google",
+			current: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GOOGLE,
+				Google: &ProjectSSOConfig_Google{
+					ClientId:       "client-id",
+					ClientSecret:   "client-secret",
+					RedirectUri:    "https://pipecd.example.com/auth/callback",
+					AllowedDomains: []string{"old.example.com"},
+				},
+			},
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GOOGLE,
+				Google: &ProjectSSOConfig_Google{
+					RedirectUri:    "https://pipecd.example.com/auth/callback",
+					AllowedDomains: []string{"new.example.com"},
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GOOGLE,
+				Google: &ProjectSSOConfig_Google{
+					ClientId:       "client-id",
+					ClientSecret:   "client-secret",
+					RedirectUri:    "https://pipecd.example.com/auth/callback",
+					AllowedDomains: []string{"new.example.com"},
+				},
+			},
+		},
+		{
+			name: "update azure ad",
+			current: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_AZUREAD,
+				AzureAd: &ProjectSSOConfig_AzureAD{
+					ClientId:       "client-id",
+					ClientSecret:   "client-secret",
+					Tenant:         "old-tenant-id",
+					AllowedTenants: []string{"old-tenant-id"},
+				},
+			},
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_AZUREAD,
+				AzureAd: &ProjectSSOConfig_AzureAD{
+					Tenant: "new-tenant-id",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_AZUREAD,
+				AzureAd: &ProjectSSOConfig_AzureAD{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Tenant:       "new-tenant-id",
+				},
+			},
+		},
+		{
+			name: "update ldap",
+			current: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_LDAP,
+				Ldap: &ProjectSSOConfig_Ldap{
+					BindPassword:   "bind-password",
+					Url:            "ldaps://ldap.example.com",
+					StartTls:       true,
+					UserSearchBase: "ou=people,dc=example,dc=com",
+				},
+			},
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_LDAP,
+				Ldap: &ProjectSSOConfig_Ldap{
+					Url:            "ldap://ldap.example.com",
+					UserSearchBase: "ou=users,dc=example,dc=com",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_LDAP,
+				Ldap: &ProjectSSOConfig_Ldap{
+					BindPassword:   "bind-password",
+					Url:            "ldap://ldap.example.com",
+					UserSearchBase: "ou=users,dc=example,dc=com",
+				},
+			},
+		},
+		{
+			name: "update saml",
+			current: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_SAML,
+				Saml: &ProjectSSOConfig_Saml{
+					PrivateKey:     "private-key",
+					IdpMetadataUrl: "https://idp.example.com/metadata",
+					EntityId:       "https://pipecd.example.com/saml",
+					Certificate:    "certificate",
+				},
+			},
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_SAML,
+				Saml: &ProjectSSOConfig_Saml{
+					IdpMetadata: "<EntityDescriptor/>",
+					EntityId:    "https://pipecd.example.com/saml",
+					Certificate: "certificate",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_SAML,
+				Saml: &ProjectSSOConfig_Saml{
+					PrivateKey:  "private-key",
+					IdpMetadata: "<EntityDescriptor/>",
+					EntityId:    "https://pipecd.example.com/saml",
+					Certificate: "certificate",
+				},
+			},
+		},
+		{
+			name: "update okta",
+			current: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OKTA,
+				Okta: &ProjectSSOConfig_Okta{
+					ClientId:              "client-id",
+					ClientSecret:          "client-secret",
+					Domain:                "https://example.okta.com",
+					AuthorizationServerId: "default",
+					GroupsClaim:           "groups",
+				},
+			},
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OKTA,
+				Okta: &ProjectSSOConfig_Okta{
+					Domain:      "https://example.okta.com",
+					GroupsClaim: "roles",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OKTA,
+				Okta: &ProjectSSOConfig_Okta{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Domain:       "https://example.okta.com",
+					GroupsClaim:  "roles",
+				},
+			},
+		},
+		{
+			name: "update bitbucket",
+			current: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_BITBUCKET,
+				Bitbucket: &ProjectSSOConfig_Bitbucket{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Flavor:       ProjectSSOConfig_Bitbucket_SERVER,
+					BaseUrl:      "https://bitbucket.example.com",
+				},
+			},
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_BITBUCKET,
+				Bitbucket: &ProjectSSOConfig_Bitbucket{
+					Flavor: ProjectSSOConfig_Bitbucket_CLOUD,
+					Scopes: []string{"account"},
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_BITBUCKET,
+				Bitbucket: &ProjectSSOConfig_Bitbucket{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Flavor:       ProjectSSOConfig_Bitbucket_CLOUD,
+					Scopes:       []string{"account"},
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := tc.current
+			if p == nil {
+				p = &ProjectSSOConfig{}
+			}
+			err := p.Update(tc.sso)
+			assert.NoError(t, err)
+			assert.True(t, proto.Equal(tc.expect, p), "expected %v, got %v", tc.expect, p)
+		})
+	}
+}
+
+func TestEncrypt(t *testing.T) {
+	cases := []struct {
+		name   string
+		sso    *ProjectSSOConfig
+		expect *ProjectSSOConfig
+	}{
+		{
+			name: "encrypt",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITHUB,
+				Github: &ProjectSSOConfig_GitHub{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					BaseUrl:      "base-url",
+					UploadUrl:    "upload-url",
+				},
+				Google: nil,
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITHUB,
+				Github: &ProjectSSOConfig_GitHub{
+					ClientId:     "encrypted-client-id",
+					ClientSecret: "encrypted-client-secret",
+					BaseUrl:      "base-url",
+					UploadUrl:    "upload-url",
+				},
+				Google: nil,
+			},
+		},
+		{
+			name: "encrypt github app private key",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITHUB,
+				Github: &ProjectSSOConfig_GitHub{
+					ClientId:          "client-id",
+					AppId:             1,
+					AppInstallationId: 2,
+					AppPrivateKey:     "app-private-key",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITHUB,
+				Github: &ProjectSSOConfig_GitHub{
+					ClientId:          "encrypted-client-id",
+					AppId:             1,
+					AppInstallationId: 2,
+					AppPrivateKey:     "encrypted-app-private-key",
+				},
+			},
+		},
+		{
+			name: "encrypt oidc client key",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:          "client-id",
+					ClientCertificate: "client-certificate",
+					ClientKey:         "client-key",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:          "client-id",
+					ClientCertificate: "client-certificate",
+					ClientKey:         "encrypted-client-key",
+				},
+			},
+		},
+		{
+			name: "encrypt oidc client assertion key",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:           "client-id",
+					ClientAssertionKey: "client-assertion-key",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:           "client-id",
+					ClientAssertionKey: "encrypted-client-assertion-key",
+				},
+			},
+		},
+		{
+			name: "encrypt extra headers",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Github: &ProjectSSOConfig_GitHub{
+					ExtraHeaders: map[string]string{"X-Api-Key": "github-key"},
+				},
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:     "client-id",
+					ExtraHeaders: map[string]string{"X-Api-Key": "oidc-key", "X-Empty": ""},
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Github: &ProjectSSOConfig_GitHub{
+					ExtraHeaders: map[string]string{"X-Api-Key": "encrypted-github-key"},
+				},
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:     "client-id",
+					ExtraHeaders: map[string]string{"X-Api-Key": "encrypted-oidc-key", "X-Empty": ""},
+				},
+			},
+		},
+		{
+			name: "encrypt oidc client secret",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:     "client-id",
+					ClientSecret: "encrypted-client-secret",
+				},
+			},
+		},
+		{
+			name: "encrypt gitlab",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITLAB,
+				Gitlab: &ProjectSSOConfig_GitLab{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					BaseUrl:      "base-url",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITLAB,
+				Gitlab: &ProjectSSOConfig_GitLab{
+					ClientId:     "encrypted-client-id",
+					ClientSecret: "encrypted-client-secret",
+					BaseUrl:      "base-url",
+				},
+			},
+		},
+		{
+			name: "encrypt google",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GOOGLE,
+				Google: &ProjectSSOConfig_Google{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					RedirectUri:  "https://pipecd.example.com/auth/callback",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GOOGLE,
+				Google: &ProjectSSOConfig_Google{
+					ClientId:     "encrypted-client-id",
+					ClientSecret: "encrypted-client-secret",
+					RedirectUri:  "https://pipecd.example.com/auth/callback",
+				},
+			},
+		},
+		{
+			name: "encrypt azure ad",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_AZUREAD,
+				AzureAd: &ProjectSSOConfig_AzureAD{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Tenant:       "tenant-id",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_AZUREAD,
+				AzureAd: &ProjectSSOConfig_AzureAD{
+					ClientId:     "encrypted-client-id",
+					ClientSecret: "encrypted-client-secret",
+					Tenant:       "tenant-id",
+				},
+			},
+		},
+		{
+			name: "encrypt ldap",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_LDAP,
+				Ldap: &ProjectSSOConfig_Ldap{
+					BindPassword: "bind-password",
+					Url:          "ldaps://ldap.example.com",
+					BindDn:       "cn=pipecd,dc=example,dc=com",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_LDAP,
+				Ldap: &ProjectSSOConfig_Ldap{
+					BindPassword: "encrypted-bind-password",
+					Url:          "ldaps://ldap.example.com",
+					BindDn:       "cn=pipecd,dc=example,dc=com",
+				},
+			},
+		},
+		{
+			name: "encrypt saml",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_SAML,
+				Saml: &ProjectSSOConfig_Saml{
+					PrivateKey:  "private-key",
+					EntityId:    "https://pipecd.example.com/saml",
+					Certificate: "certificate",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_SAML,
+				Saml: &ProjectSSOConfig_Saml{
+					PrivateKey:  "encrypted-private-key",
+					EntityId:    "https://pipecd.example.com/saml",
+					Certificate: "certificate",
+				},
+			},
+		},
+		{
+			name: "encrypt okta",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OKTA,
+				Okta: &ProjectSSOConfig_Okta{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Domain:       "https://example.okta.com",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OKTA,
+				Okta: &ProjectSSOConfig_Okta{
+					ClientId:     "encrypted-client-id",
+					ClientSecret: "encrypted-client-secret",
+					Domain:       "https://example.okta.com",
+				},
+			},
+		},
+		{
+			name: "encrypt bitbucket",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_BITBUCKET,
+				Bitbucket: &ProjectSSOConfig_Bitbucket{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Flavor:       ProjectSSOConfig_Bitbucket_SERVER,
+					BaseUrl:      "https://bitbucket.example.com",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_BITBUCKET,
+				Bitbucket: &ProjectSSOConfig_Bitbucket{
+					ClientId:     "encrypted-client-id",
+					ClientSecret: "encrypted-client-secret",
+					Flavor:       ProjectSSOConfig_Bitbucket_SERVER,
+					BaseUrl:      "https://bitbucket.example.com",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			e := &mockEncrypter{}
+			err := tc.sso.Encrypt(e)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, tc.sso)
+		})
+	}
+}
+
+func TestDecrypt(t *testing.T) {
+	cases := []struct {
+		name   string
+		sso    *ProjectSSOConfig
+		expect *ProjectSSOConfig
+	}{
+		{
+			name: "decrypt",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITHUB,
+				Github: &ProjectSSOConfig_GitHub{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					BaseUrl:      "base-url",
+					UploadUrl:    "upload-url",
+				},
+				Google: nil,
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITHUB,
+				Github: &ProjectSSOConfig_GitHub{
+					ClientId:     "decrypted-client-id",
+					ClientSecret: "decrypted-client-secret",
+					BaseUrl:      "base-url",
+					UploadUrl:    "upload-url",
+				},
+				Google: nil,
+			},
+		},
+		{
+			name: "decrypt oidc client key",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:          "client-id",
+					ClientCertificate: "client-certificate",
+					ClientKey:         "client-key",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:          "client-id",
+					ClientCertificate: "client-certificate",
+					ClientKey:         "decrypted-client-key",
+				},
+			},
+		},
+		{
+			name: "decrypt oidc client assertion key",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:           "client-id",
+					ClientAssertionKey: "client-assertion-key",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:           "client-id",
+					ClientAssertionKey: "decrypted-client-assertion-key",
+				},
+			},
+		},
+		{
+			name: "decrypt extra headers",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Github: &ProjectSSOConfig_GitHub{
+					ExtraHeaders: map[string]string{"X-Api-Key": "github-key"},
+				},
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:     "client-id",
+					ExtraHeaders: map[string]string{"X-Api-Key": "oidc-key", "X-Empty": ""},
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Github: &ProjectSSOConfig_GitHub{
+					ExtraHeaders: map[string]string{"X-Api-Key": "decrypted-github-key"},
+				},
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:     "client-id",
+					ExtraHeaders: map[string]string{"X-Api-Key": "decrypted-oidc-key", "X-Empty": ""},
+				},
+			},
+		},
+		{
+			name: "decrypt oidc client secret",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OIDC,
+				Oidc: &ProjectSSOConfig_Oidc{
+					ClientId:     "client-id",
+					ClientSecret: "decrypted-client-secret",
+				},
+			},
+		},
+		{
+			name: "decrypt gitlab",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITLAB,
+				Gitlab: &ProjectSSOConfig_GitLab{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					BaseUrl:      "base-url",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITLAB,
+				Gitlab: &ProjectSSOConfig_GitLab{
+					ClientId:     "decrypted-client-id",
+					ClientSecret: "decrypted-client-secret",
+					BaseUrl:      "base-url",
+				},
+			},
+		},
+		{
+			name: "decrypt google",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GOOGLE,
+				Google: &ProjectSSOConfig_Google{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					RedirectUri:  "https://pipecd.example.com/auth/callback",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GOOGLE,
+				Google: &ProjectSSOConfig_Google{
+					ClientId:     "decrypted-client-id",
+					ClientSecret: "decrypted-client-secret",
+					RedirectUri:  "https://pipecd.example.com/auth/callback",
+				},
+			},
+		},
+		{
+			name: "decrypt azure ad",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_AZUREAD,
+				AzureAd: &ProjectSSOConfig_AzureAD{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Tenant:       "tenant-id",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_AZUREAD,
+				AzureAd: &ProjectSSOConfig_AzureAD{
+					ClientId:     "decrypted-client-id",
+					ClientSecret: "decrypted-client-secret",
+					Tenant:       "tenant-id",
+				},
+			},
+		},
+		{
+			name: "decrypt ldap",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_LDAP,
+				Ldap: &ProjectSSOConfig_Ldap{
+					BindPassword: "bind-password",
+					Url:          "ldaps://ldap.example.com",
+					BindDn:       "cn=pipecd,dc=example,dc=com",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_LDAP,
+				Ldap: &ProjectSSOConfig_Ldap{
+					BindPassword: "decrypted-bind-password",
+					Url:          "ldaps://ldap.example.com",
+					BindDn:       "cn=pipecd,dc=example,dc=com",
+				},
+			},
+		},
+		{
+			name: "decrypt saml",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_SAML,
+				Saml: &ProjectSSOConfig_Saml{
+					PrivateKey:  "private-key",
+					EntityId:    "https://pipecd.example.com/saml",
+					Certificate: "certificate",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_SAML,
+				Saml: &ProjectSSOConfig_Saml{
+					PrivateKey:  "decrypted-private-key",
+					EntityId:    "https://pipecd.example.com/saml",
+					Certificate: "certificate",
+				},
+			},
+		},
+		{
+			name: "decrypt okta",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OKTA,
+				Okta: &ProjectSSOConfig_Okta{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Domain:       "https://example.okta.com",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OKTA,
+				Okta: &ProjectSSOConfig_Okta{
+					ClientId:     "decrypted-client-id",
+					ClientSecret: "decrypted-client-secret",
+					Domain:       "https://example.okta.com",
+				},
+			},
+		},
+		{
+			name: "decrypt bitbucket",
+			sso: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_BITBUCKET,
+				Bitbucket: &ProjectSSOConfig_Bitbucket{
+					ClientId:     "client-id",
+					ClientSecret: "client-secret",
+					Flavor:       ProjectSSOConfig_Bitbucket_SERVER,
+					BaseUrl:      "https://bitbucket.example.com",
+				},
+			},
+			expect: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_BITBUCKET,
+				Bitbucket: &ProjectSSOConfig_Bitbucket{
+					ClientId:     "decrypted-client-id",
+					ClientSecret: "decrypted-client-secret",
+					Flavor:       ProjectSSOConfig_Bitbucket_SERVER,
+					BaseUrl:      "https://bitbucket.example.com",
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			d := &mockDecrypter{}
+			err := tc.sso.Decrypt(d)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expect, tc.sso)
+		})
+	}
+}
+
+func TestProject_HasRBACRole(t *testing.T) {
+	roles := []*ProjectRBACRole{
+		{
+			Name: "test",
+			Policies: []*ProjectRBACPolicy{
+				{
+					Resources: []*ProjectRBACResource{
 						{
 							Type: ProjectRBACResource_APPLICATION,
 						},
 					},
-					Actions: []ProjectRBACPolicy_Action{
-						ProjectRBACPolicy_GET,
+					Actions: []ProjectRBACPolicy_Action{
+						ProjectRBACPolicy_GET,
+					},
+				},
+			},
+		},
+	}
+	p := &Project{RbacRoles: roles}
+
+	// True
+	assert.True(t, p.HasRBACRole("test"))
+
+	// False
+	assert.False(t, p.HasRBACRole("foo"))
+}
+
+func TestProject_HasUserGroup(t *testing.T) {
+	groups := []*ProjectUserGroup{
+		{
+			SsoGroup: "team/tester",
+			Role:     "Tester",
+		},
+	}
+	rbac := &ProjectRBACConfig{
+		Admin:  "team/admin",
+		Editor: "team/editor",
+		Viewer: "team/viewer",
+	}
+	p := &Project{
+		UserGroups: groups,
+		Rbac:       rbac,
+	}
+
+	// True
+	assert.True(t, p.HasUserGroup("team/tester"))
+	assert.True(t, p.HasUserGroup("team/admin"))
+
+	// False
+	assert.False(t, p.HasUserGroup("team/foo"))
+}
+
+func TestProject_StrayRoles(t *testing.T) {
+	assert.Nil(t, (&Project{}).StrayRoles())
+	assert.Equal(t, []string{"Viewer"}, (&Project{AllowStrayAsViewer: true}).StrayRoles())
+	assert.Equal(t, []string{"Editor"}, (&Project{DefaultRole: "Editor"}).StrayRoles())
+	assert.Equal(t, []string{"Editor"}, (&Project{DefaultRole: "Editor", AllowStrayAsViewer: true}).StrayRoles())
+}
+
+func TestProject_AssignStrayRoles(t *testing.T) {
+	role := &Role{ProjectId: "project"}
+	assert.False(t, (&Project{}).AssignStrayRoles(role))
+	assert.Nil(t, role.ProjectRbacRoles)
+
+	assert.True(t, (&Project{DefaultRole: "Editor", AllowStrayAsViewer: true}).AssignStrayRoles(role))
+	assert.Equal(t, []string{"Editor"}, role.ProjectRbacRoles)
+	assert.Equal(t, "project", role.ProjectId)
+}
+
+func TestProject_ValidateDefaultRole(t *testing.T) {
+	assert.NoError(t, (&Project{}).ValidateDefaultRole())
+	assert.NoError(t, (&Project{DefaultRole: "Viewer"}).ValidateDefaultRole())
+	p := &Project{
+		DefaultRole: "Auditor",
+		RbacRoles:   []*ProjectRBACRole{{Name: "Auditor"}},
+	}
+	assert.NoError(t, p.ValidateDefaultRole())
+	assert.Error(t, (&Project{DefaultRole: "Auditor"}).ValidateDefaultRole())
+	assert.Error(t, (&Project{DefaultRole: "viewer"}).ValidateDefaultRole())
+}
+
+func TestProject_RolesByEmailDomain(t *testing.T) {
+	p := &Project{
+		EmailDomainRoles: []*ProjectEmailDomainRole{
+			{Domain: "admin.example.com", Role: "Admin"},
+			{Domain: "example.com", Role: "Viewer"},
+			{Domain: "Example.com", Role: "Auditor"},
+			{Domain: "example.com", Role: "Viewer"},
+		},
+	}
+	assert.Equal(t, []string{"Admin"}, p.RolesByEmailDomain("alice@admin.example.com"))
+	assert.Equal(t, []string{"Viewer", "Auditor"}, p.RolesByEmailDomain("bob@EXAMPLE.COM"))
+	assert.Nil(t, p.RolesByEmailDomain("carol@sub.example.com"))
+	assert.Nil(t, p.RolesByEmailDomain("example.com"))
+	assert.Nil(t, p.RolesByEmailDomain(""))
+	assert.Nil(t, (&Project{}).RolesByEmailDomain("alice@admin.example.com"))
+}
+
+func TestProject_ValidateEmailDomainRoles(t *testing.T) {
+	assert.NoError(t, (&Project{}).ValidateEmailDomainRoles())
+	p := &Project{
+		EmailDomainRoles: []*ProjectEmailDomainRole{
+			{Domain: "admin.example.com", Role: "Admin"},
+			{Domain: "example.com", Role: "Auditor"},
+		},
+		RbacRoles: []*ProjectRBACRole{{Name: "Auditor"}},
+	}
+	assert.NoError(t, p.ValidateEmailDomainRoles())
+	p.RbacRoles = nil
+	assert.EqualError(t, p.ValidateEmailDomainRoles(), "role Auditor of email domain example.com does not exist")
+}
+
+func TestProject_MatchedUserGroups(t *testing.T) {
+	p := &Project{
+		UserGroups: []*ProjectUserGroup{
+			{SsoGroup: "team/admin", Role: "Admin"},
+			{SsoGroup: "team/editor", Role: "Editor"},
+			{SsoGroup: "team/editor", Role: "Custom"},
+			{SsoGroup: "team/viewer", Role: "Viewer"},
+		},
+	}
+
+	assert.Equal(t, []string{"team/admin", "team/editor"}, p.MatchedUserGroups([]string{"team/editor", "other", "team/admin"}))
+	assert.Empty(t, p.MatchedUserGroups([]string{"other"}))
+	assert.Empty(t, p.MatchedUserGroups(nil))
+}
+
+func TestProject_SessionTTL(t *testing.T) {
+	p := &Project{
+		UserGroups: []*ProjectUserGroup{
+			{SsoGroup: "team/admin", Role: "Admin", SessionTtl: 1},
+			{SsoGroup: "team/oncall", Role: "Admin", SessionTtl: 4},
+			{SsoGroup: "team/editor", Role: "Editor"},
+			{SsoGroup: "team/viewer", Role: "Viewer", SessionTtl: 720},
+		},
+	}
+
+	assert.Equal(t, time.Hour, p.SessionTTL([]string{"Admin"}))
+	assert.Equal(t, time.Hour, p.SessionTTL([]string{"Viewer", "Admin"}))
+	assert.Equal(t, 720*time.Hour, p.SessionTTL([]string{"Viewer", "Editor"}))
+	assert.Zero(t, p.SessionTTL([]string{"Editor"}))
+	assert.Zero(t, p.SessionTTL(nil))
+}
+
+func TestProject_SetLegacyUserGroups(t *testing.T) {
+	testcases := []struct {
+		name    string
+		project *Project
+		want    *Project
+	}{
+		{
+			name:    "empty",
+			project: &Project{},
+			want:    &Project{},
+		},
+		{
+			name: "merge rbac config and user group",
+			project: &Project{
+				Rbac: &ProjectRBACConfig{
+					Admin:  "team/admin",
+					Editor: "team/editor",
+					Viewer: "team/viewer",
+				},
+				UserGroups: []*ProjectUserGroup{
+					{
+						Role:     "Tester",
+						SsoGroup: "team/tester",
+					},
+					{
+						Role:     "Owner",
+						SsoGroup: "team/owner",
+					},
+				},
+			},
+			want: &Project{
+				Rbac: &ProjectRBACConfig{
+					Admin:  "team/admin",
+					Editor: "team/editor",
+					Viewer: "team/viewer",
+				},
+				UserGroups: []*ProjectUserGroup{
+					{
+						Role:     "Admin",
+						SsoGroup: "team/admin",
+					},
+					{
+						Role:     "Editor",
+						SsoGroup: "team/editor",
+					},
+					{
+						Role:     "Viewer",
+						SsoGroup: "team/viewer",
+					},
+					{
+						Role:     "Tester",
+						SsoGroup: "team/tester",
+					},
+					{
+						Role:     "Owner",
+						SsoGroup: "team/owner",
+					},
+				},
+			},
+		},
+		{
+			name: "exists same name rbac config",
+			project: &Project{
+				Rbac: &ProjectRBACConfig{
+					Admin:  "team/admin",
+					Editor: "team/admin",
+					Viewer: "team/admin",
+				},
+				UserGroups: []*ProjectUserGroup{
+					{
+						Role:     "Tester",
+						SsoGroup: "team/tester",
+					},
+					{
+						Role:     "Owner",
+						SsoGroup: "team/owner",
+					},
+				},
+			},
+			want: &Project{
+				Rbac: &ProjectRBACConfig{
+					Admin:  "team/admin",
+					Editor: "team/admin",
+					Viewer: "team/admin",
+				},
+				UserGroups: []*ProjectUserGroup{
+					{
+						Role:     "Admin",
+						SsoGroup: "team/admin",
+					},
+					{
+						Role:     "Tester",
+						SsoGroup: "team/tester",
+					},
+					{
+						Role:     "Owner",
+						SsoGroup: "team/owner",
 					},
 				},
 			},
 		},
 	}
-	p := &Project{RbacRoles: roles}
 
-	// True
-	assert.True(t, p.HasRBACRole("test"))
-
-	// False
-	assert.False(t, p.HasRBACRole("foo"))
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.project.SetLegacyUserGroups()
+			assert.Equal(t, tc.want, tc.project)
+		})
+	}
 }
 
-func TestProject_HasUserGroup(t *testing.T) {
-	groups := []*ProjectUserGroup{
+func TestProject_AddUserGroup(t *testing.T) {
+	type args struct {
+		sso  string
+		role string
+	}
+	testcases := []struct {
+		name    string
+		args    args
+		project *Project
+		wantErr bool
+	}{
 		{
-			SsoGroup: "team/tester",
-			Role:     "Tester",
+			name: "ok",
+			args: args{
+				sso:  "team/admin",
+				role: "Admin",
+			},
+			project: &Project{
+				RbacRoles: []*ProjectRBACRole{
+					builtinAdminRBACRole,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "the role is already assigned in rbac config",
+			args: args{
+				sso:  "team/admin",
+				role: "Admin",
+			},
+			project: &Project{
+				Rbac: &ProjectRBACConfig{
+					Admin: "team/admin",
+				},
+				RbacRoles: []*ProjectRBACRole{
+					builtinAdminRBACRole,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "the role is already assigned in user group",
+			args: args{
+				sso:  "team/tester",
+				role: "Tester",
+			},
+			project: &Project{
+				UserGroups: []*ProjectUserGroup{
+					{
+						SsoGroup: "team/tester",
+						Role:     "Tester",
+					},
+				},
+				RbacRoles: []*ProjectRBACRole{
+					{
+						Name: "Tester",
+						Policies: []*ProjectRBACPolicy{
+							{
+								Resources: []*ProjectRBACResource{
+									{
+										Type: ProjectRBACResource_APPLICATION,
+									},
+								},
+								Actions: []ProjectRBACPolicy_Action{
+									ProjectRBACPolicy_GET,
+								},
+							},
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "the role doesn't exist",
+			args: args{
+				sso:  "team/tester",
+				role: "Tester",
+			},
+			project: &Project{},
+			wantErr: true,
 		},
 	}
-	rbac := &ProjectRBACConfig{
-		Admin:  "team/admin",
-		Editor: "team/editor",
-		Viewer: "team/viewer",
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.project.AddUserGroup(tc.args.sso, tc.args.role)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
 	}
-	p := &Project{
-		UserGroups: groups,
-		Rbac:       rbac,
+}
+
+func TestProject_DeleteUserGroup(t *testing.T) {
+	type args struct {
+		sso string
+	}
+	testcases := []struct {
+		name    string
+		args    args
+		project *Project
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			args: args{
+				sso: "team/admin",
+			},
+			project: &Project{
+				UserGroups: []*ProjectUserGroup{
+					{
+						SsoGroup: "team/admin",
+						Role:     "Admin",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "the user group doen't exist",
+			args: args{
+				sso: "team/admin",
+			},
+			project: &Project{},
+			wantErr: true,
+		},
+		{
+			name: "delete rbac config role",
+			args: args{
+				sso: "team/admin",
+			},
+			project: &Project{
+				Rbac: &ProjectRBACConfig{
+					Admin: "team/admin",
+				},
+			},
+			wantErr: false,
+		},
 	}
 
-	// True
-	assert.True(t, p.HasUserGroup("team/tester"))
-	assert.True(t, p.HasUserGroup("team/admin"))
-
-	// False
-	assert.False(t, p.HasUserGroup("team/foo"))
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.project.DeleteUserGroup(tc.args.sso)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
 }
 
-func TestProject_SetLegacyUserGroups(t *testing.T) {
+func TestProject_SetBuiltinRBACRoles(t *testing.T) {
 	testcases := []struct {
 		name    string
 		project *Project
 		want    *Project
 	}{
 		{
-			name:    "empty",
-			project: &Project{},
-			want:    &Project{},
-		},
-		{
-			name: "merge rbac config and user group",
+			name: "ok",
 			project: &Project{
-				Rbac: &ProjectRBACConfig{
-					Admin:  "team/admin",
-					Editor: "team/editor",
-					Viewer: "team/viewer",
-				},
-				UserGroups: []*ProjectUserGroup{
-					{
-						Role:     "Tester",
-						SsoGroup: "team/tester",
-					},
+				RbacRoles: []*ProjectRBACRole{
 					{
-						Role:     "Owner",
-						SsoGroup: "team/owner",
+						Name: "Tester",
+						Policies: []*ProjectRBACPolicy{
+							{
+								Resources: []*ProjectRBACResource{
+									{
+										Type: ProjectRBACResource_APPLICATION,
+									},
+								},
+								Actions: []ProjectRBACPolicy_Action{
+									ProjectRBACPolicy_GET,
+								},
+							},
+						},
 					},
 				},
 			},
 			want: &Project{
-				Rbac: &ProjectRBACConfig{
-					Admin:  "team/admin",
-					Editor: "team/editor",
-					Viewer: "team/viewer",
-				},
-				UserGroups: []*ProjectUserGroup{
-					{
-						Role:     "Admin",
-						SsoGroup: "team/admin",
-					},
+				RbacRoles: []*ProjectRBACRole{
+					builtinAdminRBACRole,
+					builtinEditorRBACRole,
+					builtinViewerRBACRole,
 					{
-						Role:     "Editor",
-						SsoGroup: "team/editor",
+						Name: "Tester",
+						Policies: []*ProjectRBACPolicy{
+							{
+								Resources: []*ProjectRBACResource{
+									{
+										Type: ProjectRBACResource_APPLICATION,
+									},
+								},
+								Actions: []ProjectRBACPolicy_Action{
+									ProjectRBACPolicy_GET,
+								},
+							},
+						},
 					},
+				},
+			},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.project.SetBuiltinRBACRoles()
+			assert.Equal(t, tc.want, tc.project)
+		})
+	}
+}
+
+func TestProject_AddRBACRole(t *testing.T) {
+	type args struct {
+		name     string
+		policies []*ProjectRBACPolicy
+	}
+	testcases := []struct {
+		name    string
+		args    args
+		project *Project
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			args: args{
+				name: "Tester",
+				policies: []*ProjectRBACPolicy{
 					{
-						Role:     "Viewer",
-						SsoGroup: "team/viewer",
+						Resources: []*ProjectRBACResource{
+							{
+								Type: ProjectRBACResource_APPLICATION,
+							},
+						},
+						Actions: []ProjectRBACPolicy_Action{
+							ProjectRBACPolicy_GET,
+						},
 					},
+				},
+			},
+			project: &Project{},
+			wantErr: false,
+		},
+		{
+			name: "the name is already used",
+			args: args{
+				name: "Tester",
+				policies: []*ProjectRBACPolicy{
 					{
-						Role:     "Tester",
-						SsoGroup: "team/tester",
+						Resources: []*ProjectRBACResource{
+							{
+								Type: ProjectRBACResource_APPLICATION,
+							},
+						},
+						Actions: []ProjectRBACPolicy_Action{
+							ProjectRBACPolicy_GET,
+						},
 					},
+				},
+			},
+			project: &Project{
+				RbacRoles: []*ProjectRBACRole{
 					{
-						Role:     "Owner",
-						SsoGroup: "team/owner",
+						Name: "Tester",
+						Policies: []*ProjectRBACPolicy{
+							{
+								Resources: []*ProjectRBACResource{
+									{
+										Type: ProjectRBACResource_APPLICATION,
+									},
+								},
+								Actions: []ProjectRBACPolicy_Action{
+									ProjectRBACPolicy_GET,
+								},
+							},
+						},
 					},
 				},
 			},
+			wantErr: true,
 		},
 		{
-			name: "exists same name rbac config",
-			project: &Project{
-				Rbac: &ProjectRBACConfig{
-					Admin:  "team/admin",
-					Editor: "team/admin",
-					Viewer: "team/admin",
-				},
-				UserGroups: []*ProjectUserGroup{
+			name: "the name of built-in role cannot be used",
+			args: args{
+				name: "Admin",
+				policies: []*ProjectRBACPolicy{
 					{
-						Role:     "Tester",
-						SsoGroup: "team/tester",
+						Resources: []*ProjectRBACResource{
+							{
+								Type: ProjectRBACResource_APPLICATION,
+							},
+						},
+						Actions: []ProjectRBACPolicy_Action{
+							ProjectRBACPolicy_GET,
+						},
 					},
+				},
+			},
+			project: &Project{},
+			wantErr: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.project.AddRBACRole(tc.args.name, tc.args.policies)
+			assert.Equal(t, tc.wantErr, got != nil)
+		})
+	}
+}
+
+func TestProject_UpdateRBACRole(t *testing.T) {
+	type args struct {
+		name     string
+		policies []*ProjectRBACPolicy
+	}
+	testcases := []struct {
+		name    string
+		args    args
+		project *Project
+		wantErr bool
+	}{
+		{
+			name: "ok",
+			args: args{
+				name: "Tester",
+				policies: []*ProjectRBACPolicy{
 					{
-						Role:     "Owner",
-						SsoGroup: "team/owner",
+						Resources: []*ProjectRBACResource{
+							{
+								Type: ProjectRBACResource_APPLICATION,
+							},
+						},
+						Actions: []ProjectRBACPolicy_Action{
+							ProjectRBACPolicy_GET,
+						},
 					},
 				},
 			},
-			want: &Project{
-				Rbac: &ProjectRBACConfig{
-					Admin:  "team/admin",
-					Editor: "team/admin",
-					Viewer: "team/admin",
-				},
-				UserGroups: []*ProjectUserGroup{
+			project: &Project{
+				RbacRoles: []*ProjectRBACRole{
 					{
-						Role:     "Admin",
-						SsoGroup: "team/admin",
+						Name: "Tester",
+						Policies: []*ProjectRBACPolicy{
+							{
+								Resources: []*ProjectRBACResource{
+									{
+										Type: ProjectRBACResource_APPLICATION,
+									},
+								},
+								Actions: []ProjectRBACPolicy_Action{
+									ProjectRBACPolicy_ALL,
+								},
+							},
+						},
 					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "the role doesn't exist",
+			args: args{
+				name: "Tester",
+				policies: []*ProjectRBACPolicy{
 					{
-						Role:     "Tester",
-						SsoGroup: "team/tester",
+						Resources: []*ProjectRBACResource{
+							{
+								Type: ProjectRBACResource_APPLICATION,
+							},
+						},
+						Actions: []ProjectRBACPolicy_Action{
+							ProjectRBACPolicy_GET,
+						},
 					},
+				},
+			},
+			project: &Project{},
+			wantErr: true,
+		},
+		{
+			name: "built-in role cannot be updated",
+			args: args{
+				name: "Admin",
+				policies: []*ProjectRBACPolicy{
 					{
-						Role:     "Owner",
-						SsoGroup: "team/owner",
+						Resources: []*ProjectRBACResource{
+							{
+								Type: ProjectRBACResource_APPLICATION,
+							},
+						},
+						Actions: []ProjectRBACPolicy_Action{
+							ProjectRBACPolicy_GET,
+						},
 					},
 				},
 			},
+			project: &Project{
+				RbacRoles: []*ProjectRBACRole{builtinAdminRBACRole},
+			},
+			wantErr: true,
 		},
 	}
-
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			tc.project.SetLegacyUserGroups()
-			assert.Equal(t, tc.want, tc.project)
+			got := tc.project.UpdateRBACRole(tc.args.name, tc.args.policies)
+			assert.Equal(t, tc.wantErr, got != nil)
 		})
 	}
 }
 
-func TestProject_AddUserGroup(t *testing.T) {
+func TestProject_DeleteRBACRole(t *testing.T) {
 	type args struct {
-		sso  string
-		role string
+		name string
 	}
 	testcases := []struct {
 		name    string
@@ -403,516 +1877,780 @@ func TestProject_AddUserGroup(t *testing.T) {
 		{
 			name: "ok",
 			args: args{
-				sso:  "team/admin",
-				role: "Admin",
+				name: "Tester",
 			},
 			project: &Project{
 				RbacRoles: []*ProjectRBACRole{
-					builtinAdminRBACRole,
+					{
+						Name: "Tester",
+						Policies: []*ProjectRBACPolicy{
+							{
+								Resources: []*ProjectRBACResource{
+									{
+										Type: ProjectRBACResource_APPLICATION,
+									},
+								},
+								Actions: []ProjectRBACPolicy_Action{
+									ProjectRBACPolicy_GET,
+								},
+							},
+						},
+					},
 				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "the role is already assigned in rbac config",
+			name: "the role doesn't exist",
 			args: args{
-				sso:  "team/admin",
-				role: "Admin",
+				name: "Tester",
+			},
+			project: &Project{},
+			wantErr: true,
+		},
+		{
+			name: "built-in role cannot be deleted",
+			args: args{
+				name: "Tester",
+			},
+			project: &Project{},
+			wantErr: true,
+		},
+		{
+			name: "default role cannot be deleted",
+			args: args{
+				name: "Tester",
 			},
 			project: &Project{
-				Rbac: &ProjectRBACConfig{
-					Admin: "team/admin",
-				},
+				DefaultRole: "Tester",
 				RbacRoles: []*ProjectRBACRole{
-					builtinAdminRBACRole,
+					{
+						Name: "Tester",
+					},
 				},
 			},
 			wantErr: true,
 		},
 		{
-			name: "the role is already assigned in user group",
+			name: "role mapped to email domain cannot be deleted",
 			args: args{
-				sso:  "team/tester",
-				role: "Tester",
+				name: "Tester",
 			},
 			project: &Project{
-				UserGroups: []*ProjectUserGroup{
-					{
-						SsoGroup: "team/tester",
-						Role:     "Tester",
-					},
+				EmailDomainRoles: []*ProjectEmailDomainRole{
+					{Domain: "example.com", Role: "Tester"},
 				},
 				RbacRoles: []*ProjectRBACRole{
 					{
 						Name: "Tester",
-						Policies: []*ProjectRBACPolicy{
-							{
-								Resources: []*ProjectRBACResource{
-									{
-										Type: ProjectRBACResource_APPLICATION,
-									},
-								},
-								Actions: []ProjectRBACPolicy_Action{
-									ProjectRBACPolicy_GET,
-								},
-							},
-						},
 					},
 				},
 			},
-			wantErr: true,
+			wantErr: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := tc.project.DeleteRBACRole(tc.args.name)
+			assert.Equal(t, tc.wantErr, got != nil)
+		})
+	}
+}
+
+func TestGenerateAuthCodeURL_Oidc(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              ProjectSSOConfig_Oidc
+		project             string
+		state               string
+		expectedAuthCodeURL string
+		expectedError       bool
+	}{
+		{
+			name: "valid config with default scope",
+			config: ProjectSSOConfig_Oidc{
+				Issuer:      "https://accounts.google.com",
+				ClientId:    "test-client-id",
+				RedirectUri: "https://example.com/callback",
+				Scopes:      []string{},
+			},
+			project:             "test-project",
+			state:               "test-state",
+			expectedAuthCodeURL: "https://accounts.google.com/o/oauth2/v2/auth?access_type=online&client_id=test-client-id&prompt=consent&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid+profile+email&state=test-state%3Aproject%3Dtest-project",
+			expectedError:       false,
+		},
+		{
+			name: "valid config with custom scopes",
+			config: ProjectSSOConfig_Oidc{
+				Issuer:      "https://accounts.google.com",
+				ClientId:    "test-client-id",
+				RedirectUri: "https://example.com/callback",
+				Scopes:      []string{"openid", "profile", "email"},
+			},
+			project:             "test-project",
+			state:               "test-state",
+			expectedAuthCodeURL: "https://accounts.google.com/o/oauth2/v2/auth?access_type=online&client_id=test-client-id&prompt=consent&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid+profile+email&state=test-state%3Aproject%3Dtest-project",
+			expectedError:       false,
+		},
+		{
+			name: "invalid issuer",
+			config: ProjectSSOConfig_Oidc{
+				Issuer:      "https://invalid-issuer.com",
+				ClientId:    "test-client-id",
+				RedirectUri: "https://example.com/callback",
+				Scopes:      []string{},
+			},
+			project:             "test-project",
+			state:               "test-state",
+			expectedAuthCodeURL: "",
+			expectedError:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authURL, err := tt.config.GenerateAuthCodeURL(tt.project, tt.state)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAuthCodeURL, authURL)
+			}
+		})
+	}
+}
+
+func TestGenerateAuthCodeURL_GitHub(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              *ProjectSSOConfig_GitHub
+		expectedAuthCodeURL string
+		expectedError       bool
+	}{
+		{
+			name: "github.com",
+			config: &ProjectSSOConfig_GitHub{
+				ClientId: "test-client-id",
+			},
+			expectedAuthCodeURL: "https://github.com/login/oauth/authorize?access_type=online&client_id=test-client-id&prompt=consent&redirect_uri=https%3A%2F%2Fpipecd.example.com%2Fauth%2Fcallback%3Fproject%3Dtest-project&response_type=code&scope=read%3Aorg+user%3Aemail&state=test-state",
+		},
+		{
+			name: "enterprise server",
+			config: &ProjectSSOConfig_GitHub{
+				ClientId: "test-client-id",
+				BaseUrl:  "https://github.example.com",
+				ApiUrl:   "https://api.github.example.com/",
+			},
+			expectedAuthCodeURL: "https://github.example.com/login/oauth/authorize?access_type=online&client_id=test-client-id&prompt=consent&redirect_uri=https%3A%2F%2Fpipecd.example.com%2Fauth%2Fcallback%3Fproject%3Dtest-project&response_type=code&scope=read%3Aorg+user%3Aemail&state=test-state",
+		},
+		{
+			name: "non https base url",
+			config: &ProjectSSOConfig_GitHub{
+				ClientId: "test-client-id",
+				BaseUrl:  "http://github.example.com",
+			},
+			expectedError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authURL, err := tt.config.GenerateAuthCodeURL("test-project", "https://pipecd.example.com/auth/callback", "test-state")
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAuthCodeURL, authURL)
+			}
+		})
+	}
+}
+
+func TestProjectSSOConfig_OfflineAccessOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *ProjectSSOConfig
+		expectedParams url.Values
+	}{
+		{
+			name: "google",
+			config: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GOOGLE,
+				Google:   &ProjectSSOConfig_Google{ClientId: "test-client-id"},
+			},
+			expectedParams: url.Values{
+				"access_type": {"offline"},
+				"scope":       {"openid email profile https://www.googleapis.com/auth/admin.directory.group.readonly"},
+			},
+		},
+		{
+			name: "azure ad",
+			config: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_AZUREAD,
+				AzureAd:  &ProjectSSOConfig_AzureAD{ClientId: "test-client-id"},
+			},
+			expectedParams: url.Values{
+				"access_type": {"online"},
+				"scope":       {"openid email profile offline_access"},
+			},
+		},
+		{
+			name: "okta",
+			config: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_OKTA,
+				Okta:     &ProjectSSOConfig_Okta{ClientId: "test-client-id", Domain: "example.okta.com", Scopes: []string{"offline_access"}},
+			},
+			expectedParams: url.Values{
+				"access_type": {"online"},
+				"scope":       {"openid email profile groups offline_access"},
+			},
+		},
+		{
+			name: "github",
+			config: &ProjectSSOConfig{
+				Provider: ProjectSSOConfig_GITHUB,
+				Github:   &ProjectSSOConfig_GitHub{ClientId: "test-client-id"},
+			},
+			expectedParams: url.Values{
+				"access_type": {"online"},
+				"scope":       {"read:org user:email"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authURL, err := tt.config.GenerateAuthCodeURL("test-project", "https://pipecd.example.com/auth/callback", "test-state", tt.config.OfflineAccessOptions()...)
+			assert.NoError(t, err)
+			u, err := url.Parse(authURL)
+			assert.NoError(t, err)
+			for k, v := range tt.expectedParams {
+				assert.Equal(t, v, u.Query()[k], k)
+			}
+		})
+	}
+}
+
+func TestProjectSSOConfig_GitHub_ValidateURLs(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        *ProjectSSOConfig_GitHub
+		expectedError bool
+	}{
+		{
+			name:   "github.com",
+			config: &ProjectSSOConfig_GitHub{},
+		},
+		{
+			name: "enterprise server",
+			config: &ProjectSSOConfig_GitHub{
+				BaseUrl:   "https://github.example.com",
+				ApiUrl:    "https://github.example.com/api/v3/",
+				UploadUrl: "https://github.example.com/api/uploads/",
+			},
+		},
+		{
+			name: "http base url",
+			config: &ProjectSSOConfig_GitHub{
+				BaseUrl: "http://github.example.com",
+			},
+			expectedError: true,
+		},
+		{
+			name: "relative api url",
+			config: &ProjectSSOConfig_GitHub{
+				BaseUrl: "https://github.example.com",
+				ApiUrl:  "/api/v3/",
+			},
+			expectedError: true,
+		},
+		{
+			name: "malformed upload url",
+			config: &ProjectSSOConfig_GitHub{
+				BaseUrl:   "https://github.example.com",
+				UploadUrl: "://invalid",
+			},
+			expectedError: true,
 		},
 		{
-			name: "the role doesn't exist",
-			args: args{
-				sso:  "team/tester",
-				role: "Tester",
+			name: "api url without base url",
+			config: &ProjectSSOConfig_GitHub{
+				ApiUrl: "https://github.example.com/api/v3/",
 			},
-			project: &Project{},
-			wantErr: true,
+			expectedError: true,
 		},
 	}
 
-	for _, tc := range testcases {
-		t.Run(tc.name, func(t *testing.T) {
-			err := tc.project.AddUserGroup(tc.args.sso, tc.args.role)
-			assert.Equal(t, tc.wantErr, err != nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.ValidateURLs()
+			assert.Equal(t, tt.expectedError, err != nil)
 		})
 	}
 }
 
-func TestProject_DeleteUserGroup(t *testing.T) {
-	type args struct {
-		sso string
+func TestValidateExtraHeaders(t *testing.T) {
+	tests := []struct {
+		name          string
+		headers       map[string]string
+		expectedError bool
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:    "valid",
+			headers: map[string]string{"X-Api-Key": "key", "x-tenant": "tenant id"},
+		},
+		{
+			name:          "invalid name",
+			headers:       map[string]string{"X Api Key": "key"},
+			expectedError: true,
+		},
+		{
+			name:          "name with newline",
+			headers:       map[string]string{"X-Api-Key\r\nHost": "example.com"},
+			expectedError: true,
+		},
+		{
+			name:          "value with newline",
+			headers:       map[string]string{"X-Api-Key": "key\r\nTransfer-Encoding: chunked"},
+			expectedError: true,
+		},
+		{
+			name:          "reserved",
+			headers:       map[string]string{"transfer-encoding": "chunked"},
+			expectedError: true,
+		},
+		{
+			name:          "authorization",
+			headers:       map[string]string{"Authorization": "Bearer token"},
+			expectedError: true,
+		},
 	}
-	testcases := []struct {
-		name    string
-		args    args
-		project *Project
-		wantErr bool
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateExtraHeaders(tt.headers)
+			assert.Equal(t, tt.expectedError, err != nil)
+			assert.Equal(t, tt.expectedError, (&ProjectSSOConfig_GitHub{ExtraHeaders: tt.headers}).ValidateExtraHeaders() != nil)
+			assert.Equal(t, tt.expectedError, (&ProjectSSOConfig_Oidc{ExtraHeaders: tt.headers}).ValidateExtraHeaders() != nil)
+		})
+	}
+}
+
+func TestProjectSSOConfig_GitHub_ValidateApp(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        *ProjectSSOConfig_GitHub
+		expectedError bool
 	}{
 		{
-			name: "ok",
-			args: args{
-				sso: "team/admin",
-			},
-			project: &Project{
-				UserGroups: []*ProjectUserGroup{
-					{
-						SsoGroup: "team/admin",
-						Role:     "Admin",
-					},
-				},
-			},
-			wantErr: false,
+			name:   "oauth token",
+			config: &ProjectSSOConfig_GitHub{},
 		},
 		{
-			name: "the user group doen't exist",
-			args: args{
-				sso: "team/admin",
+			name: "github app",
+			config: &ProjectSSOConfig_GitHub{
+				AppId:             1,
+				AppInstallationId: 2,
+				AppPrivateKey:     "key",
 			},
-			project: &Project{},
-			wantErr: true,
 		},
 		{
-			name: "delete rbac config role",
-			args: args{
-				sso: "team/admin",
+			name: "missing installation id",
+			config: &ProjectSSOConfig_GitHub{
+				AppId:         1,
+				AppPrivateKey: "key",
 			},
-			project: &Project{
-				Rbac: &ProjectRBACConfig{
-					Admin: "team/admin",
-				},
+			expectedError: true,
+		},
+		{
+			name: "missing private key",
+			config: &ProjectSSOConfig_GitHub{
+				AppId:             1,
+				AppInstallationId: 2,
 			},
-			wantErr: false,
+			expectedError: true,
 		},
 	}
 
-	for _, tc := range testcases {
-		t.Run(tc.name, func(t *testing.T) {
-			err := tc.project.DeleteUserGroup(tc.args.sso)
-			assert.Equal(t, tc.wantErr, err != nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.ValidateApp()
+			assert.Equal(t, tt.expectedError, err != nil)
 		})
 	}
 }
 
-func TestProject_SetBuiltinRBACRoles(t *testing.T) {
-	testcases := []struct {
-		name    string
-		project *Project
-		want    *Project
+func TestProjectSSOConfig_Oidc_Scopes(t *testing.T) {
+	tests := []struct {
+		name           string
+		scopes         []string
+		expectedScopes []string
+		expectedError  bool
 	}{
 		{
-			name: "ok",
-			project: &Project{
-				RbacRoles: []*ProjectRBACRole{
-					{
-						Name: "Tester",
-						Policies: []*ProjectRBACPolicy{
-							{
-								Resources: []*ProjectRBACResource{
-									{
-										Type: ProjectRBACResource_APPLICATION,
-									},
-								},
-								Actions: []ProjectRBACPolicy_Action{
-									ProjectRBACPolicy_GET,
-								},
-							},
-						},
-					},
-				},
-			},
-			want: &Project{
-				RbacRoles: []*ProjectRBACRole{
-					builtinAdminRBACRole,
-					builtinEditorRBACRole,
-					builtinViewerRBACRole,
-					{
-						Name: "Tester",
-						Policies: []*ProjectRBACPolicy{
-							{
-								Resources: []*ProjectRBACResource{
-									{
-										Type: ProjectRBACResource_APPLICATION,
-									},
-								},
-								Actions: []ProjectRBACPolicy_Action{
-									ProjectRBACPolicy_GET,
-								},
-							},
-						},
-					},
-				},
-			},
+			name:           "default",
+			expectedScopes: []string{"openid", "profile", "email"},
+		},
+		{
+			name:           "custom",
+			scopes:         []string{"openid", "groups", "offline_access"},
+			expectedScopes: []string{"openid", "groups", "offline_access"},
+		},
+		{
+			name:           "missing openid",
+			scopes:         []string{"profile", "email"},
+			expectedScopes: []string{"profile", "email"},
+			expectedError:  true,
 		},
 	}
-	for _, tc := range testcases {
-		t.Run(tc.name, func(t *testing.T) {
-			tc.project.SetBuiltinRBACRoles()
-			assert.Equal(t, tc.want, tc.project)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &ProjectSSOConfig_Oidc{Scopes: tt.scopes}
+			assert.Equal(t, tt.expectedScopes, config.ScopesOrDefault())
+			assert.Equal(t, tt.expectedError, config.ValidateScopes() != nil)
 		})
 	}
 }
 
-func TestProject_AddRBACRole(t *testing.T) {
-	type args struct {
-		name     string
-		policies []*ProjectRBACPolicy
+func TestProjectSSOConfig_Oidc_ValidateClientAuthentication(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         *ProjectSSOConfig_Oidc
+		expectedPublic bool
+		expectedError  bool
+	}{
+		{
+			name:   "client secret",
+			config: &ProjectSSOConfig_Oidc{ClientSecret: "secret"},
+		},
+		{
+			name:   "client certificate",
+			config: &ProjectSSOConfig_Oidc{ClientCertificate: "cert", ClientKey: "key"},
+		},
+		{
+			name:   "client assertion key",
+			config: &ProjectSSOConfig_Oidc{ClientAssertionKey: "key"},
+		},
+		{
+			name:           "public client with pkce",
+			config:         &ProjectSSOConfig_Oidc{PkceEnabled: true},
+			expectedPublic: true,
+		},
+		{
+			name:           "public client without pkce",
+			config:         &ProjectSSOConfig_Oidc{},
+			expectedPublic: true,
+			expectedError:  true,
+		},
 	}
-	testcases := []struct {
-		name    string
-		args    args
-		project *Project
-		wantErr bool
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedPublic, tt.config.IsPublicClient())
+			assert.Equal(t, tt.expectedError, tt.config.ValidateClientAuthentication() != nil)
+		})
+	}
+}
+
+func TestProjectSSOConfig_GitHub_APIURL(t *testing.T) {
+	assert.Empty(t, (&ProjectSSOConfig_GitHub{}).APIURL())
+	assert.Equal(t, "https://github.example.com", (&ProjectSSOConfig_GitHub{BaseUrl: "https://github.example.com"}).APIURL())
+	assert.Equal(t, "https://api.github.example.com/", (&ProjectSSOConfig_GitHub{BaseUrl: "https://github.example.com", ApiUrl: "https://api.github.example.com/"}).APIURL())
+}
+
+func TestGenerateAuthCodeURL_GitLab(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              *ProjectSSOConfig_GitLab
+		project             string
+		state               string
+		expectedAuthCodeURL string
+		expectedError       bool
 	}{
 		{
-			name: "ok",
-			args: args{
-				name: "Tester",
-				policies: []*ProjectRBACPolicy{
-					{
-						Resources: []*ProjectRBACResource{
-							{
-								Type: ProjectRBACResource_APPLICATION,
-							},
-						},
-						Actions: []ProjectRBACPolicy_Action{
-							ProjectRBACPolicy_GET,
-						},
-					},
-				},
+			name: "gitlab.com",
+			config: &ProjectSSOConfig_GitLab{
+				ClientId:    "test-client-id",
+				RedirectUri: "https://example.com/callback",
 			},
-			project: &Project{},
-			wantErr: false,
+			project:             "test-project",
+			state:               "test-state",
+			expectedAuthCodeURL: "https://gitlab.com/oauth/authorize?access_type=online&client_id=test-client-id&prompt=consent&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=read_api&state=test-state%3Aproject%3Dtest-project",
+			expectedError:       false,
 		},
 		{
-			name: "the name is already used",
-			args: args{
-				name: "Tester",
-				policies: []*ProjectRBACPolicy{
-					{
-						Resources: []*ProjectRBACResource{
-							{
-								Type: ProjectRBACResource_APPLICATION,
-							},
-						},
-						Actions: []ProjectRBACPolicy_Action{
-							ProjectRBACPolicy_GET,
-						},
-					},
-				},
-			},
-			project: &Project{
-				RbacRoles: []*ProjectRBACRole{
-					{
-						Name: "Tester",
-						Policies: []*ProjectRBACPolicy{
-							{
-								Resources: []*ProjectRBACResource{
-									{
-										Type: ProjectRBACResource_APPLICATION,
-									},
-								},
-								Actions: []ProjectRBACPolicy_Action{
-									ProjectRBACPolicy_GET,
-								},
-							},
-						},
-					},
-				},
+			name: "self-hosted",
+			config: &ProjectSSOConfig_GitLab{
+				ClientId:    "test-client-id",
+				BaseUrl:     "https://gitlab.example.com/",
+				RedirectUri: "https://example.com/callback",
 			},
-			wantErr: true,
+			project:             "test-project",
+			state:               "test-state",
+			expectedAuthCodeURL: "https://gitlab.example.com/oauth/authorize?access_type=online&client_id=test-client-id&prompt=consent&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=read_api&state=test-state%3Aproject%3Dtest-project",
+			expectedError:       false,
 		},
 		{
-			name: "the name of built-in role cannot be used",
-			args: args{
-				name: "Admin",
-				policies: []*ProjectRBACPolicy{
-					{
-						Resources: []*ProjectRBACResource{
-							{
-								Type: ProjectRBACResource_APPLICATION,
-							},
-						},
-						Actions: []ProjectRBACPolicy_Action{
-							ProjectRBACPolicy_GET,
-						},
-					},
-				},
+			name: "invalid base url",
+			config: &ProjectSSOConfig_GitLab{
+				ClientId:    "test-client-id",
+				BaseUrl:     "://invalid",
+				RedirectUri: "https://example.com/callback",
 			},
-			project: &Project{},
-			wantErr: true,
+			project:       "test-project",
+			state:         "test-state",
+			expectedError: true,
 		},
 	}
-	for _, tc := range testcases {
-		t.Run(tc.name, func(t *testing.T) {
-			got := tc.project.AddRBACRole(tc.args.name, tc.args.policies)
-			assert.Equal(t, tc.wantErr, got != nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authURL, err := tt.config.GenerateAuthCodeURL(tt.project, tt.state)
+			if tt.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedAuthCodeURL, authURL)
+			}
 		})
 	}
 }
 
-func TestProject_UpdateRBACRole(t *testing.T) {
-	type args struct {
-		name     string
-		policies []*ProjectRBACPolicy
-	}
-	testcases := []struct {
-		name    string
-		args    args
-		project *Project
-		wantErr bool
+func TestGenerateAuthCodeURL_Google(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              *ProjectSSOConfig_Google
+		project             string
+		state               string
+		expectedAuthCodeURL string
 	}{
 		{
-			name: "ok",
-			args: args{
-				name: "Tester",
-				policies: []*ProjectRBACPolicy{
-					{
-						Resources: []*ProjectRBACResource{
-							{
-								Type: ProjectRBACResource_APPLICATION,
-							},
-						},
-						Actions: []ProjectRBACPolicy_Action{
-							ProjectRBACPolicy_GET,
-						},
-					},
-				},
-			},
-			project: &Project{
-				RbacRoles: []*ProjectRBACRole{
-					{
-						Name: "Tester",
-						Policies: []*ProjectRBACPolicy{
-							{
-								Resources: []*ProjectRBACResource{
-									{
-										Type: ProjectRBACResource_APPLICATION,
-									},
-								},
-								Actions: []ProjectRBACPolicy_Action{
-									ProjectRBACPolicy_ALL,
-								},
-							},
-						},
-					},
-				},
+			name: "no allowed domains",
+			config: &ProjectSSOConfig_Google{
+				ClientId:    "test-client-id",
+				RedirectUri: "https://example.com/callback",
 			},
-			wantErr: false,
+			project:             "test-project",
+			state:               "test-state",
+			expectedAuthCodeURL: "https://accounts.google.com/o/oauth2/auth?access_type=online&client_id=test-client-id&prompt=consent&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid+email+profile+https%3A%2F%2Fwww.googleapis.com%2Fauth%2Fadmin.directory.group.readonly&state=test-state%3Aproject%3Dtest-project",
 		},
 		{
-			name: "the role doesn't exist",
-			args: args{
-				name: "Tester",
-				policies: []*ProjectRBACPolicy{
-					{
-						Resources: []*ProjectRBACResource{
-							{
-								Type: ProjectRBACResource_APPLICATION,
-							},
-						},
-						Actions: []ProjectRBACPolicy_Action{
-							ProjectRBACPolicy_GET,
-						},
-					},
-				},
+			name: "single allowed domain",
+			config: &ProjectSSOConfig_Google{
+				ClientId:       "test-client-id",
+				RedirectUri:    "https://example.com/callback",
+				AllowedDomains: []string{"example.com"},
 			},
-			project: &Project{},
-			wantErr: true,
+			project:             "test-project",
+			state:               "test-state",
+			expectedAuthCodeURL: "https://accounts.google.com/o/oauth2/auth?access_type=online&client_id=test-client-id&hd=example.com&prompt=consent&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid+email+profile+https%3A%2F%2Fwww.googleapis.com%2Fauth%2Fadmin.directory.group.readonly&state=test-state%3Aproject%3Dtest-project",
 		},
 		{
-			name: "built-in role cannot be updated",
-			args: args{
-				name: "Admin",
-				policies: []*ProjectRBACPolicy{
-					{
-						Resources: []*ProjectRBACResource{
-							{
-								Type: ProjectRBACResource_APPLICATION,
-							},
-						},
-						Actions: []ProjectRBACPolicy_Action{
-							ProjectRBACPolicy_GET,
-						},
-					},
-				},
+			name: "multiple allowed domains",
+			config: &ProjectSSOConfig_Google{
+				ClientId:       "test-client-id",
+				RedirectUri:    "https://example.com/callback",
+				AllowedDomains: []string{"example.com", "example.org"},
 			},
-			project: &Project{
-				RbacRoles: []*ProjectRBACRole{builtinAdminRBACRole},
-			},
-			wantErr: true,
+			project:             "test-project",
+			state:               "test-state",
+			expectedAuthCodeURL: "https://accounts.google.com/o/oauth2/auth?access_type=online&client_id=test-client-id&prompt=consent&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid+email+profile+https%3A%2F%2Fwww.googleapis.com%2Fauth%2Fadmin.directory.group.readonly&state=test-state%3Aproject%3Dtest-project",
 		},
 	}
-	for _, tc := range testcases {
-		t.Run(tc.name, func(t *testing.T) {
-			got := tc.project.UpdateRBACRole(tc.args.name, tc.args.policies)
-			assert.Equal(t, tc.wantErr, got != nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authURL, err := tt.config.GenerateAuthCodeURL(tt.project, tt.state)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAuthCodeURL, authURL)
 		})
 	}
 }
 
-func TestProject_DeleteRBACRole(t *testing.T) {
-	type args struct {
-		name string
-	}
-	testcases := []struct {
-		name    string
-		args    args
-		project *Project
-		wantErr bool
+func TestGenerateAuthCodeURL_AzureAD(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              *ProjectSSOConfig_AzureAD
+		project             string
+		state               string
+		expectedAuthCodeURL string
 	}{
 		{
-			name: "ok",
-			args: args{
-				name: "Tester",
+			name: "default tenant",
+			config: &ProjectSSOConfig_AzureAD{
+				ClientId:    "test-client-id",
+				RedirectUri: "https://example.com/callback",
 			},
-			project: &Project{
-				RbacRoles: []*ProjectRBACRole{
-					{
-						Name: "Tester",
-						Policies: []*ProjectRBACPolicy{
-							{
-								Resources: []*ProjectRBACResource{
-									{
-										Type: ProjectRBACResource_APPLICATION,
-									},
-								},
-								Actions: []ProjectRBACPolicy_Action{
-									ProjectRBACPolicy_GET,
-								},
-							},
-						},
-					},
-				},
+			project:             "test-project",
+			state:               "test-state",
+			expectedAuthCodeURL: "https://login.microsoftonline.com/organizations/oauth2/v2.0/authorize?access_type=online&client_id=test-client-id&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid+email+profile&state=test-state%3Aproject%3Dtest-project",
+		},
+		{
+			name: "specific tenant",
+			config: &ProjectSSOConfig_AzureAD{
+				ClientId:    "test-client-id",
+				Tenant:      "00000000-0000-0000-0000-000000000001",
+				RedirectUri: "https://example.com/callback",
 			},
-			wantErr: false,
+			project:             "test-project",
+			state:               "test-state",
+			expectedAuthCodeURL: "https://login.microsoftonline.com/00000000-0000-0000-0000-000000000001/oauth2/v2.0/authorize?access_type=online&client_id=test-client-id&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid+email+profile&state=test-state%3Aproject%3Dtest-project",
 		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			authURL, err := tt.config.GenerateAuthCodeURL(tt.project, tt.state)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAuthCodeURL, authURL)
+		})
+	}
+}
+
+func TestGenerateAuthCodeURL_Okta(t *testing.T) {
+	tests := []struct {
+		name                string
+		config              *ProjectSSOConfig_Okta
+		expectedIssuer      string
+		expectedAuthCodeURL string
+	}{
 		{
-			name: "the role doesn't exist",
-			args: args{
-				name: "Tester",
+			name: "org authorization server",
+			config: &ProjectSSOConfig_Okta{
+				ClientId:    "test-client-id",
+				Domain:      "https://example.okta.com/",
+				RedirectUri: "https://example.com/callback",
 			},
-			project: &Project{},
-			wantErr: true,
+			expectedIssuer:      "https://example.okta.com",
+			expectedAuthCodeURL: "https://example.okta.com/oauth2/v1/authorize?access_type=online&client_id=test-client-id&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid+email+profile+groups&state=test-state%3Aproject%3Dtest-project",
 		},
 		{
-			name: "built-in role cannot be deleted",
-			args: args{
-				name: "Tester",
+			name: "custom authorization server with additional scopes",
+			config: &ProjectSSOConfig_Okta{
+				ClientId:              "test-client-id",
+				Domain:                "https://example.okta.com",
+				AuthorizationServerId: "default",
+				RedirectUri:           "https://example.com/callback",
+				Scopes:                []string{"groups", "offline_access"},
 			},
-			project: &Project{},
-			wantErr: true,
+			expectedIssuer:      "https://example.okta.com/oauth2/default",
+			expectedAuthCodeURL: "https://example.okta.com/oauth2/default/v1/authorize?access_type=online&client_id=test-client-id&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid+email+profile+groups+offline_access&state=test-state%3Aproject%3Dtest-project",
 		},
 	}
-	for _, tc := range testcases {
-		t.Run(tc.name, func(t *testing.T) {
-			got := tc.project.DeleteRBACRole(tc.args.name)
-			assert.Equal(t, tc.wantErr, got != nil)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedIssuer, tt.config.Issuer())
+			authURL, err := tt.config.GenerateAuthCodeURL("test-project", "test-state")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAuthCodeURL, authURL)
 		})
 	}
 }
 
-func TestGenerateAuthCodeURL_Oidc(t *testing.T) {
+func TestGenerateAuthCodeURL_Bitbucket(t *testing.T) {
 	tests := []struct {
 		name                string
-		config              ProjectSSOConfig_Oidc
-		project             string
-		state               string
+		config              *ProjectSSOConfig_Bitbucket
 		expectedAuthCodeURL string
-		expectedError       bool
+		expectErr           bool
 	}{
 		{
-			name: "valid config with default scope",
-			config: ProjectSSOConfig_Oidc{
-				Issuer:      "https://accounts.google.com",
+			name: "cloud",
+			config: &ProjectSSOConfig_Bitbucket{
 				ClientId:    "test-client-id",
 				RedirectUri: "https://example.com/callback",
-				Scopes:      []string{},
 			},
-			project:             "test-project",
-			state:               "test-state",
-			expectedAuthCodeURL: "https://accounts.google.com/o/oauth2/v2/auth?access_type=online&client_id=test-client-id&prompt=consent&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid&state=test-state%3Atest-project",
-			expectedError:       false,
+			expectedAuthCodeURL: "https://bitbucket.org/site/oauth2/authorize?access_type=online&client_id=test-client-id&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=account&state=test-state%3Aproject%3Dtest-project",
 		},
 		{
-			name: "valid config with custom scopes",
-			config: ProjectSSOConfig_Oidc{
-				Issuer:      "https://accounts.google.com",
+			name: "server under context path",
+			config: &ProjectSSOConfig_Bitbucket{
 				ClientId:    "test-client-id",
+				Flavor:      ProjectSSOConfig_Bitbucket_SERVER,
+				BaseUrl:     "https://git.example.com/bitbucket/",
 				RedirectUri: "https://example.com/callback",
-				Scopes:      []string{"openid", "profile", "email"},
 			},
-			project:             "test-project",
-			state:               "test-state",
-			expectedAuthCodeURL: "https://accounts.google.com/o/oauth2/v2/auth?access_type=online&client_id=test-client-id&prompt=consent&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=openid+profile+email&state=test-state%3Atest-project",
-			expectedError:       false,
+			expectedAuthCodeURL: "https://git.example.com/bitbucket/rest/oauth2/latest/authorize?access_type=online&client_id=test-client-id&redirect_uri=https%3A%2F%2Fexample.com%2Fcallback&response_type=code&scope=PUBLIC_REPOS&state=test-state%3Aproject%3Dtest-project",
 		},
 		{
-			name: "invalid issuer",
-			config: ProjectSSOConfig_Oidc{
-				Issuer:      "https://invalid-issuer.com",
+			name: "server without base url",
+			config: &ProjectSSOConfig_Bitbucket{
 				ClientId:    "test-client-id",
+				Flavor:      ProjectSSOConfig_Bitbucket_SERVER,
 				RedirectUri: "https://example.com/callback",
-				Scopes:      []string{},
 			},
-			project:             "test-project",
-			state:               "test-state",
-			expectedAuthCodeURL: "",
-			expectedError:       true,
+			expectErr: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			authURL, err := tt.config.GenerateAuthCodeURL(tt.project, tt.state)
-			if tt.expectedError {
+			authURL, err := tt.config.GenerateAuthCodeURL("test-project", "test-state")
+			if tt.expectErr {
 				assert.Error(t, err)
-			} else {
-				assert.NoError(t, err)
-				assert.Equal(t, tt.expectedAuthCodeURL, authURL)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAuthCodeURL, authURL)
+		})
+	}
+}
+
+func TestCheckRedirectURI(t *testing.T) {
+	tests := []struct {
+		name      string
+		uri       string
+		allowed   []string
+		expectErr bool
+	}{
+		{
+			name: "no allowed list",
+			uri:  "https://evil.example.com/auth/callback",
+		},
+		{
+			name:    "allowed",
+			uri:     "https://pipecd.example.com/auth/callback?project=test",
+			allowed: []string{"https://other.example.com/auth/callback", "https://PipeCD.example.com/auth/callback"},
+		},
+		{
+			name:      "different host",
+			uri:       "https://evil.example.com/auth/callback",
+			allowed:   []string{"https://pipecd.example.com/auth/callback"},
+			expectErr: true,
+		},
+		{
+			name:      "different scheme",
+			uri:       "http://pipecd.example.com/auth/callback",
+			allowed:   []string{"https://pipecd.example.com/auth/callback"},
+			expectErr: true,
+		},
+		{
+			name:      "different path",
+			uri:       "https://pipecd.example.com/auth/callback/evil",
+			allowed:   []string{"https://pipecd.example.com/auth/callback"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid uri",
+			uri:       "https://pipecd.example.com/%zz",
+			allowed:   []string{"https://pipecd.example.com/auth/callback"},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRedirectURI(tt.uri, tt.allowed)
+			if tt.expectErr {
+				assert.ErrorIs(t, err, ErrRedirectURINotAllowed)
+				return
 			}
+			assert.NoError(t, err)
 		})
 	}
 }
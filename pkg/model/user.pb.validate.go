@@ -109,6 +109,10 @@ func (m *User) validate(all bool) error {
 		}
 	}
 
+	// no validation rules for Email
+
+	// no validation rules for EmailVerified
+
 	if len(errors) > 0 {
 		return UserMultiError(errors)
 	}
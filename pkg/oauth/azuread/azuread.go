@@ -0,0 +1,246 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuread
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/microsoft"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	authority = "https://login.microsoftonline.com"
+	// issuerTemplate is the issuer of the v2.0 tokens, where {tenantid} is replaced by the tid claim.
+	issuerTemplate = authority + "/{tenantid}/v2.0"
+)
+
+// multiTenants are the tenants allowing users from several tenants to log in.
+var multiTenants = []string{"common", "organizations", "consumers"}
+
+// ErrTenantNotPermitted is returned when the tenant of the user
+// is not in the allowed tenants of the SSO configuration.
+var ErrTenantNotPermitted = errors.New("tenant not permitted")
+
+// OAuthClient is a oauth client for Microsoft Entra ID (Azure AD).
+type OAuthClient struct {
+	*oauth2.Token
+
+	sso     *model.ProjectSSOConfig_AzureAD
+	project *model.Project
+	keySet  oidc.KeySet
+}
+
+type claims struct {
+	Issuer            string   `json:"iss"`
+	TenantID          string   `json:"tid"`
+	PreferredUsername string   `json:"preferred_username"`
+	Email             string   `json:"email"`
+	Groups            []string `json:"groups"`
+	// ClaimNames is set instead of the groups claim when the user
+	// belongs to too many groups to include in the token.
+	ClaimNames map[string]string `json:"_claim_names"`
+}
+
+// NewOAuthClient creates a new oauth client for Microsoft Entra ID.
+func NewOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_AzureAD,
+	project *model.Project,
+	code string,
+) (*OAuthClient, error) {
+	return newOAuthClient(ctx, sso, project, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.Exchange(ctx, code)
+	})
+}
+
+// RefreshOAuthClient creates a new oauth client for Microsoft Entra ID by the refresh token issued to the user at login,
+// which is used to look up the user again after login. The new ID token is always requested.
+func RefreshOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_AzureAD,
+	project *model.Project,
+	refreshToken string,
+) (*OAuthClient, error) {
+	return newOAuthClient(ctx, sso, project, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	})
+}
+
+// newOAuthClient creates a new oauth client for Microsoft Entra ID by the token obtained by the given function.
+func newOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_AzureAD,
+	project *model.Project,
+	obtainToken func(context.Context, *oauth2.Config) (*oauth2.Token, error),
+) (*OAuthClient, error) {
+	c := &OAuthClient{
+		sso:     sso,
+		project: project,
+	}
+
+	if sso.ProxyUrl != "" {
+		proxyURL, err := url.Parse(sso.ProxyUrl)
+		if err != nil {
+			return nil, err
+		}
+
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyURL(proxyURL)
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: t})
+	}
+
+	tenant := sso.TenantOrDefault()
+	// The discovery of the multi-tenant endpoints returns the issuer template
+	// which does not match the issuer of the tokens, so the keys are fetched
+	// directly and the issuer is checked by checkIssuer.
+	c.keySet = oidc.NewRemoteKeySet(ctx, fmt.Sprintf("%s/%s/discovery/v2.0/keys", authority, tenant))
+
+	cfg := &oauth2.Config{
+		ClientID:     sso.ClientId,
+		ClientSecret: sso.ClientSecret,
+		RedirectURL:  sso.RedirectUri,
+		Endpoint:     microsoft.AzureADEndpoint(tenant),
+	}
+	token, err := obtainToken(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.Token = token
+
+	return c, nil
+}
+
+// GetUser returns a user model.
+func (c *OAuthClient) GetUser(ctx context.Context) (*model.User, error) {
+	idTokenRAW, ok := c.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("no id_token in oauth2 token")
+	}
+
+	verifier := oidc.NewVerifier("", c.keySet, &oidc.Config{
+		ClientID:        c.sso.ClientId,
+		SkipIssuerCheck: true,
+	})
+	idToken, err := verifier.Verify(ctx, idTokenRAW)
+	if err != nil {
+		return nil, err
+	}
+
+	var cl claims
+	if err := idToken.Claims(&cl); err != nil {
+		return nil, err
+	}
+	if err := c.checkTenant(cl.TenantID); err != nil {
+		return nil, err
+	}
+	if err := checkIssuer(cl.Issuer, cl.TenantID); err != nil {
+		return nil, err
+	}
+	if _, ok := cl.ClaimNames["groups"]; ok {
+		return nil, fmt.Errorf("user belongs to too many groups to be included in the token, configure the application to emit only the groups assigned to it")
+	}
+
+	username := cl.PreferredUsername
+	if username == "" {
+		username = cl.Email
+	}
+	if username == "" {
+		return nil, fmt.Errorf("no username found in claims")
+	}
+
+	role, err := c.decideRole(username, cl.Groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.User{
+		Username: username,
+		Role:     role,
+		Groups:   c.project.MatchedUserGroups(cl.Groups),
+	}, nil
+}
+
+// allowedTenants returns the tenants allowed to log in.
+// Only the configured tenant is allowed if it is a tenant ID and no allowed tenants are given.
+func (c *OAuthClient) allowedTenants() []string {
+	if len(c.sso.AllowedTenants) != 0 {
+		return c.sso.AllowedTenants
+	}
+	tenant := c.sso.TenantOrDefault()
+	if slices.Contains(multiTenants, tenant) {
+		return nil
+	}
+	return []string{tenant}
+}
+
+func (c *OAuthClient) checkTenant(tid string) error {
+	if tid == "" {
+		return fmt.Errorf("%w: missing tid in id_token", ErrTenantNotPermitted)
+	}
+	allowed := c.allowedTenants()
+	if len(allowed) == 0 {
+		return fmt.Errorf("%w: no allowed tenants are configured for the %s tenant", ErrTenantNotPermitted, c.sso.TenantOrDefault())
+	}
+	for _, t := range allowed {
+		if strings.EqualFold(t, tid) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrTenantNotPermitted, tid)
+}
+
+func checkIssuer(iss, tid string) error {
+	expected := strings.Replace(issuerTemplate, "{tenantid}", tid, 1)
+	if iss != expected {
+		return fmt.Errorf("invalid id_token issuer: expected %q but got %q", expected, iss)
+	}
+	return nil
+}
+
+func (c *OAuthClient) decideRole(user string, groups []string) (role *model.Role, err error) {
+	role = &model.Role{
+		ProjectId:        c.project.Id,
+		ProjectRbacRoles: make([]string, 0, len(groups)),
+	}
+	userGroups := c.project.UserGroups
+	roles := make(map[string]string, len(userGroups))
+	for _, g := range userGroups {
+		roles[g.SsoGroup] = g.Role
+	}
+
+	for _, g := range groups {
+		if v, ok := roles[g]; ok {
+			role.ProjectRbacRoles = append(role.ProjectRbacRoles, v)
+		}
+	}
+
+	if len(role.ProjectRbacRoles) != 0 {
+		return
+	}
+
+	if c.project.AssignStrayRoles(role) {
+		return
+	}
+
+	err = fmt.Errorf("user (%s) not found in any of the %d project groups", user, len(groups))
+	return
+}
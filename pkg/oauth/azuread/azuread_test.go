@@ -0,0 +1,188 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package azuread
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	tenantA = "00000000-0000-0000-0000-00000000000a"
+	tenantB = "00000000-0000-0000-0000-00000000000b"
+)
+
+func TestCheckTenant(t *testing.T) {
+	cases := []struct {
+		name           string
+		tenant         string
+		allowedTenants []string
+		tid            string
+		wantErr        bool
+	}{
+		{
+			name:   "single tenant",
+			tenant: tenantA,
+			tid:    tenantA,
+		},
+		{
+			name:    "other tenant of single tenant",
+			tenant:  tenantA,
+			tid:     tenantB,
+			wantErr: true,
+		},
+		{
+			name:           "allowed tenant of multi-tenant",
+			tenant:         "organizations",
+			allowedTenants: []string{tenantA, tenantB},
+			tid:            tenantB,
+		},
+		{
+			name:           "disallowed tenant of multi-tenant",
+			tenant:         "common",
+			allowedTenants: []string{tenantA},
+			tid:            tenantB,
+			wantErr:        true,
+		},
+		{
+			name:    "multi-tenant without allowed tenants",
+			tid:     tenantA,
+			wantErr: true,
+		},
+		{
+			name:    "missing tid",
+			tenant:  tenantA,
+			tid:     "",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &OAuthClient{
+				sso: &model.ProjectSSOConfig_AzureAD{
+					Tenant:         tc.tenant,
+					AllowedTenants: tc.allowedTenants,
+				},
+			}
+			err := c.checkTenant(tc.tid)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrTenantNotPermitted))
+			}
+		})
+	}
+}
+
+func TestCheckIssuer(t *testing.T) {
+	cases := []struct {
+		name    string
+		iss     string
+		tid     string
+		wantErr bool
+	}{
+		{
+			name: "v2.0 issuer",
+			iss:  "https://login.microsoftonline.com/" + tenantA + "/v2.0",
+			tid:  tenantA,
+		},
+		{
+			name:    "issuer of other tenant",
+			iss:     "https://login.microsoftonline.com/" + tenantB + "/v2.0",
+			tid:     tenantA,
+			wantErr: true,
+		},
+		{
+			name:    "v1.0 issuer",
+			iss:     "https://sts.windows.net/" + tenantA + "/",
+			tid:     tenantA,
+			wantErr: true,
+		},
+		{
+			name:    "issuer template",
+			iss:     "https://login.microsoftonline.com/{tenantid}/v2.0",
+			tid:     tenantA,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkIssuer(tc.iss, tc.tid)
+			assert.Equal(t, tc.wantErr, err != nil)
+		})
+	}
+}
+
+func TestDecideRole(t *testing.T) {
+	cases := []struct {
+		name    string
+		project *model.Project
+		groups  []string
+		role    *model.Role
+		wantErr bool
+	}{
+		{
+			name: "matched groups",
+			project: &model.Project{
+				Id: "id",
+				UserGroups: []*model.ProjectUserGroup{
+					{SsoGroup: "11111111-1111-1111-1111-111111111111", Role: "Admin"},
+					{SsoGroup: "22222222-2222-2222-2222-222222222222", Role: "Viewer"},
+				},
+			},
+			groups: []string{"22222222-2222-2222-2222-222222222222", "33333333-3333-3333-3333-333333333333"},
+			role: &model.Role{
+				ProjectId:        "id",
+				ProjectRbacRoles: []string{"Viewer"},
+			},
+		},
+		{
+			name: "viewer as default",
+			project: &model.Project{
+				Id:                 "id",
+				AllowStrayAsViewer: true,
+			},
+			groups: []string{"33333333-3333-3333-3333-333333333333"},
+			role: &model.Role{
+				ProjectId:        "id",
+				ProjectRbacRoles: []string{model.BuiltinRBACRoleViewer.String()},
+			},
+		},
+		{
+			name: "nothing",
+			project: &model.Project{
+				Id: "id",
+			},
+			groups:  []string{"33333333-3333-3333-3333-333333333333"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &OAuthClient{project: tc.project}
+			role, err := c.decideRole("user@example.com", tc.groups)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if err == nil {
+				assert.Equal(t, tc.role, role)
+			}
+		})
+	}
+}
@@ -0,0 +1,325 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	cloudAPIURL = "https://api.bitbucket.org"
+	listPerPage = 100
+	// maxPages limits the pages to follow in case the API keeps returning the next page.
+	maxPages = 50
+)
+
+// OAuthClient is a oauth client for Bitbucket Cloud and Server.
+type OAuthClient struct {
+	*http.Client
+	*oauth2.Token
+
+	flavor model.ProjectSSOConfig_Bitbucket_Flavor
+	// apiURL is the address of the REST API, which is a different host from the web for Cloud.
+	apiURL  string
+	project *model.Project
+}
+
+// NewOAuthClient creates a new oauth client for Bitbucket.
+func NewOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Bitbucket,
+	project *model.Project,
+	code string,
+) (*OAuthClient, error) {
+	return newOAuthClient(ctx, sso, project, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.Exchange(ctx, code)
+	})
+}
+
+// RefreshOAuthClient creates a new oauth client for Bitbucket by the token issued to the user at login,
+// which is refreshed if expired. It is used to look up the user again after login.
+func RefreshOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Bitbucket,
+	project *model.Project,
+	token *oauth2.Token,
+) (*OAuthClient, error) {
+	return newOAuthClient(ctx, sso, project, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.TokenSource(ctx, token).Token()
+	})
+}
+
+// newOAuthClient creates a new oauth client for Bitbucket by the token obtained by the given function.
+func newOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Bitbucket,
+	project *model.Project,
+	obtainToken func(context.Context, *oauth2.Config) (*oauth2.Token, error),
+) (*OAuthClient, error) {
+	endpoint, err := sso.Endpoint()
+	if err != nil {
+		return nil, err
+	}
+	c := &OAuthClient{
+		flavor:  sso.Flavor,
+		apiURL:  cloudAPIURL,
+		project: project,
+	}
+	if sso.Flavor == model.ProjectSSOConfig_Bitbucket_SERVER {
+		// The REST API of Bitbucket Server is served by the same host.
+		if c.apiURL, err = sso.BaseURL(); err != nil {
+			return nil, err
+		}
+	}
+	cfg := &oauth2.Config{
+		ClientID:     sso.ClientId,
+		ClientSecret: sso.ClientSecret,
+		RedirectURL:  sso.RedirectUri,
+		Endpoint:     endpoint,
+		Scopes:       sso.AllScopes(),
+	}
+
+	if sso.ProxyUrl != "" {
+		proxyURL, err := url.Parse(sso.ProxyUrl)
+		if err != nil {
+			return nil, err
+		}
+
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyURL(proxyURL)
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: t})
+	}
+
+	token, err := obtainToken(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Token = token
+	c.Client = cfg.Client(ctx, token)
+	return c, nil
+}
+
+// GetUser returns a user model.
+// The workspaces of the user are mapped to the user groups for Cloud,
+// and the groups of the user for Server.
+func (c *OAuthClient) GetUser(ctx context.Context) (*model.User, error) {
+	var (
+		username, avatarURL string
+		groups              []string
+		err                 error
+	)
+	if c.flavor == model.ProjectSSOConfig_Bitbucket_SERVER {
+		username, err = c.getServerUsername(ctx)
+		if err == nil {
+			groups, err = c.listServerGroups(ctx, username)
+		}
+	} else {
+		username, avatarURL, err = c.getCloudUser(ctx)
+		if err == nil {
+			groups, err = c.listCloudWorkspaces(ctx)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := c.decideRole(username, groups)
+	if err != nil {
+		return nil, err
+	}
+	return &model.User{
+		Username:  username,
+		AvatarUrl: avatarURL,
+		Role:      role,
+		Groups:    c.project.MatchedUserGroups(groups),
+	}, nil
+}
+
+type cloudUser struct {
+	Username string `json:"username"`
+	Nickname string `json:"nickname"`
+	Links    struct {
+		Avatar struct {
+			Href string `json:"href"`
+		} `json:"avatar"`
+	} `json:"links"`
+}
+
+func (c *OAuthClient) getCloudUser(ctx context.Context) (username, avatarURL string, err error) {
+	var u cloudUser
+	if err := c.get(ctx, c.apiURL+"/2.0/user", &u); err != nil {
+		return "", "", err
+	}
+	// The username is not returned for the accounts migrated to Atlassian accounts.
+	username = u.Username
+	if username == "" {
+		username = u.Nickname
+	}
+	if username == "" {
+		return "", "", fmt.Errorf("no username found in the Bitbucket user")
+	}
+	return username, u.Links.Avatar.Href, nil
+}
+
+type cloudWorkspacePage struct {
+	Values []struct {
+		Workspace struct {
+			Slug string `json:"slug"`
+		} `json:"workspace"`
+	} `json:"values"`
+	Next string `json:"next"`
+}
+
+func (c *OAuthClient) listCloudWorkspaces(ctx context.Context) ([]string, error) {
+	var (
+		workspaces []string
+		next       = fmt.Sprintf("%s/2.0/user/permissions/workspaces?pagelen=%d", c.apiURL, listPerPage)
+	)
+	for i := 0; next != ""; i++ {
+		if i == maxPages {
+			return nil, fmt.Errorf("too many pages of workspaces")
+		}
+		// The token must not be sent to other hosts.
+		if !strings.HasPrefix(next, c.apiURL+"/") {
+			return nil, fmt.Errorf("unexpected next page %q", next)
+		}
+		var page cloudWorkspacePage
+		if err := c.get(ctx, next, &page); err != nil {
+			return nil, err
+		}
+		for _, v := range page.Values {
+			if v.Workspace.Slug != "" {
+				workspaces = append(workspaces, v.Workspace.Slug)
+			}
+		}
+		next = page.Next
+	}
+	return workspaces, nil
+}
+
+// getServerUsername returns the name of the authenticated user.
+// Bitbucket Server has no REST API for it but the whoami servlet of the application links.
+func (c *OAuthClient) getServerUsername(ctx context.Context) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.apiURL+"/plugins/servlet/applinks/whoami", nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("unexpected status code %d from whoami: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	username := strings.TrimSpace(string(body))
+	if username == "" {
+		return "", fmt.Errorf("no username found in the Bitbucket user")
+	}
+	return username, nil
+}
+
+type serverGroupPage struct {
+	Values []struct {
+		Name string `json:"name"`
+	} `json:"values"`
+	IsLastPage    bool `json:"isLastPage"`
+	NextPageStart int  `json:"nextPageStart"`
+}
+
+func (c *OAuthClient) listServerGroups(ctx context.Context, username string) ([]string, error) {
+	var groups []string
+	start := 0
+	for i := 0; ; i++ {
+		if i == maxPages {
+			return nil, fmt.Errorf("too many pages of groups")
+		}
+		u := fmt.Sprintf("%s/rest/api/latest/admin/users/more-members?context=%s&limit=%d&start=%d", c.apiURL, url.QueryEscape(username), listPerPage, start)
+		var page serverGroupPage
+		if err := c.get(ctx, u, &page); err != nil {
+			return nil, err
+		}
+		for _, v := range page.Values {
+			if v.Name != "" {
+				groups = append(groups, v.Name)
+			}
+		}
+		if page.IsLastPage || page.NextPageStart <= start {
+			return groups, nil
+		}
+		start = page.NextPageStart
+	}
+}
+
+func (c *OAuthClient) get(ctx context.Context, u string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %d from %s: %s", resp.StatusCode, req.URL.Path, strings.TrimSpace(string(body)))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (c *OAuthClient) decideRole(user string, groups []string) (role *model.Role, err error) {
+	role = &model.Role{
+		ProjectId:        c.project.Id,
+		ProjectRbacRoles: make([]string, 0, len(groups)),
+	}
+	userGroups := c.project.UserGroups
+	roles := make(map[string]string, len(userGroups))
+	for _, g := range userGroups {
+		roles[g.SsoGroup] = g.Role
+	}
+
+	for _, g := range groups {
+		if v, ok := roles[g]; ok {
+			role.ProjectRbacRoles = append(role.ProjectRbacRoles, v)
+		}
+	}
+
+	if len(role.ProjectRbacRoles) != 0 {
+		return
+	}
+
+	if c.project.AssignStrayRoles(role) {
+		return
+	}
+
+	err = fmt.Errorf("user (%s) not found in any of the %d project groups", user, len(groups))
+	return
+}
@@ -0,0 +1,134 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bitbucket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func testProject() *model.Project {
+	return &model.Project{
+		Id: "id",
+		UserGroups: []*model.ProjectUserGroup{
+			{
+				SsoGroup: "team-editor",
+				Role:     "Editor",
+			},
+		},
+	}
+}
+
+func TestGetUser_Cloud(t *testing.T) {
+	mux := http.NewServeMux()
+	var server *httptest.Server
+	mux.HandleFunc("/2.0/user", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"nickname": "foo", "links": {"avatar": {"href": "https://example.com/foo.png"}}}`)
+	})
+	mux.HandleFunc("/2.0/user/permissions/workspaces", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "" {
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"values": []interface{}{map[string]interface{}{"workspace": map[string]string{"slug": "other"}}},
+				"next":   server.URL + "/2.0/user/permissions/workspaces?pagelen=100&page=2",
+			})
+			return
+		}
+		fmt.Fprint(w, `{"values": [{"workspace": {"slug": "team-editor"}}]}`)
+	})
+	server = httptest.NewServer(mux)
+	defer server.Close()
+
+	c := &OAuthClient{
+		Client:  server.Client(),
+		apiURL:  server.URL,
+		project: testProject(),
+	}
+	user, err := c.GetUser(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "foo", user.Username)
+	assert.Equal(t, "https://example.com/foo.png", user.AvatarUrl)
+	assert.Equal(t, []string{"Editor"}, user.Role.ProjectRbacRoles)
+}
+
+func TestListCloudWorkspaces_OtherHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"values": [], "next": "https://attacker.example.com/2.0/user/permissions/workspaces"}`)
+	}))
+	defer server.Close()
+
+	c := &OAuthClient{
+		Client:  server.Client(),
+		apiURL:  server.URL,
+		project: testProject(),
+	}
+	_, err := c.listCloudWorkspaces(context.Background())
+	assert.Error(t, err)
+}
+
+func TestGetUser_Server(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/plugins/servlet/applinks/whoami", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "foo\n")
+	})
+	mux.HandleFunc("/rest/api/latest/admin/users/more-members", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("context") != "foo" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("start") == "0" {
+			fmt.Fprint(w, `{"values": [{"name": "other"}], "isLastPage": false, "nextPageStart": 1}`)
+			return
+		}
+		fmt.Fprint(w, `{"values": [{"name": "team-editor"}], "isLastPage": true}`)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := &OAuthClient{
+		Client:  server.Client(),
+		flavor:  model.ProjectSSOConfig_Bitbucket_SERVER,
+		apiURL:  server.URL,
+		project: testProject(),
+	}
+	user, err := c.GetUser(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "foo", user.Username)
+	assert.Equal(t, []string{"Editor"}, user.Role.ProjectRbacRoles)
+}
+
+func TestDecideRole(t *testing.T) {
+	c := &OAuthClient{project: testProject()}
+
+	role, err := c.decideRole("foo", []string{"team-editor", "other"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Editor"}, role.ProjectRbacRoles)
+
+	_, err = c.decideRole("foo", []string{"other"})
+	assert.Error(t, err)
+
+	c.project.AllowStrayAsViewer = true
+	role, err = c.decideRole("foo", []string{"other"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.BuiltinRBACRoleViewer.String()}, role.ProjectRbacRoles)
+}
@@ -0,0 +1,140 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-github/v29/github"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	// appJWTTTL is the lifetime of the JWT authenticating as the GitHub App, GitHub accepts up to 10 minutes.
+	appJWTTTL = 9 * time.Minute
+	// appJWTClockSkew backdates the JWT to allow the clock of GitHub to be behind ours.
+	appJWTClockSkew = time.Minute
+)
+
+// sharedAppTokens is shared by all GitHub clients so that the installation token
+// is reused by the logins until it expires instead of being created for every login.
+var sharedAppTokens = &appTokenCache{sources: make(map[string]oauth2.TokenSource)}
+
+type appTokenCache struct {
+	mu      sync.Mutex
+	sources map[string]oauth2.TokenSource
+}
+
+// tokenSource returns the cached token source of the given key, or the one created by newSource.
+func (c *appTokenCache) tokenSource(key string, newSource func() oauth2.TokenSource) oauth2.TokenSource {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ts, ok := c.sources[key]; ok {
+		return ts
+	}
+	ts := oauth2.ReuseTokenSource(nil, newSource())
+	c.sources[key] = ts
+	return ts
+}
+
+// appTokenSource creates the installation tokens of the GitHub App.
+type appTokenSource struct {
+	client         *github.Client
+	appID          int64
+	installationID int64
+	key            *rsa.PrivateKey
+	now            func() time.Time
+}
+
+// Token creates a new installation token authenticating as the GitHub App by the JWT signed by its private key.
+func (s *appTokenSource) Token() (*oauth2.Token, error) {
+	signed, err := s.appJWT()
+	if err != nil {
+		return nil, err
+	}
+	req, err := s.client.NewRequest(http.MethodPost, fmt.Sprintf("app/installations/%d/access_tokens", s.installationID), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+signed)
+
+	var token github.InstallationToken
+	if _, err := s.client.Do(context.Background(), req, &token); err != nil {
+		return nil, fmt.Errorf("failed to create the installation token of GitHub App %d: %w", s.appID, err)
+	}
+	return &oauth2.Token{
+		AccessToken: token.GetToken(),
+		TokenType:   "Bearer",
+		Expiry:      token.GetExpiresAt(),
+	}, nil
+}
+
+func (s *appTokenSource) appJWT() (string, error) {
+	now := s.now()
+	claims := jwt.RegisteredClaims{
+		Issuer:    strconv.FormatInt(s.appID, 10),
+		IssuedAt:  jwt.NewNumericDate(now.Add(-appJWTClockSkew)),
+		ExpiresAt: jwt.NewNumericDate(now.Add(appJWTTTL)),
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodRS256, claims).SignedString(s.key)
+}
+
+// newAppClient returns the client calling the API of GitHub as the installation of the GitHub App.
+// The given client is used to call GitHub, including the creation of the installation token.
+func newAppClient(sso *model.ProjectSSOConfig_GitHub, httpClient *http.Client) (*github.Client, error) {
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(sso.AppPrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid app_private_key: %w", err)
+	}
+	tokenClient, err := newAPIClient(sso, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	// The token is shared only by the configurations of the same app, installation, key and addresses.
+	cacheKey := fmt.Sprintf("%s|%s|%s|%d|%d|%x", sso.APIURL(), sso.UploadUrl, sso.ProxyUrl, sso.AppId, sso.AppInstallationId, sha256.Sum256([]byte(sso.AppPrivateKey)))
+	ts := sharedAppTokens.tokenSource(cacheKey, func() oauth2.TokenSource {
+		return &appTokenSource{
+			client:         tokenClient,
+			appID:          sso.AppId,
+			installationID: sso.AppInstallationId,
+			key:            key,
+			now:            time.Now,
+		}
+	})
+
+	return newAPIClient(sso, &http.Client{
+		Transport: &oauth2.Transport{Source: ts, Base: httpClient.Transport},
+		Timeout:   httpClient.Timeout,
+	})
+}
+
+// newAPIClient returns the client of github.com, or of GitHub Enterprise Server if configured.
+func newAPIClient(sso *model.ProjectSSOConfig_GitHub, httpClient *http.Client) (*github.Client, error) {
+	if apiURL := sso.APIURL(); apiURL != "" {
+		return github.NewEnterpriseClient(apiURL, sso.UploadUrl, httpClient)
+	}
+	return github.NewClient(httpClient), nil
+}
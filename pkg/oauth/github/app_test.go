@@ -0,0 +1,179 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/go-github/v29/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func generateAppKey(t *testing.T) (*rsa.PrivateKey, string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	pemKey := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return key, string(pemKey)
+}
+
+func TestNewAppClient(t *testing.T) {
+	key, pemKey := generateAppKey(t)
+	var tokenRequests atomic.Int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v3/app/installations/2/access_tokens":
+			tokenRequests.Add(1)
+			assert.Equal(t, http.MethodPost, r.Method)
+			claims := &jwt.RegisteredClaims{}
+			_, err := jwt.ParseWithClaims(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "), claims, func(*jwt.Token) (interface{}, error) {
+				return &key.PublicKey, nil
+			}, jwt.WithValidMethods([]string{"RS256"}))
+			if !assert.NoError(t, err) {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "1", claims.Issuer)
+			fmt.Fprintf(w, `{"token":"installation-token","expires_at":%q}`, time.Now().Add(time.Hour).Format(time.RFC3339))
+		case "/api/v3/orgs/org/memberships/user":
+			if r.Header.Get("Authorization") != "Bearer installation-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprint(w, `{"state":"active","role":"member"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	sso := &model.ProjectSSOConfig_GitHub{
+		BaseUrl:           srv.URL,
+		AppId:             1,
+		AppInstallationId: 2,
+		AppPrivateKey:     pemKey,
+	}
+	for i := 0; i < 2; i++ {
+		cli, err := newAppClient(sso, srv.Client())
+		require.NoError(t, err)
+		m, _, err := cli.Organizations.GetOrgMembership(context.Background(), "user", "org")
+		require.NoError(t, err)
+		assert.Equal(t, "active", m.GetState())
+	}
+	// The installation token is reused until it expires.
+	assert.Equal(t, int32(1), tokenRequests.Load())
+
+	_, err := newAppClient(&model.ProjectSSOConfig_GitHub{AppId: 1, AppInstallationId: 2, AppPrivateKey: "invalid"}, srv.Client())
+	assert.ErrorContains(t, err, "invalid app_private_key")
+}
+
+func TestAppTokenSourceError(t *testing.T) {
+	key, _ := generateAppKey(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"message":"A JSON web token could not be decoded"}`)
+	}))
+	defer srv.Close()
+
+	cli := github.NewClient(srv.Client())
+	u, err := url.Parse(srv.URL + "/")
+	require.NoError(t, err)
+	cli.BaseURL = u
+
+	ts := &appTokenSource{client: cli, appID: 1, installationID: 2, key: key, now: time.Now}
+	_, err = ts.Token()
+	assert.ErrorContains(t, err, "failed to create the installation token of GitHub App 1")
+}
+
+func TestAppLookups(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/orgs/active-org/memberships/user":
+			fmt.Fprint(w, `{"state":"active","role":"member"}`)
+		case "/orgs/org/teams/team-admin/memberships/user":
+			fmt.Fprint(w, `{"state":"active","role":"member"}`)
+		case "/orgs/org/teams/team-editor/memberships/user":
+			fmt.Fprint(w, `{"state":"pending","role":"member"}`)
+		case "/orgs/org/teams/broken/memberships/user":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	appClient := github.NewClient(srv.Client())
+	u, err := url.Parse(srv.URL + "/")
+	require.NoError(t, err)
+	appClient.BaseURL = u
+
+	newClient := func(groups ...string) *OAuthClient {
+		project := &model.Project{Id: "project"}
+		for _, g := range groups {
+			project.UserGroups = append(project.UserGroups, &model.ProjectUserGroup{SsoGroup: g, Role: "Viewer"})
+		}
+		// The client of the user must not be used for the lookups.
+		return &OAuthClient{Client: github.NewClient(nil), appClient: appClient, project: project}
+	}
+
+	t.Run("org membership", func(t *testing.T) {
+		c := newClient()
+		c.requiredOrgs = []string{"other-org", "active-org"}
+		assert.NoError(t, c.checkOrgMembership(context.Background(), "user"))
+
+		c.requiredOrgs = []string{"other-org"}
+		assert.ErrorIs(t, c.checkOrgMembership(context.Background(), "user"), ErrNotOrgMember)
+	})
+
+	t.Run("teams", func(t *testing.T) {
+		teams, err := newClient("org/team-admin", "org/team-editor", "org/team-viewer", "invalid").listUserTeams(context.Background(), "user")
+		require.NoError(t, err)
+		require.Len(t, teams, 1)
+		assert.Equal(t, "org", teams[0].Organization.GetLogin())
+		assert.Equal(t, "team-admin", teams[0].GetSlug())
+
+		_, err = newClient("org/broken").listUserTeams(context.Background(), "user")
+		assert.ErrorContains(t, err, "failed to get the membership of team org/broken")
+	})
+
+	t.Run("teams by bare slug", func(t *testing.T) {
+		c := newClient("team-admin")
+		teams, err := c.listUserTeams(context.Background(), "user")
+		require.NoError(t, err)
+		assert.Empty(t, teams)
+
+		c.requiredOrgs = []string{"other-org", "org"}
+		teams, err = c.listUserTeams(context.Background(), "user")
+		require.NoError(t, err)
+		require.Len(t, teams, 1)
+		assert.Equal(t, "org", teams[0].Organization.GetLogin())
+		assert.Equal(t, "team-admin", teams[0].GetSlug())
+	})
+}
@@ -16,107 +16,316 @@ package github
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
+	"strings"
 
 	"github.com/google/go-github/v29/github"
 	"golang.org/x/oauth2"
-	oauth2github "golang.org/x/oauth2/github"
 
 	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oauthhttp"
 )
 
 const (
 	listPerPage = 100
+
+	customRolePrecedence = 3
 )
 
+// ErrCodeExpired is returned when GitHub rejects the auth code as incorrect or expired,
+// e.g. the user stayed on the authorization page too long.
+var ErrCodeExpired = errors.New("auth code expired")
+
+// ErrNotOrgMember is returned when the user is not an active member of any of the required organizations.
+var ErrNotOrgMember = errors.New("not a member of an allowed organization")
+
 // OAuthClient is a oauth client for github.
 type OAuthClient struct {
 	*github.Client
+	*oauth2.Token
 
 	project *model.Project
+	// requiredOrgs are the organizations one of which the user must be a member of.
+	// Empty means no membership is required.
+	requiredOrgs []string
+	// appClient calls GitHub as the installation of the GitHub App to look up the organizations and teams of the user.
+	// Nil means they are looked up by the OAuth token of the user.
+	appClient *github.Client
 }
 
 // NewOAuthClient creates a new oauth client for GitHub.
+// The redirect URI must be the one used in the authorization request and in the given allowed list.
 func NewOAuthClient(ctx context.Context,
 	sso *model.ProjectSSOConfig_GitHub,
 	project *model.Project,
 	code string,
+	redirectURI string,
+	allowedRedirectURIs []string,
+) (*OAuthClient, error) {
+	if err := model.CheckRedirectURI(redirectURI, allowedRedirectURIs); err != nil {
+		return nil, err
+	}
+	return newOAuthClient(ctx, sso, project, redirectURI, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		token, err := cfg.Exchange(ctx, code)
+		if err != nil && isCodeExpired(err) {
+			return nil, fmt.Errorf("%w: %w", ErrCodeExpired, err)
+		}
+		return token, err
+	})
+}
+
+// RefreshOAuthClient creates a new oauth client for GitHub by the token issued to the user at login,
+// which is refreshed if expired. It is used to look up the user again after login.
+func RefreshOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_GitHub,
+	project *model.Project,
+	token *oauth2.Token,
+) (*OAuthClient, error) {
+	return newOAuthClient(ctx, sso, project, "", func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.TokenSource(ctx, token).Token()
+	})
+}
+
+// newOAuthClient creates a new oauth client for GitHub by the token obtained by the given function.
+func newOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_GitHub,
+	project *model.Project,
+	redirectURI string,
+	obtainToken func(context.Context, *oauth2.Config) (*oauth2.Token, error),
 ) (*OAuthClient, error) {
+	if err := sso.ValidateURLs(); err != nil {
+		return nil, err
+	}
+	if err := sso.ValidateApp(); err != nil {
+		return nil, err
+	}
+	if err := sso.ValidateExtraHeaders(); err != nil {
+		return nil, err
+	}
+	endpoint, err := sso.Endpoint()
+	if err != nil {
+		return nil, err
+	}
 	c := &OAuthClient{
-		project: project,
+		project:      project,
+		requiredOrgs: sso.RequiredOrgs,
 	}
-	cfg := oauth2.Config{
+	cfg := &oauth2.Config{
 		ClientID:     sso.ClientId,
 		ClientSecret: sso.ClientSecret,
-		Endpoint:     oauth2github.Endpoint,
+		Endpoint:     endpoint,
+		RedirectURL:  redirectURI,
 	}
 
-	if sso.ProxyUrl != "" {
-		proxyURL, err := url.Parse(sso.ProxyUrl)
-		if err != nil {
-			return nil, err
-		}
+	// The calls to GitHub are bounded by the timeouts of the client in addition to the context.
+	ctx, httpClient, err := oauthhttp.ContextWithClient(ctx, sso.ProxyUrl, sso.ExtraHeaders)
+	if err != nil {
+		return nil, err
+	}
 
-		t := http.DefaultTransport.(*http.Transport).Clone()
-		t.Proxy = http.ProxyURL(proxyURL)
-		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: t})
+	token, err := obtainToken(ctx, cfg)
+	if err != nil {
+		return nil, err
 	}
+	c.Token = token
 
-	if sso.BaseUrl != "" {
-		baseURL, err := url.Parse(sso.BaseUrl)
-		if err != nil {
-			return nil, err
-		}
-		cfg.Endpoint.TokenURL = fmt.Sprintf("%s://%s%s", baseURL.Scheme, baseURL.Host, "/login/oauth/access_token")
+	// The oauth2 client only keeps the transport of the base client.
+	apiClient := cfg.Client(ctx, token)
+	apiClient.Timeout = httpClient.Timeout
 
-		token, err := cfg.Exchange(ctx, code)
-		if err != nil {
+	// The user, org and team lookups go to GitHub Enterprise Server if configured.
+	if c.Client, err = newAPIClient(sso, apiClient); err != nil {
+		return nil, err
+	}
+	if sso.AppEnabled() {
+		if c.appClient, err = newAppClient(sso, httpClient); err != nil {
 			return nil, err
 		}
+	}
+	return c, nil
+}
+
+// TeamRole represents a GitHub team of the user which is mapped to a project role.
+type TeamRole struct {
+	Team string
+	Role string
+}
+
+// GetUser returns the user and all of its teams which are mapped to a project role.
+func (c *OAuthClient) GetUser(ctx context.Context) (*model.User, []TeamRole, error) {
+	user, _, err := c.Users.Get(ctx, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := c.checkOrgMembership(ctx, user.GetLogin()); err != nil {
+		return nil, nil, err
+	}
+	teams, err := c.listUserTeams(ctx, user.GetLogin())
+	if err != nil {
+		return nil, nil, err
+	}
+	email, verified := c.primaryEmail(ctx)
+	verifiedEmail := ""
+	if verified {
+		verifiedEmail = email
+	}
+	role, matched, err := c.decideRole(user.GetLogin(), verifiedEmail, teams)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	groups := make([]string, 0, len(matched))
+	for _, m := range matched {
+		groups = append(groups, m.Team)
+	}
 
-		cli, err := github.NewEnterpriseClient(sso.BaseUrl, sso.UploadUrl, cfg.Client(ctx, token))
+	return &model.User{
+		Username:      user.GetLogin(),
+		AvatarUrl:     user.GetAvatarURL(),
+		Role:          role,
+		Email:         email,
+		EmailVerified: verified,
+		Groups:        c.project.MatchedUserGroups(groups),
+	}, matched, nil
+}
+
+// checkOrgMembership checks whether the user is an active member of any of the required organizations.
+// The membership of the authenticated user is looked up instead of the public members list,
+// so the private membership is also found with the read:org scope, or by the GitHub App if configured.
+// The pending invitation is not counted as the membership.
+func (c *OAuthClient) checkOrgMembership(ctx context.Context, user string) error {
+	if len(c.requiredOrgs) == 0 {
+		return nil
+	}
+	// The empty user means the authenticated user, which is not available to the GitHub App.
+	cli, member := c.Client, ""
+	if c.appClient != nil {
+		cli, member = c.appClient, user
+	}
+	for _, org := range c.requiredOrgs {
+		m, resp, err := cli.Organizations.GetOrgMembership(ctx, member, org)
 		if err != nil {
-			return nil, err
+			// GitHub responds 404 if the user is not a member of the organization.
+			if resp != nil && resp.StatusCode == http.StatusNotFound {
+				continue
+			}
+			return fmt.Errorf("failed to get the membership of organization %s: %w", org, err)
 		}
+		if m.GetState() == "active" {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: user (%s) is not a member of any of %v", ErrNotOrgMember, user, c.requiredOrgs)
+}
 
-		c.Client = cli
-		return c, nil
+// isRequiredOrg reports whether the given organization is one of the required organizations.
+// Any organization is accepted if none is required.
+func (c *OAuthClient) isRequiredOrg(org string) bool {
+	if len(c.requiredOrgs) == 0 {
+		return true
 	}
+	for _, o := range c.requiredOrgs {
+		if strings.EqualFold(o, org) {
+			return true
+		}
+	}
+	return false
+}
 
-	token, err := cfg.Exchange(ctx, code)
-	if err != nil {
-		return nil, err
+// listUserTeams returns the teams of the user.
+// The GitHub App can not list the teams of the user, so the membership of each team
+// mapped to a project role is looked up instead. The team given by the bare slug
+// is looked up in each of the required organizations, and skipped if none is required.
+func (c *OAuthClient) listUserTeams(ctx context.Context, user string) ([]*github.Team, error) {
+	if c.appClient == nil {
+		teams, _, err := c.Teams.ListUserTeams(ctx, &github.ListOptions{PerPage: listPerPage})
+		return teams, err
 	}
 
-	c.Client = github.NewClient(cfg.Client(ctx, token))
-	return c, nil
+	var teams []*github.Team
+	for _, g := range c.project.UserGroups {
+		orgs := c.requiredOrgs
+		org, slug, ok := strings.Cut(g.SsoGroup, "/")
+		if ok {
+			orgs = []string{org}
+		} else {
+			slug = g.SsoGroup
+		}
+		for _, org := range orgs {
+			if org == "" || slug == "" {
+				continue
+			}
+			active, err := c.isActiveTeamMember(ctx, org, slug, user)
+			if err != nil {
+				return nil, err
+			}
+			if active {
+				teams = append(teams, &github.Team{
+					Organization: &github.Organization{Login: github.String(org)},
+					Slug:         github.String(slug),
+				})
+			}
+		}
+	}
+	return teams, nil
 }
 
-// GetUser returns a user model.
-func (c *OAuthClient) GetUser(ctx context.Context) (*model.User, error) {
-	user, _, err := c.Users.Get(ctx, "")
+// isActiveTeamMember reports whether the user is an active member of the team by the GitHub App.
+func (c *OAuthClient) isActiveTeamMember(ctx context.Context, org, slug, user string) (bool, error) {
+	u := fmt.Sprintf("orgs/%s/teams/%s/memberships/%s", url.PathEscape(org), url.PathEscape(slug), url.PathEscape(user))
+	req, err := c.appClient.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
-		return nil, err
+		return false, err
 	}
-	teams, _, err := c.Teams.ListUserTeams(ctx, &github.ListOptions{PerPage: listPerPage})
+	var m github.Membership
+	resp, err := c.appClient.Do(ctx, req, &m)
 	if err != nil {
-		return nil, err
+		// GitHub responds 404 if the user is not a member of the team or the team does not exist.
+		if resp != nil && resp.StatusCode == http.StatusNotFound {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get the membership of team %s/%s: %w", org, slug, err)
 	}
-	role, err := c.decideRole(user.GetLogin(), teams)
+	return m.GetState() == "active", nil
+}
+
+// primaryEmail returns the primary email of the user and whether it is verified.
+// The email is optional since it needs the user:email scope which
+// the OAuth apps authorized before may not have, so the errors are ignored.
+func (c *OAuthClient) primaryEmail(ctx context.Context) (string, bool) {
+	emails, _, err := c.Users.ListEmails(ctx, &github.ListOptions{PerPage: listPerPage})
 	if err != nil {
-		return nil, err
+		return "", false
 	}
+	for _, e := range emails {
+		if e.GetPrimary() {
+			return e.GetEmail(), e.GetVerified()
+		}
+	}
+	return "", false
+}
 
-	return &model.User{
-		Username:  user.GetLogin(),
-		AvatarUrl: user.GetAvatarURL(),
-		Role:      role,
-	}, nil
+// rolePrecedence returns the precedence of the given role, the lower is the more privileged.
+// Built-in roles are ordered as Admin > Editor > Viewer and come before the custom roles.
+func rolePrecedence(role string) int {
+	switch role {
+	case model.BuiltinRBACRoleAdmin.String():
+		return 0
+	case model.BuiltinRBACRoleEditor.String():
+		return 1
+	case model.BuiltinRBACRoleViewer.String():
+		return 2
+	default:
+		return customRolePrecedence
+	}
 }
 
-func (c *OAuthClient) decideRole(user string, teams []*github.Team) (role *model.Role, err error) {
+func (c *OAuthClient) decideRole(user, verifiedEmail string, teams []*github.Team) (role *model.Role, matched []TeamRole, err error) {
 	role = &model.Role{
 		ProjectId:        c.project.Id,
 		ProjectRbacRoles: make([]string, 0, len(teams)),
@@ -136,22 +345,64 @@ func (c *OAuthClient) decideRole(user string, teams []*github.Team) (role *model
 
 		t := fmt.Sprintf("%s/%s", org, slug)
 		if v, ok := roles[t]; ok {
-			role.ProjectRbacRoles = append(role.ProjectRbacRoles, v)
+			matched = append(matched, TeamRole{Team: t, Role: v})
+			continue
+		}
+		// The user group given by the bare slug without the organization matches the team
+		// of the same slug in any of the required organizations, or in any organization if none is required.
+		if v, ok := roles[slug]; ok && c.isRequiredOrg(org) {
+			matched = append(matched, TeamRole{Team: t, Role: v})
 		}
 	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		pi, pj := rolePrecedence(matched[i].Role), rolePrecedence(matched[j].Role)
+		if pi != pj {
+			return pi < pj
+		}
+		if matched[i].Role != matched[j].Role {
+			return matched[i].Role < matched[j].Role
+		}
+		return matched[i].Team < matched[j].Team
+	})
+
+	// Only the highest-privileged built-in role is granted since it includes
+	// all permissions of the lower ones, while all custom roles are kept.
+	builtinGranted := false
+	for _, m := range matched {
+		if rolePrecedence(m.Role) < customRolePrecedence {
+			if builtinGranted {
+				continue
+			}
+			builtinGranted = true
+		}
+		if slices.Contains(role.ProjectRbacRoles, m.Role) {
+			continue
+		}
+		role.ProjectRbacRoles = append(role.ProjectRbacRoles, m.Role)
+	}
 
 	if len(role.ProjectRbacRoles) != 0 {
 		return
 	}
 
-	// In case the current user does not belong to any registered
-	// teams, if AllowStrayAsViewer option is set, assign Viewer role
-	// as user's role.
-	if c.project.AllowStrayAsViewer {
-		role.ProjectRbacRoles = []string{model.BuiltinRBACRoleViewer.String()}
+	// In case the user is not in any of the teams,
+	// assign the roles mapped to the domain of the verified primary email.
+	if roles := c.project.RolesByEmailDomain(verifiedEmail); len(roles) > 0 {
+		role.ProjectRbacRoles = roles
+		return
+	}
+
+	if c.project.AssignStrayRoles(role) {
 		return
 	}
 
 	err = fmt.Errorf("user (%s) not found in any of the %d project teams", user, len(teams))
 	return
 }
+
+// isCodeExpired reports whether the token endpoint rejected the auth code.
+// GitHub responds bad_verification_code for the incorrect or expired code.
+func isCodeExpired(err error) bool {
+	var re *oauth2.RetrieveError
+	return errors.As(err, &re) && re.ErrorCode == "bad_verification_code"
+}
@@ -15,10 +15,18 @@
 package github
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"testing"
 
 	"github.com/google/go-github/v29/github"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 
 	"github.com/pipe-cd/pipecd/pkg/model"
 )
@@ -29,6 +37,7 @@ func TestDecideRole(t *testing.T) {
 	cases := []struct {
 		name     string
 		username string
+		email    string
 		oc       *OAuthClient
 		teams    []*github.Team
 		role     *model.Role
@@ -143,8 +152,6 @@ func TestDecideRole(t *testing.T) {
 				ProjectId: "id",
 				ProjectRbacRoles: []string{
 					model.BuiltinRBACRoleAdmin.String(),
-					model.BuiltinRBACRoleEditor.String(),
-					model.BuiltinRBACRoleViewer.String(),
 				},
 			},
 			wantErr: false,
@@ -190,7 +197,6 @@ func TestDecideRole(t *testing.T) {
 				ProjectId: "id",
 				ProjectRbacRoles: []string{
 					model.BuiltinRBACRoleEditor.String(),
-					model.BuiltinRBACRoleViewer.String(),
 				},
 			},
 			wantErr: false,
@@ -239,11 +245,62 @@ func TestDecideRole(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:     "email domain",
+			username: "foo",
+			email:    "foo@example.com",
+			oc: &OAuthClient{
+				project: &model.Project{
+					Id:                 "id",
+					AllowStrayAsViewer: true,
+					UserGroups: []*model.ProjectUserGroup{
+						{
+							SsoGroup: "org/team-admin",
+							Role:     "Admin",
+						},
+					},
+					EmailDomainRoles: []*model.ProjectEmailDomainRole{
+						{
+							Domain: "example.com",
+							Role:   "Editor",
+						},
+					},
+				},
+			},
+			teams: []*github.Team{
+				{
+					Organization: &github.Organization{Login: stringPointer("org")},
+					Slug:         stringPointer("team1"),
+				},
+			},
+			role: &model.Role{
+				ProjectId: "id",
+				ProjectRbacRoles: []string{
+					model.BuiltinRBACRoleEditor.String(),
+				},
+			},
+		},
+		{
+			name:     "unverified email domain",
+			username: "foo",
+			oc: &OAuthClient{
+				project: &model.Project{
+					Id: "id",
+					EmailDomainRoles: []*model.ProjectEmailDomainRole{
+						{
+							Domain: "example.com",
+							Role:   "Editor",
+						},
+					},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
-			role, err := tc.oc.decideRole(tc.username, tc.teams)
+			role, _, err := tc.oc.decideRole(tc.username, tc.email, tc.teams)
 			assert.Equal(t, tc.wantErr, err != nil)
 			if err == nil {
 				assert.Equal(t, tc.role, role)
@@ -251,3 +308,189 @@ func TestDecideRole(t *testing.T) {
 		})
 	}
 }
+
+func TestDecideRoleMatchedTeams(t *testing.T) {
+	oc := &OAuthClient{
+		project: &model.Project{
+			Id: "id",
+			UserGroups: []*model.ProjectUserGroup{
+				{
+					SsoGroup: "org/readonly",
+					Role:     "Viewer",
+				},
+				{
+					SsoGroup: "org/app-dev",
+					Role:     "Editor",
+				},
+				{
+					SsoGroup: "org/platform",
+					Role:     "Admin",
+				},
+				{
+					SsoGroup: "org/release",
+					Role:     "Releaser",
+				},
+				{
+					SsoGroup: "org/ops",
+					Role:     "Editor",
+				},
+			},
+		},
+	}
+	teams := []*github.Team{
+		{
+			Organization: &github.Organization{Login: stringPointer("org")},
+			Slug:         stringPointer("readonly"),
+		},
+		{
+			Organization: &github.Organization{Login: stringPointer("org")},
+			Slug:         stringPointer("release"),
+		},
+		{
+			Organization: &github.Organization{Login: stringPointer("org")},
+			Slug:         stringPointer("ops"),
+		},
+		{
+			Organization: &github.Organization{Login: stringPointer("org")},
+			Slug:         stringPointer("app-dev"),
+		},
+		{
+			Organization: &github.Organization{Login: stringPointer("org")},
+			Slug:         stringPointer("platform"),
+		},
+	}
+
+	role, matched, err := oc.decideRole("foo", "", teams)
+	assert.NoError(t, err)
+	assert.Equal(t, &model.Role{
+		ProjectId:        "id",
+		ProjectRbacRoles: []string{"Admin", "Releaser"},
+	}, role)
+	assert.Equal(t, []TeamRole{
+		{Team: "org/platform", Role: "Admin"},
+		{Team: "org/app-dev", Role: "Editor"},
+		{Team: "org/ops", Role: "Editor"},
+		{Team: "org/readonly", Role: "Viewer"},
+		{Team: "org/release", Role: "Releaser"},
+	}, matched)
+}
+
+func TestDecideRoleOrgScopedTeams(t *testing.T) {
+	newClient := func(requiredOrgs ...string) *OAuthClient {
+		return &OAuthClient{
+			project: &model.Project{
+				Id: "id",
+				UserGroups: []*model.ProjectUserGroup{
+					{
+						SsoGroup: "org-b/platform",
+						Role:     "Admin",
+					},
+					{
+						SsoGroup: "release",
+						Role:     "Releaser",
+					},
+					{
+						SsoGroup: "org-a/release",
+						Role:     "Viewer",
+					},
+				},
+			},
+			requiredOrgs: requiredOrgs,
+		}
+	}
+	team := func(org, slug string) *github.Team {
+		return &github.Team{
+			Organization: &github.Organization{Login: stringPointer(org)},
+			Slug:         stringPointer(slug),
+		}
+	}
+
+	// The team of the same slug in another organization does not match the user group of the organization.
+	_, _, err := newClient().decideRole("foo", "", []*github.Team{team("org-a", "platform")})
+	assert.Error(t, err)
+
+	// The user group of the organization takes precedence over the bare slug.
+	_, matched, err := newClient().decideRole("foo", "", []*github.Team{team("org-a", "release"), team("org-b", "release")})
+	require.NoError(t, err)
+	assert.Equal(t, []TeamRole{
+		{Team: "org-a/release", Role: "Viewer"},
+		{Team: "org-b/release", Role: "Releaser"},
+	}, matched)
+
+	// The bare slug only matches the teams of the required organizations if any.
+	_, matched, err = newClient("Org-B").decideRole("foo", "", []*github.Team{team("org-c", "release"), team("org-b", "release")})
+	require.NoError(t, err)
+	assert.Equal(t, []TeamRole{
+		{Team: "org-b/release", Role: "Releaser"},
+	}, matched)
+}
+
+func TestIsCodeExpired(t *testing.T) {
+	assert.True(t, isCodeExpired(&oauth2.RetrieveError{ErrorCode: "bad_verification_code"}))
+	assert.True(t, isCodeExpired(fmt.Errorf("exchange: %w", &oauth2.RetrieveError{ErrorCode: "bad_verification_code"})))
+	assert.False(t, isCodeExpired(&oauth2.RetrieveError{ErrorCode: "incorrect_client_credentials"}))
+	assert.False(t, isCodeExpired(errors.New("connection refused")))
+}
+
+func TestCheckOrgMembership(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user/memberships/orgs/active-org":
+			fmt.Fprint(w, `{"state":"active","role":"member"}`)
+		case "/user/memberships/orgs/pending-org":
+			fmt.Fprint(w, `{"state":"pending","role":"member"}`)
+		case "/user/memberships/orgs/broken-org":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	newClient := func(orgs ...string) *OAuthClient {
+		cli := github.NewClient(srv.Client())
+		u, err := url.Parse(srv.URL + "/")
+		require.NoError(t, err)
+		cli.BaseURL = u
+		return &OAuthClient{Client: cli, requiredOrgs: orgs}
+	}
+
+	testcases := []struct {
+		name    string
+		orgs    []string
+		wantErr error
+	}{
+		{
+			name: "no required orgs",
+		},
+		{
+			name: "active member",
+			orgs: []string{"other-org", "active-org"},
+		},
+		{
+			name:    "not a member",
+			orgs:    []string{"other-org"},
+			wantErr: ErrNotOrgMember,
+		},
+		{
+			name:    "pending invitation",
+			orgs:    []string{"pending-org"},
+			wantErr: ErrNotOrgMember,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := newClient(tc.orgs...).checkOrgMembership(context.Background(), "user")
+			if tc.wantErr == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Contains(t, err.Error(), "not a member of an allowed organization")
+		})
+	}
+
+	err := newClient("broken-org", "active-org").checkOrgMembership(context.Background(), "user")
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrNotOrgMember)
+}
@@ -0,0 +1,222 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitlab
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"golang.org/x/oauth2"
+	oauth2gitlab "golang.org/x/oauth2/gitlab"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	defaultBaseURL = "https://gitlab.com"
+	listPerPage    = 100
+	// Guest is the lowest access level, so listing groups with it
+	// returns every group the user is a member of.
+	minAccessLevel = 10
+)
+
+// OAuthClient is a oauth client for GitLab.
+type OAuthClient struct {
+	*http.Client
+	*oauth2.Token
+
+	baseURL string
+	project *model.Project
+}
+
+type user struct {
+	Username  string `json:"username"`
+	AvatarURL string `json:"avatar_url"`
+}
+
+type group struct {
+	FullPath string `json:"full_path"`
+}
+
+// NewOAuthClient creates a new oauth client for GitLab.
+func NewOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_GitLab,
+	project *model.Project,
+	code string,
+) (*OAuthClient, error) {
+	return newOAuthClient(ctx, sso, project, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.Exchange(ctx, code)
+	})
+}
+
+// RefreshOAuthClient creates a new oauth client for GitLab by the token issued to the user at login,
+// which is refreshed if expired. It is used to look up the user again after login.
+func RefreshOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_GitLab,
+	project *model.Project,
+	token *oauth2.Token,
+) (*OAuthClient, error) {
+	return newOAuthClient(ctx, sso, project, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.TokenSource(ctx, token).Token()
+	})
+}
+
+// newOAuthClient creates a new oauth client for GitLab by the token obtained by the given function.
+func newOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_GitLab,
+	project *model.Project,
+	obtainToken func(context.Context, *oauth2.Config) (*oauth2.Token, error),
+) (*OAuthClient, error) {
+	c := &OAuthClient{
+		baseURL: defaultBaseURL,
+		project: project,
+	}
+	cfg := &oauth2.Config{
+		ClientID:     sso.ClientId,
+		ClientSecret: sso.ClientSecret,
+		RedirectURL:  sso.RedirectUri,
+		Endpoint:     oauth2gitlab.Endpoint,
+	}
+
+	if sso.ProxyUrl != "" {
+		proxyURL, err := url.Parse(sso.ProxyUrl)
+		if err != nil {
+			return nil, err
+		}
+
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyURL(proxyURL)
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: t})
+	}
+
+	if sso.BaseUrl != "" {
+		baseURL, err := url.Parse(sso.BaseUrl)
+		if err != nil {
+			return nil, err
+		}
+		c.baseURL = fmt.Sprintf("%s://%s", baseURL.Scheme, baseURL.Host)
+		cfg.Endpoint.TokenURL = c.baseURL + "/oauth/token"
+	}
+
+	token, err := obtainToken(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Token = token
+	c.Client = cfg.Client(ctx, token)
+	return c, nil
+}
+
+// GetUser returns a user model.
+func (c *OAuthClient) GetUser(ctx context.Context) (*model.User, error) {
+	var u user
+	if _, err := c.get(ctx, "/api/v4/user", &u); err != nil {
+		return nil, err
+	}
+	groups, err := c.listGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	role, err := c.decideRole(u.Username, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(groups))
+	for _, g := range groups {
+		paths = append(paths, g.FullPath)
+	}
+	return &model.User{
+		Username:  u.Username,
+		AvatarUrl: u.AvatarURL,
+		Role:      role,
+		Groups:    c.project.MatchedUserGroups(paths),
+	}, nil
+}
+
+func (c *OAuthClient) listGroups(ctx context.Context) ([]*group, error) {
+	var groups []*group
+	page := "1"
+	for page != "" {
+		path := fmt.Sprintf("/api/v4/groups?min_access_level=%d&per_page=%d&page=%s", minAccessLevel, listPerPage, page)
+		var gs []*group
+		resp, err := c.get(ctx, path, &gs)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, gs...)
+		page = resp.Header.Get("X-Next-Page")
+	}
+	return groups, nil
+}
+
+func (c *OAuthClient) get(ctx context.Context, path string, v interface{}) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("unexpected status code %d from %s: %s", resp.StatusCode, path, strings.TrimSpace(string(body)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (c *OAuthClient) decideRole(user string, groups []*group) (role *model.Role, err error) {
+	role = &model.Role{
+		ProjectId:        c.project.Id,
+		ProjectRbacRoles: make([]string, 0, len(groups)),
+	}
+	userGroups := c.project.UserGroups
+	roles := make(map[string]string, len(userGroups))
+	for _, g := range userGroups {
+		roles[g.SsoGroup] = g.Role
+	}
+
+	for _, g := range groups {
+		if g.FullPath == "" {
+			continue
+		}
+		if v, ok := roles[g.FullPath]; ok {
+			role.ProjectRbacRoles = append(role.ProjectRbacRoles, v)
+		}
+	}
+
+	if len(role.ProjectRbacRoles) != 0 {
+		return
+	}
+
+	if c.project.AssignStrayRoles(role) {
+		return
+	}
+
+	err = fmt.Errorf("user (%s) not found in any of the %d project groups", user, len(groups))
+	return
+}
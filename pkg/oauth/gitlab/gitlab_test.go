@@ -0,0 +1,165 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestDecideRole(t *testing.T) {
+	userGroups := []*model.ProjectUserGroup{
+		{
+			SsoGroup: "org/team-admin",
+			Role:     "Admin",
+		},
+		{
+			SsoGroup: "org/team-editor",
+			Role:     "Editor",
+		},
+		{
+			SsoGroup: "org/sub/team-viewer",
+			Role:     "Viewer",
+		},
+	}
+	cases := []struct {
+		name     string
+		username string
+		oc       *OAuthClient
+		groups   []*group
+		role     *model.Role
+		wantErr  bool
+	}{
+		{
+			name:     "nothing",
+			username: "foo",
+			oc: &OAuthClient{
+				project: &model.Project{
+					Id:         "id",
+					UserGroups: userGroups,
+				},
+			},
+			groups: []*group{
+				{FullPath: "org/team1"},
+			},
+			wantErr: true,
+		},
+		{
+			name:     "viewer as default",
+			username: "foo",
+			oc: &OAuthClient{
+				project: &model.Project{
+					Id:                 "id",
+					AllowStrayAsViewer: true,
+					UserGroups:         userGroups,
+				},
+			},
+			groups: []*group{
+				{FullPath: "org/team1"},
+			},
+			role: &model.Role{
+				ProjectId: "id",
+				ProjectRbacRoles: []string{
+					model.BuiltinRBACRoleViewer.String(),
+				},
+			},
+		},
+		{
+			name:     "multiple groups",
+			username: "foo",
+			oc: &OAuthClient{
+				project: &model.Project{
+					Id:         "id",
+					UserGroups: userGroups,
+				},
+			},
+			groups: []*group{
+				{FullPath: "org"},
+				{FullPath: "org/team-admin"},
+				{FullPath: "org/sub/team-viewer"},
+				{FullPath: ""},
+			},
+			role: &model.Role{
+				ProjectId: "id",
+				ProjectRbacRoles: []string{
+					model.BuiltinRBACRoleAdmin.String(),
+					model.BuiltinRBACRoleViewer.String(),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			role, err := tc.oc.decideRole(tc.username, tc.groups)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if err == nil {
+				assert.Equal(t, tc.role, role)
+			}
+		})
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"username":"foo","avatar_url":"https://example.com/foo.png"}`))
+	})
+	mux.HandleFunc("/api/v4/groups", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("X-Next-Page", "2")
+			w.Write([]byte(`[{"full_path":"org/team1"}]`))
+		case "2":
+			w.Write([]byte(`[{"full_path":"org/team-editor"}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	c := &OAuthClient{
+		Client:  server.Client(),
+		baseURL: server.URL,
+		project: &model.Project{
+			Id: "id",
+			UserGroups: []*model.ProjectUserGroup{
+				{
+					SsoGroup: "org/team-editor",
+					Role:     "Editor",
+				},
+			},
+		},
+	}
+	user, err := c.GetUser(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, &model.User{
+		Username:  "foo",
+		AvatarUrl: "https://example.com/foo.png",
+		Role: &model.Role{
+			ProjectId:        "id",
+			ProjectRbacRoles: []string{model.BuiltinRBACRoleEditor.String()},
+		},
+		Groups: []string{"org/team-editor"},
+	}, user)
+}
@@ -0,0 +1,227 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+	oauth2google "golang.org/x/oauth2/google"
+	admin "google.golang.org/api/admin/directory/v1"
+	"google.golang.org/api/option"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const issuer = "https://accounts.google.com"
+
+// ErrDomainNotPermitted is returned when the hosted domain of the user
+// is not in the allowed domains of the SSO configuration.
+var ErrDomainNotPermitted = errors.New("domain not permitted")
+
+// OAuthClient is a oauth client for Google.
+type OAuthClient struct {
+	*oidc.Provider
+	*oauth2.Token
+
+	groups  *admin.GroupsService
+	sso     *model.ProjectSSOConfig_Google
+	project *model.Project
+}
+
+type claims struct {
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	HostedDomain  string `json:"hd"`
+	Picture       string `json:"picture"`
+}
+
+// NewOAuthClient creates a new oauth client for Google.
+func NewOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Google,
+	project *model.Project,
+	code string,
+) (*OAuthClient, error) {
+	return newOAuthClient(ctx, sso, project, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.Exchange(ctx, code)
+	})
+}
+
+// RefreshOAuthClient creates a new oauth client for Google by the refresh token issued to the user at login,
+// which is used to look up the user again after login. The new ID token is always requested.
+func RefreshOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Google,
+	project *model.Project,
+	refreshToken string,
+) (*OAuthClient, error) {
+	return newOAuthClient(ctx, sso, project, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	})
+}
+
+// newOAuthClient creates a new oauth client for Google by the token obtained by the given function.
+func newOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Google,
+	project *model.Project,
+	obtainToken func(context.Context, *oauth2.Config) (*oauth2.Token, error),
+) (*OAuthClient, error) {
+	c := &OAuthClient{
+		sso:     sso,
+		project: project,
+	}
+
+	if sso.ProxyUrl != "" {
+		proxyURL, err := url.Parse(sso.ProxyUrl)
+		if err != nil {
+			return nil, err
+		}
+
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyURL(proxyURL)
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: t})
+	}
+
+	provider, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, err
+	}
+	c.Provider = provider
+
+	cfg := &oauth2.Config{
+		ClientID:     sso.ClientId,
+		ClientSecret: sso.ClientSecret,
+		RedirectURL:  sso.RedirectUri,
+		Endpoint:     oauth2google.Endpoint,
+	}
+	token, err := obtainToken(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.Token = token
+
+	svc, err := admin.NewService(ctx, option.WithHTTPClient(cfg.Client(ctx, token)))
+	if err != nil {
+		return nil, err
+	}
+	c.groups = svc.Groups
+
+	return c, nil
+}
+
+// GetUser returns a user model.
+func (c *OAuthClient) GetUser(ctx context.Context) (*model.User, error) {
+	idTokenRAW, ok := c.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("no id_token in oauth2 token")
+	}
+
+	verifier := c.Verifier(&oidc.Config{ClientID: c.sso.ClientId})
+	idToken, err := verifier.Verify(ctx, idTokenRAW)
+	if err != nil {
+		return nil, err
+	}
+
+	var cl claims
+	if err := idToken.Claims(&cl); err != nil {
+		return nil, err
+	}
+	if cl.Email == "" || !cl.EmailVerified {
+		return nil, fmt.Errorf("email of the user is missing or not verified")
+	}
+	if err := c.checkDomain(cl.HostedDomain); err != nil {
+		return nil, err
+	}
+
+	var groups []string
+	err = c.groups.List().UserKey(cl.Email).Pages(ctx, func(gs *admin.Groups) error {
+		for _, g := range gs.Groups {
+			groups = append(groups, g.Email)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	role, err := c.decideRole(cl.Email, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.User{
+		Username:      cl.Email,
+		AvatarUrl:     cl.Picture,
+		Role:          role,
+		Email:         cl.Email,
+		EmailVerified: cl.EmailVerified,
+		Groups:        c.project.MatchedUserGroups(groups),
+	}, nil
+}
+
+func (c *OAuthClient) checkDomain(hd string) error {
+	if len(c.sso.AllowedDomains) == 0 {
+		return nil
+	}
+	for _, d := range c.sso.AllowedDomains {
+		if hd == d {
+			return nil
+		}
+	}
+	if hd == "" {
+		return fmt.Errorf("%w: user does not belong to any Google Workspace domain", ErrDomainNotPermitted)
+	}
+	return fmt.Errorf("%w: %s", ErrDomainNotPermitted, hd)
+}
+
+func (c *OAuthClient) decideRole(user string, groups []string) (role *model.Role, err error) {
+	role = &model.Role{
+		ProjectId:        c.project.Id,
+		ProjectRbacRoles: make([]string, 0, len(groups)),
+	}
+	userGroups := c.project.UserGroups
+	roles := make(map[string]string, len(userGroups))
+	for _, g := range userGroups {
+		roles[g.SsoGroup] = g.Role
+	}
+
+	for _, g := range groups {
+		if v, ok := roles[g]; ok {
+			role.ProjectRbacRoles = append(role.ProjectRbacRoles, v)
+		}
+	}
+
+	if len(role.ProjectRbacRoles) != 0 {
+		return
+	}
+
+	// The roles mapped to the domain of the email come next,
+	// which was verified in GetUser.
+	if roles := c.project.RolesByEmailDomain(user); len(roles) > 0 {
+		role.ProjectRbacRoles = roles
+		return
+	}
+
+	if c.project.AssignStrayRoles(role) {
+		return
+	}
+
+	err = fmt.Errorf("user (%s) not found in any of the %d project groups", user, len(groups))
+	return
+}
@@ -0,0 +1,152 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package google
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestCheckDomain(t *testing.T) {
+	cases := []struct {
+		name           string
+		allowedDomains []string
+		hd             string
+		wantErr        bool
+	}{
+		{
+			name: "no restriction",
+			hd:   "",
+		},
+		{
+			name:           "allowed domain",
+			allowedDomains: []string{"example.com", "example.org"},
+			hd:             "example.org",
+		},
+		{
+			name:           "disallowed domain",
+			allowedDomains: []string{"example.com"},
+			hd:             "other.com",
+			wantErr:        true,
+		},
+		{
+			name:           "consumer account",
+			allowedDomains: []string{"example.com"},
+			hd:             "",
+			wantErr:        true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &OAuthClient{
+				sso: &model.ProjectSSOConfig_Google{AllowedDomains: tc.allowedDomains},
+			}
+			err := c.checkDomain(tc.hd)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrDomainNotPermitted))
+			}
+		})
+	}
+}
+
+func TestDecideRole(t *testing.T) {
+	userGroups := []*model.ProjectUserGroup{
+		{
+			SsoGroup: "admin@example.com",
+			Role:     "Admin",
+		},
+		{
+			SsoGroup: "editor@example.com",
+			Role:     "Editor",
+		},
+	}
+	cases := []struct {
+		name               string
+		allowStrayAsViewer bool
+		emailDomainRoles   []*model.ProjectEmailDomainRole
+		groups             []string
+		role               *model.Role
+		wantErr            bool
+	}{
+		{
+			name:    "nothing",
+			groups:  []string{"other@example.com"},
+			wantErr: true,
+		},
+		{
+			name:               "viewer as default",
+			allowStrayAsViewer: true,
+			groups:             []string{"other@example.com"},
+			role: &model.Role{
+				ProjectId:        "id",
+				ProjectRbacRoles: []string{model.BuiltinRBACRoleViewer.String()},
+			},
+		},
+		{
+			name:               "email domain over viewer as default",
+			allowStrayAsViewer: true,
+			emailDomainRoles:   []*model.ProjectEmailDomainRole{{Domain: "example.com", Role: model.BuiltinRBACRoleEditor.String()}},
+			groups:             []string{"other@example.com"},
+			role: &model.Role{
+				ProjectId:        "id",
+				ProjectRbacRoles: []string{model.BuiltinRBACRoleEditor.String()},
+			},
+		},
+		{
+			name:             "group over email domain",
+			emailDomainRoles: []*model.ProjectEmailDomainRole{{Domain: "example.com", Role: model.BuiltinRBACRoleEditor.String()}},
+			groups:           []string{"admin@example.com"},
+			role: &model.Role{
+				ProjectId:        "id",
+				ProjectRbacRoles: []string{model.BuiltinRBACRoleAdmin.String()},
+			},
+		},
+		{
+			name:   "admin and editor",
+			groups: []string{"admin@example.com", "other@example.com", "editor@example.com"},
+			role: &model.Role{
+				ProjectId: "id",
+				ProjectRbacRoles: []string{
+					model.BuiltinRBACRoleAdmin.String(),
+					model.BuiltinRBACRoleEditor.String(),
+				},
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &OAuthClient{
+				project: &model.Project{
+					Id:                 "id",
+					UserGroups:         userGroups,
+					AllowStrayAsViewer: tc.allowStrayAsViewer,
+					EmailDomainRoles:   tc.emailDomainRoles,
+				},
+			}
+			role, err := c.decideRole("foo@example.com", tc.groups)
+			assert.Equal(t, tc.wantErr, err != nil)
+			if err == nil {
+				assert.Equal(t, tc.role, role)
+			}
+		})
+	}
+}
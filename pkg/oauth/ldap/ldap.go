@@ -0,0 +1,425 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	goldap "github.com/go-ldap/ldap/v3"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	defaultUserFilter         = "(uid={username})"
+	defaultGroupFilter        = "(member={dn})"
+	defaultGroupNameAttribute = "cn"
+	defaultPoolSize           = 4
+	memberOfAttribute         = "memberOf"
+	requestTimeout            = 10 * time.Second
+)
+
+// ErrInvalidCredentials is returned when the username or the password is wrong.
+// It is also returned when the user was not found to not reveal which users exist.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// ErrUserNotFound is returned when the looked up user does not exist in the directory.
+var ErrUserNotFound = errors.New("user not found")
+
+// ErrNoRole is returned when the user is granted no role in the project.
+var ErrNoRole = errors.New("no role assigned")
+
+// Client authenticates users by binding to the LDAP server.
+type Client struct {
+	sso  *model.ProjectSSOConfig_Ldap
+	pool *pool
+}
+
+// NewClient returns a client for the given LDAP configuration.
+// The connections are pooled and shared among the clients for the same server.
+func NewClient(sso *model.ProjectSSOConfig_Ldap) (*Client, error) {
+	p, err := sharedPools.get(sso)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		sso:  sso,
+		pool: p,
+	}, nil
+}
+
+// Authenticate binds to the LDAP server with the given credentials and
+// returns the user whose roles are decided from the groups the user belongs to.
+func (c *Client) Authenticate(project *model.Project, username, password string) (*model.User, error) {
+	// An empty password is treated as an unauthenticated bind by most servers.
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	conn, err := c.pool.get()
+	if err != nil {
+		return nil, err
+	}
+	reusable := false
+	defer func() {
+		if reusable {
+			c.pool.put(conn)
+			return
+		}
+		conn.Close()
+	}()
+
+	if err := c.bindServiceAccount(conn); err != nil {
+		return nil, err
+	}
+	entry, err := c.findUser(conn, username)
+	if err != nil {
+		reusable = errors.Is(err, ErrInvalidCredentials)
+		return nil, err
+	}
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			reusable = true
+			return nil, ErrInvalidCredentials
+		}
+		return nil, fmt.Errorf("unable to bind as the user: %w", err)
+	}
+
+	// Bind back to the service account to search the groups.
+	if err := c.bindServiceAccount(conn); err != nil {
+		return nil, err
+	}
+	groups, err := c.findGroups(conn, entry)
+	if err != nil {
+		return nil, err
+	}
+	reusable = true
+
+	role, err := decideRole(project, username, groups)
+	if err != nil {
+		return nil, err
+	}
+	return &model.User{
+		Username: username,
+		Role:     role,
+		Groups:   project.MatchedUserGroups(groups),
+	}, nil
+}
+
+// Lookup searches the given user as the service account and returns the user
+// whose roles are decided from the groups the user currently belongs to.
+// It is used to re-validate the user after login without the password.
+func (c *Client) Lookup(project *model.Project, username string) (*model.User, error) {
+	if username == "" {
+		return nil, ErrUserNotFound
+	}
+
+	conn, err := c.pool.get()
+	if err != nil {
+		return nil, err
+	}
+	reusable := false
+	defer func() {
+		if reusable {
+			c.pool.put(conn)
+			return
+		}
+		conn.Close()
+	}()
+
+	if err := c.bindServiceAccount(conn); err != nil {
+		return nil, err
+	}
+	entry, err := c.findUser(conn, username)
+	if errors.Is(err, ErrInvalidCredentials) {
+		reusable = true
+		return nil, ErrUserNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	groups, err := c.findGroups(conn, entry)
+	if err != nil {
+		return nil, err
+	}
+	reusable = true
+
+	role, err := decideRole(project, username, groups)
+	if err != nil {
+		return nil, err
+	}
+	return &model.User{
+		Username: username,
+		Role:     role,
+		Groups:   project.MatchedUserGroups(groups),
+	}, nil
+}
+
+func (c *Client) bindServiceAccount(conn goldap.Client) error {
+	var err error
+	if c.sso.BindDn == "" {
+		err = conn.UnauthenticatedBind("")
+	} else {
+		err = conn.Bind(c.sso.BindDn, c.sso.BindPassword)
+	}
+	if err != nil {
+		return fmt.Errorf("unable to bind as the service account: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) findUser(conn goldap.Client, username string) (*goldap.Entry, error) {
+	filter := c.sso.UserFilter
+	if filter == "" {
+		filter = defaultUserFilter
+	}
+	filter = strings.ReplaceAll(filter, "{username}", goldap.EscapeFilter(username))
+
+	res, err := conn.Search(goldap.NewSearchRequest(
+		c.sso.UserSearchBase,
+		goldap.ScopeWholeSubtree,
+		goldap.NeverDerefAliases,
+		2,
+		int(requestTimeout.Seconds()),
+		false,
+		filter,
+		[]string{memberOfAttribute},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("unable to search the user: %w", err)
+	}
+	switch len(res.Entries) {
+	case 0:
+		return nil, ErrInvalidCredentials
+	case 1:
+		return res.Entries[0], nil
+	default:
+		return nil, fmt.Errorf("multiple users matched the user filter")
+	}
+}
+
+// findGroups returns the names of the groups listed in the memberOf attribute of
+// the given user and the ones found under the group search base.
+func (c *Client) findGroups(conn goldap.Client, user *goldap.Entry) ([]string, error) {
+	nameAttr := c.sso.GroupNameAttribute
+	if nameAttr == "" {
+		nameAttr = defaultGroupNameAttribute
+	}
+
+	var (
+		groups []string
+		seen   = make(map[string]struct{})
+	)
+	add := func(name string) {
+		if _, ok := seen[name]; ok || name == "" {
+			return
+		}
+		seen[name] = struct{}{}
+		groups = append(groups, name)
+	}
+
+	for _, dn := range user.GetAttributeValues(memberOfAttribute) {
+		add(groupNameFromDN(dn, nameAttr))
+	}
+
+	if c.sso.GroupSearchBase == "" {
+		return groups, nil
+	}
+	filter := c.sso.GroupFilter
+	if filter == "" {
+		filter = defaultGroupFilter
+	}
+	filter = strings.ReplaceAll(filter, "{dn}", goldap.EscapeFilter(user.DN))
+
+	res, err := conn.Search(goldap.NewSearchRequest(
+		c.sso.GroupSearchBase,
+		goldap.ScopeWholeSubtree,
+		goldap.NeverDerefAliases,
+		0,
+		int(requestTimeout.Seconds()),
+		false,
+		filter,
+		[]string{nameAttr},
+		nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("unable to search the groups: %w", err)
+	}
+	for _, e := range res.Entries {
+		add(e.GetAttributeValue(nameAttr))
+	}
+	return groups, nil
+}
+
+// groupNameFromDN returns the value of the given attribute in the first RDN of the DN.
+func groupNameFromDN(dn, attr string) string {
+	parsed, err := goldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return ""
+	}
+	for _, a := range parsed.RDNs[0].Attributes {
+		if strings.EqualFold(a.Type, attr) {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func decideRole(project *model.Project, user string, groups []string) (*model.Role, error) {
+	role := &model.Role{
+		ProjectId:        project.Id,
+		ProjectRbacRoles: make([]string, 0, len(groups)),
+	}
+	roles := make(map[string]string, len(project.UserGroups))
+	for _, g := range project.UserGroups {
+		roles[g.SsoGroup] = g.Role
+	}
+
+	for _, g := range groups {
+		if v, ok := roles[g]; ok {
+			role.ProjectRbacRoles = append(role.ProjectRbacRoles, v)
+		}
+	}
+	if len(role.ProjectRbacRoles) != 0 {
+		return role, nil
+	}
+
+	if project.AssignStrayRoles(role) {
+		return role, nil
+	}
+	return nil, fmt.Errorf("%w: user (%s) not found in any of the %d project groups", ErrNoRole, user, len(groups))
+}
+
+// pool keeps the idle connections to an LDAP server.
+type pool struct {
+	dial  func() (goldap.Client, error)
+	conns chan goldap.Client
+}
+
+func newPool(size int, dial func() (goldap.Client, error)) *pool {
+	return &pool{
+		dial:  dial,
+		conns: make(chan goldap.Client, size),
+	}
+}
+
+// get returns an idle connection or dials a new one if there is no idle connection.
+func (p *pool) get() (goldap.Client, error) {
+	for {
+		select {
+		case c := <-p.conns:
+			if c.IsClosing() {
+				continue
+			}
+			return c, nil
+		default:
+			return p.dial()
+		}
+	}
+}
+
+// put returns the connection to the pool or closes it if the pool is full.
+func (p *pool) put(c goldap.Client) {
+	if c.IsClosing() {
+		return
+	}
+	select {
+	case p.conns <- c:
+	default:
+		c.Close()
+	}
+}
+
+type poolCache struct {
+	mu    sync.Mutex
+	pools map[string]*pool
+}
+
+var sharedPools = &poolCache{
+	pools: make(map[string]*pool),
+}
+
+func (pc *poolCache) get(sso *model.ProjectSSOConfig_Ldap) (*pool, error) {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s|%t|%s|%d", sso.Url, sso.StartTls, sso.RootCa, sso.PoolSize)))
+	key := hex.EncodeToString(sum[:])
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if p, ok := pc.pools[key]; ok {
+		return p, nil
+	}
+
+	tlsConfig, err := newTLSConfig(sso)
+	if err != nil {
+		return nil, err
+	}
+	size := int(sso.PoolSize)
+	if size <= 0 {
+		size = defaultPoolSize
+	}
+	p := newPool(size, func() (goldap.Client, error) {
+		return dial(sso.Url, sso.StartTls, tlsConfig)
+	})
+	pc.pools[key] = p
+	return p, nil
+}
+
+func newTLSConfig(sso *model.ProjectSSOConfig_Ldap) (*tls.Config, error) {
+	u, err := url.Parse(sso.Url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid LDAP url: %w", err)
+	}
+	cfg := &tls.Config{
+		ServerName: u.Hostname(),
+		MinVersion: tls.VersionTLS12,
+	}
+	if sso.RootCa != "" {
+		certs := x509.NewCertPool()
+		if !certs.AppendCertsFromPEM([]byte(sso.RootCa)) {
+			return nil, fmt.Errorf("invalid LDAP root CA: no certificate found")
+		}
+		cfg.RootCAs = certs
+	}
+	return cfg, nil
+}
+
+func dial(addr string, startTLS bool, tlsConfig *tls.Config) (goldap.Client, error) {
+	conn, err := goldap.DialURL(addr,
+		goldap.DialWithDialer(&net.Dialer{Timeout: requestTimeout}),
+		goldap.DialWithTLSConfig(tlsConfig),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to the LDAP server: %w", err)
+	}
+	conn.SetTimeout(requestTimeout)
+	if startTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("unable to start TLS: %w", err)
+		}
+	}
+	return conn, nil
+}
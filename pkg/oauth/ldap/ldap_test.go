@@ -0,0 +1,297 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ldap
+
+import (
+	"errors"
+	"testing"
+
+	goldap "github.com/go-ldap/ldap/v3"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+type fakeConn struct {
+	goldap.Client
+
+	passwords map[string]string
+	users     map[string]*goldap.Entry
+	groups    map[string][]*goldap.Entry
+	closed    bool
+	searches  []*goldap.SearchRequest
+}
+
+func (c *fakeConn) Bind(username, password string) error {
+	if p, ok := c.passwords[username]; ok && p == password {
+		return nil
+	}
+	return goldap.NewError(goldap.LDAPResultInvalidCredentials, errors.New("invalid credentials"))
+}
+
+func (c *fakeConn) UnauthenticatedBind(string) error {
+	return nil
+}
+
+func (c *fakeConn) Search(req *goldap.SearchRequest) (*goldap.SearchResult, error) {
+	c.searches = append(c.searches, req)
+	if e, ok := c.users[req.Filter]; ok {
+		return &goldap.SearchResult{Entries: []*goldap.Entry{e}}, nil
+	}
+	return &goldap.SearchResult{Entries: c.groups[req.Filter]}, nil
+}
+
+func (c *fakeConn) IsClosing() bool {
+	return c.closed
+}
+
+func (c *fakeConn) Close() error {
+	c.closed = true
+	return nil
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{
+		passwords: map[string]string{
+			"cn=service,dc=example,dc=com": "service-password",
+			"uid=alice,dc=example,dc=com":  "alice-password",
+		},
+		users: map[string]*goldap.Entry{
+			"(uid=alice)": goldap.NewEntry("uid=alice,dc=example,dc=com", map[string][]string{
+				memberOfAttribute: {"cn=admins,ou=groups,dc=example,dc=com"},
+			}),
+			"(uid=bob)": goldap.NewEntry("uid=bob,dc=example,dc=com", nil),
+		},
+		groups: map[string][]*goldap.Entry{
+			"(member=uid=alice,dc=example,dc=com)": {
+				goldap.NewEntry("cn=editors,ou=groups,dc=example,dc=com", map[string][]string{"cn": {"editors"}}),
+				goldap.NewEntry("cn=admins,ou=groups,dc=example,dc=com", map[string][]string{"cn": {"admins"}}),
+			},
+		},
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	project := &model.Project{
+		Id: "test-project",
+		UserGroups: []*model.ProjectUserGroup{
+			{SsoGroup: "admins", Role: model.BuiltinRBACRoleAdmin.String()},
+			{SsoGroup: "editors", Role: model.BuiltinRBACRoleEditor.String()},
+		},
+	}
+	sso := &model.ProjectSSOConfig_Ldap{
+		BindDn:          "cn=service,dc=example,dc=com",
+		BindPassword:    "service-password",
+		UserSearchBase:  "dc=example,dc=com",
+		GroupSearchBase: "ou=groups,dc=example,dc=com",
+	}
+
+	testcases := []struct {
+		name         string
+		username     string
+		password     string
+		expected     *model.User
+		expectedErr  error
+		expectReused bool
+	}{
+		{
+			name:     "valid credentials",
+			username: "alice",
+			password: "alice-password",
+			expected: &model.User{
+				Username: "alice",
+				Role: &model.Role{
+					ProjectId:        "test-project",
+					ProjectRbacRoles: []string{model.BuiltinRBACRoleAdmin.String(), model.BuiltinRBACRoleEditor.String()},
+				},
+				Groups: []string{"admins", "editors"},
+			},
+			expectReused: true,
+		},
+		{
+			name:         "wrong password",
+			username:     "alice",
+			password:     "wrong",
+			expectedErr:  ErrInvalidCredentials,
+			expectReused: true,
+		},
+		{
+			name:         "unknown user",
+			username:     "carol",
+			password:     "carol-password",
+			expectedErr:  ErrInvalidCredentials,
+			expectReused: true,
+		},
+		{
+			name:        "empty password",
+			username:    "alice",
+			password:    "",
+			expectedErr: ErrInvalidCredentials,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := newFakeConn()
+			c := &Client{
+				sso:  sso,
+				pool: newPool(1, func() (goldap.Client, error) { return conn, nil }),
+			}
+			user, err := c.Authenticate(project, tc.username, tc.password)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, user)
+			}
+			assert.Equal(t, tc.expectReused, len(c.pool.conns) == 1)
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	project := &model.Project{
+		Id: "test-project",
+		UserGroups: []*model.ProjectUserGroup{
+			{SsoGroup: "admins", Role: model.BuiltinRBACRoleAdmin.String()},
+		},
+	}
+	sso := &model.ProjectSSOConfig_Ldap{
+		BindDn:          "cn=service,dc=example,dc=com",
+		BindPassword:    "service-password",
+		UserSearchBase:  "dc=example,dc=com",
+		GroupSearchBase: "ou=groups,dc=example,dc=com",
+	}
+
+	testcases := []struct {
+		name        string
+		username    string
+		expected    []string
+		expectedErr error
+	}{
+		{
+			name:     "user in the groups",
+			username: "alice",
+			expected: []string{model.BuiltinRBACRoleAdmin.String()},
+		},
+		{
+			name:        "user in no group",
+			username:    "bob",
+			expectedErr: ErrNoRole,
+		},
+		{
+			name:        "unknown user",
+			username:    "carol",
+			expectedErr: ErrUserNotFound,
+		},
+		{
+			name:        "empty username",
+			username:    "",
+			expectedErr: ErrUserNotFound,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := newFakeConn()
+			c := &Client{
+				sso:  sso,
+				pool: newPool(1, func() (goldap.Client, error) { return conn, nil }),
+			}
+			user, err := c.Lookup(project, tc.username)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, user.Role.ProjectRbacRoles)
+		})
+	}
+}
+
+func TestFindUserEscapesFilter(t *testing.T) {
+	conn := newFakeConn()
+	c := &Client{
+		sso: &model.ProjectSSOConfig_Ldap{
+			UserSearchBase: "dc=example,dc=com",
+			UserFilter:     "(sAMAccountName={username})",
+		},
+	}
+	_, err := c.findUser(conn, "alice)(uid=*")
+	assert.ErrorIs(t, err, ErrInvalidCredentials)
+	require.Len(t, conn.searches, 1)
+	assert.Equal(t, `(sAMAccountName=alice\29\28uid=\2a)`, conn.searches[0].Filter)
+}
+
+func TestGroupNameFromDN(t *testing.T) {
+	testcases := []struct {
+		dn       string
+		attr     string
+		expected string
+	}{
+		{dn: "cn=admins,ou=groups,dc=example,dc=com", attr: "cn", expected: "admins"},
+		{dn: "CN=Domain Admins,CN=Users,DC=example,DC=com", attr: "cn", expected: "Domain Admins"},
+		{dn: "ou=groups,dc=example,dc=com", attr: "cn", expected: ""},
+		{dn: "invalid", attr: "cn", expected: ""},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.dn, func(t *testing.T) {
+			assert.Equal(t, tc.expected, groupNameFromDN(tc.dn, tc.attr))
+		})
+	}
+}
+
+func TestDecideRole(t *testing.T) {
+	project := &model.Project{
+		Id: "test-project",
+		UserGroups: []*model.ProjectUserGroup{
+			{SsoGroup: "admins", Role: model.BuiltinRBACRoleAdmin.String()},
+		},
+	}
+	_, err := decideRole(project, "bob", []string{"others"})
+	assert.ErrorIs(t, err, ErrNoRole)
+
+	project.AllowStrayAsViewer = true
+	role, err := decideRole(project, "bob", []string{"others"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.BuiltinRBACRoleViewer.String()}, role.ProjectRbacRoles)
+}
+
+func TestPool(t *testing.T) {
+	dialed := 0
+	p := newPool(1, func() (goldap.Client, error) {
+		dialed++
+		return &fakeConn{}, nil
+	})
+
+	c1, err := p.get()
+	require.NoError(t, err)
+	c2, err := p.get()
+	require.NoError(t, err)
+	assert.Equal(t, 2, dialed)
+
+	p.put(c1)
+	p.put(c2)
+	assert.True(t, c2.IsClosing(), "the connection over the pool size should be closed")
+
+	c3, err := p.get()
+	require.NoError(t, err)
+	assert.Same(t, c1, c3)
+
+	c3.Close()
+	p.put(c3)
+	_, err = p.get()
+	require.NoError(t, err)
+	assert.Equal(t, 3, dialed)
+}
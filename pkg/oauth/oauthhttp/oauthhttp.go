@@ -0,0 +1,148 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package oauthhttp provides the HTTP clients with bounded timeouts and the restricted TLS
+// used to communicate with the OAuth providers.
+package oauthhttp
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// Timeouts bounds each HTTP call to the OAuth providers.
+// The calls are also canceled when the context of the request is done.
+type Timeouts struct {
+	// Connect is the limit of establishing the connection including the TLS handshake.
+	Connect time.Duration
+	// Read is the limit of waiting for the response headers after the request is written.
+	Read time.Duration
+	// Total is the limit of the whole call including reading the response body.
+	Total time.Duration
+}
+
+// DefaultTimeouts is used unless the client is given by the context.
+var DefaultTimeouts = Timeouts{
+	Connect: 5 * time.Second,
+	Read:    10 * time.Second,
+	Total:   15 * time.Second,
+}
+
+// Validate checks whether all of the timeouts are positive and bounded by the total one.
+func (t Timeouts) Validate() error {
+	if t.Connect <= 0 || t.Read <= 0 || t.Total <= 0 {
+		return fmt.Errorf("all of the connect, read and total timeouts must be positive")
+	}
+	if t.Connect > t.Total || t.Read > t.Total {
+		return fmt.Errorf("connect timeout %s and read timeout %s must not be longer than total timeout %s", t.Connect, t.Read, t.Total)
+	}
+	return nil
+}
+
+// NewClient returns a new HTTP client bounded by the given timeouts, which connects
+// with the given TLS configuration. Nil means the TLS configuration of DefaultMinTLSVersion
+// trusting the system CAs. The connections negotiating a lower TLS version fail.
+func NewClient(t Timeouts, tlsConfig *tls.Config) *http.Client {
+	if tlsConfig == nil {
+		tlsConfig = defaultTLSConfig()
+	}
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	tr.TLSClientConfig = tlsConfig.Clone()
+	tr.DialContext = (&net.Dialer{
+		Timeout:   t.Connect,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	tr.TLSHandshakeTimeout = t.Connect
+	tr.ResponseHeaderTimeout = t.Read
+	return &http.Client{
+		Transport: tr,
+		Timeout:   t.Total,
+	}
+}
+
+// Client returns the HTTP client given by the context as oauth2.HTTPClient,
+// or a new one bounded by DefaultTimeouts with the default TLS configuration if not given.
+// The returned client goes through the given proxy unless it is empty,
+// and attaches the given headers to each of the requests.
+// The headers should be checked by model.ValidateExtraHeaders in advance.
+func Client(ctx context.Context, proxyURL string, headers map[string]string) (*http.Client, error) {
+	c, ok := ctx.Value(oauth2.HTTPClient).(*http.Client)
+	if !ok || c == nil {
+		c = NewClient(DefaultTimeouts, nil)
+	}
+	if proxyURL != "" {
+		u, err := url.Parse(proxyURL)
+		if err != nil {
+			return nil, err
+		}
+		tr := http.DefaultTransport.(*http.Transport).Clone()
+		if base, ok := c.Transport.(*http.Transport); ok {
+			tr = base.Clone()
+		}
+		tr.Proxy = http.ProxyURL(u)
+		c = &http.Client{
+			Transport: tr,
+			Timeout:   c.Timeout,
+		}
+	}
+	if len(headers) == 0 {
+		return c, nil
+	}
+
+	h := make(http.Header, len(headers))
+	for name, value := range headers {
+		h.Set(name, value)
+	}
+	return &http.Client{
+		Transport: &headerTransport{base: c.Transport, headers: h},
+		Timeout:   c.Timeout,
+	}, nil
+}
+
+// ContextWithClient returns the context carrying the HTTP client of Client
+// to be used by the oauth2 package.
+func ContextWithClient(ctx context.Context, proxyURL string, headers map[string]string) (context.Context, *http.Client, error) {
+	c, err := Client(ctx, proxyURL, headers)
+	if err != nil {
+		return nil, nil, err
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, c), c, nil
+}
+
+// headerTransport attaches the headers to each of the requests before sending it through the base transport.
+// The headers replace the ones of the same name set by the request.
+type headerTransport struct {
+	base    http.RoundTripper
+	headers http.Header
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	// The request must not be modified by the transport.
+	req = req.Clone(req.Context())
+	for name, values := range t.headers {
+		req.Header[name] = values
+	}
+	return base.RoundTrip(req)
+}
@@ -0,0 +1,155 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauthhttp
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+)
+
+// newSlowServer returns the server which does not respond until the test finishes.
+func newSlowServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-done:
+		case <-r.Context().Done():
+		}
+	}))
+	t.Cleanup(func() {
+		close(done)
+		srv.Close()
+	})
+	return srv
+}
+
+func TestNewClientCutsOffSlowServer(t *testing.T) {
+	t.Parallel()
+	srv := newSlowServer(t)
+
+	testcases := []struct {
+		name     string
+		timeouts Timeouts
+	}{
+		{
+			name:     "read timeout",
+			timeouts: Timeouts{Connect: time.Second, Read: 50 * time.Millisecond, Total: 10 * time.Second},
+		},
+		{
+			name:     "total timeout",
+			timeouts: Timeouts{Connect: time.Second, Read: 10 * time.Second, Total: 50 * time.Millisecond},
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			start := time.Now()
+			_, err := NewClient(tc.timeouts, nil).Get(srv.URL)
+			require.Error(t, err)
+			assert.Less(t, time.Since(start), 5*time.Second)
+		})
+	}
+}
+
+func TestClientHonorsContext(t *testing.T) {
+	t.Parallel()
+	srv := newSlowServer(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	c, err := Client(ctx, "", nil)
+	require.NoError(t, err)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = c.Do(req)
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClient(t *testing.T) {
+	t.Parallel()
+
+	c, err := Client(context.Background(), "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTimeouts.Total, c.Timeout)
+
+	given := NewClient(Timeouts{Connect: time.Second, Read: 2 * time.Second, Total: 3 * time.Second}, nil)
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, given)
+	c, err = Client(ctx, "", nil)
+	require.NoError(t, err)
+	assert.Same(t, given, c)
+
+	c, err = Client(ctx, "http://proxy.example.com:3128", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3*time.Second, c.Timeout)
+	tr := c.Transport.(*http.Transport)
+	assert.Equal(t, 2*time.Second, tr.ResponseHeaderTimeout)
+	proxy, err := tr.Proxy(httptest.NewRequest(http.MethodGet, "https://github.com", nil))
+	require.NoError(t, err)
+	assert.Equal(t, "proxy.example.com:3128", proxy.Host)
+	// The transport of the given client is not changed.
+	assert.NotSame(t, given.Transport, c.Transport)
+
+	_, err = Client(ctx, "://invalid", nil)
+	assert.Error(t, err)
+}
+
+func TestClientHeaders(t *testing.T) {
+	t.Parallel()
+	var got http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Clone()
+	}))
+	defer srv.Close()
+
+	c, err := Client(context.Background(), "", map[string]string{
+		"x-api-key": "secret",
+		"Accept":    "application/json",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTimeouts.Total, c.Timeout)
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set("Accept", "text/html")
+	req.Header.Set("X-Request-Id", "id")
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, "secret", got.Get("X-Api-Key"))
+	assert.Equal(t, []string{"application/json"}, got.Values("Accept"))
+	assert.Equal(t, "id", got.Get("X-Request-Id"))
+	// The request is not changed.
+	assert.Equal(t, "text/html", req.Header.Get("Accept"))
+	assert.Empty(t, req.Header.Get("X-Api-Key"))
+}
+
+func TestTimeoutsValidate(t *testing.T) {
+	t.Parallel()
+	assert.NoError(t, DefaultTimeouts.Validate())
+	assert.Error(t, Timeouts{Connect: time.Second, Read: time.Second}.Validate())
+	assert.Error(t, Timeouts{Connect: time.Second, Read: 20 * time.Second, Total: 10 * time.Second}.Validate())
+}
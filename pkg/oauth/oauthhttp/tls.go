@@ -0,0 +1,67 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauthhttp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+)
+
+// DefaultMinTLSVersion is the minimum TLS version of the connections to the OAuth providers
+// used unless configured.
+const DefaultMinTLSVersion = "1.2"
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// NewTLSConfig returns the TLS configuration of the connections to the OAuth providers
+// which refuses to negotiate the TLS version lower than the given one, e.g. "1.2".
+// The CAs in the given PEM file are trusted in addition to the system ones
+// to connect to the providers using the certificates of the private CAs. Empty means the system CAs only.
+func NewTLSConfig(minVersion, caBundleFile string) (*tls.Config, error) {
+	version, ok := tlsVersions[minVersion]
+	if !ok {
+		return nil, fmt.Errorf("invalid minimum TLS version %q, must be one of 1.0, 1.1, 1.2 and 1.3", minVersion)
+	}
+	cfg := &tls.Config{MinVersion: version}
+	if caBundleFile == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(caBundleFile)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read CA bundle file: %w", err)
+	}
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificate found in CA bundle file %s", caBundleFile)
+	}
+	cfg.RootCAs = pool
+	return cfg, nil
+}
+
+// defaultTLSConfig returns the TLS configuration of DefaultMinTLSVersion trusting the system CAs.
+func defaultTLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: tlsVersions[DefaultMinTLSVersion]}
+}
@@ -0,0 +1,106 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oauthhttp
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTLSServer returns the TLS server negotiating up to the given version
+// along with the PEM file of its certificate.
+func newTLSServer(t *testing.T, maxVersion uint16) (*httptest.Server, string) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.TLS = &tls.Config{MaxVersion: maxVersion}
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+
+	file := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	require.NoError(t, os.WriteFile(file, data, 0o600))
+	return srv, file
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := NewTLSConfig(DefaultMinTLSVersion, "")
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), cfg.MinVersion)
+	assert.Nil(t, cfg.RootCAs)
+
+	cfg, err = NewTLSConfig("1.3", "")
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), cfg.MinVersion)
+
+	_, err = NewTLSConfig("1.4", "")
+	assert.Error(t, err)
+	_, err = NewTLSConfig("", "")
+	assert.Error(t, err)
+
+	_, err = NewTLSConfig("1.2", filepath.Join(t.TempDir(), "not-found.pem"))
+	assert.Error(t, err)
+
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	require.NoError(t, os.WriteFile(empty, []byte("no certificate"), 0o600))
+	_, err = NewTLSConfig("1.2", empty)
+	assert.Error(t, err)
+}
+
+func TestNewClientTLS(t *testing.T) {
+	t.Parallel()
+	srv, caFile := newTLSServer(t, 0)
+	timeouts := DefaultTimeouts
+
+	// The default client only trusts the system CAs.
+	_, err := NewClient(timeouts, nil).Get(srv.URL)
+	require.Error(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), NewClient(timeouts, nil).Transport.(*http.Transport).TLSClientConfig.MinVersion)
+
+	cfg, err := NewTLSConfig("1.2", caFile)
+	require.NoError(t, err)
+	resp, err := NewClient(timeouts, cfg).Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	// The given configuration is not shared with the transport.
+	assert.NotSame(t, cfg, NewClient(timeouts, cfg).Transport.(*http.Transport).TLSClientConfig)
+}
+
+func TestNewClientRejectsLowerTLSVersion(t *testing.T) {
+	t.Parallel()
+	srv, caFile := newTLSServer(t, tls.VersionTLS12)
+
+	cfg, err := NewTLSConfig("1.2", caFile)
+	require.NoError(t, err)
+	resp, err := NewClient(DefaultTimeouts, cfg).Get(srv.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	cfg, err = NewTLSConfig("1.3", caFile)
+	require.NoError(t, err)
+	_, err = NewClient(DefaultTimeouts, cfg).Get(srv.URL)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "protocol version")
+}
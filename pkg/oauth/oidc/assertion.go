@@ -0,0 +1,83 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+	// clientAssertionLifetime is kept short since the assertion is used only once right after it is signed.
+	clientAssertionLifetime = time.Minute
+)
+
+// clientAssertionOptions returns the options to authenticate the client by the signed client assertion
+// at the given token endpoint (private_key_jwt), or nil if no client assertion key is configured.
+func clientAssertionOptions(sso *model.ProjectSSOConfig_Oidc, tokenURL string, now time.Time) ([]oauth2.AuthCodeOption, error) {
+	if sso.ClientAssertionKey == "" {
+		return nil, nil
+	}
+	key, err := parsePrivateKey(sso.ClientAssertionKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid client assertion key: %w", err)
+	}
+	method, err := clientAssertionSigningMethod(key, sso.ClientAssertionSigningAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.RegisteredClaims{
+		Issuer:    sso.ClientId,
+		Subject:   sso.ClientId,
+		Audience:  jwt.ClaimStrings{tokenURL},
+		IssuedAt:  jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(now.Add(clientAssertionLifetime)),
+		ID:        uuid.New().String(),
+	}
+	assertion, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign client assertion: %w", err)
+	}
+	return []oauth2.AuthCodeOption{
+		oauth2.SetAuthURLParam("client_assertion_type", clientAssertionType),
+		oauth2.SetAuthURLParam("client_assertion", assertion),
+	}, nil
+}
+
+// clientAssertionSigningMethod returns the signing method for the given key.
+// The algorithm defaults to RS256 for the RSA key and ES256 for the EC key.
+func clientAssertionSigningMethod(key interface{}, alg string) (jwt.SigningMethod, error) {
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		if alg == "" || alg == jwt.SigningMethodRS256.Alg() {
+			return jwt.SigningMethodRS256, nil
+		}
+	case *ecdsa.PrivateKey:
+		if alg == "" || alg == jwt.SigningMethodES256.Alg() {
+			return jwt.SigningMethodES256, nil
+		}
+	}
+	return nil, fmt.Errorf("client assertion signing algorithm %q is not supported for the key type %T", alg, key)
+}
@@ -0,0 +1,110 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestClientAssertionOptions(t *testing.T) {
+	t.Parallel()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	now := time.Now().Truncate(time.Second)
+	tokenURL := "https://idp.example.com/token"
+
+	testcases := []struct {
+		name      string
+		sso       *model.ProjectSSOConfig_Oidc
+		publicKey crypto.PublicKey
+		expectAlg string
+		expectErr bool
+	}{
+		{
+			name: "no client assertion key",
+			sso:  &model.ProjectSSOConfig_Oidc{ClientId: "client-id", ClientSecret: "secret"},
+		},
+		{
+			name:      "rsa key with default algorithm",
+			sso:       &model.ProjectSSOConfig_Oidc{ClientId: "client-id", ClientAssertionKey: encodePrivateKey(t, rsaKey)},
+			publicKey: &rsaKey.PublicKey,
+			expectAlg: "RS256",
+		},
+		{
+			name:      "ec key with ES256",
+			sso:       &model.ProjectSSOConfig_Oidc{ClientId: "client-id", ClientAssertionKey: encodePrivateKey(t, ecKey), ClientAssertionSigningAlgorithm: "ES256"},
+			publicKey: &ecKey.PublicKey,
+			expectAlg: "ES256",
+		},
+		{
+			name:      "algorithm not matching the key",
+			sso:       &model.ProjectSSOConfig_Oidc{ClientId: "client-id", ClientAssertionKey: encodePrivateKey(t, rsaKey), ClientAssertionSigningAlgorithm: "ES256"},
+			expectErr: true,
+		},
+		{
+			name:      "invalid key",
+			sso:       &model.ProjectSSOConfig_Oidc{ClientId: "client-id", ClientAssertionKey: "invalid"},
+			expectErr: true,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts, err := clientAssertionOptions(tc.sso, tokenURL, now)
+			if tc.expectErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tc.publicKey == nil {
+				assert.Nil(t, opts)
+				return
+			}
+
+			// The options are applied to the auth code URL as well as the token request.
+			cfg := oauth2.Config{Endpoint: oauth2.Endpoint{AuthURL: "https://idp.example.com/auth"}}
+			u, err := url.Parse(cfg.AuthCodeURL("state", opts...))
+			require.NoError(t, err)
+			assert.Equal(t, "urn:ietf:params:oauth:client-assertion-type:jwt-bearer", u.Query().Get("client_assertion_type"))
+
+			var claims jwt.RegisteredClaims
+			token, err := jwt.ParseWithClaims(u.Query().Get("client_assertion"), &claims, func(*jwt.Token) (interface{}, error) {
+				return tc.publicKey, nil
+			}, jwt.WithTimeFunc(func() time.Time { return now }))
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectAlg, token.Method.Alg())
+			assert.Equal(t, "client-id", claims.Issuer)
+			assert.Equal(t, "client-id", claims.Subject)
+			assert.Equal(t, jwt.ClaimStrings{tokenURL}, claims.Audience)
+			assert.Equal(t, now.Add(time.Minute), claims.ExpiresAt.Time)
+			assert.NotEmpty(t, claims.ID)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oauthhttp"
+)
+
+// backchannelLogoutEvent is the member of the events claim telling the token is a logout token.
+const backchannelLogoutEvent = "http://schemas.openid.net/event/backchannel-logout"
+
+// ErrInvalidLogoutToken is returned when the logout token of the back-channel logout is not valid.
+var ErrInvalidLogoutToken = errors.New("invalid logout token")
+
+// LogoutToken is the logout token the provider sends to log the user out of the sessions,
+// as described in OpenID Connect Back-Channel Logout 1.0.
+type LogoutToken struct {
+	// Subject and SessionID are the sub and sid claims identifying the user and the session at the provider.
+	// Either of them may be empty.
+	Subject   string
+	SessionID string
+}
+
+// VerifyLogoutToken verifies the signature and the claims of the given logout token,
+// which must be issued by the provider of the given SSO configuration for its client.
+func VerifyLogoutToken(ctx context.Context, sso *model.ProjectSSOConfig_Oidc, rawToken string) (*LogoutToken, error) {
+	rawToken, err := decryptIDToken(rawToken, sso)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLogoutToken, err)
+	}
+	ctx, httpClient, err := oauthhttp.ContextWithClient(ctx, sso.ProxyUrl, sso.ExtraHeaders)
+	if err != nil {
+		return nil, err
+	}
+	_, discovery, err := newProvider(ctx, sso, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	keySet := sharedJWKSCache.keySet(discovery.JWKSURL, httpClient)
+	verifier := oidc.NewVerifier(discovery.Issuer, keySet, &oidc.Config{
+		SkipClientIDCheck:    true,
+		SkipIssuerCheck:      true,
+		SupportedSigningAlgs: supportedSigningAlgs(discovery.Algorithms),
+	})
+	token, err := verifier.Verify(ctx, rawToken)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLogoutToken, err)
+	}
+	var claims jwt.MapClaims
+	if err := token.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLogoutToken, err)
+	}
+	c := &OAuthClient{sharedSSOConfig: sso}
+	if err := c.checkIssuer(token.Issuer); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLogoutToken, err)
+	}
+	azp, _ := claims["azp"].(string)
+	if err := c.checkAudience(token.Audience, azp); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidLogoutToken, err)
+	}
+	if err := checkLogoutClaims(claims, token.IssuedAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	sid, _ := claims["sid"].(string)
+	return &LogoutToken{
+		Subject:   token.Subject,
+		SessionID: sid,
+	}, nil
+}
+
+// checkLogoutClaims checks the claims specific to the logout token, which distinguish it from the ID token.
+func checkLogoutClaims(claims jwt.MapClaims, issuedAt, now time.Time) error {
+	events, ok := claims["events"].(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("%w: missing events claim", ErrInvalidLogoutToken)
+	}
+	if _, ok := events[backchannelLogoutEvent].(map[string]interface{}); !ok {
+		return fmt.Errorf("%w: missing %s event", ErrInvalidLogoutToken, backchannelLogoutEvent)
+	}
+	sub, _ := claims["sub"].(string)
+	sid, _ := claims["sid"].(string)
+	if sub == "" && sid == "" {
+		return fmt.Errorf("%w: missing both sub and sid claims", ErrInvalidLogoutToken)
+	}
+	// The nonce claim is prohibited so that the ID token is never accepted as the logout token.
+	if _, ok := claims["nonce"]; ok {
+		return fmt.Errorf("%w: unexpected nonce claim", ErrInvalidLogoutToken)
+	}
+	if issuedAt.IsZero() {
+		return fmt.Errorf("%w: missing iat claim", ErrInvalidLogoutToken)
+	}
+	if issuedAt.After(now.Add(clockSkew)) {
+		return fmt.Errorf("%w: issued in the future at %s", ErrInvalidLogoutToken, issuedAt)
+	}
+	return nil
+}
+
+// IDTokenSession returns the sub and sid claims of the ID token in the given token returned by the login,
+// which identify the session at the provider to be logged out by the back-channel logout.
+// The ID token must have been verified by GetUser. The sid claim is empty if the provider does not give it.
+func IDTokenSession(sso *model.ProjectSSOConfig_Oidc, token *oauth2.Token) (subject, sessionID string, err error) {
+	raw, ok := token.Extra("id_token").(string)
+	if !ok {
+		return "", "", fmt.Errorf("no id_token in oauth2 token")
+	}
+	raw, err = decryptIDToken(raw, sso)
+	if err != nil {
+		return "", "", err
+	}
+	var claims jwt.MapClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(raw, &claims); err != nil {
+		return "", "", err
+	}
+	subject, _ = claims["sub"].(string)
+	sessionID, _ = claims["sid"].(string)
+	return subject, sessionID, nil
+}
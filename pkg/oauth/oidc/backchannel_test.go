@@ -0,0 +1,171 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestVerifyLogoutToken(t *testing.T) {
+	key := generateKey(t)
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/jwks":
+			json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: []jose.JSONWebKey{
+				{Key: &key.PublicKey, KeyID: "key", Algorithm: string(jose.RS256), Use: "sig"},
+			}})
+		default:
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"issuer":                                server.URL,
+				"jwks_uri":                              server.URL + "/jwks",
+				"id_token_signing_alg_values_supported": []string{"RS256"},
+			})
+		}
+	}))
+	defer server.Close()
+
+	sign := func(claims map[string]interface{}) string {
+		signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, (&jose.SignerOptions{}).WithHeader(jose.HeaderKey("kid"), "key").WithType("logout+jwt"))
+		require.NoError(t, err)
+		payload, err := json.Marshal(claims)
+		require.NoError(t, err)
+		jws, err := signer.Sign(payload)
+		require.NoError(t, err)
+		token, err := jws.CompactSerialize()
+		require.NoError(t, err)
+		return token
+	}
+	now := time.Now()
+	claims := func(overrides map[string]interface{}) map[string]interface{} {
+		c := map[string]interface{}{
+			"iss":    server.URL,
+			"aud":    "client-id",
+			"iat":    now.Unix(),
+			"exp":    now.Add(time.Minute).Unix(),
+			"jti":    "jti",
+			"sub":    "user",
+			"sid":    "session",
+			"events": map[string]interface{}{backchannelLogoutEvent: map[string]interface{}{}},
+		}
+		for k, v := range overrides {
+			if v == nil {
+				delete(c, k)
+				continue
+			}
+			c[k] = v
+		}
+		return c
+	}
+	sso := &model.ProjectSSOConfig_Oidc{
+		ClientId: "client-id",
+		Issuer:   server.URL,
+	}
+
+	cases := []struct {
+		name     string
+		token    string
+		expected *LogoutToken
+	}{
+		{
+			name:     "valid",
+			token:    sign(claims(nil)),
+			expected: &LogoutToken{Subject: "user", SessionID: "session"},
+		},
+		{
+			name:     "only sid",
+			token:    sign(claims(map[string]interface{}{"sub": nil})),
+			expected: &LogoutToken{SessionID: "session"},
+		},
+		{
+			name:  "missing sub and sid",
+			token: sign(claims(map[string]interface{}{"sub": nil, "sid": nil})),
+		},
+		{
+			name:  "missing events",
+			token: sign(claims(map[string]interface{}{"events": nil})),
+		},
+		{
+			name:  "unexpected event",
+			token: sign(claims(map[string]interface{}{"events": map[string]interface{}{"http://example.com/event": map[string]interface{}{}}})),
+		},
+		{
+			name:  "nonce",
+			token: sign(claims(map[string]interface{}{"nonce": "nonce"})),
+		},
+		{
+			name:  "missing iat",
+			token: sign(claims(map[string]interface{}{"iat": nil})),
+		},
+		{
+			name:  "expired",
+			token: sign(claims(map[string]interface{}{"exp": now.Add(-time.Hour).Unix()})),
+		},
+		{
+			name:  "another issuer",
+			token: sign(claims(map[string]interface{}{"iss": "https://issuer.example.com"})),
+		},
+		{
+			name:  "another audience",
+			token: sign(claims(map[string]interface{}{"aud": "another-client"})),
+		},
+		{
+			name:  "invalid signature",
+			token: sign(claims(nil))[:10] + "x",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := VerifyLogoutToken(context.Background(), sso, c.token)
+			if c.expected == nil {
+				assert.ErrorIs(t, err, ErrInvalidLogoutToken)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
+
+func TestIDTokenSession(t *testing.T) {
+	key := generateKey(t)
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, nil)
+	require.NoError(t, err)
+	jws, err := signer.Sign([]byte(`{"sub":"user","sid":"session"}`))
+	require.NoError(t, err)
+	idToken, err := jws.CompactSerialize()
+	require.NoError(t, err)
+
+	token := (&oauth2.Token{AccessToken: "token"}).WithExtra(map[string]interface{}{"id_token": idToken})
+	subject, sessionID, err := IDTokenSession(&model.ProjectSSOConfig_Oidc{}, token)
+	require.NoError(t, err)
+	assert.Equal(t, "user", subject)
+	assert.Equal(t, "session", sessionID)
+
+	_, _, err = IDTokenSession(&model.ProjectSSOConfig_Oidc{}, &oauth2.Token{AccessToken: "token"})
+	assert.Error(t, err)
+}
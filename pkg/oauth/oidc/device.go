@@ -0,0 +1,131 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const deviceCodeGrantType = "urn:ietf:params:oauth:grant-type:device_code"
+
+// The errors of the token endpoint polled with the device code, as defined in RFC 8628 section 3.5.
+var (
+	// ErrAuthorizationPending is returned while the user has not completed the authorization yet.
+	ErrAuthorizationPending = errors.New("authorization pending")
+	// ErrSlowDown is returned when the token endpoint is polled too often.
+	ErrSlowDown = errors.New("slow down")
+	// ErrAccessDenied is returned when the user denied the authorization.
+	ErrAccessDenied = errors.New("access denied")
+	// ErrDeviceCodeExpired is returned when the device code has expired.
+	ErrDeviceCodeExpired = errors.New("device code expired")
+)
+
+// StartDeviceAuthorization starts the device authorization grant of RFC 8628
+// with the device authorization endpoint of the provider.
+// The user code and the verification URI in the response should be shown to the user.
+// Unlike the auth code flow, the public client does not need PKCE since no code is redirected.
+// The given options are added to the request, e.g. the scopes requesting the refresh token.
+func StartDeviceAuthorization(ctx context.Context,
+	sso *model.ProjectSSOConfig_Oidc,
+	project *model.Project,
+	authOpts ...oauth2.AuthCodeOption,
+) (*oauth2.DeviceAuthResponse, error) {
+	if err := sso.ValidateScopes(); err != nil {
+		return nil, err
+	}
+	c := &OAuthClient{
+		project:         project,
+		sharedSSOConfig: sso,
+	}
+	exchangeCtx, cfg, opts, err := c.setup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Endpoint.DeviceAuthURL == "" {
+		return nil, fmt.Errorf("device_authorization_endpoint is not provided by %s", sso.Issuer)
+	}
+	// The device authorization request only carries the client id,
+	// so the confidential clients add their authentication to the request body.
+	if cfg.ClientSecret != "" {
+		opts = append(opts, oauth2.SetAuthURLParam("client_secret", cfg.ClientSecret))
+	}
+	return cfg.DeviceAuth(exchangeCtx, append(opts, authOpts...)...)
+}
+
+// NewDeviceOAuthClient polls the token endpoint once with the given device code
+// and creates a new oauth client for OIDC when the user has completed the authorization.
+// The caller is responsible for the polling interval, ErrAuthorizationPending and ErrSlowDown
+// are returned while the authorization is still in progress.
+func NewDeviceOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Oidc,
+	project *model.Project,
+	deviceCode string,
+) (*OAuthClient, error) {
+	if err := sso.ValidateScopes(); err != nil {
+		return nil, err
+	}
+	c := &OAuthClient{
+		project:         project,
+		sharedSSOConfig: sso,
+	}
+	exchangeCtx, cfg, opts, err := c.setup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The token request of the auth code is reused since DeviceAccessToken of the oauth2 package
+	// keeps polling until the authorization completes, while each poll is a request of the CLI here.
+	// The empty code parameter is ignored by the token endpoint as an unrecognized parameter of this grant.
+	cfg.RedirectURL = ""
+	// The client secret is sent in the request body as in the device authorization request,
+	// since the auto detection of the oauth2 package retries the rejected poll in another style
+	// and the retry counts as a poll against the interval.
+	cfg.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+	opts = append(opts,
+		oauth2.SetAuthURLParam("grant_type", deviceCodeGrantType),
+		oauth2.SetAuthURLParam("device_code", deviceCode),
+	)
+	oauth2Token, err := cfg.Exchange(exchangeCtx, "", opts...)
+	if err != nil {
+		return nil, deviceTokenError(err)
+	}
+	c.Token = oauth2Token
+
+	return c, nil
+}
+
+// deviceTokenError wraps the error of the token endpoint with the matching sentinel error.
+func deviceTokenError(err error) error {
+	var re *oauth2.RetrieveError
+	if !errors.As(err, &re) {
+		return err
+	}
+	switch re.ErrorCode {
+	case "authorization_pending":
+		return fmt.Errorf("%w: %w", ErrAuthorizationPending, err)
+	case "slow_down":
+		return fmt.Errorf("%w: %w", ErrSlowDown, err)
+	case "access_denied":
+		return fmt.Errorf("%w: %w", ErrAccessDenied, err)
+	case "expired_token":
+		return fmt.Errorf("%w: %w", ErrDeviceCodeExpired, err)
+	}
+	return err
+}
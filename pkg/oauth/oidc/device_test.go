@@ -0,0 +1,123 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestDeviceAuthorization(t *testing.T) {
+	var (
+		server   *httptest.Server
+		approved bool
+		polls    int
+	)
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"device_authorization_endpoint":%q,"jwks_uri":%q}`,
+				server.URL, server.URL+"/auth", server.URL+"/token", server.URL+"/device", server.URL+"/keys")
+		case "/device":
+			require.NoError(t, r.ParseForm())
+			if r.PostForm.Get("client_id") != "client-id" || r.PostForm.Get("client_secret") != "client-secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error":"invalid_client"}`)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"device_code":"device-code","user_code":"ABCD-EFGH","verification_uri":%q,"expires_in":600,"interval":5}`, server.URL+"/activate")
+		case "/token":
+			require.NoError(t, r.ParseForm())
+			polls++
+			w.Header().Set("Content-Type", "application/json")
+			switch {
+			case r.PostForm.Get("grant_type") != deviceCodeGrantType || r.PostForm.Get("device_code") != "device-code" || r.PostForm.Get("client_secret") != "client-secret" || r.PostForm.Has("redirect_uri"):
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"invalid_request"}`)
+			case polls == 2:
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"slow_down"}`)
+			case !approved:
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"authorization_pending"}`)
+			default:
+				fmt.Fprint(w, `{"access_token":"access-token","token_type":"Bearer"}`)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	sso := &model.ProjectSSOConfig_Oidc{
+		ClientId:     "client-id",
+		ClientSecret: "client-secret",
+		Issuer:       server.URL,
+		RedirectUri:  "https://pipecd.example.com/auth/callback",
+	}
+	project := &model.Project{Id: "project"}
+
+	da, err := StartDeviceAuthorization(context.Background(), sso, project)
+	require.NoError(t, err)
+	assert.Equal(t, "device-code", da.DeviceCode)
+	assert.Equal(t, "ABCD-EFGH", da.UserCode)
+	assert.Equal(t, server.URL+"/activate", da.VerificationURI)
+	assert.Equal(t, int64(5), da.Interval)
+
+	_, err = NewDeviceOAuthClient(context.Background(), sso, project, da.DeviceCode)
+	assert.ErrorIs(t, err, ErrAuthorizationPending)
+	_, err = NewDeviceOAuthClient(context.Background(), sso, project, da.DeviceCode)
+	assert.ErrorIs(t, err, ErrSlowDown)
+
+	approved = true
+	c, err := NewDeviceOAuthClient(context.Background(), sso, project, da.DeviceCode)
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", c.Token.AccessToken)
+}
+
+func TestStartDeviceAuthorizationUnsupported(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"jwks_uri":%q}`,
+			server.URL, server.URL+"/auth", server.URL+"/token", server.URL+"/keys")
+	}))
+	defer server.Close()
+
+	sso := &model.ProjectSSOConfig_Oidc{ClientId: "client-id", Issuer: server.URL}
+	_, err := StartDeviceAuthorization(context.Background(), sso, &model.Project{Id: "project"})
+	assert.ErrorContains(t, err, "device_authorization_endpoint is not provided")
+}
+
+func TestDeviceTokenError(t *testing.T) {
+	assert.ErrorIs(t, deviceTokenError(&oauth2.RetrieveError{ErrorCode: "authorization_pending"}), ErrAuthorizationPending)
+	assert.ErrorIs(t, deviceTokenError(&oauth2.RetrieveError{ErrorCode: "slow_down"}), ErrSlowDown)
+	assert.ErrorIs(t, deviceTokenError(&oauth2.RetrieveError{ErrorCode: "access_denied"}), ErrAccessDenied)
+	assert.ErrorIs(t, deviceTokenError(&oauth2.RetrieveError{ErrorCode: "expired_token"}), ErrDeviceCodeExpired)
+	assert.NotErrorIs(t, deviceTokenError(&oauth2.RetrieveError{ErrorCode: "invalid_client"}), ErrAccessDenied)
+}
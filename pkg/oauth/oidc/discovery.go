@@ -0,0 +1,162 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultDiscoveryCacheTTL is the default duration for which the fetched discovery document is cached.
+const DefaultDiscoveryCacheTTL = time.Hour
+
+// sharedDiscoveryCache is shared by all OIDC clients so that projects
+// using the same issuer do not fetch the same discovery document separately.
+var sharedDiscoveryCache = newDiscoveryCache(DefaultDiscoveryCacheTTL)
+
+// SetDiscoveryCacheTTL changes the TTL of the shared discovery cache.
+// It should be called before handling any login.
+func SetDiscoveryCacheTTL(ttl time.Duration) {
+	sharedDiscoveryCache.setTTL(ttl)
+}
+
+type discoveryCache struct {
+	now func() time.Time
+	// group deduplicates concurrent fetches of the same issuer.
+	group singleflight.Group
+
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]cachedDiscovery
+}
+
+type cachedDiscovery struct {
+	doc    providerJSON
+	expiry time.Time
+}
+
+func newDiscoveryCache(ttl time.Duration) *discoveryCache {
+	return &discoveryCache{
+		now:     time.Now,
+		ttl:     ttl,
+		entries: make(map[string]cachedDiscovery),
+	}
+}
+
+func (c *discoveryCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// discover returns the discovery document of the given issuer, which is fetched only if not cached.
+// The given client is used to fetch the document, http.DefaultClient is used if nil.
+func (c *discoveryCache) discover(ctx context.Context, issuer string, client *http.Client) (providerJSON, error) {
+	c.mu.Lock()
+	e, ok := c.entries[issuer]
+	c.mu.Unlock()
+	if ok && c.now().Before(e.expiry) {
+		return e.doc, nil
+	}
+
+	ch := c.group.DoChan(issuer, func() (interface{}, error) {
+		// Do not let the caller cancel the shared fetch.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		v, err := retryFetch(ctx, fetchKindDiscovery, issuer, func() (interface{}, error) {
+			return fetchDiscovery(ctx, issuer, client)
+		})
+		if err != nil {
+			// Drop the stale document so that it is not served after a failed fetch.
+			c.invalidate(issuer)
+			return nil, err
+		}
+		doc := v.(providerJSON)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.entries[issuer] = cachedDiscovery{
+			doc:    doc,
+			expiry: c.now().Add(c.ttl),
+		}
+		return doc, nil
+	})
+	select {
+	case <-ctx.Done():
+		return providerJSON{}, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return providerJSON{}, res.Err
+		}
+		return res.Val.(providerJSON), nil
+	}
+}
+
+// invalidate removes the cached discovery document of the given issuer
+// so that it is fetched again on the next login.
+func (c *discoveryCache) invalidate(issuer string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, issuer)
+}
+
+// Portions of this function are derived from the CoreOS Project:
+// https://pkg.go.dev/github.com/coreos/go-oidc/v3@v3.11.0/oidc#NewProvider
+func fetchDiscovery(ctx context.Context, issuer string, client *http.Client) (providerJSON, error) {
+	// NOTICE: https://github.com/coreos/go-oidc/blob/master/NOTICE
+	// CoreOS Project
+	// Copyright 2014 CoreOS, Inc
+	//
+	// This product includes software developed at CoreOS, Inc.
+	// (http://www.coreos.com/).
+	// Copied from go-oidc package
+	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, wellKnown, nil)
+	if err != nil {
+		return providerJSON{}, err
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return providerJSON{}, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return providerJSON{}, fmt.Errorf("unable to read response body: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return providerJSON{}, &fetchStatusError{status: resp.Status, code: resp.StatusCode, body: body}
+	}
+
+	var p providerJSON
+	if err := unmarshalResp(resp, body, &p); err != nil {
+		return providerJSON{}, fmt.Errorf("oidc: failed to decode provider discovery object: %v", err)
+	}
+	// End of Copied from go-oidc package
+	return p, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+type fakeDiscoveryServer struct {
+	*httptest.Server
+
+	mu      sync.Mutex
+	body    string
+	fetched int
+}
+
+func newFakeDiscoveryServer(t *testing.T) *fakeDiscoveryServer {
+	s := &fakeDiscoveryServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.fetched++
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(s.body))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeDiscoveryServer) setBody(body string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body = body
+}
+
+func (s *fakeDiscoveryServer) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetched
+}
+
+func TestDiscoveryCache(t *testing.T) {
+	server := newFakeDiscoveryServer(t)
+	server.setBody(`{"issuer": "https://issuer.example.com", "jwks_uri": "https://issuer.example.com/keys"}`)
+
+	now := time.Now()
+	cache := newDiscoveryCache(time.Minute)
+	cache.now = func() time.Time { return now }
+
+	doc, err := cache.discover(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://issuer.example.com/keys", doc.JWKSURL)
+	assert.Equal(t, 1, server.fetchCount())
+
+	// The cached document is used until it expires.
+	server.setBody(`{"issuer": "https://issuer.example.com", "jwks_uri": "https://issuer.example.com/rotated"}`)
+	doc, err = cache.discover(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://issuer.example.com/keys", doc.JWKSURL)
+	assert.Equal(t, 1, server.fetchCount())
+
+	now = now.Add(2 * time.Minute)
+	doc, err = cache.discover(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://issuer.example.com/rotated", doc.JWKSURL)
+	assert.Equal(t, 2, server.fetchCount())
+
+	// The document which failed to parse is not cached and the stale one is dropped.
+	now = now.Add(2 * time.Minute)
+	server.setBody(`not json`)
+	_, err = cache.discover(context.Background(), server.URL, nil)
+	require.Error(t, err)
+	assert.Equal(t, 3, server.fetchCount())
+
+	server.setBody(`{"issuer": "https://issuer.example.com", "jwks_uri": "https://issuer.example.com/fixed"}`)
+	doc, err = cache.discover(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://issuer.example.com/fixed", doc.JWKSURL)
+	assert.Equal(t, 4, server.fetchCount())
+}
+
+func TestDiscoveryCacheInvalidate(t *testing.T) {
+	server := newFakeDiscoveryServer(t)
+	server.setBody(`{"issuer": "https://issuer.example.com"}`)
+	cache := newDiscoveryCache(time.Hour)
+
+	_, err := cache.discover(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	cache.invalidate(server.URL)
+	_, err = cache.discover(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, server.fetchCount())
+}
+
+func TestNewProviderIssuerMismatch(t *testing.T) {
+	server := newFakeDiscoveryServer(t)
+	server.setBody(`{"issuer": "https://other.example.com"}`)
+
+	_, _, err := newProvider(context.Background(), &model.ProjectSSOConfig_Oidc{Issuer: server.URL}, nil)
+	require.Error(t, err)
+
+	// The mismatched document is fetched again on the next login.
+	server.setBody(`{"issuer": "` + server.URL + `", "jwks_uri": "` + server.URL + `/keys"}`)
+	_, discovery, err := newProvider(context.Background(), &model.ProjectSSOConfig_Oidc{Issuer: server.URL}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/keys", discovery.JWKSURL)
+	assert.Equal(t, 2, server.fetchCount())
+}
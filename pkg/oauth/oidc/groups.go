@@ -0,0 +1,144 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// defaultMaxGroups is the maximum number of the groups fetched when the groups overflow, unless configured.
+	defaultMaxGroups = 1000
+	// maxGroupsResponseSize limits the size of the response of the groups endpoint.
+	maxGroupsResponseSize = 1 << 20
+)
+
+// ErrTooManyGroups is returned when the user belongs to more groups than the configured maximum,
+// which are not truncated since the dropped groups might be the ones granting the roles.
+var ErrTooManyGroups = errors.New("too many groups")
+
+// groupsOverflowed reports whether the claims tell that the groups were omitted from the ID token
+// by the configured overflow claim, e.g. {"hasgroups": true} or {"_claim_names": {"groups": "src1"}}.
+// It is always false unless the overflow claim is configured.
+func (c *OAuthClient) groupsOverflowed(claims jwt.MapClaims) bool {
+	key := c.sharedSSOConfig.GroupsOverflowClaim
+	if key == "" {
+		return false
+	}
+	switch v := claims[key].(type) {
+	case nil:
+		return false
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "false"
+	case map[string]interface{}:
+		return len(v) > 0
+	case []interface{}:
+		return len(v) > 0
+	default:
+		return true
+	}
+}
+
+// fetchGroups returns all groups of the user from the configured groups endpoint,
+// or from the claims of the user info endpoint if not configured.
+// The given user info claims are used instead of fetching them again if not nil.
+func (c *OAuthClient) fetchGroups(ctx context.Context, userInfoClaims map[string]interface{}) ([]string, error) {
+	var (
+		groups []string
+		err    error
+	)
+	switch {
+	case c.sharedSSOConfig.GroupsEndpoint != "":
+		groups, err = c.fetchGroupsEndpoint(ctx, c.sharedSSOConfig.GroupsEndpoint)
+	case c.UserInfoEndpoint() == "":
+		return nil, fmt.Errorf("neither groups endpoint nor user info endpoint is available")
+	default:
+		if userInfoClaims == nil {
+			if userInfoClaims, err = c.fetchUserInfo(ctx); err != nil {
+				return nil, err
+			}
+		}
+		groups = roleClaimValues(userInfoClaims, c.sharedSSOConfig.RolesClaimKey, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	maxGroups := int(c.sharedSSOConfig.MaxGroups)
+	if maxGroups == 0 {
+		maxGroups = defaultMaxGroups
+	}
+	if len(groups) > maxGroups {
+		return nil, fmt.Errorf("%w: %d groups exceed the maximum %d", ErrTooManyGroups, len(groups), maxGroups)
+	}
+	return groups, nil
+}
+
+// fetchGroupsEndpoint requests the groups of the user to the given endpoint with the access token.
+// The response is either a JSON array of the groups or a JSON object containing them in the roles claim.
+func (c *OAuthClient) fetchGroupsEndpoint(ctx context.Context, endpoint string) ([]string, error) {
+	if err := sharedHostAllowlist.check(endpoint); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	client := c.httpClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s: %s", resp.Status, body)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxGroupsResponseSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(body) > maxGroupsResponseSize {
+		return nil, fmt.Errorf("%w: response of groups endpoint exceeds %d bytes", ErrTooManyGroups, maxGroupsResponseSize)
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return nil, fmt.Errorf("unable to parse response of groups endpoint: %w", err)
+	}
+	switch v := v.(type) {
+	case []interface{}:
+		return claimStrings(v), nil
+	case map[string]interface{}:
+		return roleClaimValues(v, c.sharedSSOConfig.RolesClaimKey, nil), nil
+	}
+	return nil, fmt.Errorf("unexpected response of groups endpoint")
+}
@@ -0,0 +1,150 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestGroupsOverflowed(t *testing.T) {
+	cases := []struct {
+		name   string
+		key    string
+		claims jwt.MapClaims
+		want   bool
+	}{
+		{name: "disabled", claims: jwt.MapClaims{"hasgroups": true}, want: false},
+		{name: "absent", key: "hasgroups", claims: jwt.MapClaims{"groups": []interface{}{"dev"}}, want: false},
+		{name: "true", key: "hasgroups", claims: jwt.MapClaims{"hasgroups": true}, want: true},
+		{name: "false", key: "hasgroups", claims: jwt.MapClaims{"hasgroups": false}, want: false},
+		{name: "string", key: "hasgroups", claims: jwt.MapClaims{"hasgroups": "true"}, want: true},
+		{name: "false string", key: "hasgroups", claims: jwt.MapClaims{"hasgroups": "false"}, want: false},
+		{name: "claim names", key: "_claim_names", claims: jwt.MapClaims{"_claim_names": map[string]interface{}{"groups": "src1"}}, want: true},
+		{name: "empty claim names", key: "_claim_names", claims: jwt.MapClaims{"_claim_names": map[string]interface{}{}}, want: false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &OAuthClient{sharedSSOConfig: &model.ProjectSSOConfig_Oidc{GroupsOverflowClaim: c.key}}
+			assert.Equal(t, c.want, client.groupsOverflowed(c.claims))
+		})
+	}
+}
+
+func TestFetchGroups(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" && r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/userinfo":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"sub":    "user",
+				"groups": []string{"dev", "ops"},
+			})
+		case "/groups":
+			json.NewEncoder(w).Encode([]string{"dev", "ops", "sre"})
+		case "/groups/object":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"teams": []string{"dev"},
+			})
+		case "/groups/error":
+			w.WriteHeader(http.StatusForbidden)
+		default:
+			json.NewEncoder(w).Encode(map[string]string{
+				"issuer":            server.URL,
+				"userinfo_endpoint": server.URL + "/userinfo",
+			})
+		}
+	}))
+	defer server.Close()
+
+	provider, err := oidc.NewProvider(context.Background(), server.URL)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name           string
+		sso            *model.ProjectSSOConfig_Oidc
+		userInfoClaims map[string]interface{}
+		want           []string
+		wantErr        error
+		fail           bool
+	}{
+		{
+			name: "user info endpoint",
+			sso:  &model.ProjectSSOConfig_Oidc{},
+			want: []string{"dev", "ops"},
+		},
+		{
+			name:           "fetched user info",
+			sso:            &model.ProjectSSOConfig_Oidc{},
+			userInfoClaims: map[string]interface{}{"groups": []interface{}{"qa"}},
+			want:           []string{"qa"},
+		},
+		{
+			name: "groups endpoint",
+			sso:  &model.ProjectSSOConfig_Oidc{GroupsEndpoint: server.URL + "/groups"},
+			want: []string{"dev", "ops", "sre"},
+		},
+		{
+			name: "groups endpoint returning object",
+			sso:  &model.ProjectSSOConfig_Oidc{GroupsEndpoint: server.URL + "/groups/object", RolesClaimKey: "teams"},
+			want: []string{"dev"},
+		},
+		{
+			name: "groups endpoint failure",
+			sso:  &model.ProjectSSOConfig_Oidc{GroupsEndpoint: server.URL + "/groups/error"},
+			fail: true,
+		},
+		{
+			name:    "too many groups",
+			sso:     &model.ProjectSSOConfig_Oidc{GroupsEndpoint: server.URL + "/groups", MaxGroups: 2},
+			wantErr: ErrTooManyGroups,
+			fail:    true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &OAuthClient{
+				Provider:        provider,
+				Token:           &oauth2.Token{AccessToken: "token"},
+				sharedSSOConfig: c.sso,
+			}
+			groups, err := client.fetchGroups(context.Background(), c.userInfoClaims)
+			if c.fail {
+				require.Error(t, err)
+				if c.wantErr != nil {
+					assert.ErrorIs(t, err, c.wantErr)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, c.want, groups)
+		})
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+// ErrHostNotAllowed is returned when the issuer or an endpoint of the provider
+// is not on one of the allowed hosts, e.g. the SSO configuration was tampered.
+var ErrHostNotAllowed = errors.New("host not allowed")
+
+// sharedHostAllowlist restricts the hosts called by all OIDC clients.
+var sharedHostAllowlist hostAllowlist
+
+// SetAllowedHosts restricts the hosts of the issuers and the endpoints of the providers
+// called by the control plane to the given hosts, which keeps the tampered SSO configuration
+// from making the control plane send the requests to the internal services.
+// The hosts should be checked by ValidateAllowedHosts in advance. Empty means any host is allowed.
+// It should be called before handling any login.
+func SetAllowedHosts(hosts []string) {
+	sharedHostAllowlist.set(hosts)
+}
+
+// ValidateAllowedHosts checks whether each of the given hosts is a host name, e.g. "accounts.example.com",
+// or a wildcard of the subdomains, e.g. "*.example.com".
+func ValidateAllowedHosts(hosts []string) error {
+	for _, h := range hosts {
+		name := strings.TrimPrefix(h, "*.")
+		if name == "" || strings.ContainsAny(name, "*/:?#@ ") {
+			return fmt.Errorf("invalid allowed host %q, must be a host name without scheme, port and path, or a wildcard such as *.example.com", h)
+		}
+	}
+	return nil
+}
+
+// CheckAllowedHosts checks whether the issuer and the endpoints configured by the given SSO configuration
+// are on the allowed hosts. It should be called before any request to the provider made outside this package,
+// e.g. the discovery to make the auth URL.
+func CheckAllowedHosts(sso *model.ProjectSSOConfig_Oidc) error {
+	return sharedHostAllowlist.check(sso.Issuer, sso.TokenEndpoint, sso.UserInfoEndpoint, sso.GroupsEndpoint)
+}
+
+type hostAllowlist struct {
+	mu    sync.RWMutex
+	hosts []string
+}
+
+func (l *hostAllowlist) set(hosts []string) {
+	normalized := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		normalized = append(normalized, strings.ToLower(h))
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.hosts = normalized
+}
+
+// check returns ErrHostNotAllowed unless the hosts of all the given URLs are allowed.
+// The empty URLs, which are not configured or not provided by the discovery, are skipped.
+func (l *hostAllowlist) check(rawURLs ...string) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if len(l.hosts) == 0 {
+		return nil
+	}
+	for _, rawURL := range rawURLs {
+		if rawURL == "" {
+			continue
+		}
+		u, err := url.Parse(rawURL)
+		if err != nil || u.Hostname() == "" {
+			return fmt.Errorf("%w: invalid url %q", ErrHostNotAllowed, rawURL)
+		}
+		if !l.allowed(strings.ToLower(u.Hostname())) {
+			return fmt.Errorf("%w: %s", ErrHostNotAllowed, u.Hostname())
+		}
+	}
+	return nil
+}
+
+func (l *hostAllowlist) allowed(host string) bool {
+	for _, h := range l.hosts {
+		if suffix, ok := strings.CutPrefix(h, "*"); ok {
+			if strings.HasSuffix(host, suffix) {
+				return true
+			}
+			continue
+		}
+		if host == h {
+			return true
+		}
+	}
+	return false
+}
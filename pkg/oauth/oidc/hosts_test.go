@@ -0,0 +1,99 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestValidateAllowedHosts(t *testing.T) {
+	assert.NoError(t, ValidateAllowedHosts(nil))
+	assert.NoError(t, ValidateAllowedHosts([]string{"accounts.example.com", "*.okta.com", "10.0.0.1"}))
+	for _, h := range []string{"", "*.", "https://accounts.example.com", "accounts.example.com:443", "accounts.example.com/", "*.*.example.com", "a*.example.com"} {
+		assert.Error(t, ValidateAllowedHosts([]string{h}), h)
+	}
+}
+
+func TestHostAllowlist(t *testing.T) {
+	var l hostAllowlist
+	assert.NoError(t, l.check("http://169.254.169.254/latest/meta-data"))
+
+	l.set([]string{"Accounts.Example.com", "*.okta.com"})
+	testcases := []struct {
+		url     string
+		allowed bool
+	}{
+		{url: "https://accounts.example.com", allowed: true},
+		{url: "https://ACCOUNTS.example.com:8443/.well-known/openid-configuration", allowed: true},
+		{url: "https://dev-1.okta.com/oauth2/default", allowed: true},
+		{url: "https://okta.com", allowed: false},
+		{url: "https://evil-okta.com", allowed: false},
+		{url: "https://accounts.example.com.evil.com", allowed: false},
+		{url: "http://169.254.169.254/latest/meta-data", allowed: false},
+		{url: "/relative", allowed: false},
+	}
+	for _, tc := range testcases {
+		err := l.check(tc.url)
+		if tc.allowed {
+			assert.NoError(t, err, tc.url)
+		} else {
+			assert.ErrorIs(t, err, ErrHostNotAllowed, tc.url)
+		}
+	}
+	// The endpoints which are not configured are skipped.
+	assert.NoError(t, l.check("https://accounts.example.com", ""))
+}
+
+func TestNewOAuthClientHostNotAllowed(t *testing.T) {
+	var requests atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"jwks_uri":%q}`,
+			server.URL, server.URL+"/auth", server.URL+"/token", "http://169.254.169.254/keys")
+	}))
+	defer server.Close()
+
+	SetAllowedHosts([]string{"127.0.0.1"})
+	defer SetAllowedHosts(nil)
+
+	// The configured token endpoint is rejected before the discovery.
+	sso := &model.ProjectSSOConfig_Oidc{
+		ClientId:      "client-id",
+		ClientSecret:  "client-secret",
+		Issuer:        server.URL,
+		TokenEndpoint: "http://metadata.internal/token",
+		RedirectUri:   "https://pipecd.example.com/auth/callback",
+	}
+	_, err := NewOAuthClient(context.Background(), sso, &model.Project{Id: "project"}, "code", nil, "")
+	require.ErrorIs(t, err, ErrHostNotAllowed)
+	assert.Zero(t, requests.Load())
+
+	// The discovered JWKS uri is rejected before the exchange.
+	sso.TokenEndpoint = ""
+	_, err = NewOAuthClient(context.Background(), sso, &model.Project{Id: "project"}, "code", nil, "")
+	require.ErrorIs(t, err, ErrHostNotAllowed)
+	assert.Equal(t, int32(1), requests.Load())
+}
@@ -0,0 +1,103 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	jose "github.com/go-jose/go-jose/v4"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+var defaultContentEncryptions = []jose.ContentEncryption{
+	jose.A128GCM, jose.A192GCM, jose.A256GCM,
+	jose.A128CBC_HS256, jose.A192CBC_HS384, jose.A256CBC_HS512,
+}
+
+// isEncryptedToken reports whether the given token is in JWE compact serialization
+// which has five parts unlike the three parts of JWS.
+func isEncryptedToken(token string) bool {
+	return strings.Count(token, ".") == 4
+}
+
+// decryptIDToken returns the inner signed token of the given ID token if it is encrypted.
+// The ID token which is not encrypted is returned as is.
+func decryptIDToken(token string, sso *model.ProjectSSOConfig_Oidc) (string, error) {
+	if !isEncryptedToken(token) {
+		return token, nil
+	}
+	if sso.IdTokenDecryptionKey == "" {
+		return "", errors.New("id_token is encrypted but no decryption key is configured")
+	}
+
+	key, err := parsePrivateKey(sso.IdTokenDecryptionKey)
+	if err != nil {
+		return "", fmt.Errorf("invalid id_token decryption key: %w", err)
+	}
+	var keyAlgs []jose.KeyAlgorithm
+	switch key.(type) {
+	case *rsa.PrivateKey:
+		keyAlgs = []jose.KeyAlgorithm{jose.RSA_OAEP, jose.RSA_OAEP_256}
+	case *ecdsa.PrivateKey:
+		keyAlgs = []jose.KeyAlgorithm{jose.ECDH_ES, jose.ECDH_ES_A128KW, jose.ECDH_ES_A192KW, jose.ECDH_ES_A256KW}
+	}
+	encs := defaultContentEncryptions
+	if len(sso.IdTokenContentEncryptionAlgorithms) > 0 {
+		encs = make([]jose.ContentEncryption, 0, len(sso.IdTokenContentEncryptionAlgorithms))
+		for _, enc := range sso.IdTokenContentEncryptionAlgorithms {
+			encs = append(encs, jose.ContentEncryption(enc))
+		}
+	}
+
+	jwe, err := jose.ParseEncryptedCompact(token, keyAlgs, encs)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse encrypted id_token: %w", err)
+	}
+	payload, err := jwe.Decrypt(key)
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt id_token: %w", err)
+	}
+	return string(payload), nil
+}
+
+// parsePrivateKey parses the PEM encoded RSA or EC private key.
+func parsePrivateKey(data string) (interface{}, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		switch key := key.(type) {
+		case *rsa.PrivateKey, *ecdsa.PrivateKey:
+			return key, nil
+		default:
+			return nil, fmt.Errorf("unsupported key type %T", key)
+		}
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, errors.New("must be a PKCS#8, PKCS#1 or EC private key")
+}
@@ -0,0 +1,260 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultJWKSCacheTTL is the default duration for which the fetched JWKS is cached
+// when the JWKS endpoint does not specify max-age in its Cache-Control header.
+const DefaultJWKSCacheTTL = time.Hour
+
+// Algorithms are checked by the verifier before verifying the signature,
+// so the key set accepts any of them.
+var allAlgs = []jose.SignatureAlgorithm{
+	jose.RS256, jose.RS384, jose.RS512,
+	jose.ES256, jose.ES384, jose.ES512,
+	jose.PS256, jose.PS384, jose.PS512,
+	jose.EdDSA,
+}
+
+// sharedJWKSCache is shared by all OIDC clients so that projects
+// using the same issuer do not fetch the same JWKS separately.
+var sharedJWKSCache = newJWKSCache(DefaultJWKSCacheTTL)
+
+// SetJWKSCacheTTL changes the TTL of the shared JWKS cache.
+// It should be called before handling any login.
+func SetJWKSCacheTTL(ttl time.Duration) {
+	sharedJWKSCache.setTTL(ttl)
+}
+
+type jwksCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	keySets map[string]*cachedKeySet
+}
+
+func newJWKSCache(ttl time.Duration) *jwksCache {
+	return &jwksCache{
+		ttl:     ttl,
+		keySets: make(map[string]*cachedKeySet),
+	}
+}
+
+func (c *jwksCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+	for _, ks := range c.keySets {
+		ks.setTTL(ttl)
+	}
+}
+
+// keySet returns the key set for the given JWKS uri.
+// The given client is used to fetch the keys, http.DefaultClient is used if nil.
+func (c *jwksCache) keySet(uri string, client *http.Client) *cachedKeySet {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ks, ok := c.keySets[uri]
+	if !ok {
+		ks = &cachedKeySet{
+			uri: uri,
+			ttl: c.ttl,
+			now: time.Now,
+		}
+		c.keySets[uri] = ks
+	}
+	ks.setClient(client)
+	return ks
+}
+
+// cachedKeySet is an oidc.KeySet which caches the keys fetched from the JWKS uri.
+// The keys are refreshed in the background once they expired, and are forcibly
+// refreshed once when no key matches the kid of the token.
+type cachedKeySet struct {
+	uri string
+	now func() time.Time
+	// group deduplicates concurrent fetches.
+	group singleflight.Group
+
+	mu     sync.RWMutex
+	ttl    time.Duration
+	client *http.Client
+	keys   []jose.JSONWebKey
+	expiry time.Time
+}
+
+func (s *cachedKeySet) setTTL(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.ttl = ttl
+}
+
+func (s *cachedKeySet) setClient(client *http.Client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.client = client
+}
+
+// VerifySignature verifies the signature of the given jwt and returns its payload.
+func (s *cachedKeySet) VerifySignature(ctx context.Context, jwt string) ([]byte, error) {
+	jws, err := jose.ParseSigned(jwt, allAlgs)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: malformed jwt: %w", err)
+	}
+	kid := ""
+	for _, sig := range jws.Signatures {
+		kid = sig.Header.KeyID
+		break
+	}
+
+	keys, err := s.cachedKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if payload, ok := verifyWithKeys(jws, kid, keys); ok {
+		return payload, nil
+	}
+
+	// The keys may have been rotated, so refresh them once before failing.
+	keys, err = s.refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if payload, ok := verifyWithKeys(jws, kid, keys); ok {
+		return payload, nil
+	}
+	return nil, fmt.Errorf("oidc: failed to verify id token signature with the keys from %s", s.uri)
+}
+
+// cachedKeys returns the cached keys. It fetches the keys synchronously only if
+// there is no cached key, otherwise it triggers a background refresh on expiry.
+func (s *cachedKeySet) cachedKeys(ctx context.Context) ([]jose.JSONWebKey, error) {
+	s.mu.RLock()
+	keys, expiry := s.keys, s.expiry
+	s.mu.RUnlock()
+
+	if len(keys) == 0 {
+		return s.refresh(ctx)
+	}
+	if s.now().After(expiry) {
+		go s.refresh(context.Background())
+	}
+	return keys, nil
+}
+
+func (s *cachedKeySet) refresh(ctx context.Context) ([]jose.JSONWebKey, error) {
+	ch := s.group.DoChan("", func() (interface{}, error) {
+		// Do not let the caller cancel the shared fetch.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		return retryFetch(ctx, fetchKindJWKS, s.uri, func() (interface{}, error) {
+			return s.fetch(ctx)
+		})
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]jose.JSONWebKey), nil
+	}
+}
+
+func (s *cachedKeySet) fetch(ctx context.Context) ([]jose.JSONWebKey, error) {
+	s.mu.RLock()
+	client, ttl := s.client, s.ttl
+	s.mu.RUnlock()
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: failed to fetch keys: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: unable to read response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc: failed to fetch keys: %w", &fetchStatusError{status: resp.Status, code: resp.StatusCode, body: body})
+	}
+
+	var keySet jose.JSONWebKeySet
+	if err := json.Unmarshal(body, &keySet); err != nil {
+		return nil, fmt.Errorf("oidc: failed to decode keys: %w", err)
+	}
+
+	if maxAge, ok := parseMaxAge(resp.Header.Get("Cache-Control")); ok {
+		ttl = maxAge
+	}
+
+	s.mu.Lock()
+	s.keys = keySet.Keys
+	s.expiry = s.now().Add(ttl)
+	s.mu.Unlock()
+
+	return keySet.Keys, nil
+}
+
+func verifyWithKeys(jws *jose.JSONWebSignature, kid string, keys []jose.JSONWebKey) ([]byte, bool) {
+	for _, key := range keys {
+		if kid != "" && key.KeyID != kid {
+			continue
+		}
+		if payload, err := jws.Verify(&key); err == nil {
+			return payload, true
+		}
+	}
+	return nil, false
+}
+
+// parseMaxAge returns the max-age directive of the given Cache-Control header value.
+func parseMaxAge(cacheControl string) (time.Duration, bool) {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(directive), "=")
+		if !ok || !strings.EqualFold(name, "max-age") {
+			continue
+		}
+		seconds, err := strconv.Atoi(strings.Trim(value, `"`))
+		if err != nil || seconds <= 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	return 0, false
+}
@@ -0,0 +1,191 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	jose "github.com/go-jose/go-jose/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeJWKSServer struct {
+	*httptest.Server
+
+	mu           sync.Mutex
+	keys         []jose.JSONWebKey
+	cacheControl string
+	fetched      int
+}
+
+func newFakeJWKSServer(t *testing.T) *fakeJWKSServer {
+	s := &fakeJWKSServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.fetched++
+		if s.cacheControl != "" {
+			w.Header().Set("Cache-Control", s.cacheControl)
+		}
+		json.NewEncoder(w).Encode(jose.JSONWebKeySet{Keys: s.keys})
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *fakeJWKSServer) setKeys(keys ...*rsa.PrivateKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = nil
+	for i, k := range keys {
+		s.keys = append(s.keys, jose.JSONWebKey{Key: &k.PublicKey, KeyID: kid(i), Algorithm: string(jose.RS256), Use: "sig"})
+	}
+}
+
+func (s *fakeJWKSServer) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetched
+}
+
+func kid(i int) string { return string(rune('a' + i)) }
+
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string) string {
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, (&jose.SignerOptions{}).WithHeader(jose.HeaderKey("kid"), kid))
+	require.NoError(t, err)
+	jws, err := signer.Sign([]byte(`{"sub":"foo"}`))
+	require.NoError(t, err)
+	token, err := jws.CompactSerialize()
+	require.NoError(t, err)
+	return token
+}
+
+func generateKey(t *testing.T) *rsa.PrivateKey {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return key
+}
+
+func TestCachedKeySet(t *testing.T) {
+	ctx := context.Background()
+	key1, key2 := generateKey(t), generateKey(t)
+
+	t.Run("keys are cached until expiry", func(t *testing.T) {
+		server := newFakeJWKSServer(t)
+		server.setKeys(key1)
+		now := time.Now()
+		ks := newJWKSCache(time.Hour).keySet(server.URL, nil)
+		ks.now = func() time.Time { return now }
+
+		for i := 0; i < 3; i++ {
+			payload, err := ks.VerifySignature(ctx, signToken(t, key1, kid(0)))
+			require.NoError(t, err)
+			assert.Equal(t, `{"sub":"foo"}`, string(payload))
+		}
+		assert.Equal(t, 1, server.fetchCount())
+
+		// Expired keys are still used while being refreshed in the background.
+		now = now.Add(2 * time.Hour)
+		_, err := ks.VerifySignature(ctx, signToken(t, key1, kid(0)))
+		require.NoError(t, err)
+		assert.Eventually(t, func() bool { return server.fetchCount() == 2 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("unknown kid triggers a single refresh", func(t *testing.T) {
+		server := newFakeJWKSServer(t)
+		server.setKeys(key1)
+		ks := newJWKSCache(time.Hour).keySet(server.URL, nil)
+
+		_, err := ks.VerifySignature(ctx, signToken(t, key1, kid(0)))
+		require.NoError(t, err)
+
+		// Rotate the keys.
+		server.setKeys(key1, key2)
+		_, err = ks.VerifySignature(ctx, signToken(t, key2, kid(1)))
+		require.NoError(t, err)
+		assert.Equal(t, 2, server.fetchCount())
+
+		// The key which is not served at all fails after one refresh.
+		_, err = ks.VerifySignature(ctx, signToken(t, generateKey(t), kid(2)))
+		assert.Error(t, err)
+		assert.Equal(t, 3, server.fetchCount())
+	})
+
+	t.Run("max-age of the response is respected", func(t *testing.T) {
+		server := newFakeJWKSServer(t)
+		server.setKeys(key1)
+		server.cacheControl = "public, max-age=60"
+		now := time.Now()
+		ks := newJWKSCache(time.Hour).keySet(server.URL, nil)
+		ks.now = func() time.Time { return now }
+
+		_, err := ks.VerifySignature(ctx, signToken(t, key1, kid(0)))
+		require.NoError(t, err)
+		ks.mu.RLock()
+		assert.Equal(t, now.Add(time.Minute), ks.expiry)
+		ks.mu.RUnlock()
+	})
+
+	t.Run("key set is shared by uri", func(t *testing.T) {
+		cache := newJWKSCache(time.Hour)
+		assert.Same(t, cache.keySet("https://example.com/jwks", nil), cache.keySet("https://example.com/jwks", nil))
+		assert.NotSame(t, cache.keySet("https://example.com/jwks", nil), cache.keySet("https://example.org/jwks", nil))
+	})
+}
+
+func TestParseMaxAge(t *testing.T) {
+	cases := []struct {
+		name         string
+		cacheControl string
+		expected     time.Duration
+		ok           bool
+	}{
+		{
+			name:         "empty",
+			cacheControl: "",
+		},
+		{
+			name:         "max-age",
+			cacheControl: "public, max-age=3600, must-revalidate",
+			expected:     time.Hour,
+			ok:           true,
+		},
+		{
+			name:         "no-cache",
+			cacheControl: "no-cache",
+		},
+		{
+			name:         "invalid max-age",
+			cacheControl: "max-age=abc",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, ok := parseMaxAge(c.cacheControl)
+			assert.Equal(t, c.ok, ok)
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oauthhttp"
+)
+
+// EndSessionURL returns the address of the end session endpoint of the provider
+// to log the user out from it, as described in OpenID Connect RP-Initiated Logout.
+// The given post logout redirect URI is sent instead of the one of the SSO configuration unless empty.
+func EndSessionURL(ctx context.Context, sso *model.ProjectSSOConfig_Oidc, idTokenHint, postLogoutRedirectURI string) (string, error) {
+	endpoint := sso.EndSessionEndpoint
+	if endpoint == "" {
+		if err := sharedHostAllowlist.check(sso.Issuer); err != nil {
+			return "", err
+		}
+		client, err := oauthhttp.Client(ctx, sso.ProxyUrl, sso.ExtraHeaders)
+		if err != nil {
+			return "", err
+		}
+
+		discovery, err := sharedDiscoveryCache.discover(ctx, sso.Issuer, client)
+		if err != nil {
+			return "", err
+		}
+		endpoint = discovery.EndSessionURL
+	}
+	if endpoint == "" {
+		return "", fmt.Errorf("end_session_endpoint is not provided by %s", sso.Issuer)
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("client_id", sso.ClientId)
+	q.Set("id_token_hint", idTokenHint)
+	if postLogoutRedirectURI == "" {
+		postLogoutRedirectURI = sso.PostLogoutRedirectUri
+	}
+	q.Set("post_logout_redirect_uri", postLogoutRedirectURI)
+	u.RawQuery = q.Encode()
+
+	return u.String(), nil
+}
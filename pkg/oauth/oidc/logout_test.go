@@ -0,0 +1,100 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestEndSessionURL(t *testing.T) {
+	var endSessionEndpoint string
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":               server.URL,
+			"end_session_endpoint": endSessionEndpoint,
+		})
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		name               string
+		sso                *model.ProjectSSOConfig_Oidc
+		endSessionEndpoint string
+		redirectURI        string
+		expected           string
+		wantErr            bool
+	}{
+		{
+			name: "custom endpoint",
+			sso: &model.ProjectSSOConfig_Oidc{
+				ClientId:              "client-id",
+				Issuer:                "https://invalid-issuer.example.com",
+				EndSessionEndpoint:    "https://example.com/logout?foo=bar",
+				PostLogoutRedirectUri: "https://pipecd.dev/",
+			},
+			expected: "https://example.com/logout?client_id=client-id&foo=bar&id_token_hint=token&post_logout_redirect_uri=https%3A%2F%2Fpipecd.dev%2F",
+		},
+		{
+			name: "discovered endpoint",
+			sso: &model.ProjectSSOConfig_Oidc{
+				ClientId:              "client-id",
+				Issuer:                server.URL,
+				PostLogoutRedirectUri: "https://pipecd.dev/",
+			},
+			endSessionEndpoint: "https://example.com/logout",
+			expected:           "https://example.com/logout?client_id=client-id&id_token_hint=token&post_logout_redirect_uri=https%3A%2F%2Fpipecd.dev%2F",
+		},
+		{
+			name: "custom redirect uri",
+			sso: &model.ProjectSSOConfig_Oidc{
+				ClientId:              "client-id",
+				Issuer:                "https://invalid-issuer.example.com",
+				EndSessionEndpoint:    "https://example.com/logout",
+				PostLogoutRedirectUri: "https://pipecd.dev/",
+			},
+			redirectURI: "https://portal.example.com/",
+			expected:    "https://example.com/logout?client_id=client-id&id_token_hint=token&post_logout_redirect_uri=https%3A%2F%2Fportal.example.com%2F",
+		},
+		{
+			name: "not supported by the provider",
+			sso: &model.ProjectSSOConfig_Oidc{
+				ClientId:              "client-id",
+				Issuer:                server.URL,
+				PostLogoutRedirectUri: "https://pipecd.dev/",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			endSessionEndpoint = c.endSessionEndpoint
+			// The discovery document changes between the cases.
+			sharedDiscoveryCache.invalidate(server.URL)
+			got, err := EndSessionURL(context.Background(), c.sso, "token", c.redirectURI)
+			assert.Equal(t, c.wantErr, err != nil)
+			assert.Equal(t, c.expected, got)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+// tokenExchangeContext returns the context used to exchange the auth code at the token endpoint.
+// The configured client certificate is presented for the mutual TLS client authentication (tls_client_auth),
+// otherwise the given context is returned as is to authenticate with the client secret.
+func tokenExchangeContext(ctx context.Context, sso *model.ProjectSSOConfig_Oidc, base *http.Client) (context.Context, error) {
+	if sso.ClientCertificate == "" && sso.ClientKey == "" {
+		return ctx, nil
+	}
+	if sso.ClientCertificate == "" || sso.ClientKey == "" {
+		return nil, errors.New("both client_certificate and client_key are required for the mutual TLS client authentication")
+	}
+	cert, err := tls.X509KeyPair([]byte(sso.ClientCertificate), []byte(sso.ClientKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid client certificate: %w", err)
+	}
+
+	// Keep the proxy, the trusted CAs and the timeouts of the base client.
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	if base != nil {
+		if bt, ok := base.Transport.(*http.Transport); ok {
+			t = bt.Clone()
+		}
+	}
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	t.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	client := &http.Client{Transport: t}
+	if base != nil {
+		client.Timeout = base.Timeout
+	}
+	return context.WithValue(ctx, oauth2.HTTPClient, client), nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func generateClientCertificate(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "pipecd"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	return cert, encodePrivateKey(t, key)
+}
+
+func TestTokenExchangeContext(t *testing.T) {
+	t.Parallel()
+	cert, key := generateClientCertificate(t)
+
+	var presented []*x509.Certificate
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		presented = r.TLS.PeerCertificates
+	}))
+	srv.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	srv.StartTLS()
+	defer srv.Close()
+
+	t.Run("with client certificate", func(t *testing.T) {
+		sso := &model.ProjectSSOConfig_Oidc{ClientCertificate: cert, ClientKey: key}
+		ctx, err := tokenExchangeContext(context.Background(), sso, srv.Client())
+		require.NoError(t, err)
+
+		client, ok := ctx.Value(oauth2.HTTPClient).(*http.Client)
+		require.True(t, ok)
+		resp, err := client.Get(srv.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		require.Len(t, presented, 1)
+		assert.Equal(t, "pipecd", presented[0].Subject.CommonName)
+	})
+
+	t.Run("without client certificate", func(t *testing.T) {
+		ctx := context.Background()
+		got, err := tokenExchangeContext(ctx, &model.ProjectSSOConfig_Oidc{ClientSecret: "secret"}, srv.Client())
+		require.NoError(t, err)
+		assert.Equal(t, ctx, got)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		_, err := tokenExchangeContext(context.Background(), &model.ProjectSSOConfig_Oidc{ClientCertificate: cert}, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid certificate", func(t *testing.T) {
+		_, err := tokenExchangeContext(context.Background(), &model.ProjectSSOConfig_Oidc{ClientCertificate: "invalid", ClientKey: key}, nil)
+		assert.Error(t, err)
+	})
+}
@@ -16,25 +16,60 @@ package oidc
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"mime"
 	"net/http"
-	"net/url"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/golang-jwt/jwt/v5"
 	"golang.org/x/oauth2"
 
 	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oauthhttp"
 )
 
-var defaultUsernameClaimKeys = []string{"username", "preferred_username", "name", "cognito:username"}
+// The email and subject are the last resorts since they are unique but less readable.
+var defaultUsernameClaimKeys = []string{"username", "preferred_username", "name", "cognito:username", "email", "sub"}
 var defaultAvatarURLClaimKeys = []string{"picture", "avatar_url"}
 var defaultRoleClaimKeys = []string{"groups", "roles", "cognito:groups", "custom:roles", "custom:groups"}
 
+// ErrNonceValidationFailed is returned when the nonce claim of the ID token
+// does not match the one sent in the authorization request.
+var ErrNonceValidationFailed = errors.New("nonce validation failed")
+
+// ErrInvalidIssuer is returned when the iss claim of the ID token
+// does not exactly match the configured issuer.
+var ErrInvalidIssuer = errors.New("invalid id_token issuer")
+
+// ErrInvalidAudience is returned when the aud claim of the ID token does not contain
+// any acceptable audience or the azp claim is not the configured client id.
+var ErrInvalidAudience = errors.New("invalid id_token audience")
+
+// ErrEmailNotVerified is returned when the verified email is required
+// but the email_verified claim is false or absent.
+var ErrEmailNotVerified = errors.New("email not verified")
+
+// ErrInvalidIdPInitiatedToken is returned when the ID token of the IdP-initiated login
+// contains the nonce claim or was not issued recently.
+var ErrInvalidIdPInitiatedToken = errors.New("invalid id_token for idp-initiated login")
+
+// ErrCodeExpired is returned when the token endpoint rejects the auth code as invalid or expired,
+// e.g. the user stayed on the consent screen of the provider too long.
+var ErrCodeExpired = errors.New("auth code expired")
+
+// ErrReauthenticationRequired is returned when max_age is configured but the auth_time claim
+// of the ID token is absent or older than it, e.g. the provider reused the existing session.
+var ErrReauthenticationRequired = errors.New("reauthentication required")
+
+// clockSkew is the allowed difference between the clocks of the identity provider and us.
+const clockSkew = time.Minute
+
 // OAuthClient is an oauth client for OIDC.
 type OAuthClient struct {
 	*oidc.Provider
@@ -42,59 +77,145 @@ type OAuthClient struct {
 
 	sharedSSOConfig *model.ProjectSSOConfig_Oidc
 	project         *model.Project
+	nonce           string
+	// idpInitiatedMaxAge is the max age of the ID token of the IdP-initiated login, zero if not initiated by the IdP.
+	idpInitiatedMaxAge time.Duration
+	// refreshed is true if the ID token was issued by refreshing the token of the login,
+	// whose auth time is the one of the login.
+	refreshed bool
+	// discovery holds the metadata used to verify the ID token.
+	discovery  providerJSON
+	httpClient *http.Client
 }
 
 // NewOAuthClient creates a new oauth client for OIDC.
+// The nonce claim of the ID token is compared with the given nonce unless it is empty.
+// The given options are passed to the token exchange, e.g. the PKCE code verifier.
+// The configured redirect URI must be in the given allowed list.
 func NewOAuthClient(ctx context.Context,
 	sso *model.ProjectSSOConfig_Oidc,
 	project *model.Project,
 	code string,
+	allowedRedirectURIs []string,
+	nonce string,
+	opts ...oauth2.AuthCodeOption,
 ) (*OAuthClient, error) {
+	if err := model.CheckRedirectURI(sso.RedirectUri, allowedRedirectURIs); err != nil {
+		return nil, err
+	}
+	if err := sso.ValidateScopes(); err != nil {
+		return nil, err
+	}
+	if err := sso.ValidateClientAuthentication(); err != nil {
+		return nil, err
+	}
+	if err := sso.ValidateExtraHeaders(); err != nil {
+		return nil, err
+	}
 	c := &OAuthClient{
 		project:         project,
 		sharedSSOConfig: sso,
+		nonce:           nonce,
 	}
-
-	if sso.AuthorizationEndpoint != "" || sso.TokenEndpoint != "" || sso.UserInfoEndpoint != "" {
-		provider, err := createCustomOIDCProvider(ctx, sso)
-		if err != nil {
-			return nil, err
-		}
-		c.Provider = provider
-	} else {
-		provider, err := oidc.NewProvider(ctx, sso.Issuer)
-		if err != nil {
-			return nil, err
+	exchangeCtx, cfg, authOpts, err := c.setup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if authOpts != nil {
+		opts = append(slices.Clone(opts), authOpts...)
+	}
+	oauth2Token, err := cfg.Exchange(exchangeCtx, code, opts...)
+	if err != nil {
+		if isCodeExpired(err) {
+			return nil, fmt.Errorf("%w: %w", ErrCodeExpired, err)
 		}
-		c.Provider = provider
+		return nil, err
+	}
+	c.Token = oauth2Token
+
+	return c, nil
+}
+
+// AuthCodeURL returns the URL of the authorization endpoint to which the user is redirected to log in.
+// Unlike GenerateAuthCodeURL of the SSO configuration, the provider is discovered
+// by the HTTP client given by the context with the cached discovery document.
+func AuthCodeURL(ctx context.Context, sso *model.ProjectSSOConfig_Oidc, project, state string, opts ...oauth2.AuthCodeOption) (string, error) {
+	if err := sso.ValidateScopes(); err != nil {
+		return "", err
+	}
+	ctx, httpClient, err := oauthhttp.ContextWithClient(ctx, sso.ProxyUrl, sso.ExtraHeaders)
+	if err != nil {
+		return "", err
+	}
+	provider, _, err := newProvider(ctx, sso, httpClient)
+	if err != nil {
+		return "", err
 	}
 
 	cfg := oauth2.Config{
+		ClientID:    sso.ClientId,
+		Endpoint:    provider.Endpoint(),
+		Scopes:      sso.ScopesOrDefault(),
+		RedirectURL: sso.RedirectUri,
+	}
+	opts = append([]oauth2.AuthCodeOption{oauth2.ApprovalForce, oauth2.AccessTypeOnline}, opts...)
+	return cfg.AuthCodeURL(model.StateWithProject(state, project), opts...), nil
+}
+
+// setup discovers the provider and returns the config of the requests to the token endpoint
+// along with the context and the options authenticating the client.
+func (c *OAuthClient) setup(ctx context.Context) (context.Context, *oauth2.Config, []oauth2.AuthCodeOption, error) {
+	sso := c.sharedSSOConfig
+	// The calls to the provider are bounded by the timeouts of the client in addition to the context.
+	ctx, httpClient, err := oauthhttp.ContextWithClient(ctx, sso.ProxyUrl, sso.ExtraHeaders)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c.httpClient = httpClient
+
+	provider, discovery, err := newProvider(ctx, sso, c.httpClient)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	c.Provider = provider
+	c.discovery = *discovery
+
+	cfg := &oauth2.Config{
 		ClientID:     sso.ClientId,
 		ClientSecret: sso.ClientSecret,
 		RedirectURL:  sso.RedirectUri,
 		Endpoint:     c.Endpoint(),
-		Scopes:       append(sso.Scopes, oidc.ScopeOpenID),
+		Scopes:       sso.ScopesOrDefault(),
 	}
 
-	if sso.ProxyUrl != "" {
-		proxyURL, err := url.Parse(sso.ProxyUrl)
-		if err != nil {
-			return nil, err
-		}
+	if sso.ClientSecret == "" {
+		// The client authenticated by the certificate and the public client using PKCE
+		// send only their client id in the request body without the client authentication,
+		// otherwise the client secret is sent by either of client_secret_post or client_secret_basic.
+		cfg.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+	}
 
-		t := http.DefaultTransport.(*http.Transport).Clone()
-		t.Proxy = http.ProxyURL(proxyURL)
-		ctx = context.WithValue(ctx, oauth2.HTTPClient, &http.Client{Transport: t})
+	opts, err := clientAssertionOptions(sso, cfg.Endpoint.TokenURL, time.Now())
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if opts != nil {
+		// The client assertion replaces the client secret.
+		cfg.ClientSecret = ""
+		cfg.Endpoint.AuthStyle = oauth2.AuthStyleInParams
 	}
 
-	oauth2Token, err := cfg.Exchange(ctx, code)
+	exchangeCtx, err := tokenExchangeContext(ctx, sso, c.httpClient)
 	if err != nil {
-		return nil, err
+		return nil, nil, nil, err
 	}
-	c.Token = oauth2Token
+	return exchangeCtx, cfg, opts, nil
+}
 
-	return c, nil
+// SetIdPInitiated makes GetUser validate the ID token as the one of the IdP-initiated login,
+// which must not contain the nonce claim and must be issued within the given max age.
+func (c *OAuthClient) SetIdPInitiated(maxAge time.Duration) {
+	c.idpInitiatedMaxAge = maxAge
 }
 
 // GetUser returns a user model.
@@ -104,104 +225,279 @@ func (c *OAuthClient) GetUser(ctx context.Context) (*model.User, error) {
 	if !ok {
 		return nil, fmt.Errorf("no id_token in oauth2 token")
 	}
+	idTokenRAW, err := decryptIDToken(idTokenRAW, c.sharedSSOConfig)
+	if err != nil {
+		return nil, err
+	}
 
-	verifier := c.Verifier(&oidc.Config{ClientID: c.sharedSSOConfig.ClientId})
+	// Use the shared key set to avoid fetching the JWKS on every login.
+	keySet := sharedJWKSCache.keySet(c.discovery.JWKSURL, c.httpClient)
+	// The issuer and audience are checked by checkIssuer and checkAudience
+	// to support multiple audiences and return precise errors.
+	verifier := oidc.NewVerifier(c.discovery.Issuer, keySet, &oidc.Config{
+		SkipClientIDCheck:    true,
+		SkipIssuerCheck:      true,
+		SupportedSigningAlgs: supportedSigningAlgs(c.discovery.Algorithms),
+	})
 	idToken, err := verifier.Verify(ctx, idTokenRAW)
 	if err != nil {
 		return nil, err
 	}
+	if err := c.checkIssuer(idToken.Issuer); err != nil {
+		return nil, err
+	}
+	if err := c.checkNonce(idToken.Nonce); err != nil {
+		return nil, err
+	}
+	if err := c.checkIdPInitiated(idToken.Nonce, idToken.IssuedAt, time.Now()); err != nil {
+		return nil, err
+	}
 
 	var claims jwt.MapClaims
 	if err := idToken.Claims(&claims); err != nil {
 		return nil, err
 	}
-
-	if c.UserInfoEndpoint() != "" {
-		userInfo, err := c.UserInfo(ctx, oauth2.StaticTokenSource(c.Token))
-		if err != nil {
-			return nil, err
-		}
-
-		var userInfoClaims map[string]interface{}
-		if err := userInfo.Claims(&userInfoClaims); err != nil {
+	azp, _ := claims["azp"].(string)
+	if err := c.checkAudience(idToken.Audience, azp); err != nil {
+		return nil, err
+	}
+	if !c.refreshed {
+		if err := c.checkAuthTime(claims, time.Now()); err != nil {
 			return nil, err
 		}
+	}
 
+	// The ID token claims are used as is when the user info is unavailable,
+	// the error is only reported if the claims are not enough to build the user.
+	var (
+		userInfoClaims map[string]interface{}
+		userInfoErr    error
+	)
+	if c.userInfoEnabled() {
+		userInfoClaims, userInfoErr = c.fetchUserInfo(ctx)
 		for k, v := range userInfoClaims {
 			claims[k] = v
 		}
 	}
+	// The groups omitted from the ID token are fetched in full,
+	// otherwise the roles mapped from them are silently lost.
+	if c.groupsOverflowed(claims) {
+		groups, err := c.fetchGroups(ctx, userInfoClaims)
+		if err != nil {
+			return nil, fmt.Errorf("groups omitted from id_token and unable to fetch them: %w", err)
+		}
+		claims[c.groupsClaimKey()] = groups
+	}
+	if err := c.checkEmailVerified(claims); err != nil {
+		return nil, err
+	}
 
-	role, err := c.decideRole(claims, c.sharedSSOConfig.RolesClaimKey)
+	role, err := c.decideRole(claims, c.sharedSSOConfig.RolesClaimKey, c.sharedSSOConfig.RolesClaimPaths)
 	if err != nil {
-		return nil, err
+		return nil, withUserInfoError(err, userInfoErr)
 	}
 
 	username, avatarURL, err := c.decideUserInfos(claims, c.sharedSSOConfig.UsernameClaimKey, c.sharedSSOConfig.AvatarUrlClaimKey)
 	if err != nil {
-		return nil, err
+		return nil, withUserInfoError(err, userInfoErr)
 	}
+	email, _ := claims["email"].(string)
 	return &model.User{
-		Username:  username,
-		AvatarUrl: avatarURL,
-		Role:      role,
+		Username:      username,
+		AvatarUrl:     avatarURL,
+		Role:          role,
+		Email:         email,
+		EmailVerified: emailVerified(claims),
+		Groups:        c.project.MatchedUserGroups(c.userGroupValues(claims, c.sharedSSOConfig.RolesClaimKey, c.sharedSSOConfig.RolesClaimPaths)),
 	}, nil
 }
 
-func (c *OAuthClient) decideRole(claims jwt.MapClaims, roleClaimKey string) (role *model.Role, err error) {
-	roleStrings := make([]string, 0)
+// groupsClaimKey returns the key of the claim holding the groups, which is the first of the default keys if not configured.
+func (c *OAuthClient) groupsClaimKey() string {
+	if c.sharedSSOConfig.RolesClaimKey != "" {
+		return c.sharedSSOConfig.RolesClaimKey
+	}
+	return defaultRoleClaimKeys[0]
+}
 
-	role = &model.Role{
-		ProjectId:        c.project.Id,
-		ProjectRbacRoles: roleStrings,
+// userInfoEnabled returns whether the claims should be fetched from the user info endpoint.
+func (c *OAuthClient) userInfoEnabled() bool {
+	if c.UserInfoEndpoint() == "" {
+		return false
 	}
+	return c.sharedSSOConfig.FetchUserinfo || c.sharedSSOConfig.UserInfoEndpoint != ""
+}
 
-	roleClaimKeys := []string{}
-	if roleClaimKey != "" {
-		roleClaimKeys = append(roleClaimKeys, roleClaimKey)
-	} else {
-		roleClaimKeys = defaultRoleClaimKeys
+func (c *OAuthClient) fetchUserInfo(ctx context.Context) (map[string]interface{}, error) {
+	if c.httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, c.httpClient)
+	}
+	userInfo, err := c.UserInfo(ctx, oauth2.StaticTokenSource(c.Token))
+	if err != nil {
+		return nil, err
 	}
 
-	for _, key := range roleClaimKeys {
-		val, ok := claims[key]
-		if !ok || val == nil {
-			continue
+	var claims map[string]interface{}
+	if err := userInfo.Claims(&claims); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+func withUserInfoError(err, userInfoErr error) error {
+	if userInfoErr == nil {
+		return err
+	}
+	return fmt.Errorf("%w (failed to fetch user info: %v)", err, userInfoErr)
+}
+
+func (c *OAuthClient) checkIssuer(issuer string) error {
+	if issuer != c.sharedSSOConfig.Issuer {
+		return fmt.Errorf("%w: expected %q but got %q", ErrInvalidIssuer, c.sharedSSOConfig.Issuer, issuer)
+	}
+	return nil
+}
+
+// checkAudience checks whether the audiences contain the client id or one of the allowed audiences.
+// As the OIDC spec, the azp claim must be the client id if present.
+func (c *OAuthClient) checkAudience(audiences []string, azp string) error {
+	clientID := c.sharedSSOConfig.ClientId
+	if azp != "" && azp != clientID {
+		return fmt.Errorf("%w: expected azp %q but got %q", ErrInvalidAudience, clientID, azp)
+	}
+	for _, aud := range audiences {
+		if aud == clientID || slices.Contains(c.sharedSSOConfig.AllowedAudiences, aud) {
+			return nil
 		}
-		switch val := val.(type) {
-		case []interface{}:
-			for _, item := range val {
-				if str, ok := item.(string); ok {
-					roleStrings = append(roleStrings, str)
-				}
-			}
-		case []string:
-			roleStrings = append(roleStrings, val...)
-		case string:
-			if val != "" {
-				roleStrings = append(roleStrings, val)
-			}
+	}
+	return fmt.Errorf("%w: expected %q in %q", ErrInvalidAudience, clientID, audiences)
+}
+
+func (c *OAuthClient) checkNonce(nonce string) error {
+	if c.nonce == "" {
+		return nil
+	}
+	if nonce == "" {
+		return fmt.Errorf("%w: missing nonce in id_token", ErrNonceValidationFailed)
+	}
+	if subtle.ConstantTimeCompare([]byte(nonce), []byte(c.nonce)) != 1 {
+		return fmt.Errorf("%w: nonce mismatch", ErrNonceValidationFailed)
+	}
+	return nil
+}
+
+// checkIdPInitiated checks the ID token of the IdP-initiated login.
+// Since no nonce was sent, the nonce claim means the token was issued for another login.
+func (c *OAuthClient) checkIdPInitiated(nonce string, issuedAt, now time.Time) error {
+	if c.idpInitiatedMaxAge <= 0 {
+		return nil
+	}
+	if nonce != "" {
+		return fmt.Errorf("%w: unexpected nonce in id_token", ErrInvalidIdPInitiatedToken)
+	}
+	if issuedAt.IsZero() {
+		return fmt.Errorf("%w: missing iat in id_token", ErrInvalidIdPInitiatedToken)
+	}
+	if issuedAt.After(now.Add(clockSkew)) {
+		return fmt.Errorf("%w: id_token issued in the future at %s", ErrInvalidIdPInitiatedToken, issuedAt)
+	}
+	if now.Sub(issuedAt) > c.idpInitiatedMaxAge+clockSkew {
+		return fmt.Errorf("%w: id_token issued too long ago at %s", ErrInvalidIdPInitiatedToken, issuedAt)
+	}
+	return nil
+}
+
+// checkAuthTime checks whether the user actively authenticated within max_age by the auth_time claim of the ID token.
+func (c *OAuthClient) checkAuthTime(claims jwt.MapClaims, now time.Time) error {
+	maxAge := time.Duration(c.sharedSSOConfig.MaxAge) * time.Second
+	if maxAge <= 0 {
+		return nil
+	}
+	var authTime time.Time
+	switch v := claims["auth_time"].(type) {
+	case nil:
+		return fmt.Errorf("%w: missing auth_time in id_token", ErrReauthenticationRequired)
+	case float64:
+		authTime = time.Unix(int64(v), 0)
+	case json.Number:
+		n, err := v.Int64()
+		if err != nil {
+			return fmt.Errorf("%w: invalid auth_time in id_token: %v", ErrReauthenticationRequired, err)
+		}
+		authTime = time.Unix(n, 0)
+	default:
+		return fmt.Errorf("%w: invalid auth_time in id_token", ErrReauthenticationRequired)
+	}
+	if now.Sub(authTime) > maxAge+clockSkew {
+		return fmt.Errorf("%w: user authenticated too long ago at %s", ErrReauthenticationRequired, authTime.UTC())
+	}
+	return nil
+}
+
+func (c *OAuthClient) checkEmailVerified(claims jwt.MapClaims) error {
+	if !c.sharedSSOConfig.RequireVerifiedEmail || emailVerified(claims) {
+		return nil
+	}
+	return ErrEmailNotVerified
+}
+
+// emailVerified reports whether the email_verified claim is true.
+func emailVerified(claims jwt.MapClaims) bool {
+	switch v := claims["email_verified"].(type) {
+	case bool:
+		return v
+	case string:
+		// Some providers return the boolean claim as a string.
+		return v == "true"
+	}
+	return false
+}
+
+// decideRole decides the role of the user from the roles in the claim of the given key, or the default keys if empty,
+// and in the nested claims of the given paths. The roles are either the built-in roles or mapped by the user groups.
+func (c *OAuthClient) decideRole(claims jwt.MapClaims, roleClaimKey string, roleClaimPaths []string) (role *model.Role, err error) {
+	role = &model.Role{
+		ProjectId:        c.project.Id,
+		ProjectRbacRoles: make([]string, 0),
+	}
+	roleStrings := c.userGroupValues(claims, roleClaimKey, roleClaimPaths)
+
+	groupRoles := make(map[string]string, len(c.project.UserGroups))
+	for _, g := range c.project.UserGroups {
+		groupRoles[g.SsoGroup] = g.Role
+	}
+	addRole := func(r string) {
+		if !slices.Contains(role.ProjectRbacRoles, r) {
+			role.ProjectRbacRoles = append(role.ProjectRbacRoles, r)
 		}
 	}
 
 	// Check if the current user belongs to any registered teams.
 	for _, r := range roleStrings {
-		if r == model.BuiltinRBACRoleAdmin.String() {
-			role.ProjectRbacRoles = append(role.ProjectRbacRoles, model.BuiltinRBACRoleAdmin.String())
+		switch r {
+		case model.BuiltinRBACRoleAdmin.String(), model.BuiltinRBACRoleEditor.String(), model.BuiltinRBACRoleViewer.String():
+			addRole(r)
 		}
-		if r == model.BuiltinRBACRoleEditor.String() {
-			role.ProjectRbacRoles = append(role.ProjectRbacRoles, model.BuiltinRBACRoleEditor.String())
+		if v, ok := groupRoles[r]; ok {
+			addRole(v)
 		}
-		if r == model.BuiltinRBACRoleViewer.String() {
-			role.ProjectRbacRoles = append(role.ProjectRbacRoles, model.BuiltinRBACRoleViewer.String())
+	}
+
+	if len(role.ProjectRbacRoles) != 0 {
+		return
+	}
+
+	// In case the current user does not have any role,
+	// assign the roles mapped to the domain of the verified email.
+	if emailVerified(claims) {
+		email, _ := claims["email"].(string)
+		if roles := c.project.RolesByEmailDomain(email); len(roles) > 0 {
+			role.ProjectRbacRoles = roles
+			return
 		}
 	}
 
-	// In case the current user does not have any role
-	// if AllowStrayAsViewer option is set, assign Viewer role
-	// as user's role.
-	if c.project.AllowStrayAsViewer && len(roleStrings) == 0 {
-		role.ProjectRbacRoles = []string{model.BuiltinRBACRoleViewer.String()}
+	if c.project.AssignStrayRoles(role) {
 		return
 	}
 
@@ -209,10 +505,98 @@ func (c *OAuthClient) decideRole(claims jwt.MapClaims, roleClaimKey string) (rol
 		err = fmt.Errorf("no role found in claims")
 		return
 	}
-
+	err = fmt.Errorf("none of the roles %q in claims is a project role", roleStrings)
 	return
 }
 
+// userGroupValues returns the values of the claims matched with the user groups of the project,
+// which are the groups in the claims of the given key and paths followed by the roles of the role source claims.
+func (c *OAuthClient) userGroupValues(claims jwt.MapClaims, roleClaimKey string, roleClaimPaths []string) []string {
+	values := roleClaimValues(claims, roleClaimKey, roleClaimPaths)
+	return append(values, roleSourceClaimValues(claims, c.sharedSSOConfig.GetRoleSourceClaims())...)
+}
+
+// roleSourceClaimValues returns the roles in the first of the given source claims present in the claims,
+// so that the sources listed earlier take precedence. Each source is a claim name or a dot-separated path of the nested claim.
+func roleSourceClaimValues(claims jwt.MapClaims, sources []string) []string {
+	for _, source := range sources {
+		if v := lookupClaimPath(claims, source); v != nil {
+			return claimStrings(v)
+		}
+	}
+	return nil
+}
+
+// roleClaimValues returns the roles or the groups in the claim of the given key, or the default keys if empty,
+// and in the nested claims of the given paths.
+func roleClaimValues(claims jwt.MapClaims, roleClaimKey string, roleClaimPaths []string) []string {
+	roleClaimKeys := defaultRoleClaimKeys
+	if roleClaimKey != "" {
+		roleClaimKeys = []string{roleClaimKey}
+	}
+
+	values := make([]string, 0)
+	for _, key := range roleClaimKeys {
+		values = append(values, claimStrings(claims[key])...)
+	}
+	for _, path := range roleClaimPaths {
+		values = append(values, claimStrings(lookupClaimPath(claims, path))...)
+	}
+	return values
+}
+
+// isCodeExpired reports whether the token endpoint rejected the auth code.
+// The providers respond invalid_grant for the expired or already used code as defined in RFC 6749,
+// while some of them respond expired_code instead.
+func isCodeExpired(err error) bool {
+	var re *oauth2.RetrieveError
+	if !errors.As(err, &re) {
+		return false
+	}
+	return re.ErrorCode == "invalid_grant" || re.ErrorCode == "expired_code"
+}
+
+// claimStrings returns the string values of the given claim, which is either a string or an array of strings.
+func claimStrings(val interface{}) []string {
+	switch val := val.(type) {
+	case []interface{}:
+		strs := make([]string, 0, len(val))
+		for _, item := range val {
+			if str, ok := item.(string); ok {
+				strs = append(strs, str)
+			}
+		}
+		return strs
+	case []string:
+		return val
+	case string:
+		if val != "" {
+			return []string{val}
+		}
+	}
+	return nil
+}
+
+// lookupClaimPath returns the value of the nested claim at the given dot-separated path,
+// e.g. realm_access.roles of Keycloak. The claim whose name is the path itself takes precedence,
+// so that the namespaced claims containing dots such as https://example.com/roles can be used.
+func lookupClaimPath(claims map[string]interface{}, path string) interface{} {
+	if v, ok := claims[path]; ok {
+		return v
+	}
+	var cur interface{} = claims
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		if cur, ok = m[key]; !ok {
+			return nil
+		}
+	}
+	return cur
+}
+
 func (c *OAuthClient) decideUserInfos(claims jwt.MapClaims, usernameClaimKey, avatarURLClaimKey string) (username, avatarURL string, err error) {
 
 	username = ""
@@ -234,13 +618,17 @@ func (c *OAuthClient) decideUserInfos(claims jwt.MapClaims, usernameClaimKey, av
 	}
 
 	if username == "" {
+		if usernameClaimKey != "" {
+			err = fmt.Errorf("username claim %q not found in the id token or userinfo", usernameClaimKey)
+			return
+		}
 		err = fmt.Errorf("no username found in claims")
 		return
 	}
 
 	avatarURL = ""
 	avatarURLClaimKeys := []string{}
-	if usernameClaimKey != "" {
+	if avatarURLClaimKey != "" {
 		avatarURLClaimKeys = append(avatarURLClaimKeys, avatarURLClaimKey)
 	} else {
 		avatarURLClaimKeys = defaultAvatarURLClaimKeys
@@ -258,54 +646,29 @@ func (c *OAuthClient) decideUserInfos(claims jwt.MapClaims, usernameClaimKey, av
 	return username, avatarURL, nil
 }
 
+// newProvider creates the OIDC provider from the discovery document of the issuer, which is cached across logins.
 // As the go-oidc package does not provide any method to override fields like UserInfoEndpoint or AuthorizeEndpoint,
-// NewOAuthClient needs to create a custom OIDC provider based on the provider created by the go-oidc package.
-// createCustomOIDCProvider will first call the openid-configuration endpoint to retrieve all endpoints from the issuer URL,
-// then pass user-provided URLs to override the existing URLs in the providerConfig struct.
+// the user-provided URLs are passed to override the discovered ones in the providerConfig struct.
 // Portions of this function are derived from the CoreOS Project:
-// https://pkg.go.dev/github.com/coreos/go-oidc/v3@v3.11.0/oidc#NewProvider
 // https://pkg.go.dev/github.com/coreos/go-oidc/v3@v3.11.0/oidc#ProviderConfig
-func createCustomOIDCProvider(ctx context.Context, sso *model.ProjectSSOConfig_Oidc) (*oidc.Provider, error) {
-	// NOTICE: https://github.com/coreos/go-oidc/blob/master/NOTICE
-	// CoreOS Project
-	// Copyright 2014 CoreOS, Inc
-	//
-	// This product includes software developed at CoreOS, Inc.
-	// (http://www.coreos.com/).
-	// Copied from go-oidc package
+func newProvider(ctx context.Context, sso *model.ProjectSSOConfig_Oidc, client *http.Client) (*oidc.Provider, *providerJSON, error) {
 	issuer := sso.Issuer
-
-	wellKnown := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
-	req, err := http.NewRequest("GET", wellKnown, nil)
-	if err != nil {
-		return nil, err
+	// The configured hosts are checked before any request to the provider.
+	if err := CheckAllowedHosts(sso); err != nil {
+		return nil, nil, err
 	}
-
-	client := http.DefaultClient
-	if c := getClient(ctx); c != nil {
-		client = c
-	}
-	resp, err := client.Do(req.WithContext(ctx))
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
+	p, err := sharedDiscoveryCache.discover(ctx, issuer, client)
 	if err != nil {
-		return nil, fmt.Errorf("unable to read response body: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("%s: %s", resp.Status, body)
+		return nil, nil, err
 	}
 
-	var p providerJSON
-	err = unmarshalResp(resp, body, &p)
-	if err != nil {
-		return nil, fmt.Errorf("oidc: failed to decode provider discovery object: %v", err)
+	custom := sso.AuthorizationEndpoint != "" || sso.TokenEndpoint != "" || sso.UserInfoEndpoint != ""
+	if !custom && p.Issuer != issuer {
+		// Same as the go-oidc package, the issuer must be the one used for the discovery.
+		// The document is fetched again on the next login in case the provider has fixed it.
+		sharedDiscoveryCache.invalidate(issuer)
+		return nil, nil, fmt.Errorf("oidc: issuer did not match the issuer returned by provider, expected %q got %q", issuer, p.Issuer)
 	}
-	// End of Copied from go-oidc package
 
 	// Override the endpoints with the user-provided URLs
 	providerConfig := oidc.ProviderConfig{
@@ -329,17 +692,34 @@ func createCustomOIDCProvider(ctx context.Context, sso *model.ProjectSSOConfig_O
 			}
 			return p.UserInfoURL
 		}(),
-		JWKSURL: p.JWKSURL,
+		JWKSURL:    p.JWKSURL,
+		Algorithms: p.Algorithms,
+	}
+	// The discovered endpoints are called as well.
+	if err := sharedHostAllowlist.check(providerConfig.TokenURL, providerConfig.UserInfoURL, providerConfig.JWKSURL, providerConfig.DeviceAuthURL); err != nil {
+		return nil, nil, err
 	}
 
-	return providerConfig.NewProvider(ctx), nil
+	// The issuer is configured by the user in the same way as go-oidc
+	// using it for the discovery.
+	p.Issuer = issuer
+	return providerConfig.NewProvider(ctx), &p, nil
 }
 
-func getClient(ctx context.Context) *http.Client {
-	if c, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok {
-		return c
+// supportedSigningAlgs returns the algorithms advertised by the provider
+// that are supported by go-oidc. RS256 is used if none of them is supported.
+func supportedSigningAlgs(algs []string) []string {
+	supported := make([]string, 0, len(algs))
+	for _, a := range algs {
+		switch a {
+		case oidc.RS256, oidc.RS384, oidc.RS512, oidc.ES256, oidc.ES384, oidc.ES512, oidc.PS256, oidc.PS384, oidc.PS512, oidc.EdDSA:
+			supported = append(supported, a)
+		}
 	}
-	return nil
+	if len(supported) == 0 {
+		return []string{oidc.RS256}
+	}
+	return supported
 }
 
 type providerJSON struct {
@@ -350,6 +730,7 @@ type providerJSON struct {
 	JWKSURL       string   `json:"jwks_uri"`
 	UserInfoURL   string   `json:"userinfo_endpoint"`
 	Algorithms    []string `json:"id_token_signing_alg_values_supported"`
+	EndSessionURL string   `json:"end_session_endpoint"`
 }
 
 func unmarshalResp(r *http.Response, body []byte, v interface{}) error {
@@ -15,13 +15,25 @@
 package oidc
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
 	"testing"
+	"time"
 
+	"github.com/coreos/go-oidc/v3/oidc"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
 
 	"github.com/pipe-cd/pipecd/pkg/model"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oauthhttp"
 )
 
 func TestDecideRole(t *testing.T) {
@@ -109,10 +121,38 @@ func TestDecideRole(t *testing.T) {
 			expected: nil,
 			err:      fmt.Errorf("no role found in claims"),
 		},
+		{
+			claims: jwt.MapClaims{
+				"groups": []interface{}{"unknown"},
+			},
+			oc: &OAuthClient{
+				project: &model.Project{
+					Id:          "project-id",
+					DefaultRole: model.BuiltinRBACRoleEditor.String(),
+				},
+			},
+			expected: &model.Role{
+				ProjectId:        "project-id",
+				ProjectRbacRoles: []string{model.BuiltinRBACRoleEditor.String()},
+			},
+			err: nil,
+		},
+		{
+			claims: jwt.MapClaims{
+				"groups": []interface{}{"unknown"},
+			},
+			oc: &OAuthClient{
+				project: &model.Project{
+					Id: "project-id",
+				},
+			},
+			expected: nil,
+			err:      fmt.Errorf(`none of the roles ["unknown"] in claims is a project role`),
+		},
 	}
 
 	for _, c := range cases {
-		role, err := c.oc.decideRole(c.claims, "")
+		role, err := c.oc.decideRole(c.claims, "", nil)
 		if c.err != nil {
 			assert.Error(t, err)
 			assert.Equal(t, c.err.Error(), err.Error())
@@ -123,11 +163,272 @@ func TestDecideRole(t *testing.T) {
 	}
 }
 
+func TestDecideRoleByEmailDomain(t *testing.T) {
+	project := &model.Project{
+		Id:          "project-id",
+		DefaultRole: model.BuiltinRBACRoleViewer.String(),
+		UserGroups: []*model.ProjectUserGroup{
+			{SsoGroup: "deployers", Role: model.BuiltinRBACRoleEditor.String()},
+		},
+		EmailDomainRoles: []*model.ProjectEmailDomainRole{
+			{Domain: "admin.example.com", Role: model.BuiltinRBACRoleAdmin.String()},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		claims   jwt.MapClaims
+		expected []string
+	}{
+		{
+			name:     "group mapping wins",
+			claims:   jwt.MapClaims{"groups": []interface{}{"deployers"}, "email": "foo@admin.example.com", "email_verified": true},
+			expected: []string{model.BuiltinRBACRoleEditor.String()},
+		},
+		{
+			name:     "domain mapping",
+			claims:   jwt.MapClaims{"groups": []interface{}{"unknown"}, "email": "foo@admin.example.com", "email_verified": true},
+			expected: []string{model.BuiltinRBACRoleAdmin.String()},
+		},
+		{
+			name:     "domain mapping with verified string claim",
+			claims:   jwt.MapClaims{"email": "foo@Admin.Example.com", "email_verified": "true"},
+			expected: []string{model.BuiltinRBACRoleAdmin.String()},
+		},
+		{
+			name:     "unverified email",
+			claims:   jwt.MapClaims{"email": "foo@admin.example.com", "email_verified": false},
+			expected: []string{model.BuiltinRBACRoleViewer.String()},
+		},
+		{
+			name:     "missing email_verified",
+			claims:   jwt.MapClaims{"email": "foo@admin.example.com"},
+			expected: []string{model.BuiltinRBACRoleViewer.String()},
+		},
+		{
+			name:     "other domain",
+			claims:   jwt.MapClaims{"email": "foo@example.com", "email_verified": true},
+			expected: []string{model.BuiltinRBACRoleViewer.String()},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &OAuthClient{project: project}
+			role, err := c.decideRole(tc.claims, "", nil)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, role.ProjectRbacRoles)
+		})
+	}
+}
+
+func TestDecideRoleKeycloak(t *testing.T) {
+	// The claims of the ID token issued by Keycloak with the realm and client role mappers.
+	claims := jwt.MapClaims{
+		"preferred_username": "foo",
+		"realm_access": map[string]interface{}{
+			"roles": []interface{}{"offline_access", "uma_authorization", "platform-admins"},
+		},
+		"resource_access": map[string]interface{}{
+			"pipecd": map[string]interface{}{
+				"roles": []interface{}{"deployers"},
+			},
+			"account": map[string]interface{}{
+				"roles": []interface{}{"manage-account", "view-profile"},
+			},
+		},
+	}
+	project := &model.Project{
+		Id: "project-id",
+		UserGroups: []*model.ProjectUserGroup{
+			{SsoGroup: "platform-admins", Role: model.BuiltinRBACRoleAdmin.String()},
+			{SsoGroup: "deployers", Role: model.BuiltinRBACRoleEditor.String()},
+			{SsoGroup: "viewers", Role: model.BuiltinRBACRoleViewer.String()},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		key      string
+		paths    []string
+		expected []string
+		err      string
+	}{
+		{
+			name:     "realm roles",
+			paths:    []string{"realm_access.roles"},
+			expected: []string{model.BuiltinRBACRoleAdmin.String()},
+		},
+		{
+			name:     "client roles",
+			paths:    []string{"resource_access.pipecd.roles"},
+			expected: []string{model.BuiltinRBACRoleEditor.String()},
+		},
+		{
+			name:     "realm and client roles",
+			paths:    []string{"realm_access.roles", "resource_access.pipecd.roles"},
+			expected: []string{model.BuiltinRBACRoleAdmin.String(), model.BuiltinRBACRoleEditor.String()},
+		},
+		{
+			name:  "roles of other client",
+			paths: []string{"resource_access.account.roles"},
+			err:   `none of the roles ["manage-account" "view-profile"] in claims is a project role`,
+		},
+		{
+			name:  "missing path",
+			paths: []string{"resource_access.unknown.roles", "realm_access.roles.nested"},
+			err:   "no role found in claims",
+		},
+		{
+			name: "flat claims only",
+			err:  "no role found in claims",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &OAuthClient{project: project}
+			role, err := c.decideRole(claims, tc.key, tc.paths)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.err, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, role.ProjectRbacRoles)
+		})
+	}
+}
+
+func TestDecideRoleSourceClaims(t *testing.T) {
+	project := &model.Project{
+		Id: "project-id",
+		UserGroups: []*model.ProjectUserGroup{
+			{SsoGroup: "platform-admins", Role: model.BuiltinRBACRoleAdmin.String()},
+			{SsoGroup: "pipecd-deployer", Role: model.BuiltinRBACRoleEditor.String()},
+			{SsoGroup: "pipecd-viewer", Role: model.BuiltinRBACRoleViewer.String()},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		claims   jwt.MapClaims
+		sources  []string
+		expected []string
+		groups   []string
+		err      string
+	}{
+		{
+			name: "roles in roles claim",
+			claims: jwt.MapClaims{
+				"roles": []interface{}{"pipecd-deployer"},
+			},
+			sources:  []string{"roles"},
+			expected: []string{model.BuiltinRBACRoleEditor.String()},
+			groups:   []string{"pipecd-deployer"},
+		},
+		{
+			name: "groups in groups claim",
+			claims: jwt.MapClaims{
+				"groups": []interface{}{"platform-admins"},
+			},
+			sources:  []string{"roles"},
+			expected: []string{model.BuiltinRBACRoleAdmin.String()},
+			groups:   []string{"platform-admins"},
+		},
+		{
+			name: "both of groups and roles",
+			claims: jwt.MapClaims{
+				"groups": []interface{}{"platform-admins"},
+				"roles":  []interface{}{"pipecd-deployer"},
+			},
+			sources:  []string{"roles"},
+			expected: []string{model.BuiltinRBACRoleAdmin.String(), model.BuiltinRBACRoleEditor.String()},
+			groups:   []string{"platform-admins", "pipecd-deployer"},
+		},
+		{
+			name: "first present source takes precedence",
+			claims: jwt.MapClaims{
+				"entitlements": []interface{}{"pipecd-viewer"},
+				"roles":        []interface{}{"pipecd-deployer"},
+			},
+			sources:  []string{"entitlements", "roles"},
+			expected: []string{model.BuiltinRBACRoleViewer.String()},
+			groups:   []string{"pipecd-viewer"},
+		},
+		{
+			name: "falls back to next source",
+			claims: jwt.MapClaims{
+				"roles": []interface{}{"pipecd-deployer"},
+			},
+			sources:  []string{"entitlements", "roles"},
+			expected: []string{model.BuiltinRBACRoleEditor.String()},
+			groups:   []string{"pipecd-deployer"},
+		},
+		{
+			name: "nested source claim",
+			claims: jwt.MapClaims{
+				"resource_access": map[string]interface{}{
+					"pipecd": map[string]interface{}{
+						"roles": []interface{}{"pipecd-deployer"},
+					},
+				},
+			},
+			sources:  []string{"resource_access.pipecd.roles"},
+			expected: []string{model.BuiltinRBACRoleEditor.String()},
+			groups:   []string{"pipecd-deployer"},
+		},
+		{
+			name: "roles claim not used as groups without source",
+			claims: jwt.MapClaims{
+				"roles": []interface{}{"pipecd-deployer"},
+			},
+			err: "no role found in claims",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &OAuthClient{
+				project: project,
+				sharedSSOConfig: &model.ProjectSSOConfig_Oidc{
+					// The groups are given only by the groups claim, not by the roles claim of the default keys.
+					RolesClaimKey:    "groups",
+					RoleSourceClaims: tc.sources,
+				},
+			}
+			role, err := c.decideRole(tc.claims, c.sharedSSOConfig.RolesClaimKey, nil)
+			if tc.err != "" {
+				require.Error(t, err)
+				assert.Equal(t, tc.err, err.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, role.ProjectRbacRoles)
+			assert.Equal(t, tc.groups, project.MatchedUserGroups(c.userGroupValues(tc.claims, c.sharedSSOConfig.RolesClaimKey, nil)))
+		})
+	}
+}
+
+func TestLookupClaimPath(t *testing.T) {
+	claims := map[string]interface{}{
+		"https://example.com/roles": []interface{}{"namespaced"},
+		"realm_access": map[string]interface{}{
+			"roles": []interface{}{"realm"},
+		},
+		"groups": "flat",
+	}
+	assert.Equal(t, []interface{}{"namespaced"}, lookupClaimPath(claims, "https://example.com/roles"))
+	assert.Equal(t, []interface{}{"realm"}, lookupClaimPath(claims, "realm_access.roles"))
+	assert.Equal(t, "flat", lookupClaimPath(claims, "groups"))
+	assert.Nil(t, lookupClaimPath(claims, "groups.nested"))
+	assert.Nil(t, lookupClaimPath(claims, "realm_access.unknown"))
+}
+
 func TestDecideUserInfos(t *testing.T) {
 	client := &OAuthClient{}
 
 	cases := []struct {
 		claims         jwt.MapClaims
+		usernameKey    string
+		avatarURLKey   string
 		expectedUser   string
 		expectedAvatar string
 		err            error
@@ -187,10 +488,47 @@ func TestDecideUserInfos(t *testing.T) {
 			expectedAvatar: "",
 			err:            fmt.Errorf("no username found in claims"),
 		},
+		{
+			claims: jwt.MapClaims{
+				"email": "john@example.com",
+				"sub":   "00u1",
+			},
+			expectedUser:   "john@example.com",
+			expectedAvatar: "",
+			err:            nil,
+		},
+		{
+			claims: jwt.MapClaims{
+				"sub": "00u1",
+			},
+			expectedUser:   "00u1",
+			expectedAvatar: "",
+			err:            nil,
+		},
+		{
+			claims: jwt.MapClaims{
+				"preferred_username": "johnny",
+				"email":              "john@example.com",
+				"picture":            "http://example.com/avatar.jpg",
+			},
+			usernameKey:    "email",
+			expectedUser:   "john@example.com",
+			expectedAvatar: "http://example.com/avatar.jpg",
+			err:            nil,
+		},
+		{
+			claims: jwt.MapClaims{
+				"preferred_username": "johnny",
+			},
+			usernameKey:    "email",
+			expectedUser:   "",
+			expectedAvatar: "",
+			err:            fmt.Errorf(`username claim "email" not found in the id token or userinfo`),
+		},
 	}
 
 	for _, c := range cases {
-		username, avatarURL, err := client.decideUserInfos(c.claims, "", "")
+		username, avatarURL, err := client.decideUserInfos(c.claims, c.usernameKey, c.avatarURLKey)
 		if c.err != nil {
 			assert.Error(t, err)
 			assert.Equal(t, c.err.Error(), err.Error())
@@ -201,3 +539,526 @@ func TestDecideUserInfos(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckNonce(t *testing.T) {
+	cases := []struct {
+		name     string
+		expected string
+		nonce    string
+		wantErr  bool
+	}{
+		{
+			name:     "validation disabled",
+			expected: "",
+			nonce:    "",
+		},
+		{
+			name:     "matched",
+			expected: "nonce",
+			nonce:    "nonce",
+		},
+		{
+			name:     "mismatched",
+			expected: "nonce",
+			nonce:    "other",
+			wantErr:  true,
+		},
+		{
+			name:     "missing",
+			expected: "nonce",
+			nonce:    "",
+			wantErr:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &OAuthClient{nonce: c.expected}
+			err := client.checkNonce(c.nonce)
+			assert.Equal(t, c.wantErr, err != nil)
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrNonceValidationFailed))
+			}
+		})
+	}
+}
+
+func TestCheckIdPInitiated(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name     string
+		maxAge   time.Duration
+		nonce    string
+		issuedAt time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "not initiated by the idp",
+			nonce:    "nonce",
+			issuedAt: now.Add(-time.Hour),
+		},
+		{
+			name:     "fresh token",
+			maxAge:   5 * time.Minute,
+			issuedAt: now.Add(-time.Minute),
+		},
+		{
+			name:     "token with nonce",
+			maxAge:   5 * time.Minute,
+			nonce:    "nonce",
+			issuedAt: now,
+			wantErr:  true,
+		},
+		{
+			name:    "missing iat",
+			maxAge:  5 * time.Minute,
+			wantErr: true,
+		},
+		{
+			name:     "token issued too long ago",
+			maxAge:   5 * time.Minute,
+			issuedAt: now.Add(-10 * time.Minute),
+			wantErr:  true,
+		},
+		{
+			name:     "token issued in the future",
+			maxAge:   5 * time.Minute,
+			issuedAt: now.Add(10 * time.Minute),
+			wantErr:  true,
+		},
+		{
+			name:     "token issued slightly in the future",
+			maxAge:   5 * time.Minute,
+			issuedAt: now.Add(30 * time.Second),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &OAuthClient{}
+			client.SetIdPInitiated(c.maxAge)
+			err := client.checkIdPInitiated(c.nonce, c.issuedAt, now)
+			assert.Equal(t, c.wantErr, err != nil)
+			if err != nil {
+				assert.ErrorIs(t, err, ErrInvalidIdPInitiatedToken)
+			}
+		})
+	}
+}
+
+func TestCheckAuthTime(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name    string
+		maxAge  int64
+		claims  jwt.MapClaims
+		wantErr bool
+	}{
+		{
+			name:   "no max age",
+			claims: jwt.MapClaims{"auth_time": float64(now.Add(-24 * time.Hour).Unix())},
+		},
+		{
+			name:   "no max age without auth_time",
+			claims: jwt.MapClaims{},
+		},
+		{
+			name:   "auth_time within max age",
+			maxAge: 300,
+			claims: jwt.MapClaims{"auth_time": float64(now.Add(-4 * time.Minute).Unix())},
+		},
+		{
+			name:   "auth_time as json number",
+			maxAge: 300,
+			claims: jwt.MapClaims{"auth_time": json.Number(strconv.FormatInt(now.Add(-time.Minute).Unix(), 10))},
+		},
+		{
+			name:    "stale auth_time",
+			maxAge:  300,
+			claims:  jwt.MapClaims{"auth_time": float64(now.Add(-10 * time.Minute).Unix())},
+			wantErr: true,
+		},
+		{
+			name:    "missing auth_time",
+			maxAge:  300,
+			claims:  jwt.MapClaims{},
+			wantErr: true,
+		},
+		{
+			name:    "invalid auth_time",
+			maxAge:  300,
+			claims:  jwt.MapClaims{"auth_time": "yesterday"},
+			wantErr: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &OAuthClient{sharedSSOConfig: &model.ProjectSSOConfig_Oidc{MaxAge: c.maxAge}}
+			err := client.checkAuthTime(c.claims, now)
+			assert.Equal(t, c.wantErr, err != nil)
+			if err != nil {
+				assert.ErrorIs(t, err, ErrReauthenticationRequired)
+			}
+		})
+	}
+}
+
+func TestCheckEmailVerified(t *testing.T) {
+	cases := []struct {
+		name     string
+		required bool
+		claims   jwt.MapClaims
+		wantErr  bool
+	}{
+		{
+			name:     "not required",
+			required: false,
+			claims:   jwt.MapClaims{},
+		},
+		{
+			name:     "verified",
+			required: true,
+			claims:   jwt.MapClaims{"email_verified": true},
+		},
+		{
+			name:     "verified as string",
+			required: true,
+			claims:   jwt.MapClaims{"email_verified": "true"},
+		},
+		{
+			name:     "not verified",
+			required: true,
+			claims:   jwt.MapClaims{"email_verified": false},
+			wantErr:  true,
+		},
+		{
+			name:     "missing claim",
+			required: true,
+			claims:   jwt.MapClaims{"email": "user@example.com"},
+			wantErr:  true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &OAuthClient{sharedSSOConfig: &model.ProjectSSOConfig_Oidc{RequireVerifiedEmail: c.required}}
+			err := client.checkEmailVerified(c.claims)
+			assert.Equal(t, c.wantErr, err != nil)
+			if err != nil {
+				assert.EqualError(t, err, "email not verified")
+				assert.True(t, errors.Is(err, ErrEmailNotVerified))
+			}
+		})
+	}
+}
+
+func TestCheckIssuer(t *testing.T) {
+	cases := []struct {
+		name    string
+		issuer  string
+		wantErr bool
+	}{
+		{
+			name:   "matched",
+			issuer: "https://issuer.example.com",
+		},
+		{
+			name:    "mismatched issuer",
+			issuer:  "https://evil.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "trailing slash",
+			issuer:  "https://issuer.example.com/",
+			wantErr: true,
+		},
+	}
+
+	client := &OAuthClient{
+		sharedSSOConfig: &model.ProjectSSOConfig_Oidc{Issuer: "https://issuer.example.com"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := client.checkIssuer(c.issuer)
+			assert.Equal(t, c.wantErr, err != nil)
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrInvalidIssuer))
+			}
+		})
+	}
+}
+
+func TestCheckAudience(t *testing.T) {
+	cases := []struct {
+		name             string
+		allowedAudiences []string
+		audiences        []string
+		azp              string
+		wantErr          bool
+	}{
+		{
+			name:      "client id",
+			audiences: []string{"client-id"},
+		},
+		{
+			name:      "wrong audience",
+			audiences: []string{"other-client"},
+			wantErr:   true,
+		},
+		{
+			name:      "no audience",
+			audiences: nil,
+			wantErr:   true,
+		},
+		{
+			name:      "multiple audiences with azp",
+			audiences: []string{"api", "client-id"},
+			azp:       "client-id",
+		},
+		{
+			name:      "multiple audiences with wrong azp",
+			audiences: []string{"api", "client-id"},
+			azp:       "other-client",
+			wantErr:   true,
+		},
+		{
+			name:             "allowed audience",
+			allowedAudiences: []string{"api"},
+			audiences:        []string{"api"},
+			azp:              "client-id",
+		},
+		{
+			name:             "not allowed audience",
+			allowedAudiences: []string{"api"},
+			audiences:        []string{"other-api"},
+			wantErr:          true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			client := &OAuthClient{
+				sharedSSOConfig: &model.ProjectSSOConfig_Oidc{
+					ClientId:         "client-id",
+					AllowedAudiences: c.allowedAudiences,
+				},
+			}
+			err := client.checkAudience(c.audiences, c.azp)
+			assert.Equal(t, c.wantErr, err != nil)
+			if err != nil {
+				assert.True(t, errors.Is(err, ErrInvalidAudience))
+			}
+		})
+	}
+}
+
+func TestFetchUserInfo(t *testing.T) {
+	var server *httptest.Server
+	var userInfoStatus int
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/userinfo":
+			if userInfoStatus != http.StatusOK {
+				w.WriteHeader(userInfoStatus)
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"sub":     "user",
+				"groups":  []string{"admin"},
+				"picture": "https://example.com/avatar.png",
+			})
+		default:
+			json.NewEncoder(w).Encode(map[string]string{
+				"issuer":            server.URL,
+				"userinfo_endpoint": server.URL + "/userinfo",
+			})
+		}
+	}))
+	defer server.Close()
+
+	provider, err := oidc.NewProvider(context.Background(), server.URL)
+	require.NoError(t, err)
+
+	cases := []struct {
+		name        string
+		sso         *model.ProjectSSOConfig_Oidc
+		status      int
+		wantEnabled bool
+		wantErr     bool
+	}{
+		{
+			name:        "disabled",
+			sso:         &model.ProjectSSOConfig_Oidc{},
+			status:      http.StatusOK,
+			wantEnabled: false,
+		},
+		{
+			name:        "enabled by flag",
+			sso:         &model.ProjectSSOConfig_Oidc{FetchUserinfo: true},
+			status:      http.StatusOK,
+			wantEnabled: true,
+		},
+		{
+			name:        "enabled by custom endpoint",
+			sso:         &model.ProjectSSOConfig_Oidc{UserInfoEndpoint: server.URL + "/userinfo"},
+			status:      http.StatusOK,
+			wantEnabled: true,
+		},
+		{
+			name:        "endpoint failure",
+			sso:         &model.ProjectSSOConfig_Oidc{FetchUserinfo: true},
+			status:      http.StatusInternalServerError,
+			wantEnabled: true,
+			wantErr:     true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			userInfoStatus = c.status
+			client := &OAuthClient{
+				Provider:        provider,
+				Token:           &oauth2.Token{AccessToken: "token"},
+				sharedSSOConfig: c.sso,
+			}
+			assert.Equal(t, c.wantEnabled, client.userInfoEnabled())
+			if !c.wantEnabled {
+				return
+			}
+
+			claims, err := client.fetchUserInfo(context.Background())
+			if c.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "https://example.com/avatar.png", claims["picture"])
+		})
+	}
+}
+
+func TestWithUserInfoError(t *testing.T) {
+	err := errors.New("no role found in claims")
+	assert.Equal(t, err, withUserInfoError(err, nil))
+
+	wrapped := withUserInfoError(err, errors.New("500 Internal Server Error"))
+	assert.ErrorIs(t, wrapped, err)
+	assert.Equal(t, "no role found in claims (failed to fetch user info: 500 Internal Server Error)", wrapped.Error())
+}
+
+func TestIsCodeExpired(t *testing.T) {
+	assert.True(t, isCodeExpired(&oauth2.RetrieveError{ErrorCode: "invalid_grant"}))
+	assert.True(t, isCodeExpired(&oauth2.RetrieveError{ErrorCode: "expired_code"}))
+	assert.False(t, isCodeExpired(&oauth2.RetrieveError{ErrorCode: "invalid_client"}))
+	assert.False(t, isCodeExpired(errors.New("connection refused")))
+}
+
+func TestNewOAuthClientSlowProvider(t *testing.T) {
+	done := make(chan struct{})
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"jwks_uri":%q}`,
+				server.URL, server.URL+"/auth", server.URL+"/token", server.URL+"/keys")
+		case "/token":
+			// The token endpoint never responds.
+			select {
+			case <-done:
+			case <-r.Context().Done():
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+	defer close(done)
+
+	client := oauthhttp.NewClient(oauthhttp.Timeouts{Connect: time.Second, Read: 50 * time.Millisecond, Total: 10 * time.Second}, nil)
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, client)
+	sso := &model.ProjectSSOConfig_Oidc{
+		ClientId:     "client-id",
+		ClientSecret: "client-secret",
+		Issuer:       server.URL,
+		RedirectUri:  "https://pipecd.example.com/auth/callback",
+	}
+
+	start := time.Now()
+	_, err := NewOAuthClient(ctx, sso, &model.Project{Id: "project"}, "code", nil, "")
+	require.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestNewOAuthClientPublicClient(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"jwks_uri":%q}`,
+				server.URL, server.URL+"/auth", server.URL+"/token", server.URL+"/keys")
+		case "/token":
+			// The public client sends its client id and the code verifier without the client authentication.
+			require.NoError(t, r.ParseForm())
+			_, _, hasBasicAuth := r.BasicAuth()
+			if hasBasicAuth || r.PostForm.Has("client_secret") || r.PostForm.Get("client_id") != "client-id" || r.PostForm.Get("code_verifier") != "verifier" {
+				w.WriteHeader(http.StatusUnauthorized)
+				fmt.Fprint(w, `{"error":"invalid_client"}`)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"access-token","token_type":"Bearer"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	sso := &model.ProjectSSOConfig_Oidc{
+		ClientId:    "client-id",
+		Issuer:      server.URL,
+		RedirectUri: "https://pipecd.example.com/auth/callback",
+		PkceEnabled: true,
+	}
+	c, err := NewOAuthClient(context.Background(), sso, &model.Project{Id: "project"}, "code", nil, "", oauth2.VerifierOption("verifier"))
+	require.NoError(t, err)
+	assert.Equal(t, "access-token", c.Token.AccessToken)
+
+	// The client without the secret must use PKCE.
+	sso.PkceEnabled = false
+	_, err = NewOAuthClient(context.Background(), sso, &model.Project{Id: "project"}, "code", nil, "")
+	assert.Error(t, err)
+}
+
+func TestAuthCodeURL(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"jwks_uri":%q}`,
+			server.URL, server.URL+"/auth", server.URL+"/token", server.URL+"/keys")
+	}))
+	defer server.Close()
+
+	sso := &model.ProjectSSOConfig_Oidc{
+		ClientId:    "client-id",
+		Issuer:      server.URL,
+		RedirectUri: "https://pipecd.example.com/auth/callback",
+	}
+	// The provider using the certificate of the private CA is discovered by the client trusting the CA.
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, server.Client())
+	got, err := AuthCodeURL(ctx, sso, "project", "state", oauth2.SetAuthURLParam("nonce", "nonce"))
+	require.NoError(t, err)
+	u, err := url.Parse(got)
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/auth", u.Scheme+"://"+u.Host+u.Path)
+	assert.Equal(t, "client-id", u.Query().Get("client_id"))
+	assert.Equal(t, model.StateWithProject("state", "project"), u.Query().Get("state"))
+	assert.Equal(t, "nonce", u.Query().Get("nonce"))
+
+	// The client not trusting the CA fails to discover the provider.
+	ctx = context.WithValue(context.Background(), oauth2.HTTPClient, oauthhttp.NewClient(oauthhttp.DefaultTimeouts, nil))
+	_, err = AuthCodeURL(ctx, &model.ProjectSSOConfig_Oidc{ClientId: "client-id", Issuer: server.URL + "/other"}, "project", "state")
+	assert.Error(t, err)
+}
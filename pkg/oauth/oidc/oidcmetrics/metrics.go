@@ -0,0 +1,44 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidcmetrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const fetchLabel = "fetch"
+
+var (
+	fetchRetryCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "oidc_fetch_retries_total",
+			Help: "Total number of retries of the fetches from the OIDC providers failed transiently, by the fetch, either discovery or jwks.",
+		},
+		[]string{fetchLabel},
+	)
+)
+
+func Register(r prometheus.Registerer) {
+	r.MustRegister(
+		fetchRetryCounter,
+	)
+}
+
+// IncFetchRetries increments the number of retries of the given fetch.
+func IncFetchRetries(fetch string) {
+	fetchRetryCounter.With(prometheus.Labels{
+		fetchLabel: fetch,
+	}).Inc()
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidcmetrics
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIncFetchRetries(t *testing.T) {
+	before := testutil.ToFloat64(fetchRetryCounter.WithLabelValues("test-inc-fetch-retries"))
+	IncFetchRetries("test-inc-fetch-retries")
+	IncFetchRetries("test-inc-fetch-retries")
+
+	assert.Equal(t, before+2, testutil.ToFloat64(fetchRetryCounter.WithLabelValues("test-inc-fetch-retries")))
+}
@@ -0,0 +1,60 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// ProviderMetadata is the part of the discovery document of an OpenID provider
+// used to check the SSO configuration.
+type ProviderMetadata struct {
+	Issuer  string
+	JWKSURL string
+}
+
+// FetchProviderMetadata fetches the discovery document of the given issuer bypassing the cache.
+func FetchProviderMetadata(ctx context.Context, issuer string, client *http.Client) (*ProviderMetadata, error) {
+	if err := sharedHostAllowlist.check(issuer); err != nil {
+		return nil, err
+	}
+	p, err := fetchDiscovery(ctx, issuer, client)
+	if err != nil {
+		return nil, err
+	}
+	return &ProviderMetadata{
+		Issuer:  p.Issuer,
+		JWKSURL: p.JWKSURL,
+	}, nil
+}
+
+// FetchKeyCount fetches the keys from the given JWKS uri bypassing the cache and returns the number of them.
+func FetchKeyCount(ctx context.Context, uri string, client *http.Client) (int, error) {
+	if err := sharedHostAllowlist.check(uri); err != nil {
+		return 0, err
+	}
+	s := &cachedKeySet{
+		uri:    uri,
+		now:    time.Now,
+		client: client,
+	}
+	keys, err := s.fetch(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(keys), nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"errors"
+
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+// RefreshOAuthClient creates a new oauth client for OIDC by the refresh token issued to the user at login,
+// which is used to look up the user again after login. The new ID token is always requested,
+// so the given refresh token must be issued with the offline_access scope.
+func RefreshOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Oidc,
+	project *model.Project,
+	refreshToken string,
+) (*OAuthClient, error) {
+	if refreshToken == "" {
+		return nil, errors.New("no refresh token issued at login")
+	}
+	if err := sso.ValidateScopes(); err != nil {
+		return nil, err
+	}
+	if err := sso.ValidateClientAuthentication(); err != nil {
+		return nil, err
+	}
+	if err := sso.ValidateExtraHeaders(); err != nil {
+		return nil, err
+	}
+	c := &OAuthClient{
+		project:         project,
+		sharedSSOConfig: sso,
+		refreshed:       true,
+	}
+	exchangeCtx, cfg, opts, err := c.setup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// The token request of the auth code is reused to send the client assertion as in NewDeviceOAuthClient.
+	// The empty code parameter is ignored by the token endpoint as an unrecognized parameter of this grant.
+	cfg.RedirectURL = ""
+	opts = append(opts,
+		oauth2.SetAuthURLParam("grant_type", "refresh_token"),
+		oauth2.SetAuthURLParam("refresh_token", refreshToken),
+	)
+	oauth2Token, err := cfg.Exchange(exchangeCtx, "", opts...)
+	if err != nil {
+		return nil, err
+	}
+	c.Token = oauth2Token
+
+	return c, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestRefreshOAuthClient(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer":%q,"authorization_endpoint":%q,"token_endpoint":%q,"jwks_uri":%q}`,
+				server.URL, server.URL+"/auth", server.URL+"/token", server.URL+"/keys")
+		case "/token":
+			require.NoError(t, r.ParseForm())
+			if r.PostForm.Get("grant_type") != "refresh_token" || r.PostForm.Get("refresh_token") != "refresh-token" || r.PostForm.Has("redirect_uri") {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"error":"invalid_grant"}`)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"new-access-token","token_type":"Bearer","id_token":"new-id-token"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	sso := &model.ProjectSSOConfig_Oidc{
+		ClientId:     "client-id",
+		ClientSecret: "client-secret",
+		Issuer:       server.URL,
+		RedirectUri:  "https://pipecd.example.com/auth/callback",
+	}
+	c, err := RefreshOAuthClient(context.Background(), sso, &model.Project{Id: "project"}, "refresh-token")
+	require.NoError(t, err)
+	assert.Equal(t, "new-access-token", c.Token.AccessToken)
+	assert.Equal(t, "new-id-token", c.Extra("id_token"))
+	// The refresh token is kept unless the provider rotates it.
+	assert.Equal(t, "refresh-token", c.Token.RefreshToken)
+	assert.True(t, c.refreshed)
+
+	_, err = RefreshOAuthClient(context.Background(), sso, &model.Project{Id: "project"}, "revoked-refresh-token")
+	var re *oauth2.RetrieveError
+	require.ErrorAs(t, err, &re)
+	assert.Equal(t, "invalid_grant", re.ErrorCode)
+
+	_, err = RefreshOAuthClient(context.Background(), sso, &model.Project{Id: "project"}, "")
+	assert.Error(t, err)
+}
@@ -0,0 +1,109 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/pipe-cd/pipecd/pkg/backoff"
+	"github.com/pipe-cd/pipecd/pkg/oauth/oidc/oidcmetrics"
+)
+
+const (
+	// fetchAttempts bounds the attempts to fetch the discovery document or the JWKS,
+	// so that the provider failing momentarily does not fail the logins outright.
+	// The waits between the attempts add 600ms at most, which is well within the deadline of the login,
+	// and the caller stops waiting for the fetch once its own deadline is exceeded anyway.
+	fetchAttempts    = 3
+	fetchBackoffBase = 200 * time.Millisecond
+	fetchBackoffMax  = 400 * time.Millisecond
+
+	fetchKindDiscovery = "discovery"
+	fetchKindJWKS      = "jwks"
+)
+
+// sharedLogger logs the retries of the fetches, which is set by SetLogger.
+var sharedLogger atomic.Pointer[zap.Logger]
+
+// SetLogger sets the logger of the fetches from the providers shared by all OIDC clients.
+// It should be called before handling any login.
+func SetLogger(logger *zap.Logger) {
+	sharedLogger.Store(logger.Named("oidc"))
+}
+
+func fetchLogger() *zap.Logger {
+	if l := sharedLogger.Load(); l != nil {
+		return l
+	}
+	return zap.NewNop()
+}
+
+// fetchStatusError is returned when the provider responds to the fetch with a status other than 200.
+type fetchStatusError struct {
+	status string
+	code   int
+	body   []byte
+}
+
+func (e *fetchStatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.status, e.body)
+}
+
+// retryFetch calls the given fetch of the given uri, retrying it with the exponential backoff with jitter
+// while it fails transiently, e.g. the provider responds 503. The given kind is either fetchKindDiscovery or fetchKindJWKS.
+func retryFetch(ctx context.Context, kind, uri string, fetch func() (interface{}, error)) (interface{}, error) {
+	retry := backoff.NewRetry(fetchAttempts, backoff.NewExponential(fetchBackoffBase, fetchBackoffMax))
+	return retry.Do(ctx, func() (interface{}, error) {
+		v, err := fetch()
+		if err == nil {
+			return v, nil
+		}
+		if !isTransientFetchError(ctx, err) {
+			return nil, backoff.NewError(err, false)
+		}
+		if retry.Calls() < fetchAttempts {
+			oidcmetrics.IncFetchRetries(kind)
+			fetchLogger().Debug("retrying transiently failed fetch",
+				zap.String("fetch", kind),
+				zap.String("uri", uri),
+				zap.Int("attempt", retry.Calls()),
+				zap.Error(err),
+			)
+		}
+		return nil, err
+	})
+}
+
+// isTransientFetchError reports whether the fetch failed with the given error is worth retrying,
+// which are the failures of the connection, and the 5xx and 429 statuses.
+func isTransientFetchError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var statusErr *fetchStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code >= http.StatusInternalServerError || statusErr.code == http.StatusTooManyRequests
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
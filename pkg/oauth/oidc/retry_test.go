@@ -0,0 +1,97 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package oidc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlakyServer returns a server responding the given statuses in order, then 200 with the given body.
+func newFlakyServer(t *testing.T, body string, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	var fetched atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(fetched.Add(1))
+		if n <= len(statuses) {
+			w.WriteHeader(statuses[n-1])
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server, &fetched
+}
+
+func TestDiscoveryCacheRetry(t *testing.T) {
+	t.Parallel()
+	const body = `{"issuer": "https://issuer.example.com", "jwks_uri": "https://issuer.example.com/keys"}`
+
+	server, fetched := newFlakyServer(t, body, http.StatusServiceUnavailable, http.StatusBadGateway)
+	doc, err := newDiscoveryCache(time.Minute).discover(context.Background(), server.URL, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "https://issuer.example.com/keys", doc.JWKSURL)
+	assert.Equal(t, int32(3), fetched.Load())
+
+	// The attempts are bounded.
+	server, fetched = newFlakyServer(t, body, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable)
+	_, err = newDiscoveryCache(time.Minute).discover(context.Background(), server.URL, nil)
+	assert.ErrorContains(t, err, "503")
+	assert.Equal(t, int32(fetchAttempts), fetched.Load())
+
+	// The non-transient failure is not retried.
+	server, fetched = newFlakyServer(t, body, http.StatusNotFound)
+	_, err = newDiscoveryCache(time.Minute).discover(context.Background(), server.URL, nil)
+	assert.ErrorContains(t, err, "404")
+	assert.Equal(t, int32(1), fetched.Load())
+}
+
+func TestCachedKeySetRetry(t *testing.T) {
+	t.Parallel()
+	server, fetched := newFlakyServer(t, `{"keys": []}`, http.StatusTooManyRequests)
+	ks := &cachedKeySet{uri: server.URL, ttl: time.Minute, now: time.Now}
+
+	_, err := ks.refresh(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), fetched.Load())
+}
+
+func TestIsTransientFetchError(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	_, err := http.Get(server.URL)
+	require.Error(t, err)
+
+	assert.True(t, isTransientFetchError(ctx, err))
+	assert.True(t, isTransientFetchError(ctx, &fetchStatusError{code: http.StatusServiceUnavailable}))
+	assert.True(t, isTransientFetchError(ctx, &fetchStatusError{code: http.StatusTooManyRequests}))
+	assert.False(t, isTransientFetchError(ctx, &fetchStatusError{code: http.StatusUnauthorized}))
+	assert.False(t, isTransientFetchError(ctx, errors.New("failed to decode")))
+
+	// Nothing is retried once the deadline is exceeded.
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.False(t, isTransientFetchError(canceled, err))
+}
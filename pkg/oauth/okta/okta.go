@@ -0,0 +1,345 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package okta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	defaultGroupsClaim = "groups"
+	// groupsPageLimit is the maximum number of groups returned by a page of the Groups API.
+	groupsPageLimit = 200
+	// maxGroupsPages limits the pages to follow in case the API keeps returning the next link.
+	maxGroupsPages = 50
+)
+
+// OAuthClient is a oauth client for Okta.
+type OAuthClient struct {
+	*oauth2.Token
+
+	sso        *model.ProjectSSOConfig_Okta
+	project    *model.Project
+	provider   *oidc.Provider
+	httpClient *http.Client
+}
+
+type claims struct {
+	Subject           string `json:"sub"`
+	PreferredUsername string `json:"preferred_username"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Picture           string `json:"picture"`
+}
+
+// NewOAuthClient creates a new oauth client for Okta.
+// The configured redirect URI must be in the given allowed list.
+func NewOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Okta,
+	project *model.Project,
+	code string,
+	allowedRedirectURIs []string,
+) (*OAuthClient, error) {
+	if err := model.CheckRedirectURI(sso.RedirectUri, allowedRedirectURIs); err != nil {
+		return nil, err
+	}
+	return newOAuthClient(ctx, sso, project, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.Exchange(ctx, code)
+	})
+}
+
+// RefreshOAuthClient creates a new oauth client for Okta by the refresh token issued to the user at login,
+// which is used to look up the user again after login. The new ID token is always requested.
+func RefreshOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Okta,
+	project *model.Project,
+	refreshToken string,
+) (*OAuthClient, error) {
+	return newOAuthClient(ctx, sso, project, func(ctx context.Context, cfg *oauth2.Config) (*oauth2.Token, error) {
+		return cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}).Token()
+	})
+}
+
+// newOAuthClient creates a new oauth client for Okta by the token obtained by the given function.
+func newOAuthClient(ctx context.Context,
+	sso *model.ProjectSSOConfig_Okta,
+	project *model.Project,
+	obtainToken func(context.Context, *oauth2.Config) (*oauth2.Token, error),
+) (*OAuthClient, error) {
+	c := &OAuthClient{
+		sso:        sso,
+		project:    project,
+		httpClient: http.DefaultClient,
+	}
+
+	if sso.ProxyUrl != "" {
+		proxyURL, err := url.Parse(sso.ProxyUrl)
+		if err != nil {
+			return nil, err
+		}
+
+		t := http.DefaultTransport.(*http.Transport).Clone()
+		t.Proxy = http.ProxyURL(proxyURL)
+		c.httpClient = &http.Client{Transport: t}
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, c.httpClient)
+	}
+
+	provider, err := oidc.NewProvider(ctx, sso.Issuer())
+	if err != nil {
+		return nil, err
+	}
+	c.provider = provider
+
+	cfg := &oauth2.Config{
+		ClientID:     sso.ClientId,
+		ClientSecret: sso.ClientSecret,
+		RedirectURL:  sso.RedirectUri,
+		Endpoint:     sso.Endpoint(),
+		Scopes:       sso.AllScopes(),
+	}
+	token, err := obtainToken(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.Token = token
+
+	return c, nil
+}
+
+// GetUser returns a user model.
+func (c *OAuthClient) GetUser(ctx context.Context) (*model.User, error) {
+	idTokenRAW, ok := c.Extra("id_token").(string)
+	if !ok {
+		return nil, fmt.Errorf("no id_token in oauth2 token")
+	}
+	idToken, err := c.provider.Verifier(&oidc.Config{ClientID: c.sso.ClientId}).Verify(ctx, idTokenRAW)
+	if err != nil {
+		return nil, err
+	}
+
+	var cl claims
+	if err := idToken.Claims(&cl); err != nil {
+		return nil, err
+	}
+	var all map[string]interface{}
+	if err := idToken.Claims(&all); err != nil {
+		return nil, err
+	}
+
+	username := cl.PreferredUsername
+	if username == "" {
+		username = cl.Email
+	}
+	if username == "" {
+		return nil, fmt.Errorf("no username found in claims")
+	}
+
+	groupsClaim := c.sso.GroupsClaim
+	if groupsClaim == "" {
+		groupsClaim = defaultGroupsClaim
+	}
+	groups, ok := normalizeGroups(all[groupsClaim])
+	if !ok {
+		// Okta omits the groups claim when the user belongs to too many groups,
+		// so they are fetched from the Groups API instead.
+		groups, err = c.fetchGroups(ctx, cl.Subject)
+		if err != nil {
+			return nil, fmt.Errorf("no %s claim in id_token and unable to fetch the groups: %w", groupsClaim, err)
+		}
+	}
+
+	verifiedEmail := ""
+	if cl.EmailVerified {
+		verifiedEmail = cl.Email
+	}
+	role, err := c.decideRole(username, verifiedEmail, groups)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.User{
+		Username:      username,
+		AvatarUrl:     cl.Picture,
+		Role:          role,
+		Email:         cl.Email,
+		EmailVerified: cl.EmailVerified,
+		Groups:        c.project.MatchedUserGroups(groups),
+	}, nil
+}
+
+// normalizeGroups returns the group names in the groups claim,
+// which is a list of names or a single name depending on the claim settings of the app.
+func normalizeGroups(v interface{}) ([]string, bool) {
+	var groups []string
+	switch v := v.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				groups = append(groups, s)
+			}
+		}
+	case []string:
+		groups = append(groups, v...)
+	case string:
+		groups = append(groups, v)
+	default:
+		return nil, false
+	}
+
+	normalized := make([]string, 0, len(groups))
+	for _, g := range groups {
+		if g = strings.TrimSpace(g); g != "" {
+			normalized = append(normalized, g)
+		}
+	}
+	return normalized, true
+}
+
+type group struct {
+	Profile struct {
+		Name string `json:"name"`
+	} `json:"profile"`
+}
+
+// fetchGroups returns the names of all groups of the user by following the pages of the Groups API.
+// The access token must be granted the okta.users.read scope by the org authorization server.
+func (c *OAuthClient) fetchGroups(ctx context.Context, userID string) ([]string, error) {
+	if userID == "" {
+		return nil, fmt.Errorf("missing sub in id_token")
+	}
+	domain, err := url.Parse(c.sso.Domain)
+	if err != nil {
+		return nil, err
+	}
+	next := fmt.Sprintf("%s/api/v1/users/%s/groups?limit=%d", strings.TrimSuffix(c.sso.Domain, "/"), url.PathEscape(userID), groupsPageLimit)
+
+	var names []string
+	for i := 0; next != ""; i++ {
+		if i == maxGroupsPages {
+			return nil, fmt.Errorf("too many pages of groups")
+		}
+		u, err := url.Parse(next)
+		if err != nil {
+			return nil, err
+		}
+		// Never send the access token to other hosts.
+		if u.Scheme != domain.Scheme || u.Host != domain.Host {
+			return nil, fmt.Errorf("unexpected next page %q", next)
+		}
+		groups, link, err := c.fetchGroupsPage(ctx, next)
+		if err != nil {
+			return nil, err
+		}
+		for _, g := range groups {
+			if g.Profile.Name != "" {
+				names = append(names, g.Profile.Name)
+			}
+		}
+		next = nextLink(link)
+	}
+	return names, nil
+}
+
+func (c *OAuthClient) fetchGroupsPage(ctx context.Context, u string) ([]group, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.AccessToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, "", fmt.Errorf("%s: %s", resp.Status, body)
+	}
+	var groups []group
+	if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
+		return nil, "", err
+	}
+	return groups, strings.Join(resp.Header.Values("Link"), ","), nil
+}
+
+// nextLink returns the URL of the next page in the Link header, e.g. `<https://example.okta.com/...>; rel="next"`.
+func nextLink(header string) string {
+	for _, link := range strings.Split(header, ",") {
+		parts := strings.Split(link, ";")
+		if len(parts) < 2 {
+			continue
+		}
+		target := strings.TrimSpace(parts[0])
+		if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, p := range parts[1:] {
+			if strings.ReplaceAll(strings.TrimSpace(p), `"`, "") == "rel=next" {
+				return strings.TrimSuffix(strings.TrimPrefix(target, "<"), ">")
+			}
+		}
+	}
+	return ""
+}
+
+func (c *OAuthClient) decideRole(user, verifiedEmail string, groups []string) (role *model.Role, err error) {
+	role = &model.Role{
+		ProjectId:        c.project.Id,
+		ProjectRbacRoles: make([]string, 0, len(groups)),
+	}
+	userGroups := c.project.UserGroups
+	roles := make(map[string]string, len(userGroups))
+	for _, g := range userGroups {
+		roles[g.SsoGroup] = g.Role
+	}
+
+	for _, g := range groups {
+		if v, ok := roles[g]; ok {
+			role.ProjectRbacRoles = append(role.ProjectRbacRoles, v)
+		}
+	}
+
+	if len(role.ProjectRbacRoles) != 0 {
+		return
+	}
+
+	// In case the user is not in any of the groups,
+	// assign the roles mapped to the domain of the verified email.
+	if roles := c.project.RolesByEmailDomain(verifiedEmail); len(roles) > 0 {
+		role.ProjectRbacRoles = roles
+		return
+	}
+
+	if c.project.AssignStrayRoles(role) {
+		return
+	}
+
+	err = fmt.Errorf("user (%s) not found in any of the %d project groups", user, len(groups))
+	return
+}
@@ -0,0 +1,184 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package okta
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/oauth2"
+
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+func TestNormalizeGroups(t *testing.T) {
+	cases := []struct {
+		name   string
+		claim  interface{}
+		want   []string
+		wantOK bool
+	}{
+		{
+			name:  "missing claim",
+			claim: nil,
+		},
+		{
+			name:   "list of names",
+			claim:  []interface{}{"admins", " dev ", "", 1},
+			want:   []string{"admins", "dev"},
+			wantOK: true,
+		},
+		{
+			name:   "single name",
+			claim:  "admins",
+			want:   []string{"admins"},
+			wantOK: true,
+		},
+		{
+			name:   "empty list",
+			claim:  []interface{}{},
+			want:   []string{},
+			wantOK: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := normalizeGroups(tc.claim)
+			assert.Equal(t, tc.wantOK, ok)
+			if tc.wantOK {
+				assert.Equal(t, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestNextLink(t *testing.T) {
+	cases := []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			name: "no header",
+		},
+		{
+			name:   "self only",
+			header: `<https://example.okta.com/api/v1/users/u/groups?limit=200>; rel="self"`,
+		},
+		{
+			name:   "self and next",
+			header: `<https://example.okta.com/api/v1/users/u/groups?limit=200>; rel="self",<https://example.okta.com/api/v1/users/u/groups?after=g2&limit=200>; rel="next"`,
+			want:   "https://example.okta.com/api/v1/users/u/groups?after=g2&limit=200",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, nextLink(tc.header))
+		})
+	}
+}
+
+func TestFetchGroups(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("after") {
+		case "":
+			w.Header().Add("Link", fmt.Sprintf(`<%s/api/v1/users/u1/groups?after=g1&limit=200>; rel="next"`, server.URL))
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"profile": map[string]string{"name": "admins"}},
+			})
+		case "g1":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"profile": map[string]string{"name": "dev"}},
+			})
+		}
+	}))
+	defer server.Close()
+
+	c := &OAuthClient{
+		Token:      &oauth2.Token{AccessToken: "access-token"},
+		sso:        &model.ProjectSSOConfig_Okta{Domain: server.URL},
+		httpClient: server.Client(),
+	}
+	groups, err := c.fetchGroups(t.Context(), "u1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admins", "dev"}, groups)
+
+	_, err = c.fetchGroups(t.Context(), "")
+	assert.Error(t, err)
+
+	c.Token = &oauth2.Token{AccessToken: "invalid"}
+	_, err = c.fetchGroups(t.Context(), "u1")
+	assert.Error(t, err)
+}
+
+func TestFetchGroups_OtherHost(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", `<https://attacker.example.com/groups>; rel="next"`)
+		json.NewEncoder(w).Encode([]map[string]interface{}{})
+	}))
+	defer server.Close()
+
+	c := &OAuthClient{
+		Token:      &oauth2.Token{AccessToken: "access-token"},
+		sso:        &model.ProjectSSOConfig_Okta{Domain: server.URL},
+		httpClient: server.Client(),
+	}
+	_, err := c.fetchGroups(t.Context(), "u1")
+	assert.Error(t, err)
+}
+
+func TestDecideRole(t *testing.T) {
+	project := &model.Project{
+		Id: "project",
+		UserGroups: []*model.ProjectUserGroup{
+			{SsoGroup: "admins", Role: model.BuiltinRBACRoleAdmin.String()},
+			{SsoGroup: "dev", Role: model.BuiltinRBACRoleEditor.String()},
+		},
+	}
+	c := &OAuthClient{project: project}
+
+	role, err := c.decideRole("user", "", []string{"dev", "other"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.BuiltinRBACRoleEditor.String()}, role.ProjectRbacRoles)
+
+	_, err = c.decideRole("user", "", []string{"other"})
+	assert.Error(t, err)
+
+	project.EmailDomainRoles = []*model.ProjectEmailDomainRole{
+		{Domain: "example.com", Role: model.BuiltinRBACRoleEditor.String()},
+	}
+	role, err = c.decideRole("user", "user@example.com", []string{"other"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.BuiltinRBACRoleEditor.String()}, role.ProjectRbacRoles)
+
+	_, err = c.decideRole("user", "", []string{"other"})
+	assert.Error(t, err)
+	project.EmailDomainRoles = nil
+
+	project.AllowStrayAsViewer = true
+	role, err = c.decideRole("user", "", []string{"other"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.BuiltinRBACRoleViewer.String()}, role.ProjectRbacRoles)
+}
@@ -0,0 +1,339 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saml
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/beevik/etree"
+	crewsaml "github.com/crewjam/saml"
+	"github.com/crewjam/saml/samlsp"
+
+	"github.com/pipe-cd/pipecd/pkg/cache"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	defaultGroupsAttribute = "groups"
+	responseFormKey        = "SAMLResponse"
+	assertionKeyPrefix     = "saml-assertion:"
+	metadataFetchTimeout   = 10 * time.Second
+)
+
+var (
+	// ErrInvalidResponse is returned when the SAMLResponse is malformed,
+	// not signed by the identity provider or its conditions are not met.
+	ErrInvalidResponse = errors.New("invalid SAML response")
+	// ErrReplayedAssertion is returned when the assertion has already been used to log in.
+	ErrReplayedAssertion = errors.New("SAML assertion has already been used")
+)
+
+// Client logs users in as the SAML service provider.
+type Client struct {
+	sso *model.ProjectSSOConfig_Saml
+	sp  *crewsaml.ServiceProvider
+	// assertions records the IDs of the consumed assertions to reject replaying them.
+	assertions cache.Cache
+}
+
+// NewClient returns a client for the given SAML configuration.
+// The metadata of the identity provider is fetched if it is given by the URL.
+// The given cache must keep the entries longer than the assertions are valid.
+func NewClient(ctx context.Context, sso *model.ProjectSSOConfig_Saml, acsURL string, assertions cache.Cache) (*Client, error) {
+	acs, err := url.Parse(acsURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SAML ACS url: %w", err)
+	}
+	metadata, err := loadIDPMetadata(ctx, sso)
+	if err != nil {
+		return nil, err
+	}
+	sp := &crewsaml.ServiceProvider{
+		EntityID:    sso.EntityId,
+		AcsURL:      *acs,
+		IDPMetadata: metadata,
+		// The IdP-initiated login is not supported since the response
+		// cannot be bound to the request made by the same browser.
+		AllowIDPInitiated: false,
+	}
+	if sso.Certificate != "" {
+		key, cert, err := parseKeyPair(sso.PrivateKey, sso.Certificate)
+		if err != nil {
+			return nil, err
+		}
+		sp.Key = key
+		sp.Certificate = cert
+		sp.SignatureMethod = "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"
+	}
+	return &Client{
+		sso:        sso,
+		sp:         sp,
+		assertions: assertions,
+	}, nil
+}
+
+// AuthnRequestURL returns the address of the identity provider to redirect the user to
+// along with the ID of the authentication request which must be given back while parsing the response.
+func (c *Client) AuthnRequestURL(relayState string) (string, string, error) {
+	req, err := c.sp.MakeAuthenticationRequest(
+		c.sp.GetSSOBindingLocation(crewsaml.HTTPRedirectBinding),
+		crewsaml.HTTPRedirectBinding,
+		crewsaml.HTTPPostBinding,
+	)
+	if err != nil {
+		return "", "", err
+	}
+	u, err := req.Redirect(relayState, c.sp)
+	if err != nil {
+		return "", "", err
+	}
+	return u.String(), req.ID, nil
+}
+
+// Authenticate validates the SAMLResponse posted to the ACS endpoint in response to the
+// given authentication request and returns the user whose roles are decided from its groups.
+func (c *Client) Authenticate(r *http.Request, project *model.Project, requestID string) (*model.User, error) {
+	raw, err := base64.StdEncoding.DecodeString(r.PostFormValue(responseFormKey))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if err := checkSingleAssertion(raw); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	// The signature, issuer, recipient, InResponseTo and the time conditions are validated here.
+	assertion, err := c.sp.ParseXMLResponse(raw, []string{requestID})
+	if err != nil {
+		var ire *crewsaml.InvalidResponseError
+		if errors.As(err, &ire) {
+			err = ire.PrivateErr
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if err := checkAudience(assertion, c.sso.EntityId); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	if err := c.consume(assertion); err != nil {
+		return nil, err
+	}
+
+	username, err := c.username(assertion)
+	if err != nil {
+		return nil, err
+	}
+	groupsAttr := c.sso.GroupsAttribute
+	if groupsAttr == "" {
+		groupsAttr = defaultGroupsAttribute
+	}
+	groups := attributeValues(assertion, groupsAttr)
+	role, err := decideRole(project, username, groups)
+	if err != nil {
+		return nil, err
+	}
+	user := &model.User{
+		Username: username,
+		Role:     role,
+		Groups:   project.MatchedUserGroups(groups),
+	}
+	if c.sso.AvatarUrlAttribute != "" {
+		if v := attributeValues(assertion, c.sso.AvatarUrlAttribute); len(v) > 0 {
+			user.AvatarUrl = v[0]
+		}
+	}
+	return user, nil
+}
+
+// consume records the assertion as used and fails if it has been used already.
+func (c *Client) consume(assertion *crewsaml.Assertion) error {
+	if assertion.ID == "" {
+		return fmt.Errorf("%w: missing assertion ID", ErrInvalidResponse)
+	}
+	key := assertionKeyPrefix + assertion.ID
+	_, err := c.assertions.Get(key)
+	if err == nil {
+		return ErrReplayedAssertion
+	}
+	if !errors.Is(err, cache.ErrNotFound) {
+		return fmt.Errorf("unable to check the SAML assertion: %w", err)
+	}
+	if err := c.assertions.Put(key, time.Now().Unix()); err != nil {
+		return fmt.Errorf("unable to record the SAML assertion: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) username(assertion *crewsaml.Assertion) (string, error) {
+	if attr := c.sso.UsernameAttribute; attr != "" {
+		if v := attributeValues(assertion, attr); len(v) > 0 && v[0] != "" {
+			return v[0], nil
+		}
+		return "", fmt.Errorf("missing attribute %q for the username in the SAML assertion", attr)
+	}
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || assertion.Subject.NameID.Value == "" {
+		return "", fmt.Errorf("missing NameID in the SAML assertion")
+	}
+	return assertion.Subject.NameID.Value, nil
+}
+
+// checkSingleAssertion rejects the response containing more than one assertion.
+// Such responses are used in the signature wrapping attacks where a forged assertion
+// is placed next to the signed one hoping that the unsigned one will be read.
+func checkSingleAssertion(raw []byte) error {
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(raw); err != nil {
+		return err
+	}
+	root := doc.Root()
+	if root == nil || root.Tag != "Response" {
+		return fmt.Errorf("root element is not a Response")
+	}
+	if n := countAssertions(root); n != 1 {
+		return fmt.Errorf("response must contain exactly one assertion, got %d", n)
+	}
+	return nil
+}
+
+func countAssertions(el *etree.Element) int {
+	n := 0
+	for _, child := range el.ChildElements() {
+		if child.Tag == "Assertion" || child.Tag == "EncryptedAssertion" {
+			n++
+		}
+		n += countAssertions(child)
+	}
+	return n
+}
+
+// checkAudience requires the assertion to be restricted to the given audience.
+// The library accepts the assertions without any audience restriction.
+func checkAudience(assertion *crewsaml.Assertion, audience string) error {
+	if assertion.Conditions == nil {
+		return fmt.Errorf("missing conditions in the assertion")
+	}
+	for _, r := range assertion.Conditions.AudienceRestrictions {
+		if r.Audience.Value == audience {
+			return nil
+		}
+	}
+	return fmt.Errorf("assertion is not restricted to the audience %q", audience)
+}
+
+// attributeValues returns the values of the attribute matching the given name or friendly name.
+func attributeValues(assertion *crewsaml.Assertion, name string) []string {
+	var values []string
+	for _, s := range assertion.AttributeStatements {
+		for _, attr := range s.Attributes {
+			if attr.Name != name && attr.FriendlyName != name {
+				continue
+			}
+			for _, v := range attr.Values {
+				values = append(values, v.Value)
+			}
+		}
+	}
+	return values
+}
+
+func decideRole(project *model.Project, user string, groups []string) (*model.Role, error) {
+	role := &model.Role{
+		ProjectId:        project.Id,
+		ProjectRbacRoles: make([]string, 0, len(groups)),
+	}
+	roles := make(map[string]string, len(project.UserGroups))
+	for _, g := range project.UserGroups {
+		roles[g.SsoGroup] = g.Role
+	}
+
+	for _, g := range groups {
+		if v, ok := roles[g]; ok {
+			role.ProjectRbacRoles = append(role.ProjectRbacRoles, v)
+		}
+	}
+	if len(role.ProjectRbacRoles) != 0 {
+		return role, nil
+	}
+
+	if project.AssignStrayRoles(role) {
+		return role, nil
+	}
+	return nil, fmt.Errorf("user (%s) not found in any of the %d project groups", user, len(groups))
+}
+
+func loadIDPMetadata(ctx context.Context, sso *model.ProjectSSOConfig_Saml) (*crewsaml.EntityDescriptor, error) {
+	if sso.IdpMetadata != "" {
+		md, err := samlsp.ParseMetadata([]byte(sso.IdpMetadata))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SAML IdP metadata: %w", err)
+		}
+		return md, nil
+	}
+	if sso.IdpMetadataUrl == "" {
+		return nil, fmt.Errorf("either idp_metadata or idp_metadata_url must be set")
+	}
+	u, err := url.Parse(sso.IdpMetadataUrl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SAML IdP metadata url: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, metadataFetchTimeout)
+	defer cancel()
+	md, err := samlsp.FetchMetadata(ctx, http.DefaultClient, *u)
+	if err != nil {
+		return nil, fmt.Errorf("unable to fetch SAML IdP metadata: %w", err)
+	}
+	return md, nil
+}
+
+func parseKeyPair(keyPEM, certPEM string) (*rsa.PrivateKey, *x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return nil, nil, fmt.Errorf("invalid SAML certificate: no PEM data found")
+	}
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid SAML certificate: %w", err)
+	}
+
+	block, _ = pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, nil, fmt.Errorf("invalid SAML private key: no PEM data found")
+	}
+	var key *rsa.PrivateKey
+	if k, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		key = k
+	} else {
+		k, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid SAML private key: %w", err)
+		}
+		rk, ok := k.(*rsa.PrivateKey)
+		if !ok {
+			return nil, nil, fmt.Errorf("invalid SAML private key: only RSA keys are supported")
+		}
+		key = rk
+	}
+	if pub, ok := cert.PublicKey.(*rsa.PublicKey); !ok || !bytes.Equal(pub.N.Bytes(), key.N.Bytes()) {
+		return nil, nil, fmt.Errorf("SAML certificate does not match the private key")
+	}
+	return key, cert, nil
+}
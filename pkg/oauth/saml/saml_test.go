@@ -0,0 +1,284 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package saml
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/xml"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	crewsaml "github.com/crewjam/saml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/cache/memorycache"
+	"github.com/pipe-cd/pipecd/pkg/model"
+)
+
+const (
+	testEntityID = "https://pipecd.example.com"
+	testACSURL   = "https://pipecd.example.com/auth/saml/acs"
+)
+
+func newTestIDP(t *testing.T) *crewsaml.IdentityProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	metadataURL, _ := url.Parse("https://idp.example.com/metadata")
+	ssoURL, _ := url.Parse("https://idp.example.com/sso")
+	return &crewsaml.IdentityProvider{
+		Key:         key,
+		Certificate: cert,
+		MetadataURL: *metadataURL,
+		SSOURL:      *ssoURL,
+	}
+}
+
+func newTestClient(t *testing.T, idp *crewsaml.IdentityProvider) *Client {
+	t.Helper()
+	metadata, err := xml.Marshal(idp.Metadata())
+	require.NoError(t, err)
+	c, err := NewClient(context.Background(), &model.ProjectSSOConfig_Saml{
+		IdpMetadata:        string(metadata),
+		EntityId:           testEntityID,
+		AvatarUrlAttribute: "avatar",
+	}, testACSURL, memorycache.NewCache())
+	require.NoError(t, err)
+	return c
+}
+
+func newTestAssertion(idp *crewsaml.IdentityProvider, requestID string) *crewsaml.Assertion {
+	now := time.Now()
+	return &crewsaml.Assertion{
+		ID:           "assertion-1",
+		IssueInstant: now,
+		Version:      "2.0",
+		Issuer:       crewsaml.Issuer{Value: idp.MetadataURL.String()},
+		Subject: &crewsaml.Subject{
+			NameID: &crewsaml.NameID{Value: "alice"},
+			SubjectConfirmations: []crewsaml.SubjectConfirmation{{
+				Method: "urn:oasis:names:tc:SAML:2.0:cm:bearer",
+				SubjectConfirmationData: &crewsaml.SubjectConfirmationData{
+					InResponseTo: requestID,
+					NotOnOrAfter: now.Add(time.Minute),
+					Recipient:    testACSURL,
+				},
+			}},
+		},
+		Conditions: &crewsaml.Conditions{
+			NotBefore:            now.Add(-time.Minute),
+			NotOnOrAfter:         now.Add(time.Minute),
+			AudienceRestrictions: []crewsaml.AudienceRestriction{{Audience: crewsaml.Audience{Value: testEntityID}}},
+		},
+		AttributeStatements: []crewsaml.AttributeStatement{{
+			Attributes: []crewsaml.Attribute{
+				{Name: "groups", Values: []crewsaml.AttributeValue{{Value: "dev"}, {Value: "ops"}}},
+				{Name: "avatar", Values: []crewsaml.AttributeValue{{Value: "https://example.com/alice.png"}}},
+			},
+		}},
+	}
+}
+
+// makeResponse returns the SAMLResponse signed by the identity provider.
+func makeResponse(t *testing.T, idp *crewsaml.IdentityProvider, requestID string, assertion *crewsaml.Assertion) *etree.Document {
+	t.Helper()
+	req := &crewsaml.IdpAuthnRequest{
+		IDP:             idp,
+		Request:         crewsaml.AuthnRequest{ID: requestID},
+		ACSEndpoint:     &crewsaml.IndexedEndpoint{Location: testACSURL},
+		SPSSODescriptor: &crewsaml.SPSSODescriptor{},
+		Assertion:       assertion,
+		Now:             time.Now(),
+	}
+	require.NoError(t, req.MakeResponse())
+	doc := etree.NewDocument()
+	doc.SetRoot(req.ResponseEl)
+	return doc
+}
+
+func postRequest(t *testing.T, doc *etree.Document) *http.Request {
+	t.Helper()
+	raw, err := doc.WriteToBytes()
+	require.NoError(t, err)
+	form := url.Values{responseFormKey: {base64.StdEncoding.EncodeToString(raw)}}
+	r := httptest.NewRequest(http.MethodPost, testACSURL, strings.NewReader(form.Encode()))
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return r
+}
+
+func TestAuthenticate(t *testing.T) {
+	idp := newTestIDP(t)
+	project := &model.Project{
+		Id: "project",
+		UserGroups: []*model.ProjectUserGroup{
+			{SsoGroup: "dev", Role: "Editor"},
+		},
+	}
+
+	testcases := []struct {
+		name      string
+		requestID string
+		modify    func(a *crewsaml.Assertion)
+		wantErr   error
+	}{
+		{
+			name:      "valid",
+			requestID: "request-1",
+		},
+		{
+			name:      "wrong request id",
+			requestID: "other",
+			wantErr:   ErrInvalidResponse,
+		},
+		{
+			name:      "wrong audience",
+			requestID: "request-1",
+			modify: func(a *crewsaml.Assertion) {
+				a.Conditions.AudienceRestrictions[0].Audience.Value = "https://other.example.com"
+			},
+			wantErr: ErrInvalidResponse,
+		},
+		{
+			name:      "missing audience",
+			requestID: "request-1",
+			modify: func(a *crewsaml.Assertion) {
+				a.Conditions.AudienceRestrictions = nil
+			},
+			wantErr: ErrInvalidResponse,
+		},
+		{
+			name:      "expired",
+			requestID: "request-1",
+			modify: func(a *crewsaml.Assertion) {
+				a.Conditions.NotOnOrAfter = time.Now().Add(-time.Hour)
+			},
+			wantErr: ErrInvalidResponse,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := newTestClient(t, idp)
+			a := newTestAssertion(idp, "request-1")
+			if tc.modify != nil {
+				tc.modify(a)
+			}
+			r := postRequest(t, makeResponse(t, idp, "request-1", a))
+
+			user, err := c.Authenticate(r, project, tc.requestID)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "alice", user.Username)
+			assert.Equal(t, "https://example.com/alice.png", user.AvatarUrl)
+			assert.Equal(t, []string{"Editor"}, user.Role.ProjectRbacRoles)
+		})
+	}
+}
+
+func TestAuthenticateRejectsReplay(t *testing.T) {
+	idp := newTestIDP(t)
+	c := newTestClient(t, idp)
+	project := &model.Project{Id: "project", AllowStrayAsViewer: true}
+	doc := makeResponse(t, idp, "request-1", newTestAssertion(idp, "request-1"))
+
+	_, err := c.Authenticate(postRequest(t, doc), project, "request-1")
+	require.NoError(t, err)
+	_, err = c.Authenticate(postRequest(t, doc), project, "request-1")
+	assert.ErrorIs(t, err, ErrReplayedAssertion)
+}
+
+func TestAuthenticateRejectsSignatureWrapping(t *testing.T) {
+	idp := newTestIDP(t)
+	c := newTestClient(t, idp)
+	project := &model.Project{Id: "project", AllowStrayAsViewer: true}
+	doc := makeResponse(t, idp, "request-1", newTestAssertion(idp, "request-1"))
+
+	// Put a forged assertion for another user before the signed one.
+	forged := newTestAssertion(idp, "request-1")
+	forged.ID = "forged"
+	forged.Subject.NameID.Value = "admin"
+	doc.Root().InsertChildAt(0, forged.Element())
+
+	_, err := c.Authenticate(postRequest(t, doc), project, "request-1")
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestAuthenticateRejectsUntrustedSigner(t *testing.T) {
+	idp := newTestIDP(t)
+	c := newTestClient(t, idp)
+	project := &model.Project{Id: "project", AllowStrayAsViewer: true}
+
+	// The response signed by another key pair with the same entity ID.
+	other := newTestIDP(t)
+	doc := makeResponse(t, other, "request-1", newTestAssertion(other, "request-1"))
+
+	_, err := c.Authenticate(postRequest(t, doc), project, "request-1")
+	assert.ErrorIs(t, err, ErrInvalidResponse)
+}
+
+func TestAuthnRequestURL(t *testing.T) {
+	c := newTestClient(t, newTestIDP(t))
+
+	u, id, err := c.AuthnRequestURL("project")
+	require.NoError(t, err)
+	assert.NotEmpty(t, id)
+	assert.True(t, strings.HasPrefix(u, "https://idp.example.com/sso?"))
+	assert.Contains(t, u, "RelayState=project")
+}
+
+func TestDecideRole(t *testing.T) {
+	project := &model.Project{
+		Id: "project",
+		UserGroups: []*model.ProjectUserGroup{
+			{SsoGroup: "dev", Role: "Editor"},
+		},
+	}
+	role, err := decideRole(project, "alice", []string{"dev", "unknown"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Editor"}, role.ProjectRbacRoles)
+
+	_, err = decideRole(project, "alice", []string{"unknown"})
+	assert.Error(t, err)
+
+	project.AllowStrayAsViewer = true
+	role, err = decideRole(project, "alice", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.BuiltinRBACRoleViewer.String()}, role.ProjectRbacRoles)
+}
@@ -0,0 +1,154 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webauthn
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+const (
+	// AlgES256 and AlgRS256 are the COSE algorithms of the credentials requested to be created.
+	AlgES256 = int(webauthncose.AlgES256)
+	AlgRS256 = int(webauthncose.AlgRS256)
+)
+
+// ErrSignCountNotIncreased is returned when the signature counter of the authenticator did not increase,
+// which means the credential may have been cloned.
+var ErrSignCountNotIncreased = errors.New("signature counter did not increase")
+
+// RelyingParty verifies the WebAuthn ceremonies of the control plane by the go-webauthn library.
+// The attestation is verified by its format but the authenticator model is not checked against any trust anchor,
+// and the user verification (e.g. PIN or biometrics) is always required.
+type RelyingParty struct {
+	// ID is the RP ID, which is the host of the control plane.
+	ID string
+	// Name is shown by the browser while registering the credential.
+	Name string
+	// Origin is the origin of the web console sending the ceremonies, e.g. https://pipecd.example.com.
+	Origin string
+}
+
+// NewRelyingParty returns the relying party of the control plane served at the given address.
+func NewRelyingParty(address, name string) (*RelyingParty, error) {
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address %q: %w", address, err)
+	}
+	if u.Scheme != "https" && u.Scheme != "http" || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid address %q, must be an absolute HTTP(S) URL", address)
+	}
+	return &RelyingParty{
+		ID:     strings.ToLower(u.Hostname()),
+		Name:   name,
+		Origin: strings.ToLower(u.Scheme + "://" + u.Host),
+	}, nil
+}
+
+// Credential is the public key credential registered by the user.
+type Credential struct {
+	ID []byte `json:"id"`
+	// PublicKey is the COSE encoded public key of the credential.
+	PublicKey []byte `json:"publicKey"`
+	// SignCount is the signature counter of the last assertion.
+	SignCount uint32 `json:"signCount"`
+}
+
+// Assertion is the PublicKeyCredential returned by navigator.credentials.get().
+type Assertion struct {
+	data *protocol.ParsedCredentialAssertionData
+}
+
+// ParseAssertion parses the JSON encoded PublicKeyCredential returned by navigator.credentials.get().
+func ParseAssertion(response []byte) (*Assertion, error) {
+	data, err := protocol.ParseCredentialRequestResponseBytes(response)
+	if err != nil {
+		return nil, fmt.Errorf("invalid assertion: %w", err)
+	}
+	return &Assertion{data: data}, nil
+}
+
+// CredentialID returns the ID of the credential which signed the assertion.
+func (a *Assertion) CredentialID() []byte {
+	return a.data.RawID
+}
+
+// VerifyRegistration verifies the JSON encoded PublicKeyCredential returned by navigator.credentials.create()
+// to the given challenge and returns the registered credential.
+func (rp *RelyingParty) VerifyRegistration(challenge, response []byte) (*Credential, error) {
+	data, err := protocol.ParseCredentialCreationResponseBytes(response)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential: %w", err)
+	}
+	if err := rp.checkClientData(&data.Response.CollectedClientData, challenge); err != nil {
+		return nil, err
+	}
+	if _, err := data.Verify(encodeChallenge(challenge), true, rp.ID, []string{rp.Origin}, nil, protocol.TopOriginExplicitVerificationMode, nil); err != nil {
+		return nil, fmt.Errorf("invalid credential: %w", err)
+	}
+
+	authData := data.Response.AttestationObject.AuthData
+	if len(authData.AttData.CredentialID) == 0 {
+		return nil, fmt.Errorf("missing attested credential data")
+	}
+	if _, err := webauthncose.ParsePublicKey(authData.AttData.CredentialPublicKey); err != nil {
+		return nil, fmt.Errorf("invalid credential public key: %w", err)
+	}
+	return &Credential{
+		ID:        authData.AttData.CredentialID,
+		PublicKey: authData.AttData.CredentialPublicKey,
+		SignCount: authData.Counter,
+	}, nil
+}
+
+// VerifyAssertion verifies the given assertion to the given challenge signed by the given credential,
+// and returns the new signature counter to store.
+func (rp *RelyingParty) VerifyAssertion(challenge []byte, cred *Credential, a *Assertion) (uint32, error) {
+	if err := rp.checkClientData(&a.data.Response.CollectedClientData, challenge); err != nil {
+		return 0, err
+	}
+	if err := a.data.Verify(encodeChallenge(challenge), rp.ID, []string{rp.Origin}, nil, protocol.TopOriginExplicitVerificationMode, "", true, cred.PublicKey); err != nil {
+		return 0, fmt.Errorf("invalid assertion: %w", err)
+	}
+
+	// The counter is zero if the authenticator does not support it.
+	signCount := a.data.Response.AuthenticatorData.Counter
+	if (signCount != 0 || cred.SignCount != 0) && signCount <= cred.SignCount {
+		return 0, ErrSignCountNotIncreased
+	}
+	return signCount, nil
+}
+
+// checkClientData rejects the ceremonies which the library accepts but the control plane does not,
+// i.e. the ones without the challenge or sent from the frame of another origin.
+func (rp *RelyingParty) checkClientData(cd *protocol.CollectedClientData, challenge []byte) error {
+	if len(challenge) == 0 {
+		return fmt.Errorf("missing challenge")
+	}
+	if cd.CrossOrigin {
+		return fmt.Errorf("unexpected cross-origin ceremony from %q", cd.Origin)
+	}
+	return nil
+}
+
+func encodeChallenge(challenge []byte) string {
+	return base64.RawURLEncoding.EncodeToString(challenge)
+}
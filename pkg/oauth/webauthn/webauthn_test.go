@@ -0,0 +1,125 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package webauthn
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pipe-cd/pipecd/pkg/oauth/webauthn/webauthntest"
+)
+
+func TestNewRelyingParty(t *testing.T) {
+	rp, err := NewRelyingParty("https://PipeCD.example.com:8443/", "PipeCD")
+	require.NoError(t, err)
+	assert.Equal(t, "pipecd.example.com", rp.ID)
+	assert.Equal(t, "https://pipecd.example.com:8443", rp.Origin)
+
+	_, err = NewRelyingParty("/path", "PipeCD")
+	assert.Error(t, err)
+}
+
+func TestRegistrationAndAssertion(t *testing.T) {
+	rp := &RelyingParty{ID: "pipecd.example.com", Origin: "https://pipecd.example.com"}
+	a := webauthntest.NewAuthenticator(t, rp.ID, rp.Origin)
+	challenge := []byte("registration-challenge")
+
+	resp := a.Create(challenge)
+	_, err := rp.VerifyRegistration([]byte("other-challenge"), resp)
+	assert.ErrorContains(t, err, "Error validating challenge")
+	cred, err := rp.VerifyRegistration(challenge, resp)
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, cred.ID)
+	assert.Equal(t, a.PublicKey(), cred.PublicKey)
+
+	challenge = []byte("assertion-challenge")
+	assertion, err := ParseAssertion(a.Get(challenge))
+	require.NoError(t, err)
+	assert.Equal(t, a.ID, assertion.CredentialID())
+	count, err := rp.VerifyAssertion(challenge, cred, assertion)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1), count)
+
+	// The replayed assertion is rejected by the signature counter.
+	cred.SignCount = count
+	_, err = rp.VerifyAssertion(challenge, cred, assertion)
+	assert.ErrorIs(t, err, ErrSignCountNotIncreased)
+
+	// The assertion of another key is rejected.
+	other := webauthntest.NewAuthenticator(t, rp.ID, rp.Origin)
+	other.SignCount = 5
+	assertion, err = ParseAssertion(other.Get(challenge))
+	require.NoError(t, err)
+	_, err = rp.VerifyAssertion(challenge, cred, assertion)
+	assert.Error(t, err)
+}
+
+func TestVerifyRegistrationRejected(t *testing.T) {
+	rp := &RelyingParty{ID: "pipecd.example.com", Origin: "https://pipecd.example.com"}
+	challenge := []byte("challenge")
+
+	_, err := rp.VerifyRegistration(challenge, []byte("{}"))
+	assert.Error(t, err)
+	a := webauthntest.NewAuthenticator(t, rp.ID, rp.Origin)
+	_, err = rp.VerifyRegistration(nil, a.Create(nil))
+	assert.Error(t, err)
+	a.Flags = webauthntest.FlagUserPresent
+	_, err = rp.VerifyRegistration(challenge, a.Create(challenge))
+	assert.Error(t, err)
+}
+
+func TestVerifyAssertionRejected(t *testing.T) {
+	rp := &RelyingParty{ID: "pipecd.example.com", Origin: "https://pipecd.example.com"}
+	challenge := []byte("challenge")
+	testcases := []struct {
+		name     string
+		modify   func(a *webauthntest.Authenticator)
+		expected string
+	}{
+		{
+			name:     "other origin",
+			modify:   func(a *webauthntest.Authenticator) { a.Origin = "https://evil.example.com" },
+			expected: "Error validating origin",
+		},
+		{
+			name:     "cross origin",
+			modify:   func(a *webauthntest.Authenticator) { a.CrossOrigin = true },
+			expected: "unexpected cross-origin",
+		},
+		{
+			name:     "other RP ID",
+			modify:   func(a *webauthntest.Authenticator) { a.RPID = "evil.example.com" },
+			expected: "Error validating the authenticator response",
+		},
+		{
+			name:     "user not verified",
+			modify:   func(a *webauthntest.Authenticator) { a.Flags = webauthntest.FlagUserPresent },
+			expected: "Error validating the authenticator response",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := webauthntest.NewAuthenticator(t, rp.ID, rp.Origin)
+			cred := &Credential{ID: a.ID, PublicKey: a.PublicKey()}
+			tc.modify(a)
+			assertion, err := ParseAssertion(a.Get(challenge))
+			require.NoError(t, err)
+			_, err = rp.VerifyAssertion(challenge, cred, assertion)
+			assert.ErrorContains(t, err, tc.expected)
+		})
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright 2024 The PipeCD Authors.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package webauthntest provides a fake WebAuthn authenticator for the tests.
+package webauthntest
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+)
+
+const (
+	FlagUserPresent  = byte(protocol.FlagUserPresent)
+	FlagUserVerified = byte(protocol.FlagUserVerified)
+	aaguidSize       = 16
+)
+
+// Authenticator creates and signs the WebAuthn responses by its ES256 key
+// in the same way as the browser and the authenticator do.
+type Authenticator struct {
+	t *testing.T
+	// RPID and Origin are the RP ID and the origin of the responses.
+	RPID   string
+	Origin string
+	// CrossOrigin tells the ceremony is sent from the frame of another origin.
+	CrossOrigin bool
+	// ID is the ID of the credential.
+	ID []byte
+	// SignCount is incremented by each assertion.
+	SignCount uint32
+	// Flags are the flags of the authenticator data.
+	Flags byte
+	key   *ecdsa.PrivateKey
+}
+
+// NewAuthenticator returns the authenticator of the given RP ID and origin
+// verifying both the user presence and the user.
+func NewAuthenticator(t *testing.T, rpID, origin string) *Authenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &Authenticator{
+		t:      t,
+		RPID:   rpID,
+		Origin: origin,
+		ID:     []byte("credential-id"),
+		Flags:  FlagUserPresent | FlagUserVerified,
+		key:    key,
+	}
+}
+
+// PublicKey returns the COSE encoded public key of the credential.
+func (a *Authenticator) PublicKey() []byte {
+	x, y := make([]byte, 32), make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+	return a.marshalCBOR(&webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  int64(webauthncose.P256),
+		XCoord: x,
+		YCoord: y,
+	})
+}
+
+// Create returns the JSON encoded PublicKeyCredential with the attestation of the "none" format
+// responded by navigator.credentials.create() to the given challenge.
+func (a *Authenticator) Create(challenge []byte) []byte {
+	attested := make([]byte, aaguidSize)
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.ID)))
+	attested = append(attested, a.ID...)
+	attested = append(attested, a.PublicKey()...)
+	authData := a.authData(a.Flags|byte(protocol.FlagAttestedCredentialData), attested)
+
+	resp := protocol.CredentialCreationResponse{PublicKeyCredential: a.credential()}
+	resp.AttestationResponse.ClientDataJSON = a.clientData(protocol.CreateCeremony, challenge)
+	resp.AttestationResponse.AttestationObject = a.marshalCBOR(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": authData,
+	})
+	return a.marshalJSON(resp)
+}
+
+// Get returns the JSON encoded PublicKeyCredential with the assertion
+// responded by navigator.credentials.get() to the given challenge.
+func (a *Authenticator) Get(challenge []byte) []byte {
+	a.SignCount++
+	clientDataJSON := a.clientData(protocol.AssertCeremony, challenge)
+	authData := a.authData(a.Flags, nil)
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(authData, clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		a.t.Fatal(err)
+	}
+
+	resp := protocol.CredentialAssertionResponse{PublicKeyCredential: a.credential()}
+	resp.AssertionResponse.ClientDataJSON = clientDataJSON
+	resp.AssertionResponse.AuthenticatorData = authData
+	resp.AssertionResponse.Signature = signature
+	return a.marshalJSON(resp)
+}
+
+func (a *Authenticator) credential() protocol.PublicKeyCredential {
+	return protocol.PublicKeyCredential{
+		Credential: protocol.Credential{
+			ID:   base64.RawURLEncoding.EncodeToString(a.ID),
+			Type: string(protocol.PublicKeyCredentialType),
+		},
+		RawID: a.ID,
+	}
+}
+
+func (a *Authenticator) clientData(typ protocol.CeremonyType, challenge []byte) []byte {
+	return a.marshalJSON(&protocol.CollectedClientData{
+		Type:        typ,
+		Challenge:   base64.RawURLEncoding.EncodeToString(challenge),
+		Origin:      a.Origin,
+		CrossOrigin: a.CrossOrigin,
+	})
+}
+
+func (a *Authenticator) authData(flags byte, attested []byte) []byte {
+	hash := sha256.Sum256([]byte(a.RPID))
+	data := append(hash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.SignCount)
+	return append(data, attested...)
+}
+
+func (a *Authenticator) marshalJSON(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return data
+}
+
+func (a *Authenticator) marshalCBOR(v interface{}) []byte {
+	data, err := webauthncbor.Marshal(v)
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return data
+}
@@ -16,11 +16,16 @@ package rpcauth
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"time"
 
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
 	"github.com/pipe-cd/pipecd/pkg/jwt"
@@ -30,6 +35,7 @@ import (
 var (
 	errUnauthenticated  = status.Error(codes.Unauthenticated, "Unauthenticated")
 	errPermissionDenied = status.Error(codes.PermissionDenied, "Permission Denied")
+	errStepUpRequired   = status.Error(codes.PermissionDenied, "Step-up authentication required")
 )
 
 // RBACAuthorizer defines a function to check required role for a specific RPC method.
@@ -42,6 +48,26 @@ type PipedTokenVerifier interface {
 	Verify(ctx context.Context, projectID, pipedID, pipedKey string) error
 }
 
+// SessionExtender re-issues the token of the active session to extend it.
+type SessionExtender interface {
+	// Extend returns the Set-Cookie header value of the re-issued token,
+	// or empty if the session does not need to be extended.
+	Extend(claims *jwt.Claims) (string, error)
+}
+
+// SessionTerminator removes the token of the revoked session from the client.
+type SessionTerminator interface {
+	// Terminate returns the Set-Cookie header values removing the token cookies.
+	Terminate() []string
+}
+
+// SessionBinder checks whether the session is used from the client it is bound to at login.
+type SessionBinder interface {
+	// Check returns an error unless the request from the given address with the given header
+	// is sent by the client the session of the given claims is bound to.
+	Check(claims *jwt.Claims, remoteAddr string, header http.Header) error
+}
+
 // APIKeyVerifier verifies the given API key.
 type APIKeyVerifier interface {
 	Verify(ctx context.Context, key string) (*model.APIKey, error)
@@ -182,16 +208,73 @@ func ExtractAPIKey(ctx context.Context) (*model.APIKey, error) {
 	return k, nil
 }
 
+// JWTOption configures the optional behaviors of JWTUnaryServerInterceptor.
+type JWTOption func(*jwtOptions)
+
+type jwtOptions struct {
+	extender        SessionExtender
+	terminator      SessionTerminator
+	binder          SessionBinder
+	tokenCookieName string
+	stepUpMethods   map[string]struct{}
+}
+
+// WithSessionExtender extends the session of the verified token by the given extender.
+func WithSessionExtender(e SessionExtender) JWTOption {
+	return func(o *jwtOptions) {
+		o.extender = e
+	}
+}
+
+// WithSessionTerminator removes the cookies of the revoked session by the given terminator.
+func WithSessionTerminator(t SessionTerminator) JWTOption {
+	return func(o *jwtOptions) {
+		o.terminator = t
+	}
+}
+
+// WithSessionBinder rejects the session used from another client than the one it is bound to.
+func WithSessionBinder(b SessionBinder) JWTOption {
+	return func(o *jwtOptions) {
+		o.binder = b
+	}
+}
+
+// WithTokenCookieName reads the token from the cookie of the given name instead of the default one.
+func WithTokenCookieName(name string) JWTOption {
+	return func(o *jwtOptions) {
+		o.tokenCookieName = name
+	}
+}
+
+// WithStepUpMethods requires the session elevated by the step-up authentication to call the given methods,
+// which are the full gRPC method names such as "/grpc.service.webservice.WebService/UpdateProjectSSOConfig".
+func WithStepUpMethods(methods ...string) JWTOption {
+	return func(o *jwtOptions) {
+		if o.stepUpMethods == nil {
+			o.stepUpMethods = make(map[string]struct{}, len(methods))
+		}
+		for _, m := range methods {
+			o.stepUpMethods[m] = struct{}{}
+		}
+	}
+}
+
 // JWTUnaryServerInterceptor ensures that the JWT credentials included in the context
 // must be verified by verifier.
-func JWTUnaryServerInterceptor(verifier jwt.Verifier, authorizer RBACAuthorizer, logger *zap.Logger) grpc.UnaryServerInterceptor {
+func JWTUnaryServerInterceptor(verifier jwt.Verifier, authorizer RBACAuthorizer, logger *zap.Logger, opts ...JWTOption) grpc.UnaryServerInterceptor {
+	o := jwtOptions{tokenCookieName: jwt.SignedTokenKey}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	extender, terminator, binder := o.extender, o.terminator, o.binder
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
 		cookie, err := extractCookie(ctx)
 		if err != nil {
 			logger.Warn("failed to extract cookie", zap.Error(err))
 			return nil, errUnauthenticated
 		}
-		token, ok := cookie[jwt.SignedTokenKey]
+		token, ok := cookie[o.tokenCookieName]
 		if !ok {
 			logger.Warn("token does not exist in cookie")
 			return nil, errUnauthenticated
@@ -199,19 +282,88 @@ func JWTUnaryServerInterceptor(verifier jwt.Verifier, authorizer RBACAuthorizer,
 		claims, err := verifier.Verify(token)
 		if err != nil {
 			logger.Warn("unable to verify token", zap.Error(err))
+			if terminator != nil && isRevoked(err) {
+				terminateSession(ctx, terminator, logger)
+			}
 			return nil, errUnauthenticated
 		}
+		if binder != nil {
+			if err := checkSessionBinding(ctx, binder, claims); err != nil {
+				logger.Warn("session is used from another client", zap.String("user", claims.Subject), zap.Error(err))
+				if terminator != nil {
+					terminateSession(ctx, terminator, logger)
+				}
+				return nil, errUnauthenticated
+			}
+		}
 		if !authorizer.Authorize(ctx, info.FullMethod, claims.Role) {
 			logger.Warn(fmt.Sprintf("unsufficient permission for method: %s", info.FullMethod),
 				zap.Any("claims", claims),
 			)
 			return nil, errPermissionDenied
 		}
+		if extender != nil {
+			extendSession(ctx, extender, claims, logger)
+		}
 		ctx = context.WithValue(ctx, claimsKey, *claims)
+		if _, ok := o.stepUpMethods[info.FullMethod]; ok {
+			if err := CheckStepUp(ctx); err != nil {
+				logger.Info(fmt.Sprintf("step-up authentication is required for method: %s", info.FullMethod),
+					zap.String("user", claims.Subject),
+				)
+				return nil, err
+			}
+		}
 		return handler(ctx, req)
 	}
 }
 
+// extendSession sends the re-issued token in the response header.
+// The request is still handled with the current token if it fails.
+func extendSession(ctx context.Context, extender SessionExtender, claims *jwt.Claims, logger *zap.Logger) {
+	cookie, err := extender.Extend(claims)
+	if err != nil {
+		logger.Warn("failed to extend session", zap.String("user", claims.Subject), zap.Error(err))
+		return
+	}
+	if cookie == "" {
+		return
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs("set-cookie", cookie)); err != nil {
+		logger.Warn("failed to send extended session", zap.String("user", claims.Subject), zap.Error(err))
+	}
+}
+
+// checkSessionBinding checks the session by the address of the peer and the metadata of the incoming request.
+func checkSessionBinding(ctx context.Context, binder SessionBinder, claims *jwt.Claims) error {
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		remoteAddr = p.Addr.String()
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	header := make(http.Header, len(md))
+	for k, v := range md {
+		header[http.CanonicalHeaderKey(k)] = v
+	}
+	return binder.Check(claims, remoteAddr, header)
+}
+
+func isRevoked(err error) bool {
+	return errors.Is(err, jwt.ErrSessionRevoked) || errors.Is(err, jwt.ErrMembershipRevoked)
+}
+
+// terminateSession sends the header removing the cookies of the revoked session.
+func terminateSession(ctx context.Context, terminator SessionTerminator, logger *zap.Logger) {
+	cookies := terminator.Terminate()
+	kv := make([]string, 0, 2*len(cookies))
+	for _, c := range cookies {
+		kv = append(kv, "set-cookie", c)
+	}
+	if err := grpc.SetHeader(ctx, metadata.Pairs(kv...)); err != nil {
+		logger.Warn("failed to send terminated session", zap.Error(err))
+	}
+}
+
 // ExtractClaims returns the claims inside a given context.
 func ExtractClaims(ctx context.Context) (jwt.Claims, error) {
 	claims, ok := ctx.Value(claimsKey).(jwt.Claims)
@@ -220,3 +372,16 @@ func ExtractClaims(ctx context.Context) (jwt.Claims, error) {
 	}
 	return claims, nil
 }
+
+// CheckStepUp returns an error unless the session of the given context is elevated by the step-up authentication,
+// which should be required by the RPC methods performing the sensitive actions such as the ones of Admin.
+func CheckStepUp(ctx context.Context) error {
+	claims, err := ExtractClaims(ctx)
+	if err != nil {
+		return err
+	}
+	if !claims.SteppedUp(time.Now()) {
+		return errStepUpRequired
+	}
+	return nil
+}
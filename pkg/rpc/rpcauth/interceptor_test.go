@@ -18,14 +18,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"testing"
+	"time"
 
+	jwtgo "github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 
+	"github.com/pipe-cd/pipecd/pkg/jwt"
 	"github.com/pipe-cd/pipecd/pkg/model"
 )
 
@@ -237,3 +243,170 @@ func TestAPIKeyUnaryServerInterceptor(t *testing.T) {
 		})
 	}
 }
+
+type fakeTransportStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *fakeTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+type fakeJWTVerifier struct {
+	claims *jwt.Claims
+	err    error
+}
+
+func (v fakeJWTVerifier) Verify(string) (*jwt.Claims, error) {
+	return v.claims, v.err
+}
+
+type fakeAuthorizer struct{}
+
+func (fakeAuthorizer) Authorize(context.Context, string, model.Role) bool {
+	return true
+}
+
+type fakeTerminator []string
+
+func (t fakeTerminator) Terminate() []string {
+	return t
+}
+
+func TestJWTUnaryServerInterceptorTerminatesRevokedSession(t *testing.T) {
+	terminator := fakeTerminator{"token=; Max-Age=0", "refresh_token=; Max-Age=0"}
+	testcases := []struct {
+		name          string
+		err           error
+		expectCleared bool
+	}{
+		{
+			name:          "revoked session",
+			err:           jwt.ErrSessionRevoked,
+			expectCleared: true,
+		},
+		{
+			name:          "revoked membership",
+			err:           fmt.Errorf("wrapped: %w", jwt.ErrMembershipRevoked),
+			expectCleared: true,
+		},
+		{
+			name: "invalid token",
+			err:  errors.New("invalid token"),
+		},
+		{
+			name: "valid token",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			verifier := fakeJWTVerifier{claims: &jwt.Claims{}, err: tc.err}
+			in := JWTUnaryServerInterceptor(verifier, fakeAuthorizer{}, zap.NewNop(), WithSessionTerminator(terminator))
+
+			stream := &fakeTransportStream{}
+			ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("cookie", "token=signed"))
+			ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+			_, err := in(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+				return nil, nil
+			})
+			assert.Equal(t, tc.err != nil, err != nil)
+			if tc.expectCleared {
+				assert.Equal(t, []string(terminator), stream.header.Get("set-cookie"))
+				return
+			}
+			assert.Empty(t, stream.header.Get("set-cookie"))
+		})
+	}
+}
+
+func TestJWTUnaryServerInterceptorTokenCookieName(t *testing.T) {
+	verifier := fakeJWTVerifier{claims: &jwt.Claims{}}
+	in := JWTUnaryServerInterceptor(verifier, fakeAuthorizer{}, zap.NewNop(), WithTokenCookieName("tenant-"+jwt.SignedTokenKey))
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		return nil, nil
+	}
+
+	// The token is read from the cookie of the given name only.
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("cookie", "token=signed"))
+	_, err := in(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	assert.Error(t, err)
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("cookie", "tenant-token=signed"))
+	_, err = in(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+	assert.NoError(t, err)
+}
+
+type fakeBinder struct {
+	remoteAddr string
+	userAgent  string
+}
+
+func (b fakeBinder) Check(_ *jwt.Claims, remoteAddr string, header http.Header) error {
+	if remoteAddr != b.remoteAddr || header.Get("User-Agent") != b.userAgent {
+		return errors.New("session binding changed")
+	}
+	return nil
+}
+
+func TestJWTUnaryServerInterceptorSessionBinding(t *testing.T) {
+	terminator := fakeTerminator{"token=; Max-Age=0"}
+	binder := fakeBinder{remoteAddr: "192.0.2.1:1234", userAgent: "Mozilla/5.0"}
+	verifier := fakeJWTVerifier{claims: &jwt.Claims{}}
+	in := JWTUnaryServerInterceptor(verifier, fakeAuthorizer{}, zap.NewNop(), WithSessionTerminator(terminator), WithSessionBinder(binder))
+	call := func(addr, userAgent string) (*fakeTransportStream, error) {
+		stream := &fakeTransportStream{}
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("cookie", "token=signed", "user-agent", userAgent))
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP(addr), Port: 1234}})
+		ctx = grpc.NewContextWithServerTransportStream(ctx, stream)
+		_, err := in(ctx, nil, &grpc.UnaryServerInfo{}, func(context.Context, interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return stream, err
+	}
+
+	stream, err := call("192.0.2.1", "Mozilla/5.0")
+	assert.NoError(t, err)
+	assert.Empty(t, stream.header.Get("set-cookie"))
+
+	stream, err = call("192.0.2.2", "Mozilla/5.0")
+	assert.Error(t, err)
+	assert.Equal(t, []string(terminator), stream.header.Get("set-cookie"))
+
+	_, err = call("192.0.2.1", "curl")
+	assert.Error(t, err)
+}
+
+func TestJWTUnaryServerInterceptorStepUpMethods(t *testing.T) {
+	const method = "/grpc.service.webservice.WebService/UpdateProjectSSOConfig"
+	call := func(claims *jwt.Claims, fullMethod string) error {
+		in := JWTUnaryServerInterceptor(fakeJWTVerifier{claims: claims}, fakeAuthorizer{}, zap.NewNop(), WithStepUpMethods(method))
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("cookie", "token=signed"))
+		_, err := in(ctx, nil, &grpc.UnaryServerInfo{FullMethod: fullMethod}, func(context.Context, interface{}) (interface{}, error) {
+			return nil, nil
+		})
+		return err
+	}
+
+	assert.Equal(t, errStepUpRequired, call(&jwt.Claims{}, method))
+	assert.NoError(t, call(&jwt.Claims{}, "/grpc.service.webservice.WebService/ListApplications"))
+	elevated := &jwt.Claims{
+		ACR:             jwt.ACRStepUp,
+		StepUpExpiresAt: jwtgo.NewNumericDate(time.Now().Add(time.Minute)),
+	}
+	assert.NoError(t, call(elevated, method))
+}
+
+func TestCheckStepUp(t *testing.T) {
+	assert.Error(t, CheckStepUp(context.Background()))
+
+	claims := jwt.Claims{}
+	ctx := context.WithValue(context.Background(), claimsKey, claims)
+	assert.Equal(t, errStepUpRequired, CheckStepUp(ctx))
+
+	claims.ACR = jwt.ACRStepUp
+	claims.StepUpExpiresAt = jwtgo.NewNumericDate(time.Now().Add(time.Minute))
+	ctx = context.WithValue(context.Background(), claimsKey, claims)
+	assert.NoError(t, CheckStepUp(ctx))
+}
@@ -45,12 +45,40 @@ export class Project extends jspb.Message {
   clearUserGroupsList(): Project;
   addUserGroups(value?: ProjectUserGroup, index?: number): ProjectUserGroup;
 
+  getAdditionalSharedSsoNamesList(): Array<string>;
+  setAdditionalSharedSsoNamesList(value: Array<string>): Project;
+  clearAdditionalSharedSsoNamesList(): Project;
+  addAdditionalSharedSsoNames(value: string, index?: number): Project;
+
+  getDefaultRole(): string;
+  setDefaultRole(value: string): Project;
+
+  getEmailDomainRolesList(): Array<ProjectEmailDomainRole>;
+  setEmailDomainRolesList(value: Array<ProjectEmailDomainRole>): Project;
+  clearEmailDomainRolesList(): Project;
+  addEmailDomainRoles(value?: ProjectEmailDomainRole, index?: number): ProjectEmailDomainRole;
+
   getCreatedAt(): number;
   setCreatedAt(value: number): Project;
 
   getUpdatedAt(): number;
   setUpdatedAt(value: number): Project;
 
+  getLoginCallbackRateLimit(): number;
+  setLoginCallbackRateLimit(value: number): Project;
+
+  getLoginCallbackRateLimitBurst(): number;
+  setLoginCallbackRateLimitBurst(value: number): Project;
+
+  getLoginLockoutThreshold(): number;
+  setLoginLockoutThreshold(value: number): Project;
+
+  getLoginLockoutWindow(): number;
+  setLoginLockoutWindow(value: number): Project;
+
+  getLoginLockoutCooldown(): number;
+  setLoginLockoutCooldown(value: number): Project;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): Project.AsObject;
   static toObject(includeInstance: boolean, msg: Project): Project.AsObject;
@@ -71,8 +99,16 @@ export namespace Project {
     allowStrayAsViewer: boolean,
     rbacRolesList: Array<ProjectRBACRole.AsObject>,
     userGroupsList: Array<ProjectUserGroup.AsObject>,
+    additionalSharedSsoNamesList: Array<string>,
+    defaultRole: string,
+    emailDomainRolesList: Array<ProjectEmailDomainRole.AsObject>,
     createdAt: number,
     updatedAt: number,
+    loginCallbackRateLimit: number,
+    loginCallbackRateLimitBurst: number,
+    loginLockoutThreshold: number,
+    loginLockoutWindow: number,
+    loginLockoutCooldown: number,
   }
 }
 
@@ -105,6 +141,11 @@ export class ProjectSSOConfig extends jspb.Message {
   getSessionTtl(): number;
   setSessionTtl(value: number): ProjectSSOConfig;
 
+  getAllowedRedirectUrisList(): Array<string>;
+  setAllowedRedirectUrisList(value: Array<string>): ProjectSSOConfig;
+  clearAllowedRedirectUrisList(): ProjectSSOConfig;
+  addAllowedRedirectUris(value: string, index?: number): ProjectSSOConfig;
+
   getGithub(): ProjectSSOConfig.GitHub | undefined;
   setGithub(value?: ProjectSSOConfig.GitHub): ProjectSSOConfig;
   hasGithub(): boolean;
@@ -120,6 +161,42 @@ export class ProjectSSOConfig extends jspb.Message {
   hasOidc(): boolean;
   clearOidc(): ProjectSSOConfig;
 
+  getGitlab(): ProjectSSOConfig.GitLab | undefined;
+  setGitlab(value?: ProjectSSOConfig.GitLab): ProjectSSOConfig;
+  hasGitlab(): boolean;
+  clearGitlab(): ProjectSSOConfig;
+
+  getAzureAd(): ProjectSSOConfig.AzureAD | undefined;
+  setAzureAd(value?: ProjectSSOConfig.AzureAD): ProjectSSOConfig;
+  hasAzureAd(): boolean;
+  clearAzureAd(): ProjectSSOConfig;
+
+  getLdap(): ProjectSSOConfig.Ldap | undefined;
+  setLdap(value?: ProjectSSOConfig.Ldap): ProjectSSOConfig;
+  hasLdap(): boolean;
+  clearLdap(): ProjectSSOConfig;
+
+  getSaml(): ProjectSSOConfig.Saml | undefined;
+  setSaml(value?: ProjectSSOConfig.Saml): ProjectSSOConfig;
+  hasSaml(): boolean;
+  clearSaml(): ProjectSSOConfig;
+
+  getOkta(): ProjectSSOConfig.Okta | undefined;
+  setOkta(value?: ProjectSSOConfig.Okta): ProjectSSOConfig;
+  hasOkta(): boolean;
+  clearOkta(): ProjectSSOConfig;
+
+  getBitbucket(): ProjectSSOConfig.Bitbucket | undefined;
+  setBitbucket(value?: ProjectSSOConfig.Bitbucket): ProjectSSOConfig;
+  hasBitbucket(): boolean;
+
+  getDisplayName(): string;
+  setDisplayName(value: string): ProjectSSOConfig;
+
+  getIcon(): string;
+  setIcon(value: string): ProjectSSOConfig;
+  clearBitbucket(): ProjectSSOConfig;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): ProjectSSOConfig.AsObject;
   static toObject(includeInstance: boolean, msg: ProjectSSOConfig): ProjectSSOConfig.AsObject;
@@ -132,9 +209,18 @@ export namespace ProjectSSOConfig {
   export type AsObject = {
     provider: ProjectSSOConfig.Provider,
     sessionTtl: number,
+    allowedRedirectUrisList: Array<string>,
     github?: ProjectSSOConfig.GitHub.AsObject,
     google?: ProjectSSOConfig.Google.AsObject,
     oidc?: ProjectSSOConfig.Oidc.AsObject,
+    gitlab?: ProjectSSOConfig.GitLab.AsObject,
+    azureAd?: ProjectSSOConfig.AzureAD.AsObject,
+    ldap?: ProjectSSOConfig.Ldap.AsObject,
+    saml?: ProjectSSOConfig.Saml.AsObject,
+    okta?: ProjectSSOConfig.Okta.AsObject,
+    bitbucket?: ProjectSSOConfig.Bitbucket.AsObject,
+    displayName: string,
+    icon: string,
   }
 
   export class GitHub extends jspb.Message {
@@ -153,6 +239,26 @@ export namespace ProjectSSOConfig {
     getProxyUrl(): string;
     setProxyUrl(value: string): GitHub;
 
+    getApiUrl(): string;
+    setApiUrl(value: string): GitHub;
+
+    getRequiredOrgsList(): Array<string>;
+    setRequiredOrgsList(value: Array<string>): GitHub;
+    clearRequiredOrgsList(): GitHub;
+    addRequiredOrgs(value: string, index?: number): GitHub;
+
+    getAppId(): number;
+    setAppId(value: number): GitHub;
+
+    getAppInstallationId(): number;
+    setAppInstallationId(value: number): GitHub;
+
+    getAppPrivateKey(): string;
+    setAppPrivateKey(value: string): GitHub;
+
+    getExtraHeadersMap(): jspb.Map<string, string>;
+    clearExtraHeadersMap(): GitHub;
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): GitHub.AsObject;
     static toObject(includeInstance: boolean, msg: GitHub): GitHub.AsObject;
@@ -168,6 +274,12 @@ export namespace ProjectSSOConfig {
       baseUrl: string,
       uploadUrl: string,
       proxyUrl: string,
+      apiUrl: string,
+      requiredOrgsList: Array<string>,
+      appId: number,
+      appInstallationId: number,
+      appPrivateKey: string,
+      extraHeadersMap: Array<[string, string]>,
     }
   }
 
@@ -179,6 +291,17 @@ export namespace ProjectSSOConfig {
     getClientSecret(): string;
     setClientSecret(value: string): Google;
 
+    getRedirectUri(): string;
+    setRedirectUri(value: string): Google;
+
+    getAllowedDomainsList(): Array<string>;
+    setAllowedDomainsList(value: Array<string>): Google;
+    clearAllowedDomainsList(): Google;
+    addAllowedDomains(value: string, index?: number): Google;
+
+    getProxyUrl(): string;
+    setProxyUrl(value: string): Google;
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): Google.AsObject;
     static toObject(includeInstance: boolean, msg: Google): Google.AsObject;
@@ -191,6 +314,9 @@ export namespace ProjectSSOConfig {
     export type AsObject = {
       clientId: string,
       clientSecret: string,
+      redirectUri: string,
+      allowedDomainsList: Array<string>,
+      proxyUrl: string,
     }
   }
 
@@ -234,6 +360,80 @@ export namespace ProjectSSOConfig {
     getAvatarUrlClaimKey(): string;
     setAvatarUrlClaimKey(value: string): Oidc;
 
+    getPkceEnabled(): boolean;
+    setPkceEnabled(value: boolean): Oidc;
+
+    getPkceChallengeMethod(): string;
+    setPkceChallengeMethod(value: string): Oidc;
+
+    getDisableNonceValidation(): boolean;
+    setDisableNonceValidation(value: boolean): Oidc;
+
+    getPostLogoutRedirectUri(): string;
+    setPostLogoutRedirectUri(value: string): Oidc;
+
+    getEndSessionEndpoint(): string;
+    setEndSessionEndpoint(value: string): Oidc;
+
+    getAllowedAudiencesList(): Array<string>;
+    setAllowedAudiencesList(value: Array<string>): Oidc;
+    clearAllowedAudiencesList(): Oidc;
+    addAllowedAudiences(value: string, index?: number): Oidc;
+
+    getIdTokenDecryptionKey(): string;
+    setIdTokenDecryptionKey(value: string): Oidc;
+
+    getIdTokenContentEncryptionAlgorithmsList(): Array<string>;
+    setIdTokenContentEncryptionAlgorithmsList(value: Array<string>): Oidc;
+    clearIdTokenContentEncryptionAlgorithmsList(): Oidc;
+    addIdTokenContentEncryptionAlgorithms(value: string, index?: number): Oidc;
+
+    getRequireVerifiedEmail(): boolean;
+    setRequireVerifiedEmail(value: boolean): Oidc;
+
+    getFetchUserinfo(): boolean;
+    setFetchUserinfo(value: boolean): Oidc;
+
+    getAllowIdpInitiatedLogin(): boolean;
+    setAllowIdpInitiatedLogin(value: boolean): Oidc;
+
+    getClientCertificate(): string;
+    setClientCertificate(value: string): Oidc;
+
+    getClientKey(): string;
+    setClientKey(value: string): Oidc;
+
+    getClientAssertionKey(): string;
+    setClientAssertionKey(value: string): Oidc;
+
+    getClientAssertionSigningAlgorithm(): string;
+    setClientAssertionSigningAlgorithm(value: string): Oidc;
+
+    getRolesClaimPathsList(): Array<string>;
+    setRolesClaimPathsList(value: Array<string>): Oidc;
+    clearRolesClaimPathsList(): Oidc;
+    addRolesClaimPaths(value: string, index?: number): Oidc;
+
+    getMaxAge(): number;
+    setMaxAge(value: number): Oidc;
+
+    getGroupsOverflowClaim(): string;
+    setGroupsOverflowClaim(value: string): Oidc;
+
+    getGroupsEndpoint(): string;
+    setGroupsEndpoint(value: string): Oidc;
+
+    getMaxGroups(): number;
+    setMaxGroups(value: number): Oidc;
+
+    getExtraHeadersMap(): jspb.Map<string, string>;
+    clearExtraHeadersMap(): Oidc;
+
+    getRoleSourceClaimsList(): Array<string>;
+    setRoleSourceClaimsList(value: Array<string>): Oidc;
+    clearRoleSourceClaimsList(): Oidc;
+    addRoleSourceClaims(value: string, index?: number): Oidc;
+
     serializeBinary(): Uint8Array;
     toObject(includeInstance?: boolean): Oidc.AsObject;
     static toObject(includeInstance: boolean, msg: Oidc): Oidc.AsObject;
@@ -256,6 +456,309 @@ export namespace ProjectSSOConfig {
       rolesClaimKey: string,
       usernameClaimKey: string,
       avatarUrlClaimKey: string,
+      pkceEnabled: boolean,
+      pkceChallengeMethod: string,
+      disableNonceValidation: boolean,
+      postLogoutRedirectUri: string,
+      endSessionEndpoint: string,
+      allowedAudiencesList: Array<string>,
+      idTokenDecryptionKey: string,
+      idTokenContentEncryptionAlgorithmsList: Array<string>,
+      requireVerifiedEmail: boolean,
+      fetchUserinfo: boolean,
+      allowIdpInitiatedLogin: boolean,
+      clientCertificate: string,
+      clientKey: string,
+      clientAssertionKey: string,
+      clientAssertionSigningAlgorithm: string,
+      rolesClaimPathsList: Array<string>,
+      maxAge: number,
+      groupsOverflowClaim: string,
+      groupsEndpoint: string,
+      maxGroups: number,
+      extraHeadersMap: Array<[string, string]>,
+      roleSourceClaimsList: Array<string>,
+    }
+  }
+
+
+  export class GitLab extends jspb.Message {
+    getClientId(): string;
+    setClientId(value: string): GitLab;
+
+    getClientSecret(): string;
+    setClientSecret(value: string): GitLab;
+
+    getBaseUrl(): string;
+    setBaseUrl(value: string): GitLab;
+
+    getRedirectUri(): string;
+    setRedirectUri(value: string): GitLab;
+
+    getProxyUrl(): string;
+    setProxyUrl(value: string): GitLab;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): GitLab.AsObject;
+    static toObject(includeInstance: boolean, msg: GitLab): GitLab.AsObject;
+    static serializeBinaryToWriter(message: GitLab, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): GitLab;
+    static deserializeBinaryFromReader(message: GitLab, reader: jspb.BinaryReader): GitLab;
+  }
+
+  export namespace GitLab {
+    export type AsObject = {
+      clientId: string,
+      clientSecret: string,
+      baseUrl: string,
+      redirectUri: string,
+      proxyUrl: string,
+    }
+  }
+
+
+  export class AzureAD extends jspb.Message {
+    getClientId(): string;
+    setClientId(value: string): AzureAD;
+
+    getClientSecret(): string;
+    setClientSecret(value: string): AzureAD;
+
+    getTenant(): string;
+    setTenant(value: string): AzureAD;
+
+    getRedirectUri(): string;
+    setRedirectUri(value: string): AzureAD;
+
+    getAllowedTenantsList(): Array<string>;
+    setAllowedTenantsList(value: Array<string>): AzureAD;
+    clearAllowedTenantsList(): AzureAD;
+    addAllowedTenants(value: string, index?: number): AzureAD;
+
+    getProxyUrl(): string;
+    setProxyUrl(value: string): AzureAD;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): AzureAD.AsObject;
+    static toObject(includeInstance: boolean, msg: AzureAD): AzureAD.AsObject;
+    static serializeBinaryToWriter(message: AzureAD, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): AzureAD;
+    static deserializeBinaryFromReader(message: AzureAD, reader: jspb.BinaryReader): AzureAD;
+  }
+
+  export namespace AzureAD {
+    export type AsObject = {
+      clientId: string,
+      clientSecret: string,
+      tenant: string,
+      redirectUri: string,
+      allowedTenantsList: Array<string>,
+      proxyUrl: string,
+    }
+  }
+
+
+  export class Ldap extends jspb.Message {
+    getUrl(): string;
+    setUrl(value: string): Ldap;
+
+    getStartTls(): boolean;
+    setStartTls(value: boolean): Ldap;
+
+    getRootCa(): string;
+    setRootCa(value: string): Ldap;
+
+    getBindDn(): string;
+    setBindDn(value: string): Ldap;
+
+    getBindPassword(): string;
+    setBindPassword(value: string): Ldap;
+
+    getUserSearchBase(): string;
+    setUserSearchBase(value: string): Ldap;
+
+    getUserFilter(): string;
+    setUserFilter(value: string): Ldap;
+
+    getGroupSearchBase(): string;
+    setGroupSearchBase(value: string): Ldap;
+
+    getGroupFilter(): string;
+    setGroupFilter(value: string): Ldap;
+
+    getGroupNameAttribute(): string;
+    setGroupNameAttribute(value: string): Ldap;
+
+    getPoolSize(): number;
+    setPoolSize(value: number): Ldap;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): Ldap.AsObject;
+    static toObject(includeInstance: boolean, msg: Ldap): Ldap.AsObject;
+    static serializeBinaryToWriter(message: Ldap, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): Ldap;
+    static deserializeBinaryFromReader(message: Ldap, reader: jspb.BinaryReader): Ldap;
+  }
+
+  export namespace Ldap {
+    export type AsObject = {
+      url: string,
+      startTls: boolean,
+      rootCa: string,
+      bindDn: string,
+      bindPassword: string,
+      userSearchBase: string,
+      userFilter: string,
+      groupSearchBase: string,
+      groupFilter: string,
+      groupNameAttribute: string,
+      poolSize: number,
+    }
+  }
+
+
+  export class Okta extends jspb.Message {
+    getClientId(): string;
+    setClientId(value: string): Okta;
+
+    getClientSecret(): string;
+    setClientSecret(value: string): Okta;
+
+    getDomain(): string;
+    setDomain(value: string): Okta;
+
+    getAuthorizationServerId(): string;
+    setAuthorizationServerId(value: string): Okta;
+
+    getRedirectUri(): string;
+    setRedirectUri(value: string): Okta;
+
+    getGroupsClaim(): string;
+    setGroupsClaim(value: string): Okta;
+
+    getScopesList(): Array<string>;
+    setScopesList(value: Array<string>): Okta;
+    clearScopesList(): Okta;
+    addScopes(value: string, index?: number): Okta;
+
+    getProxyUrl(): string;
+    setProxyUrl(value: string): Okta;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): Okta.AsObject;
+    static toObject(includeInstance: boolean, msg: Okta): Okta.AsObject;
+    static serializeBinaryToWriter(message: Okta, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): Okta;
+    static deserializeBinaryFromReader(message: Okta, reader: jspb.BinaryReader): Okta;
+  }
+
+  export namespace Okta {
+    export type AsObject = {
+      clientId: string,
+      clientSecret: string,
+      domain: string,
+      authorizationServerId: string,
+      redirectUri: string,
+      groupsClaim: string,
+      scopesList: Array<string>,
+      proxyUrl: string,
+    }
+  }
+
+
+  export class Bitbucket extends jspb.Message {
+    getClientId(): string;
+    setClientId(value: string): Bitbucket;
+
+    getClientSecret(): string;
+    setClientSecret(value: string): Bitbucket;
+
+    getFlavor(): ProjectSSOConfig.Bitbucket.Flavor;
+    setFlavor(value: ProjectSSOConfig.Bitbucket.Flavor): Bitbucket;
+
+    getBaseUrl(): string;
+    setBaseUrl(value: string): Bitbucket;
+
+    getRedirectUri(): string;
+    setRedirectUri(value: string): Bitbucket;
+
+    getScopesList(): Array<string>;
+    setScopesList(value: Array<string>): Bitbucket;
+    clearScopesList(): Bitbucket;
+    addScopes(value: string, index?: number): Bitbucket;
+
+    getProxyUrl(): string;
+    setProxyUrl(value: string): Bitbucket;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): Bitbucket.AsObject;
+    static toObject(includeInstance: boolean, msg: Bitbucket): Bitbucket.AsObject;
+    static serializeBinaryToWriter(message: Bitbucket, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): Bitbucket;
+    static deserializeBinaryFromReader(message: Bitbucket, reader: jspb.BinaryReader): Bitbucket;
+  }
+
+  export namespace Bitbucket {
+    export type AsObject = {
+      clientId: string,
+      clientSecret: string,
+      flavor: ProjectSSOConfig.Bitbucket.Flavor,
+      baseUrl: string,
+      redirectUri: string,
+      scopesList: Array<string>,
+      proxyUrl: string,
+    }
+
+    export enum Flavor { 
+      CLOUD = 0,
+      SERVER = 1,
+    }
+  }
+
+
+  export class Saml extends jspb.Message {
+    getIdpMetadataUrl(): string;
+    setIdpMetadataUrl(value: string): Saml;
+
+    getIdpMetadata(): string;
+    setIdpMetadata(value: string): Saml;
+
+    getEntityId(): string;
+    setEntityId(value: string): Saml;
+
+    getUsernameAttribute(): string;
+    setUsernameAttribute(value: string): Saml;
+
+    getAvatarUrlAttribute(): string;
+    setAvatarUrlAttribute(value: string): Saml;
+
+    getGroupsAttribute(): string;
+    setGroupsAttribute(value: string): Saml;
+
+    getCertificate(): string;
+    setCertificate(value: string): Saml;
+
+    getPrivateKey(): string;
+    setPrivateKey(value: string): Saml;
+
+    serializeBinary(): Uint8Array;
+    toObject(includeInstance?: boolean): Saml.AsObject;
+    static toObject(includeInstance: boolean, msg: Saml): Saml.AsObject;
+    static serializeBinaryToWriter(message: Saml, writer: jspb.BinaryWriter): void;
+    static deserializeBinary(bytes: Uint8Array): Saml;
+    static deserializeBinaryFromReader(message: Saml, reader: jspb.BinaryReader): Saml;
+  }
+
+  export namespace Saml {
+    export type AsObject = {
+      idpMetadataUrl: string,
+      idpMetadata: string,
+      entityId: string,
+      usernameAttribute: string,
+      avatarUrlAttribute: string,
+      groupsAttribute: string,
+      certificate: string,
+      privateKey: string,
     }
   }
 
@@ -264,6 +767,12 @@ export namespace ProjectSSOConfig {
     GITHUB = 0,
     GOOGLE = 2,
     OIDC = 3,
+    GITLAB = 4,
+    AZUREAD = 5,
+    LDAP = 6,
+    SAML = 7,
+    OKTA = 8,
+    BITBUCKET = 9,
   }
 }
 
@@ -300,6 +809,9 @@ export class ProjectUserGroup extends jspb.Message {
   getRole(): string;
   setRole(value: string): ProjectUserGroup;
 
+  getSessionTtl(): number;
+  setSessionTtl(value: number): ProjectUserGroup;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): ProjectUserGroup.AsObject;
   static toObject(includeInstance: boolean, msg: ProjectUserGroup): ProjectUserGroup.AsObject;
@@ -312,6 +824,29 @@ export namespace ProjectUserGroup {
   export type AsObject = {
     ssoGroup: string,
     role: string,
+    sessionTtl: number,
+  }
+}
+
+export class ProjectEmailDomainRole extends jspb.Message {
+  getDomain(): string;
+  setDomain(value: string): ProjectEmailDomainRole;
+
+  getRole(): string;
+  setRole(value: string): ProjectEmailDomainRole;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): ProjectEmailDomainRole.AsObject;
+  static toObject(includeInstance: boolean, msg: ProjectEmailDomainRole): ProjectEmailDomainRole.AsObject;
+  static serializeBinaryToWriter(message: ProjectEmailDomainRole, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): ProjectEmailDomainRole;
+  static deserializeBinaryFromReader(message: ProjectEmailDomainRole, reader: jspb.BinaryReader): ProjectEmailDomainRole;
+}
+
+export namespace ProjectEmailDomainRole {
+  export type AsObject = {
+    domain: string,
+    role: string,
   }
 }
 
@@ -26,6 +26,7 @@ var global =
 var google_protobuf_descriptor_pb = require('google-protobuf/google/protobuf/descriptor_pb.js');
 goog.object.extend(proto, google_protobuf_descriptor_pb);
 goog.exportSymbol('proto.model.Project', null, global);
+goog.exportSymbol('proto.model.ProjectEmailDomainRole', null, global);
 goog.exportSymbol('proto.model.ProjectRBACConfig', null, global);
 goog.exportSymbol('proto.model.ProjectRBACPolicy', null, global);
 goog.exportSymbol('proto.model.ProjectRBACPolicy.Action', null, global);
@@ -33,10 +34,17 @@ goog.exportSymbol('proto.model.ProjectRBACResource', null, global);
 goog.exportSymbol('proto.model.ProjectRBACResource.ResourceType', null, global);
 goog.exportSymbol('proto.model.ProjectRBACRole', null, global);
 goog.exportSymbol('proto.model.ProjectSSOConfig', null, global);
+goog.exportSymbol('proto.model.ProjectSSOConfig.AzureAD', null, global);
+goog.exportSymbol('proto.model.ProjectSSOConfig.Bitbucket', null, global);
+goog.exportSymbol('proto.model.ProjectSSOConfig.Bitbucket.Flavor', null, global);
 goog.exportSymbol('proto.model.ProjectSSOConfig.GitHub', null, global);
+goog.exportSymbol('proto.model.ProjectSSOConfig.GitLab', null, global);
 goog.exportSymbol('proto.model.ProjectSSOConfig.Google', null, global);
+goog.exportSymbol('proto.model.ProjectSSOConfig.Ldap', null, global);
 goog.exportSymbol('proto.model.ProjectSSOConfig.Oidc', null, global);
+goog.exportSymbol('proto.model.ProjectSSOConfig.Okta', null, global);
 goog.exportSymbol('proto.model.ProjectSSOConfig.Provider', null, global);
+goog.exportSymbol('proto.model.ProjectSSOConfig.Saml', null, global);
 goog.exportSymbol('proto.model.ProjectStaticUser', null, global);
 goog.exportSymbol('proto.model.ProjectUserGroup', null, global);
 /**
@@ -92,7 +100,7 @@ if (goog.DEBUG && !COMPILED) {
  * @constructor
  */
 proto.model.ProjectSSOConfig = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.model.ProjectSSOConfig.repeatedFields_, null);
 };
 goog.inherits(proto.model.ProjectSSOConfig, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
@@ -113,7 +121,7 @@ if (goog.DEBUG && !COMPILED) {
  * @constructor
  */
 proto.model.ProjectSSOConfig.GitHub = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.model.ProjectSSOConfig.GitHub.repeatedFields_, null);
 };
 goog.inherits(proto.model.ProjectSSOConfig.GitHub, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
@@ -134,7 +142,7 @@ if (goog.DEBUG && !COMPILED) {
  * @constructor
  */
 proto.model.ProjectSSOConfig.Google = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.model.ProjectSSOConfig.Google.repeatedFields_, null);
 };
 goog.inherits(proto.model.ProjectSSOConfig.Google, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
@@ -165,6 +173,132 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.model.ProjectSSOConfig.Oidc.displayName = 'proto.model.ProjectSSOConfig.Oidc';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.model.ProjectSSOConfig.GitLab = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.model.ProjectSSOConfig.GitLab, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.model.ProjectSSOConfig.GitLab.displayName = 'proto.model.ProjectSSOConfig.GitLab';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.model.ProjectSSOConfig.AzureAD = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.model.ProjectSSOConfig.AzureAD.repeatedFields_, null);
+};
+goog.inherits(proto.model.ProjectSSOConfig.AzureAD, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.model.ProjectSSOConfig.AzureAD.displayName = 'proto.model.ProjectSSOConfig.AzureAD';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.model.ProjectSSOConfig.Ldap = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.model.ProjectSSOConfig.Ldap, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.model.ProjectSSOConfig.Ldap.displayName = 'proto.model.ProjectSSOConfig.Ldap';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.model.ProjectSSOConfig.Okta = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.model.ProjectSSOConfig.Okta.repeatedFields_, null);
+};
+goog.inherits(proto.model.ProjectSSOConfig.Okta, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.model.ProjectSSOConfig.Okta.displayName = 'proto.model.ProjectSSOConfig.Okta';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.model.ProjectSSOConfig.Bitbucket = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.model.ProjectSSOConfig.Bitbucket.repeatedFields_, null);
+};
+goog.inherits(proto.model.ProjectSSOConfig.Bitbucket, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.model.ProjectSSOConfig.Bitbucket.displayName = 'proto.model.ProjectSSOConfig.Bitbucket';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.model.ProjectSSOConfig.Saml = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.model.ProjectSSOConfig.Saml, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.model.ProjectSSOConfig.Saml.displayName = 'proto.model.ProjectSSOConfig.Saml';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -207,6 +341,27 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.model.ProjectUserGroup.displayName = 'proto.model.ProjectUserGroup';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.model.ProjectEmailDomainRole = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.model.ProjectEmailDomainRole, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.model.ProjectEmailDomainRole.displayName = 'proto.model.ProjectEmailDomainRole';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -276,7 +431,7 @@ if (goog.DEBUG && !COMPILED) {
  * @private {!Array<number>}
  * @const
  */
-proto.model.Project.repeatedFields_ = [9,10];
+proto.model.Project.repeatedFields_ = [9,10,11,13];
 
 
 
@@ -321,8 +476,17 @@ proto.model.Project.toObject = function(includeInstance, msg) {
     proto.model.ProjectRBACRole.toObject, includeInstance),
     userGroupsList: jspb.Message.toObjectList(msg.getUserGroupsList(),
     proto.model.ProjectUserGroup.toObject, includeInstance),
+    additionalSharedSsoNamesList: (f = jspb.Message.getRepeatedField(msg, 11)) == null ? undefined : f,
+    defaultRole: jspb.Message.getFieldWithDefault(msg, 12, ""),
+    emailDomainRolesList: jspb.Message.toObjectList(msg.getEmailDomainRolesList(),
+    proto.model.ProjectEmailDomainRole.toObject, includeInstance),
     createdAt: jspb.Message.getFieldWithDefault(msg, 14, 0),
-    updatedAt: jspb.Message.getFieldWithDefault(msg, 15, 0)
+    updatedAt: jspb.Message.getFieldWithDefault(msg, 15, 0),
+    loginCallbackRateLimit: jspb.Message.getFieldWithDefault(msg, 16, 0),
+    loginCallbackRateLimitBurst: jspb.Message.getFieldWithDefault(msg, 17, 0),
+    loginLockoutThreshold: jspb.Message.getFieldWithDefault(msg, 18, 0),
+    loginLockoutWindow: jspb.Message.getFieldWithDefault(msg, 19, 0),
+    loginLockoutCooldown: jspb.Message.getFieldWithDefault(msg, 20, 0)
   };
 
   if (includeInstance) {
@@ -404,6 +568,19 @@ proto.model.Project.deserializeBinaryFromReader = function(msg, reader) {
       reader.readMessage(value,proto.model.ProjectUserGroup.deserializeBinaryFromReader);
       msg.addUserGroups(value);
       break;
+    case 11:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addAdditionalSharedSsoNames(value);
+      break;
+    case 12:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDefaultRole(value);
+      break;
+    case 13:
+      var value = new proto.model.ProjectEmailDomainRole;
+      reader.readMessage(value,proto.model.ProjectEmailDomainRole.deserializeBinaryFromReader);
+      msg.addEmailDomainRoles(value);
+      break;
     case 14:
       var value = /** @type {number} */ (reader.readInt64());
       msg.setCreatedAt(value);
@@ -412,6 +589,26 @@ proto.model.Project.deserializeBinaryFromReader = function(msg, reader) {
       var value = /** @type {number} */ (reader.readInt64());
       msg.setUpdatedAt(value);
       break;
+    case 16:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setLoginCallbackRateLimit(value);
+      break;
+    case 17:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setLoginCallbackRateLimitBurst(value);
+      break;
+    case 18:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setLoginLockoutThreshold(value);
+      break;
+    case 19:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setLoginLockoutWindow(value);
+      break;
+    case 20:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setLoginLockoutCooldown(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -516,6 +713,28 @@ proto.model.Project.serializeBinaryToWriter = function(message, writer) {
       proto.model.ProjectUserGroup.serializeBinaryToWriter
     );
   }
+  f = message.getAdditionalSharedSsoNamesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      11,
+      f
+    );
+  }
+  f = message.getDefaultRole();
+  if (f.length > 0) {
+    writer.writeString(
+      12,
+      f
+    );
+  }
+  f = message.getEmailDomainRolesList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      13,
+      f,
+      proto.model.ProjectEmailDomainRole.serializeBinaryToWriter
+    );
+  }
   f = message.getCreatedAt();
   if (f !== 0) {
     writer.writeInt64(
@@ -530,6 +749,41 @@ proto.model.Project.serializeBinaryToWriter = function(message, writer) {
       f
     );
   }
+  f = message.getLoginCallbackRateLimit();
+  if (f !== 0) {
+    writer.writeInt32(
+      16,
+      f
+    );
+  }
+  f = message.getLoginCallbackRateLimitBurst();
+  if (f !== 0) {
+    writer.writeInt32(
+      17,
+      f
+    );
+  }
+  f = message.getLoginLockoutThreshold();
+  if (f !== 0) {
+    writer.writeInt32(
+      18,
+      f
+    );
+  }
+  f = message.getLoginLockoutWindow();
+  if (f !== 0) {
+    writer.writeInt64(
+      19,
+      f
+    );
+  }
+  f = message.getLoginLockoutCooldown();
+  if (f !== 0) {
+    writer.writeInt64(
+      20,
+      f
+    );
+  }
 };
 
 
@@ -811,103 +1065,286 @@ proto.model.Project.prototype.clearUserGroupsList = function() {
 
 
 /**
- * optional int64 created_at = 14;
- * @return {number}
+ * repeated string additional_shared_sso_names = 11;
+ * @return {!Array<string>}
  */
-proto.model.Project.prototype.getCreatedAt = function() {
-  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 14, 0));
+proto.model.Project.prototype.getAdditionalSharedSsoNamesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 11));
 };
 
 
 /**
- * @param {number} value
+ * @param {!Array<string>} value
  * @return {!proto.model.Project} returns this
  */
-proto.model.Project.prototype.setCreatedAt = function(value) {
-  return jspb.Message.setProto3IntField(this, 14, value);
+proto.model.Project.prototype.setAdditionalSharedSsoNamesList = function(value) {
+  return jspb.Message.setField(this, 11, value || []);
 };
 
 
 /**
- * optional int64 updated_at = 15;
- * @return {number}
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.Project} returns this
  */
-proto.model.Project.prototype.getUpdatedAt = function() {
-  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 15, 0));
+proto.model.Project.prototype.addAdditionalSharedSsoNames = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 11, value, opt_index);
 };
 
 
 /**
- * @param {number} value
+ * Clears the list making it empty but non-null.
  * @return {!proto.model.Project} returns this
  */
-proto.model.Project.prototype.setUpdatedAt = function(value) {
-  return jspb.Message.setProto3IntField(this, 15, value);
+proto.model.Project.prototype.clearAdditionalSharedSsoNamesList = function() {
+  return this.setAdditionalSharedSsoNamesList([]);
 };
 
 
+/**
+ * optional string default_role = 12;
+ * @return {string}
+ */
+proto.model.Project.prototype.getDefaultRole = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 12, ""));
+};
 
 
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * @param {string} value
+ * @return {!proto.model.Project} returns this
  */
-proto.model.ProjectStaticUser.prototype.toObject = function(opt_includeInstance) {
-  return proto.model.ProjectStaticUser.toObject(opt_includeInstance, this);
+proto.model.Project.prototype.setDefaultRole = function(value) {
+  return jspb.Message.setProto3StringField(this, 12, value);
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.model.ProjectStaticUser} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * repeated ProjectEmailDomainRole email_domain_roles = 13;
+ * @return {!Array<!proto.model.ProjectEmailDomainRole>}
  */
-proto.model.ProjectStaticUser.toObject = function(includeInstance, msg) {
-  var f, obj = {
-    username: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    passwordHash: jspb.Message.getFieldWithDefault(msg, 2, "")
-  };
+proto.model.Project.prototype.getEmailDomainRolesList = function() {
+  return /** @type{!Array<!proto.model.ProjectEmailDomainRole>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.model.ProjectEmailDomainRole, 13));
+};
 
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+
+/**
+ * @param {!Array<!proto.model.ProjectEmailDomainRole>} value
+ * @return {!proto.model.Project} returns this
+*/
+proto.model.Project.prototype.setEmailDomainRolesList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 13, value);
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.model.ProjectStaticUser}
+ * @param {!proto.model.ProjectEmailDomainRole=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectEmailDomainRole}
  */
-proto.model.ProjectStaticUser.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.model.ProjectStaticUser;
-  return proto.model.ProjectStaticUser.deserializeBinaryFromReader(msg, reader);
+proto.model.Project.prototype.addEmailDomainRoles = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 13, opt_value, proto.model.ProjectEmailDomainRole, opt_index);
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.model.ProjectStaticUser} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.model.ProjectStaticUser}
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.Project} returns this
+ */
+proto.model.Project.prototype.clearEmailDomainRolesList = function() {
+  return this.setEmailDomainRolesList([]);
+};
+
+
+/**
+ * optional int64 created_at = 14;
+ * @return {number}
+ */
+proto.model.Project.prototype.getCreatedAt = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 14, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.Project} returns this
+ */
+proto.model.Project.prototype.setCreatedAt = function(value) {
+  return jspb.Message.setProto3IntField(this, 14, value);
+};
+
+
+/**
+ * optional int64 updated_at = 15;
+ * @return {number}
+ */
+proto.model.Project.prototype.getUpdatedAt = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 15, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.Project} returns this
+ */
+proto.model.Project.prototype.setUpdatedAt = function(value) {
+  return jspb.Message.setProto3IntField(this, 15, value);
+};
+
+
+/**
+ * optional int32 login_callback_rate_limit = 16;
+ * @return {number}
+ */
+proto.model.Project.prototype.getLoginCallbackRateLimit = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 16, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.Project} returns this
+ */
+proto.model.Project.prototype.setLoginCallbackRateLimit = function(value) {
+  return jspb.Message.setProto3IntField(this, 16, value);
+};
+
+
+/**
+ * optional int32 login_callback_rate_limit_burst = 17;
+ * @return {number}
+ */
+proto.model.Project.prototype.getLoginCallbackRateLimitBurst = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 17, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.Project} returns this
+ */
+proto.model.Project.prototype.setLoginCallbackRateLimitBurst = function(value) {
+  return jspb.Message.setProto3IntField(this, 17, value);
+};
+
+
+/**
+ * optional int32 login_lockout_threshold = 18;
+ * @return {number}
+ */
+proto.model.Project.prototype.getLoginLockoutThreshold = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 18, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.Project} returns this
+ */
+proto.model.Project.prototype.setLoginLockoutThreshold = function(value) {
+  return jspb.Message.setProto3IntField(this, 18, value);
+};
+
+
+/**
+ * optional int64 login_lockout_window = 19;
+ * @return {number}
+ */
+proto.model.Project.prototype.getLoginLockoutWindow = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 19, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.Project} returns this
+ */
+proto.model.Project.prototype.setLoginLockoutWindow = function(value) {
+  return jspb.Message.setProto3IntField(this, 19, value);
+};
+
+
+/**
+ * optional int64 login_lockout_cooldown = 20;
+ * @return {number}
+ */
+proto.model.Project.prototype.getLoginLockoutCooldown = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 20, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.Project} returns this
+ */
+proto.model.Project.prototype.setLoginLockoutCooldown = function(value) {
+  return jspb.Message.setProto3IntField(this, 20, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.model.ProjectStaticUser.prototype.toObject = function(opt_includeInstance) {
+  return proto.model.ProjectStaticUser.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.model.ProjectStaticUser} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectStaticUser.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    username: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    passwordHash: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.model.ProjectStaticUser}
+ */
+proto.model.ProjectStaticUser.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.model.ProjectStaticUser;
+  return proto.model.ProjectStaticUser.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.model.ProjectStaticUser} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.model.ProjectStaticUser}
  */
 proto.model.ProjectStaticUser.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
@@ -1007,6 +1444,13 @@ proto.model.ProjectStaticUser.prototype.setPasswordHash = function(value) {
 
 
 
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.model.ProjectSSOConfig.repeatedFields_ = [3];
+
 
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
@@ -1040,9 +1484,18 @@ proto.model.ProjectSSOConfig.toObject = function(includeInstance, msg) {
   var f, obj = {
     provider: jspb.Message.getFieldWithDefault(msg, 1, 0),
     sessionTtl: jspb.Message.getFieldWithDefault(msg, 2, 0),
+    allowedRedirectUrisList: (f = jspb.Message.getRepeatedField(msg, 3)) == null ? undefined : f,
     github: (f = msg.getGithub()) && proto.model.ProjectSSOConfig.GitHub.toObject(includeInstance, f),
     google: (f = msg.getGoogle()) && proto.model.ProjectSSOConfig.Google.toObject(includeInstance, f),
-    oidc: (f = msg.getOidc()) && proto.model.ProjectSSOConfig.Oidc.toObject(includeInstance, f)
+    oidc: (f = msg.getOidc()) && proto.model.ProjectSSOConfig.Oidc.toObject(includeInstance, f),
+    gitlab: (f = msg.getGitlab()) && proto.model.ProjectSSOConfig.GitLab.toObject(includeInstance, f),
+    azureAd: (f = msg.getAzureAd()) && proto.model.ProjectSSOConfig.AzureAD.toObject(includeInstance, f),
+    ldap: (f = msg.getLdap()) && proto.model.ProjectSSOConfig.Ldap.toObject(includeInstance, f),
+    saml: (f = msg.getSaml()) && proto.model.ProjectSSOConfig.Saml.toObject(includeInstance, f),
+    okta: (f = msg.getOkta()) && proto.model.ProjectSSOConfig.Okta.toObject(includeInstance, f),
+    bitbucket: (f = msg.getBitbucket()) && proto.model.ProjectSSOConfig.Bitbucket.toObject(includeInstance, f),
+    displayName: jspb.Message.getFieldWithDefault(msg, 19, ""),
+    icon: jspb.Message.getFieldWithDefault(msg, 20, "")
   };
 
   if (includeInstance) {
@@ -1087,6 +1540,10 @@ proto.model.ProjectSSOConfig.deserializeBinaryFromReader = function(msg, reader)
       var value = /** @type {number} */ (reader.readInt64());
       msg.setSessionTtl(value);
       break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addAllowedRedirectUris(value);
+      break;
     case 10:
       var value = new proto.model.ProjectSSOConfig.GitHub;
       reader.readMessage(value,proto.model.ProjectSSOConfig.GitHub.deserializeBinaryFromReader);
@@ -1102,6 +1559,44 @@ proto.model.ProjectSSOConfig.deserializeBinaryFromReader = function(msg, reader)
       reader.readMessage(value,proto.model.ProjectSSOConfig.Oidc.deserializeBinaryFromReader);
       msg.setOidc(value);
       break;
+    case 13:
+      var value = new proto.model.ProjectSSOConfig.GitLab;
+      reader.readMessage(value,proto.model.ProjectSSOConfig.GitLab.deserializeBinaryFromReader);
+      msg.setGitlab(value);
+      break;
+    case 14:
+      var value = new proto.model.ProjectSSOConfig.AzureAD;
+      reader.readMessage(value,proto.model.ProjectSSOConfig.AzureAD.deserializeBinaryFromReader);
+      msg.setAzureAd(value);
+      break;
+    case 15:
+      var value = new proto.model.ProjectSSOConfig.Ldap;
+      reader.readMessage(value,proto.model.ProjectSSOConfig.Ldap.deserializeBinaryFromReader);
+      msg.setLdap(value);
+      break;
+    case 16:
+      var value = new proto.model.ProjectSSOConfig.Saml;
+      reader.readMessage(value,proto.model.ProjectSSOConfig.Saml.deserializeBinaryFromReader);
+      msg.setSaml(value);
+      break;
+    case 17:
+      var value = new proto.model.ProjectSSOConfig.Okta;
+      reader.readMessage(value,proto.model.ProjectSSOConfig.Okta.deserializeBinaryFromReader);
+      msg.setOkta(value);
+      break;
+    case 18:
+      var value = new proto.model.ProjectSSOConfig.Bitbucket;
+      reader.readMessage(value,proto.model.ProjectSSOConfig.Bitbucket.deserializeBinaryFromReader);
+      msg.setBitbucket(value);
+      break;
+    case 19:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDisplayName(value);
+      break;
+    case 20:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setIcon(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1145,6 +1640,13 @@ proto.model.ProjectSSOConfig.serializeBinaryToWriter = function(message, writer)
       f
     );
   }
+  f = message.getAllowedRedirectUrisList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      3,
+      f
+    );
+  }
   f = message.getGithub();
   if (f != null) {
     writer.writeMessage(
@@ -1169,6 +1671,68 @@ proto.model.ProjectSSOConfig.serializeBinaryToWriter = function(message, writer)
       proto.model.ProjectSSOConfig.Oidc.serializeBinaryToWriter
     );
   }
+  f = message.getGitlab();
+  if (f != null) {
+    writer.writeMessage(
+      13,
+      f,
+      proto.model.ProjectSSOConfig.GitLab.serializeBinaryToWriter
+    );
+  }
+  f = message.getAzureAd();
+  if (f != null) {
+    writer.writeMessage(
+      14,
+      f,
+      proto.model.ProjectSSOConfig.AzureAD.serializeBinaryToWriter
+    );
+  }
+  f = message.getLdap();
+  if (f != null) {
+    writer.writeMessage(
+      15,
+      f,
+      proto.model.ProjectSSOConfig.Ldap.serializeBinaryToWriter
+    );
+  }
+  f = message.getSaml();
+  if (f != null) {
+    writer.writeMessage(
+      16,
+      f,
+      proto.model.ProjectSSOConfig.Saml.serializeBinaryToWriter
+    );
+  }
+  f = message.getOkta();
+  if (f != null) {
+    writer.writeMessage(
+      17,
+      f,
+      proto.model.ProjectSSOConfig.Okta.serializeBinaryToWriter
+    );
+  }
+  f = message.getBitbucket();
+  if (f != null) {
+    writer.writeMessage(
+      18,
+      f,
+      proto.model.ProjectSSOConfig.Bitbucket.serializeBinaryToWriter
+    );
+  }
+  f = message.getDisplayName();
+  if (f.length > 0) {
+    writer.writeString(
+      19,
+      f
+    );
+  }
+  f = message.getIcon();
+  if (f.length > 0) {
+    writer.writeString(
+      20,
+      f
+    );
+  }
 };
 
 
@@ -1178,10 +1742,23 @@ proto.model.ProjectSSOConfig.serializeBinaryToWriter = function(message, writer)
 proto.model.ProjectSSOConfig.Provider = {
   GITHUB: 0,
   GOOGLE: 2,
-  OIDC: 3
+  OIDC: 3,
+  GITLAB: 4,
+  AZUREAD: 5,
+  LDAP: 6,
+  SAML: 7,
+  OKTA: 8,
+  BITBUCKET: 9
 };
 
 
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.model.ProjectSSOConfig.GitHub.repeatedFields_ = [7];
+
 
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
@@ -1217,7 +1794,13 @@ proto.model.ProjectSSOConfig.GitHub.toObject = function(includeInstance, msg) {
     clientSecret: jspb.Message.getFieldWithDefault(msg, 2, ""),
     baseUrl: jspb.Message.getFieldWithDefault(msg, 3, ""),
     uploadUrl: jspb.Message.getFieldWithDefault(msg, 4, ""),
-    proxyUrl: jspb.Message.getFieldWithDefault(msg, 5, "")
+    proxyUrl: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    apiUrl: jspb.Message.getFieldWithDefault(msg, 6, ""),
+    requiredOrgsList: (f = jspb.Message.getRepeatedField(msg, 7)) == null ? undefined : f,
+    appId: jspb.Message.getFieldWithDefault(msg, 8, 0),
+    appInstallationId: jspb.Message.getFieldWithDefault(msg, 9, 0),
+    appPrivateKey: jspb.Message.getFieldWithDefault(msg, 10, ""),
+    extraHeadersMap: (f = msg.getExtraHeadersMap()) ? f.toObject(includeInstance, undefined) : []
   };
 
   if (includeInstance) {
@@ -1274,16 +1857,42 @@ proto.model.ProjectSSOConfig.GitHub.deserializeBinaryFromReader = function(msg,
       var value = /** @type {string} */ (reader.readString());
       msg.setProxyUrl(value);
       break;
-    default:
-      reader.skipField();
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setApiUrl(value);
       break;
-    }
-  }
-  return msg;
-};
-
-
-/**
+    case 7:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addRequiredOrgs(value);
+      break;
+    case 8:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setAppId(value);
+      break;
+    case 9:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setAppInstallationId(value);
+      break;
+    case 10:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAppPrivateKey(value);
+      break;
+    case 11:
+      var value = msg.getExtraHeadersMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
+         });
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
@@ -1338,6 +1947,45 @@ proto.model.ProjectSSOConfig.GitHub.serializeBinaryToWriter = function(message,
       f
     );
   }
+  f = message.getApiUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      6,
+      f
+    );
+  }
+  f = message.getRequiredOrgsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      7,
+      f
+    );
+  }
+  f = message.getAppId();
+  if (f !== 0) {
+    writer.writeInt64(
+      8,
+      f
+    );
+  }
+  f = message.getAppInstallationId();
+  if (f !== 0) {
+    writer.writeInt64(
+      9,
+      f
+    );
+  }
+  f = message.getAppPrivateKey();
+  if (f.length > 0) {
+    writer.writeString(
+      10,
+      f
+    );
+  }
+  f = message.getExtraHeadersMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(11, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
 };
 
 
@@ -1431,6 +2079,145 @@ proto.model.ProjectSSOConfig.GitHub.prototype.setProxyUrl = function(value) {
 };
 
 
+/**
+ * optional string api_url = 6;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.getApiUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.GitHub} returns this
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.setApiUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+/**
+ * repeated string required_orgs = 7;
+ * @return {!Array<string>}
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.getRequiredOrgsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 7));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.model.ProjectSSOConfig.GitHub} returns this
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.setRequiredOrgsList = function(value) {
+  return jspb.Message.setField(this, 7, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectSSOConfig.GitHub} returns this
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.addRequiredOrgs = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 7, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.ProjectSSOConfig.GitHub} returns this
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.clearRequiredOrgsList = function() {
+  return this.setRequiredOrgsList([]);
+};
+
+
+/**
+ * optional int64 app_id = 8;
+ * @return {number}
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.getAppId = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 8, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.ProjectSSOConfig.GitHub} returns this
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.setAppId = function(value) {
+  return jspb.Message.setProto3IntField(this, 8, value);
+};
+
+
+/**
+ * optional int64 app_installation_id = 9;
+ * @return {number}
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.getAppInstallationId = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 9, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.ProjectSSOConfig.GitHub} returns this
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.setAppInstallationId = function(value) {
+  return jspb.Message.setProto3IntField(this, 9, value);
+};
+
+
+/**
+ * optional string app_private_key = 10;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.getAppPrivateKey = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 10, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.GitHub} returns this
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.setAppPrivateKey = function(value) {
+  return jspb.Message.setProto3StringField(this, 10, value);
+};
+
+
+/**
+ * map<string, string> extra_headers = 11;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.getExtraHeadersMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 11, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.model.ProjectSSOConfig.GitHub} returns this
+ */
+proto.model.ProjectSSOConfig.GitHub.prototype.clearExtraHeadersMap = function() {
+  this.getExtraHeadersMap().clear();
+  return this;
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.model.ProjectSSOConfig.Google.repeatedFields_ = [4];
 
 
 
@@ -1464,7 +2251,10 @@ proto.model.ProjectSSOConfig.Google.prototype.toObject = function(opt_includeIns
 proto.model.ProjectSSOConfig.Google.toObject = function(includeInstance, msg) {
   var f, obj = {
     clientId: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    clientSecret: jspb.Message.getFieldWithDefault(msg, 2, "")
+    clientSecret: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    redirectUri: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    allowedDomainsList: (f = jspb.Message.getRepeatedField(msg, 4)) == null ? undefined : f,
+    proxyUrl: jspb.Message.getFieldWithDefault(msg, 5, "")
   };
 
   if (includeInstance) {
@@ -1509,6 +2299,18 @@ proto.model.ProjectSSOConfig.Google.deserializeBinaryFromReader = function(msg,
       var value = /** @type {string} */ (reader.readString());
       msg.setClientSecret(value);
       break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRedirectUri(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addAllowedDomains(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setProxyUrl(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1552,6 +2354,27 @@ proto.model.ProjectSSOConfig.Google.serializeBinaryToWriter = function(message,
       f
     );
   }
+  f = message.getRedirectUri();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getAllowedDomainsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      4,
+      f
+    );
+  }
+  f = message.getProxyUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
 };
 
 
@@ -1591,72 +2414,167 @@ proto.model.ProjectSSOConfig.Google.prototype.setClientSecret = function(value)
 };
 
 
-
 /**
- * List of repeated fields within this message type.
- * @private {!Array<number>}
- * @const
+ * optional string redirect_uri = 3;
+ * @return {string}
  */
-proto.model.ProjectSSOConfig.Oidc.repeatedFields_ = [9];
-
+proto.model.ProjectSSOConfig.Google.prototype.getRedirectUri = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
 
 
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Google} returns this
  */
-proto.model.ProjectSSOConfig.Oidc.prototype.toObject = function(opt_includeInstance) {
-  return proto.model.ProjectSSOConfig.Oidc.toObject(opt_includeInstance, this);
+proto.model.ProjectSSOConfig.Google.prototype.setRedirectUri = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.model.ProjectSSOConfig.Oidc} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * repeated string allowed_domains = 4;
+ * @return {!Array<string>}
  */
-proto.model.ProjectSSOConfig.Oidc.toObject = function(includeInstance, msg) {
-  var f, obj = {
-    clientId: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    clientSecret: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    issuer: jspb.Message.getFieldWithDefault(msg, 3, ""),
-    redirectUri: jspb.Message.getFieldWithDefault(msg, 4, ""),
-    authorizationEndpoint: jspb.Message.getFieldWithDefault(msg, 5, ""),
-    tokenEndpoint: jspb.Message.getFieldWithDefault(msg, 6, ""),
-    userInfoEndpoint: jspb.Message.getFieldWithDefault(msg, 7, ""),
-    proxyUrl: jspb.Message.getFieldWithDefault(msg, 8, ""),
-    scopesList: (f = jspb.Message.getRepeatedField(msg, 9)) == null ? undefined : f,
-    rolesClaimKey: jspb.Message.getFieldWithDefault(msg, 10, ""),
-    usernameClaimKey: jspb.Message.getFieldWithDefault(msg, 11, ""),
-    avatarUrlClaimKey: jspb.Message.getFieldWithDefault(msg, 12, "")
-  };
-
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+proto.model.ProjectSSOConfig.Google.prototype.getAllowedDomainsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 4));
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.model.ProjectSSOConfig.Oidc}
- */
+ * @param {!Array<string>} value
+ * @return {!proto.model.ProjectSSOConfig.Google} returns this
+ */
+proto.model.ProjectSSOConfig.Google.prototype.setAllowedDomainsList = function(value) {
+  return jspb.Message.setField(this, 4, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectSSOConfig.Google} returns this
+ */
+proto.model.ProjectSSOConfig.Google.prototype.addAllowedDomains = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 4, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.ProjectSSOConfig.Google} returns this
+ */
+proto.model.ProjectSSOConfig.Google.prototype.clearAllowedDomainsList = function() {
+  return this.setAllowedDomainsList([]);
+};
+
+
+/**
+ * optional string proxy_url = 5;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Google.prototype.getProxyUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Google} returns this
+ */
+proto.model.ProjectSSOConfig.Google.prototype.setProxyUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.model.ProjectSSOConfig.Oidc.repeatedFields_ = [9,18,20,28,34];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.toObject = function(opt_includeInstance) {
+  return proto.model.ProjectSSOConfig.Oidc.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.model.ProjectSSOConfig.Oidc} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.Oidc.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    clientId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    clientSecret: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    issuer: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    redirectUri: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    authorizationEndpoint: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    tokenEndpoint: jspb.Message.getFieldWithDefault(msg, 6, ""),
+    userInfoEndpoint: jspb.Message.getFieldWithDefault(msg, 7, ""),
+    proxyUrl: jspb.Message.getFieldWithDefault(msg, 8, ""),
+    scopesList: (f = jspb.Message.getRepeatedField(msg, 9)) == null ? undefined : f,
+    rolesClaimKey: jspb.Message.getFieldWithDefault(msg, 10, ""),
+    usernameClaimKey: jspb.Message.getFieldWithDefault(msg, 11, ""),
+    avatarUrlClaimKey: jspb.Message.getFieldWithDefault(msg, 12, ""),
+    pkceEnabled: jspb.Message.getBooleanFieldWithDefault(msg, 13, false),
+    pkceChallengeMethod: jspb.Message.getFieldWithDefault(msg, 14, ""),
+    disableNonceValidation: jspb.Message.getBooleanFieldWithDefault(msg, 15, false),
+    postLogoutRedirectUri: jspb.Message.getFieldWithDefault(msg, 16, ""),
+    endSessionEndpoint: jspb.Message.getFieldWithDefault(msg, 17, ""),
+    allowedAudiencesList: (f = jspb.Message.getRepeatedField(msg, 18)) == null ? undefined : f,
+    idTokenDecryptionKey: jspb.Message.getFieldWithDefault(msg, 19, ""),
+    idTokenContentEncryptionAlgorithmsList: (f = jspb.Message.getRepeatedField(msg, 20)) == null ? undefined : f,
+    requireVerifiedEmail: jspb.Message.getBooleanFieldWithDefault(msg, 21, false),
+    fetchUserinfo: jspb.Message.getBooleanFieldWithDefault(msg, 22, false),
+    allowIdpInitiatedLogin: jspb.Message.getBooleanFieldWithDefault(msg, 23, false),
+    clientCertificate: jspb.Message.getFieldWithDefault(msg, 24, ""),
+    clientKey: jspb.Message.getFieldWithDefault(msg, 25, ""),
+    clientAssertionKey: jspb.Message.getFieldWithDefault(msg, 26, ""),
+    clientAssertionSigningAlgorithm: jspb.Message.getFieldWithDefault(msg, 27, ""),
+    rolesClaimPathsList: (f = jspb.Message.getRepeatedField(msg, 28)) == null ? undefined : f,
+    maxAge: jspb.Message.getFieldWithDefault(msg, 29, 0),
+    groupsOverflowClaim: jspb.Message.getFieldWithDefault(msg, 30, ""),
+    groupsEndpoint: jspb.Message.getFieldWithDefault(msg, 31, ""),
+    maxGroups: jspb.Message.getFieldWithDefault(msg, 32, 0),
+    extraHeadersMap: (f = msg.getExtraHeadersMap()) ? f.toObject(includeInstance, undefined) : [],
+    roleSourceClaimsList: (f = jspb.Message.getRepeatedField(msg, 34)) == null ? undefined : f
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.model.ProjectSSOConfig.Oidc}
+ */
 proto.model.ProjectSSOConfig.Oidc.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
   var msg = new proto.model.ProjectSSOConfig.Oidc;
@@ -1726,6 +2644,96 @@ proto.model.ProjectSSOConfig.Oidc.deserializeBinaryFromReader = function(msg, re
       var value = /** @type {string} */ (reader.readString());
       msg.setAvatarUrlClaimKey(value);
       break;
+    case 13:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setPkceEnabled(value);
+      break;
+    case 14:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPkceChallengeMethod(value);
+      break;
+    case 15:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setDisableNonceValidation(value);
+      break;
+    case 16:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPostLogoutRedirectUri(value);
+      break;
+    case 17:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setEndSessionEndpoint(value);
+      break;
+    case 18:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addAllowedAudiences(value);
+      break;
+    case 19:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setIdTokenDecryptionKey(value);
+      break;
+    case 20:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addIdTokenContentEncryptionAlgorithms(value);
+      break;
+    case 21:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setRequireVerifiedEmail(value);
+      break;
+    case 22:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setFetchUserinfo(value);
+      break;
+    case 23:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setAllowIdpInitiatedLogin(value);
+      break;
+    case 24:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientCertificate(value);
+      break;
+    case 25:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientKey(value);
+      break;
+    case 26:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientAssertionKey(value);
+      break;
+    case 27:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientAssertionSigningAlgorithm(value);
+      break;
+    case 28:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addRolesClaimPaths(value);
+      break;
+    case 29:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setMaxAge(value);
+      break;
+    case 30:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setGroupsOverflowClaim(value);
+      break;
+    case 31:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setGroupsEndpoint(value);
+      break;
+    case 32:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setMaxGroups(value);
+      break;
+    case 33:
+      var value = msg.getExtraHeadersMap();
+      reader.readMessage(value, function(message, reader) {
+        jspb.Map.deserializeBinary(message, reader, jspb.BinaryReader.prototype.readString, jspb.BinaryReader.prototype.readString, null, "", "");
+         });
+      break;
+    case 34:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addRoleSourceClaims(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1839,6 +2847,157 @@ proto.model.ProjectSSOConfig.Oidc.serializeBinaryToWriter = function(message, wr
       f
     );
   }
+  f = message.getPkceEnabled();
+  if (f) {
+    writer.writeBool(
+      13,
+      f
+    );
+  }
+  f = message.getPkceChallengeMethod();
+  if (f.length > 0) {
+    writer.writeString(
+      14,
+      f
+    );
+  }
+  f = message.getDisableNonceValidation();
+  if (f) {
+    writer.writeBool(
+      15,
+      f
+    );
+  }
+  f = message.getPostLogoutRedirectUri();
+  if (f.length > 0) {
+    writer.writeString(
+      16,
+      f
+    );
+  }
+  f = message.getEndSessionEndpoint();
+  if (f.length > 0) {
+    writer.writeString(
+      17,
+      f
+    );
+  }
+  f = message.getAllowedAudiencesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      18,
+      f
+    );
+  }
+  f = message.getIdTokenDecryptionKey();
+  if (f.length > 0) {
+    writer.writeString(
+      19,
+      f
+    );
+  }
+  f = message.getIdTokenContentEncryptionAlgorithmsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      20,
+      f
+    );
+  }
+  f = message.getRequireVerifiedEmail();
+  if (f) {
+    writer.writeBool(
+      21,
+      f
+    );
+  }
+  f = message.getFetchUserinfo();
+  if (f) {
+    writer.writeBool(
+      22,
+      f
+    );
+  }
+  f = message.getAllowIdpInitiatedLogin();
+  if (f) {
+    writer.writeBool(
+      23,
+      f
+    );
+  }
+  f = message.getClientCertificate();
+  if (f.length > 0) {
+    writer.writeString(
+      24,
+      f
+    );
+  }
+  f = message.getClientKey();
+  if (f.length > 0) {
+    writer.writeString(
+      25,
+      f
+    );
+  }
+  f = message.getClientAssertionKey();
+  if (f.length > 0) {
+    writer.writeString(
+      26,
+      f
+    );
+  }
+  f = message.getClientAssertionSigningAlgorithm();
+  if (f.length > 0) {
+    writer.writeString(
+      27,
+      f
+    );
+  }
+  f = message.getRolesClaimPathsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      28,
+      f
+    );
+  }
+  f = message.getMaxAge();
+  if (f !== 0) {
+    writer.writeInt64(
+      29,
+      f
+    );
+  }
+  f = message.getGroupsOverflowClaim();
+  if (f.length > 0) {
+    writer.writeString(
+      30,
+      f
+    );
+  }
+  f = message.getGroupsEndpoint();
+  if (f.length > 0) {
+    writer.writeString(
+      31,
+      f
+    );
+  }
+  f = message.getMaxGroups();
+  if (f !== 0) {
+    writer.writeInt32(
+      32,
+      f
+    );
+  }
+  f = message.getExtraHeadersMap(true);
+  if (f && f.getLength() > 0) {
+    f.serializeBinary(33, writer, jspb.BinaryWriter.prototype.writeString, jspb.BinaryWriter.prototype.writeString);
+  }
+  f = message.getRoleSourceClaimsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      34,
+      f
+    );
+  }
 };
 
 
@@ -2033,102 +3192,2911 @@ proto.model.ProjectSSOConfig.Oidc.prototype.getRolesClaimKey = function() {
 
 
 /**
- * @param {string} value
- * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setRolesClaimKey = function(value) {
+  return jspb.Message.setProto3StringField(this, 10, value);
+};
+
+
+/**
+ * optional string username_claim_key = 11;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getUsernameClaimKey = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 11, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setUsernameClaimKey = function(value) {
+  return jspb.Message.setProto3StringField(this, 11, value);
+};
+
+
+/**
+ * optional string avatar_url_claim_key = 12;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getAvatarUrlClaimKey = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 12, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setAvatarUrlClaimKey = function(value) {
+  return jspb.Message.setProto3StringField(this, 12, value);
+};
+
+
+/**
+ * optional bool pkce_enabled = 13;
+ * @return {boolean}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getPkceEnabled = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 13, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setPkceEnabled = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 13, value);
+};
+
+
+/**
+ * optional string pkce_challenge_method = 14;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getPkceChallengeMethod = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 14, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setPkceChallengeMethod = function(value) {
+  return jspb.Message.setProto3StringField(this, 14, value);
+};
+
+
+/**
+ * optional bool disable_nonce_validation = 15;
+ * @return {boolean}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getDisableNonceValidation = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 15, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setDisableNonceValidation = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 15, value);
+};
+
+
+/**
+ * optional string post_logout_redirect_uri = 16;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getPostLogoutRedirectUri = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 16, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setPostLogoutRedirectUri = function(value) {
+  return jspb.Message.setProto3StringField(this, 16, value);
+};
+
+
+/**
+ * optional string end_session_endpoint = 17;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getEndSessionEndpoint = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 17, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setEndSessionEndpoint = function(value) {
+  return jspb.Message.setProto3StringField(this, 17, value);
+};
+
+
+/**
+ * repeated string allowed_audiences = 18;
+ * @return {!Array<string>}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getAllowedAudiencesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 18));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setAllowedAudiencesList = function(value) {
+  return jspb.Message.setField(this, 18, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.addAllowedAudiences = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 18, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.clearAllowedAudiencesList = function() {
+  return this.setAllowedAudiencesList([]);
+};
+
+
+/**
+ * optional string id_token_decryption_key = 19;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getIdTokenDecryptionKey = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 19, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setIdTokenDecryptionKey = function(value) {
+  return jspb.Message.setProto3StringField(this, 19, value);
+};
+
+
+/**
+ * repeated string id_token_content_encryption_algorithms = 20;
+ * @return {!Array<string>}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getIdTokenContentEncryptionAlgorithmsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 20));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setIdTokenContentEncryptionAlgorithmsList = function(value) {
+  return jspb.Message.setField(this, 20, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.addIdTokenContentEncryptionAlgorithms = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 20, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.clearIdTokenContentEncryptionAlgorithmsList = function() {
+  return this.setIdTokenContentEncryptionAlgorithmsList([]);
+};
+
+
+/**
+ * optional bool require_verified_email = 21;
+ * @return {boolean}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getRequireVerifiedEmail = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 21, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setRequireVerifiedEmail = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 21, value);
+};
+
+
+/**
+ * optional bool fetch_userinfo = 22;
+ * @return {boolean}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getFetchUserinfo = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 22, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setFetchUserinfo = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 22, value);
+};
+
+
+/**
+ * optional bool allow_idp_initiated_login = 23;
+ * @return {boolean}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getAllowIdpInitiatedLogin = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 23, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setAllowIdpInitiatedLogin = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 23, value);
+};
+
+
+/**
+ * optional string client_certificate = 24;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getClientCertificate = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 24, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setClientCertificate = function(value) {
+  return jspb.Message.setProto3StringField(this, 24, value);
+};
+
+
+/**
+ * optional string client_key = 25;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getClientKey = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 25, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setClientKey = function(value) {
+  return jspb.Message.setProto3StringField(this, 25, value);
+};
+
+
+/**
+ * optional string client_assertion_key = 26;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getClientAssertionKey = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 26, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setClientAssertionKey = function(value) {
+  return jspb.Message.setProto3StringField(this, 26, value);
+};
+
+
+/**
+ * optional string client_assertion_signing_algorithm = 27;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getClientAssertionSigningAlgorithm = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 27, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setClientAssertionSigningAlgorithm = function(value) {
+  return jspb.Message.setProto3StringField(this, 27, value);
+};
+
+
+/**
+ * repeated string roles_claim_paths = 28;
+ * @return {!Array<string>}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getRolesClaimPathsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 28));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setRolesClaimPathsList = function(value) {
+  return jspb.Message.setField(this, 28, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.addRolesClaimPaths = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 28, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.clearRolesClaimPathsList = function() {
+  return this.setRolesClaimPathsList([]);
+};
+
+
+/**
+ * optional int64 max_age = 29;
+ * @return {number}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getMaxAge = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 29, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setMaxAge = function(value) {
+  return jspb.Message.setProto3IntField(this, 29, value);
+};
+
+
+/**
+ * optional string groups_overflow_claim = 30;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getGroupsOverflowClaim = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 30, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setGroupsOverflowClaim = function(value) {
+  return jspb.Message.setProto3StringField(this, 30, value);
+};
+
+
+/**
+ * optional string groups_endpoint = 31;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getGroupsEndpoint = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 31, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setGroupsEndpoint = function(value) {
+  return jspb.Message.setProto3StringField(this, 31, value);
+};
+
+
+/**
+ * optional int32 max_groups = 32;
+ * @return {number}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getMaxGroups = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 32, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setMaxGroups = function(value) {
+  return jspb.Message.setProto3IntField(this, 32, value);
+};
+
+
+/**
+ * map<string, string> extra_headers = 33;
+ * @param {boolean=} opt_noLazyCreate Do not create the map if
+ * empty, instead returning `undefined`
+ * @return {!jspb.Map<string,string>}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getExtraHeadersMap = function(opt_noLazyCreate) {
+  return /** @type {!jspb.Map<string,string>} */ (
+      jspb.Message.getMapField(this, 33, opt_noLazyCreate,
+      null));
+};
+
+
+/**
+ * Clears values from the map. The map will be non-null.
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.clearExtraHeadersMap = function() {
+  this.getExtraHeadersMap().clear();
+  return this;
+};
+
+
+/**
+ * repeated string role_source_claims = 34;
+ * @return {!Array<string>}
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.getRoleSourceClaimsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 34));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.setRoleSourceClaimsList = function(value) {
+  return jspb.Message.setField(this, 34, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.addRoleSourceClaims = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 34, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ */
+proto.model.ProjectSSOConfig.Oidc.prototype.clearRoleSourceClaimsList = function() {
+  return this.setRoleSourceClaimsList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.toObject = function(opt_includeInstance) {
+  return proto.model.ProjectSSOConfig.GitLab.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.model.ProjectSSOConfig.GitLab} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.GitLab.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    clientId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    clientSecret: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    baseUrl: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    redirectUri: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    proxyUrl: jspb.Message.getFieldWithDefault(msg, 5, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.model.ProjectSSOConfig.GitLab}
+ */
+proto.model.ProjectSSOConfig.GitLab.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.model.ProjectSSOConfig.GitLab;
+  return proto.model.ProjectSSOConfig.GitLab.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.model.ProjectSSOConfig.GitLab} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.model.ProjectSSOConfig.GitLab}
+ */
+proto.model.ProjectSSOConfig.GitLab.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientSecret(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setBaseUrl(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRedirectUri(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setProxyUrl(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.model.ProjectSSOConfig.GitLab.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.model.ProjectSSOConfig.GitLab} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.GitLab.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getClientId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getClientSecret();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getBaseUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getRedirectUri();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getProxyUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string client_id = 1;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.getClientId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.GitLab} returns this
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.setClientId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string client_secret = 2;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.getClientSecret = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.GitLab} returns this
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.setClientSecret = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string base_url = 3;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.getBaseUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.GitLab} returns this
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.setBaseUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string redirect_uri = 4;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.getRedirectUri = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.GitLab} returns this
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.setRedirectUri = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional string proxy_url = 5;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.getProxyUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.GitLab} returns this
+ */
+proto.model.ProjectSSOConfig.GitLab.prototype.setProxyUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.model.ProjectSSOConfig.AzureAD.repeatedFields_ = [5];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.toObject = function(opt_includeInstance) {
+  return proto.model.ProjectSSOConfig.AzureAD.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.model.ProjectSSOConfig.AzureAD} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.AzureAD.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    clientId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    clientSecret: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    tenant: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    redirectUri: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    allowedTenantsList: (f = jspb.Message.getRepeatedField(msg, 5)) == null ? undefined : f,
+    proxyUrl: jspb.Message.getFieldWithDefault(msg, 6, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.model.ProjectSSOConfig.AzureAD}
+ */
+proto.model.ProjectSSOConfig.AzureAD.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.model.ProjectSSOConfig.AzureAD;
+  return proto.model.ProjectSSOConfig.AzureAD.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.model.ProjectSSOConfig.AzureAD} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.model.ProjectSSOConfig.AzureAD}
+ */
+proto.model.ProjectSSOConfig.AzureAD.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientSecret(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTenant(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRedirectUri(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addAllowedTenants(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setProxyUrl(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.model.ProjectSSOConfig.AzureAD.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.model.ProjectSSOConfig.AzureAD} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.AzureAD.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getClientId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getClientSecret();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getTenant();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getRedirectUri();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getAllowedTenantsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      5,
+      f
+    );
+  }
+  f = message.getProxyUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      6,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string client_id = 1;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.getClientId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.AzureAD} returns this
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.setClientId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string client_secret = 2;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.getClientSecret = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.AzureAD} returns this
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.setClientSecret = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string tenant = 3;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.getTenant = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.AzureAD} returns this
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.setTenant = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string redirect_uri = 4;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.getRedirectUri = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.AzureAD} returns this
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.setRedirectUri = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * repeated string allowed_tenants = 5;
+ * @return {!Array<string>}
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.getAllowedTenantsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 5));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.model.ProjectSSOConfig.AzureAD} returns this
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.setAllowedTenantsList = function(value) {
+  return jspb.Message.setField(this, 5, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectSSOConfig.AzureAD} returns this
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.addAllowedTenants = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 5, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.ProjectSSOConfig.AzureAD} returns this
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.clearAllowedTenantsList = function() {
+  return this.setAllowedTenantsList([]);
+};
+
+
+/**
+ * optional string proxy_url = 6;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.getProxyUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.AzureAD} returns this
+ */
+proto.model.ProjectSSOConfig.AzureAD.prototype.setProxyUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.toObject = function(opt_includeInstance) {
+  return proto.model.ProjectSSOConfig.Ldap.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.model.ProjectSSOConfig.Ldap} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.Ldap.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    url: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    startTls: jspb.Message.getBooleanFieldWithDefault(msg, 2, false),
+    rootCa: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    bindDn: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    bindPassword: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    userSearchBase: jspb.Message.getFieldWithDefault(msg, 6, ""),
+    userFilter: jspb.Message.getFieldWithDefault(msg, 7, ""),
+    groupSearchBase: jspb.Message.getFieldWithDefault(msg, 8, ""),
+    groupFilter: jspb.Message.getFieldWithDefault(msg, 9, ""),
+    groupNameAttribute: jspb.Message.getFieldWithDefault(msg, 10, ""),
+    poolSize: jspb.Message.getFieldWithDefault(msg, 11, 0)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.model.ProjectSSOConfig.Ldap}
+ */
+proto.model.ProjectSSOConfig.Ldap.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.model.ProjectSSOConfig.Ldap;
+  return proto.model.ProjectSSOConfig.Ldap.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.model.ProjectSSOConfig.Ldap} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.model.ProjectSSOConfig.Ldap}
+ */
+proto.model.ProjectSSOConfig.Ldap.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUrl(value);
+      break;
+    case 2:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setStartTls(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRootCa(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setBindDn(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setBindPassword(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUserSearchBase(value);
+      break;
+    case 7:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUserFilter(value);
+      break;
+    case 8:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setGroupSearchBase(value);
+      break;
+    case 9:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setGroupFilter(value);
+      break;
+    case 10:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setGroupNameAttribute(value);
+      break;
+    case 11:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setPoolSize(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.model.ProjectSSOConfig.Ldap.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.model.ProjectSSOConfig.Ldap} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.Ldap.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getStartTls();
+  if (f) {
+    writer.writeBool(
+      2,
+      f
+    );
+  }
+  f = message.getRootCa();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getBindDn();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getBindPassword();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+  f = message.getUserSearchBase();
+  if (f.length > 0) {
+    writer.writeString(
+      6,
+      f
+    );
+  }
+  f = message.getUserFilter();
+  if (f.length > 0) {
+    writer.writeString(
+      7,
+      f
+    );
+  }
+  f = message.getGroupSearchBase();
+  if (f.length > 0) {
+    writer.writeString(
+      8,
+      f
+    );
+  }
+  f = message.getGroupFilter();
+  if (f.length > 0) {
+    writer.writeString(
+      9,
+      f
+    );
+  }
+  f = message.getGroupNameAttribute();
+  if (f.length > 0) {
+    writer.writeString(
+      10,
+      f
+    );
+  }
+  f = message.getPoolSize();
+  if (f !== 0) {
+    writer.writeInt32(
+      11,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string url = 1;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional bool start_tls = 2;
+ * @return {boolean}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getStartTls = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 2, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setStartTls = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 2, value);
+};
+
+
+/**
+ * optional string root_ca = 3;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getRootCa = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setRootCa = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string bind_dn = 4;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getBindDn = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setBindDn = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional string bind_password = 5;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getBindPassword = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setBindPassword = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+/**
+ * optional string user_search_base = 6;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getUserSearchBase = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setUserSearchBase = function(value) {
+  return jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+/**
+ * optional string user_filter = 7;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getUserFilter = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setUserFilter = function(value) {
+  return jspb.Message.setProto3StringField(this, 7, value);
+};
+
+
+/**
+ * optional string group_search_base = 8;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getGroupSearchBase = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setGroupSearchBase = function(value) {
+  return jspb.Message.setProto3StringField(this, 8, value);
+};
+
+
+/**
+ * optional string group_filter = 9;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getGroupFilter = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 9, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setGroupFilter = function(value) {
+  return jspb.Message.setProto3StringField(this, 9, value);
+};
+
+
+/**
+ * optional string group_name_attribute = 10;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getGroupNameAttribute = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 10, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setGroupNameAttribute = function(value) {
+  return jspb.Message.setProto3StringField(this, 10, value);
+};
+
+
+/**
+ * optional int32 pool_size = 11;
+ * @return {number}
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.getPoolSize = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 11, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.ProjectSSOConfig.Ldap} returns this
+ */
+proto.model.ProjectSSOConfig.Ldap.prototype.setPoolSize = function(value) {
+  return jspb.Message.setProto3IntField(this, 11, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.model.ProjectSSOConfig.Okta.repeatedFields_ = [7];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.toObject = function(opt_includeInstance) {
+  return proto.model.ProjectSSOConfig.Okta.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.model.ProjectSSOConfig.Okta} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.Okta.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    clientId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    clientSecret: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    domain: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    authorizationServerId: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    redirectUri: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    groupsClaim: jspb.Message.getFieldWithDefault(msg, 6, ""),
+    scopesList: (f = jspb.Message.getRepeatedField(msg, 7)) == null ? undefined : f,
+    proxyUrl: jspb.Message.getFieldWithDefault(msg, 8, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.model.ProjectSSOConfig.Okta}
+ */
+proto.model.ProjectSSOConfig.Okta.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.model.ProjectSSOConfig.Okta;
+  return proto.model.ProjectSSOConfig.Okta.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.model.ProjectSSOConfig.Okta} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.model.ProjectSSOConfig.Okta}
+ */
+proto.model.ProjectSSOConfig.Okta.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientSecret(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDomain(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAuthorizationServerId(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRedirectUri(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setGroupsClaim(value);
+      break;
+    case 7:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addScopes(value);
+      break;
+    case 8:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setProxyUrl(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.model.ProjectSSOConfig.Okta.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.model.ProjectSSOConfig.Okta} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.Okta.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getClientId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getClientSecret();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getDomain();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getAuthorizationServerId();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getRedirectUri();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+  f = message.getGroupsClaim();
+  if (f.length > 0) {
+    writer.writeString(
+      6,
+      f
+    );
+  }
+  f = message.getScopesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      7,
+      f
+    );
+  }
+  f = message.getProxyUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      8,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string client_id = 1;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.getClientId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Okta} returns this
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.setClientId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string client_secret = 2;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.getClientSecret = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Okta} returns this
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.setClientSecret = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string domain = 3;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.getDomain = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Okta} returns this
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.setDomain = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string authorization_server_id = 4;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.getAuthorizationServerId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Okta} returns this
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.setAuthorizationServerId = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional string redirect_uri = 5;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.getRedirectUri = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Okta} returns this
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.setRedirectUri = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+/**
+ * optional string groups_claim = 6;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.getGroupsClaim = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Okta} returns this
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.setGroupsClaim = function(value) {
+  return jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+/**
+ * repeated string scopes = 7;
+ * @return {!Array<string>}
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.getScopesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 7));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.model.ProjectSSOConfig.Okta} returns this
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.setScopesList = function(value) {
+  return jspb.Message.setField(this, 7, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectSSOConfig.Okta} returns this
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.addScopes = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 7, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.ProjectSSOConfig.Okta} returns this
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.clearScopesList = function() {
+  return this.setScopesList([]);
+};
+
+
+/**
+ * optional string proxy_url = 8;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.getProxyUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Okta} returns this
+ */
+proto.model.ProjectSSOConfig.Okta.prototype.setProxyUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 8, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.model.ProjectSSOConfig.Bitbucket.repeatedFields_ = [6];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.toObject = function(opt_includeInstance) {
+  return proto.model.ProjectSSOConfig.Bitbucket.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.model.ProjectSSOConfig.Bitbucket} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.Bitbucket.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    clientId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    clientSecret: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    flavor: jspb.Message.getFieldWithDefault(msg, 3, 0),
+    baseUrl: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    redirectUri: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    scopesList: (f = jspb.Message.getRepeatedField(msg, 6)) == null ? undefined : f,
+    proxyUrl: jspb.Message.getFieldWithDefault(msg, 7, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.model.ProjectSSOConfig.Bitbucket;
+  return proto.model.ProjectSSOConfig.Bitbucket.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.model.ProjectSSOConfig.Bitbucket} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setClientSecret(value);
+      break;
+    case 3:
+      var value = /** @type {!proto.model.ProjectSSOConfig.Bitbucket.Flavor} */ (reader.readEnum());
+      msg.setFlavor(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setBaseUrl(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRedirectUri(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addScopes(value);
+      break;
+    case 7:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setProxyUrl(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.model.ProjectSSOConfig.Bitbucket.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.model.ProjectSSOConfig.Bitbucket} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.Bitbucket.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getClientId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getClientSecret();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getFlavor();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      3,
+      f
+    );
+  }
+  f = message.getBaseUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getRedirectUri();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+  f = message.getScopesList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      6,
+      f
+    );
+  }
+  f = message.getProxyUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      7,
+      f
+    );
+  }
+};
+
+
+/**
+ * @enum {number}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.Flavor = {
+  CLOUD: 0,
+  SERVER: 1
+};
+
+/**
+ * optional string client_id = 1;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.getClientId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket} returns this
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.setClientId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string client_secret = 2;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.getClientSecret = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket} returns this
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.setClientSecret = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional Flavor flavor = 3;
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket.Flavor}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.getFlavor = function() {
+  return /** @type {!proto.model.ProjectSSOConfig.Bitbucket.Flavor} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/**
+ * @param {!proto.model.ProjectSSOConfig.Bitbucket.Flavor} value
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket} returns this
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.setFlavor = function(value) {
+  return jspb.Message.setProto3EnumField(this, 3, value);
+};
+
+
+/**
+ * optional string base_url = 4;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.getBaseUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket} returns this
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.setBaseUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional string redirect_uri = 5;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.getRedirectUri = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket} returns this
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.setRedirectUri = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+/**
+ * repeated string scopes = 6;
+ * @return {!Array<string>}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.getScopesList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 6));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket} returns this
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.setScopesList = function(value) {
+  return jspb.Message.setField(this, 6, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket} returns this
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.addScopes = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 6, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket} returns this
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.clearScopesList = function() {
+  return this.setScopesList([]);
+};
+
+
+/**
+ * optional string proxy_url = 7;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.getProxyUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Bitbucket} returns this
+ */
+proto.model.ProjectSSOConfig.Bitbucket.prototype.setProxyUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 7, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.toObject = function(opt_includeInstance) {
+  return proto.model.ProjectSSOConfig.Saml.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.model.ProjectSSOConfig.Saml} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.Saml.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    idpMetadataUrl: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    idpMetadata: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    entityId: jspb.Message.getFieldWithDefault(msg, 3, ""),
+    usernameAttribute: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    avatarUrlAttribute: jspb.Message.getFieldWithDefault(msg, 5, ""),
+    groupsAttribute: jspb.Message.getFieldWithDefault(msg, 6, ""),
+    certificate: jspb.Message.getFieldWithDefault(msg, 7, ""),
+    privateKey: jspb.Message.getFieldWithDefault(msg, 8, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.model.ProjectSSOConfig.Saml}
+ */
+proto.model.ProjectSSOConfig.Saml.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.model.ProjectSSOConfig.Saml;
+  return proto.model.ProjectSSOConfig.Saml.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.model.ProjectSSOConfig.Saml} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.model.ProjectSSOConfig.Saml}
+ */
+proto.model.ProjectSSOConfig.Saml.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setIdpMetadataUrl(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setIdpMetadata(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setEntityId(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setUsernameAttribute(value);
+      break;
+    case 5:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAvatarUrlAttribute(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setGroupsAttribute(value);
+      break;
+    case 7:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setCertificate(value);
+      break;
+    case 8:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setPrivateKey(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.model.ProjectSSOConfig.Saml.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.model.ProjectSSOConfig.Saml} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectSSOConfig.Saml.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getIdpMetadataUrl();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getIdpMetadata();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getEntityId();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getUsernameAttribute();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getAvatarUrlAttribute();
+  if (f.length > 0) {
+    writer.writeString(
+      5,
+      f
+    );
+  }
+  f = message.getGroupsAttribute();
+  if (f.length > 0) {
+    writer.writeString(
+      6,
+      f
+    );
+  }
+  f = message.getCertificate();
+  if (f.length > 0) {
+    writer.writeString(
+      7,
+      f
+    );
+  }
+  f = message.getPrivateKey();
+  if (f.length > 0) {
+    writer.writeString(
+      8,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string idp_metadata_url = 1;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.getIdpMetadataUrl = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Saml} returns this
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.setIdpMetadataUrl = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string idp_metadata = 2;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.getIdpMetadata = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Saml} returns this
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.setIdpMetadata = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string entity_id = 3;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.getEntityId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Saml} returns this
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.setEntityId = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional string username_attribute = 4;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.getUsernameAttribute = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Saml} returns this
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.setUsernameAttribute = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional string avatar_url_attribute = 5;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.getAvatarUrlAttribute = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 5, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Saml} returns this
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.setAvatarUrlAttribute = function(value) {
+  return jspb.Message.setProto3StringField(this, 5, value);
+};
+
+
+/**
+ * optional string groups_attribute = 6;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.getGroupsAttribute = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 6, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Saml} returns this
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.setGroupsAttribute = function(value) {
+  return jspb.Message.setProto3StringField(this, 6, value);
+};
+
+
+/**
+ * optional string certificate = 7;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.getCertificate = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 7, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Saml} returns this
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.setCertificate = function(value) {
+  return jspb.Message.setProto3StringField(this, 7, value);
+};
+
+
+/**
+ * optional string private_key = 8;
+ * @return {string}
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.getPrivateKey = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 8, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig.Saml} returns this
+ */
+proto.model.ProjectSSOConfig.Saml.prototype.setPrivateKey = function(value) {
+  return jspb.Message.setProto3StringField(this, 8, value);
+};
+
+
+/**
+ * optional Provider provider = 1;
+ * @return {!proto.model.ProjectSSOConfig.Provider}
+ */
+proto.model.ProjectSSOConfig.prototype.getProvider = function() {
+  return /** @type {!proto.model.ProjectSSOConfig.Provider} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+};
+
+
+/**
+ * @param {!proto.model.ProjectSSOConfig.Provider} value
+ * @return {!proto.model.ProjectSSOConfig} returns this
+ */
+proto.model.ProjectSSOConfig.prototype.setProvider = function(value) {
+  return jspb.Message.setProto3EnumField(this, 1, value);
+};
+
+
+/**
+ * optional int64 session_ttl = 2;
+ * @return {number}
+ */
+proto.model.ProjectSSOConfig.prototype.getSessionTtl = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.ProjectSSOConfig} returns this
+ */
+proto.model.ProjectSSOConfig.prototype.setSessionTtl = function(value) {
+  return jspb.Message.setProto3IntField(this, 2, value);
+};
+
+
+/**
+ * repeated string allowed_redirect_uris = 3;
+ * @return {!Array<string>}
+ */
+proto.model.ProjectSSOConfig.prototype.getAllowedRedirectUrisList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 3));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.model.ProjectSSOConfig} returns this
+ */
+proto.model.ProjectSSOConfig.prototype.setAllowedRedirectUrisList = function(value) {
+  return jspb.Message.setField(this, 3, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.ProjectSSOConfig} returns this
+ */
+proto.model.ProjectSSOConfig.prototype.addAllowedRedirectUris = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 3, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.ProjectSSOConfig} returns this
+ */
+proto.model.ProjectSSOConfig.prototype.clearAllowedRedirectUrisList = function() {
+  return this.setAllowedRedirectUrisList([]);
+};
+
+
+/**
+ * optional GitHub github = 10;
+ * @return {?proto.model.ProjectSSOConfig.GitHub}
+ */
+proto.model.ProjectSSOConfig.prototype.getGithub = function() {
+  return /** @type{?proto.model.ProjectSSOConfig.GitHub} */ (
+    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.GitHub, 10));
+};
+
+
+/**
+ * @param {?proto.model.ProjectSSOConfig.GitHub|undefined} value
+ * @return {!proto.model.ProjectSSOConfig} returns this
+*/
+proto.model.ProjectSSOConfig.prototype.setGithub = function(value) {
+  return jspb.Message.setWrapperField(this, 10, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.model.ProjectSSOConfig} returns this
+ */
+proto.model.ProjectSSOConfig.prototype.clearGithub = function() {
+  return this.setGithub(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.model.ProjectSSOConfig.prototype.hasGithub = function() {
+  return jspb.Message.getField(this, 10) != null;
+};
+
+
+/**
+ * optional Google google = 11;
+ * @return {?proto.model.ProjectSSOConfig.Google}
+ */
+proto.model.ProjectSSOConfig.prototype.getGoogle = function() {
+  return /** @type{?proto.model.ProjectSSOConfig.Google} */ (
+    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.Google, 11));
+};
+
+
+/**
+ * @param {?proto.model.ProjectSSOConfig.Google|undefined} value
+ * @return {!proto.model.ProjectSSOConfig} returns this
+*/
+proto.model.ProjectSSOConfig.prototype.setGoogle = function(value) {
+  return jspb.Message.setWrapperField(this, 11, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.model.ProjectSSOConfig} returns this
+ */
+proto.model.ProjectSSOConfig.prototype.clearGoogle = function() {
+  return this.setGoogle(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.model.ProjectSSOConfig.prototype.hasGoogle = function() {
+  return jspb.Message.getField(this, 11) != null;
+};
+
+
+/**
+ * optional Oidc oidc = 12;
+ * @return {?proto.model.ProjectSSOConfig.Oidc}
+ */
+proto.model.ProjectSSOConfig.prototype.getOidc = function() {
+  return /** @type{?proto.model.ProjectSSOConfig.Oidc} */ (
+    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.Oidc, 12));
+};
+
+
+/**
+ * @param {?proto.model.ProjectSSOConfig.Oidc|undefined} value
+ * @return {!proto.model.ProjectSSOConfig} returns this
+*/
+proto.model.ProjectSSOConfig.prototype.setOidc = function(value) {
+  return jspb.Message.setWrapperField(this, 12, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.model.ProjectSSOConfig} returns this
+ */
+proto.model.ProjectSSOConfig.prototype.clearOidc = function() {
+  return this.setOidc(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.model.ProjectSSOConfig.prototype.hasOidc = function() {
+  return jspb.Message.getField(this, 12) != null;
+};
+
+
+/**
+ * optional GitLab gitlab = 13;
+ * @return {?proto.model.ProjectSSOConfig.GitLab}
+ */
+proto.model.ProjectSSOConfig.prototype.getGitlab = function() {
+  return /** @type{?proto.model.ProjectSSOConfig.GitLab} */ (
+    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.GitLab, 13));
+};
+
+
+/**
+ * @param {?proto.model.ProjectSSOConfig.GitLab|undefined} value
+ * @return {!proto.model.ProjectSSOConfig} returns this
+*/
+proto.model.ProjectSSOConfig.prototype.setGitlab = function(value) {
+  return jspb.Message.setWrapperField(this, 13, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.model.ProjectSSOConfig} returns this
+ */
+proto.model.ProjectSSOConfig.prototype.clearGitlab = function() {
+  return this.setGitlab(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.model.ProjectSSOConfig.prototype.hasGitlab = function() {
+  return jspb.Message.getField(this, 13) != null;
+};
+
+
+/**
+ * optional AzureAD azure_ad = 14;
+ * @return {?proto.model.ProjectSSOConfig.AzureAD}
+ */
+proto.model.ProjectSSOConfig.prototype.getAzureAd = function() {
+  return /** @type{?proto.model.ProjectSSOConfig.AzureAD} */ (
+    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.AzureAD, 14));
+};
+
+
+/**
+ * @param {?proto.model.ProjectSSOConfig.AzureAD|undefined} value
+ * @return {!proto.model.ProjectSSOConfig} returns this
+*/
+proto.model.ProjectSSOConfig.prototype.setAzureAd = function(value) {
+  return jspb.Message.setWrapperField(this, 14, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.model.ProjectSSOConfig} returns this
+ */
+proto.model.ProjectSSOConfig.prototype.clearAzureAd = function() {
+  return this.setAzureAd(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.model.ProjectSSOConfig.Oidc.prototype.setRolesClaimKey = function(value) {
-  return jspb.Message.setProto3StringField(this, 10, value);
+proto.model.ProjectSSOConfig.prototype.hasAzureAd = function() {
+  return jspb.Message.getField(this, 14) != null;
 };
 
 
 /**
- * optional string username_claim_key = 11;
- * @return {string}
+ * optional Ldap ldap = 15;
+ * @return {?proto.model.ProjectSSOConfig.Ldap}
  */
-proto.model.ProjectSSOConfig.Oidc.prototype.getUsernameClaimKey = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 11, ""));
+proto.model.ProjectSSOConfig.prototype.getLdap = function() {
+  return /** @type{?proto.model.ProjectSSOConfig.Ldap} */ (
+    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.Ldap, 15));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
- */
-proto.model.ProjectSSOConfig.Oidc.prototype.setUsernameClaimKey = function(value) {
-  return jspb.Message.setProto3StringField(this, 11, value);
+ * @param {?proto.model.ProjectSSOConfig.Ldap|undefined} value
+ * @return {!proto.model.ProjectSSOConfig} returns this
+*/
+proto.model.ProjectSSOConfig.prototype.setLdap = function(value) {
+  return jspb.Message.setWrapperField(this, 15, value);
 };
 
 
 /**
- * optional string avatar_url_claim_key = 12;
- * @return {string}
+ * Clears the message field making it undefined.
+ * @return {!proto.model.ProjectSSOConfig} returns this
  */
-proto.model.ProjectSSOConfig.Oidc.prototype.getAvatarUrlClaimKey = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 12, ""));
+proto.model.ProjectSSOConfig.prototype.clearLdap = function() {
+  return this.setLdap(undefined);
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.model.ProjectSSOConfig.Oidc} returns this
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.model.ProjectSSOConfig.Oidc.prototype.setAvatarUrlClaimKey = function(value) {
-  return jspb.Message.setProto3StringField(this, 12, value);
+proto.model.ProjectSSOConfig.prototype.hasLdap = function() {
+  return jspb.Message.getField(this, 15) != null;
 };
 
 
 /**
- * optional Provider provider = 1;
- * @return {!proto.model.ProjectSSOConfig.Provider}
+ * optional Saml saml = 16;
+ * @return {?proto.model.ProjectSSOConfig.Saml}
  */
-proto.model.ProjectSSOConfig.prototype.getProvider = function() {
-  return /** @type {!proto.model.ProjectSSOConfig.Provider} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
+proto.model.ProjectSSOConfig.prototype.getSaml = function() {
+  return /** @type{?proto.model.ProjectSSOConfig.Saml} */ (
+    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.Saml, 16));
 };
 
 
 /**
- * @param {!proto.model.ProjectSSOConfig.Provider} value
+ * @param {?proto.model.ProjectSSOConfig.Saml|undefined} value
  * @return {!proto.model.ProjectSSOConfig} returns this
- */
-proto.model.ProjectSSOConfig.prototype.setProvider = function(value) {
-  return jspb.Message.setProto3EnumField(this, 1, value);
+*/
+proto.model.ProjectSSOConfig.prototype.setSaml = function(value) {
+  return jspb.Message.setWrapperField(this, 16, value);
 };
 
 
 /**
- * optional int64 session_ttl = 2;
- * @return {number}
+ * Clears the message field making it undefined.
+ * @return {!proto.model.ProjectSSOConfig} returns this
  */
-proto.model.ProjectSSOConfig.prototype.getSessionTtl = function() {
-  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
+proto.model.ProjectSSOConfig.prototype.clearSaml = function() {
+  return this.setSaml(undefined);
 };
 
 
 /**
- * @param {number} value
- * @return {!proto.model.ProjectSSOConfig} returns this
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.model.ProjectSSOConfig.prototype.setSessionTtl = function(value) {
-  return jspb.Message.setProto3IntField(this, 2, value);
+proto.model.ProjectSSOConfig.prototype.hasSaml = function() {
+  return jspb.Message.getField(this, 16) != null;
 };
 
 
 /**
- * optional GitHub github = 10;
- * @return {?proto.model.ProjectSSOConfig.GitHub}
+ * optional Okta okta = 17;
+ * @return {?proto.model.ProjectSSOConfig.Okta}
  */
-proto.model.ProjectSSOConfig.prototype.getGithub = function() {
-  return /** @type{?proto.model.ProjectSSOConfig.GitHub} */ (
-    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.GitHub, 10));
+proto.model.ProjectSSOConfig.prototype.getOkta = function() {
+  return /** @type{?proto.model.ProjectSSOConfig.Okta} */ (
+    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.Okta, 17));
 };
 
 
 /**
- * @param {?proto.model.ProjectSSOConfig.GitHub|undefined} value
+ * @param {?proto.model.ProjectSSOConfig.Okta|undefined} value
  * @return {!proto.model.ProjectSSOConfig} returns this
 */
-proto.model.ProjectSSOConfig.prototype.setGithub = function(value) {
-  return jspb.Message.setWrapperField(this, 10, value);
+proto.model.ProjectSSOConfig.prototype.setOkta = function(value) {
+  return jspb.Message.setWrapperField(this, 17, value);
 };
 
 
@@ -2136,8 +6104,8 @@ proto.model.ProjectSSOConfig.prototype.setGithub = function(value) {
  * Clears the message field making it undefined.
  * @return {!proto.model.ProjectSSOConfig} returns this
  */
-proto.model.ProjectSSOConfig.prototype.clearGithub = function() {
-  return this.setGithub(undefined);
+proto.model.ProjectSSOConfig.prototype.clearOkta = function() {
+  return this.setOkta(undefined);
 };
 
 
@@ -2145,27 +6113,27 @@ proto.model.ProjectSSOConfig.prototype.clearGithub = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.model.ProjectSSOConfig.prototype.hasGithub = function() {
-  return jspb.Message.getField(this, 10) != null;
+proto.model.ProjectSSOConfig.prototype.hasOkta = function() {
+  return jspb.Message.getField(this, 17) != null;
 };
 
 
 /**
- * optional Google google = 11;
- * @return {?proto.model.ProjectSSOConfig.Google}
+ * optional Bitbucket bitbucket = 18;
+ * @return {?proto.model.ProjectSSOConfig.Bitbucket}
  */
-proto.model.ProjectSSOConfig.prototype.getGoogle = function() {
-  return /** @type{?proto.model.ProjectSSOConfig.Google} */ (
-    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.Google, 11));
+proto.model.ProjectSSOConfig.prototype.getBitbucket = function() {
+  return /** @type{?proto.model.ProjectSSOConfig.Bitbucket} */ (
+    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.Bitbucket, 18));
 };
 
 
 /**
- * @param {?proto.model.ProjectSSOConfig.Google|undefined} value
+ * @param {?proto.model.ProjectSSOConfig.Bitbucket|undefined} value
  * @return {!proto.model.ProjectSSOConfig} returns this
 */
-proto.model.ProjectSSOConfig.prototype.setGoogle = function(value) {
-  return jspb.Message.setWrapperField(this, 11, value);
+proto.model.ProjectSSOConfig.prototype.setBitbucket = function(value) {
+  return jspb.Message.setWrapperField(this, 18, value);
 };
 
 
@@ -2173,8 +6141,8 @@ proto.model.ProjectSSOConfig.prototype.setGoogle = function(value) {
  * Clears the message field making it undefined.
  * @return {!proto.model.ProjectSSOConfig} returns this
  */
-proto.model.ProjectSSOConfig.prototype.clearGoogle = function() {
-  return this.setGoogle(undefined);
+proto.model.ProjectSSOConfig.prototype.clearBitbucket = function() {
+  return this.setBitbucket(undefined);
 };
 
 
@@ -2182,45 +6150,44 @@ proto.model.ProjectSSOConfig.prototype.clearGoogle = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.model.ProjectSSOConfig.prototype.hasGoogle = function() {
-  return jspb.Message.getField(this, 11) != null;
+proto.model.ProjectSSOConfig.prototype.hasBitbucket = function() {
+  return jspb.Message.getField(this, 18) != null;
 };
 
 
 /**
- * optional Oidc oidc = 12;
- * @return {?proto.model.ProjectSSOConfig.Oidc}
+ * optional string display_name = 19;
+ * @return {string}
  */
-proto.model.ProjectSSOConfig.prototype.getOidc = function() {
-  return /** @type{?proto.model.ProjectSSOConfig.Oidc} */ (
-    jspb.Message.getWrapperField(this, proto.model.ProjectSSOConfig.Oidc, 12));
+proto.model.ProjectSSOConfig.prototype.getDisplayName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 19, ""));
 };
 
 
 /**
- * @param {?proto.model.ProjectSSOConfig.Oidc|undefined} value
+ * @param {string} value
  * @return {!proto.model.ProjectSSOConfig} returns this
-*/
-proto.model.ProjectSSOConfig.prototype.setOidc = function(value) {
-  return jspb.Message.setWrapperField(this, 12, value);
+ */
+proto.model.ProjectSSOConfig.prototype.setDisplayName = function(value) {
+  return jspb.Message.setProto3StringField(this, 19, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.model.ProjectSSOConfig} returns this
+ * optional string icon = 20;
+ * @return {string}
  */
-proto.model.ProjectSSOConfig.prototype.clearOidc = function() {
-  return this.setOidc(undefined);
+proto.model.ProjectSSOConfig.prototype.getIcon = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 20, ""));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {string} value
+ * @return {!proto.model.ProjectSSOConfig} returns this
  */
-proto.model.ProjectSSOConfig.prototype.hasOidc = function() {
-  return jspb.Message.getField(this, 12) != null;
+proto.model.ProjectSSOConfig.prototype.setIcon = function(value) {
+  return jspb.Message.setProto3StringField(this, 20, value);
 };
 
 
@@ -2447,7 +6414,8 @@ proto.model.ProjectUserGroup.prototype.toObject = function(opt_includeInstance)
 proto.model.ProjectUserGroup.toObject = function(includeInstance, msg) {
   var f, obj = {
     ssoGroup: jspb.Message.getFieldWithDefault(msg, 1, ""),
-    role: jspb.Message.getFieldWithDefault(msg, 2, "")
+    role: jspb.Message.getFieldWithDefault(msg, 2, ""),
+    sessionTtl: jspb.Message.getFieldWithDefault(msg, 3, 0)
   };
 
   if (includeInstance) {
@@ -2492,6 +6460,10 @@ proto.model.ProjectUserGroup.deserializeBinaryFromReader = function(msg, reader)
       var value = /** @type {string} */ (reader.readString());
       msg.setRole(value);
       break;
+    case 3:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setSessionTtl(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -2535,6 +6507,13 @@ proto.model.ProjectUserGroup.serializeBinaryToWriter = function(message, writer)
       f
     );
   }
+  f = message.getSessionTtl();
+  if (f !== 0) {
+    writer.writeInt64(
+      3,
+      f
+    );
+  }
 };
 
 
@@ -2574,6 +6553,182 @@ proto.model.ProjectUserGroup.prototype.setRole = function(value) {
 };
 
 
+/**
+ * optional int64 session_ttl = 3;
+ * @return {number}
+ */
+proto.model.ProjectUserGroup.prototype.getSessionTtl = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.model.ProjectUserGroup} returns this
+ */
+proto.model.ProjectUserGroup.prototype.setSessionTtl = function(value) {
+  return jspb.Message.setProto3IntField(this, 3, value);
+};
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.model.ProjectEmailDomainRole.prototype.toObject = function(opt_includeInstance) {
+  return proto.model.ProjectEmailDomainRole.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.model.ProjectEmailDomainRole} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectEmailDomainRole.toObject = function(includeInstance, msg) {
+  var f, obj = {
+    domain: jspb.Message.getFieldWithDefault(msg, 1, ""),
+    role: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.model.ProjectEmailDomainRole}
+ */
+proto.model.ProjectEmailDomainRole.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.model.ProjectEmailDomainRole;
+  return proto.model.ProjectEmailDomainRole.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.model.ProjectEmailDomainRole} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.model.ProjectEmailDomainRole}
+ */
+proto.model.ProjectEmailDomainRole.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDomain(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRole(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.model.ProjectEmailDomainRole.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.model.ProjectEmailDomainRole.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.model.ProjectEmailDomainRole} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.model.ProjectEmailDomainRole.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getDomain();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getRole();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string domain = 1;
+ * @return {string}
+ */
+proto.model.ProjectEmailDomainRole.prototype.getDomain = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectEmailDomainRole} returns this
+ */
+proto.model.ProjectEmailDomainRole.prototype.setDomain = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string role = 2;
+ * @return {string}
+ */
+proto.model.ProjectEmailDomainRole.prototype.getRole = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.ProjectEmailDomainRole} returns this
+ */
+proto.model.ProjectEmailDomainRole.prototype.setRole = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
 
 /**
  * List of repeated fields within this message type.
@@ -16,6 +16,17 @@ export class User extends jspb.Message {
   hasRole(): boolean;
   clearRole(): User;
 
+  getEmail(): string;
+  setEmail(value: string): User;
+
+  getEmailVerified(): boolean;
+  setEmailVerified(value: boolean): User;
+
+  getGroupsList(): Array<string>;
+  setGroupsList(value: Array<string>): User;
+  clearGroupsList(): User;
+  addGroups(value: string, index?: number): User;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): User.AsObject;
   static toObject(includeInstance: boolean, msg: User): User.AsObject;
@@ -29,6 +40,9 @@ export namespace User {
     username: string,
     avatarUrl: string,
     role?: pkg_model_role_pb.Role.AsObject,
+    email: string,
+    emailVerified: boolean,
+    groupsList: Array<string>,
   }
 }
 
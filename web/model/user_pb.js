@@ -37,7 +37,7 @@ goog.exportSymbol('proto.model.User', null, global);
  * @constructor
  */
 proto.model.User = function(opt_data) {
-  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.model.User.repeatedFields_, null);
 };
 goog.inherits(proto.model.User, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
@@ -48,6 +48,13 @@ if (goog.DEBUG && !COMPILED) {
   proto.model.User.displayName = 'proto.model.User';
 }
 
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.model.User.repeatedFields_ = [6];
+
 
 
 if (jspb.Message.GENERATE_TO_OBJECT) {
@@ -81,7 +88,10 @@ proto.model.User.toObject = function(includeInstance, msg) {
   var f, obj = {
     username: jspb.Message.getFieldWithDefault(msg, 1, ""),
     avatarUrl: jspb.Message.getFieldWithDefault(msg, 2, ""),
-    role: (f = msg.getRole()) && pkg_model_role_pb.Role.toObject(includeInstance, f)
+    role: (f = msg.getRole()) && pkg_model_role_pb.Role.toObject(includeInstance, f),
+    email: jspb.Message.getFieldWithDefault(msg, 4, ""),
+    emailVerified: jspb.Message.getBooleanFieldWithDefault(msg, 5, false),
+    groupsList: (f = jspb.Message.getRepeatedField(msg, 6)) == null ? undefined : f
   };
 
   if (includeInstance) {
@@ -131,6 +141,18 @@ proto.model.User.deserializeBinaryFromReader = function(msg, reader) {
       reader.readMessage(value,pkg_model_role_pb.Role.deserializeBinaryFromReader);
       msg.setRole(value);
       break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setEmail(value);
+      break;
+    case 5:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setEmailVerified(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addGroups(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -182,6 +204,27 @@ proto.model.User.serializeBinaryToWriter = function(message, writer) {
       pkg_model_role_pb.Role.serializeBinaryToWriter
     );
   }
+  f = message.getEmail();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getEmailVerified();
+  if (f) {
+    writer.writeBool(
+      5,
+      f
+    );
+  }
+  f = message.getGroupsList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      6,
+      f
+    );
+  }
 };
 
 
@@ -258,4 +301,77 @@ proto.model.User.prototype.hasRole = function() {
 };
 
 
+/**
+ * optional string email = 4;
+ * @return {string}
+ */
+proto.model.User.prototype.getEmail = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.model.User} returns this
+ */
+proto.model.User.prototype.setEmail = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional bool email_verified = 5;
+ * @return {boolean}
+ */
+proto.model.User.prototype.getEmailVerified = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 5, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.model.User} returns this
+ */
+proto.model.User.prototype.setEmailVerified = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 5, value);
+};
+
+
+/**
+ * repeated string groups = 6;
+ * @return {!Array<string>}
+ */
+proto.model.User.prototype.getGroupsList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 6));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.model.User} returns this
+ */
+proto.model.User.prototype.setGroupsList = function(value) {
+  return jspb.Message.setField(this, 6, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.model.User} returns this
+ */
+proto.model.User.prototype.addGroups = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 6, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.model.User} returns this
+ */
+proto.model.User.prototype.clearGroupsList = function() {
+  return this.setGroupsList([]);
+};
+
+
 goog.object.extend(exports, proto.model);
@@ -82,11 +82,13 @@ export const updateGitHubSSO = ({
   clientSecret,
   baseUrl,
   uploadUrl,
+  apiUrl,
 }: {
   clientId: string;
   clientSecret: string;
   baseUrl?: string;
   uploadUrl?: string;
+  apiUrl?: string;
 }): Promise<UpdateProjectSSOConfigResponse.AsObject> => {
   const req = new UpdateProjectSSOConfigRequest();
   const sso = new ProjectSSOConfig();
@@ -95,6 +97,7 @@ export const updateGitHubSSO = ({
   github.setClientSecret(clientSecret);
   if (baseUrl) github.setBaseUrl(baseUrl);
   if (uploadUrl) github.setUploadUrl(uploadUrl);
+  if (apiUrl) github.setApiUrl(apiUrl);
 
   sso.setGithub(github);
   req.setSso(sso);
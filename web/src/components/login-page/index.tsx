@@ -9,6 +9,10 @@ import { getQueryStringValue } from "~/hooks/use-query-string";
 import { LoginForm } from "./login-form";
 import { LOGGING_IN_PROJECT } from "~/constants/localstorage";
 import useAuth from "~/contexts/auth-context/use-auth";
+import {
+  LOGIN_ERROR_TEXT,
+  LOGIN_ERROR_TEXT_UNKNOWN,
+} from "~/constants/login-error-text";
 
 const CONTENT_WIDTH = 500;
 
@@ -17,12 +21,23 @@ export const LoginPage: FC = memo(function LoginPage() {
   const [name, setName] = useState<string>("");
   const [cookies, , removeCookie] = useCookies(["error"]);
   const queryProject = getQueryStringValue("project") as string;
+  const [loginError, setLoginError] = useState<string | null>(
+    getQueryStringValue("login_error") as string | null
+  );
   const project = queryProject
     ? queryProject
     : localStorage.getItem(LOGGING_IN_PROJECT) || "";
 
   const handleCloseErrorAlert = (): void => {
     removeCookie("error");
+    setLoginError(null);
+  };
+
+  const handleRetry = (): void => {
+    removeCookie("error");
+    window.location.href = project
+      ? `${PAGE_PATH_LOGIN}?project=${encodeURIComponent(project)}`
+      : PAGE_PATH_LOGIN;
   };
 
   const handleOnContinue = (): void => {
@@ -43,7 +58,7 @@ export const LoginPage: FC = memo(function LoginPage() {
       }}
     >
       {me && me.isLogin && <Navigate to={PAGE_PATH_APPLICATIONS} replace />}
-      {cookies.error && (
+      {loginError ? (
         <MuiAlert
           severity="error"
           sx={{
@@ -51,9 +66,27 @@ export const LoginPage: FC = memo(function LoginPage() {
             marginBottom: 2,
           }}
           onClose={handleCloseErrorAlert}
+          action={
+            <Button color="inherit" size="small" onClick={handleRetry}>
+              RETRY
+            </Button>
+          }
         >
-          {cookies.error}
+          {LOGIN_ERROR_TEXT[loginError] || LOGIN_ERROR_TEXT_UNKNOWN}
         </MuiAlert>
+      ) : (
+        cookies.error && (
+          <MuiAlert
+            severity="error"
+            sx={{
+              width: CONTENT_WIDTH,
+              marginBottom: 2,
+            }}
+            onClose={handleCloseErrorAlert}
+          >
+            {cookies.error}
+          </MuiAlert>
+        )
       )}
       <Card
         sx={{
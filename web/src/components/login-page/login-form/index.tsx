@@ -2,11 +2,15 @@ import { FC, memo } from "react";
 import { TextField, Button, Typography, Box } from "@mui/material";
 import {
   STATIC_LOGIN_ENDPOINT,
+  LDAP_LOGIN_ENDPOINT,
   LOGIN_ENDPOINT,
   PAGE_PATH_LOGIN,
 } from "~/constants/path";
 import { MarkGithubIcon } from "@primer/octicons-react";
-import { LOGGING_IN_PROJECT } from "~/constants/localstorage";
+import {
+  LOGGING_IN_PROJECT,
+  REDIRECT_PATH_KEY,
+} from "~/constants/localstorage";
 
 export interface LoginFormProps {
   projectName: string;
@@ -51,6 +55,12 @@ export const LoginForm: FC<LoginFormProps> = memo(function LoginForm({
             name="project"
             value={projectName || undefined}
           />
+          <input
+            type="hidden"
+            id="return-to"
+            name="return_to"
+            value={localStorage.getItem(REDIRECT_PATH_KEY) || undefined}
+          />
           <Button
             type="submit"
             color="primary"
@@ -124,6 +134,12 @@ export const LoginForm: FC<LoginFormProps> = memo(function LoginForm({
             name="project"
             value={projectName || undefined}
           />
+          <input
+            type="hidden"
+            id="return-to-ldap"
+            name="return_to"
+            value={localStorage.getItem(REDIRECT_PATH_KEY) || undefined}
+          />
           <TextField
             id="username"
             name="username"
@@ -151,6 +167,14 @@ export const LoginForm: FC<LoginFormProps> = memo(function LoginForm({
             <Button type="reset" color="primary" onClick={handleOnBack}>
               back
             </Button>
+            <Button
+              type="submit"
+              color="primary"
+              formAction={LDAP_LOGIN_ENDPOINT}
+              sx={{ marginRight: 1 }}
+            >
+              login with ldap
+            </Button>
             <Button type="submit" color="primary" variant="contained">
               login
             </Button>
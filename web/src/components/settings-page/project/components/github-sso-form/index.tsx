@@ -33,6 +33,7 @@ export interface GitHubSSOFormParams {
   clientSecret: string;
   baseUrl: string;
   uploadUrl: string;
+  apiUrl: string;
   org: string;
   adminTeam: string;
   editorTeam: string;
@@ -48,6 +49,7 @@ export const GithubSSOForm: FC = memo(function GithubSSOForm() {
   const [clientSecret, setClientSecret] = useState("");
   const [baseUrl, setBaseUrl] = useState("");
   const [uploadUrl, setUploadUrl] = useState("");
+  const [apiUrl, setApiUrl] = useState("");
 
   const { data: projectDetail } = useGetProject();
   const { github: sso, sharedSSO: sharedSSO } = projectDetail || {};
@@ -60,7 +62,13 @@ export const GithubSSOForm: FC = memo(function GithubSSOForm() {
 
   const handleSave = (e: React.FormEvent<HTMLFormElement>): void => {
     e.preventDefault();
-    updateGithubSso({ clientId, clientSecret, baseUrl, uploadUrl }).then(() => {
+    updateGithubSso({
+      clientId,
+      clientSecret,
+      baseUrl,
+      uploadUrl,
+      apiUrl,
+    }).then(() => {
       addToast({
         message: UPDATE_SSO_SUCCESS,
         severity: "success",
@@ -93,6 +101,7 @@ export const GithubSSOForm: FC = memo(function GithubSSOForm() {
                 label="Upload URL"
                 value={sso.uploadUrl}
               />
+              <ProjectSettingLabeledText label="API URL" value={sso.apiUrl} />
             </ProjectValues>
 
             <div>
@@ -128,6 +137,7 @@ export const GithubSSOForm: FC = memo(function GithubSSOForm() {
             onEnter: () => {
               setBaseUrl(sso?.baseUrl ?? "");
               setUploadUrl(sso?.uploadUrl ?? "");
+              setApiUrl(sso?.apiUrl ?? "");
             },
           },
         }}
@@ -181,6 +191,14 @@ export const GithubSSOForm: FC = memo(function GithubSSOForm() {
                 fullWidth
                 onChange={(e) => setUploadUrl(e.currentTarget.value)}
               />
+              <TextField
+                value={apiUrl}
+                variant="outlined"
+                size="small"
+                label="API URL"
+                fullWidth
+                onChange={(e) => setApiUrl(e.currentTarget.value)}
+              />
             </Box>
           </DialogContent>
           <DialogActions>
@@ -0,0 +1,20 @@
+// The messages of the error codes passed by the login_error query parameter.
+export const LOGIN_ERROR_TEXT: Record<string, string> = {
+  method_not_allowed: "The login request was invalid. Please try again.",
+  invalid_request: "The login request was invalid. Please try again.",
+  unauthorized: "Unable to sign in. Please try again.",
+  login_expired: "Your login timed out. Please try again.",
+  state_invalid:
+    "The login session could not be verified. Please start the login again from this page.",
+  forbidden: "You are not allowed to sign in to this project.",
+  project_not_found: "The project was not found.",
+  invalid_sso_configuration:
+    "The SSO configuration of the project is invalid. Please contact the project admin.",
+  too_many_requests:
+    "Too many login attempts. Please wait a moment and try again.",
+  temporarily_unavailable:
+    "The service is temporarily unavailable. Please try again in a moment.",
+  internal: "An internal error occurred. Please try again later.",
+};
+
+export const LOGIN_ERROR_TEXT_UNKNOWN = "Unable to sign in. Please try again.";
@@ -12,5 +12,6 @@ export const PAGE_PATH_SETTINGS_PROJECT = "/settings/project";
 export const PAGE_PATH_SETTINGS_API_KEY = "/settings/api-key";
 
 export const STATIC_LOGIN_ENDPOINT = "/auth/login/static";
+export const LDAP_LOGIN_ENDPOINT = "/auth/login/ldap";
 export const LOGIN_ENDPOINT = "/auth/login";
 export const LOGOUT_ENDPOINT = "/auth/logout";